@@ -10,6 +10,7 @@ import (
 
 	"github.com/alecthomas/jsonschema"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 )
 
@@ -23,7 +24,7 @@ func main() {
 	}
 
 	if len(os.Args) < 3 {
-		log.Fatalf("syntax: %s md-docs-file jsonschema-file\n", os.Args[0])
+		log.Fatalf("syntax: %s md-docs-file jsonschema-file [output-jsonschema-file]\n", os.Args[0])
 	}
 
 	err = os.WriteFile(os.Args[1], data, 0644)
@@ -52,4 +53,24 @@ func main() {
 	if err != nil {
 		log.Fatalf("Could not write jsonschema: %s\n", err)
 	}
+
+	if len(os.Args) < 4 {
+		return
+	}
+	// Generate json output jsonschema
+	outputReflector := &jsonschema.Reflector{FullyQualifyTypeNames: true}
+	outputSchemaData := outputReflector.Reflect(&output.ResultEvent{})
+	outputSchemaData.Title = "nuclei json output " + output.SchemaVersion
+	outputSchemaData.Description = "schema of nuclei json/jsonl result records, schema_version contains the version of a record"
+
+	buf.Reset()
+	_ = encoder.Encode(outputSchemaData)
+	outputSchema := buf.String()
+	for _, match := range pathRegex.FindAllStringSubmatch(outputSchema, -1) {
+		outputSchema = strings.ReplaceAll(outputSchema, match[0], match[1])
+	}
+	err = os.WriteFile(os.Args[3], []byte(outputSchema), 0644)
+	if err != nil {
+		log.Fatalf("Could not write output jsonschema: %s\n", err)
+	}
 }
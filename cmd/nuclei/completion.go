@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/internal/completion"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+)
+
+// isCompletionCommand returns true if nuclei is invoked to generate or query shell completions
+func isCompletionCommand(args []string) bool {
+	return len(args) > 1 && (args[1] == "completion" || args[1] == completion.CompleteCommand)
+}
+
+// runCompletion prints the completion script of a shell (nuclei completion bash|zsh|fish)
+// or the candidates queried by the scripts (nuclei __complete <kind> <current>)
+func runCompletion(args []string) {
+	command, args := args[1], args[2:]
+	if customConfigDir := os.Getenv(config.NucleiConfigDirEnv); customConfigDir != "" {
+		config.DefaultConfig.SetConfigDir(customConfigDir)
+	}
+
+	if command == completion.CompleteCommand {
+		if len(args) == 0 {
+			return
+		}
+		current := ""
+		if len(args) > 1 {
+			current = args[1]
+		}
+		candidates, err := completion.Candidates(args[0], current)
+		if err != nil {
+			gologger.Fatal().Msgf("Could not get completions: %s\n", err)
+		}
+		for _, candidate := range candidates {
+			fmt.Println(candidate)
+		}
+		return
+	}
+
+	if len(args) == 0 {
+		gologger.Fatal().Msgf("Usage: nuclei completion %s\n", strings.Join(completion.Shells, "|"))
+	}
+	// flags are collected from the flagset without parsing the command line
+	os.Args = os.Args[:1]
+	flagSet := readConfig()
+	var flags []string
+	flagSet.CommandLine.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, f.Name)
+	})
+	script, err := completion.Script(args[0], flags)
+	if err != nil {
+		gologger.Fatal().Msgf("Could not generate completion script: %s\n", err)
+	}
+	fmt.Print(script)
+}
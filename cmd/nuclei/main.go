@@ -2,10 +2,10 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"io/fs"
 	"os"
-	"os/signal"
 	"path/filepath"
 	"runtime"
 	"runtime/pprof"
@@ -16,13 +16,22 @@ import (
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/gologger/levels"
 	"github.com/projectdiscovery/interactsh/pkg/client"
+	"github.com/projectdiscovery/interactsh/pkg/settings"
 	"github.com/projectdiscovery/nuclei/v3/internal/runner"
+	"github.com/projectdiscovery/nuclei/v3/internal/server"
+	"github.com/projectdiscovery/nuclei/v3/internal/service"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/core/inputs/cloudassets"
+	"github.com/projectdiscovery/nuclei/v3/pkg/core/inputs/liveness"
 	"github.com/projectdiscovery/nuclei/v3/pkg/installer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/uncover"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/extensions"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
@@ -33,15 +42,40 @@ import (
 	errorutil "github.com/projectdiscovery/utils/errors"
 	fileutil "github.com/projectdiscovery/utils/file"
 	updateutils "github.com/projectdiscovery/utils/update"
+	"gopkg.in/yaml.v2"
 )
 
 var (
 	cfgFile    string
 	memProfile string // optional profile file path
+	profile    string // optional scan profile name or path
 	options    = &types.Options{}
 )
 
 func main() {
+	// exit code is deferred first so that other deferred calls run before exit
+	var exitCode int
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	// nuclei completion prints shell completion scripts instead of a scan
+	if isCompletionCommand(os.Args) {
+		runCompletion(os.Args)
+		return
+	}
+	// nuclei service installs or uninstalls the system service
+	if isServiceCommand(os.Args) {
+		runService(os.Args)
+		return
+	}
+	// nuclei server runs the rest api server instead of a scan
+	if len(os.Args) > 1 && os.Args[1] == "server" {
+		options.ServerMode = true
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
 	if err := runner.ConfigureOptions(); err != nil {
 		gologger.Fatal().Msgf("Could not initialize options: %s\n", err)
 	}
@@ -111,6 +145,18 @@ func main() {
 
 	runner.ParseOptions(options)
 
+	if options.ReplayFile != "" {
+		if err := runner.Replay(options); err != nil {
+			gologger.Fatal().Msgf("Could not replay requests: %s\n", err)
+		}
+		return
+	}
+
+	if options.ServerMode {
+		runServer()
+		return
+	}
+
 	if options.HangMonitor {
 		cancel := monitor.NewStackMonitor(10 * time.Second)
 		defer cancel()
@@ -128,10 +174,10 @@ func main() {
 	resumeFileName := types.DefaultResumeFilePath()
 	c := make(chan os.Signal, 1)
 	defer close(c)
-	signal.Notify(c, os.Interrupt)
+	service.NotifyShutdown(c)
 	go func() {
-		for range c {
-			gologger.Info().Msgf("CTRL+C pressed: Exiting\n")
+		for sig := range c {
+			logShutdown(sig)
 			nucleiRunner.Close()
 			if options.ShouldSaveResume() {
 				gologger.Info().Msgf("Creating resume file: %s\n", resumeFileName)
@@ -156,6 +202,10 @@ func main() {
 	if fileutil.FileExists(resumeFileName) {
 		os.Remove(resumeFileName)
 	}
+	if exceeded := nucleiRunner.FailOnExceeded(); len(exceeded) > 0 {
+		gologger.Error().Msgf("Findings reached fail-on thresholds: %s", strings.Join(exceeded, ", "))
+		exitCode = runner.FailOnExitCode
+	}
 }
 
 func readConfig() *goflags.FlagSet {
@@ -175,9 +225,12 @@ on extensive configurability, massive extensibility and ease of use.`)
 	flagSet.CreateGroup("input", "Target",
 		flagSet.StringSliceVarP(&options.Targets, "target", "u", nil, "target URLs/hosts to scan", goflags.StringSliceOptions),
 		flagSet.StringVarP(&options.TargetsFilePath, "list", "l", "", "path to file containing a list of target URLs/hosts to scan (one per line)"),
+		flagSet.StringSliceVarP(&options.PortScanFiles, "port-scan-list", "psl", nil, "nmap xml or masscan (xml,json,list) output to import open ports as targets", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.ExcludeHosts, "exclude-hosts", "eh", nil, "hosts/ips to exclude from the scan (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.ExcludeCIDRs, "exclude-cidr", "ec", nil, "cidr ranges to exclude from the scan (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.StringVar(&options.Resume, "resume", "", "resume scan using resume.cfg (clustering will be disabled)"),
 		flagSet.BoolVarP(&options.ScanAllIPs, "scan-all-ips", "sa", false, "scan all the IP's associated with dns record"),
-		flagSet.StringSliceVarP(&options.IPVersion, "ip-version", "iv", nil, "IP version to scan of hostname (4,6) - (default 4)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.IPVersion, "ip-version", "iv", nil, "IP versions to connect to in preference order (4,6), both race connections (happy eyeballs)", goflags.CommaSeparatedStringSliceOptions),
 	)
 
 	flagSet.CreateGroup("templates", "Templates",
@@ -194,6 +247,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVar(&options.TemplateList, "tl", false, "list all available templates"),
 		flagSet.StringSliceVarConfigOnly(&options.RemoteTemplateDomainList, "remote-template-domain", []string{"templates.nuclei.sh"}, "allowed domain list to load remote templates from"),
 		flagSet.BoolVar(&options.SignTemplates, "sign", false, "signs the templates with the private key defined in NUCLEI_SIGNATURE_PRIVATE_KEY env variable"),
+		flagSet.StringVarP(&options.TemplateOverrides, "template-overrides", "tov", "", "file mapping template ids to severity, tags or suppression overrides (default overrides.yaml in config dir)"),
 	)
 
 	flagSet.CreateGroup("filters", "Filtering",
@@ -217,15 +271,19 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringVarP(&options.Output, "output", "o", "", "output file to write found issues/vulnerabilities"),
 		flagSet.BoolVarP(&options.StoreResponse, "store-resp", "sresp", false, "store all request/response passed through nuclei to output directory"),
 		flagSet.StringVarP(&options.StoreResponseDir, "store-resp-dir", "srd", runner.DefaultDumpTrafficOutputFolder, "store all request/response passed through nuclei to custom directory"),
+		flagSet.StringVarP(&options.EvidenceDirectory, "evidence-dir", "ed", "", "directory to write request/response chain evidence files of findings"),
 		flagSet.BoolVar(&options.Silent, "silent", false, "display findings only"),
 		flagSet.BoolVarP(&options.NoColor, "no-color", "nc", false, "disable output content coloring (ANSI escape codes)"),
 		flagSet.BoolVarP(&options.JSONL, "jsonl", "j", false, "write output in JSONL(ines) format"),
+		flagSet.StringVarP(&options.OutputSchemaVersion, "schema-version", "sv", "", fmt.Sprintf("json output schema version to write results in for compatibility (%s)", strings.Join(output.SupportedSchemaVersions(), ","))),
+		flagSet.StringVarP(&options.CVEDatabase, "cve-db", "cdb", "", "offline cve database directory used to enrich results with cve metadata"),
 		flagSet.BoolVarP(&options.JSONRequests, "include-rr", "irr", true, "include request/response pairs in the JSON, JSONL, and Markdown outputs (for findings only) [DEPRECATED use `-omit-raw`]"),
 		flagSet.BoolVarP(&options.OmitRawRequests, "omit-raw", "or", false, "omit request/response pairs in the JSON, JSONL, and Markdown outputs (for findings only)"),
 		flagSet.BoolVarP(&options.NoMeta, "no-meta", "nm", false, "disable printing result metadata in cli output"),
 		flagSet.BoolVarP(&options.Timestamp, "timestamp", "ts", false, "enables printing timestamp in cli output"),
 		flagSet.StringVarP(&options.ReportingDB, "report-db", "rdb", "", "nuclei reporting database (always use this to persist report data)"),
 		flagSet.BoolVarP(&options.MatcherStatus, "matcher-status", "ms", false, "display match failure status"),
+		flagSet.StringSliceVarP(&options.FailOn, "fail-on", "fo", nil, "exit with code 2 when findings reach severity:count thresholds (ex: high:1,critical:1)", goflags.CommaSeparatedStringSliceOptions),
 		flagSet.StringVarP(&options.MarkdownExportDirectory, "markdown-export", "me", "", "directory to export results in markdown format"),
 		flagSet.StringVarP(&options.SarifExport, "sarif-export", "se", "", "file to export results in SARIF format"),
 		flagSet.StringVarP(&options.JSONExport, "json-export", "je", "", "file to export results in JSON format"),
@@ -234,6 +292,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 
 	flagSet.CreateGroup("configs", "Configurations",
 		flagSet.StringVar(&cfgFile, "config", "", "path to the nuclei configuration file"),
+		flagSet.StringVarP(&profile, "profile", "sp", "", "scan profile to use from the profiles config directory (name or file)"),
+		flagSet.CallbackVarP(printProfiles, "profile-list", "spl", "list available scan profiles"),
 		flagSet.BoolVarP(&options.FollowRedirects, "follow-redirects", "fr", false, "enable following redirects for http templates"),
 		flagSet.BoolVarP(&options.FollowHostRedirects, "follow-host-redirects", "fhr", false, "follow redirects on the same host"),
 		flagSet.IntVarP(&options.MaxRedirects, "max-redirects", "mr", 10, "max number of redirects to follow for http templates"),
@@ -241,7 +301,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringVarP(&options.ReportingConfig, "report-config", "rc", "", "nuclei reporting module configuration file"), // TODO merge into the config file or rename to issue-tracking
 		flagSet.StringSliceVarP(&options.CustomHeaders, "header", "H", nil, "custom header/cookie to include in all http request in header:value format (cli, file)", goflags.FileStringSliceOptions),
 		flagSet.RuntimeMapVarP(&options.Vars, "var", "V", nil, "custom vars in key=value format"),
-		flagSet.StringVarP(&options.ResolversFile, "resolvers", "r", "", "file containing resolver list for nuclei"),
+		flagSet.StringVarP(&options.ResolversFile, "resolvers", "r", "", "file containing resolver list for nuclei (doh and dot resolvers supported)"),
 		flagSet.BoolVarP(&options.SystemResolvers, "system-resolvers", "sr", false, "use system DNS resolving as error fallback"),
 		flagSet.BoolVarP(&options.DisableClustering, "disable-clustering", "dc", false, "disable clustering of requests"),
 		flagSet.BoolVar(&options.OfflineHTTP, "passive", false, "enable passive HTTP response processing mode"),
@@ -254,19 +314,40 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVar(&options.ZTLS, "ztls", false, "use ztls library with autofallback to standard one for tls13 [Deprecated] autofallback to ztls is enabled by default"), //nolint:all
 		flagSet.StringVar(&options.SNI, "sni", "", "tls sni hostname to use (default: input domain name)"),
 		flagSet.BoolVarP(&options.AllowLocalFileAccess, "allow-local-file-access", "lfa", false, "allows file (payload) access anywhere on the system"),
+		flagSet.BoolVarP(&options.AllowRemotePayloads, "allow-remote-payloads", "arp", false, "allows templates to fetch payload files from https urls"),
+		flagSet.BoolVarP(&options.AllowTemplateProxy, "allow-template-proxy", "atp", false, "allows templates to send http requests through their own proxies chained after the scan proxy"),
 		flagSet.BoolVarP(&options.RestrictLocalNetworkAccess, "restrict-local-network-access", "lna", false, "blocks connections to the local / private network"),
+		flagSet.BoolVarP(&options.DisallowPrivateIPs, "disallow-private-ips", "dpi", false, "blocks connections to private / reserved addresses including redirects and re-resolved hosts (dns rebinding)"),
 		flagSet.StringVarP(&options.Interface, "interface", "i", "", "network interface to use for network scan"),
 		flagSet.StringVarP(&options.AttackType, "attack-type", "at", "", "type of payload combinations to perform (batteringram,pitchfork,clusterbomb)"),
 		flagSet.StringVarP(&options.SourceIP, "source-ip", "sip", "", "source ip address to use for network scan"),
 		flagSet.IntVarP(&options.ResponseReadSize, "response-size-read", "rsr", 10*1024*1024, "max response size to read in bytes"),
 		flagSet.IntVarP(&options.ResponseSaveSize, "response-size-save", "rss", 1*1024*1024, "max response size to read in bytes"),
+		flagSet.BoolVarP(&options.HTTPCompression, "http-compression", "hcomp", false, "advertise brotli and zstd with gzip and deflate in the accept-encoding of http requests"),
 		flagSet.CallbackVar(resetCallback, "reset", "reset removes all nuclei configuration and data files (including nuclei-templates)"),
 		flagSet.BoolVarP(&options.TlsImpersonate, "tls-impersonate", "tlsi", false, "enable experimental client hello (ja3) tls randomization"),
+		flagSet.StringVarP(&options.TLSMinVersion, "tls-min-version", "tlsmin", "", "minimum tls version to use (tls10, tls11, tls12, tls13)"),
+		flagSet.StringVarP(&options.TLSMaxVersion, "tls-max-version", "tlsmax", "", "maximum tls version to use (tls10, tls11, tls12, tls13)"),
+		flagSet.StringSliceVarP(&options.TLSCiphers, "tls-ciphers", "tlsc", nil, "tls cipher suites to offer for tls10-tls12 (ex: TLS_RSA_WITH_AES_128_CBC_SHA)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.TLSCurves, "tls-curves", "tlscu", nil, "elliptic curves to offer in preference order (x25519, p256, p384, p521)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.TLSALPN, "tls-alpn", "tlsa", nil, "alpn protocols to offer (ex: h2,http/1.1)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringVarP(&options.TLSRenegotiation, "tls-renegotiation", "tlsr", "", "tls renegotiation support (never, once, freely)"),
+		flagSet.StringVarP(&options.HTTPAuthType, "http-auth-type", "hat", "", "authentication of http requests (ntlm, negotiate, kerberos, digest)"),
+		flagSet.StringVarP(&options.HTTPAuthUsername, "http-auth-user", "hau", "", "username of the http authentication (username, domain\\username or username@realm)"),
+		flagSet.StringVarP(&options.HTTPAuthPassword, "http-auth-password", "hap", "", "password of the http authentication"),
+		flagSet.StringVarP(&options.HTTPAuthKDC, "http-auth-kdc", "hakdc", "", "kerberos kdc address of the http authentication (looked up with dns if empty)"),
+		flagSet.StringVarP(&options.HTTPAuthKeytab, "http-auth-keytab", "hakt", "", "kerberos keytab file of the http authentication"),
+		flagSet.StringVarP(&options.HTTPAuthCCache, "http-auth-ccache", "hacc", "", "kerberos credential cache file of the http authentication"),
+		flagSet.StringVarP(&options.SessionFile, "session-file", "sesf", "", "session file with the login requests of the authenticated http scans"),
+		flagSet.BoolVar(&options.FIPS, "fips", false, "restrict tls and dsl crypto to fips approved algorithms (enabled in fips builds)"),
+		flagSet.StringSliceVarP(&options.Plugins, "plugin", "pl", nil, "matcher/extractor plugin executables or directories of them to load", goflags.FileCommaSeparatedStringSliceOptions),
 	)
 
 	flagSet.CreateGroup("interactsh", "interactsh",
-		flagSet.StringVarP(&options.InteractshURL, "interactsh-server", "iserver", "", fmt.Sprintf("interactsh server url for self-hosted instance (default: %s)", client.DefaultOptions.ServerURL)),
-		flagSet.StringVarP(&options.InteractshToken, "interactsh-token", "itoken", "", "authentication token for self-hosted interactsh server"),
+		flagSet.StringVarP(&options.InteractshURL, "interactsh-server", "iserver", "", fmt.Sprintf("interactsh server url for self-hosted instance, multiple servers are tried in order (default: %s)", client.DefaultOptions.ServerURL)),
+		flagSet.StringVarP(&options.InteractshToken, "interactsh-token", "itoken", "", "authentication token for self-hosted interactsh server (comma separated for each server)"),
+		flagSet.IntVarP(&options.InteractshCorrelationIDLength, "interactsh-cid-length", "icidl", settings.CorrelationIdLengthDefault, "correlation id length configured on the interactsh server"),
+		flagSet.IntVarP(&options.InteractshCorrelationIDNonceLength, "interactsh-cid-nonce-length", "icidn", settings.CorrelationIdNonceLengthDefault, "correlation id nonce length configured on the interactsh server"),
 		flagSet.IntVar(&options.InteractionsCacheSize, "interactions-cache-size", 5000, "number of requests to keep in the interactions cache"),
 		flagSet.IntVar(&options.InteractionsEviction, "interactions-eviction", 60, "number of seconds to wait before evicting requests from cache"),
 		flagSet.IntVar(&options.InteractionsPollDuration, "interactions-poll-duration", 5, "number of seconds to wait before each interaction poll request"),
@@ -277,6 +358,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 	flagSet.CreateGroup("fuzzing", "Fuzzing",
 		flagSet.StringVarP(&options.FuzzingType, "fuzzing-type", "ft", "", "overrides fuzzing type set in template (replace, prefix, postfix, infix)"),
 		flagSet.StringVarP(&options.FuzzingMode, "fuzzing-mode", "fm", "", "overrides fuzzing mode set in template (multiple, single)"),
+		flagSet.BoolVarP(&options.RespectRobots, "respect-robots", "rr", false, "skip fuzzing paths disallowed by robots.txt and report the skipped coverage"),
 	)
 
 	flagSet.CreateGroup("uncover", "Uncover",
@@ -286,11 +368,19 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringVarP(&options.UncoverField, "uncover-field", "uf", "ip:port", "uncover fields to return (ip,port,host)"),
 		flagSet.IntVarP(&options.UncoverLimit, "uncover-limit", "ul", 100, "uncover results to return"),
 		flagSet.IntVarP(&options.UncoverRateLimit, "uncover-ratelimit", "ur", 60, "override ratelimit of engines with unknown ratelimit (default 60 req/min)"),
+		flagSet.StringSliceVarP(&options.UncoverEngineRateLimit, "uncover-engine-ratelimit", "uer", nil, "override ratelimit of specific engines in req/min (engine=count)", goflags.CommaSeparatedStringSliceOptions),
+	)
+
+	flagSet.CreateGroup("cloud-assets", "Cloud-Assets",
+		flagSet.StringSliceVarP(&options.CloudAssets, "cloud-assets", "cas", nil, fmt.Sprintf("enumerate endpoints and buckets of cloud accounts as targets (%s)", cloudassets.SupportedProviders()), goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.CloudAssetsRegions, "cloud-assets-region", "car", nil, "aws regions to enumerate (default configured region)", goflags.CommaSeparatedStringSliceOptions),
 	)
 
 	flagSet.CreateGroup("rate-limit", "Rate-Limit",
 		flagSet.IntVarP(&options.RateLimit, "rate-limit", "rl", 150, "maximum number of requests to send per second"),
 		flagSet.IntVarP(&options.RateLimitMinute, "rate-limit-minute", "rlm", 0, "maximum number of requests to send per minute"),
+		flagSet.BoolVarP(&options.RateLimitAdaptive, "rate-limit-adaptive", "rla", false, "throttle every host on slow responses, errors and 429/503 statuses"),
+		flagSet.IntVarP(&options.RateLimitAdaptiveMin, "rate-limit-adaptive-min", "rlam", hostratelimit.DefaultMinRate, "minimum number of requests to send per second to a throttled host"),
 		flagSet.IntVarP(&options.BulkSize, "bulk-size", "bs", 25, "maximum number of hosts to be analyzed in parallel per template"),
 		flagSet.IntVarP(&options.TemplateThreads, "concurrency", "c", 25, "maximum number of templates to be executed in parallel"),
 		flagSet.IntVarP(&options.HeadlessBulkSize, "headless-bulk-size", "hbs", 10, "maximum number of headless hosts to be analyzed in parallel per template"),
@@ -314,6 +404,14 @@ on extensive configurability, massive extensibility and ease of use.`)
 		}),
 		flagSet.DurationVarP(&options.InputReadTimeout, "input-read-timeout", "irt", time.Duration(3*time.Minute), "timeout on input read"),
 		flagSet.BoolVarP(&options.DisableHTTPProbe, "no-httpx", "nh", false, "disable httpx probing for non-url input"),
+		flagSet.StringSliceVarP(&options.LivenessCheck, "liveness-check", "lc", nil, "skip hosts failing liveness check with given methods before scanning (tcp,http,icmp), reported with -ms", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.LivenessPorts, "liveness-ports", "lcp", nil, "ports to connect to in tcp liveness check of targets without port (default 80,443)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.IntVarP(&options.LivenessConcurrency, "liveness-concurrency", "lcc", liveness.DefaultConcurrency, "number of targets to check for liveness in parallel"),
+		flagSet.IntVarP(&options.JSPoolSize, "js-pool-size", "jps", protocolstate.DefaultJSPoolSize, "maximum number of database connections pooled by javascript libraries"),
+		flagSet.DurationVarP(&options.JSTimeout, "js-timeout", "jst", 0, "maximum execution time of the javascript protocol code (0 to disable)"),
+		flagSet.StringVarP(&options.JSMaxMemory, "js-max-memory", "jsm", "", "maximum memory the javascript protocol code can allocate (ex: 256mb)"),
+		flagSet.StringVarP(&options.HTTPConnectionReuse, "http-conn-reuse", "hcr", "", "reuse keep-alive http connections within a template or across templates (template, global)"),
+		flagSet.IntVarP(&options.HTTPMaxHostConnections, "http-max-host-conns", "hmhc", httpclientpool.DefaultMaxHostConnections, "maximum number of reused http connections per host"),
 		flagSet.BoolVar(&options.DisableStdin, "no-stdin", false, "disable stdin processing"),
 	)
 
@@ -324,6 +422,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringSliceVarP(&options.HeadlessOptionalArguments, "headless-options", "ho", nil, "start headless chrome with additional options", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.BoolVarP(&options.UseInstalledChrome, "system-chrome", "sc", false, "use local installed Chrome browser instead of nuclei installed"),
 		flagSet.BoolVarP(&options.ShowActions, "list-headless-action", "lha", false, "list available headless actions"),
+		flagSet.StringVarP(&options.HeadlessRemoteURL, "headless-remote", "hremote", "", "devtools url of a running browser to use for headless templates (ws://host:port/devtools/browser/id or host:port)"),
 	)
 
 	flagSet.CreateGroup("debug", "Debug",
@@ -332,9 +431,15 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.DebugResponse, "debug-resp", "dresp", false, "show all received responses"),
 		flagSet.StringSliceVarP(&options.Proxy, "proxy", "p", nil, "list of http/socks5 proxy to use (comma separated or file input)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.BoolVarP(&options.ProxyInternal, "proxy-internal", "pi", false, "proxy all internal requests"),
+		flagSet.StringVarP(&options.ProxyRotation, "proxy-rotation", "prot", "", "rotate http requests across proxies, dead proxies are removed (round-robin, sticky)"),
 		flagSet.BoolVarP(&options.ListDslSignatures, "list-dsl-function", "ldf", false, "list all supported DSL function signatures"),
 		flagSet.StringVarP(&options.TraceLogFile, "trace-log", "tlog", "", "file to write sent requests trace log"),
 		flagSet.StringVarP(&options.ErrorLogFile, "error-log", "elog", "", "file to write sent requests error log"),
+		flagSet.StringVarP(&options.AuditLogFile, "audit-log", "alog", "", "file to append audit log of all outgoing requests to (signed with NUCLEI_AUDIT_LOG_KEY env if set)"),
+		flagSet.BoolVarP(&options.AuditLogRaw, "audit-log-raw", "alr", false, "include raw requests and responses in the audit log (required by -replay)"),
+		flagSet.StringVarP(&options.ReplayFile, "replay", "rpl", "", "re-send the requests of an audit log in order and diff the responses with the recorded ones"),
+		flagSet.StringVarP(&options.LogFile, "log-file", "lf", "", "file to write nuclei logs to instead of stderr, rotated when reaching log-max-size"),
+		flagSet.IntVarP(&options.LogMaxSize, "log-max-size", "lms", 100, "maximum size in MB of the log file before it is rotated"),
 		flagSet.CallbackVar(printVersion, "version", "show nuclei version"),
 		flagSet.BoolVarP(&options.HangMonitor, "hang-monitor", "hm", false, "enable nuclei hang monitoring"),
 		flagSet.BoolVarP(&options.Verbose, "verbose", "v", false, "show verbose output"),
@@ -350,6 +455,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&updateNucleiBinary, "update", "up", false, "update nuclei engine to the latest released version"),
 		flagSet.BoolVarP(&options.UpdateTemplates, "update-templates", "ut", false, "update nuclei-templates to latest released version"),
 		flagSet.StringVarP(&options.NewTemplatesDirectory, "update-template-dir", "ud", "", "custom directory to install / update nuclei-templates"),
+		flagSet.BoolVarP(&options.UpdateCVEDatabase, "update-cve-db", "ucd", false, "download / update the offline cve database from nvd (NVD_API_KEY env raises the rate limit)"),
 		flagSet.CallbackVarP(disableUpdatesCallback, "disable-update-check", "duc", "disable automatic nuclei/templates update check"),
 	)
 
@@ -358,6 +464,18 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.StatsJSON, "stats-json", "sj", false, "display statistics in JSONL(ines) format"),
 		flagSet.IntVarP(&options.StatsInterval, "stats-interval", "si", 5, "number of seconds to wait between showing a statistics update"),
 		flagSet.IntVarP(&options.MetricsPort, "metrics-port", "mp", 9092, "port to expose nuclei metrics on"),
+		flagSet.BoolVarP(&options.ScanReport, "scan-report", "srp", false, "display statistics report with latencies, errors and slowest templates at the end of the scan"),
+		flagSet.StringVarP(&options.ScanReportJSON, "scan-report-json", "srj", "", "file to write end of scan statistics report to in JSON format"),
+		flagSet.BoolVarP(&options.ProfileTemplates, "profile-templates", "ptpl", false, "display all templates ranked by execution time with request and error counts at the end of the scan"),
+		flagSet.StringVarP(&options.ProfileTemplatesJSON, "profile-templates-json", "ptj", "", "file to write the templates profile to in JSON format"),
+	)
+
+	flagSet.CreateGroup("server", "Server",
+		flagSet.StringVarP(&options.ServerAddress, "server-addr", "saddr", server.DefaultAddress, "listen address of the rest api server (nuclei server)"),
+		flagSet.StringVarEnv(&options.ServerToken, "server-token", "stoken", "", "NUCLEI_SERVER_TOKEN", "token required to access the rest api server (random if empty)"),
+		flagSet.StringVarP(&options.ServerTenantsFile, "server-tenants", "stenants", "", "yaml file of rest api server tenants with their own token, templates, rate limit, results and reporting config"),
+		flagSet.DurationVarP(&options.ServerRetention, "server-retention", "sret", server.DefaultRetention, "duration the finished scans and their results are kept by the rest api server"),
+		flagSet.IntVarP(&options.ServerMaxFinishedScans, "server-max-finished", "smf", server.DefaultMaxFinishedScans, "maximum number of finished scans kept by the rest api server"),
 	)
 
 	flagSet.CreateGroup("cloud", "Cloud",
@@ -408,6 +526,23 @@ Additional documentation is available at: https://docs.nuclei.sh/getting-started
 	// ex: config.yaml moved to platform standard config dir from linux specific config dir
 	// and hence it will be attempted in config package during init
 	goflags.DisableAutoConfigMigration = true
+
+	customConfigDir := os.Getenv(config.NucleiConfigDirEnv)
+	if customConfigDir != "" {
+		config.DefaultConfig.SetConfigDir(customConfigDir)
+	}
+	// the profile is merged before parsing the flags so that its values take
+	// precedence over the config files while the flags given on cli win over it
+	cliFlags := parseCLIFlags(flagSet, os.Args[1:])
+	if value, ok := cliFlags[flagSet.CommandLine.Lookup("profile").Value]; ok {
+		profilePath, err := config.DefaultConfig.ResolveProfile(value)
+		if err != nil {
+			gologger.Fatal().Msgf("Could not load profile: %s\n", err)
+		}
+		if err := mergeProfile(flagSet, profilePath, cliFlags); err != nil {
+			gologger.Fatal().Msgf("Could not read profile %s: %s\n", profilePath, err)
+		}
+	}
 	_ = flagSet.Parse()
 
 	gologger.DefaultLogger.SetTimestamp(options.Timestamp, levels.LevelDebug)
@@ -428,8 +563,7 @@ Additional documentation is available at: https://docs.nuclei.sh/getting-started
 	if options.LeaveDefaultPorts {
 		http.LeaveDefaultPorts = true
 	}
-	if customConfigDir := os.Getenv(config.NucleiConfigDirEnv); customConfigDir != "" {
-		config.DefaultConfig.SetConfigDir(customConfigDir)
+	if customConfigDir != "" {
 		readFlagsConfig(flagSet)
 	}
 	if cfgFile != "" {
@@ -501,6 +635,79 @@ func printVersion() {
 	os.Exit(0)
 }
 
+// parseCLIFlags returns the values of the flags given in args, the short and
+// long names of a flag share the same value
+func parseCLIFlags(flagSet *goflags.FlagSet, args []string) map[flag.Value]string {
+	values := make(map[flag.Value]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		fl := flagSet.CommandLine.Lookup(name)
+		if fl == nil {
+			continue
+		}
+		if boolFlag, ok := fl.Value.(interface{ IsBoolFlag() bool }); !hasValue && (!ok || !boolFlag.IsBoolFlag()) && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		values[fl.Value] = value
+	}
+	return values
+}
+
+// mergeProfile merges the values of the profile file into the flags not given on cli
+func mergeProfile(flagSet *goflags.FlagSet, profilePath string, cliFlags map[flag.Value]string) error {
+	data, err := os.ReadFile(profilePath)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	for key := range values {
+		if fl := flagSet.CommandLine.Lookup(key); fl != nil {
+			if _, ok := cliFlags[fl.Value]; ok {
+				delete(values, key)
+			}
+		}
+	}
+	filtered, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp("", "nuclei-profile-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	if _, err := file.Write(filtered); err != nil {
+		return err
+	}
+	return flagSet.MergeConfigFile(file.Name())
+}
+
+// printProfiles prints the scan profiles available in the profiles directory and exits.
+func printProfiles() {
+	profiles := config.DefaultConfig.GetProfiles()
+	if len(profiles) == 0 {
+		gologger.Info().Msgf("No scan profiles found in %s", config.DefaultConfig.GetProfilesDir())
+		os.Exit(0)
+	}
+	gologger.Info().Msgf("Available scan profiles (%s):", config.DefaultConfig.GetProfilesDir())
+	for _, name := range profiles {
+		gologger.Silent().Msgf("%s\n", name)
+	}
+	os.Exit(0)
+}
+
 // printTemplateVersion prints the nuclei template version and exits.
 func printTemplateVersion() {
 	cfg := config.DefaultConfig
@@ -561,6 +768,38 @@ Note: Make sure you have backup of your custom nuclei-templates before proceedin
 	os.Exit(0)
 }
 
+// runServer runs the rest api server until interrupted
+func runServer() {
+	var tenants []*server.Tenant
+	if options.ServerTenantsFile != "" {
+		var err error
+		if tenants, err = server.LoadTenants(options.ServerTenantsFile); err != nil {
+			gologger.Fatal().Msgf("Could not load server tenants: %s\n", err)
+		}
+	}
+	apiServer, err := server.New(&server.Options{
+		Address:          options.ServerAddress,
+		Token:            options.ServerToken,
+		Tenants:          tenants,
+		Retention:        options.ServerRetention,
+		MaxFinishedScans: options.ServerMaxFinishedScans,
+	})
+	if err != nil {
+		gologger.Fatal().Msgf("Could not create server: %s\n", err)
+	}
+
+	c := make(chan os.Signal, 1)
+	service.NotifyShutdown(c)
+	go func() {
+		logShutdown(<-c)
+		apiServer.Close()
+	}()
+
+	if err := apiServer.ListenAndServe(); err != nil {
+		gologger.Fatal().Msgf("Could not run server: %s\n", err)
+	}
+}
+
 func init() {
 	// print stacktrace of errors in debug mode
 	if strings.EqualFold(os.Getenv("DEBUG"), "true") {
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/internal/service"
+)
+
+// isServiceCommand returns true if nuclei is invoked to manage its system service
+func isServiceCommand(args []string) bool {
+	return len(args) > 1 && args[1] == "service"
+}
+
+// runService installs or uninstalls the nuclei system service, the arguments
+// following install are the arguments of nuclei when run by the service
+// (ex: nuclei service install server -saddr 0.0.0.0:8822 -log-file /var/log/nuclei/nuclei.log)
+func runService(args []string) {
+	if len(args) < 3 {
+		gologger.Fatal().Msgf("Usage: nuclei service install [nuclei arguments] | nuclei service uninstall\n")
+	}
+	switch args[2] {
+	case "install":
+		if err := service.Install(args[3:]); err != nil {
+			gologger.Fatal().Msgf("Could not install service: %s\n", err)
+		}
+		gologger.Info().Msgf("Installed %s service, start it with: %s\n", service.Name, service.StartHint())
+	case "uninstall":
+		if err := service.Uninstall(); err != nil {
+			gologger.Fatal().Msgf("Could not uninstall service: %s\n", err)
+		}
+		gologger.Info().Msgf("Uninstalled %s service\n", service.Name)
+	default:
+		gologger.Fatal().Msgf("Unknown service command %s, supported: install, uninstall\n", args[2])
+	}
+}
+
+// logShutdown logs the signal which requested nuclei to exit
+func logShutdown(sig os.Signal) {
+	if sig == os.Interrupt {
+		gologger.Info().Msgf("CTRL+C pressed: Exiting\n")
+		return
+	}
+	gologger.Info().Msgf("Received %s: Exiting\n", sig)
+}
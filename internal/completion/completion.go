@@ -0,0 +1,223 @@
+// Package completion generates shell completion scripts for nuclei which
+// complete flags as well as template ids, tags and scan profile names.
+package completion
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// CompleteCommand is the hidden command used by completion scripts to query
+// the values of dynamically completed flags
+const CompleteCommand = "__complete"
+
+// Kinds of dynamically completed flag values
+const (
+	KindTemplates = "templates"
+	KindTags      = "tags"
+	KindProfiles  = "profiles"
+)
+
+// templatesStatsFile contains the stats of the installed nuclei-templates including tags
+const templatesStatsFile = "TEMPLATES-STATS.json"
+
+// Shells contains the supported shells
+var Shells = []string{"bash", "zsh", "fish"}
+
+// Commands are the nuclei commands completed as first argument
+var Commands = []string{"completion", "server", "service"}
+
+// ValueFlags maps flags whose values are completed dynamically to their kind
+var ValueFlags = map[string]string{
+	"template-id":  KindTemplates,
+	"id":           KindTemplates,
+	"exclude-id":   KindTemplates,
+	"eid":          KindTemplates,
+	"tags":         KindTags,
+	"exclude-tags": KindTags,
+	"etags":        KindTags,
+	"include-tags": KindTags,
+	"itags":        KindTags,
+	"profile":      KindProfiles,
+	"sp":           KindProfiles,
+}
+
+// Script returns the completion script of a shell completing the given flags
+func Script(shell string, flags []string) (string, error) {
+	flags = sortedFlags(flags)
+	switch shell {
+	case "bash":
+		return bashScript(flags), nil
+	case "zsh":
+		return zshScript(flags), nil
+	case "fish":
+		return fishScript(flags), nil
+	}
+	return "", errorutil.New("unsupported shell %s, supported: %s", shell, strings.Join(Shells, ","))
+}
+
+// Candidates returns the values of a kind matching current, for comma
+// separated values only the last value is completed
+func Candidates(kind, current string) ([]string, error) {
+	var values []string
+	switch kind {
+	case KindTemplates:
+		index, err := config.GetNucleiTemplatesIndex()
+		if err != nil {
+			return nil, err
+		}
+		for id := range index {
+			values = append(values, id)
+		}
+	case KindTags:
+		values = installedTags()
+	case KindProfiles:
+		values = config.DefaultConfig.GetProfiles()
+	default:
+		return nil, errorutil.New("unsupported completion kind %s", kind)
+	}
+
+	prefix, last := "", current
+	if index := strings.LastIndex(current, ","); index != -1 {
+		prefix, last = current[:index+1], current[index+1:]
+	}
+	var candidates []string
+	for _, value := range values {
+		if strings.HasPrefix(value, last) {
+			candidates = append(candidates, prefix+value)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates, nil
+}
+
+// installedTags returns the tags of the installed nuclei-templates
+func installedTags() []string {
+	data, err := os.ReadFile(filepath.Join(config.DefaultConfig.TemplatesDirectory, templatesStatsFile))
+	if err != nil {
+		return nil
+	}
+	var stats struct {
+		Tags []struct {
+			Name string `json:"name"`
+		} `json:"tags"`
+	}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil
+	}
+	tags := make([]string, 0, len(stats.Tags))
+	for _, tag := range stats.Tags {
+		if tag.Name != "" {
+			tags = append(tags, tag.Name)
+		}
+	}
+	return tags
+}
+
+func sortedFlags(flags []string) []string {
+	sorted := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		sorted = append(sorted, "-"+strings.TrimLeft(flag, "-"))
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// valueFlagPatterns returns the flags of each kind joined by separator
+func valueFlagPatterns(separator string) map[string]string {
+	byKind := make(map[string][]string)
+	for flag, kind := range ValueFlags {
+		byKind[kind] = append(byKind[kind], "-"+flag)
+	}
+	patterns := make(map[string]string, len(byKind))
+	for kind, flags := range byKind {
+		sort.Strings(flags)
+		patterns[kind] = strings.Join(flags, separator)
+	}
+	return patterns
+}
+
+func bashScript(flags []string) string {
+	patterns := valueFlagPatterns("|")
+	return fmt.Sprintf(`# bash completion for nuclei, load with: source <(nuclei completion bash)
+_nuclei_completion() {
+    local cur prev kind
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    case "$prev" in
+        %s) kind=%s ;;
+        %s) kind=%s ;;
+        %s) kind=%s ;;
+    esac
+    if [[ -n "$kind" ]]; then
+        local IFS=$'\n'
+        COMPREPLY=($(nuclei %s "$kind" "$cur" 2>/dev/null))
+        return
+    fi
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+        return
+    fi
+    if [[ $COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+    fi
+}
+complete -o default -F _nuclei_completion nuclei
+`, patterns[KindTemplates], KindTemplates, patterns[KindTags], KindTags, patterns[KindProfiles], KindProfiles,
+		CompleteCommand, strings.Join(flags, " "), strings.Join(Commands, " "))
+}
+
+func zshScript(flags []string) string {
+	patterns := valueFlagPatterns("|")
+	return fmt.Sprintf(`#compdef nuclei
+# zsh completion for nuclei, load with: source <(nuclei completion zsh)
+_nuclei() {
+    local cur prev kind
+    cur="${words[CURRENT]}"
+    prev="${words[CURRENT-1]}"
+    case "$prev" in
+        %s) kind=%s ;;
+        %s) kind=%s ;;
+        %s) kind=%s ;;
+    esac
+    if [[ -n "$kind" ]]; then
+        local -a candidates
+        candidates=(${(f)"$(nuclei %s "$kind" "$cur" 2>/dev/null)"})
+        compadd -Q -- "${candidates[@]}"
+        return
+    fi
+    if [[ "$cur" == -* ]]; then
+        compadd -- %s
+        return
+    fi
+    if (( CURRENT == 2 )); then
+        compadd -- %s
+    fi
+    _files
+}
+compdef _nuclei nuclei
+`, patterns[KindTemplates], KindTemplates, patterns[KindTags], KindTags, patterns[KindProfiles], KindProfiles,
+		CompleteCommand, strings.Join(flags, " "), strings.Join(Commands, " "))
+}
+
+func fishScript(flags []string) string {
+	var builder strings.Builder
+	builder.WriteString("# fish completion for nuclei, load with: nuclei completion fish | source\n")
+	fmt.Fprintf(&builder, "complete -c nuclei -n __fish_use_subcommand -a '%s'\n", strings.Join(Commands, " "))
+	for _, flag := range flags {
+		name := strings.TrimPrefix(flag, "-")
+		if kind, ok := ValueFlags[name]; ok {
+			fmt.Fprintf(&builder, "complete -c nuclei -o %s -x -a '(nuclei %s %s (commandline -ct) 2>/dev/null)'\n", name, CompleteCommand, kind)
+			continue
+		}
+		fmt.Fprintf(&builder, "complete -c nuclei -o %s\n", name)
+	}
+	return builder.String()
+}
@@ -0,0 +1,42 @@
+package completion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScript(t *testing.T) {
+	for _, shell := range Shells {
+		script, err := Script(shell, []string{"tags", "u"})
+		require.Nil(t, err, "could not generate %s script", shell)
+		require.Contains(t, script, "tags")
+		require.Contains(t, script, CompleteCommand)
+	}
+	_, err := Script("powershell", nil)
+	require.NotNil(t, err, "could generate script of unsupported shell")
+}
+
+func TestCandidatesTags(t *testing.T) {
+	templatesDir := t.TempDir()
+	stats := `{"tags":[{"name":"cve","count":10},{"name":"rce","count":5},{"name":"cves","count":1}]}`
+	require.Nil(t, os.WriteFile(filepath.Join(templatesDir, templatesStatsFile), []byte(stats), 0600))
+
+	previous := config.DefaultConfig.TemplatesDirectory
+	config.DefaultConfig.TemplatesDirectory = templatesDir
+	defer func() { config.DefaultConfig.TemplatesDirectory = previous }()
+
+	candidates, err := Candidates(KindTags, "cv")
+	require.Nil(t, err)
+	require.Equal(t, []string{"cve", "cves"}, candidates)
+
+	candidates, err = Candidates(KindTags, "cve,r")
+	require.Nil(t, err)
+	require.Equal(t, []string{"cve,rce"}, candidates, "could not complete comma separated value")
+
+	_, err = Candidates("unknown", "")
+	require.NotNil(t, err, "could complete unsupported kind")
+}
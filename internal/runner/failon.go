@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// FailOnExitCode is the exit code used when findings exceed the fail-on policy
+const FailOnExitCode = 2
+
+// failOnPolicy maps severities to the number of findings at which nuclei fails
+type failOnPolicy map[severity.Severity]int
+
+// parseFailOnPolicy parses fail-on thresholds in severity:count format,
+// a severity without count fails on the first finding
+func parseFailOnPolicy(values []string) (failOnPolicy, error) {
+	policy := make(failOnPolicy)
+	for _, value := range values {
+		name, countValue, hasCount := strings.Cut(strings.TrimSpace(value), ":")
+		var severities severity.Severities
+		if err := severities.Set(name); err != nil || len(severities) != 1 {
+			return nil, errorutil.New("invalid fail-on severity %s, supported: %s", name, severity.GetSupportedSeverities().String())
+		}
+		count := 1
+		if hasCount {
+			parsed, err := strconv.Atoi(countValue)
+			if err != nil || parsed < 1 {
+				return nil, errorutil.New("invalid fail-on count %s for %s, must be a positive number", countValue, name)
+			}
+			count = parsed
+		}
+		policy[severities[0]] = count
+	}
+	return policy, nil
+}
+
+// findingCounter is an output writer counting findings by severity
+type findingCounter struct {
+	output.Writer
+
+	mutex  sync.Mutex
+	counts map[severity.Severity]int
+}
+
+func newFindingCounter(writer output.Writer) *findingCounter {
+	return &findingCounter{Writer: writer, counts: make(map[severity.Severity]int)}
+}
+
+// Write counts matched events and writes them to the underlying writer
+func (c *findingCounter) Write(event *output.ResultEvent) error {
+	if event.MatcherStatus {
+		c.mutex.Lock()
+		c.counts[event.Info.SeverityHolder.Severity]++
+		c.mutex.Unlock()
+	}
+	return c.Writer.Write(event)
+}
+
+// exceeded returns the thresholds of policy reached by the counted findings
+func (c *findingCounter) exceeded(policy failOnPolicy) []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var exceeded []string
+	for sev, threshold := range policy {
+		if count := c.counts[sev]; count >= threshold {
+			exceeded = append(exceeded, fmt.Sprintf("%s (%d >= %d)", sev, count, threshold))
+		}
+	}
+	sort.Strings(exceeded)
+	return exceeded
+}
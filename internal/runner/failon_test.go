@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFailOnPolicy(t *testing.T) {
+	policy, err := parseFailOnPolicy([]string{"high:3", "critical"})
+	require.Nil(t, err, "could not parse policy")
+	require.Equal(t, failOnPolicy{severity.High: 3, severity.Critical: 1}, policy)
+
+	for _, value := range []string{"severe:1", "high:0", "high:many"} {
+		_, err := parseFailOnPolicy([]string{value})
+		require.NotNil(t, err, "could not get error for %s", value)
+	}
+}
+
+func TestFindingCounterExceeded(t *testing.T) {
+	counter := newFindingCounter(testutils.NewMockOutputWriter())
+	write := func(sev severity.Severity, matched bool) {
+		event := &output.ResultEvent{Info: model.Info{SeverityHolder: severity.Holder{Severity: sev}}, MatcherStatus: matched}
+		require.Nil(t, counter.Write(event))
+	}
+	policy := failOnPolicy{severity.High: 2, severity.Critical: 1}
+
+	write(severity.High, true)
+	write(severity.Critical, false)
+	require.Empty(t, counter.exceeded(policy), "could not ignore failed matches")
+
+	write(severity.High, true)
+	write(severity.Critical, true)
+	require.Equal(t, []string{"critical (1 >= 1)", "high (2 >= 2)"}, counter.exceeded(policy))
+}
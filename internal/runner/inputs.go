@@ -9,6 +9,7 @@ import (
 	"github.com/projectdiscovery/hmap/store/hybrid"
 	"github.com/projectdiscovery/httpx/common/httpx"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	httputil "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils/http"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 	"github.com/remeh/sizedwaitgroup"
@@ -42,7 +43,7 @@ func (r *Runner) initializeTemplatesHTTPInput() (*hybrid.HybridMap, error) {
 	swg := sizedwaitgroup.New(bulkSize)
 	count := int32(0)
 	r.hmapInputProvider.Scan(func(value *contextargs.MetaInput) bool {
-		if stringsutil.HasPrefixAny(value.Input, "http://", "https://") {
+		if stringsutil.HasPrefixAny(value.Input, "http://", "https://") || httputil.IsSocket(value.Input) {
 			return true
 		}
 
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/core/inputs/liveness"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+)
+
+// livenessCheckType is the request type used to record liveness checks in the trace and error logs
+const livenessCheckType = "liveness"
+
+// removeDeadTargets runs the liveness check on the input and removes the
+// dead hosts from it, every check is recorded in the trace and error logs
+// and the skipped hosts are written to the output as failure events
+func (r *Runner) removeDeadTargets() error {
+	prober, err := liveness.New(&liveness.Options{
+		Methods:     r.options.LivenessCheck,
+		Ports:       r.options.LivenessPorts,
+		Concurrency: r.options.LivenessConcurrency,
+		Timeout:     time.Duration(r.options.Timeout) * time.Second,
+	})
+	if err != nil {
+		return err
+	}
+	gologger.Info().Msgf("Running liveness check (%s) on %d targets", strings.Join(r.options.LivenessCheck, ","), r.hmapInputProvider.Count())
+
+	dead := prober.DeadTargets(r.hmapInputProvider, func(input *contextargs.MetaInput, err error) {
+		if err != nil {
+			gologger.Verbose().Msgf("Skipping %s: %s", input.Input, err)
+			// failure events are only written with -matcher-status
+			_ = r.output.WriteFailure(&output.InternalWrappedEvent{Results: []*output.ResultEvent{{
+				TemplateID: livenessCheckType,
+				Type:       livenessCheckType,
+				Host:       input.Input,
+				Metadata:   map[string]interface{}{"error": err.Error()},
+				Timestamp:  time.Now(),
+			}}})
+		}
+		r.output.Request(livenessCheckType, input.Input, livenessCheckType, err)
+	})
+	for _, input := range dead {
+		r.hmapInputProvider.Delete(input)
+	}
+	gologger.Info().Msgf("Skipped %d dead targets, %d targets left to scan", len(dead), r.hmapInputProvider.Count())
+	if len(dead) > 0 && !r.options.MatcherStatus {
+		gologger.Info().Msgf("Use -matcher-status to write the skipped targets to the output")
+	}
+	return nil
+}
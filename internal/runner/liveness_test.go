@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"net"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/core/inputs/hybrid"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveDeadTargets(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	dead := closed.Addr().String()
+	closed.Close()
+
+	options := &types.Options{Targets: []string{listener.Addr().String(), dead}, LivenessCheck: []string{"tcp"}, MatcherStatus: true, Timeout: 2}
+	input, err := hybrid.New(&hybrid.Options{Options: options})
+	require.Nil(t, err, "could not create input provider")
+	defer input.Close()
+
+	var skipped []*output.ResultEvent
+	writer := testutils.NewMockOutputWriter()
+	writer.WriteCallback = func(event *output.ResultEvent) {
+		skipped = append(skipped, event)
+	}
+	r := &Runner{options: options, output: writer, hmapInputProvider: input}
+	require.Nil(t, r.removeDeadTargets(), "could not run liveness check")
+
+	require.Equal(t, int64(1), input.Count(), "could not remove dead target")
+	require.Len(t, skipped, 1, "could not write skipped target to output")
+	require.Equal(t, dead, skipped[0].Host)
+	require.False(t, skipped[0].MatcherStatus)
+	require.Equal(t, livenessCheckType, skipped[0].TemplateID)
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 
 	"github.com/go-playground/validator/v10"
@@ -16,10 +17,21 @@ import (
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/gologger/formatter"
 	"github.com/projectdiscovery/gologger/levels"
+	"github.com/projectdiscovery/gologger/writer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/core/inputs/cloudassets"
+	"github.com/projectdiscovery/nuclei/v3/pkg/core/inputs/liveness"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/proxypool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/secrets"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/uncover"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/dns/dnsclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 	protocoltypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	fileutil "github.com/projectdiscovery/utils/file"
@@ -57,6 +69,9 @@ func ParseOptions(options *types.Options) {
 	if options.ShowVarDump {
 		vardump.EnableVarDump = true
 	}
+	if options.FIPS {
+		fips.Enable()
+	}
 	if options.ShowActions {
 		gologger.Info().Msgf("Showing available headless actions: ")
 		for action := range engine.ActionStringToAction {
@@ -82,6 +97,10 @@ func ParseOptions(options *types.Options) {
 		gologger.Fatal().Msgf("Could not initialize protocols: %s\n", err)
 	}
 
+	if err := templates.LoadOverrides(options.TemplateOverrides); err != nil {
+		gologger.Fatal().Msgf("Could not load template overrides: %s\n", err)
+	}
+
 	// Set GitHub token in env variable. runner.getGHClientWithToken() reads token from env
 	if options.GitHubToken != "" && os.Getenv("GITHUB_TOKEN") != options.GitHubToken {
 		os.Setenv("GITHUB_TOKEN", options.GitHubToken)
@@ -115,9 +134,15 @@ func ValidateOptions(options *types.Options) error {
 	if options.Verbose && options.Silent {
 		return errors.New("both verbose and silent mode specified")
 	}
+	if err := resolveSecrets(options); err != nil {
+		return err
+	}
 
-	if (options.HeadlessOptionalArguments != nil || options.ShowBrowser || options.UseInstalledChrome) && !options.Headless {
-		return errors.New("headless mode (-headless) is required if -ho, -sb, -sc or -lha are set")
+	if (options.HeadlessOptionalArguments != nil || options.ShowBrowser || options.UseInstalledChrome || options.HeadlessRemoteURL != "") && !options.Headless {
+		return errors.New("headless mode (-headless) is required if -ho, -sb, -sc, -hremote or -lha are set")
+	}
+	if options.HeadlessRemoteURL != "" && (options.HeadlessOptionalArguments != nil || options.ShowBrowser || options.UseInstalledChrome) {
+		return errors.New("-ho, -sb and -sc cannot be used with a remote browser (-hremote)")
 	}
 
 	if options.FollowHostRedirects && options.FollowRedirects {
@@ -126,6 +151,18 @@ func ValidateOptions(options *types.Options) error {
 	if options.ShouldFollowHTTPRedirects() && options.DisableRedirects {
 		return errors.New("both follow redirects and disable redirects specified")
 	}
+	if options.InteractshCorrelationIDLength < 0 || options.InteractshCorrelationIDNonceLength < 0 {
+		return errors.New("interactsh correlation id and nonce lengths cannot be negative")
+	}
+	if options.DisallowPrivateIPs && len(options.Proxy) > 0 {
+		return errors.New("-disallow-private-ips can't be used with -proxy as proxies connect to destinations themselves")
+	}
+	if options.DisallowPrivateIPs && options.AllowTemplateProxy {
+		return errors.New("-disallow-private-ips can't be used with -allow-template-proxy as the proxies of the templates connect to destinations themselves")
+	}
+	if options.ProxyRotation != "" && !proxypool.IsValidStrategy(options.ProxyRotation) {
+		return fmt.Errorf("invalid proxy rotation strategy %s, supported: round-robin, sticky", options.ProxyRotation)
+	}
 	// loading the proxy server list from file or cli and test the connectivity
 	if err := loadProxyServers(options); err != nil {
 		return err
@@ -165,25 +202,61 @@ func ValidateOptions(options *types.Options) error {
 		}
 	}
 
-	// verify that a valid ip version type was selected (4, 6)
-	if len(options.IPVersion) == 0 {
-		// add ipv4 as default
-		options.IPVersion = append(options.IPVersion, "4")
+	if _, err := uncover.ParseEngineRateLimits(options.UncoverEngineRateLimit); err != nil {
+		return err
+	}
+	if options.OutputSchemaVersion != "" && !output.IsSupportedSchemaVersion(options.OutputSchemaVersion) {
+		return fmt.Errorf("unsupported json output schema version %s, supported: %s", options.OutputSchemaVersion, strings.Join(output.SupportedSchemaVersions(), ", "))
+	}
+	if _, err := parseFailOnPolicy(options.FailOn); err != nil {
+		return err
+	}
+	if err := options.TLSConfig().Validate(); err != nil {
+		return err
+	}
+	if err := options.HTTPAuthConfig().Validate(); err != nil {
+		return err
+	}
+	switch options.HTTPConnectionReuse {
+	case "", httpclientpool.ReuseTemplate, httpclientpool.ReuseGlobal:
+	default:
+		return fmt.Errorf("invalid http connection reuse %s, supported: template, global", options.HTTPConnectionReuse)
+	}
+	if options.RateLimitAdaptive && options.RateLimitAdaptiveMin < 1 {
+		return fmt.Errorf("invalid adaptive minimum rate %d, must be at least 1", options.RateLimitAdaptiveMin)
+	}
+	if err := liveness.ValidateMethods(options.LivenessCheck); err != nil {
+		return err
+	}
+	if len(options.LivenessCheck) > 0 && options.Stream {
+		return errors.New("liveness check (-lc) can't be used with stream mode (-stream)")
+	}
+	if options.LogFile != "" && options.LogMaxSize <= 0 {
+		return errors.New("log max size (-lms) must be greater than 0")
 	}
-	var useIPV4, useIPV6 bool
+	if options.JSTimeout < 0 {
+		return errors.New("javascript timeout (-jst) cannot be negative")
+	}
+	if options.JSMaxMemory != "" {
+		if _, err := units.FromHumanSize(options.JSMaxMemory); err != nil {
+			return errors.Wrap(err, "invalid javascript max memory (-jsm)")
+		}
+	}
+
+	// verify that only supported cloud providers were selected for cloud asset discovery
+	for _, provider := range options.CloudAssets {
+		if !cloudassets.IsSupported(provider) {
+			return fmt.Errorf("unsupported cloud provider %s, supported: %s", provider, cloudassets.SupportedProviders())
+		}
+	}
+
+	// verify that a valid ip version type was selected (4, 6), connections
+	// are not restricted to an address family if none is selected
 	for _, ipv := range options.IPVersion {
-		switch ipv {
-		case "4":
-			useIPV4 = true
-		case "6":
-			useIPV6 = true
-		default:
+		if ipv != "4" && ipv != "6" {
 			return fmt.Errorf("unsupported ip version: %s", ipv)
 		}
 	}
-	if !useIPV4 && !useIPV6 {
-		return errors.New("ipv4 and/or ipv6 must be selected")
-	}
 
 	// Validate cloud option
 	if err := validateCloudOptions(options); err != nil {
@@ -192,6 +265,25 @@ func ValidateOptions(options *types.Options) error {
 	return nil
 }
 
+// resolveSecrets resolves the env://VAR and file://path references of the
+// credential options, ex: -H "Authorization: Bearer env://API_TOKEN"
+func resolveSecrets(options *types.Options) error {
+	if err := secrets.ResolveAll(options.CustomHeaders); err != nil {
+		return errors.Wrap(err, "could not resolve custom headers")
+	}
+	var err error
+	if options.InteractshToken, err = secrets.Resolve(options.InteractshToken); err != nil {
+		return errors.Wrap(err, "could not resolve interactsh token")
+	}
+	if options.HTTPAuthUsername, err = secrets.Resolve(options.HTTPAuthUsername); err != nil {
+		return errors.Wrap(err, "could not resolve http authentication username")
+	}
+	if options.HTTPAuthPassword, err = secrets.Resolve(options.HTTPAuthPassword); err != nil {
+		return errors.Wrap(err, "could not resolve http authentication password")
+	}
+	return nil
+}
+
 func validateCloudOptions(options *types.Options) error {
 	if options.HasCloudOptions() && !options.Cloud {
 		return errors.New("cloud flags cannot be used without cloud option")
@@ -293,11 +385,35 @@ func configureOutput(options *types.Options) {
 	if options.Silent {
 		gologger.DefaultLogger.SetMaxLevel(levels.LevelSilent)
 	}
+	if options.LogFile != "" {
+		logWriter, err := newLogFileWriter(options.LogFile, options.LogMaxSize)
+		if err != nil {
+			gologger.Fatal().Msgf("Could not create log file: %s\n", err)
+		}
+		gologger.DefaultLogger.SetFormatter(formatter.NewCLI(true))
+		gologger.DefaultLogger.SetWriter(logWriter)
+	}
 
 	// disable standard logger (ref: https://github.com/golang/go/issues/19895)
 	logutil.DisableDefaultLogger()
 }
 
+// newLogFileWriter returns a log writer to file rotated and compressed
+// once its size reaches maxSize megabytes
+func newLogFileWriter(file string, maxSize int) (*writer.FileWithRotation, error) {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	rotationOptions := writer.DefaultFileWithRotationOptions
+	rotationOptions.Location = filepath.Dir(path)
+	rotationOptions.FileName = filepath.Base(path)
+	rotationOptions.Rotate = true
+	rotationOptions.MaxSize = maxSize
+	rotationOptions.Compress = true
+	return writer.NewFileWithRotation(&rotationOptions)
+}
+
 // loadResolvers loads resolvers from both user-provided flags and file
 func loadResolvers(options *types.Options) {
 	if options.ResolversFile == "" {
@@ -316,6 +432,11 @@ func loadResolvers(options *types.Options) {
 		if part == "" {
 			continue
 		}
+		// doh and dot resolvers can be given as https:// urls and tls:// addresses
+		part, err = dnsclientpool.NormalizeResolver(part)
+		if err != nil {
+			gologger.Fatal().Msgf("Could not parse resolvers file: %s\n", err)
+		}
 		if strings.Contains(part, ":") {
 			options.InternalResolversList = append(options.InternalResolversList, part)
 		} else {
@@ -410,6 +531,9 @@ func readEnvInputVars(options *types.Options) {
 	options.CodeTemplateSignaturePublicKey = os.Getenv("NUCLEI_SIGNATURE_PUBLIC_KEY")
 	options.CodeTemplateSignatureAlgorithm = os.Getenv("NUCLEI_SIGNATURE_ALGORITHM")
 
+	// Key used to sign the audit log entries
+	options.AuditLogKey = os.Getenv("NUCLEI_AUDIT_LOG_KEY")
+
 	// General options to disable the template download locations from being used.
 	// This will override the default behavior of downloading templates from the default locations as well as the
 	// custom locations.
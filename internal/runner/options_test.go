@@ -59,3 +59,21 @@ func TestParseHeadlessOptionalArguments(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("RUNNER_API_TOKEN", "t0k3n")
+	options := &types.Options{
+		CustomHeaders:    goflags.StringSlice{"Authorization: Bearer env://RUNNER_API_TOKEN", "X-Scanner: nuclei"},
+		InteractshToken:  "env://RUNNER_API_TOKEN",
+		HTTPAuthUsername: `CORP\scanner`,
+		HTTPAuthPassword: "env://RUNNER_API_TOKEN",
+	}
+	require.Nil(t, resolveSecrets(options))
+	require.Equal(t, goflags.StringSlice{"Authorization: Bearer t0k3n", "X-Scanner: nuclei"}, options.CustomHeaders)
+	require.Equal(t, "t0k3n", options.InteractshToken)
+	require.Equal(t, `CORP\scanner`, options.HTTPAuthUsername)
+	require.Equal(t, "t0k3n", options.HTTPAuthPassword)
+
+	options = &types.Options{CustomHeaders: goflags.StringSlice{"Authorization: Bearer env://RUNNER_MISSING_TOKEN"}}
+	require.EqualError(t, resolveSecrets(options), "could not resolve custom headers: environment variable RUNNER_MISSING_TOKEN is not set")
+}
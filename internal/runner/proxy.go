@@ -6,8 +6,10 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/proxypool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	fileutil "github.com/projectdiscovery/utils/file"
@@ -16,6 +18,7 @@ import (
 
 // loadProxyServers load list of proxy servers from file or comma separated
 func loadProxyServers(options *types.Options) error {
+	proxypool.Default = nil
 	if len(options.Proxy) == 0 {
 		return nil
 	}
@@ -39,6 +42,19 @@ func loadProxyServers(options *types.Options) error {
 			proxyList = append(proxyList, p)
 		}
 	}
+	if options.ProxyRotation != "" {
+		pool, err := proxypool.New(proxyList, proxypool.Strategy(options.ProxyRotation))
+		if err != nil {
+			return err
+		}
+		alive := pool.Check(time.Duration(options.Timeout) * time.Second)
+		if alive == 0 {
+			return proxypool.ErrNoAliveProxy
+		}
+		proxypool.Default = pool
+		gologger.Verbose().Msgf("Rotating http requests across %d/%d alive proxies (%s)", alive, pool.Len(), options.ProxyRotation)
+	}
+	// a single proxy is still used for requests not made by http clients
 	aliveProxy, err := proxyutils.GetAnyAliveProxy(options.Timeout, proxyList...)
 	if err != nil {
 		return err
@@ -0,0 +1,194 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	httpProtocol "github.com/projectdiscovery/nuclei/v3/pkg/protocols/http"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/utils/reader"
+)
+
+// replayIgnoredHeaders are the response headers expected to change between runs
+// or between the transport used by the scan and the raw replayed connection
+var replayIgnoredHeaders = []string{"date", "age", "expires", "content-length", "content-encoding", "transfer-encoding"}
+
+// Replay re-sends the raw requests recorded in an audit log (-audit-log-raw) in the
+// same order and prints the diff of the responses which differ from the recorded ones
+func Replay(options *types.Options) error {
+	timeout := time.Duration(options.Timeout) * time.Second
+	var replayed, changed, failed, skipped int
+	err := output.ReadAuditLog(options.ReplayFile, func(entry *output.AuditEntry) error {
+		if entry.Request == "" {
+			skipped++
+			return nil
+		}
+		var response string
+		var err error
+		switch entry.Type {
+		case "http":
+			response, err = replayHTTP(entry, timeout, options.ResponseReadSize)
+		case "tcp":
+			response, err = replayNetwork(entry, timeout)
+		default:
+			skipped++
+			return nil
+		}
+		replayed++
+		if err != nil {
+			failed++
+			gologger.Warning().Msgf("[%s] [%s] Could not replay request to %s: %s\n", entry.TemplateID, entry.Type, entry.Target, err)
+			return nil
+		}
+		if diff := responseDiff(entry.Type, entry.Response, response); diff != "" {
+			changed++
+			gologger.Info().Msgf("[%s] [%s] Response changed for %s\n%s", entry.TemplateID, entry.Type, entry.Target, diff)
+		} else {
+			gologger.Verbose().Msgf("[%s] [%s] Response unchanged for %s\n", entry.TemplateID, entry.Type, entry.Target)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not replay audit log")
+	}
+	gologger.Info().Msgf("Replayed %d requests: %d changed, %d failed, %d skipped without raw request\n", replayed, changed, failed, skipped)
+	return nil
+}
+
+// replayHTTP sends the raw http request of an entry to its target and returns the normalized response
+func replayHTTP(entry *output.AuditEntry, timeout time.Duration, maxSize int) (string, error) {
+	target, err := url.Parse(entry.Target)
+	if err != nil {
+		return "", err
+	}
+	port := target.Port()
+	if port == "" {
+		port = "80"
+		if target.Scheme == "https" {
+			port = "443"
+		}
+	}
+	address := net.JoinHostPort(target.Hostname(), port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var conn net.Conn
+	if target.Scheme == "https" {
+		conn, err = protocolstate.Dialer.DialTLS(ctx, "tcp", address)
+	} else {
+		conn, err = protocolstate.Dialer.Dial(ctx, "tcp", address)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := conn.Write([]byte(entry.Request)); err != nil {
+		return "", err
+	}
+	// the request method is required to know if the response has a body,
+	// unsafe raw requests which can't be parsed are read as GET requests
+	request, _ := http.ReadRequest(bufio.NewReader(strings.NewReader(entry.Request)))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), request)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if maxSize > 0 {
+		body = io.LimitReader(resp.Body, int64(maxSize))
+	}
+	data, err := io.ReadAll(body)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+	dumped, err := httpProtocol.DumpNormalizedResponse(resp, data)
+	if err != nil {
+		return "", err
+	}
+	return string(dumped), nil
+}
+
+// replayNetwork sends the raw data of an entry to its target and returns the data read in response
+func replayNetwork(entry *output.AuditEntry, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := protocolstate.Dialer.Dial(ctx, "tcp", entry.Target)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(entry.Request)); err != nil {
+		return "", err
+	}
+	size := len(entry.Response)
+	if size == 0 {
+		size = 1024
+	}
+	data, err := reader.ConnReadNWithTimeout(conn, int64(size), timeout)
+	if err != nil && !reader.IsAcceptedError(err) {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// responseDiff returns the unified diff of the recorded and replayed responses, empty if they match
+func responseDiff(requestType, recorded, replayed string) string {
+	if requestType == "http" {
+		recorded, replayed = normalizeHTTPResponse(recorded), normalizeHTTPResponse(replayed)
+	}
+	if recorded == replayed {
+		return ""
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(recorded),
+		B:        difflib.SplitLines(replayed),
+		FromFile: "recorded",
+		ToFile:   "replayed",
+		Context:  2,
+	})
+	return diff
+}
+
+// normalizeHTTPResponse removes the headers expected to change from a raw http response
+func normalizeHTTPResponse(response string) string {
+	response = strings.ReplaceAll(response, "\r\n", "\n")
+	headers, body, _ := strings.Cut(response, "\n\n")
+	var builder strings.Builder
+	for _, line := range strings.Split(headers, "\n") {
+		name, _, _ := strings.Cut(line, ":")
+		if isIgnoredHeader(name) {
+			continue
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\n")
+	builder.WriteString(body)
+	return builder.String()
+}
+
+func isIgnoredHeader(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, header := range replayIgnoredHeaders {
+		if name == header {
+			return true
+		}
+	}
+	return false
+}
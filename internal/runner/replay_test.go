@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+func TestReplayHTTP(t *testing.T) {
+	require.Nil(t, protocolstate.Init(&types.Options{}), "could not init dialer")
+
+	var version atomic.Value
+	version.Store("1.0")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Path", r.URL.Path)
+		fmt.Fprintf(w, "version %s", version.Load())
+	}))
+	defer ts.Close()
+
+	entry := &output.AuditEntry{
+		Type:    "http",
+		Target:  ts.URL + "/status",
+		Request: "GET /status HTTP/1.1\r\nHost: " + ts.Listener.Addr().String() + "\r\nConnection: close\r\n\r\n",
+	}
+	recorded, err := replayHTTP(entry, 5*time.Second, 0)
+	require.Nil(t, err, "could not replay request")
+	require.Contains(t, recorded, "X-Path: /status")
+	entry.Response = recorded
+
+	replayed, err := replayHTTP(entry, 5*time.Second, 0)
+	require.Nil(t, err)
+	require.Empty(t, responseDiff("http", entry.Response, replayed), "could not ignore changing headers")
+
+	version.Store("1.1")
+	replayed, err = replayHTTP(entry, 5*time.Second, 0)
+	require.Nil(t, err)
+	diff := responseDiff("http", entry.Response, replayed)
+	require.Contains(t, diff, "-version 1.0")
+	require.Contains(t, diff, "+version 1.1")
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/internal/runner/nucleicloud"
 	"github.com/projectdiscovery/nuclei/v3/pkg/installer"
 	uncoverlib "github.com/projectdiscovery/uncover"
+	fileutil "github.com/projectdiscovery/utils/file"
 	permissionutil "github.com/projectdiscovery/utils/permission"
 	updateutils "github.com/projectdiscovery/utils/update"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/loader"
 	"github.com/projectdiscovery/nuclei/v3/pkg/core"
 	"github.com/projectdiscovery/nuclei/v3/pkg/core/inputs/hybrid"
+	"github.com/projectdiscovery/nuclei/v3/pkg/cvedb"
 	"github.com/projectdiscovery/nuclei/v3/pkg/external/customtemplates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
@@ -40,8 +42,11 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/automaticscan"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/robots"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/session"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/uncover"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/excludematchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
@@ -51,6 +56,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/jsonl"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/markdown"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/sarif"
+	"github.com/projectdiscovery/nuclei/v3/pkg/scanstats"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils"
@@ -78,6 +84,11 @@ type Runner struct {
 	pprofServer       *http.Server
 	cloudClient       *nucleicloud.Client
 	cloudTargets      []string
+	failOn            failOnPolicy
+	findings          *findingCounter
+	scanStats         *scanstats.Collector
+	robots            *robots.Policy
+	hostRateLimiter   *hostratelimit.Limiter
 }
 
 const pprofServerAddress = "127.0.0.1:8086"
@@ -143,6 +154,10 @@ func New(options *types.Options) (*Runner, error) {
 		}
 	}
 
+	if err := setupCVEDatabase(options); err != nil {
+		return nil, err
+	}
+
 	if options.Validate {
 		parsers.ShouldValidate = true
 	}
@@ -152,7 +167,7 @@ func New(options *types.Options) (*Runner, error) {
 	yaml.StrictSyntax = !options.NoStrictSyntax
 
 	if options.Headless {
-		if engine.MustDisableSandbox() {
+		if options.HeadlessRemoteURL == "" && engine.MustDisableSandbox() {
 			gologger.Warning().Msgf("The current platform and privileged user will run the browser without sandbox\n")
 		}
 		browser, err := engine.New(options)
@@ -213,6 +228,9 @@ func New(options *types.Options) (*Runner, error) {
 	if (len(options.Templates) == 0 || !options.NewTemplates || (options.TargetsFilePath == "" && !options.Stdin && len(options.Targets) == 0)) && (options.UpdateTemplates && !options.Cloud) {
 		os.Exit(0)
 	}
+	if options.UpdateCVEDatabase && options.TargetsFilePath == "" && !options.Stdin && len(options.Targets) == 0 && !options.Cloud {
+		os.Exit(0)
+	}
 
 	// Initialize the input source
 	hmapInput, err := hybrid.New(&hybrid.Options{
@@ -248,6 +266,19 @@ func New(options *types.Options) (*Runner, error) {
 	}
 	runner.output = outputWriter
 
+	if len(options.FailOn) > 0 {
+		if runner.failOn, err = parseFailOnPolicy(options.FailOn); err != nil {
+			return nil, err
+		}
+		runner.findings = newFindingCounter(outputWriter)
+		runner.output = runner.findings
+	}
+
+	if options.ScanReport || options.ScanReportJSON != "" || options.ProfileTemplates || options.ProfileTemplatesJSON != "" {
+		runner.scanStats = scanstats.New()
+		runner.output = scanstats.NewWriter(runner.output, runner.scanStats)
+	}
+
 	if options.JSONL && options.EnableProgressBar {
 		options.StatsJSON = true
 	}
@@ -298,6 +329,8 @@ func New(options *types.Options) (*Runner, error) {
 		opts.ServerURL = options.InteractshURL
 	}
 	opts.Authorization = options.InteractshToken
+	opts.CorrelationIdLength = options.InteractshCorrelationIDLength
+	opts.CorrelationIdNonceLength = options.InteractshCorrelationIDNonceLength
 	opts.CacheSize = options.InteractionsCacheSize
 	opts.Eviction = time.Duration(options.InteractionsEviction) * time.Second
 	opts.CooldownPeriod = time.Duration(options.InteractionsCoolDownPeriod) * time.Second
@@ -376,6 +409,34 @@ func createReportingOptions(options *types.Options) (*reporting.Options, error)
 	return reportingOptions, nil
 }
 
+// setupCVEDatabase updates the offline cve database if requested and
+// enables it to enrich results when it exists
+func setupCVEDatabase(options *types.Options) error {
+	directory := options.CVEDatabase
+	if directory == "" {
+		directory = config.DefaultConfig.GetCVEDatabaseDir()
+	}
+	if options.UpdateCVEDatabase {
+		metadata, err := cvedb.NewSyncer(os.Getenv("NVD_API_KEY")).Sync(directory)
+		if err != nil {
+			return errors.Wrap(err, "could not update cve database")
+		}
+		gologger.Info().Msgf("Updated cve database %s with %d cves", directory, metadata.Records)
+	}
+	if !fileutil.FolderExists(directory) {
+		if options.CVEDatabase != "" {
+			return errors.Errorf("cve database %s does not exist, use -update-cve-db to download it", directory)
+		}
+		return nil
+	}
+	db, err := cvedb.Open(directory)
+	if err != nil {
+		return err
+	}
+	cvedb.SetDefault(db)
+	return nil
+}
+
 // Close releases all the resources and cleans up
 func (r *Runner) Close() {
 	if r.output != nil {
@@ -394,6 +455,14 @@ func (r *Runner) Close() {
 	}
 }
 
+// FailOnExceeded returns the fail-on thresholds reached by the findings of the scan
+func (r *Runner) FailOnExceeded() []string {
+	if r.findings == nil {
+		return nil
+	}
+	return r.findings.exceeded(r.failOn)
+}
+
 // RunEnumeration sets up the input layer for giving input nuclei.
 // binary and runs the actual enumeration
 func (r *Runner) RunEnumeration() error {
@@ -421,6 +490,7 @@ func (r *Runner) RunEnumeration() error {
 		Output:          r.output,
 		Options:         r.options,
 		Progress:        r.progress,
+		ScanStats:       r.scanStats,
 		Catalog:         r.catalog,
 		IssuesClient:    r.issuesClient,
 		RateLimiter:     r.rateLimiter,
@@ -440,6 +510,36 @@ func (r *Runner) RunEnumeration() error {
 		executorOpts.HostErrorsCache = cache
 	}
 
+	if r.options.RespectRobots {
+		httpclient, err := httpclientpool.Get(r.options, &httpclientpool.Configuration{})
+		if err != nil {
+			return errors.Wrap(err, "could not create robots.txt http client")
+		}
+		r.robots = robots.New(httpclient)
+		executorOpts.Robots = r.robots
+	}
+
+	if r.options.RateLimitAdaptive {
+		maxRate := float64(r.options.RateLimit)
+		if r.options.RateLimitMinute > 0 {
+			maxRate = float64(r.options.RateLimitMinute) / 60
+		}
+		r.hostRateLimiter = hostratelimit.New(maxRate, float64(r.options.RateLimitAdaptiveMin))
+		executorOpts.HostRateLimiter = r.hostRateLimiter
+	}
+
+	if r.options.SessionFile != "" {
+		config, err := session.Load(r.options.SessionFile)
+		if err != nil {
+			return err
+		}
+		httpclient, err := httpclientpool.Get(r.options, &httpclientpool.Configuration{RedirectFlow: httpclientpool.DontFollowRedirect})
+		if err != nil {
+			return errors.Wrap(err, "could not create session http client")
+		}
+		executorOpts.Session = session.New(config, httpclient)
+	}
+
 	executorEngine := core.New(r.options)
 	executorEngine.SetExecuterOptions(executorOpts)
 
@@ -498,9 +598,11 @@ func (r *Runner) RunEnumeration() error {
 			RateLimit:     uint(r.options.UncoverRateLimit),
 			RateLimitUnit: time.Minute, // default unit is minute
 		}
-		ret := uncover.GetUncoverTargetsFromMetadata(context.TODO(), store.Templates(), r.options.UncoverField, uncoverOpts)
-		for host := range ret {
-			r.hmapInputProvider.Set(host)
+		// engine ratelimits are verified while validating options
+		engineRateLimits, _ := uncover.ParseEngineRateLimits(r.options.UncoverEngineRateLimit)
+		ret := uncover.GetUncoverTargetsFromMetadata(context.TODO(), store.Templates(), r.options.UncoverField, uncoverOpts, engineRateLimits)
+		for target := range ret {
+			r.hmapInputProvider.SetWithMetadata(target.Value, target.Metadata)
 		}
 	}
 	// list all templates
@@ -512,6 +614,12 @@ func (r *Runner) RunEnumeration() error {
 	// display execution info like version , templates used etc
 	r.displayExecutionInfo(store)
 
+	if len(r.options.LivenessCheck) > 0 {
+		if err := r.removeDeadTargets(); err != nil {
+			return errors.Wrap(err, "could not run liveness check")
+		}
+	}
+
 	// If not explicitly disabled, check if http based protocols
 	// are used, and if inputs are non-http to pre-perform probing
 	// of urls and storing them for execution.
@@ -586,6 +694,9 @@ func (r *Runner) RunEnumeration() error {
 		}
 	}
 	r.progress.Stop()
+	r.writeScanReport()
+	r.writeRobotsReport()
+	r.writeHostRateLimitReport()
 
 	if executorOpts.InputHelper != nil {
 		_ = executorOpts.InputHelper.Close()
@@ -610,6 +721,68 @@ func (r *Runner) RunEnumeration() error {
 	return err
 }
 
+// writeScanReport displays and writes the end of scan statistics report and templates profile if requested
+func (r *Runner) writeScanReport() {
+	if r.scanStats == nil {
+		return
+	}
+	if r.options.ScanReport || r.options.ScanReportJSON != "" {
+		report := r.scanStats.Report()
+		if r.options.ScanReport {
+			gologger.Print().Msgf("\n%s", report.String())
+		}
+		if r.options.ScanReportJSON != "" {
+			if err := report.WriteJSON(r.options.ScanReportJSON); err != nil {
+				gologger.Error().Msgf("Could not write scan report to %s: %s", r.options.ScanReportJSON, err)
+			}
+		}
+	}
+	if r.options.ProfileTemplates || r.options.ProfileTemplatesJSON != "" {
+		profile := r.scanStats.TemplateProfile()
+		if r.options.ProfileTemplates {
+			gologger.Print().Msgf("\n%s", profile.String())
+		}
+		if r.options.ProfileTemplatesJSON != "" {
+			if err := profile.WriteJSON(r.options.ProfileTemplatesJSON); err != nil {
+				gologger.Error().Msgf("Could not write templates profile to %s: %s", r.options.ProfileTemplatesJSON, err)
+			}
+		}
+	}
+}
+
+// writeRobotsReport displays the fuzzing coverage skipped because of robots.txt
+func (r *Runner) writeRobotsReport() {
+	if r.robots == nil {
+		return
+	}
+	report := r.robots.Report()
+	if report.Skipped == 0 {
+		return
+	}
+	gologger.Info().Msgf("Skipped %d fuzzing requests to %d hosts disallowed by robots.txt", report.Skipped, len(report.Hosts))
+	for _, host := range report.Hosts {
+		gologger.Info().Msgf("%s: %d requests skipped (paths: %s)", host.Host, host.Skipped, strings.Join(host.Paths, ", "))
+		if host.SecurityTxt != nil && len(host.SecurityTxt.Contact) > 0 {
+			gologger.Info().Msgf("%s: security contact %s", host.Host, strings.Join(host.SecurityTxt.Contact, ", "))
+		}
+	}
+}
+
+// writeHostRateLimitReport displays the hosts throttled by the adaptive rate limit
+func (r *Runner) writeHostRateLimitReport() {
+	if r.hostRateLimiter == nil {
+		return
+	}
+	report := r.hostRateLimiter.Report()
+	if len(report) == 0 {
+		return
+	}
+	gologger.Info().Msgf("Throttled %d hosts with the adaptive rate limit", len(report))
+	for _, host := range report {
+		gologger.Info().Msgf("%s: throttled %d times (current rate: %.2f req/s)", host.Host, host.Throttled, host.Rate)
+	}
+}
+
 func (r *Runner) isInputNonHTTP() bool {
 	var nonURLInput bool
 	r.hmapInputProvider.Scan(func(value *contextargs.MetaInput) bool {
@@ -672,6 +845,7 @@ func (r *Runner) displayExecutionInfo(store *loader.Store) {
 		// only print these stats in verbose mode
 		stats.DisplayAsWarning(parsers.HeadlessFlagWarningStats)
 		stats.DisplayAsWarning(parsers.TemplatesExecutedStats)
+		stats.DisplayAsWarning(parsers.SuppressedTemplatesStats)
 	}
 	stats.DisplayAsWarning(parsers.UnsignedWarning)
 
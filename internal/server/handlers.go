@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// maxRequestBodySize is the maximum size of a scan submission
+const maxRequestBodySize = 10 * 1024 * 1024
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleSubmitScan(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	req := &ScanRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, "could not decode scan request: "+err.Error())
+		return
+	}
+	if len(req.Targets) == 0 {
+		writeError(w, http.StatusBadRequest, "no targets provided")
+		return
+	}
+	tenant := requestTenant(r)
+	if tenant != nil {
+		for _, path := range append(append([]string{}, req.Templates...), req.Workflows...) {
+			if !tenant.AllowsTemplate(path) {
+				writeError(w, http.StatusForbidden, "template not allowed for tenant: "+path)
+				return
+			}
+		}
+	}
+	scan, err := s.submit(req, tenant)
+	if err != nil {
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, scan.Info())
+}
+
+func (s *Server) handleListScans(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	scans := s.listScans(requestTenant(r))
+	infos := make([]ScanInfo, 0, len(scans))
+	for _, scan := range scans {
+		infos = append(infos, scan.Info())
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (s *Server) handleGetScan(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	scan, ok := s.getScan(params.ByName("id"), requestTenant(r))
+	if !ok {
+		writeError(w, http.StatusNotFound, "scan not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, scan.Info())
+}
+
+func (s *Server) handleCancelScan(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	scan, ok := s.getScan(params.ByName("id"), requestTenant(r))
+	if !ok {
+		writeError(w, http.StatusNotFound, "scan not found")
+		return
+	}
+	if !scan.Cancel() {
+		writeError(w, http.StatusConflict, "scan is not running")
+		return
+	}
+	writeJSON(w, http.StatusOK, scan.Info())
+}
+
+// handleDeleteScan cancels the scan if it is running and removes it with its results
+func (s *Server) handleDeleteScan(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	scan, ok := s.deleteScan(params.ByName("id"), requestTenant(r))
+	if !ok {
+		writeError(w, http.StatusNotFound, "scan not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, scan.Info())
+}
+
+// handleScanResults returns the results of a scan as a json array, or as
+// json lines written until the scan is done when stream=true is given
+func (s *Server) handleScanResults(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	scan, ok := s.getScan(params.ByName("id"), requestTenant(r))
+	if !ok {
+		writeError(w, http.StatusNotFound, "scan not found")
+		return
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+	if stream, _ := strconv.ParseBool(r.URL.Query().Get("stream")); !stream {
+		results, _, _ := scan.Results(offset)
+		if results == nil {
+			results = []*output.ResultEvent{}
+		}
+		writeJSON(w, http.StatusOK, results)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for {
+		results, done, updated := scan.Results(offset)
+		for _, result := range results {
+			if err := encoder.Encode(result); err != nil {
+				return
+			}
+		}
+		offset += len(results)
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if done {
+			return
+		}
+		select {
+		case <-updated:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
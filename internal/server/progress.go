@@ -0,0 +1,100 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/julienschmidt/httprouter"
+)
+
+// defaultProgressInterval is the default interval between streamed progress events
+const defaultProgressInterval = time.Second
+
+// ProgressEvent is a progress update of a scan streamed over websocket
+type ProgressEvent struct {
+	ScanID    string       `json:"scan_id"`
+	Status    ScanStatus   `json:"status"`
+	Results   int          `json:"results"`
+	Progress  ScanProgress `json:"progress"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// handleScanProgress streams progress events of a scan over a websocket
+// connection every interval seconds until the scan is done
+func (s *Server) handleScanProgress(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	scan, ok := s.getScan(params.ByName("id"), requestTenant(r))
+	if !ok {
+		writeError(w, http.StatusNotFound, "scan not found")
+		return
+	}
+	interval := defaultProgressInterval
+	if value := r.URL.Query().Get("interval"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 1 {
+			writeError(w, http.StatusBadRequest, "invalid progress interval")
+			return
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	conn, _, _, err := ws.UpgradeHTTP(r, w)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// frames sent by the client are discarded, reading them is
+	// only required to notice when the client closes the connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			header, err := ws.ReadHeader(conn)
+			if err != nil || header.OpCode == ws.OpClose {
+				return
+			}
+			if _, err := io.CopyN(io.Discard, conn, header.Length); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		info := scan.Info()
+		data, err := json.Marshal(ProgressEvent{
+			ScanID:    info.ID,
+			Status:    info.Status,
+			Results:   info.Results,
+			Progress:  info.Progress,
+			Timestamp: time.Now(),
+		})
+		if err != nil {
+			return
+		}
+		if err := wsutil.WriteServerText(conn, data); err != nil {
+			return
+		}
+		if info.Status != StatusRunning {
+			_ = ws.WriteFrame(conn, ws.NewCloseFrame(ws.NewCloseFrameBody(ws.StatusNormalClosure, "scan "+string(info.Status))))
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-closed:
+			return
+		}
+	}
+}
+
+// isWebSocketUpgrade returns true if the request is a websocket handshake
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
@@ -0,0 +1,431 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	nuclei "github.com/projectdiscovery/nuclei/v3/lib"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// ScanStatus is the state of a scan
+type ScanStatus string
+
+const (
+	// StatusRunning is the status of a scan being executed
+	StatusRunning ScanStatus = "running"
+	// StatusFinished is the status of a scan that completed
+	StatusFinished ScanStatus = "finished"
+	// StatusFailed is the status of a scan that could not be executed
+	StatusFailed ScanStatus = "failed"
+	// StatusCancelled is the status of a scan cancelled by the user
+	StatusCancelled ScanStatus = "cancelled"
+)
+
+// ScanRequest is the body of a scan submission
+type ScanRequest struct {
+	// Targets to scan (urls/domains/ips)
+	Targets []string `json:"targets"`
+	// Templates is the list of template files/directories to execute
+	Templates []string `json:"templates,omitempty"`
+	// Workflows is the list of workflow files/directories to execute
+	Workflows []string `json:"workflows,omitempty"`
+	// Filters contains the template filters of the scan
+	Filters ScanFilters `json:"filters,omitempty"`
+	// Config contains the scan configuration
+	Config ScanConfig `json:"config,omitempty"`
+}
+
+// ScanFilters contains the template filters of a scan
+type ScanFilters struct {
+	Severity             string   `json:"severity,omitempty"`
+	ExcludeSeverities    string   `json:"exclude_severity,omitempty"`
+	ProtocolTypes        string   `json:"protocol_type,omitempty"`
+	ExcludeProtocolTypes string   `json:"exclude_protocol_type,omitempty"`
+	Authors              []string `json:"authors,omitempty"`
+	Tags                 []string `json:"tags,omitempty"`
+	ExcludeTags          []string `json:"exclude_tags,omitempty"`
+	IncludeTags          []string `json:"include_tags,omitempty"`
+	IDs                  []string `json:"ids,omitempty"`
+	ExcludeIDs           []string `json:"exclude_ids,omitempty"`
+	TemplateCondition    []string `json:"template_condition,omitempty"`
+}
+
+// ScanConfig contains the configuration of a scan, zero values use nuclei defaults
+type ScanConfig struct {
+	RateLimit           int      `json:"rate_limit,omitempty"`
+	TemplateConcurrency int      `json:"template_concurrency,omitempty"`
+	HostConcurrency     int      `json:"host_concurrency,omitempty"`
+	Timeout             int      `json:"timeout,omitempty"`
+	Retries             int      `json:"retries,omitempty"`
+	ScanStrategy        string   `json:"scan_strategy,omitempty"`
+	Proxy               []string `json:"proxy,omitempty"`
+	Headless            bool     `json:"headless,omitempty"`
+}
+
+// ScanProgress contains the progress counters of a scan
+type ScanProgress struct {
+	Hosts     int64   `json:"hosts"`
+	Templates int64   `json:"templates"`
+	Total     int64   `json:"total"`
+	Requests  int64   `json:"requests"`
+	Matched   int64   `json:"matched"`
+	Errors    int64   `json:"errors"`
+	Percent   float64 `json:"percent"`
+	// HostsCompleted is estimated from the requests performed
+	HostsCompleted int64 `json:"hosts_completed"`
+	// RPS is the number of requests per second since the last progress update
+	RPS float64 `json:"rps"`
+	// MatchesBySeverity counts the results of the scan by severity
+	MatchesBySeverity map[string]int `json:"matches_by_severity,omitempty"`
+}
+
+// ScanInfo is the state of a scan returned by the api
+type ScanInfo struct {
+	ID         string       `json:"id"`
+	Tenant     string       `json:"tenant,omitempty"`
+	Status     ScanStatus   `json:"status"`
+	Targets    []string     `json:"targets"`
+	Error      string       `json:"error,omitempty"`
+	Results    int          `json:"results"`
+	Progress   ScanProgress `json:"progress"`
+	CreatedAt  time.Time    `json:"created_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+}
+
+// Scan is a scan submitted to the server
+type Scan struct {
+	ID        string
+	Request   *ScanRequest
+	CreatedAt time.Time
+	// Tenant is the tenant which submitted the scan, nil for the admin token
+	Tenant *Tenant
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex      sync.RWMutex
+	status     ScanStatus
+	progress   nuclei.ProgressEvent
+	progressAt time.Time
+	rps        float64
+	severities map[string]int
+	err        error
+	results    []*output.ResultEvent
+	finishedAt time.Time
+	// updated is closed and replaced whenever results or status change
+	updated chan struct{}
+}
+
+func newScan(id string, req *ScanRequest, tenant *Tenant) *Scan {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scan{
+		ID:         id,
+		Request:    req,
+		CreatedAt:  time.Now(),
+		Tenant:     tenant,
+		ctx:        ctx,
+		cancel:     cancel,
+		status:     StatusRunning,
+		severities: make(map[string]int),
+		updated:    make(chan struct{}),
+	}
+}
+
+// Info returns the current state of the scan
+func (s *Scan) Info() ScanInfo {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	info := ScanInfo{
+		ID:        s.ID,
+		Tenant:    tenantName(s.Tenant),
+		Status:    s.status,
+		Targets:   s.Request.Targets,
+		Results:   len(s.results),
+		CreatedAt: s.CreatedAt,
+		Progress: ScanProgress{
+			Hosts:     s.progress.Hosts,
+			Templates: s.progress.Templates,
+			Total:     s.progress.TotalRequests,
+			Requests:  s.progress.Requests,
+			Matched:   s.progress.Matched,
+			Errors:    s.progress.Errors,
+			Percent:   s.progress.Percent,
+			RPS:       s.rps,
+		},
+	}
+	info.Progress.HostsCompleted = int64(float64(s.progress.Hosts) * s.progress.Percent / 100)
+	if s.status == StatusFinished {
+		info.Progress.HostsCompleted = s.progress.Hosts
+	}
+	if len(s.severities) > 0 {
+		info.Progress.MatchesBySeverity = make(map[string]int, len(s.severities))
+		for severity, count := range s.severities {
+			info.Progress.MatchesBySeverity[severity] = count
+		}
+	}
+	if s.err != nil {
+		info.Error = s.err.Error()
+	}
+	if !s.finishedAt.IsZero() {
+		finishedAt := s.finishedAt
+		info.FinishedAt = &finishedAt
+	}
+	return info
+}
+
+// visibleTo returns true if the scan can be accessed by tenant, the admin (nil) can access all scans
+func (s *Scan) visibleTo(tenant *Tenant) bool {
+	return tenant == nil || s.Tenant == tenant
+}
+
+func (s *Scan) running() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.status == StatusRunning
+}
+
+// finished returns the time the scan is done at, false if it is running
+func (s *Scan) finished() (time.Time, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.finishedAt, s.status != StatusRunning
+}
+
+// Cancel cancels the scan aborting its in-flight requests
+func (s *Scan) Cancel() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.status != StatusRunning {
+		return false
+	}
+	s.cancel()
+	s.setStatus(StatusCancelled, nil)
+	return true
+}
+
+// Results returns the results starting at offset, whether the scan is
+// done and a channel closed on the next update of the scan
+func (s *Scan) Results(offset int) ([]*output.ResultEvent, bool, <-chan struct{}) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var results []*output.ResultEvent
+	if offset < len(s.results) {
+		results = s.results[offset:]
+	}
+	return results, s.status != StatusRunning, s.updated
+}
+
+func (s *Scan) addResult(event *output.ResultEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.status != StatusRunning {
+		return
+	}
+	s.results = append(s.results, event)
+	s.severities[event.Info.SeverityHolder.Severity.String()]++
+	s.notify()
+}
+
+func (s *Scan) setProgress(event nuclei.ProgressEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if !s.progressAt.IsZero() {
+		if elapsed := now.Sub(s.progressAt).Seconds(); elapsed > 0 {
+			s.rps = float64(event.Requests-s.progress.Requests) / elapsed
+		}
+	}
+	s.progress = event
+	s.progressAt = now
+}
+
+func (s *Scan) finish(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	defer s.cancel()
+
+	if s.status != StatusRunning {
+		return
+	}
+	if err != nil {
+		s.setStatus(StatusFailed, err)
+	} else {
+		s.setStatus(StatusFinished, nil)
+	}
+}
+
+// setStatus must be called with the mutex held
+func (s *Scan) setStatus(status ScanStatus, err error) {
+	s.status = status
+	s.err = err
+	s.finishedAt = time.Now()
+	s.notify()
+}
+
+// notify must be called with the mutex held
+func (s *Scan) notify() {
+	close(s.updated)
+	s.updated = make(chan struct{})
+}
+
+// templates returns the templates of the scan, scans of tenants
+// with a template set run the whole set if none is selected
+func (s *Scan) templates() []string {
+	if s.Tenant != nil && len(s.Request.Templates) == 0 && len(s.Request.Workflows) == 0 {
+		return s.Tenant.Templates
+	}
+	return s.Request.Templates
+}
+
+// sdkOptions returns the nuclei sdk options for the scan
+func (s *Scan) sdkOptions() []nuclei.NucleiSDKOptions {
+	req := s.Request
+	defaults := types.DefaultOptions()
+
+	opts := []nuclei.NucleiSDKOptions{
+		nuclei.WithTemplatesOrWorkflows(nuclei.TemplateSources{
+			Templates: s.templates(),
+			Workflows: req.Workflows,
+		}),
+		nuclei.WithTemplateFilters(nuclei.TemplateFilters{
+			Severity:             req.Filters.Severity,
+			ExcludeSeverities:    req.Filters.ExcludeSeverities,
+			ProtocolTypes:        req.Filters.ProtocolTypes,
+			ExcludeProtocolTypes: req.Filters.ExcludeProtocolTypes,
+			Authors:              req.Filters.Authors,
+			Tags:                 req.Filters.Tags,
+			ExcludeTags:          req.Filters.ExcludeTags,
+			IncludeTags:          req.Filters.IncludeTags,
+			IDs:                  req.Filters.IDs,
+			ExcludeIDs:           req.Filters.ExcludeIDs,
+			TemplateCondition:    req.Filters.TemplateCondition,
+		}),
+		nuclei.WithProgressCallback(time.Second, s.setProgress),
+	}
+
+	cfg := req.Config
+	if s.Tenant != nil && s.Tenant.limiter != nil {
+		// the budget of the tenant is shared by all its scans and can't be raised by a scan
+		opts = append(opts, nuclei.WithSharedRateLimiter(s.Tenant.limiter))
+	} else if cfg.RateLimit > 0 {
+		opts = append(opts, nuclei.WithGlobalRateLimit(cfg.RateLimit, time.Second))
+	}
+	if s.Tenant != nil && s.Tenant.ReportingConfig != "" {
+		opts = append(opts, nuclei.WithReportingConfig(s.Tenant.ReportingConfig))
+	}
+	if cfg.TemplateConcurrency > 0 || cfg.HostConcurrency > 0 {
+		concurrency := nuclei.Concurrency{
+			TemplateConcurrency:         defaults.TemplateThreads,
+			HostConcurrency:             defaults.BulkSize,
+			HeadlessHostConcurrency:     defaults.HeadlessBulkSize,
+			HeadlessTemplateConcurrency: defaults.HeadlessTemplateThreads,
+		}
+		if cfg.TemplateConcurrency > 0 {
+			concurrency.TemplateConcurrency = cfg.TemplateConcurrency
+		}
+		if cfg.HostConcurrency > 0 {
+			concurrency.HostConcurrency = cfg.HostConcurrency
+		}
+		opts = append(opts, nuclei.WithConcurrency(concurrency))
+	}
+	if cfg.Timeout > 0 || cfg.Retries > 0 {
+		network := nuclei.NetworkConfig{
+			Timeout:      defaults.Timeout,
+			Retries:      defaults.Retries,
+			MaxHostError: defaults.MaxHostError,
+		}
+		if cfg.Timeout > 0 {
+			network.Timeout = cfg.Timeout
+		}
+		if cfg.Retries > 0 {
+			network.Retries = cfg.Retries
+		}
+		opts = append(opts, nuclei.WithNetworkConfig(network))
+	}
+	if cfg.ScanStrategy != "" {
+		opts = append(opts, nuclei.WithScanStrategy(cfg.ScanStrategy))
+	}
+	if len(cfg.Proxy) > 0 {
+		opts = append(opts, nuclei.WithProxy(cfg.Proxy, false))
+	}
+	if cfg.Headless {
+		opts = append(opts, nuclei.EnableHeadlessWithOpts(&nuclei.HeadlessOpts{PageTimeout: defaults.PageTimeout}))
+	}
+	return opts
+}
+
+// runScan executes the scan using the nuclei sdk
+func runScan(scan *Scan) error {
+	ne, err := nuclei.NewNucleiEngine(scan.sdkOptions()...)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not create nuclei engine")
+	}
+	defer ne.Close()
+
+	callback := scan.addResult
+	if scan.Tenant != nil && scan.Tenant.ResultsDirectory != "" {
+		store, err := newResultStore(scan.Tenant.ResultsDirectory, scan.ID)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		callback = func(event *output.ResultEvent) {
+			store.Write(event)
+			scan.addResult(event)
+		}
+	}
+
+	ne.LoadTargets(scan.Request.Targets, false)
+	if err := ne.ExecuteCallbackWithCtx(scan.ctx, callback); err != nil && scan.ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// resultStore writes the results of a scan to a jsonl file in the results directory of its tenant
+type resultStore struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func newResultStore(directory, scanID string) (*resultStore, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not create results directory")
+	}
+	file, err := os.OpenFile(filepath.Join(directory, scanID+".jsonl"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not create results file")
+	}
+	return &resultStore{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Write appends a result to the results file
+func (r *resultStore) Write(event *output.ResultEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.encoder.Encode(event); err != nil {
+		gologger.Warning().Msgf("Could not store result in %s: %s", r.file.Name(), err)
+	}
+}
+
+// Close closes the results file
+func (r *resultStore) Close() {
+	_ = r.file.Close()
+}
@@ -0,0 +1,306 @@
+// Package server implements the nuclei REST API server used to manage scans.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/projectdiscovery/gologger"
+	"github.com/rs/xid"
+)
+
+const (
+	// DefaultAddress is the default listen address of the server
+	DefaultAddress = "127.0.0.1:8822"
+	// DefaultRetention is the default duration the finished scans are kept
+	DefaultRetention = 24 * time.Hour
+	// DefaultMaxFinishedScans is the default maximum number of finished scans kept
+	DefaultMaxFinishedScans = 100
+
+	// evictionInterval is the interval of the removal of the expired scans
+	evictionInterval = time.Minute
+)
+
+// errTooManyScans is returned when a tenant reached its maximum number of running scans
+var errTooManyScans = errors.New("maximum number of running scans reached")
+
+// tenantContextKey is the request context key of the authenticated tenant
+type tenantContextKey struct{}
+
+// Options contains the configuration of the server
+type Options struct {
+	// Address is the address the server listens on
+	Address string
+	// Token is the token required in the Authorization header of api requests,
+	// it is the admin token managing the scans of all the tenants
+	Token string
+	// Tenants are the teams sharing the server with their own token
+	Tenants []*Tenant
+	// Retention is the duration the finished scans and their results are
+	// kept after they are done, DefaultRetention if zero
+	Retention time.Duration
+	// MaxFinishedScans is the maximum number of finished scans kept, the
+	// oldest are removed first, DefaultMaxFinishedScans if zero
+	MaxFinishedScans int
+}
+
+// Server is the nuclei REST API server
+type Server struct {
+	options *Options
+	server  *http.Server
+
+	mutex sync.RWMutex
+	scans map[string]*Scan
+	// done stops the removal of the expired scans
+	done chan struct{}
+
+	// execute runs a scan and returns once it is complete
+	execute func(scan *Scan) error
+}
+
+// New creates a new server, a random token is generated if none is provided
+func New(options *Options) (*Server, error) {
+	if options.Address == "" {
+		options.Address = DefaultAddress
+	}
+	if options.Token == "" {
+		token, err := generateToken()
+		if err != nil {
+			return nil, err
+		}
+		options.Token = token
+		gologger.Info().Msgf("Generated api token: %s", token)
+	}
+	if options.Retention <= 0 {
+		options.Retention = DefaultRetention
+	}
+	if options.MaxFinishedScans <= 0 {
+		options.MaxFinishedScans = DefaultMaxFinishedScans
+	}
+	for _, tenant := range options.Tenants {
+		tenant.start()
+	}
+	s := &Server{
+		options: options,
+		scans:   make(map[string]*Scan),
+		done:    make(chan struct{}),
+		execute: runScan,
+	}
+	go s.evictExpired()
+	s.server = &http.Server{
+		Addr:              options.Address,
+		Handler:           s.router(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s, nil
+}
+
+// ListenAndServe starts serving the api
+func (s *Server) ListenAndServe() error {
+	gologger.Info().Msgf("Listening nuclei api server on: %s", s.options.Address)
+	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Close cancels running scans and stops the server
+func (s *Server) Close() {
+	s.mutex.RLock()
+	for _, scan := range s.scans {
+		scan.Cancel()
+	}
+	s.mutex.RUnlock()
+	close(s.done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = s.server.Shutdown(ctx)
+
+	for _, tenant := range s.options.Tenants {
+		tenant.close()
+	}
+}
+
+func (s *Server) router() http.Handler {
+	router := httprouter.New()
+	router.POST("/api/v1/scans", s.authenticated(s.handleSubmitScan))
+	router.GET("/api/v1/scans", s.authenticated(s.handleListScans))
+	router.GET("/api/v1/scans/:id", s.authenticated(s.handleGetScan))
+	router.DELETE("/api/v1/scans/:id", s.authenticated(s.handleDeleteScan))
+	router.POST("/api/v1/scans/:id/cancel", s.authenticated(s.handleCancelScan))
+	router.GET("/api/v1/scans/:id/results", s.authenticated(s.handleScanResults))
+	router.GET("/api/v1/scans/:id/progress", s.authenticated(s.handleScanProgress))
+	return router
+}
+
+// authenticated rejects requests without a valid bearer token, websocket
+// handshakes can provide it with the token query parameter instead.
+// The tenant owning the token is added to the request context.
+func (s *Server) authenticated(handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		// browsers can not set headers on websocket handshakes
+		if token == "" && isWebSocketUpgrade(r) {
+			token = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.options.Token)) == 1 {
+			handle(w, r, params)
+			return
+		}
+		for _, tenant := range s.options.Tenants {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(tenant.Token)) == 1 {
+				handle(w, r.WithContext(context.WithValue(r.Context(), tenantContextKey{}, tenant)), params)
+				return
+			}
+		}
+		writeError(w, http.StatusUnauthorized, "invalid or missing api token")
+	}
+}
+
+// requestTenant returns the tenant of an authenticated request, nil for the admin token
+func requestTenant(r *http.Request) *Tenant {
+	tenant, _ := r.Context().Value(tenantContextKey{}).(*Tenant)
+	return tenant
+}
+
+// submit registers a new scan of a tenant and starts executing it
+func (s *Server) submit(req *ScanRequest, tenant *Tenant) (*Scan, error) {
+	scan := newScan(xid.New().String(), req, tenant)
+
+	s.mutex.Lock()
+	s.evict(time.Now())
+	if tenant != nil && tenant.MaxScans > 0 && s.runningScans(tenant) >= tenant.MaxScans {
+		s.mutex.Unlock()
+		return nil, errTooManyScans
+	}
+	s.scans[scan.ID] = scan
+	s.mutex.Unlock()
+
+	go func() {
+		err := s.execute(scan)
+		if err != nil {
+			gologger.Warning().Msgf("Scan %s failed: %s", scan.ID, err)
+		}
+		scan.finish(err)
+	}()
+	return scan, nil
+}
+
+// runningScans returns the number of running scans of a tenant, it must be called with the mutex held
+func (s *Server) runningScans(tenant *Tenant) int {
+	var count int
+	for _, scan := range s.scans {
+		if scan.Tenant == tenant && scan.running() {
+			count++
+		}
+	}
+	return count
+}
+
+// getScan returns a scan visible to tenant, tenants can only access their own scans
+func (s *Server) getScan(id string, tenant *Tenant) (*Scan, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	scan, ok := s.scans[id]
+	if !ok || !scan.visibleTo(tenant) {
+		return nil, false
+	}
+	return scan, true
+}
+
+// deleteScan cancels a scan visible to tenant if it is running and removes it with its results
+func (s *Server) deleteScan(id string, tenant *Tenant) (*Scan, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	scan, ok := s.scans[id]
+	if !ok || !scan.visibleTo(tenant) {
+		return nil, false
+	}
+	scan.Cancel()
+	delete(s.scans, id)
+	return scan, true
+}
+
+// evictExpired periodically removes the scans finished for longer than the retention
+func (s *Server) evictExpired() {
+	ticker := time.NewTicker(evictionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.mutex.Lock()
+			s.evict(now)
+			s.mutex.Unlock()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// evict removes the scans finished for longer than the retention and the oldest
+// finished scans above the maximum, it must be called with the mutex held
+func (s *Server) evict(now time.Time) {
+	type finishedScan struct {
+		id string
+		at time.Time
+	}
+	var finished []finishedScan
+	for id, scan := range s.scans {
+		finishedAt, ok := scan.finished()
+		if !ok {
+			continue
+		}
+		if now.Sub(finishedAt) > s.options.Retention {
+			delete(s.scans, id)
+			continue
+		}
+		finished = append(finished, finishedScan{id: id, at: finishedAt})
+	}
+	if len(finished) <= s.options.MaxFinishedScans {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].at.Before(finished[j].at)
+	})
+	for _, scan := range finished[:len(finished)-s.options.MaxFinishedScans] {
+		delete(s.scans, scan.id)
+	}
+}
+
+// listScans returns the scans visible to tenant sorted by creation time
+func (s *Server) listScans(tenant *Tenant) []*Scan {
+	s.mutex.RLock()
+	scans := make([]*Scan, 0, len(s.scans))
+	for _, scan := range s.scans {
+		if scan.visibleTo(tenant) {
+			scans = append(scans, scan)
+		}
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(scans, func(i, j int) bool {
+		return scans[i].CreatedAt.Before(scans[j].CreatedAt)
+	})
+	return scans
+}
+
+func generateToken() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
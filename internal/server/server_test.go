@@ -0,0 +1,276 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	nuclei "github.com/projectdiscovery/nuclei/v3/lib"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, execute func(scan *Scan) error) *httptest.Server {
+	s, err := New(&Options{Token: "secret"})
+	require.Nil(t, err, "could not create server")
+	s.execute = execute
+
+	ts := httptest.NewServer(s.router())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func doRequest(t *testing.T, method, url, token, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.Nil(t, err, "could not create request")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.Nil(t, err, "could not do request")
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestServerAuthentication(t *testing.T) {
+	ts := newTestServer(t, func(scan *Scan) error { return nil })
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "", "")
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "invalid", "")
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServerScanLifecycle(t *testing.T) {
+	release := make(chan struct{})
+	ts := newTestServer(t, func(scan *Scan) error {
+		scan.addResult(&output.ResultEvent{TemplateID: "first", Host: scan.Request.Targets[0]})
+		<-release
+		scan.addResult(&output.ResultEvent{TemplateID: "second", Host: scan.Request.Targets[0]})
+		return nil
+	})
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans", "secret", `{}`)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, "scan without targets was accepted")
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans", "secret", `{"targets":["scanme.sh"],"filters":{"tags":["tech"]}}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	info := ScanInfo{}
+	require.Nil(t, json.NewDecoder(resp.Body).Decode(&info))
+	require.Equal(t, StatusRunning, info.Status)
+	require.Equal(t, []string{"scanme.sh"}, info.Targets)
+
+	stream := doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans/"+info.ID+"/results?stream=true", "secret", "")
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+	reader := bufio.NewScanner(stream.Body)
+
+	var templateIDs []string
+	for reader.Scan() {
+		event := map[string]interface{}{}
+		require.Nil(t, json.Unmarshal(reader.Bytes(), &event))
+		templateIDs = append(templateIDs, event["template-id"].(string))
+		if len(templateIDs) == 1 {
+			close(release)
+		}
+	}
+	require.Equal(t, []string{"first", "second"}, templateIDs, "could not stream results")
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans/"+info.ID, "secret", "")
+	require.Nil(t, json.NewDecoder(resp.Body).Decode(&info))
+	require.Equal(t, StatusFinished, info.Status)
+	require.Equal(t, 2, info.Results)
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans/"+info.ID+"/cancel", "secret", "")
+	require.Equal(t, http.StatusConflict, resp.StatusCode, "finished scan was cancelled")
+
+	resp = doRequest(t, http.MethodDelete, ts.URL+"/api/v1/scans/"+info.ID, "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans/"+info.ID, "secret", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode, "deleted scan was kept")
+}
+
+func TestServerCancelScan(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	ts := newTestServer(t, func(scan *Scan) error {
+		<-release
+		scan.addResult(&output.ResultEvent{TemplateID: "late"})
+		return nil
+	})
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans", "secret", `{"targets":["scanme.sh"]}`)
+	info := ScanInfo{}
+	require.Nil(t, json.NewDecoder(resp.Body).Decode(&info))
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans/"+info.ID+"/cancel", "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Nil(t, json.NewDecoder(resp.Body).Decode(&info))
+	require.Equal(t, StatusCancelled, info.Status)
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans/unknown", "secret", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServerScanEviction(t *testing.T) {
+	s, err := New(&Options{Token: "secret", Retention: time.Hour, MaxFinishedScans: 2})
+	require.Nil(t, err, "could not create server")
+	t.Cleanup(s.Close)
+	release := make(chan struct{})
+	defer close(release)
+	s.execute = func(scan *Scan) error {
+		if scan.Request.Targets[0] == "running" {
+			<-release
+		}
+		return nil
+	}
+
+	running, err := s.submit(&ScanRequest{Targets: []string{"running"}}, nil)
+	require.Nil(t, err)
+	var finished []*Scan
+	for i := 0; i < 3; i++ {
+		scan, err := s.submit(&ScanRequest{Targets: []string{"finished"}}, nil)
+		require.Nil(t, err)
+		require.Eventually(t, func() bool { _, ok := scan.finished(); return ok }, time.Second, 10*time.Millisecond)
+		finished = append(finished, scan)
+	}
+
+	s.mutex.Lock()
+	s.evict(time.Now())
+	s.mutex.Unlock()
+	_, ok := s.getScan(finished[0].ID, nil)
+	require.False(t, ok, "could not remove the oldest finished scan above the maximum")
+	require.Len(t, s.listScans(nil), 3)
+
+	s.mutex.Lock()
+	s.evict(time.Now().Add(2 * time.Hour))
+	s.mutex.Unlock()
+	scans := s.listScans(nil)
+	require.Len(t, scans, 1, "could not remove the expired finished scans")
+	require.Equal(t, running.ID, scans[0].ID, "running scan was removed")
+}
+
+func TestServerScanProgress(t *testing.T) {
+	release := make(chan struct{})
+	ts := newTestServer(t, func(scan *Scan) error {
+		scan.setProgress(nuclei.ProgressEvent{Hosts: 2, TotalRequests: 10, Requests: 5, Percent: 50})
+		scan.addResult(&output.ResultEvent{TemplateID: "first", Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.High}}})
+		<-release
+		return nil
+	})
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans", "secret", `{"targets":["a.scanme.sh","b.scanme.sh"]}`)
+	info := ScanInfo{}
+	require.Nil(t, json.NewDecoder(resp.Body).Decode(&info))
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/v1/scans/" + info.ID + "/progress"
+	_, _, _, err := ws.Dial(context.Background(), wsURL)
+	require.NotNil(t, err, "could not reject websocket without token")
+
+	conn, _, _, err := ws.Dial(context.Background(), wsURL+"?token=secret")
+	require.Nil(t, err, "could not connect to progress websocket")
+	defer conn.Close()
+
+	var events []ProgressEvent
+	released := false
+	for {
+		data, err := wsutil.ReadServerText(conn)
+		if err != nil {
+			break
+		}
+		event := ProgressEvent{}
+		require.Nil(t, json.Unmarshal(data, &event))
+		events = append(events, event)
+		if event.Results == 1 && !released {
+			close(release)
+			released = true
+		}
+		if event.Status != StatusRunning {
+			break
+		}
+	}
+	require.NotEmpty(t, events, "could not stream progress events")
+	last := events[len(events)-1]
+	require.Equal(t, StatusFinished, last.Status)
+	require.Equal(t, int64(2), last.Progress.HostsCompleted)
+	require.Equal(t, map[string]int{"high": 1}, last.Progress.MatchesBySeverity)
+}
+
+func TestServerTenants(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	teamA := &Tenant{Name: "team-a", Token: "token-a", Templates: []string{"/templates/team-a"}, MaxScans: 1, RateLimit: 10}
+	teamB := &Tenant{Name: "team-b", Token: "token-b"}
+	s, err := New(&Options{Token: "secret", Tenants: []*Tenant{teamA, teamB}})
+	require.Nil(t, err, "could not create server")
+	s.execute = func(scan *Scan) error {
+		<-release
+		return nil
+	}
+	ts := httptest.NewServer(s.router())
+	t.Cleanup(ts.Close)
+	t.Cleanup(s.Close)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans", "token-a", `{"targets":["scanme.sh"],"templates":["/templates/team-b/cves"]}`)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode, "could not restrict tenant templates")
+	resp = doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans", "token-a", `{"targets":["scanme.sh"],"templates":["/templates/team-a/../team-b"]}`)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode, "could not restrict tenant templates with traversal")
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans", "token-a", `{"targets":["scanme.sh"]}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	info := ScanInfo{}
+	require.Nil(t, json.NewDecoder(resp.Body).Decode(&info))
+	require.Equal(t, "team-a", info.Tenant)
+	scan, ok := s.getScan(info.ID, nil)
+	require.True(t, ok)
+	require.Equal(t, []string{"/templates/team-a"}, scan.templates(), "could not default to tenant templates")
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans", "token-a", `{"targets":["scanme.sh"]}`)
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "could not limit running scans")
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans/"+info.ID, "token-b", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode, "tenant could access scan of another tenant")
+	resp = doRequest(t, http.MethodPost, ts.URL+"/api/v1/scans/"+info.ID+"/cancel", "token-b", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode, "tenant could cancel scan of another tenant")
+	resp = doRequest(t, http.MethodDelete, ts.URL+"/api/v1/scans/"+info.ID, "token-b", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode, "tenant could delete scan of another tenant")
+
+	var infos []ScanInfo
+	resp = doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "token-b", "")
+	require.Nil(t, json.NewDecoder(resp.Body).Decode(&infos))
+	require.Empty(t, infos, "tenant could list scans of another tenant")
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "secret", "")
+	require.Nil(t, json.NewDecoder(resp.Body).Decode(&infos))
+	require.Len(t, infos, 1, "admin could not list tenant scans")
+}
+
+func TestLoadTenants(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tenants.yaml")
+	require.Nil(t, os.WriteFile(file, []byte("tenants:\n  - name: team-a\n    token: a\n    rate-limit: 50\n    results-directory: /var/lib/nuclei/team-a\n  - name: team-b\n    token: a\n"), 0600))
+	_, err := LoadTenants(file)
+	require.NotNil(t, err, "could not detect reused token")
+
+	require.Nil(t, os.WriteFile(file, []byte("tenants:\n  - name: team-a\n    token: a\n    rate-limit: 50\n    results-directory: /var/lib/nuclei/team-a\n"), 0600))
+	tenants, err := LoadTenants(file)
+	require.Nil(t, err, "could not load tenants")
+	require.Equal(t, []*Tenant{{Name: "team-a", Token: "a", RateLimit: 50, ResultsDirectory: "/var/lib/nuclei/team-a"}}, tenants)
+
+	require.Nil(t, os.WriteFile(file, []byte("tenants:\n  - name: team-a\n"), 0600))
+	_, err = LoadTenants(file)
+	require.NotNil(t, err, "could not require tenant token")
+}
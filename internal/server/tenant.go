@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yaml"
+	"github.com/projectdiscovery/ratelimit"
+)
+
+// Tenant is a team sharing the server with its own api token, template set,
+// rate limit budget, result storage and reporting configuration
+type Tenant struct {
+	// Name is the name of the tenant shown in its scans
+	Name string `yaml:"name" validate:"required"`
+	// Token is the api token of the tenant
+	Token string `yaml:"token" validate:"required"`
+	// Templates are the template and workflow paths the tenant is allowed
+	// to run, they are used for scans not selecting templates
+	Templates []string `yaml:"templates,omitempty"`
+	// RateLimit is the number of requests per second shared by all the running scans of the tenant
+	RateLimit int `yaml:"rate-limit,omitempty"`
+	// MaxScans is the maximum number of running scans of the tenant
+	MaxScans int `yaml:"max-scans,omitempty"`
+	// ResultsDirectory is the directory the results of the scans are written to in jsonl format
+	ResultsDirectory string `yaml:"results-directory,omitempty"`
+	// ReportingConfig is the reporting config file used by the scans of the tenant
+	ReportingConfig string `yaml:"reporting-config,omitempty"`
+
+	limiter *ratelimit.Limiter
+}
+
+type tenantsFile struct {
+	Tenants []*Tenant `yaml:"tenants" validate:"dive"`
+}
+
+// LoadTenants loads the tenants of the server from a yaml file
+func LoadTenants(file string) ([]*Tenant, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open tenants file")
+	}
+	defer f.Close()
+
+	config := &tenantsFile{}
+	if err := yaml.DecodeAndValidate(f, config); err != nil {
+		return nil, errors.Wrap(err, "could not parse tenants file")
+	}
+	names := make(map[string]struct{}, len(config.Tenants))
+	tokens := make(map[string]struct{}, len(config.Tenants))
+	for _, tenant := range config.Tenants {
+		if _, ok := names[tenant.Name]; ok {
+			return nil, errors.Errorf("duplicate tenant name %s", tenant.Name)
+		}
+		if _, ok := tokens[tenant.Token]; ok {
+			return nil, errors.Errorf("tenant %s reuses the token of another tenant", tenant.Name)
+		}
+		names[tenant.Name] = struct{}{}
+		tokens[tenant.Token] = struct{}{}
+	}
+	return config.Tenants, nil
+}
+
+// AllowsTemplate returns true if the template path is within the template set of the tenant
+func (t *Tenant) AllowsTemplate(path string) bool {
+	if len(t.Templates) == 0 {
+		return true
+	}
+	path = filepath.Clean(path)
+	for _, allowed := range t.Templates {
+		allowed = filepath.Clean(allowed)
+		if path == allowed || strings.HasPrefix(path, allowed+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// start creates the rate limiter shared by the scans of the tenant
+func (t *Tenant) start() {
+	if t.RateLimit > 0 {
+		t.limiter = ratelimit.New(context.Background(), uint(t.RateLimit), time.Second)
+	}
+}
+
+// close stops the rate limiter of the tenant
+func (t *Tenant) close() {
+	if t.limiter != nil {
+		t.limiter.Stop()
+	}
+}
+
+// tenantName returns the name of a tenant, empty for scans of the admin token
+func tenantName(tenant *Tenant) string {
+	if tenant == nil {
+		return ""
+	}
+	return tenant.Name
+}
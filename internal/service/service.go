@@ -0,0 +1,33 @@
+// Package service installs nuclei as a system service (systemd on linux and
+// the service control manager on windows) and handles shutdown requests.
+package service
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+)
+
+const (
+	// Name is the name of the installed service
+	Name = "nuclei"
+	// Description is the description of the installed service
+	Description = "Nuclei vulnerability scanner"
+)
+
+// NotifyShutdown relays interrupts, termination signals and stop requests of the
+// service manager to c so that nuclei can flush the scan state before exiting
+func NotifyShutdown(c chan<- os.Signal) {
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	notifyServiceManager(c)
+}
+
+// executable returns the absolute path of the running nuclei binary
+func executable() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(path)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// systemdUnitDir is the directory systemd units are installed to
+var systemdUnitDir = "/etc/systemd/system"
+
+// Install installs a systemd unit running nuclei with args and enables it
+func Install(args []string) error {
+	path, err := executable()
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not get nuclei executable")
+	}
+	unitFile := filepath.Join(systemdUnitDir, Name+".service")
+	if fileutil.FileExists(unitFile) {
+		return errorutil.New("service %s is already installed at %s", Name, unitFile)
+	}
+	if err := os.WriteFile(unitFile, []byte(systemdUnit(path, args)), 0644); err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not write systemd unit %s", unitFile)
+	}
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	return systemctl("enable", Name+".service")
+}
+
+// Uninstall stops, disables and removes the systemd unit of nuclei
+func Uninstall() error {
+	unitFile := filepath.Join(systemdUnitDir, Name+".service")
+	if !fileutil.FileExists(unitFile) {
+		return errorutil.New("service %s is not installed", Name)
+	}
+	if err := systemctl("disable", "--now", Name+".service"); err != nil {
+		return err
+	}
+	if err := os.Remove(unitFile); err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not remove systemd unit %s", unitFile)
+	}
+	return systemctl("daemon-reload")
+}
+
+// StartHint returns the command starting the installed service
+func StartHint() string {
+	return "systemctl start " + Name
+}
+
+// systemdUnit returns the systemd unit running executable with args, stop
+// requests send SIGTERM and wait for nuclei to flush the scan state. The scans
+// exceeding the -fail-on policy exit with runner.FailOnExitCode and are not restarted.
+func systemdUnit(executable string, args []string) string {
+	command := []string{quoteArgument(executable)}
+	for _, arg := range args {
+		command = append(command, quoteArgument(arg))
+	}
+	return fmt.Sprintf(`[Unit]
+Description=%s
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+ExecStart=%s
+Restart=on-failure
+RestartPreventExitStatus=2
+RestartSec=10
+KillSignal=SIGTERM
+TimeoutStopSec=60
+
+[Install]
+WantedBy=multi-user.target
+`, Description, strings.Join(command, " "))
+}
+
+// quoteArgument quotes an argument of ExecStart if it contains spaces or quotes
+func quoteArgument(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\") {
+		return arg
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(arg) + `"`
+}
+
+func systemctl(args ...string) error {
+	output, err := exec.Command("systemctl", args...).CombinedOutput()
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not run systemctl %s: %s", strings.Join(args, " "), strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// notifyServiceManager is not required for systemd which sends SIGTERM
+func notifyServiceManager(chan<- os.Signal) {}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdUnit(t *testing.T) {
+	unit := systemdUnit("/usr/local/bin/nuclei", []string{"server", "-saddr", "0.0.0.0:8822", "-H", "X-Team: red"})
+	require.Contains(t, unit, `ExecStart=/usr/local/bin/nuclei server -saddr 0.0.0.0:8822 -H "X-Team: red"`)
+	require.Contains(t, unit, "RestartPreventExitStatus=2")
+	require.Contains(t, unit, "KillSignal=SIGTERM")
+	require.Contains(t, unit, "WantedBy=multi-user.target")
+
+	require.Equal(t, `"say \"hi\""`, quoteArgument(`say "hi"`))
+	require.Equal(t, `""`, quoteArgument(""))
+}
@@ -0,0 +1,27 @@
+//go:build !linux && !windows
+
+package service
+
+import (
+	"os"
+	"runtime"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// Install is not supported on this platform
+func Install([]string) error {
+	return errorutil.New("service install is not supported on %s", runtime.GOOS)
+}
+
+// Uninstall is not supported on this platform
+func Uninstall() error {
+	return errorutil.New("service uninstall is not supported on %s", runtime.GOOS)
+}
+
+// StartHint returns the command starting the installed service
+func StartHint() string {
+	return ""
+}
+
+func notifyServiceManager(chan<- os.Signal) {}
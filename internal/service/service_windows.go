@@ -0,0 +1,112 @@
+package service
+
+import (
+	"os"
+	"time"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// stopTimeout is the time waited for the service to stop on uninstall
+const stopTimeout = 30 * time.Second
+
+// Install installs an automatically started windows service running nuclei with args
+func Install(args []string) error {
+	path, err := executable()
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not get nuclei executable")
+	}
+	manager, err := mgr.Connect()
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not connect to service manager")
+	}
+	defer manager.Disconnect()
+
+	if service, err := manager.OpenService(Name); err == nil {
+		service.Close()
+		return errorutil.New("service %s is already installed", Name)
+	}
+	service, err := manager.CreateService(Name, path, mgr.Config{
+		DisplayName: Description,
+		Description: Description,
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not create service %s", Name)
+	}
+	defer service.Close()
+
+	return service.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 10 * time.Second},
+	}, 0)
+}
+
+// Uninstall stops and removes the windows service of nuclei
+func Uninstall() error {
+	manager, err := mgr.Connect()
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not connect to service manager")
+	}
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(Name)
+	if err != nil {
+		return errorutil.New("service %s is not installed", Name)
+	}
+	defer service.Close()
+
+	if status, err := service.Control(svc.Stop); err == nil {
+		deadline := time.Now().Add(stopTimeout)
+		for status.State != svc.Stopped && time.Now().Before(deadline) {
+			time.Sleep(500 * time.Millisecond)
+			if status, err = service.Query(); err != nil {
+				break
+			}
+		}
+	}
+	if err := service.Delete(); err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not delete service %s", Name)
+	}
+	return nil
+}
+
+// StartHint returns the command starting the installed service
+func StartHint() string {
+	return "sc start " + Name
+}
+
+// notifyServiceManager reports nuclei as running to the service control manager
+// when started as a windows service and relays its stop requests to c
+func notifyServiceManager(c chan<- os.Signal) {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return
+	}
+	go func() {
+		_ = svc.Run(Name, &handler{shutdown: c})
+	}()
+}
+
+// handler handles the requests of the service control manager
+type handler struct {
+	shutdown chan<- os.Signal
+}
+
+// Execute implements svc.Handler
+func (h *handler) Execute(_ []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for request := range requests {
+		switch request.Cmd {
+		case svc.Interrogate:
+			status <- request.CurrentStatus
+		case svc.Stop, svc.Shutdown:
+			status <- svc.Status{State: svc.StopPending}
+			h.shutdown <- os.Interrupt
+			// the process exits once the scan state is flushed
+			select {}
+		}
+	}
+	return false, 0
+}
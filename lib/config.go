@@ -6,6 +6,7 @@ import (
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
@@ -89,6 +90,15 @@ func WithTemplateFilters(filters TemplateFilters) NucleiSDKOptions {
 	}
 }
 
+// WithTemplateOverrides sets the file mapping template ids to severity, tags
+// or suppression overrides applied when templates are loaded
+func WithTemplateOverrides(file string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.TemplateOverrides = file
+		return nil
+	}
+}
+
 // InteractshOpts contains options for interactsh
 type InteractshOpts interactsh.Options
 
@@ -131,12 +141,24 @@ func WithGlobalRateLimit(maxTokens int, duration time.Duration) NucleiSDKOptions
 	}
 }
 
+// WithSharedRateLimiter uses a rate limiter shared with other engines, the
+// limiter is owned by the caller and is not stopped when the engine is closed
+func WithSharedRateLimiter(limiter *ratelimit.Limiter) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.rateLimiter = limiter
+		e.sharedRateLimiter = true
+		return nil
+	}
+}
+
 // HeadlessOpts contains options for headless templates
 type HeadlessOpts struct {
 	PageTimeout     int // timeout for page load
 	ShowBrowser     bool
 	HeadlessOptions []string
 	UseChrome       bool
+	// RemoteURL is the devtools url of an already running browser
+	RemoteURL string
 }
 
 // EnableHeadless allows execution of headless templates
@@ -150,8 +172,9 @@ func EnableHeadlessWithOpts(hopts *HeadlessOpts) NucleiSDKOptions {
 			e.opts.PageTimeout = hopts.PageTimeout
 			e.opts.ShowBrowser = hopts.ShowBrowser
 			e.opts.UseInstalledChrome = hopts.UseChrome
+			e.opts.HeadlessRemoteURL = hopts.RemoteURL
 		}
-		if engine.MustDisableSandbox() {
+		if e.opts.HeadlessRemoteURL == "" && engine.MustDisableSandbox() {
 			gologger.Warning().Msgf("The current platform and privileged user will run the browser without sandbox\n")
 		}
 		browser, err := engine.New(e.opts)
@@ -188,6 +211,22 @@ func EnableStatsWithOpts(opts StatsOptions) NucleiSDKOptions {
 	}
 }
 
+// WithProgressCallback sets a callback which is called with the statistics of a running
+// scan every interval (5 seconds if zero) and once with Done set when the scan completes
+func WithProgressCallback(interval time.Duration, callback func(event ProgressEvent)) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		if e.mode == threadSafe {
+			return ErrOptionsNotSupported.Msgf("WithProgressCallback")
+		}
+		if interval <= 0 {
+			interval = 5 * time.Second
+		}
+		e.progressInterval = interval
+		e.progressCallback = callback
+		return nil
+	}
+}
+
 // VerbosityOptions
 type VerbosityOptions struct {
 	Verbose       bool // show verbose output
@@ -254,6 +293,45 @@ func WithProxy(proxy []string, proxyInternalRequests bool) NucleiSDKOptions {
 	}
 }
 
+// WithProxyRotation allows rotating http requests across the proxies set with WithProxy
+// using round-robin or sticky (same proxy per host) strategy
+func WithProxyRotation(strategy string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		if e.mode == threadSafe {
+			return ErrOptionsNotSupported.Msgf("WithProxyRotation")
+		}
+		e.opts.ProxyRotation = strategy
+		return nil
+	}
+}
+
+// EnableFIPSMode restricts tls and dsl crypto to FIPS approved algorithms,
+// templates using other algorithms fail to load
+func EnableFIPSMode() NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.FIPS = true
+		return nil
+	}
+}
+
+// WithPlugins loads matcher and extractor plugins from executables
+// (or directories of them) which templates can use with the plugin type
+func WithPlugins(paths ...string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.Plugins = append(e.opts.Plugins, paths...)
+		return nil
+	}
+}
+
+// WithDSLFunction registers an additional dsl helper function which can be used
+// in templates. Functions are global and shared by all engines in the process,
+// they must be registered before the first engine of the process is initialized
+func WithDSLFunction(name string, function func(args ...interface{}) (interface{}, error)) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		return dsl.AddHelperFunction(name, function)
+	}
+}
+
 // WithScanStrategy allows setting scan strategy options
 func WithScanStrategy(strategy string) NucleiSDKOptions {
 	return func(e *NucleiEngine) error {
@@ -319,3 +397,26 @@ func WithSandboxOptions(allowLocalFileAccess bool, restrictLocalNetworkAccess bo
 		return nil
 	}
 }
+
+// WithReportingConfig creates issues and exports results using a reporting config file
+func WithReportingConfig(file string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		if e.mode == threadSafe {
+			return ErrOptionsNotSupported.Msgf("WithReportingConfig")
+		}
+		e.opts.ReportingConfig = file
+		return nil
+	}
+}
+
+// WithDisallowPrivateIPs rejects connections to private and reserved addresses
+// at the dialer level including redirects, re-resolved hosts and interactsh polling
+func WithDisallowPrivateIPs() NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		if e.mode == threadSafe {
+			return ErrOptionsNotSupported.Msgf("WithDisallowPrivateIPs")
+		}
+		e.opts.DisallowPrivateIPs = true
+		return nil
+	}
+}
@@ -15,13 +15,33 @@ import (
 // GetTargetsFromUncover returns targets from uncover in given format .
 // supported formats are any string with [ip,host,port,url] placeholders
 func GetTargetsFromUncover(ctx context.Context, outputFormat string, opts *uncover.Options) (chan string, error) {
-	return uncoverNuclei.GetTargetsFromUncover(ctx, outputFormat, opts)
+	ch, err := uncoverNuclei.GetTargetsFromUncover(ctx, outputFormat, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return targetValues(ctx, ch), nil
 }
 
 // GetTargetsFromTemplateMetadata returns all targets by querying engine metadata (ex: fofo-query,shodan-query) etc from given templates .
 // supported formats are any string with [ip,host,port,url] placeholders
 func GetTargetsFromTemplateMetadata(ctx context.Context, templates []*templates.Template, outputFormat string, opts *uncover.Options) chan string {
-	return uncoverNuclei.GetUncoverTargetsFromMetadata(ctx, templates, outputFormat, opts)
+	return targetValues(ctx, uncoverNuclei.GetUncoverTargetsFromMetadata(ctx, templates, outputFormat, opts, nil))
+}
+
+// targetValues returns the values of the uncover targets without their metadata
+func targetValues(ctx context.Context, targets chan uncoverNuclei.Target) chan string {
+	values := make(chan string)
+	go func() {
+		defer close(values)
+		for target := range targets {
+			select {
+			case values <- target.Value:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return values
 }
 
 // DefaultConfig is instance of default nuclei configs
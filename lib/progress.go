@@ -0,0 +1,135 @@
+package nuclei
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
+)
+
+// ProgressEvent contains the statistics of a running scan
+type ProgressEvent struct {
+	Hosts         int64   // number of hosts being scanned
+	Templates     int64   // number of templates being executed
+	TotalRequests int64   // estimated total number of requests
+	Requests      int64   // number of requests performed
+	Matched       int64   // number of matches found
+	Errors        int64   // number of errors
+	Connections   int64   // number of http connections used with the connections reuse
+	Reused        int64   // number of reused http connections
+	Percent       float64 // percentage of requests performed
+	Done          bool    // true for the last event of a scan
+}
+
+var _ progress.Progress = &progressTracker{}
+
+// progressTracker records the statistics of a scan for progress events
+// and forwards them to the underlying progress client
+type progressTracker struct {
+	progress.Progress
+
+	hosts     atomic.Int64
+	templates atomic.Int64
+	total     atomic.Int64
+	requests  atomic.Int64
+	matched   atomic.Int64
+	errors    atomic.Int64
+
+	connections atomic.Int64
+	reused      atomic.Int64
+}
+
+func (p *progressTracker) Init(hostCount int64, rulesCount int, requestCount int64) {
+	p.hosts.Store(hostCount)
+	p.templates.Store(int64(rulesCount))
+	p.total.Store(requestCount)
+	p.Progress.Init(hostCount, rulesCount, requestCount)
+}
+
+func (p *progressTracker) AddToTotal(delta int64) {
+	p.total.Add(delta)
+	p.Progress.AddToTotal(delta)
+}
+
+func (p *progressTracker) IncrementRequests() {
+	p.requests.Add(1)
+	p.Progress.IncrementRequests()
+}
+
+func (p *progressTracker) SetRequests(count uint64) {
+	p.requests.Add(int64(count))
+	p.Progress.SetRequests(count)
+}
+
+func (p *progressTracker) IncrementMatched() {
+	p.matched.Add(1)
+	p.Progress.IncrementMatched()
+}
+
+func (p *progressTracker) IncrementErrorsBy(count int64) {
+	p.errors.Add(count)
+	p.Progress.IncrementErrorsBy(count)
+}
+
+func (p *progressTracker) IncrementFailedRequestsBy(count int64) {
+	p.requests.Add(count)
+	p.errors.Add(count)
+	p.Progress.IncrementFailedRequestsBy(count)
+}
+
+func (p *progressTracker) IncrementConnections(reused bool) {
+	p.connections.Add(1)
+	if reused {
+		p.reused.Add(1)
+	}
+	p.Progress.IncrementConnections(reused)
+}
+
+// event returns a progress event with current statistics
+func (p *progressTracker) event() ProgressEvent {
+	event := ProgressEvent{
+		Hosts:         p.hosts.Load(),
+		Templates:     p.templates.Load(),
+		TotalRequests: p.total.Load(),
+		Requests:      p.requests.Load(),
+		Matched:       p.matched.Load(),
+		Errors:        p.errors.Load(),
+		Connections:   p.connections.Load(),
+		Reused:        p.reused.Load(),
+	}
+	if event.TotalRequests > 0 {
+		event.Percent = float64(event.Requests) * 100 / float64(event.TotalRequests)
+		if event.Percent > 100 {
+			event.Percent = 100
+		}
+	}
+	return event
+}
+
+// report calls callback with progress events every interval until
+// the returned function is called, which sends the final event
+func (p *progressTracker) report(interval time.Duration, callback func(event ProgressEvent)) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				callback(p.event())
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+
+		event := p.event()
+		event.Done = true
+		callback(event)
+	}
+}
@@ -3,7 +3,9 @@ package nuclei
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"io"
+	"time"
 
 	"github.com/projectdiscovery/httpx/common/httpx"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/disk"
@@ -56,6 +58,8 @@ type NucleiEngine struct {
 	disableTemplatesAutoUpgrade bool
 	enableStats                 bool
 	onUpdateAvailableCallback   func(newVersion string)
+	progressCallback            func(event ProgressEvent)
+	progressInterval            time.Duration
 
 	// ready-status fields
 	templatesLoaded bool
@@ -64,13 +68,15 @@ type NucleiEngine struct {
 	interactshClient *interactsh.Client
 	catalog          *disk.DiskCatalog
 	rateLimiter      *ratelimit.Limiter
-	store            *loader.Store
-	httpxClient      *httpx.HTTPX
-	inputProvider    *inputs.SimpleInputProvider
-	engine           *core.Engine
-	mode             engineMode
-	browserInstance  *engine.Browser
-	httpClient       *retryablehttp.Client
+	// sharedRateLimiter is set when the rate limiter is owned by the caller
+	sharedRateLimiter bool
+	store             *loader.Store
+	httpxClient       *httpx.HTTPX
+	inputProvider     *inputs.SimpleInputProvider
+	engine            *core.Engine
+	mode              engineMode
+	browserInstance   *engine.Browser
+	httpClient        *retryablehttp.Client
 
 	// unexported meta options
 	opts           *types.Options
@@ -78,6 +84,7 @@ type NucleiEngine struct {
 	hostErrCache   *hosterrorscache.Cache
 	customWriter   output.Writer
 	customProgress progress.Progress
+	tracker        *progressTracker
 	rc             reporting.Client
 	executerOpts   protocols.ExecutorOptions
 }
@@ -168,11 +175,20 @@ func (e *NucleiEngine) Close() {
 	e.rc.Close()
 	e.customWriter.Close()
 	e.hostErrCache.Close()
-	e.executerOpts.RateLimiter.Stop()
+	if !e.sharedRateLimiter {
+		e.executerOpts.RateLimiter.Stop()
+	}
 }
 
 // ExecuteWithCallback executes templates on targets and calls callback on each result(only if results are found)
 func (e *NucleiEngine) ExecuteWithCallback(callback ...func(event *output.ResultEvent)) error {
+	return e.ExecuteCallbackWithCtx(context.Background(), callback...)
+}
+
+// ExecuteCallbackWithCtx executes templates on targets and calls callback on each result(only if results are found).
+// Once ctx is cancelled no new requests are started, in-flight protocol requests are aborted
+// and ctx.Err() is returned after all running executions have returned
+func (e *NucleiEngine) ExecuteCallbackWithCtx(ctx context.Context, callback ...func(event *output.ResultEvent)) error {
 	if !e.templatesLoaded {
 		_ = e.LoadAllTemplates()
 	}
@@ -191,9 +207,14 @@ func (e *NucleiEngine) ExecuteWithCallback(callback ...func(event *output.Result
 	}
 	e.resultCallbacks = append(e.resultCallbacks, filtered...)
 
-	_ = e.engine.ExecuteScanWithOpts(e.store.Templates(), e.inputProvider, false)
-	defer e.engine.WorkPool().Wait()
-	return nil
+	if e.tracker != nil {
+		stopProgress := e.tracker.report(e.progressInterval, e.progressCallback)
+		defer stopProgress()
+	}
+
+	_ = e.engine.ExecuteScanWithCtx(ctx, e.store.Templates(), e.inputProvider, false)
+	e.engine.WorkPool().Wait()
+	return ctx.Err()
 }
 
 // NewNucleiEngine creates a new nuclei engine instance
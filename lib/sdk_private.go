@@ -3,6 +3,7 @@ package nuclei
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -27,11 +28,32 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yaml"
 	"github.com/projectdiscovery/ratelimit"
+	errorutil "github.com/projectdiscovery/utils/errors"
 )
 
+// reportingOptions returns the reporting options of the reporting config file if any
+func (e *NucleiEngine) reportingOptions() (*reporting.Options, error) {
+	reportingOptions := &reporting.Options{}
+	if e.opts.ReportingConfig == "" {
+		return reportingOptions, nil
+	}
+	file, err := os.Open(e.opts.ReportingConfig)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not open reporting config file")
+	}
+	defer file.Close()
+
+	if err := yaml.DecodeAndValidate(file, reportingOptions); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse reporting config file")
+	}
+	return reportingOptions, nil
+}
+
 // applyRequiredDefaults to options
 func (e *NucleiEngine) applyRequiredDefaults() {
 	if e.customWriter == nil {
@@ -108,6 +130,9 @@ func (e *NucleiEngine) init() error {
 
 	_ = protocolstate.Init(e.opts)
 	_ = protocolinit.Init(e.opts)
+	if err := templates.LoadOverrides(e.opts.TemplateOverrides); err != nil {
+		return err
+	}
 	e.applyRequiredDefaults()
 	var err error
 
@@ -120,12 +145,20 @@ func (e *NucleiEngine) init() error {
 		e.customProgress = progressInstance
 		e.interactshOpts.Progress = progressInstance
 	}
+	if e.progressCallback != nil {
+		e.tracker = &progressTracker{Progress: e.customProgress}
+		e.customProgress = e.tracker
+		e.interactshOpts.Progress = e.tracker
+	}
 
 	if err := reporting.CreateConfigIfNotExists(); err != nil {
 		return err
 	}
-	// we don't support reporting config in sdk mode
-	if e.rc, err = reporting.New(&reporting.Options{}, ""); err != nil {
+	reportingOptions, err := e.reportingOptions()
+	if err != nil {
+		return err
+	}
+	if e.rc, err = reporting.New(reportingOptions, e.opts.ReportingDB); err != nil {
 		return err
 	}
 	e.interactshOpts.IssuesClient = e.rc
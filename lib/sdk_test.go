@@ -1,7 +1,9 @@
 package nuclei_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	nuclei "github.com/projectdiscovery/nuclei/v3/lib"
 	"github.com/stretchr/testify/require"
@@ -58,3 +60,24 @@ func TestThreadSafeNuclei(t *testing.T) {
 	// wait for all scans to finish
 	defer ne.Close()
 }
+
+func TestSimpleNucleiWithCtx(t *testing.T) {
+	var events []nuclei.ProgressEvent
+	ne, err := nuclei.NewNucleiEngine(
+		nuclei.WithTemplateFilters(nuclei.TemplateFilters{ProtocolTypes: "dns"}),
+		nuclei.WithProgressCallback(time.Second, func(event nuclei.ProgressEvent) {
+			events = append(events, event)
+		}),
+	)
+	require.Nil(t, err)
+	defer ne.Close()
+	ne.LoadTargets([]string{"scanme.sh"}, false)
+
+	// a cancelled context stops the scan before any request is sent
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ne.ExecuteCallbackWithCtx(ctx, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotEmpty(t, events, "no progress events received")
+	require.True(t, events[len(events)-1].Done, "last progress event is not done")
+}
@@ -16,6 +16,9 @@ const (
 	NewTemplateAdditionsFileName    = ".new-additions"
 	CLIConfigFileName               = "config.yaml"
 	ReportingConfigFilename         = "reporting-config.yaml"
+	ProfilesDirName                 = "profiles"
+	CVEDatabaseDirName              = "cvedb"
+	TemplateOverridesFileName       = "overrides.yaml"
 	// Version is the current version of nuclei
 	Version = `v3.0.3`
 	// Directory Names of custom templates
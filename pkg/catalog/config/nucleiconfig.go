@@ -125,6 +125,21 @@ func (c *Config) GetKeysDir() string {
 	return filepath.Join(c.configDir, "keys")
 }
 
+// GetProfilesDir returns the nuclei scan profiles directory
+func (c *Config) GetProfilesDir() string {
+	return filepath.Join(c.configDir, ProfilesDirName)
+}
+
+// GetCVEDatabaseDir returns the offline cve database directory
+func (c *Config) GetCVEDatabaseDir() string {
+	return filepath.Join(c.configDir, CVEDatabaseDirName)
+}
+
+// GetTemplateOverridesFilePath returns the nuclei template overrides file path
+func (c *Config) GetTemplateOverridesFilePath() string {
+	return filepath.Join(c.configDir, TemplateOverridesFileName)
+}
+
 // GetAllCustomTemplateDirs returns all custom template directories
 func (c *Config) GetAllCustomTemplateDirs() []string {
 	return []string{c.CustomS3TemplatesDirectory, c.CustomGitHubTemplatesDirectory, c.CustomGitLabTemplatesDirectory, c.CustomAzureTemplatesDirectory}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// profileExtensions are the extensions of scan profile files
+var profileExtensions = []string{".yaml", ".yml"}
+
+// GetProfiles returns the names of scan profiles available in the profiles directory
+func (c *Config) GetProfiles() []string {
+	entries, err := os.ReadDir(c.GetProfilesDir())
+	if err != nil {
+		return nil
+	}
+	var profiles []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		for _, profileExt := range profileExtensions {
+			if ext == profileExt {
+				profiles = append(profiles, strings.TrimSuffix(entry.Name(), ext))
+				break
+			}
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// ResolveProfile returns the path of a scan profile given either its name
+// in the profiles directory (ex: internal-prod) or the path to a profile file
+func (c *Config) ResolveProfile(profile string) (string, error) {
+	if fileutil.FileExists(profile) {
+		return profile, nil
+	}
+	// names are resolved only from the profiles directory
+	if !strings.ContainsAny(profile, `/\`) {
+		for _, ext := range profileExtensions {
+			path := filepath.Join(c.GetProfilesDir(), strings.TrimSuffix(profile, ext)+ext)
+			if fileutil.FileExists(path) {
+				return path, nil
+			}
+		}
+	}
+	available := c.GetProfiles()
+	if len(available) == 0 {
+		return "", errorutil.NewWithTag("config", "profile %s not found: no profiles in %s", profile, c.GetProfilesDir())
+	}
+	return "", errorutil.NewWithTag("config", "profile %s not found: available profiles are %s", profile, strings.Join(available, ", "))
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveProfile(t *testing.T) {
+	c := &Config{configDir: t.TempDir()}
+	require.Nil(t, os.MkdirAll(c.GetProfilesDir(), 0755))
+	for _, name := range []string{"internal-prod.yaml", "external.yml", "notes.txt"} {
+		require.Nil(t, os.WriteFile(filepath.Join(c.GetProfilesDir(), name), []byte("rate-limit: 50\n"), 0644))
+	}
+	require.Equal(t, []string{"external", "internal-prod"}, c.GetProfiles(), "could not list profiles")
+
+	path, err := c.ResolveProfile("internal-prod")
+	require.Nil(t, err, "could not resolve profile by name")
+	require.Equal(t, filepath.Join(c.GetProfilesDir(), "internal-prod.yaml"), path)
+
+	path, err = c.ResolveProfile("external.yml")
+	require.Nil(t, err, "could not resolve profile by name with extension")
+	require.Equal(t, filepath.Join(c.GetProfilesDir(), "external.yml"), path)
+
+	custom := filepath.Join(t.TempDir(), "custom.yaml")
+	require.Nil(t, os.WriteFile(custom, []byte("severity: critical\n"), 0644))
+	path, err = c.ResolveProfile(custom)
+	require.Nil(t, err, "could not resolve profile by path")
+	require.Equal(t, custom, path)
+
+	_, err = c.ResolveProfile("missing")
+	require.ErrorContains(t, err, "available profiles are external, internal-prod")
+}
@@ -0,0 +1,185 @@
+// Package compliance maps templates to OWASP Top 10 categories, CWE trees and
+// MITRE ATT&CK techniques for compliance reporting of results.
+package compliance
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed mapping.yaml
+var bundledMapping []byte
+
+// maxDepth limits the walk of the cwe tree protecting against cycles
+const maxDepth = 16
+
+// Mapping is the compliance classification of a template
+type Mapping struct {
+	// OWASP contains the OWASP Top 10 categories of the template
+	OWASP []string `json:"owasp,omitempty"`
+	// CWE contains the CWEs of the template along with their ancestors
+	CWE []string `json:"cwe,omitempty"`
+	// Attack contains the MITRE ATT&CK technique ids of the template
+	Attack []string `json:"attack,omitempty"`
+}
+
+// IsEmpty returns true if the mapping contains no classification
+func (m *Mapping) IsEmpty() bool {
+	return m == nil || len(m.OWASP) == 0 && len(m.CWE) == 0 && len(m.Attack) == 0
+}
+
+// entry is a cwe or tag entry of the mapping file
+type entry struct {
+	Parent string   `yaml:"parent"`
+	CWE    []string `yaml:"cwe"`
+	OWASP  []string `yaml:"owasp"`
+	Attack []string `yaml:"attack"`
+}
+
+// mappingFile is the layout of the bundled mapping file
+type mappingFile struct {
+	CWE  map[string]*entry `yaml:"cwe"`
+	Tags map[string]*entry `yaml:"tags"`
+}
+
+var mapping = mustParseMapping(bundledMapping)
+
+func mustParseMapping(data []byte) *mappingFile {
+	parsed := &mappingFile{}
+	if err := yaml.UnmarshalStrict(data, parsed); err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// Map returns the compliance mapping of a template from its classification
+// and tags, nil is returned if the template could not be mapped
+func Map(info *model.Info) *Mapping {
+	result := newBuilder()
+	var cwes []string
+	if info.Classification != nil {
+		cwes = append(cwes, info.Classification.CWEID.ToSlice()...)
+		result.add(result.owasp, info.Classification.OWASP.ToSlice()...)
+		result.add(result.attack, info.Classification.AttackID.ToSlice()...)
+		if len(info.Classification.CVEID.ToSlice()) > 0 {
+			cwes = append(cwes, result.addTag("cve")...)
+		}
+	}
+	for _, tag := range info.Tags.ToSlice() {
+		cwes = append(cwes, result.addTag(tag)...)
+	}
+	for _, cwe := range cwes {
+		result.addCWE(cwe)
+	}
+	return result.build()
+}
+
+// builder collects deduplicated values of a mapping
+type builder struct {
+	owasp, cwe, attack map[string]struct{}
+}
+
+func newBuilder() *builder {
+	return &builder{owasp: map[string]struct{}{}, cwe: map[string]struct{}{}, attack: map[string]struct{}{}}
+}
+
+func (b *builder) add(values map[string]struct{}, items ...string) {
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			values[item] = struct{}{}
+		}
+	}
+}
+
+// addTag adds the categories of a tag and returns its cwes
+func (b *builder) addTag(tag string) []string {
+	value, ok := mapping.Tags[strings.ToLower(strings.TrimSpace(tag))]
+	if !ok || value == nil {
+		return nil
+	}
+	b.add(b.owasp, value.OWASP...)
+	b.add(b.attack, value.Attack...)
+	return value.CWE
+}
+
+// addCWE adds a cwe with its ancestors, categories and techniques are
+// taken from the nearest entry of the tree defining them
+func (b *builder) addCWE(cwe string) {
+	id := normalizeCWE(cwe)
+	if id == "" {
+		return
+	}
+	var owaspFound, attackFound bool
+	for depth := 0; id != "" && depth < maxDepth; depth++ {
+		b.add(b.cwe, id)
+		value, ok := mapping.CWE[id]
+		if !ok || value == nil {
+			return
+		}
+		if !owaspFound && len(value.OWASP) > 0 {
+			b.add(b.owasp, value.OWASP...)
+			owaspFound = true
+		}
+		if !attackFound && len(value.Attack) > 0 {
+			b.add(b.attack, value.Attack...)
+			attackFound = true
+		}
+		id = value.Parent
+	}
+}
+
+func (b *builder) build() *Mapping {
+	result := &Mapping{OWASP: sorted(b.owasp), CWE: sortedCWEs(b.cwe), Attack: sorted(b.attack)}
+	if result.IsEmpty() {
+		return nil
+	}
+	return result
+}
+
+// normalizeCWE returns a cwe id in the CWE-<number> form
+func normalizeCWE(cwe string) string {
+	cwe = strings.ToUpper(strings.TrimSpace(cwe))
+	if cwe == "" {
+		return ""
+	}
+	if !strings.HasPrefix(cwe, "CWE-") {
+		cwe = "CWE-" + strings.TrimPrefix(cwe, "CWE")
+	}
+	return cwe
+}
+
+func sorted(values map[string]struct{}) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	items := make([]string, 0, len(values))
+	for value := range values {
+		items = append(items, value)
+	}
+	sort.Strings(items)
+	return items
+}
+
+// sortedCWEs sorts cwes by their number
+func sortedCWEs(values map[string]struct{}) []string {
+	items := sorted(values)
+	sort.SliceStable(items, func(i, j int) bool {
+		return cweNumber(items[i]) < cweNumber(items[j])
+	})
+	return items
+}
+
+func cweNumber(cwe string) int {
+	number := 0
+	for _, r := range strings.TrimPrefix(cwe, "CWE-") {
+		if r < '0' || r > '9' {
+			return number
+		}
+		number = number*10 + int(r-'0')
+	}
+	return number
+}
@@ -0,0 +1,41 @@
+package compliance
+
+import (
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap(t *testing.T) {
+	t.Run("cwe", func(t *testing.T) {
+		mapping := Map(&model.Info{Classification: &model.Classification{CWEID: stringslice.New("cwe-89")}})
+		require.Equal(t, []string{"CWE-74", "CWE-89", "CWE-707", "CWE-943"}, mapping.CWE)
+		require.Equal(t, []string{"A03:2021-Injection"}, mapping.OWASP)
+		require.Equal(t, []string{"T1190"}, mapping.Attack)
+	})
+	t.Run("nearest-ancestor", func(t *testing.T) {
+		mapping := Map(&model.Info{Classification: &model.Classification{CWEID: stringslice.New("CWE-918")}})
+		require.Equal(t, []string{"A10:2021-Server-Side Request Forgery"}, mapping.OWASP, "could not stop at nearest category")
+		require.Contains(t, mapping.CWE, "CWE-664")
+	})
+	t.Run("tags", func(t *testing.T) {
+		mapping := Map(&model.Info{Tags: stringslice.New([]string{"xss", "unknown"})})
+		require.Equal(t, []string{"CWE-74", "CWE-79", "CWE-707"}, mapping.CWE)
+		require.Equal(t, []string{"T1189"}, mapping.Attack)
+	})
+	t.Run("metadata", func(t *testing.T) {
+		mapping := Map(&model.Info{Classification: &model.Classification{
+			CVEID:    stringslice.New("CVE-2021-44228"),
+			OWASP:    stringslice.New("A08:2021-Software and Data Integrity Failures"),
+			AttackID: stringslice.New("T1203"),
+		}})
+		require.Equal(t, []string{"A06:2021-Vulnerable and Outdated Components", "A08:2021-Software and Data Integrity Failures"}, mapping.OWASP)
+		require.Equal(t, []string{"T1203"}, mapping.Attack)
+		require.Empty(t, mapping.CWE)
+	})
+	t.Run("unmapped", func(t *testing.T) {
+		require.Nil(t, Map(&model.Info{Tags: stringslice.New("tech")}))
+	})
+}
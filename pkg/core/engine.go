@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
@@ -20,6 +22,7 @@ type Engine struct {
 	options      *types.Options
 	executerOpts protocols.ExecutorOptions
 	Callback     func(*output.ResultEvent) // Executed on results
+	ctx          context.Context           // Cancels the running scan
 }
 
 // InputProvider is an input providing interface for the nuclei execution
@@ -67,6 +70,24 @@ func (e *Engine) ExecuterOptions() protocols.ExecutorOptions {
 	return e.executerOpts
 }
 
+// scanContext returns the context of the running scan
+func (e *Engine) scanContext() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// newContextArgs returns contextargs for an input bound to the scan context
+func (e *Engine) newContextArgs(input *contextargs.MetaInput) *contextargs.Context {
+	ctxArgs := contextargs.New()
+	if input != nil {
+		ctxArgs.MetaInput = input
+	}
+	ctxArgs.SetContext(e.scanContext())
+	return ctxArgs
+}
+
 // WorkPool returns the worker pool for the engine
 func (e *Engine) WorkPool() *WorkPool {
 	return e.workPool
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 
@@ -32,6 +33,13 @@ func (e *Engine) ExecuteWithResults(templatesList []*templates.Template, target
 
 // ExecuteScanWithOpts executes scan with given scanStrategy
 func (e *Engine) ExecuteScanWithOpts(templatesList []*templates.Template, target InputProvider, noCluster bool) *atomic.Bool {
+	return e.ExecuteScanWithCtx(context.Background(), templatesList, target, noCluster)
+}
+
+// ExecuteScanWithCtx executes scan with given scanStrategy, no new work is started
+// once ctx is cancelled and in-flight protocol requests are aborted
+func (e *Engine) ExecuteScanWithCtx(ctx context.Context, templatesList []*templates.Template, target InputProvider, noCluster bool) *atomic.Bool {
+	e.ctx = ctx
 	results := &atomic.Bool{}
 	selfcontainedWg := &sync.WaitGroup{}
 
@@ -108,6 +116,9 @@ func (e *Engine) executeTemplateSpray(templatesList []*templates.Template, targe
 	wp := e.GetWorkPool()
 
 	for _, template := range templatesList {
+		if e.scanContext().Err() != nil {
+			break
+		}
 		templateType := template.Type()
 
 		var wg *sizedwaitgroup.SizedWaitGroup
@@ -136,6 +147,9 @@ func (e *Engine) executeHostSpray(templatesList []*templates.Template, target In
 	wp := sizedwaitgroup.New(e.options.BulkSize + e.options.HeadlessBulkSize)
 
 	target.Scan(func(value *contextargs.MetaInput) bool {
+		if e.scanContext().Err() != nil {
+			return false
+		}
 		wp.Add()
 		go func(targetval *contextargs.MetaInput) {
 			defer wp.Done()
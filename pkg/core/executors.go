@@ -18,20 +18,23 @@ import (
 // executeAllSelfContained executes all self contained templates that do not use `target`
 func (e *Engine) executeAllSelfContained(alltemplates []*templates.Template, results *atomic.Bool, sg *sync.WaitGroup) {
 	for _, v := range alltemplates {
+		if e.scanContext().Err() != nil {
+			return
+		}
 		sg.Add(1)
 		go func(template *templates.Template) {
 			defer sg.Done()
 			var err error
 			var match bool
 			if e.Callback != nil {
-				err = template.Executer.ExecuteWithResults(contextargs.New(), func(event *output.InternalWrappedEvent) {
+				err = template.Executer.ExecuteWithResults(e.newContextArgs(nil), func(event *output.InternalWrappedEvent) {
 					for _, result := range event.Results {
 						e.Callback(result)
 					}
 				})
 				match = true
 			} else {
-				match, err = template.Executer.Execute(contextargs.New())
+				match, err = template.Executer.Execute(e.newContextArgs(nil))
 			}
 			if err != nil {
 				gologger.Warning().Msgf("[%s] Could not execute step: %s\n", e.executerOpts.Colorizer.BrightBlue(template.ID), err)
@@ -74,6 +77,9 @@ func (e *Engine) executeTemplateWithTargets(template *templates.Template, target
 	}
 
 	target.Scan(func(scannedValue *contextargs.MetaInput) bool {
+		if e.scanContext().Err() != nil {
+			return false
+		}
 		// Best effort to track the host progression
 		// skips indexes lower than the minimum in-flight at interruption time
 		var skip bool
@@ -115,8 +121,7 @@ func (e *Engine) executeTemplateWithTargets(template *templates.Template, target
 			case types.WorkflowProtocol:
 				match = e.executeWorkflow(value, template.CompiledWorkflow)
 			default:
-				ctxArgs := contextargs.New()
-				ctxArgs.MetaInput = value
+				ctxArgs := e.newContextArgs(value)
 				if e.Callback != nil {
 					err = template.Executer.ExecuteWithResults(ctxArgs, func(event *output.InternalWrappedEvent) {
 						for _, result := range event.Results {
@@ -154,6 +159,9 @@ func (e *Engine) executeTemplatesOnTarget(alltemplates []*templates.Template, ta
 	wp := e.GetWorkPool()
 
 	for _, tpl := range alltemplates {
+		if e.scanContext().Err() != nil {
+			break
+		}
 		var sg *sizedwaitgroup.SizedWaitGroup
 		if tpl.Type() == types.HeadlessProtocol {
 			sg = wp.Headless
@@ -170,8 +178,7 @@ func (e *Engine) executeTemplatesOnTarget(alltemplates []*templates.Template, ta
 			case types.WorkflowProtocol:
 				match = e.executeWorkflow(value, template.CompiledWorkflow)
 			default:
-				ctxArgs := contextargs.New()
-				ctxArgs.MetaInput = value
+				ctxArgs := e.newContextArgs(value)
 				if e.Callback != nil {
 					err = template.Executer.ExecuteWithResults(ctxArgs, func(event *output.InternalWrappedEvent) {
 						for _, result := range event.Results {
@@ -219,8 +226,7 @@ func (e *ChildExecuter) Execute(template *templates.Template, value *contextargs
 	go func(tpl *templates.Template) {
 		defer wg.Done()
 
-		ctxArgs := contextargs.New()
-		ctxArgs.MetaInput = value
+		ctxArgs := e.e.newContextArgs(value)
 		match, err := template.Executer.Execute(ctxArgs)
 		if err != nil {
 			gologger.Warning().Msgf("[%s] Could not execute step: %s\n", e.e.executerOpts.Colorizer.BrightBlue(template.ID), err)
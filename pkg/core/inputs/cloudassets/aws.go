@@ -0,0 +1,220 @@
+package cloudassets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/projectdiscovery/gologger"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// awsEndpoint returns the api endpoint of an aws service in a region
+var awsEndpoint = func(service, region string) string {
+	if service == "cloudfront" {
+		return "https://cloudfront.amazonaws.com"
+	}
+	return fmt.Sprintf("https://%s.%s.amazonaws.com", service, region)
+}
+
+// awsClient performs signed requests against aws apis
+type awsClient struct {
+	client *http.Client
+	creds  aws.Credentials
+	signer *v4.Signer
+}
+
+// enumerateAWS enumerates ec2 public ips, elastic ips, internet facing load
+// balancers, cloudfront distributions and s3 buckets
+func enumerateAWS(ctx context.Context, client *http.Client, opts *Options, results chan<- string) error {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not load aws config")
+	}
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not retrieve aws credentials")
+	}
+	c := &awsClient{client: client, creds: creds, signer: v4.NewSigner()}
+
+	regions := opts.Regions
+	if len(regions) == 0 {
+		regions = []string{cfg.Region}
+		if cfg.Region == "" {
+			regions = []string{"us-east-1"}
+		}
+	}
+	for _, region := range regions {
+		if err := c.ec2Instances(ctx, region, results); err != nil {
+			gologger.Warning().Msgf("aws: could not list ec2 instances in %s: %s", region, err)
+		}
+		if err := c.elasticIPs(ctx, region, results); err != nil {
+			gologger.Warning().Msgf("aws: could not list elastic ips in %s: %s", region, err)
+		}
+		if err := c.loadBalancers(ctx, region, results); err != nil {
+			gologger.Warning().Msgf("aws: could not list load balancers in %s: %s", region, err)
+		}
+	}
+	if err := c.cloudfrontDistributions(ctx, results); err != nil {
+		gologger.Warning().Msgf("aws: could not list cloudfront distributions: %s", err)
+	}
+
+	buckets, err := s3.NewFromConfig(cfg).ListBuckets(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		gologger.Warning().Msgf("aws: could not list s3 buckets: %s", err)
+		return nil
+	}
+	// the buckets are listed regardless of their access policy, the scan tells
+	// the publicly accessible ones
+	for _, bucket := range buckets.Buckets {
+		if !send(ctx, results, fmt.Sprintf("https://%s.s3.amazonaws.com", aws.ToString(bucket.Name))) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// get performs a signed get request and decodes the xml response
+func (c *awsClient) get(ctx context.Context, service, region, path string, query url.Values, v interface{}) error {
+	endpoint := awsEndpoint(service, region) + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	signingRegion := region
+	if service == "cloudfront" {
+		signingRegion = "us-east-1"
+	}
+	if err := c.signer.SignHTTP(ctx, c.creds, req, emptyPayloadHash, service, signingRegion, time.Now()); err != nil {
+		return err
+	}
+	return doRequest(c.client, req, v)
+}
+
+type ec2InstancesResponse struct {
+	Reservations []struct {
+		Instances []struct {
+			IPAddress string `xml:"ipAddress"`
+			DNSName   string `xml:"dnsName"`
+		} `xml:"instancesSet>item"`
+	} `xml:"reservationSet>item"`
+	NextToken string `xml:"nextToken"`
+}
+
+func (c *awsClient) ec2Instances(ctx context.Context, region string, results chan<- string) error {
+	query := url.Values{"Action": {"DescribeInstances"}, "Version": {"2016-11-15"}}
+	for {
+		var resp ec2InstancesResponse
+		if err := c.get(ctx, "ec2", region, "/", query, &resp); err != nil {
+			return err
+		}
+		for _, reservation := range resp.Reservations {
+			for _, instance := range reservation.Instances {
+				// prefer the public dns name, fallback to the public ip
+				value := instance.DNSName
+				if value == "" {
+					value = instance.IPAddress
+				}
+				if value != "" && !send(ctx, results, value) {
+					return nil
+				}
+			}
+		}
+		if resp.NextToken == "" {
+			return nil
+		}
+		query.Set("NextToken", resp.NextToken)
+	}
+}
+
+type ec2AddressesResponse struct {
+	Addresses []struct {
+		PublicIP string `xml:"publicIp"`
+	} `xml:"addressesSet>item"`
+}
+
+func (c *awsClient) elasticIPs(ctx context.Context, region string, results chan<- string) error {
+	var resp ec2AddressesResponse
+	query := url.Values{"Action": {"DescribeAddresses"}, "Version": {"2016-11-15"}}
+	if err := c.get(ctx, "ec2", region, "/", query, &resp); err != nil {
+		return err
+	}
+	for _, address := range resp.Addresses {
+		if !send(ctx, results, address.PublicIP) {
+			return nil
+		}
+	}
+	return nil
+}
+
+type loadBalancersResponse struct {
+	LoadBalancers []struct {
+		DNSName string `xml:"DNSName"`
+		Scheme  string `xml:"Scheme"`
+	} `xml:"DescribeLoadBalancersResult>LoadBalancers>member"`
+	NextMarker string `xml:"DescribeLoadBalancersResult>NextMarker"`
+}
+
+func (c *awsClient) loadBalancers(ctx context.Context, region string, results chan<- string) error {
+	query := url.Values{"Action": {"DescribeLoadBalancers"}, "Version": {"2015-12-01"}}
+	for {
+		var resp loadBalancersResponse
+		if err := c.get(ctx, "elasticloadbalancing", region, "/", query, &resp); err != nil {
+			return err
+		}
+		for _, lb := range resp.LoadBalancers {
+			if !strings.EqualFold(lb.Scheme, "internet-facing") {
+				continue
+			}
+			if !send(ctx, results, lb.DNSName) {
+				return nil
+			}
+		}
+		if resp.NextMarker == "" {
+			return nil
+		}
+		query.Set("Marker", resp.NextMarker)
+	}
+}
+
+type distributionsResponse struct {
+	Items []struct {
+		DomainName string   `xml:"DomainName"`
+		Aliases    []string `xml:"Aliases>Items>CNAME"`
+	} `xml:"Items>DistributionSummary"`
+	IsTruncated bool   `xml:"IsTruncated"`
+	NextMarker  string `xml:"NextMarker"`
+}
+
+func (c *awsClient) cloudfrontDistributions(ctx context.Context, results chan<- string) error {
+	query := url.Values{}
+	for {
+		var resp distributionsResponse
+		if err := c.get(ctx, "cloudfront", "", "/2020-05-31/distribution", query, &resp); err != nil {
+			return err
+		}
+		for _, distribution := range resp.Items {
+			for _, domain := range append([]string{distribution.DomainName}, distribution.Aliases...) {
+				if !send(ctx, results, domain) {
+					return nil
+				}
+			}
+		}
+		if !resp.IsTruncated || resp.NextMarker == "" {
+			return nil
+		}
+		query.Set("Marker", resp.NextMarker)
+	}
+}
@@ -0,0 +1,128 @@
+package cloudassets
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/projectdiscovery/gologger"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// azureManagementURL is the azure resource manager endpoint
+var azureManagementURL = "https://management.azure.com"
+
+// azureClient performs authenticated requests against the azure resource manager
+type azureClient struct {
+	client *http.Client
+	token  string
+}
+
+// enumerateAzure enumerates public ips, app services and storage accounts
+// of the subscription in AZURE_SUBSCRIPTION_ID or all accessible subscriptions,
+// the storage accounts are listed regardless of their access level
+func enumerateAzure(ctx context.Context, client *http.Client, _ *Options, results chan<- string) error {
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not load azure credentials")
+	}
+	token, err := credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureManagementURL + "/.default"}})
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not get azure access token")
+	}
+	c := &azureClient{client: client, token: token.Token}
+
+	subscriptions := []string{}
+	if subscription := os.Getenv("AZURE_SUBSCRIPTION_ID"); subscription != "" {
+		subscriptions = append(subscriptions, subscription)
+	} else {
+		err := c.list(ctx, "/subscriptions?api-version=2020-01-01", func(value azureResource) bool {
+			subscriptions = append(subscriptions, value.SubscriptionID)
+			return true
+		})
+		if err != nil {
+			return errorutil.NewWithErr(err).Msgf("could not list azure subscriptions")
+		}
+	}
+
+	for _, subscription := range subscriptions {
+		prefix := "/subscriptions/" + subscription + "/providers/"
+		err := c.list(ctx, prefix+"Microsoft.Network/publicIPAddresses?api-version=2023-04-01", func(value azureResource) bool {
+			if value.Properties.DNSSettings.FQDN != "" {
+				return send(ctx, results, value.Properties.DNSSettings.FQDN)
+			}
+			return value.Properties.IPAddress == "" || send(ctx, results, value.Properties.IPAddress)
+		})
+		if err != nil {
+			gologger.Warning().Msgf("azure: could not list public ips of %s: %s", subscription, err)
+		}
+		err = c.list(ctx, prefix+"Microsoft.Web/sites?api-version=2022-03-01", func(value azureResource) bool {
+			for _, hostname := range append([]string{value.Properties.DefaultHostName}, value.Properties.HostNames...) {
+				if hostname != "" && !send(ctx, results, hostname) {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			gologger.Warning().Msgf("azure: could not list app services of %s: %s", subscription, err)
+		}
+		err = c.list(ctx, prefix+"Microsoft.Storage/storageAccounts?api-version=2023-01-01", func(value azureResource) bool {
+			blob := strings.TrimSuffix(value.Properties.PrimaryEndpoints.Blob, "/")
+			return blob == "" || send(ctx, results, blob)
+		})
+		if err != nil {
+			gologger.Warning().Msgf("azure: could not list storage accounts of %s: %s", subscription, err)
+		}
+	}
+	return nil
+}
+
+// azureResource contains the fields used from azure resource manager resources
+type azureResource struct {
+	SubscriptionID string `json:"subscriptionId"`
+	Properties     struct {
+		IPAddress   string `json:"ipAddress"`
+		DNSSettings struct {
+			FQDN string `json:"fqdn"`
+		} `json:"dnsSettings"`
+		DefaultHostName  string   `json:"defaultHostName"`
+		HostNames        []string `json:"hostNames"`
+		PrimaryEndpoints struct {
+			Blob string `json:"blob"`
+		} `json:"primaryEndpoints"`
+	} `json:"properties"`
+}
+
+type azureListResponse struct {
+	Value    []azureResource `json:"value"`
+	NextLink string          `json:"nextLink"`
+}
+
+// list iterates all pages of a resource list calling callback for each
+// resource until it returns false
+func (c *azureClient) list(ctx context.Context, path string, callback func(value azureResource) bool) error {
+	next := azureManagementURL + path
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		var resp azureListResponse
+		if err := doRequest(c.client, req, &resp); err != nil {
+			return err
+		}
+		for _, value := range resp.Value {
+			if !callback(value) {
+				return nil
+			}
+		}
+		next = resp.NextLink
+	}
+	return nil
+}
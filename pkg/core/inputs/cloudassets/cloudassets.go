@@ -0,0 +1,142 @@
+// Package cloudassets enumerates the endpoints and storage buckets of cloud accounts to be used as scan targets.
+package cloudassets
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	mapsutil "github.com/projectdiscovery/utils/maps"
+)
+
+// Options contains the configuration for cloud asset discovery
+// credentials are read from the standard environment/config of each provider
+type Options struct {
+	// Providers is the list of cloud providers to enumerate (aws, azure, gcp)
+	Providers []string
+	// Regions is the list of aws regions to enumerate, defaults to the configured region
+	Regions []string
+	// Timeout is the timeout of each api request
+	Timeout time.Duration
+}
+
+// provider enumerates the endpoints of a cloud account and sends them to results
+type provider func(ctx context.Context, client *http.Client, opts *Options, results chan<- string) error
+
+var providers = map[string]provider{
+	"aws":   enumerateAWS,
+	"azure": enumerateAzure,
+	"gcp":   enumerateGCP,
+}
+
+// SupportedProviders returns the csv list of supported cloud providers
+func SupportedProviders() string {
+	names := mapsutil.GetKeys(providers)
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// IsSupported returns true if the cloud provider is supported
+func IsSupported(name string) bool {
+	_, ok := providers[strings.ToLower(name)]
+	return ok
+}
+
+// GetTargets returns the endpoints discovered from the given cloud providers.
+// errors of a provider are logged and do not stop the enumeration of the others
+func GetTargets(ctx context.Context, opts *Options) (chan string, error) {
+	var enumerators []provider
+	for _, name := range opts.Providers {
+		enumerate, ok := providers[strings.ToLower(name)]
+		if !ok {
+			return nil, errorutil.New("unsupported cloud provider %s (supported: %s)", name, SupportedProviders())
+		}
+		enumerators = append(enumerators, enumerate)
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	// dedupe endpoints reported by multiple services (ex: instance ip and elastic ip)
+	seen := make(map[string]struct{})
+	outputChan := make(chan string)
+	resultsChan := make(chan string)
+
+	wg := &sync.WaitGroup{}
+	for i, enumerate := range enumerators {
+		wg.Add(1)
+		go func(name string, enumerate provider) {
+			defer wg.Done()
+			if err := enumerate(ctx, client, opts, resultsChan); err != nil {
+				gologger.Error().Msgf("Could not enumerate %s cloud assets: %s", name, err)
+			}
+		}(opts.Providers[i], enumerate)
+	}
+	go func() {
+		wg.Wait()
+		close(resultsChan)
+	}()
+	go func() {
+		defer close(outputChan)
+		for result := range resultsChan {
+			if result == "" {
+				continue
+			}
+			if _, ok := seen[result]; ok {
+				continue
+			}
+			seen[result] = struct{}{}
+			select {
+			case outputChan <- result:
+			case <-ctx.Done():
+				// drain remaining results so enumerators can exit
+				for range resultsChan {
+				}
+				return
+			}
+		}
+	}()
+	return outputChan, nil
+}
+
+// send sends a result unless the context is cancelled
+func send(ctx context.Context, results chan<- string, value string) bool {
+	select {
+	case results <- value:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// doRequest performs a request and decodes the json or xml response into v
+func doRequest(client *http.Client, req *http.Request, v interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 50*1024*1024))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d for %s: %s", resp.StatusCode, req.URL.Path, strings.TrimSpace(string(body)))
+	}
+	if strings.Contains(resp.Header.Get("Content-Type"), "xml") || strings.HasPrefix(strings.TrimSpace(string(body)), "<") {
+		return xml.Unmarshal(body, v)
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,83 @@
+package cloudassets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTargetsUnsupportedProvider(t *testing.T) {
+	_, err := GetTargets(context.Background(), &Options{Providers: []string{"aws", "digitalocean"}})
+	require.NotNil(t, err, "could not get error for unsupported provider")
+
+	require.True(t, IsSupported("AWS"), "could not match provider case insensitively")
+	require.Equal(t, "aws,azure,gcp", SupportedProviders(), "could not get supported providers")
+}
+
+func TestAWSResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		switch r.URL.Query().Get("Action") {
+		case "DescribeInstances":
+			if r.URL.Query().Get("NextToken") == "" {
+				_, _ = w.Write([]byte(`<DescribeInstancesResponse><reservationSet><item><instancesSet>
+<item><dnsName>ec2-1-2-3-4.compute.amazonaws.com</dnsName><ipAddress>1.2.3.4</ipAddress></item>
+<item><dnsName></dnsName><ipAddress></ipAddress></item>
+</instancesSet></item></reservationSet><nextToken>page2</nextToken></DescribeInstancesResponse>`))
+				return
+			}
+			_, _ = w.Write([]byte(`<DescribeInstancesResponse><reservationSet><item><instancesSet>
+<item><ipAddress>5.6.7.8</ipAddress></item>
+</instancesSet></item></reservationSet></DescribeInstancesResponse>`))
+		case "DescribeLoadBalancers":
+			_, _ = w.Write([]byte(`<DescribeLoadBalancersResponse><DescribeLoadBalancersResult><LoadBalancers>
+<member><DNSName>public.elb.amazonaws.com</DNSName><Scheme>internet-facing</Scheme></member>
+<member><DNSName>internal.elb.amazonaws.com</DNSName><Scheme>internal</Scheme></member>
+</LoadBalancers></DescribeLoadBalancersResult></DescribeLoadBalancersResponse>`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	original := awsEndpoint
+	awsEndpoint = func(service, region string) string { return ts.URL }
+	defer func() { awsEndpoint = original }()
+
+	c := &awsClient{
+		client: ts.Client(),
+		creds:  aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"},
+		signer: v4.NewSigner(),
+	}
+
+	results := make(chan string, 10)
+	require.Nil(t, c.ec2Instances(context.Background(), "us-east-1", results), "could not list instances")
+	require.Nil(t, c.loadBalancers(context.Background(), "us-east-1", results), "could not list load balancers")
+	close(results)
+
+	var got []string
+	for result := range results {
+		got = append(got, result)
+	}
+	require.ElementsMatch(t, []string{"ec2-1-2-3-4.compute.amazonaws.com", "5.6.7.8", "public.elb.amazonaws.com"}, got, "could not get aws targets")
+}
+
+func TestGCPListResponse(t *testing.T) {
+	var aggregated gcpListResponse
+	err := json.Unmarshal([]byte(`{"items":{"zones/us-central1-a":{"instances":[{"networkInterfaces":[{"accessConfigs":[{"natIP":"1.2.3.4"}]}]}]}},"nextPageToken":"next"}`), &aggregated)
+	require.Nil(t, err, "could not unmarshal aggregated list")
+	require.Equal(t, "next", aggregated.NextPageToken)
+	require.Equal(t, "1.2.3.4", aggregated.AggregatedItems["zones/us-central1-a"].Instances[0].NetworkInterfaces[0].AccessConfigs[0].NatIP)
+
+	var buckets gcpListResponse
+	err = json.Unmarshal([]byte(`{"items":[{"name":"bucket"}]}`), &buckets)
+	require.Nil(t, err, "could not unmarshal bucket list")
+	require.Len(t, buckets.Buckets, 1)
+	require.Equal(t, "bucket", buckets.Buckets[0].Name)
+}
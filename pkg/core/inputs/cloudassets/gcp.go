@@ -0,0 +1,193 @@
+package cloudassets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/projectdiscovery/gologger"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+var (
+	// gcpComputeURL is the google compute engine api endpoint
+	gcpComputeURL = "https://compute.googleapis.com/compute/v1"
+	// gcpStorageURL is the google cloud storage api endpoint
+	gcpStorageURL = "https://storage.googleapis.com/storage/v1"
+)
+
+// gcpServiceAccount contains the fields used from a service account key file
+type gcpServiceAccount struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// gcpClient performs authenticated requests against google cloud apis
+type gcpClient struct {
+	client      *http.Client
+	tokenSource oauth2.TokenSource
+}
+
+// enumerateGCP enumerates compute instance external ips, external addresses and
+// storage buckets of the project using the service account key in GOOGLE_APPLICATION_CREDENTIALS
+func enumerateGCP(ctx context.Context, client *http.Client, _ *Options, results chan<- string) error {
+	keyFile := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if keyFile == "" {
+		return errorutil.New("GOOGLE_APPLICATION_CREDENTIALS is not set")
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not read gcp service account key")
+	}
+	var account gcpServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not parse gcp service account key")
+	}
+	project := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if project == "" {
+		project = account.ProjectID
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	config := &jwt.Config{
+		Email:        account.ClientEmail,
+		PrivateKey:   []byte(account.PrivateKey),
+		PrivateKeyID: account.PrivateKeyID,
+		Scopes:       []string{"https://www.googleapis.com/auth/cloud-platform.read-only"},
+		TokenURL:     account.TokenURI,
+	}
+	c := &gcpClient{
+		client:      client,
+		tokenSource: config.TokenSource(context.WithValue(ctx, oauth2.HTTPClient, client)),
+	}
+
+	projectURL := gcpComputeURL + "/projects/" + url.PathEscape(project)
+	err = c.list(ctx, projectURL+"/aggregated/instances", func(page gcpListResponse) bool {
+		for _, scope := range page.AggregatedItems {
+			for _, instance := range scope.Instances {
+				for _, networkInterface := range instance.NetworkInterfaces {
+					for _, accessConfig := range networkInterface.AccessConfigs {
+						if accessConfig.NatIP != "" && !send(ctx, results, accessConfig.NatIP) {
+							return false
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		gologger.Warning().Msgf("gcp: could not list compute instances of %s: %s", project, err)
+	}
+	err = c.list(ctx, projectURL+"/aggregated/addresses", func(page gcpListResponse) bool {
+		for _, scope := range page.AggregatedItems {
+			for _, address := range scope.Addresses {
+				if address.AddressType == "EXTERNAL" && !send(ctx, results, address.Address) {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		gologger.Warning().Msgf("gcp: could not list addresses of %s: %s", project, err)
+	}
+	// the buckets are listed regardless of their iam policy
+	err = c.list(ctx, gcpStorageURL+"/b?project="+url.QueryEscape(project), func(page gcpListResponse) bool {
+		for _, bucket := range page.Buckets {
+			if !send(ctx, results, "https://storage.googleapis.com/"+bucket.Name) {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		gologger.Warning().Msgf("gcp: could not list storage buckets of %s: %s", project, err)
+	}
+	return nil
+}
+
+// gcpListResponse contains the fields used from google cloud list responses
+type gcpListResponse struct {
+	// AggregatedItems is set for compute aggregated lists
+	AggregatedItems map[string]struct {
+		Instances []struct {
+			NetworkInterfaces []struct {
+				AccessConfigs []struct {
+					NatIP string `json:"natIP"`
+				} `json:"accessConfigs"`
+			} `json:"networkInterfaces"`
+		} `json:"instances"`
+		Addresses []struct {
+			Address     string `json:"address"`
+			AddressType string `json:"addressType"`
+		} `json:"addresses"`
+	}
+	// Buckets is set for storage bucket lists
+	Buckets []struct {
+		Name string `json:"name"`
+	}
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// UnmarshalJSON decodes the items field which is a map for compute
+// aggregated lists and a list for storage buckets
+func (r *gcpListResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Items         json.RawMessage `json:"items"`
+		NextPageToken string          `json:"nextPageToken"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.NextPageToken = raw.NextPageToken
+	if len(raw.Items) == 0 {
+		return nil
+	}
+	if raw.Items[0] == '[' {
+		return json.Unmarshal(raw.Items, &r.Buckets)
+	}
+	return json.Unmarshal(raw.Items, &r.AggregatedItems)
+}
+
+// list iterates all pages of a list api calling callback for each page until it returns false
+func (c *gcpClient) list(ctx context.Context, endpoint string, callback func(page gcpListResponse) bool) error {
+	pageToken := ""
+	for {
+		pageURL, err := url.Parse(endpoint)
+		if err != nil {
+			return err
+		}
+		if pageToken != "" {
+			query := pageURL.Query()
+			query.Set("pageToken", pageToken)
+			pageURL.RawQuery = query.Encode()
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), nil)
+		if err != nil {
+			return err
+		}
+		token, err := c.tokenSource.Token()
+		if err != nil {
+			return errorutil.NewWithErr(err).Msgf("could not get gcp access token")
+		}
+		token.SetAuthHeader(req)
+
+		var page gcpListResponse
+		if err := doRequest(c.client, req, &page); err != nil {
+			return err
+		}
+		if !callback(page) || page.NextPageToken == "" {
+			return nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/projectdiscovery/hmap/store/hybrid"
 	"github.com/projectdiscovery/mapcidr"
 	"github.com/projectdiscovery/mapcidr/asn"
+	"github.com/projectdiscovery/nuclei/v3/pkg/core/inputs/cloudassets"
+	"github.com/projectdiscovery/nuclei/v3/pkg/core/inputs/portscan"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/uncover"
@@ -36,8 +38,10 @@ const DefaultMaxDedupeItemsCount = 10000
 // Input is a hmap/filekv backed nuclei Input provider
 type Input struct {
 	ipOptions         *ipOptions
+	exclusions        *exclusions
 	inputCount        int64
 	dupeCount         int64
+	excludedCount     int64
 	hostMap           *hybrid.HybridMap
 	hostMapStream     *filekv.FileDB
 	hostMapStreamOnce sync.Once
@@ -58,16 +62,21 @@ type Options struct {
 func New(opts *Options) (*Input, error) {
 	options := opts.Options
 
+	excluded, err := newExclusions(options.ExcludeHosts, options.ExcludeCIDRs)
+	if err != nil {
+		return nil, err
+	}
 	hm, err := hybrid.New(hybrid.DefaultDiskOptions)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create temporary input file")
 	}
 
 	input := &Input{
-		hostMap: hm,
+		hostMap:    hm,
+		exclusions: excluded,
 		ipOptions: &ipOptions{
 			ScanAllIPs: options.ScanAllIPs,
-			IPV4:       sliceutil.Contains(options.IPVersion, "4"),
+			IPV4:       len(options.IPVersion) == 0 || sliceutil.Contains(options.IPVersion, "4"), // ipv4 is scanned by default
 			IPV6:       sliceutil.Contains(options.IPVersion, "6"),
 		},
 	}
@@ -91,6 +100,9 @@ func New(opts *Options) (*Input, error) {
 	if input.dupeCount > 0 {
 		gologger.Info().Msgf("Supplied input was automatically deduplicated (%d removed).", input.dupeCount)
 	}
+	if input.excludedCount > 0 {
+		gologger.Info().Msgf("Supplied input was filtered by exclusions (%d removed).", input.excludedCount)
+	}
 	return input, nil
 }
 
@@ -137,6 +149,12 @@ func (i *Input) initializeInputSources(opts *Options) error {
 			input.Close()
 		}
 	}
+	// Handle nmap/masscan output
+	for _, file := range options.PortScanFiles {
+		if err := i.scanInputFromPortScan(file); err != nil {
+			return err
+		}
+	}
 	if options.Uncover && options.UncoverQuery != nil {
 		gologger.Info().Msgf("Running uncover query against: %s", strings.Join(options.UncoverEngine, ","))
 		uncoverOpts := &uncoverlib.Options{
@@ -148,7 +166,25 @@ func (i *Input) initializeInputSources(opts *Options) error {
 			RateLimit:     uint(options.UncoverRateLimit),
 			RateLimitUnit: time.Minute, // default unit is minute
 		}
-		ch, err := uncover.GetTargetsFromUncover(context.TODO(), options.UncoverField, uncoverOpts)
+		engineRateLimits, err := uncover.ParseEngineRateLimits(options.UncoverEngineRateLimit)
+		if err != nil {
+			return err
+		}
+		ch, err := uncover.GetTargetsFromUncover(context.TODO(), options.UncoverField, uncoverOpts, engineRateLimits)
+		if err != nil {
+			return err
+		}
+		for target := range ch {
+			i.SetWithMetadata(target.Value, target.Metadata)
+		}
+	}
+	if len(options.CloudAssets) > 0 {
+		gologger.Info().Msgf("Running cloud asset discovery against: %s", strings.Join(options.CloudAssets, ","))
+		ch, err := cloudassets.GetTargets(context.TODO(), &cloudassets.Options{
+			Providers: options.CloudAssets,
+			Regions:   options.CloudAssetsRegions,
+			Timeout:   time.Duration(options.Timeout) * time.Second,
+		})
 		if err != nil {
 			return err
 		}
@@ -175,8 +211,33 @@ func (i *Input) scanInputFromReader(reader io.Reader) {
 	}
 }
 
+// scanInputFromPortScan stores the open ports of a nmap/masscan output file
+// with the detected services so only matching templates are executed against them
+func (i *Input) scanInputFromPortScan(file string) error {
+	input, err := os.Open(file)
+	if err != nil {
+		return errors.Wrap(err, "could not open port scan file")
+	}
+	defer input.Close()
+
+	err = portscan.Parse(input, func(service portscan.Service) {
+		metaInput := &contextargs.MetaInput{Input: service.Address(), Service: service.Name, PortScanned: true}
+		if service.Host != "" {
+			metaInput.CustomIP = service.IP
+		}
+		i.setItem(metaInput)
+	})
+	return errors.Wrapf(err, "could not parse port scan file %s", file)
+}
+
 // Set normalizes and stores passed input values
 func (i *Input) Set(value string) {
+	i.SetWithMetadata(value, nil)
+}
+
+// SetWithMetadata normalizes and stores passed input values with the
+// metadata of their source exposed as template variables
+func (i *Input) SetWithMetadata(value string, metadata map[string]string) {
 	URL := strings.TrimSpace(value)
 	if URL == "" {
 		return
@@ -190,14 +251,14 @@ func (i *Input) Set(value string) {
 			}
 			return fmt.Sprintf("got empty hostname for %v skipping ip selection", URL)
 		})
-		metaInput := &contextargs.MetaInput{Input: URL}
+		metaInput := &contextargs.MetaInput{Input: URL, Metadata: metadata}
 		i.setItem(metaInput)
 		return
 	}
 
 	// Check if input is ip or hostname
 	if iputil.IsIP(urlx.Hostname()) {
-		metaInput := &contextargs.MetaInput{Input: URL}
+		metaInput := &contextargs.MetaInput{Input: URL, Metadata: metadata}
 		i.setItem(metaInput)
 		return
 	}
@@ -218,7 +279,7 @@ func (i *Input) Set(value string) {
 					if ip == "" {
 						continue
 					}
-					metaInput := &contextargs.MetaInput{Input: value, CustomIP: ip}
+					metaInput := &contextargs.MetaInput{Input: value, CustomIP: ip, Metadata: metadata}
 					i.setItem(metaInput)
 				}
 				return
@@ -249,10 +310,10 @@ func (i *Input) Set(value string) {
 
 	for _, ip := range ips {
 		if ip != "" {
-			metaInput := &contextargs.MetaInput{Input: URL, CustomIP: ip}
+			metaInput := &contextargs.MetaInput{Input: URL, CustomIP: ip, Metadata: metadata}
 			i.setItem(metaInput)
 		} else {
-			metaInput := &contextargs.MetaInput{Input: URL}
+			metaInput := &contextargs.MetaInput{Input: URL, Metadata: metadata}
 			i.setItem(metaInput)
 		}
 	}
@@ -260,6 +321,10 @@ func (i *Input) Set(value string) {
 
 // setItem in the kv store
 func (i *Input) setItem(metaInput *contextargs.MetaInput) {
+	if i.exclusions.isExcluded(metaInput) {
+		i.excludedCount++
+		return
+	}
 	key, err := metaInput.MarshalString()
 	if err != nil {
 		gologger.Warning().Msgf("%s\n", err)
@@ -285,6 +350,20 @@ func (i *Input) setHostMapStream(data string) {
 	}
 }
 
+// Delete removes an item from the input, it is not supported in stream mode
+func (i *Input) Delete(metaInput *contextargs.MetaInput) {
+	key, err := metaInput.MarshalString()
+	if err != nil {
+		gologger.Warning().Msgf("%s\n", err)
+		return
+	}
+	if _, ok := i.hostMap.Get(key); !ok {
+		return
+	}
+	_ = i.hostMap.Del(key)
+	i.inputCount--
+}
+
 // Count returns the input count
 func (i *Input) Count() int64 {
 	return i.inputCount
@@ -317,31 +396,31 @@ func (i *Input) Scan(callback func(value *contextargs.MetaInput) bool) {
 	}
 }
 
-// expandCIDRInputValue expands CIDR and stores expanded IPs
+// expandCIDRInputValue expands CIDR and stores expanded IPs.
+// addresses are streamed so large ranges are not held in memory
 func (i *Input) expandCIDRInputValue(value string) {
-	ips, _ := mapcidr.IPAddressesAsStream(value)
+	// skip ranges that are entirely excluded without expanding them
+	if i.exclusions.containsCIDR(value) {
+		gologger.Debug().Msgf("Skipping excluded cidr %s", value)
+		return
+	}
+	ips, err := mapcidr.IPAddressesAsStream(value)
+	if err != nil {
+		gologger.Warning().Msgf("Could not expand cidr %s: %s\n", value, err)
+		return
+	}
 	for ip := range ips {
-		metaInput := &contextargs.MetaInput{Input: ip}
-		key, err := metaInput.MarshalString()
-		if err != nil {
-			gologger.Warning().Msgf("%s\n", err)
-			return
-		}
-		if _, ok := i.hostMap.Get(key); ok {
-			i.dupeCount++
-			continue
-		}
-		i.inputCount++
-		_ = i.hostMap.Set(key, nil)
-		if i.hostMapStream != nil {
-			i.setHostMapStream(key)
-		}
+		i.setItem(&contextargs.MetaInput{Input: ip})
 	}
 }
 
 // expandASNInputValue expands CIDRs for given ASN and stores expanded IPs
 func (i *Input) expandASNInputValue(value string) {
-	cidrs, _ := asn.GetCIDRsForASNNum(value)
+	cidrs, err := asn.GetCIDRsForASNNum(value)
+	if err != nil {
+		gologger.Warning().Msgf("Could not get cidrs for asn %s: %s\n", value, err)
+		return
+	}
 	for _, cidr := range cidrs {
 		i.expandCIDRInputValue(cidr.String())
 	}
@@ -49,6 +49,58 @@ func Test_expandCIDRInputValue(t *testing.T) {
 	}
 }
 
+func Test_expandCIDRInputValueWithExclusions(t *testing.T) {
+	excluded, err := newExclusions([]string{"173.0.84.1", "http://example.com", "173.0.85.0/24"}, []string{"173.0.84.2/31"})
+	require.Nil(t, err, "could not create exclusions")
+
+	hm, err := hybrid.New(hybrid.DefaultDiskOptions)
+	require.Nil(t, err, "could not create temporary input file")
+	input := &Input{hostMap: hm, exclusions: excluded, ipOptions: &ipOptions{IPV4: true}}
+	defer input.Close()
+
+	input.expandCIDRInputValue("173.0.84.0/29")
+	input.expandCIDRInputValue("173.0.85.0/28")
+	input.Set("example.com:443")
+	input.Set("https://scanme.sh")
+
+	got := []string{}
+	input.hostMap.Scan(func(k, _ []byte) error {
+		var metainput contextargs.MetaInput
+		if err := metainput.Unmarshal(string(k)); err != nil {
+			return err
+		}
+		got = append(got, metainput.Input)
+		return nil
+	})
+	require.ElementsMatch(t, []string{"173.0.84.0", "173.0.84.4", "173.0.84.5", "173.0.84.6", "173.0.84.7", "https://scanme.sh"}, got, "could not get correct inputs")
+	require.Equal(t, int64(4), input.excludedCount, "could not get correct excluded count")
+
+	_, err = newExclusions(nil, []string{"example.com"})
+	require.NotNil(t, err, "could not get error for invalid cidr")
+}
+
+func Test_SetWithMetadata(t *testing.T) {
+	hm, err := hybrid.New(hybrid.DefaultDiskOptions)
+	require.Nil(t, err, "could not create temporary input file")
+	input := &Input{hostMap: hm, ipOptions: &ipOptions{IPV4: true}}
+	defer input.Close()
+
+	input.SetWithMetadata("10.0.0.1:443", map[string]string{"uncover_source": "shodan"})
+
+	got := []*contextargs.MetaInput{}
+	input.hostMap.Scan(func(k, _ []byte) error {
+		metainput := &contextargs.MetaInput{}
+		if err := metainput.Unmarshal(string(k)); err != nil {
+			return err
+		}
+		got = append(got, metainput)
+		return nil
+	})
+	require.Len(t, got, 1)
+	require.Equal(t, "10.0.0.1:443", got[0].Input)
+	require.Equal(t, map[string]string{"uncover_source": "shodan"}, got[0].Metadata, "could not get input metadata")
+}
+
 type mockDnsHandler struct{}
 
 func (m *mockDnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
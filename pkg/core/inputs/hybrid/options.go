@@ -1,7 +1,114 @@
 package hybrid
 
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	iputil "github.com/projectdiscovery/utils/ip"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
 type ipOptions struct {
 	ScanAllIPs bool
 	IPV4       bool
 	IPV6       bool
 }
+
+// exclusions contains the hosts and networks excluded from the input
+type exclusions struct {
+	hosts    map[string]struct{}
+	networks []*net.IPNet
+}
+
+// newExclusions creates exclusions from a list of hosts/ips and cidr ranges.
+// cidr ranges passed as hosts are treated as networks as well
+func newExclusions(hosts, cidrs []string) (*exclusions, error) {
+	if len(hosts) == 0 && len(cidrs) == 0 {
+		return nil, nil
+	}
+	e := &exclusions{hosts: make(map[string]struct{})}
+	for _, host := range hosts {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		if iputil.IsCIDR(host) {
+			cidrs = append(cidrs, host)
+			continue
+		}
+		e.hosts[strings.ToLower(normalizeHostname(host))] = struct{}{}
+	}
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse excluded cidr %s", cidr)
+		}
+		e.networks = append(e.networks, network)
+	}
+	return e, nil
+}
+
+// isExcluded returns true if the host or custom ip of the input is excluded
+func (e *exclusions) isExcluded(metaInput *contextargs.MetaInput) bool {
+	if e == nil {
+		return false
+	}
+	host := normalizeHostname(metaInput.Input)
+	if _, ok := e.hosts[strings.ToLower(host)]; ok {
+		return true
+	}
+	if metaInput.CustomIP != "" {
+		if _, ok := e.hosts[metaInput.CustomIP]; ok {
+			return true
+		}
+	}
+	return e.containsIP(host) || e.containsIP(metaInput.CustomIP)
+}
+
+// containsCIDR returns true if all addresses of the cidr are excluded
+func (e *exclusions) containsCIDR(cidr string) bool {
+	if e == nil {
+		return false
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	ones, _ := network.Mask.Size()
+	for _, excluded := range e.networks {
+		excludedOnes, _ := excluded.Mask.Size()
+		if excludedOnes <= ones && excluded.Contains(network.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *exclusions) containsIP(value string) bool {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+	for _, network := range e.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHostname returns the hostname of an input value which
+// can be an url, a host:port pair or a plain host
+func normalizeHostname(value string) string {
+	if urlx, err := urlutil.Parse(value); err == nil && urlx.Hostname() != "" {
+		return urlx.Hostname()
+	}
+	return value
+}
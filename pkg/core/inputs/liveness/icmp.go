@@ -0,0 +1,126 @@
+package liveness
+
+import (
+	"errors"
+	"net"
+	"os"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// icmp protocol numbers and echo request parameters
+const (
+	protocolICMP     = 1
+	protocolICMPv6   = 58
+	icmpReadBufSize  = 1500
+	icmpEchoSequence = 1
+)
+
+// checkICMP sends an echo request to the first address of the target,
+// an unprivileged socket is used where supported with raw sockets as fallback
+func (p *Prober) checkICMP(t target) error {
+	ip, err := resolve(t.address)
+	if err != nil {
+		return err
+	}
+	if err := protocolstate.CheckHost(ip.String()); err != nil {
+		return err
+	}
+
+	network, address, protocol := "udp4", "0.0.0.0", protocolICMP
+	var echoType, replyType icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	if ip.To4() == nil {
+		network, address, protocol = "udp6", "::", protocolICMPv6
+		echoType, replyType = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+	conn, err := icmp.ListenPacket(network, address)
+	privileged := false
+	if err != nil {
+		rawNetwork := "ip4:icmp"
+		if protocol == protocolICMPv6 {
+			rawNetwork = "ip6:ipv6-icmp"
+		}
+		if conn, err = icmp.ListenPacket(rawNetwork, address); err != nil {
+			return err
+		}
+		privileged = true
+	}
+	defer conn.Close()
+
+	id := os.Getpid() & 0xffff
+	message := icmp.Message{
+		Type: echoType,
+		Body: &icmp.Echo{ID: id, Seq: icmpEchoSequence, Data: []byte("nuclei")},
+	}
+	data, err := message.Marshal(nil)
+	if err != nil {
+		return err
+	}
+	var destination net.Addr = &net.UDPAddr{IP: ip}
+	if privileged {
+		destination = &net.IPAddr{IP: ip}
+	}
+	if err := conn.SetDeadline(time.Now().Add(p.options.Timeout)); err != nil {
+		return err
+	}
+	if _, err := conn.WriteTo(data, destination); err != nil {
+		return err
+	}
+
+	buffer := make([]byte, icmpReadBufSize)
+	for {
+		n, peer, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return err
+		}
+		if !addressIP(peer).Equal(ip) {
+			continue
+		}
+		reply, err := icmp.ParseMessage(protocol, buffer[:n])
+		if err != nil || reply.Type != replyType {
+			continue
+		}
+		// the kernel rewrites the identifier of unprivileged echo requests
+		if echo, ok := reply.Body.(*icmp.Echo); ok && (!privileged || echo.ID == id) {
+			return nil
+		}
+	}
+}
+
+// resolve returns the first ip address of a host
+func resolve(host string) (net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip, nil
+	}
+	if protocolstate.Dialer != nil {
+		data, err := protocolstate.Dialer.GetDNSData(host)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range append(data.A, data.AAAA...) {
+			if ip := net.ParseIP(value); ip != nil {
+				return ip, nil
+			}
+		}
+		return nil, errors.New("no address found for host")
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil, err
+	}
+	return ips[0], nil
+}
+
+func addressIP(addr net.Addr) net.IP {
+	switch value := addr.(type) {
+	case *net.UDPAddr:
+		return value.IP
+	case *net.IPAddr:
+		return value.IP
+	}
+	return nil
+}
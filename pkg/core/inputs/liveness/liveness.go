@@ -0,0 +1,264 @@
+// Package liveness implements the pre-scan liveness check of targets
+// which removes dead hosts from the input before templates are scheduled.
+package liveness
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	"github.com/remeh/sizedwaitgroup"
+)
+
+const (
+	// MethodTCP checks if a tcp connection can be established to the target
+	MethodTCP = "tcp"
+	// MethodHTTP checks if the target responds to a HEAD request
+	MethodHTTP = "http"
+	// MethodICMP checks if the target answers an icmp echo request
+	MethodICMP = "icmp"
+)
+
+// DefaultConcurrency is the default number of targets checked in parallel
+const DefaultConcurrency = 50
+
+// DefaultPorts are the ports checked by the tcp method for targets without port
+var DefaultPorts = []string{"80", "443"}
+
+// Methods contains the supported liveness check methods
+var Methods = []string{MethodTCP, MethodHTTP, MethodICMP}
+
+// Options contains the configuration of the liveness check
+type Options struct {
+	// Methods are the check methods tried in order until one succeeds
+	Methods []string
+	// Ports are the ports checked by the tcp method for targets without port
+	Ports []string
+	// Concurrency is the number of targets checked in parallel
+	Concurrency int
+	// Timeout is the timeout of each check
+	Timeout time.Duration
+}
+
+// Prober checks the liveness of targets
+type Prober struct {
+	options    *Options
+	httpClient *http.Client
+}
+
+// ValidateMethods returns an error if a method is not supported
+func ValidateMethods(methods []string) error {
+	for _, method := range methods {
+		if !isSupportedMethod(method) {
+			return errorutil.New("invalid liveness check method %s, supported: %s", method, strings.Join(Methods, ","))
+		}
+	}
+	return nil
+}
+
+func isSupportedMethod(method string) bool {
+	for _, supported := range Methods {
+		if strings.EqualFold(method, supported) {
+			return true
+		}
+	}
+	return false
+}
+
+// New creates a new liveness prober
+func New(options *Options) (*Prober, error) {
+	if err := ValidateMethods(options.Methods); err != nil {
+		return nil, err
+	}
+	if len(options.Ports) == 0 {
+		options.Ports = DefaultPorts
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultConcurrency
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	fips.RestrictTLSConfig(tlsConfig)
+	transport := &http.Transport{
+		DialContext:       dial,
+		TLSClientConfig:   tlsConfig,
+		DisableKeepAlives: true,
+	}
+	prober := &Prober{
+		options: options,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   options.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+	return prober, nil
+}
+
+// dial uses the shared dialer so checks follow the network configuration of the scan
+func dial(ctx context.Context, network, address string) (net.Conn, error) {
+	if protocolstate.Dialer != nil {
+		return protocolstate.Dialer.Dial(ctx, network, address)
+	}
+	dialer := &net.Dialer{}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// Provider is an input provider whose targets are checked
+type Provider interface {
+	Scan(callback func(value *contextargs.MetaInput) bool)
+}
+
+// DeadTargets checks all the targets of provider and returns the dead ones,
+// callback is called with the result of every check
+func (p *Prober) DeadTargets(provider Provider, callback func(input *contextargs.MetaInput, err error)) []*contextargs.MetaInput {
+	var (
+		mutex sync.Mutex
+		dead  []*contextargs.MetaInput
+	)
+	swg := sizedwaitgroup.New(p.options.Concurrency)
+	provider.Scan(func(value *contextargs.MetaInput) bool {
+		swg.Add()
+		go func(input *contextargs.MetaInput) {
+			defer swg.Done()
+
+			err := p.Check(input)
+			if err != nil {
+				mutex.Lock()
+				dead = append(dead, input)
+				mutex.Unlock()
+			}
+			if callback != nil {
+				callback(input, err)
+			}
+		}(value)
+		return true
+	})
+	swg.Wait()
+	return dead
+}
+
+// Check returns nil if the target is alive for one of the methods,
+// or an error with the failures of all methods otherwise
+func (p *Prober) Check(input *contextargs.MetaInput) error {
+	target := parseTarget(input)
+	var failures []string
+	for _, method := range p.options.Methods {
+		var err error
+		switch strings.ToLower(method) {
+		case MethodTCP:
+			err = p.checkTCP(target)
+		case MethodHTTP:
+			err = p.checkHTTP(target)
+		case MethodICMP:
+			err = p.checkICMP(target)
+		}
+		if err == nil {
+			return nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", strings.ToLower(method), err))
+	}
+	return errorutil.New("host is not alive (%s)", strings.Join(failures, "; "))
+}
+
+// target is the host and port of an input to check
+type target struct {
+	host   string
+	port   string
+	scheme string
+	url    string
+	// address is the custom ip of the input, or host otherwise
+	address string
+}
+
+func parseTarget(input *contextargs.MetaInput) target {
+	var t target
+	value := strings.TrimSpace(input.Input)
+	if strings.Contains(value, "://") {
+		if parsed, err := url.Parse(value); err == nil {
+			t.host, t.port, t.scheme, t.url = parsed.Hostname(), parsed.Port(), strings.ToLower(parsed.Scheme), value
+		}
+	}
+	if t.host == "" {
+		value, _, _ = strings.Cut(value, "/")
+		if host, port, err := net.SplitHostPort(value); err == nil {
+			t.host, t.port = host, port
+		} else {
+			t.host = strings.Trim(value, "[]")
+		}
+	}
+	t.address = t.host
+	if input.CustomIP != "" {
+		t.address = input.CustomIP
+	}
+	return t
+}
+
+// ports returns the ports to check with tcp
+func (p *Prober) ports(t target) []string {
+	switch {
+	case t.port != "":
+		return []string{t.port}
+	case t.scheme == "http":
+		return []string{"80"}
+	case t.scheme == "https":
+		return []string{"443"}
+	}
+	return p.options.Ports
+}
+
+func (p *Prober) checkTCP(t target) error {
+	var lastErr error
+	for _, port := range p.ports(t) {
+		ctx, cancel := context.WithTimeout(context.Background(), p.options.Timeout)
+		conn, err := dial(ctx, "tcp", net.JoinHostPort(t.address, port))
+		cancel()
+		if err == nil {
+			_ = conn.Close()
+			return nil
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+func (p *Prober) checkHTTP(t target) error {
+	var urls []string
+	if t.scheme == "http" || t.scheme == "https" {
+		urls = []string{t.url}
+	} else {
+		hostPort := t.host
+		if t.port != "" {
+			hostPort = net.JoinHostPort(t.host, t.port)
+		} else if strings.Contains(t.host, ":") {
+			hostPort = "[" + t.host + "]"
+		}
+		urls = []string{"https://" + hostPort, "http://" + hostPort}
+	}
+	var lastErr error
+	for _, target := range urls {
+		req, err := http.NewRequest(http.MethodHead, target, nil)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp, err := p.httpClient.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+			return nil
+		}
+		lastErr = err
+	}
+	return lastErr
+}
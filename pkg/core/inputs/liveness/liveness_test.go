@@ -0,0 +1,73 @@
+package liveness
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/stretchr/testify/require"
+)
+
+type testProvider []string
+
+func (p testProvider) Scan(callback func(value *contextargs.MetaInput) bool) {
+	for _, value := range p {
+		if !callback(&contextargs.MetaInput{Input: value}) {
+			return
+		}
+	}
+}
+
+// closedAddress returns an address nothing is listening on
+func closedAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	address := listener.Addr().String()
+	listener.Close()
+	return address
+}
+
+func TestDeadTargets(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodHead, r.Method)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	closed := closedAddress(t)
+
+	for _, method := range []string{MethodTCP, MethodHTTP} {
+		prober, err := New(&Options{Methods: []string{method}, Timeout: 2 * time.Second})
+		require.Nil(t, err, "could not create prober")
+
+		checked := 0
+		dead := prober.DeadTargets(testProvider{ts.URL, strings.TrimPrefix(ts.URL, "http://"), "http://" + closed, closed}, func(_ *contextargs.MetaInput, _ error) {
+			checked++
+		})
+		require.Equal(t, 4, checked)
+		var deadInputs []string
+		for _, input := range dead {
+			deadInputs = append(deadInputs, input.Input)
+		}
+		require.ElementsMatch(t, []string{"http://" + closed, closed}, deadInputs, "could not detect dead targets with %s", method)
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		input    *contextargs.MetaInput
+		expected target
+	}{
+		{&contextargs.MetaInput{Input: "https://example.com/path"}, target{host: "example.com", scheme: "https", url: "https://example.com/path", address: "example.com"}},
+		{&contextargs.MetaInput{Input: "example.com:8080"}, target{host: "example.com", port: "8080", address: "example.com"}},
+		{&contextargs.MetaInput{Input: "[::1]:22"}, target{host: "::1", port: "22", address: "::1"}},
+		{&contextargs.MetaInput{Input: "example.com", CustomIP: "10.0.0.1"}, target{host: "example.com", address: "10.0.0.1"}},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, parseTarget(test.input), "could not parse %s", test.input.Input)
+	}
+	require.NotNil(t, ValidateMethods([]string{"tcp", "udp"}), "could not get error for invalid method")
+}
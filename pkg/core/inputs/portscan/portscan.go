@@ -0,0 +1,260 @@
+// Package portscan parses nmap and masscan output into targets with port and service context.
+package portscan
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// Service is an open tcp port found by a port scanner
+type Service struct {
+	// IP is the address of the scanned host
+	IP string
+	// Host is the hostname of the scanned host if known
+	Host string
+	// Port is the open port
+	Port int
+	// Name is the detected service name (ex: http, https, postgresql) if known
+	Name string
+}
+
+// Address returns the host:port address of the service
+// preferring the hostname over the ip when known
+func (s Service) Address() string {
+	host := s.Host
+	if host == "" {
+		host = s.IP
+	}
+	return net.JoinHostPort(host, strconv.Itoa(s.Port))
+}
+
+// Parse parses nmap xml, masscan xml, masscan json or masscan list output
+// from reader and calls callback for each open tcp port. format is detected from the contents
+func Parse(reader io.Reader, callback func(service Service)) error {
+	buffered := bufio.NewReader(reader)
+	for {
+		b, err := buffered.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = buffered.ReadByte()
+			continue
+		case '<':
+			return parseXML(buffered, callback)
+		case '[', '{':
+			return parseMasscanJSON(buffered, callback)
+		default:
+			return parseMasscanList(buffered, callback)
+		}
+	}
+}
+
+type xmlHost struct {
+	Status struct {
+		State string `xml:"state,attr"`
+	} `xml:"status"`
+	Addresses []struct {
+		Addr     string `xml:"addr,attr"`
+		AddrType string `xml:"addrtype,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"hostnames>hostname"`
+	Ports []struct {
+		Protocol string `xml:"protocol,attr"`
+		PortID   int    `xml:"portid,attr"`
+		State    struct {
+			State string `xml:"state,attr"`
+		} `xml:"state"`
+		Service struct {
+			Name   string `xml:"name,attr"`
+			Tunnel string `xml:"tunnel,attr"`
+			Method string `xml:"method,attr"`
+		} `xml:"service"`
+	} `xml:"ports>port"`
+}
+
+// parseXML parses nmap and masscan (-oX) xml output host by host
+func parseXML(reader io.Reader, callback func(service Service)) error {
+	// masscan reports banners as separate hosts so its results are aggregated
+	var masscan *masscanResults
+	emit := callback
+
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			if masscan != nil {
+				masscan.flush(callback)
+			}
+			return nil
+		}
+		if err != nil {
+			return errorutil.NewWithErr(err).Msgf("could not parse xml port scan output")
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "nmaprun" {
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "scanner" && attr.Value == "masscan" {
+					masscan = &masscanResults{}
+					emit = masscan.add
+				}
+			}
+			continue
+		}
+		if start.Name.Local != "host" {
+			continue
+		}
+		var host xmlHost
+		if err := decoder.DecodeElement(&host, &start); err != nil {
+			return errorutil.NewWithErr(err).Msgf("could not parse xml port scan host")
+		}
+		if host.Status.State != "" && host.Status.State != "up" {
+			continue
+		}
+		var ip, hostname string
+		for _, address := range host.Addresses {
+			if address.AddrType == "ipv4" || address.AddrType == "ipv6" {
+				ip = address.Addr
+				break
+			}
+		}
+		// prefer the hostname given by the user over ptr records
+		for _, name := range host.Hostnames {
+			if hostname == "" || name.Type == "user" {
+				hostname = name.Name
+			}
+		}
+		for _, port := range host.Ports {
+			if port.Protocol != "tcp" || port.State.State != "open" {
+				continue
+			}
+			name := port.Service.Name
+			if port.Service.Tunnel == "ssl" && name == "http" {
+				name = "https"
+			}
+			// names guessed from the nmap services table without version
+			// detection are unreliable for non standard ports
+			if port.Service.Method == "table" {
+				name = ""
+			}
+			emit(Service{IP: ip, Host: hostname, Port: port.PortID, Name: name})
+		}
+	}
+}
+
+type masscanJSONHost struct {
+	IP    string `json:"ip"`
+	Ports []struct {
+		Port    int    `json:"port"`
+		Proto   string `json:"proto"`
+		Status  string `json:"status"`
+		Service struct {
+			Name string `json:"name"`
+		} `json:"service"`
+	} `json:"ports"`
+}
+
+// masscanResults aggregates masscan records since open ports and their
+// banners are reported as separate records
+type masscanResults struct {
+	services []Service
+	index    map[string]int
+}
+
+func (m *masscanResults) add(service Service) {
+	if m.index == nil {
+		m.index = make(map[string]int)
+	}
+	key := service.Address()
+	if i, ok := m.index[key]; ok {
+		if m.services[i].Name == "" {
+			m.services[i].Name = service.Name
+		}
+		return
+	}
+	m.index[key] = len(m.services)
+	m.services = append(m.services, service)
+}
+
+func (m *masscanResults) flush(callback func(service Service)) {
+	for _, service := range m.services {
+		callback(service)
+	}
+}
+
+// parseMasscanJSON parses masscan (-oJ) json output which contains one host
+// object per line that may be followed by a comma
+func parseMasscanJSON(reader io.Reader, callback func(service Service)) error {
+	results := &masscanResults{}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(strings.TrimSpace(scanner.Text()), ",")
+		line = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "["), "]"))
+		if line == "" || !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var host masscanJSONHost
+		if err := json.Unmarshal([]byte(line), &host); err != nil {
+			return errorutil.NewWithErr(err).Msgf("could not parse masscan json line")
+		}
+		for _, port := range host.Ports {
+			if port.Proto != "tcp" || (port.Status != "" && port.Status != "open") {
+				continue
+			}
+			results.add(Service{IP: host.IP, Port: port.Port, Name: port.Service.Name})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	results.flush(callback)
+	return nil
+}
+
+// parseMasscanList parses masscan (-oL) list output with lines in
+// `open tcp <port> <ip> <timestamp>` or `banner tcp <port> <ip> <timestamp> <service> <banner>` format
+func parseMasscanList(reader io.Reader, callback func(service Service)) error {
+	results := &masscanResults{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || strings.HasPrefix(fields[0], "#") || fields[1] != "tcp" {
+			continue
+		}
+		port, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return errorutil.New("invalid port %s in masscan list output", fields[2])
+		}
+		switch fields[0] {
+		case "open":
+			results.add(Service{IP: fields[3], Port: port})
+		case "banner":
+			if len(fields) > 5 {
+				results.add(Service{IP: fields[3], Port: port, Name: fields[5]})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	results.flush(callback)
+	return nil
+}
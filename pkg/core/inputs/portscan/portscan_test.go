@@ -0,0 +1,65 @@
+package portscan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, data string) []Service {
+	var services []Service
+	err := Parse(strings.NewReader(data), func(service Service) {
+		services = append(services, service)
+	})
+	require.Nil(t, err, "could not parse port scan output")
+	return services
+}
+
+func TestParseNmapXML(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE nmaprun>
+<nmaprun scanner="nmap" args="nmap -sV -oX - scanme.sh">
+<host><status state="up" reason="syn-ack"/>
+<address addr="10.0.0.1" addrtype="ipv4"/>
+<hostnames><hostname name="scanme.sh" type="user"/><hostname name="ptr.example.com" type="PTR"/></hostnames>
+<ports>
+<port protocol="tcp" portid="22"><state state="open"/><service name="ssh" method="probed"/></port>
+<port protocol="tcp" portid="443"><state state="open"/><service name="http" tunnel="ssl" method="probed"/></port>
+<port protocol="tcp" portid="3000"><state state="open"/><service name="ppp" method="table"/></port>
+<port protocol="tcp" portid="5432"><state state="closed"/><service name="postgresql" method="probed"/></port>
+<port protocol="udp" portid="53"><state state="open"/><service name="domain" method="probed"/></port>
+</ports>
+</host>
+<host><status state="down" reason="no-response"/><address addr="10.0.0.2" addrtype="ipv4"/></host>
+</nmaprun>`
+	require.Equal(t, []Service{
+		{IP: "10.0.0.1", Host: "scanme.sh", Port: 22, Name: "ssh"},
+		{IP: "10.0.0.1", Host: "scanme.sh", Port: 443, Name: "https"},
+		{IP: "10.0.0.1", Host: "scanme.sh", Port: 3000},
+	}, parse(t, data), "could not parse nmap xml")
+}
+
+func TestParseMasscan(t *testing.T) {
+	xml := `<?xml version="1.0"?>
+<nmaprun scanner="masscan" start="1700000000" version="1.0-BETA">
+<host endtime="1700000001"><address addr="10.0.0.1" addrtype="ipv4"/><ports><port protocol="tcp" portid="80"><state state="open" reason="syn-ack"/></port></ports></host>
+<host endtime="1700000002"><address addr="10.0.0.1" addrtype="ipv4"/><ports><port protocol="tcp" portid="80"><state state="open" reason="response"/><service name="http" banner="nginx"></service></port></ports></host>
+</nmaprun>`
+	require.Equal(t, []Service{{IP: "10.0.0.1", Port: 80, Name: "http"}}, parse(t, xml), "could not parse masscan xml")
+
+	json := `[
+{   "ip": "10.0.0.1",   "timestamp": "1700000000", "ports": [ {"port": 5432, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 54} ] },
+{   "ip": "10.0.0.1",   "timestamp": "1700000001", "ports": [ {"port": 5432, "proto": "tcp", "service": {"name": "postgresql", "banner": ""} } ] },
+{   "ip": "10.0.0.2",   "timestamp": "1700000002", "ports": [ {"port": 22, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 54} ] }
+]`
+	require.Equal(t, []Service{{IP: "10.0.0.1", Port: 5432, Name: "postgresql"}, {IP: "10.0.0.2", Port: 22}}, parse(t, json), "could not parse masscan json")
+
+	list := `#masscan
+open tcp 6379 10.0.0.3 1700000000
+banner tcp 6379 10.0.0.3 1700000001 redis -ERR unknown command
+open udp 161 10.0.0.3 1700000000
+# end
+`
+	require.Equal(t, []Service{{IP: "10.0.0.3", Port: 6379, Name: "redis"}}, parse(t, list), "could not parse masscan list")
+}
@@ -6,6 +6,7 @@ import (
 	"sync/atomic"
 
 	"github.com/remeh/sizedwaitgroup"
+	"golang.org/x/net/publicsuffix"
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
@@ -20,10 +21,11 @@ func (e *Engine) executeWorkflow(input *contextargs.MetaInput, w *workflows.Work
 	results := &atomic.Bool{}
 
 	// at this point we should be at the start root execution of a workflow tree, hence we create global shared instances
-	workflowCookieJar, _ := cookiejar.New(nil)
-	ctxArgs := contextargs.New()
-	ctxArgs.MetaInput = input
+	workflowCookieJar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	ctxArgs := e.newContextArgs(input)
 	ctxArgs.CookieJar = workflowCookieJar
+	// cookie-reuse makes every step use the workflow cookies, not only the http ones
+	ctxArgs.CookieReuse = w.CookieReuse
 
 	// we can know the nesting level only at runtime, so the best we can do here is increase template threads by one unit in case it's equal to 1 to allow
 	// at least one subtemplate to go through, which it's idempotent to one in-flight template as the parent one is in an idle state
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
@@ -161,9 +163,43 @@ func TestWorkflowsSubtemplatesWithMatcherNoMatch(t *testing.T) {
 	require.Equal(t, "", secondInput, "could not get correct second input")
 }
 
+func TestWorkflowsCookieReuse(t *testing.T) {
+	progressBar, _ := progress.NewStatsTicker(0, false, false, false, 0)
+
+	target, _ := url.Parse("https://test.com")
+	var cookies []*http.Cookie
+	var cookieReuse bool
+	workflow := &workflows.Workflow{Options: &protocols.ExecutorOptions{Options: &types.Options{TemplateThreads: 10}}, CookieReuse: true, Workflows: []*workflows.WorkflowTemplate{
+		{Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: true, contextHook: func(input *contextargs.Context) {
+				input.CookieJar.SetCookies(target, []*http.Cookie{{Name: "session", Value: "admin"}})
+			}, outputs: []*output.InternalWrappedEvent{
+				{OperatorsResult: &operators.Result{
+					Matches:  map[string][]string{"login": {}},
+					Extracts: map[string][]string{},
+				}},
+			}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}, Matchers: []*workflows.Matcher{{Name: stringslice.StringSlice{Value: "login"}, Subtemplates: []*workflows.WorkflowTemplate{{Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: true, contextHook: func(input *contextargs.Context) {
+				cookies = input.CookieJar.Cookies(target)
+				cookieReuse = input.CookieReuse
+			}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}}}}}},
+	}}
+
+	engine := &Engine{}
+	matched := engine.executeWorkflow(&contextargs.MetaInput{Input: "https://test.com"}, workflow)
+	require.True(t, matched, "could not get correct match value")
+
+	require.True(t, cookieReuse, "could not get cookie reuse of subtemplate")
+	require.Len(t, cookies, 1, "could not get cookies of subtemplate")
+	require.Equal(t, "admin", cookies[0].Value, "could not get session cookie of subtemplate")
+}
+
 type mockExecuter struct {
 	result      bool
 	executeHook func(input *contextargs.MetaInput)
+	contextHook func(input *contextargs.Context)
 	outputs     []*output.InternalWrappedEvent
 }
 
@@ -182,6 +218,9 @@ func (m *mockExecuter) Execute(input *contextargs.Context) (bool, error) {
 	if m.executeHook != nil {
 		m.executeHook(input.MetaInput)
 	}
+	if m.contextHook != nil {
+		m.contextHook(input)
+	}
 	return m.result, nil
 }
 
@@ -190,6 +229,9 @@ func (m *mockExecuter) ExecuteWithResults(input *contextargs.Context, callback p
 	if m.executeHook != nil {
 		m.executeHook(input.MetaInput)
 	}
+	if m.contextHook != nil {
+		m.contextHook(input)
+	}
 	for _, output := range m.outputs {
 		callback(output)
 	}
@@ -0,0 +1,189 @@
+// Package cvedb implements an offline CVE metadata database used to enrich
+// results of templates referencing CVE IDs on scanners without internet access.
+package cvedb
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// metadataFile is the file containing the sync state of a database directory
+const metadataFile = "metadata.json"
+
+// Record contains the metadata of a CVE
+type Record struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description,omitempty"`
+	CVSSScore   float64    `json:"cvss-score,omitempty"`
+	CVSSMetrics string     `json:"cvss-metrics,omitempty"`
+	Severity    string     `json:"severity,omitempty"`
+	Published   string     `json:"published,omitempty"`
+	Affected    []Affected `json:"affected,omitempty"`
+}
+
+// Affected is a product version range affected by a CVE
+type Affected struct {
+	CPE                   string `json:"cpe"`
+	VersionStartIncluding string `json:"version-start-including,omitempty"`
+	VersionStartExcluding string `json:"version-start-excluding,omitempty"`
+	VersionEndIncluding   string `json:"version-end-including,omitempty"`
+	VersionEndExcluding   string `json:"version-end-excluding,omitempty"`
+}
+
+// Metadata is the sync state of a database
+type Metadata struct {
+	// LastSync is the time of the last successful sync
+	LastSync time.Time `json:"last-sync"`
+	// Records is the number of records in the database
+	Records int `json:"records"`
+}
+
+// DB is an offline CVE database stored as one gzip json file per CVE year,
+// the file of a year is loaded on the first lookup of a CVE of that year
+type DB struct {
+	directory string
+
+	mutex sync.Mutex
+	years map[string]map[string]*Record
+}
+
+// Open opens the database stored in directory
+func Open(directory string) (*DB, error) {
+	if !fileutil.FolderExists(directory) {
+		return nil, errors.Errorf("cve database %s does not exist", directory)
+	}
+	return &DB{directory: directory, years: make(map[string]map[string]*Record)}, nil
+}
+
+// Get returns the record of a CVE, nil if it is not in the database
+func (db *DB) Get(id string) *Record {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	year := cveYear(id)
+	if year == "" {
+		return nil
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	records, ok := db.years[year]
+	if !ok {
+		var err error
+		if records, err = readYear(db.directory, year); err != nil && !os.IsNotExist(err) {
+			return nil
+		}
+		db.years[year] = records
+	}
+	return records[id]
+}
+
+// Metadata returns the sync state of the database
+func (db *DB) Metadata() (*Metadata, error) {
+	return readMetadata(db.directory)
+}
+
+// cveYear returns the year of a CVE id, empty if it is not a CVE id
+func cveYear(id string) string {
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 || parts[0] != "CVE" || len(parts[1]) != 4 {
+		return ""
+	}
+	return parts[1]
+}
+
+func yearFile(directory, year string) string {
+	return filepath.Join(directory, "CVE-"+year+".json.gz")
+}
+
+func readYear(directory, year string) (map[string]*Record, error) {
+	file, err := os.Open(yearFile(directory, year))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	records := make(map[string]*Record)
+	if err := json.NewDecoder(reader).Decode(&records); err != nil {
+		return nil, errors.Wrapf(err, "could not decode cve database year %s", year)
+	}
+	return records, nil
+}
+
+func writeYear(directory, year string, records map[string]*Record) error {
+	path := yearFile(directory, year)
+	temporary := path + ".tmp"
+	file, err := os.Create(temporary)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(file)
+	if err := json.NewEncoder(writer).Encode(records); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temporary, path)
+}
+
+func readMetadata(directory string) (*Metadata, error) {
+	data, err := os.ReadFile(filepath.Join(directory, metadataFile))
+	if err != nil {
+		return nil, err
+	}
+	metadata := &Metadata{}
+	if err := json.Unmarshal(data, metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func writeMetadata(directory string, metadata *Metadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(directory, metadataFile), data, 0644)
+}
+
+var defaultDB atomic.Pointer[DB]
+
+// SetDefault sets the database used to enrich results, nil disables enrichment
+func SetDefault(db *DB) {
+	defaultDB.Store(db)
+}
+
+// Lookup returns the records of the CVE ids found in the default database
+func Lookup(ids ...string) []*Record {
+	db := defaultDB.Load()
+	if db == nil {
+		return nil
+	}
+	var records []*Record
+	for _, id := range ids {
+		if record := db.Get(id); record != nil {
+			records = append(records, record)
+		}
+	}
+	return records
+}
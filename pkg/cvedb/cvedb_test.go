@@ -0,0 +1,75 @@
+package cvedb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+const nvdCVEFormat = `{"cve":{"id":"%s","published":"2021-12-10T10:15:09.143","descriptions":[{"lang":"es","value":"otro"},{"lang":"en","value":"%s"}],
+"metrics":{"cvssMetricV31":[{"cvssData":{"baseScore":10.0,"vectorString":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H","baseSeverity":"CRITICAL"}}],
+"cvssMetricV2":[{"cvssData":{"baseScore":9.3,"vectorString":"AV:N/AC:M/Au:N/C:C/I:C/A:C"},"baseSeverity":"HIGH"}]},
+"configurations":[{"nodes":[{"cpeMatch":[{"vulnerable":true,"criteria":"cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*","versionStartIncluding":"2.0.1","versionEndExcluding":"2.3.1"},
+{"vulnerable":false,"criteria":"cpe:2.3:o:linux:linux_kernel:-:*:*:*:*:*:*:*"}]}]}]}}`
+
+func TestSync(t *testing.T) {
+	var incremental bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "key", r.Header.Get("apiKey"))
+		vulnerabilities := []string{fmt.Sprintf(nvdCVEFormat, "CVE-2021-44228", "log4shell"), fmt.Sprintf(nvdCVEFormat, "CVE-2022-22965", "spring4shell")}
+		if incremental {
+			require.NotEmpty(t, r.URL.Query().Get("lastModStartDate"), "could not request modified cves only")
+			vulnerabilities = []string{fmt.Sprintf(nvdCVEFormat, "CVE-2021-44228", "log4shell updated"), fmt.Sprintf(nvdCVEFormat, "CVE-2021-45046", "log4j incomplete fix")}
+		} else {
+			require.Empty(t, r.URL.Query().Get("lastModStartDate"))
+		}
+		fmt.Fprintf(w, `{"totalResults":%d,"vulnerabilities":[%s]}`, len(vulnerabilities), strings.Join(vulnerabilities, ","))
+	}))
+	defer server.Close()
+
+	directory := t.TempDir()
+	syncer := &Syncer{URL: server.URL, APIKey: "key", client: retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)}
+	metadata, err := syncer.Sync(directory)
+	require.Nil(t, err, "could not sync cve database")
+	require.Equal(t, 2, metadata.Records)
+
+	db, err := Open(directory)
+	require.Nil(t, err)
+	record := db.Get("cve-2021-44228")
+	require.NotNil(t, record, "could not get cve record")
+	require.Equal(t, "log4shell", record.Description)
+	require.Equal(t, 10.0, record.CVSSScore)
+	require.Equal(t, "critical", record.Severity)
+	require.Equal(t, []Affected{{CPE: "cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*", VersionStartIncluding: "2.0.1", VersionEndExcluding: "2.3.1"}}, record.Affected)
+	require.Nil(t, db.Get("CVE-2020-0001"))
+	require.Nil(t, db.Get("GHSA-xxxx"))
+
+	incremental = true
+	metadata, err = syncer.Sync(directory)
+	require.Nil(t, err, "could not update cve database")
+	require.Equal(t, 3, metadata.Records)
+
+	SetDefault(nil)
+	require.Empty(t, Lookup("CVE-2021-44228"))
+	db, err = Open(directory)
+	require.Nil(t, err)
+	SetDefault(db)
+	defer SetDefault(nil)
+	records := Lookup("CVE-2021-44228", "CVE-2021-45046", "CVE-2022-22965", "CVE-2023-0001")
+	require.Len(t, records, 3)
+	require.Equal(t, "log4shell updated", records[0].Description, "could not merge updated cve")
+}
+
+func TestRecordSeverityFallback(t *testing.T) {
+	c := &nvdCVE{ID: "CVE-2010-0001"}
+	c.Metrics.CVSSMetricV2 = []nvdMetric{{BaseSeverity: "MEDIUM"}}
+	c.Metrics.CVSSMetricV2[0].CVSSData.BaseScore = 5.0
+	record := c.record()
+	require.Equal(t, "medium", record.Severity, "could not use cvss v2 severity")
+	require.Equal(t, 5.0, record.CVSSScore)
+}
@@ -0,0 +1,238 @@
+package cvedb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const (
+	// DefaultNVDURL is the cve endpoint of the NVD 2.0 api
+	DefaultNVDURL = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+	// resultsPerPage is the maximum number of cves returned by a page of the NVD api
+	resultsPerPage = 2000
+	// maxIncrementalRange is the maximum last modified range accepted by the NVD api
+	maxIncrementalRange = 120 * 24 * time.Hour
+	// nvdTimeLayout is the time format of the NVD api date parameters
+	nvdTimeLayout = "2006-01-02T15:04:05.000"
+)
+
+// Syncer downloads the NVD dataset into a database directory
+type Syncer struct {
+	// URL is the cve endpoint of the NVD api
+	URL string
+	// APIKey (optional) is the NVD api key raising the rate limit
+	APIKey string
+	// Delay is the delay between api requests, defaults to the NVD rate limit
+	Delay time.Duration
+
+	client *retryablehttp.Client
+}
+
+// NewSyncer creates a new NVD syncer
+func NewSyncer(apiKey string) *Syncer {
+	// public rate limit is 5 requests per 30 seconds, 50 with an api key
+	delay := 6 * time.Second
+	if apiKey != "" {
+		delay = 600 * time.Millisecond
+	}
+	return &Syncer{
+		URL:    DefaultNVDURL,
+		APIKey: apiKey,
+		Delay:  delay,
+		client: retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle),
+	}
+}
+
+// Sync downloads the cves modified since the last sync of the database in directory,
+// the full dataset is downloaded for new databases or outdated incremental ranges
+func (s *Syncer) Sync(directory string) (*Metadata, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, errors.Wrap(err, "could not create cve database directory")
+	}
+	started := time.Now().UTC()
+	params := url.Values{}
+	metadata, err := readMetadata(directory)
+	if err == nil && started.Sub(metadata.LastSync) < maxIncrementalRange {
+		params.Set("lastModStartDate", metadata.LastSync.UTC().Format(nvdTimeLayout))
+		params.Set("lastModEndDate", started.Format(nvdTimeLayout))
+		gologger.Info().Msgf("Updating cve database with cves modified since %s", metadata.LastSync.Format(time.RFC3339))
+	} else {
+		metadata = &Metadata{}
+		gologger.Info().Msgf("Downloading full cve database, this can take a while without an nvd api key")
+	}
+
+	updated := make(map[string]map[string]*Record)
+	for startIndex, total := 0, 1; startIndex < total; startIndex += resultsPerPage {
+		if startIndex > 0 {
+			time.Sleep(s.Delay)
+		}
+		page, err := s.fetchPage(params, startIndex)
+		if err != nil {
+			return nil, err
+		}
+		total = page.TotalResults
+		for _, vulnerability := range page.Vulnerabilities {
+			record := vulnerability.CVE.record()
+			year := cveYear(record.ID)
+			if year == "" {
+				continue
+			}
+			if updated[year] == nil {
+				updated[year] = make(map[string]*Record)
+			}
+			updated[year][record.ID] = record
+		}
+		gologger.Verbose().Msgf("Downloaded %d/%d cves", min(startIndex+resultsPerPage, total), total)
+	}
+
+	for year, records := range updated {
+		existing, err := readYear(directory, year)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if existing == nil {
+			existing = records
+		} else {
+			metadata.Records -= len(existing)
+			for id, record := range records {
+				existing[id] = record
+			}
+		}
+		metadata.Records += len(existing)
+		if err := writeYear(directory, year, existing); err != nil {
+			return nil, errors.Wrapf(err, "could not write cve database year %s", year)
+		}
+	}
+	metadata.LastSync = started
+	if err := writeMetadata(directory, metadata); err != nil {
+		return nil, errors.Wrap(err, "could not write cve database metadata")
+	}
+	return metadata, nil
+}
+
+func (s *Syncer) fetchPage(params url.Values, startIndex int) (*nvdPage, error) {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("startIndex", strconv.Itoa(startIndex))
+	query.Set("resultsPerPage", strconv.Itoa(resultsPerPage))
+
+	req, err := retryablehttp.NewRequest(http.MethodGet, s.URL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.APIKey != "" {
+		req.Header.Set("apiKey", s.APIKey)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch cves from nvd")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nvd api responded with status %d", resp.StatusCode)
+	}
+	page := &nvdPage{}
+	if err := json.NewDecoder(resp.Body).Decode(page); err != nil {
+		return nil, errors.Wrap(err, "could not decode nvd response")
+	}
+	return page, nil
+}
+
+// nvdPage is a page of the NVD 2.0 cve api
+type nvdPage struct {
+	TotalResults    int `json:"totalResults"`
+	Vulnerabilities []struct {
+		CVE nvdCVE `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+type nvdCVE struct {
+	ID           string `json:"id"`
+	Published    string `json:"published"`
+	Descriptions []struct {
+		Lang  string `json:"lang"`
+		Value string `json:"value"`
+	} `json:"descriptions"`
+	Metrics struct {
+		CVSSMetricV31 []nvdMetric `json:"cvssMetricV31"`
+		CVSSMetricV30 []nvdMetric `json:"cvssMetricV30"`
+		CVSSMetricV2  []nvdMetric `json:"cvssMetricV2"`
+	} `json:"metrics"`
+	Configurations []struct {
+		Nodes []struct {
+			CPEMatch []struct {
+				Vulnerable            bool   `json:"vulnerable"`
+				Criteria              string `json:"criteria"`
+				VersionStartIncluding string `json:"versionStartIncluding"`
+				VersionStartExcluding string `json:"versionStartExcluding"`
+				VersionEndIncluding   string `json:"versionEndIncluding"`
+				VersionEndExcluding   string `json:"versionEndExcluding"`
+			} `json:"cpeMatch"`
+		} `json:"nodes"`
+	} `json:"configurations"`
+}
+
+type nvdMetric struct {
+	CVSSData struct {
+		BaseScore    float64 `json:"baseScore"`
+		VectorString string  `json:"vectorString"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+	// BaseSeverity is outside of cvssData for cvss v2 metrics
+	BaseSeverity string `json:"baseSeverity"`
+}
+
+// record returns the database record of an NVD cve using the most recent cvss version
+func (c *nvdCVE) record() *Record {
+	record := &Record{ID: c.ID, Published: c.Published}
+	for _, description := range c.Descriptions {
+		if description.Lang == "en" {
+			record.Description = description.Value
+			break
+		}
+	}
+	for _, metrics := range [][]nvdMetric{c.Metrics.CVSSMetricV31, c.Metrics.CVSSMetricV30, c.Metrics.CVSSMetricV2} {
+		if len(metrics) == 0 {
+			continue
+		}
+		metric := metrics[0]
+		record.CVSSScore = metric.CVSSData.BaseScore
+		record.CVSSMetrics = metric.CVSSData.VectorString
+		record.Severity = metric.CVSSData.BaseSeverity
+		if record.Severity == "" {
+			record.Severity = metric.BaseSeverity
+		}
+		record.Severity = strings.ToLower(record.Severity)
+		break
+	}
+	for _, configuration := range c.Configurations {
+		for _, node := range configuration.Nodes {
+			for _, match := range node.CPEMatch {
+				if !match.Vulnerable {
+					continue
+				}
+				record.Affected = append(record.Affected, Affected{
+					CPE:                   match.Criteria,
+					VersionStartIncluding: match.VersionStartIncluding,
+					VersionStartExcluding: match.VersionStartExcluding,
+					VersionEndIncluding:   match.VersionEndIncluding,
+					VersionEndExcluding:   match.VersionEndExcluding,
+				})
+			}
+		}
+	}
+	return record
+}
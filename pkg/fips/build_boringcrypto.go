@@ -0,0 +1,8 @@
+//go:build fips && boringcrypto
+
+package fips
+
+import (
+	// restrict all tls configurations to approved settings
+	_ "crypto/tls/fipsonly"
+)
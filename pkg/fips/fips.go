@@ -0,0 +1,151 @@
+// Package fips restricts the crypto used by nuclei to FIPS 140 approved algorithms.
+//
+// The restrictions are enabled with the fips build tag or at runtime with Enable.
+// Building with the fips and boringcrypto tags (GOEXPERIMENT=boringcrypto) additionally
+// enforces them for every tls connection through crypto/tls/fipsonly.
+package fips
+
+import (
+	"fmt"
+	"strings"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// enabled determines if crypto usage is restricted to approved algorithms
+var enabled = buildEnabled
+
+// notApprovedError returns the error for an algorithm not approved in fips mode
+func notApprovedError(format string, args ...any) error {
+	return errorutil.NewWithTag("fips", "%s not approved in fips mode", fmt.Sprintf(format, args...))
+}
+
+// Enable restricts crypto usage to FIPS approved algorithms
+func Enable() {
+	enabled = true
+}
+
+// IsEnabled returns true if crypto usage is restricted to FIPS approved algorithms
+func IsEnabled() bool {
+	return enabled
+}
+
+// notApprovedFunctions contains the dsl helper functions using algorithms
+// not approved in fips mode and the algorithm they use
+var notApprovedFunctions = map[string]string{
+	"md5":  "md5",
+	"sha1": "sha1",
+	"mmh3": "murmur3",
+	"jarm": "jarm (non approved tls ciphers)",
+}
+
+// notApprovedHMACs contains the hash algorithms of the hmac dsl helper not approved in fips mode
+var notApprovedHMACs = []string{"md5", "sha1"}
+
+// IsRestrictedFunction returns true if calls of a dsl helper function are restricted in fips mode
+func IsRestrictedFunction(name string) bool {
+	_, ok := notApprovedFunctions[name]
+	return ok || name == "hmac"
+}
+
+// CheckFunction returns an error if a dsl helper function call uses an algorithm not
+// approved in fips mode
+func CheckFunction(name string, args ...interface{}) error {
+	if !enabled {
+		return nil
+	}
+	if algorithm, ok := notApprovedFunctions[name]; ok {
+		return notApprovedError("dsl function %s (%s)", name, algorithm)
+	}
+	if name == "hmac" && len(args) > 0 {
+		if algorithm, ok := args[0].(string); ok && isNotApprovedHMAC(algorithm) {
+			return notApprovedError("dsl function hmac (%s)", algorithm)
+		}
+	}
+	return nil
+}
+
+func isNotApprovedHMAC(algorithm string) bool {
+	for _, v := range notApprovedHMACs {
+		if strings.EqualFold(v, algorithm) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpressionViolations returns the calls to helper functions using algorithms not
+// approved in fips mode of a dsl expression, the string literals of the expression
+// are not inspected
+func ExpressionViolations(expression string) []string {
+	if !enabled {
+		return nil
+	}
+	var violations []string
+	for _, call := range functionCalls(expression) {
+		switch {
+		case call.name == "hmac" && isNotApprovedHMAC(call.firstArg):
+			violations = append(violations, "hmac("+strings.ToLower(call.firstArg)+")")
+		case call.name != "hmac" && IsRestrictedFunction(call.name):
+			violations = append(violations, call.name)
+		}
+	}
+	return violations
+}
+
+// functionCall is a function call of a dsl expression
+type functionCall struct {
+	name string
+	// firstArg is the value of the first argument if it is a string literal
+	firstArg string
+}
+
+// functionCalls returns the function calls of a dsl expression
+func functionCalls(expression string) []functionCall {
+	var calls []functionCall
+	var last *functionCall
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(expression) && expression[end] != c {
+				if expression[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if last != nil && last.firstArg == "" {
+				last.firstArg = expression[i+1 : min(end, len(expression))]
+			}
+			last = nil
+			i = end + 1
+		case isIdentifierChar(c):
+			end := i
+			for end < len(expression) && isIdentifierChar(expression[end]) {
+				end++
+			}
+			name := expression[i:end]
+			for end < len(expression) && expression[end] == ' ' {
+				end++
+			}
+			last = nil
+			if end < len(expression) && expression[end] == '(' {
+				calls = append(calls, functionCall{name: name})
+				last = &calls[len(calls)-1]
+				end++
+			}
+			i = end
+		case c == ' ':
+			i++
+		default:
+			last = nil
+			i++
+		}
+	}
+	return calls
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
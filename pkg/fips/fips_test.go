@@ -0,0 +1,68 @@
+package fips
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func enableForTest(t *testing.T) {
+	previous := enabled
+	enabled = true
+	t.Cleanup(func() { enabled = previous })
+}
+
+func TestCheckFunction(t *testing.T) {
+	enableForTest(t)
+
+	require.NotNil(t, CheckFunction("md5", "data"), "could not restrict md5")
+	require.NotNil(t, CheckFunction("hmac", "SHA1", "data", "secret"), "could not restrict hmac with sha1")
+	require.Nil(t, CheckFunction("hmac", "sha256", "data", "secret"))
+	require.Nil(t, CheckFunction("sha256", "data"))
+	require.True(t, IsRestrictedFunction("hmac"))
+	require.False(t, IsRestrictedFunction("sha512"))
+}
+
+func TestExpressionViolations(t *testing.T) {
+	enableForTest(t)
+
+	require.Equal(t, []string{"hmac(md5)", "md5"}, ExpressionViolations(`hmac("md5", "a", "b") + md5(id)`))
+	require.Equal(t, []string{"sha1"}, ExpressionViolations("sha1 (body) == hash"))
+	require.Empty(t, ExpressionViolations("sha256(body) == hmac('sha256', body, key)"))
+	require.Empty(t, ExpressionViolations(`contains(body, "md5(") && hash == "sha1(x)"`), "could not skip string literals")
+	require.Empty(t, ExpressionViolations("xmd5(body) == md5_hash"))
+}
+
+func TestTLSConfig(t *testing.T) {
+	enableForTest(t)
+
+	require.NotNil(t, CheckTLSConfig(&tls.Config{MinVersion: tls.VersionTLS11}), "could not restrict old tls versions")
+	require.NotNil(t, CheckTLSConfig(&tls.Config{CipherSuites: []uint16{tls.TLS_RSA_WITH_RC4_128_SHA}}), "could not restrict cipher suite")
+	require.NotNil(t, CheckTLSConfig(&tls.Config{CurvePreferences: []tls.CurveID{tls.X25519}}), "could not restrict curve")
+	require.Nil(t, CheckTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12, CurvePreferences: []tls.CurveID{tls.CurveP384}}))
+
+	config := &tls.Config{
+		MinVersion:       tls.VersionTLS10,
+		CipherSuites:     []uint16{tls.TLS_RSA_WITH_RC4_128_SHA, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		CurvePreferences: []tls.CurveID{tls.X25519},
+	}
+	RestrictTLSConfig(config)
+	require.Equal(t, uint16(MinTLSVersion), config.MinVersion)
+	require.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, config.CipherSuites)
+	require.Equal(t, approvedCurves, config.CurvePreferences)
+}
+
+func TestDisabled(t *testing.T) {
+	previous := enabled
+	enabled = false
+	defer func() { enabled = previous }()
+
+	require.Nil(t, CheckFunction("md5", "data"))
+	require.Empty(t, ExpressionViolations("md5(id)"))
+
+	config := &tls.Config{MinVersion: tls.VersionTLS10}
+	require.Nil(t, CheckTLSConfig(config))
+	RestrictTLSConfig(config)
+	require.Equal(t, uint16(tls.VersionTLS10), config.MinVersion)
+}
@@ -0,0 +1,107 @@
+package fips
+
+import (
+	"crypto/tls"
+)
+
+// MinTLSVersion is the minimum tls version approved in fips mode
+const MinTLSVersion = tls.VersionTLS12
+
+// approvedCipherSuites contains the tls 1.2 cipher suites approved in fips mode.
+// tls 1.3 suites are not configurable in crypto/tls and are only restricted by
+// boringcrypto builds.
+var approvedCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// approvedCurves contains the elliptic curves approved in fips mode
+var approvedCurves = []tls.CurveID{tls.CurveP256, tls.CurveP384, tls.CurveP521}
+
+// IsCipherSuiteApproved returns true if a tls cipher suite is approved in fips mode
+func IsCipherSuiteApproved(id uint16) bool {
+	for _, approved := range approvedCipherSuites {
+		if approved == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ApprovedCipherSuiteNames returns the names of the tls 1.2 cipher suites approved in fips mode
+func ApprovedCipherSuiteNames() []string {
+	names := make([]string, 0, len(approvedCipherSuites))
+	for _, id := range approvedCipherSuites {
+		names = append(names, tls.CipherSuiteName(id))
+	}
+	return names
+}
+
+// IsCurveApproved returns true if an elliptic curve is approved in fips mode
+func IsCurveApproved(curve tls.CurveID) bool {
+	for _, approved := range approvedCurves {
+		if approved == curve {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTLSConfig returns an error if explicitly configured parameters of a tls
+// client config are not approved in fips mode
+func CheckTLSConfig(config *tls.Config) error {
+	if !enabled {
+		return nil
+	}
+	if config.MinVersion != 0 && config.MinVersion < MinTLSVersion {
+		return notApprovedError("tls versions older than tls12")
+	}
+	if config.MaxVersion != 0 && config.MaxVersion < MinTLSVersion {
+		return notApprovedError("tls versions older than tls12")
+	}
+	for _, id := range config.CipherSuites {
+		if !IsCipherSuiteApproved(id) {
+			return notApprovedError("cipher suite %s", tls.CipherSuiteName(id))
+		}
+	}
+	for _, curve := range config.CurvePreferences {
+		if !IsCurveApproved(curve) {
+			return notApprovedError("curve %s", curve)
+		}
+	}
+	return nil
+}
+
+// RestrictTLSConfig restricts a tls client config to parameters approved in fips mode
+// keeping the approved ones already configured
+func RestrictTLSConfig(config *tls.Config) {
+	if !enabled {
+		return
+	}
+	if config.MinVersion < MinTLSVersion {
+		config.MinVersion = MinTLSVersion
+	}
+	if config.MaxVersion != 0 && config.MaxVersion < MinTLSVersion {
+		config.MaxVersion = MinTLSVersion
+	}
+	config.CipherSuites = restrict(config.CipherSuites, approvedCipherSuites, IsCipherSuiteApproved)
+	config.CurvePreferences = restrict(config.CurvePreferences, approvedCurves, IsCurveApproved)
+}
+
+// restrict returns the approved values or the default approved ones if none is
+func restrict[T comparable](values, defaults []T, approved func(T) bool) []T {
+	var filtered []T
+	for _, value := range values {
+		if approved(value) {
+			filtered = append(filtered, value)
+		}
+	}
+	if len(filtered) == 0 {
+		return append([]T{}, defaults...)
+	}
+	return filtered
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/projectdiscovery/hmap/store/hybrid"
+	httputil "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils/http"
 	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 	fileutil "github.com/projectdiscovery/utils/file"
 	"github.com/projectdiscovery/utils/ports"
@@ -41,7 +42,13 @@ func (h *Helper) Transform(input string, protocol templateTypes.ProtocolType) st
 		return h.convertInputToType(input, typeHostOnly, "")
 	case templateTypes.FileProtocol, templateTypes.OfflineHTTPProtocol:
 		return h.convertInputToType(input, typeFilepath, "")
-	case templateTypes.HTTPProtocol, templateTypes.HeadlessProtocol:
+	case templateTypes.HTTPProtocol:
+		// the unix sockets and named pipes are only supported by the http protocol
+		if httputil.IsSocket(input) {
+			return input
+		}
+		return h.convertInputToType(input, typeURL, "")
+	case templateTypes.HeadlessProtocol:
 		return h.convertInputToType(input, typeURL, "")
 	case templateTypes.NetworkProtocol:
 		return h.convertInputToType(input, typeHostWithOptionalPort, "")
@@ -5,6 +5,8 @@ import (
 
 	"github.com/projectdiscovery/hmap/store/hybrid"
 	"github.com/stretchr/testify/require"
+
+	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 )
 
 func TestConvertInputToType(t *testing.T) {
@@ -70,3 +72,11 @@ func TestConvertInputToType(t *testing.T) {
 		require.Equal(t, test.result, result, "could not get correct result %+v", test)
 	}
 }
+
+func TestTransformSocket(t *testing.T) {
+	helper := &Helper{}
+
+	require.Equal(t, "unix:///var/run/docker.sock", helper.Transform("unix:///var/run/docker.sock", templateTypes.HTTPProtocol))
+	require.Equal(t, "npipe:////./pipe/docker_engine", helper.Transform("npipe:////./pipe/docker_engine", templateTypes.HTTPProtocol))
+	require.Empty(t, helper.Transform("unix:///var/run/docker.sock", templateTypes.HeadlessProtocol), "sockets are only supported by http")
+}
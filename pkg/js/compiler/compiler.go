@@ -2,7 +2,11 @@
 package compiler
 
 import (
+	"context"
+	"path/filepath"
 	"runtime/debug"
+	"sync"
+	"time"
 
 	"github.com/dop251/goja"
 	"github.com/dop251/goja/parser"
@@ -12,14 +16,27 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/projectdiscovery/gologger"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libamqp"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libbytes"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libcassandra"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libcouchdb"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libdocker"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libelasticsearch"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libfs"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libftp"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libgrpc"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libikev2"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libkafka"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libkerberos"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libkubernetes"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libldap"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmemcached"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmongodb"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmqtt"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmssql"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmysql"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libnet"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libnfs"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/liboracle"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libpop3"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libpostgres"
@@ -28,9 +45,11 @@ import (
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/librsync"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libsmb"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libsmtp"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libsnmp"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libssh"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libstructs"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libtelnet"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libtls"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libvnc"
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/global"
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/goconsole"
@@ -38,19 +57,47 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
+var (
+	// ErrJSExecDeadline is returned when the script execution exceeds its timeout
+	ErrJSExecDeadline = errors.New("javascript execution timeout exceeded")
+	// ErrJSMemoryLimit is returned when the script execution exceeds its memory limit
+	ErrJSMemoryLimit = errors.New("javascript memory limit exceeded")
+)
+
 // Compiler provides a runtime to execute goja runtime
 // based javascript scripts efficiently while also
 // providing them access to custom modules defined in libs/.
 type Compiler struct {
 	registry *require.Registry
+	// modules are the sources of the modules which can be
+	// required by the scripts indexed by their path
+	modules sync.Map
 }
 
 // New creates a new compiler for the goja runtime.
 func New() *Compiler {
-	registry := new(require.Registry) // this can be shared by multiple runtimes
+	c := &Compiler{}
+	// this can be shared by multiple runtimes
+	// only the modules added to the compiler can be loaded
+	c.registry = require.NewRegistry(require.WithLoader(c.loadModule))
 	// autoregister console node module with default printer it uses gologger backend
 	require.RegisterNativeModule(console.ModuleName, console.RequireWithPrinter(goconsole.NewGoConsolePrinter()))
-	return &Compiler{registry: registry}
+	return c
+}
+
+// AddModule adds the source of the module at the path, the module can be
+// required by the scripts executed with a filename in the same directory.
+func (c *Compiler) AddModule(path, source string) {
+	c.modules.Store(filepath.ToSlash(path), source)
+}
+
+// loadModule returns the source of a module added to the compiler
+func (c *Compiler) loadModule(path string) ([]byte, error) {
+	source, ok := c.modules.Load(path)
+	if !ok {
+		return nil, require.ModuleFileDoesNotExistError
+	}
+	return []byte(source.(string)), nil
 }
 
 // ExecuteOptions provides options for executing a script.
@@ -71,6 +118,23 @@ type ExecuteOptions struct {
 	// Callback can be used to register new runtime helper functions
 	// ex: export etc
 	Callback func(runtime *goja.Runtime) error
+
+	// Context interrupts the script execution when cancelled
+	Context context.Context
+
+	// Filename is the path of the file of the script, the relative
+	// modules required by the script are resolved from its directory
+	Filename string
+
+	// Timeout is the maximum wall-clock time of the script execution.
+	// The runtime is interrupted once exceeded, busy loops included.
+	Timeout time.Duration
+
+	// MaxMemory is the maximum number of bytes the values held by the
+	// runtime can grow by during the script execution. The memory is
+	// checked by the loops and functions of the script, the allocations
+	// of a single call are not interrupted before the call returns.
+	MaxMemory int64
 }
 
 // ExecuteArgs is the arguments to pass to the script.
@@ -151,8 +215,36 @@ func (c *Compiler) ExecuteWithOptions(code string, args *ExecuteArgs, opts *Exec
 	args.TemplateCtx = generators.MergeMaps(args.TemplateCtx, args.Args)
 	_ = runtime.Set("template", args.TemplateCtx)
 
-	results, err := runtime.RunString(code)
+	if opts.Context != nil || opts.Timeout > 0 {
+		ctx, cancel := executionContext(opts)
+		stopInterrupt := context.AfterFunc(ctx, func() {
+			runtime.Interrupt(context.Cause(ctx))
+		})
+		defer func() {
+			// clear the interrupt if it fired so pooled runtimes stay usable
+			if !stopInterrupt() {
+				runtime.ClearInterrupt()
+			}
+			cancel(nil)
+		}()
+	}
+
+	var results goja.Value
+	var err error
+	if opts.MaxMemory > 0 {
+		setMemoryLimit(runtime, opts.MaxMemory)
+		results, err = runWithMemoryChecks(runtime, filepath.ToSlash(opts.Filename), code)
+	} else {
+		results, err = runtime.RunScript(filepath.ToSlash(opts.Filename), code)
+	}
 	if err != nil {
+		// return the cause of the interrupts, ex: ErrJSExecDeadline
+		var interrupted *goja.InterruptedError
+		if errors.As(err, &interrupted) {
+			if cause, ok := interrupted.Value().(error); ok {
+				return nil, cause
+			}
+		}
 		return nil, err
 	}
 	captured := results.Export()
@@ -168,6 +260,27 @@ func (c *Compiler) ExecuteWithOptions(code string, args *ExecuteArgs, opts *Exec
 	return ExecuteResult{"response": captured, "success": results.ToBoolean()}, nil
 }
 
+// executionContext returns the context of the script execution which is
+// cancelled with ErrJSExecDeadline as cause once the timeout is exceeded
+func executionContext(opts *ExecuteOptions) (context.Context, context.CancelCauseFunc) {
+	parent := opts.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	if opts.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, opts.Timeout, ErrJSExecDeadline)
+		cancelParent := cancel
+		cancel = func(cause error) {
+			// the parent is cancelled first to propagate the cause
+			cancelParent(cause)
+			cancelTimeout()
+		}
+	}
+	return ctx, cancel
+}
+
 // captureVariables captures the variables from the runtime.
 func (c *Compiler) captureVariables(runtime *goja.Runtime, variables []string) (ExecuteResult, error) {
 	results := make(ExecuteResult, len(variables))
@@ -1,8 +1,11 @@
 package compiler
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/gologger/levels"
@@ -77,3 +80,96 @@ func (n *noopWriter) Write(data []byte, level levels.Level) {
 		n.Callback(data, level)
 	}
 }
+
+func TestCompilerContextCancel(t *testing.T) {
+	compiler := New()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := compiler.ExecuteWithOptions("while (true) {}", NewExecuteArgs(), &ExecuteOptions{Context: ctx})
+	if err == nil {
+		t.Fatalf("expected script to be interrupted")
+	}
+	result, err := compiler.ExecuteWithOptions("1+1 == 2", NewExecuteArgs(), &ExecuteOptions{Context: context.Background()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GetSuccess() != true {
+		t.Fatalf("expected true, got=%v", result.GetSuccess())
+	}
+}
+
+func TestCompilerTimeout(t *testing.T) {
+	compiler := New()
+
+	_, err := compiler.ExecuteWithOptions("while (true) {}", NewExecuteArgs(), &ExecuteOptions{Timeout: 100 * time.Millisecond})
+	if !errors.Is(err, ErrJSExecDeadline) {
+		t.Fatalf("expected timeout error, got=%v", err)
+	}
+	result, err := compiler.ExecuteWithOptions("1+1 == 2", NewExecuteArgs(), &ExecuteOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GetSuccess() != true {
+		t.Fatalf("expected true, got=%v", result.GetSuccess())
+	}
+}
+
+func TestCompilerMaxMemory(t *testing.T) {
+	compiler := New()
+
+	code := `let items = []; while (true) { items.push("x".repeat(1024) + items.length) }`
+	_, err := compiler.ExecuteWithOptions(code, NewExecuteArgs(), &ExecuteOptions{MaxMemory: 32 * 1024 * 1024, Timeout: time.Minute})
+	if !errors.Is(err, ErrJSMemoryLimit) {
+		t.Fatalf("expected memory limit error, got=%v", err)
+	}
+
+	// the go values of the arguments are not allocated by the script
+	args := NewExecuteArgs()
+	args.Args["data"] = make([]byte, 64*1024*1024)
+	result, err := compiler.ExecuteWithOptions("let total = 0; for (let i = 0; i < 100000; i++) { total += i % 2 }; total == 50000", args, &ExecuteOptions{MaxMemory: 8 * 1024 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GetSuccess() != true {
+		t.Fatalf("expected true, got=%v", result.GetSuccess())
+	}
+}
+
+func TestCompilerKVStore(t *testing.T) {
+	compiler := New()
+
+	_, err := compiler.ExecuteWithOptions(`kvSet("token", "secret")`, NewExecuteArgs(), &ExecuteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the value is shared with the other executions
+	result, err := compiler.ExecuteWithOptions(`kvGet("token") == "secret" && kvGet("missing") === null`, NewExecuteArgs(), &ExecuteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GetSuccess() != true {
+		t.Fatalf("expected true, got=%v", result.GetSuccess())
+	}
+}
+
+func TestCompilerModules(t *testing.T) {
+	compiler := New()
+	compiler.AddModule("/templates/lib/login.js", `const util = require("./util.js"); module.exports = { login: (user) => util.prefix + user };`)
+	compiler.AddModule("/templates/lib/util.js", `module.exports = { prefix: "user:" };`)
+
+	code := `const lib = require("./lib/login.js"); lib.login("admin") == "user:admin"`
+	result, err := compiler.ExecuteWithOptions(code, NewExecuteArgs(), &ExecuteOptions{Filename: "/templates/template.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GetSuccess() != true {
+		t.Fatalf("expected true, got=%v", result.GetSuccess())
+	}
+
+	// only the added modules can be required
+	_, err = compiler.ExecuteWithOptions(`require("./missing.js")`, NewExecuteArgs(), &ExecuteOptions{Filename: "/templates/template.yaml"})
+	if err == nil {
+		t.Fatalf("expected missing module error")
+	}
+}
@@ -0,0 +1,289 @@
+package compiler
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/dop251/goja"
+	"github.com/dop251/goja/ast"
+	"github.com/dop251/goja/parser"
+)
+
+const (
+	// memoryCheckInterval is the minimum interval between the memory checks of a script
+	memoryCheckInterval = 50 * time.Millisecond
+	// checkMemoryFunction is the function called by the instrumented scripts
+	// at the start of the bodies of their loops and functions
+	checkMemoryFunction = "__nuclei_check_memory"
+	// gojaPackage is the package of the types followed by runtimeMemory
+	gojaPackage = "github.com/dop251/goja"
+)
+
+// memoryLimit interrupts a runtime with ErrJSMemoryLimit once the values held
+// by the runtime grew by more than maxMemory bytes during the execution.
+//
+// goja has no memory accounting and its values can't be read while the script
+// is running, so the checks are called by the instrumented script itself and
+// only walk the values of its own runtime.
+type memoryLimit struct {
+	runtime   *goja.Runtime
+	maxMemory int64
+	baseline  int64
+	next      time.Time
+}
+
+// setMemoryLimit registers the memory checks of the instrumented code in the runtime
+func setMemoryLimit(runtime *goja.Runtime, maxMemory int64) {
+	limit := &memoryLimit{
+		runtime:   runtime,
+		maxMemory: maxMemory,
+		baseline:  runtimeMemory(runtime),
+		next:      time.Now().Add(memoryCheckInterval),
+	}
+	_ = runtime.Set(checkMemoryFunction, limit.check)
+}
+
+// check measures the memory of the runtime at most every memoryCheckInterval
+func (m *memoryLimit) check(call goja.FunctionCall) goja.Value {
+	if time.Now().Before(m.next) {
+		return goja.Undefined()
+	}
+	if runtimeMemory(m.runtime)-m.baseline > m.maxMemory {
+		// the script is stopped at its next instruction once the check returns
+		m.runtime.Interrupt(ErrJSMemoryLimit)
+		return goja.Undefined()
+	}
+	// the interval starts after the walk to bound its overhead on large runtimes
+	m.next = time.Now().Add(memoryCheckInterval)
+	return goja.Undefined()
+}
+
+// runWithMemoryChecks runs the code calling checkMemoryFunction at the start
+// of the bodies of its loops and functions, the code of the required modules
+// is not instrumented. The syntax tree is instrumented to keep the positions
+// of the errors of the code.
+func runWithMemoryChecks(runtime *goja.Runtime, filename, code string) (goja.Value, error) {
+	program, err := parser.ParseFile(nil, filename, code, 0, parser.WithDisableSourceMaps)
+	if err != nil {
+		// the syntax errors are returned by the runtime as for the other scripts
+		return runtime.RunScript(filename, code)
+	}
+	walkNodes(reflect.ValueOf(program), make(map[ast.Node]struct{}), func(node ast.Node) {
+		switch node := node.(type) {
+		case *ast.ForStatement:
+			node.Body = withMemoryCheck(node.Body)
+		case *ast.ForInStatement:
+			node.Body = withMemoryCheck(node.Body)
+		case *ast.ForOfStatement:
+			node.Body = withMemoryCheck(node.Body)
+		case *ast.WhileStatement:
+			node.Body = withMemoryCheck(node.Body)
+		case *ast.DoWhileStatement:
+			node.Body = withMemoryCheck(node.Body)
+		case *ast.FunctionLiteral:
+			node.Body = withMemoryCheck(node.Body)
+		case *ast.ArrowFunctionLiteral:
+			// the expression bodies can only loop by calling functions
+			if body, ok := node.Body.(*ast.BlockStatement); ok {
+				node.Body = withMemoryCheck(body)
+			}
+		}
+	})
+	compiled, err := goja.CompileAST(program, false)
+	if err != nil {
+		return nil, err
+	}
+	return runtime.RunProgram(compiled)
+}
+
+// withMemoryCheck returns the body starting with a call of checkMemoryFunction,
+// the statements other than blocks are wrapped in a block
+func withMemoryCheck(body ast.Statement) *ast.BlockStatement {
+	check := &ast.ExpressionStatement{Expression: &ast.CallExpression{
+		Callee:           &ast.Identifier{Name: checkMemoryFunction, Idx: body.Idx0()},
+		LeftParenthesis:  body.Idx0(),
+		RightParenthesis: body.Idx0(),
+	}}
+	block, ok := body.(*ast.BlockStatement)
+	if !ok {
+		return &ast.BlockStatement{LeftBrace: body.Idx0(), List: []ast.Statement{check, body}, RightBrace: body.Idx1()}
+	}
+	// the check is inserted after the directives, ex: "use strict"
+	directives := 0
+	for _, statement := range block.List {
+		expression, ok := statement.(*ast.ExpressionStatement)
+		if !ok {
+			break
+		}
+		if _, ok := expression.Expression.(*ast.StringLiteral); !ok {
+			break
+		}
+		directives++
+	}
+	list := make([]ast.Statement, 0, len(block.List)+1)
+	list = append(list, block.List[:directives]...)
+	list = append(list, check)
+	block.List = append(list, block.List[directives:]...)
+	return block
+}
+
+// walkNodes calls visit once for each node of the syntax tree
+func walkNodes(value reflect.Value, visited map[ast.Node]struct{}, visit func(ast.Node)) {
+	switch value.Kind() {
+	case reflect.Interface:
+		if !value.IsNil() {
+			walkNodes(value.Elem(), visited, visit)
+		}
+	case reflect.Pointer:
+		if value.IsNil() {
+			return
+		}
+		if node, ok := value.Interface().(ast.Node); ok {
+			// the declarations are also referenced by the declaration lists
+			if _, ok := visited[node]; ok {
+				return
+			}
+			visited[node] = struct{}{}
+			visit(node)
+		}
+		walkNodes(value.Elem(), visited, visit)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				walkNodes(value.Field(i), visited, visit)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			walkNodes(value.Index(i), visited, visit)
+		}
+	}
+}
+
+// memoryReference is a reference to a value counted by runtimeMemory
+type memoryReference struct {
+	address uintptr
+	kind    reflect.Type
+}
+
+// memoryWalker sums the sizes of the values reachable from a runtime
+type memoryWalker struct {
+	pending []reflect.Value
+	seen    map[memoryReference]struct{}
+	size    int64
+}
+
+// runtimeMemory estimates the bytes of the values held by the runtime. Only
+// the goja types are followed, the go values exposed to the scripts are not
+// counted as they are not allocated by the script and can be shared with
+// other goroutines.
+func runtimeMemory(runtime *goja.Runtime) int64 {
+	walker := &memoryWalker{seen: make(map[memoryReference]struct{})}
+	walker.push(reflect.ValueOf(runtime))
+	for len(walker.pending) > 0 {
+		value := walker.pending[len(walker.pending)-1]
+		walker.pending = walker.pending[:len(walker.pending)-1]
+		walker.visit(value)
+	}
+	return walker.size
+}
+
+// push adds the value to the values to walk if it is a goja value or a string
+func (w *memoryWalker) push(value reflect.Value) {
+	if value.Kind() == reflect.String || isGojaType(value.Type()) {
+		w.pending = append(w.pending, value)
+	}
+}
+
+// mark returns true the first time a reference is seen
+func (w *memoryWalker) mark(address uintptr, kind reflect.Type) bool {
+	reference := memoryReference{address: address, kind: kind}
+	if _, ok := w.seen[reference]; ok {
+		return false
+	}
+	w.seen[reference] = struct{}{}
+	return true
+}
+
+func (w *memoryWalker) visit(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() || !w.mark(value.Pointer(), value.Type()) {
+			return
+		}
+		w.size += int64(value.Type().Elem().Size())
+		w.push(value.Elem())
+	case reflect.Interface:
+		if value.IsNil() {
+			return
+		}
+		elem := value.Elem()
+		if elem.Kind() != reflect.Pointer {
+			// the non pointer values are boxed in the interfaces
+			w.size += int64(elem.Type().Size())
+		}
+		w.push(elem)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			w.push(value.Field(i))
+		}
+	case reflect.Array:
+		if walksElements(value.Type().Elem()) {
+			for i := 0; i < value.Len(); i++ {
+				w.push(value.Index(i))
+			}
+		}
+	case reflect.Slice:
+		if value.IsNil() || !w.mark(value.Pointer(), value.Type()) {
+			return
+		}
+		w.size += int64(value.Cap()) * int64(value.Type().Elem().Size())
+		if walksElements(value.Type().Elem()) {
+			for i := 0; i < value.Len(); i++ {
+				w.push(value.Index(i))
+			}
+		}
+	case reflect.Map:
+		if value.IsNil() || !w.mark(value.Pointer(), value.Type()) {
+			return
+		}
+		w.size += int64(value.Len()) * int64(value.Type().Key().Size()+value.Type().Elem().Size())
+		if walksElements(value.Type().Key()) || walksElements(value.Type().Elem()) {
+			iter := value.MapRange()
+			for iter.Next() {
+				w.push(iter.Key())
+				w.push(iter.Value())
+			}
+		}
+	case reflect.String:
+		if value.Len() == 0 {
+			return
+		}
+		data := value.String()
+		if w.mark(uintptr(unsafe.Pointer(unsafe.StringData(data))), nil) {
+			w.size += int64(len(data))
+		}
+	}
+}
+
+// walksElements returns true if the elements of the type are walked
+func walksElements(elem reflect.Type) bool {
+	return elem.Kind() == reflect.String || isGojaType(elem)
+}
+
+// isGojaType returns true for the goja types and the pointers,
+// slices, arrays and maps of goja types
+func isGojaType(kind reflect.Type) bool {
+	if kind.Name() == "" {
+		switch kind.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			return isGojaType(kind.Elem())
+		case reflect.Map:
+			return isGojaType(kind.Key()) || isGojaType(kind.Elem())
+		}
+	}
+	pkgPath := kind.PkgPath()
+	return pkgPath == gojaPackage || strings.HasPrefix(pkgPath, gojaPackage+"/")
+}
@@ -0,0 +1,36 @@
+package amqp
+
+import (
+	lib_amqp "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/amqp"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/amqp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"AMQPClient":     func() lib_amqp.AMQPClient { return lib_amqp.AMQPClient{} },
+			"IsAMQPResponse": func() lib_amqp.IsAMQPResponse { return lib_amqp.IsAMQPResponse{} },
+			"Queue":          func() lib_amqp.Queue { return lib_amqp.Queue{} },
+
+			// Types (pointer type)
+			"NewAMQPClient":     func() *lib_amqp.AMQPClient { return &lib_amqp.AMQPClient{} },
+			"NewIsAMQPResponse": func() *lib_amqp.IsAMQPResponse { return &lib_amqp.IsAMQPResponse{} },
+			"NewQueue":          func() *lib_amqp.Queue { return &lib_amqp.Queue{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
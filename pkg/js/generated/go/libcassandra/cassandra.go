@@ -0,0 +1,34 @@
+package cassandra
+
+import (
+	lib_cassandra "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/cassandra"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/cassandra")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"CassandraClient":     func() lib_cassandra.CassandraClient { return lib_cassandra.CassandraClient{} },
+			"IsCassandraResponse": func() lib_cassandra.IsCassandraResponse { return lib_cassandra.IsCassandraResponse{} },
+
+			// Types (pointer type)
+			"NewCassandraClient":     func() *lib_cassandra.CassandraClient { return &lib_cassandra.CassandraClient{} },
+			"NewIsCassandraResponse": func() *lib_cassandra.IsCassandraResponse { return &lib_cassandra.IsCassandraResponse{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
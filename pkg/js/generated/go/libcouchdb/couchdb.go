@@ -0,0 +1,34 @@
+package couchdb
+
+import (
+	lib_couchdb "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/couchdb"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/couchdb")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"CouchDBClient":     func() lib_couchdb.CouchDBClient { return lib_couchdb.CouchDBClient{} },
+			"IsCouchDBResponse": func() lib_couchdb.IsCouchDBResponse { return lib_couchdb.IsCouchDBResponse{} },
+
+			// Types (pointer type)
+			"NewCouchDBClient":     func() *lib_couchdb.CouchDBClient { return &lib_couchdb.CouchDBClient{} },
+			"NewIsCouchDBResponse": func() *lib_couchdb.IsCouchDBResponse { return &lib_couchdb.IsCouchDBResponse{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,46 @@
+package docker
+
+import (
+	lib_docker "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/docker"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/docker")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"CRIClient":          func() lib_docker.CRIClient { return lib_docker.CRIClient{} },
+			"CRIContainer":       func() lib_docker.CRIContainer { return lib_docker.CRIContainer{} },
+			"CRIImage":           func() lib_docker.CRIImage { return lib_docker.CRIImage{} },
+			"CRIVersionResponse": func() lib_docker.CRIVersionResponse { return lib_docker.CRIVersionResponse{} },
+			"Container":          func() lib_docker.Container { return lib_docker.Container{} },
+			"DockerClient":       func() lib_docker.DockerClient { return lib_docker.DockerClient{} },
+			"Image":              func() lib_docker.Image { return lib_docker.Image{} },
+			"IsDockerResponse":   func() lib_docker.IsDockerResponse { return lib_docker.IsDockerResponse{} },
+
+			// Types (pointer type)
+			"NewCRIClient":          func() *lib_docker.CRIClient { return &lib_docker.CRIClient{} },
+			"NewCRIContainer":       func() *lib_docker.CRIContainer { return &lib_docker.CRIContainer{} },
+			"NewCRIImage":           func() *lib_docker.CRIImage { return &lib_docker.CRIImage{} },
+			"NewCRIVersionResponse": func() *lib_docker.CRIVersionResponse { return &lib_docker.CRIVersionResponse{} },
+			"NewContainer":          func() *lib_docker.Container { return &lib_docker.Container{} },
+			"NewDockerClient":       func() *lib_docker.DockerClient { return &lib_docker.DockerClient{} },
+			"NewImage":              func() *lib_docker.Image { return &lib_docker.Image{} },
+			"NewIsDockerResponse":   func() *lib_docker.IsDockerResponse { return &lib_docker.IsDockerResponse{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,34 @@
+package elasticsearch
+
+import (
+	lib_elasticsearch "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/elasticsearch"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/elasticsearch")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"ElasticsearchClient":     func() lib_elasticsearch.ElasticsearchClient { return lib_elasticsearch.ElasticsearchClient{} },
+			"IsElasticsearchResponse": func() lib_elasticsearch.IsElasticsearchResponse { return lib_elasticsearch.IsElasticsearchResponse{} },
+
+			// Types (pointer type)
+			"NewElasticsearchClient":     func() *lib_elasticsearch.ElasticsearchClient { return &lib_elasticsearch.ElasticsearchClient{} },
+			"NewIsElasticsearchResponse": func() *lib_elasticsearch.IsElasticsearchResponse { return &lib_elasticsearch.IsElasticsearchResponse{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
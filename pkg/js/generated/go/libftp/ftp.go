@@ -0,0 +1,34 @@
+package ftp
+
+import (
+	lib_ftp "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/ftp"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/ftp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"FTPClient":     func() lib_ftp.FTPClient { return lib_ftp.FTPClient{} },
+			"IsFTPResponse": func() lib_ftp.IsFTPResponse { return lib_ftp.IsFTPResponse{} },
+
+			// Types (pointer type)
+			"NewFTPClient":     func() *lib_ftp.FTPClient { return &lib_ftp.FTPClient{} },
+			"NewIsFTPResponse": func() *lib_ftp.IsFTPResponse { return &lib_ftp.IsFTPResponse{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	lib_grpc "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/grpc"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/grpc")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"GRPCClient": func() lib_grpc.GRPCClient { return lib_grpc.GRPCClient{} },
+			"Method":     func() lib_grpc.Method { return lib_grpc.Method{} },
+
+			// Types (pointer type)
+			"NewGRPCClient": func() *lib_grpc.GRPCClient { return &lib_grpc.GRPCClient{} },
+			"NewMethod":     func() *lib_grpc.Method { return &lib_grpc.Method{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,42 @@
+package kafka
+
+import (
+	lib_kafka "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/kafka"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/kafka")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"Broker":           func() lib_kafka.Broker { return lib_kafka.Broker{} },
+			"IsKafkaResponse":  func() lib_kafka.IsKafkaResponse { return lib_kafka.IsKafkaResponse{} },
+			"KafkaClient":      func() lib_kafka.KafkaClient { return lib_kafka.KafkaClient{} },
+			"MetadataResponse": func() lib_kafka.MetadataResponse { return lib_kafka.MetadataResponse{} },
+			"Partition":        func() lib_kafka.Partition { return lib_kafka.Partition{} },
+			"Topic":            func() lib_kafka.Topic { return lib_kafka.Topic{} },
+
+			// Types (pointer type)
+			"NewBroker":           func() *lib_kafka.Broker { return &lib_kafka.Broker{} },
+			"NewIsKafkaResponse":  func() *lib_kafka.IsKafkaResponse { return &lib_kafka.IsKafkaResponse{} },
+			"NewKafkaClient":      func() *lib_kafka.KafkaClient { return &lib_kafka.KafkaClient{} },
+			"NewMetadataResponse": func() *lib_kafka.MetadataResponse { return &lib_kafka.MetadataResponse{} },
+			"NewPartition":        func() *lib_kafka.Partition { return &lib_kafka.Partition{} },
+			"NewTopic":            func() *lib_kafka.Topic { return &lib_kafka.Topic{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
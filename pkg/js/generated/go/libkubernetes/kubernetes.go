@@ -0,0 +1,42 @@
+package kubernetes
+
+import (
+	lib_kubernetes "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/kubernetes"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/kubernetes")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"AccessResponse":   func() lib_kubernetes.AccessResponse { return lib_kubernetes.AccessResponse{} },
+			"KubernetesClient": func() lib_kubernetes.KubernetesClient { return lib_kubernetes.KubernetesClient{} },
+			"Pod":              func() lib_kubernetes.Pod { return lib_kubernetes.Pod{} },
+			"Secret":           func() lib_kubernetes.Secret { return lib_kubernetes.Secret{} },
+			"UserInfo":         func() lib_kubernetes.UserInfo { return lib_kubernetes.UserInfo{} },
+			"VersionResponse":  func() lib_kubernetes.VersionResponse { return lib_kubernetes.VersionResponse{} },
+
+			// Types (pointer type)
+			"NewAccessResponse":   func() *lib_kubernetes.AccessResponse { return &lib_kubernetes.AccessResponse{} },
+			"NewKubernetesClient": func() *lib_kubernetes.KubernetesClient { return &lib_kubernetes.KubernetesClient{} },
+			"NewPod":              func() *lib_kubernetes.Pod { return &lib_kubernetes.Pod{} },
+			"NewSecret":           func() *lib_kubernetes.Secret { return &lib_kubernetes.Secret{} },
+			"NewUserInfo":         func() *lib_kubernetes.UserInfo { return &lib_kubernetes.UserInfo{} },
+			"NewVersionResponse":  func() *lib_kubernetes.VersionResponse { return &lib_kubernetes.VersionResponse{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -19,10 +19,12 @@ func init() {
 			// Var and consts
 
 			// Types (value type)
+			"Entry":        func() lib_ldap.Entry { return lib_ldap.Entry{} },
 			"LDAPMetadata": func() lib_ldap.LDAPMetadata { return lib_ldap.LDAPMetadata{} },
 			"LdapClient":   func() lib_ldap.LdapClient { return lib_ldap.LdapClient{} },
 
 			// Types (pointer type)
+			"NewEntry":        func() *lib_ldap.Entry { return &lib_ldap.Entry{} },
 			"NewLDAPMetadata": func() *lib_ldap.LDAPMetadata { return &lib_ldap.LDAPMetadata{} },
 			"NewLdapClient":   func() *lib_ldap.LdapClient { return &lib_ldap.LdapClient{} },
 		},
@@ -0,0 +1,34 @@
+package memcached
+
+import (
+	lib_memcached "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/memcached"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/memcached")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"AmplificationResponse": func() lib_memcached.AmplificationResponse { return lib_memcached.AmplificationResponse{} },
+			"MemcachedClient":       func() lib_memcached.MemcachedClient { return lib_memcached.MemcachedClient{} },
+
+			// Types (pointer type)
+			"NewAmplificationResponse": func() *lib_memcached.AmplificationResponse { return &lib_memcached.AmplificationResponse{} },
+			"NewMemcachedClient":       func() *lib_memcached.MemcachedClient { return &lib_memcached.MemcachedClient{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
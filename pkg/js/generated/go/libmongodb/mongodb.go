@@ -0,0 +1,32 @@
+package mongodb
+
+import (
+	lib_mongodb "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/mongodb"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/mongodb")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"MongoDBClient": func() lib_mongodb.MongoDBClient { return lib_mongodb.MongoDBClient{} },
+
+			// Types (pointer type)
+			"NewMongoDBClient": func() *lib_mongodb.MongoDBClient { return &lib_mongodb.MongoDBClient{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
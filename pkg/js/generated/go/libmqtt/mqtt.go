@@ -0,0 +1,36 @@
+package mqtt
+
+import (
+	lib_mqtt "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/mqtt"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/mqtt")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"IsMQTTResponse": func() lib_mqtt.IsMQTTResponse { return lib_mqtt.IsMQTTResponse{} },
+			"MQTTClient":     func() lib_mqtt.MQTTClient { return lib_mqtt.MQTTClient{} },
+			"Message":        func() lib_mqtt.Message { return lib_mqtt.Message{} },
+
+			// Types (pointer type)
+			"NewIsMQTTResponse": func() *lib_mqtt.IsMQTTResponse { return &lib_mqtt.IsMQTTResponse{} },
+			"NewMQTTClient":     func() *lib_mqtt.MQTTClient { return &lib_mqtt.MQTTClient{} },
+			"NewMessage":        func() *lib_mqtt.Message { return &lib_mqtt.Message{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
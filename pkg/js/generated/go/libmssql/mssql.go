@@ -19,9 +19,11 @@ func init() {
 			// Var and consts
 
 			// Types (value type)
+			"Instance":    func() lib_mssql.Instance { return lib_mssql.Instance{} },
 			"MSSQLClient": func() lib_mssql.MSSQLClient { return lib_mssql.MSSQLClient{} },
 
 			// Types (pointer type)
+			"NewInstance":    func() *lib_mssql.Instance { return &lib_mssql.Instance{} },
 			"NewMSSQLClient": func() *lib_mssql.MSSQLClient { return &lib_mssql.MSSQLClient{} },
 		},
 	).Register()
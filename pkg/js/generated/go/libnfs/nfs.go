@@ -0,0 +1,36 @@
+package nfs
+
+import (
+	lib_nfs "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/nfs"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/nfs")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"Export":       func() lib_nfs.Export { return lib_nfs.Export{} },
+			"NFSClient":    func() lib_nfs.NFSClient { return lib_nfs.NFSClient{} },
+			"ReadResponse": func() lib_nfs.ReadResponse { return lib_nfs.ReadResponse{} },
+
+			// Types (pointer type)
+			"NewExport":       func() *lib_nfs.Export { return &lib_nfs.Export{} },
+			"NewNFSClient":    func() *lib_nfs.NFSClient { return &lib_nfs.NFSClient{} },
+			"NewReadResponse": func() *lib_nfs.ReadResponse { return &lib_nfs.ReadResponse{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -21,10 +21,12 @@ func init() {
 			// Types (value type)
 			"IsOracleResponse": func() lib_oracle.IsOracleResponse { return lib_oracle.IsOracleResponse{} },
 			"OracleClient":     func() lib_oracle.OracleClient { return lib_oracle.OracleClient{} },
+			"TNSVersion":       func() lib_oracle.TNSVersion { return lib_oracle.TNSVersion{} },
 
 			// Types (pointer type)
 			"NewIsOracleResponse": func() *lib_oracle.IsOracleResponse { return &lib_oracle.IsOracleResponse{} },
 			"NewOracleClient":     func() *lib_oracle.OracleClient { return &lib_oracle.OracleClient{} },
+			"NewTNSVersion":       func() *lib_oracle.TNSVersion { return &lib_oracle.TNSVersion{} },
 		},
 	).Register()
 }
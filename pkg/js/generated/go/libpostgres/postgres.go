@@ -15,14 +15,20 @@ func init() {
 	module.Set(
 		gojs.Objects{
 			// Functions
+			"QuoteIdentifier": lib_postgres.QuoteIdentifier,
+			"QuoteLiteral":    lib_postgres.QuoteLiteral,
 
 			// Var and consts
 
 			// Types (value type)
-			"PGClient": func() lib_postgres.PGClient { return lib_postgres.PGClient{} },
+			"BruteForceResult": func() lib_postgres.BruteForceResult { return lib_postgres.BruteForceResult{} },
+			"Credential":       func() lib_postgres.Credential { return lib_postgres.Credential{} },
+			"PGClient":         func() lib_postgres.PGClient { return lib_postgres.PGClient{} },
 
 			// Types (pointer type)
-			"NewPGClient": func() *lib_postgres.PGClient { return &lib_postgres.PGClient{} },
+			"NewBruteForceResult": func() *lib_postgres.BruteForceResult { return &lib_postgres.BruteForceResult{} },
+			"NewCredential":       func() *lib_postgres.Credential { return &lib_postgres.Credential{} },
+			"NewPGClient":         func() *lib_postgres.PGClient { return &lib_postgres.PGClient{} },
 		},
 	).Register()
 }
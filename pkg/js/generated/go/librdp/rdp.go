@@ -21,12 +21,16 @@ func init() {
 			// Types (value type)
 			"CheckRDPAuthResponse": func() lib_rdp.CheckRDPAuthResponse { return lib_rdp.CheckRDPAuthResponse{} },
 			"IsRDPResponse":        func() lib_rdp.IsRDPResponse { return lib_rdp.IsRDPResponse{} },
+			"NTLMInfo":             func() lib_rdp.NTLMInfo { return lib_rdp.NTLMInfo{} },
 			"RDPClient":            func() lib_rdp.RDPClient { return lib_rdp.RDPClient{} },
+			"SecurityLayer":        func() lib_rdp.SecurityLayer { return lib_rdp.SecurityLayer{} },
 
 			// Types (pointer type)
 			"NewCheckRDPAuthResponse": func() *lib_rdp.CheckRDPAuthResponse { return &lib_rdp.CheckRDPAuthResponse{} },
 			"NewIsRDPResponse":        func() *lib_rdp.IsRDPResponse { return &lib_rdp.IsRDPResponse{} },
+			"NewNTLMInfo":             func() *lib_rdp.NTLMInfo { return &lib_rdp.NTLMInfo{} },
 			"NewRDPClient":            func() *lib_rdp.RDPClient { return &lib_rdp.RDPClient{} },
+			"NewSecurityLayer":        func() *lib_rdp.SecurityLayer { return &lib_rdp.SecurityLayer{} },
 		},
 	).Register()
 }
@@ -24,8 +24,10 @@ func init() {
 			// Var and consts
 
 			// Types (value type)
+			"RedisClient": func() lib_redis.RedisClient { return lib_redis.RedisClient{} },
 
 			// Types (pointer type)
+			"NewRedisClient": func() *lib_redis.RedisClient { return &lib_redis.RedisClient{} },
 		},
 	).Register()
 }
@@ -19,10 +19,14 @@ func init() {
 			// Var and consts
 
 			// Types (value type)
+			"File":      func() lib_smb.File { return lib_smb.File{} },
 			"SMBClient": func() lib_smb.SMBClient { return lib_smb.SMBClient{} },
+			"Share":     func() lib_smb.Share { return lib_smb.Share{} },
 
 			// Types (pointer type)
+			"NewFile":      func() *lib_smb.File { return &lib_smb.File{} },
 			"NewSMBClient": func() *lib_smb.SMBClient { return &lib_smb.SMBClient{} },
+			"NewShare":     func() *lib_smb.Share { return &lib_smb.Share{} },
 		},
 	).Register()
 }
@@ -19,10 +19,14 @@ func init() {
 			// Var and consts
 
 			// Types (value type)
-			"SSHClient": func() lib_ssh.SSHClient { return lib_ssh.SSHClient{} },
+			"Algorithms": func() lib_ssh.Algorithms { return lib_ssh.Algorithms{} },
+			"HostKey":    func() lib_ssh.HostKey { return lib_ssh.HostKey{} },
+			"SSHClient":  func() lib_ssh.SSHClient { return lib_ssh.SSHClient{} },
 
 			// Types (pointer type)
-			"NewSSHClient": func() *lib_ssh.SSHClient { return &lib_ssh.SSHClient{} },
+			"NewAlgorithms": func() *lib_ssh.Algorithms { return &lib_ssh.Algorithms{} },
+			"NewHostKey":    func() *lib_ssh.HostKey { return &lib_ssh.HostKey{} },
+			"NewSSHClient":  func() *lib_ssh.SSHClient { return &lib_ssh.SSHClient{} },
 		},
 	).Register()
 }
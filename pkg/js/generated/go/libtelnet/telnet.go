@@ -20,10 +20,12 @@ func init() {
 
 			// Types (value type)
 			"IsTelnetResponse": func() lib_telnet.IsTelnetResponse { return lib_telnet.IsTelnetResponse{} },
+			"LoginResponse":    func() lib_telnet.LoginResponse { return lib_telnet.LoginResponse{} },
 			"TelnetClient":     func() lib_telnet.TelnetClient { return lib_telnet.TelnetClient{} },
 
 			// Types (pointer type)
 			"NewIsTelnetResponse": func() *lib_telnet.IsTelnetResponse { return &lib_telnet.IsTelnetResponse{} },
+			"NewLoginResponse":    func() *lib_telnet.LoginResponse { return &lib_telnet.LoginResponse{} },
 			"NewTelnetClient":     func() *lib_telnet.TelnetClient { return &lib_telnet.TelnetClient{} },
 		},
 	).Register()
@@ -0,0 +1,38 @@
+package tls
+
+import (
+	lib_tls "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/tls"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/tls")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"Certificate":     func() lib_tls.Certificate { return lib_tls.Certificate{} },
+			"ConnectionState": func() lib_tls.ConnectionState { return lib_tls.ConnectionState{} },
+			"TLSClient":       func() lib_tls.TLSClient { return lib_tls.TLSClient{} },
+			"TLSConn":         func() lib_tls.TLSConn { return lib_tls.TLSConn{} },
+
+			// Types (pointer type)
+			"NewCertificate":     func() *lib_tls.Certificate { return &lib_tls.Certificate{} },
+			"NewConnectionState": func() *lib_tls.ConnectionState { return &lib_tls.ConnectionState{} },
+			"NewTLSClient":       func() *lib_tls.TLSClient { return &lib_tls.TLSClient{} },
+			"NewTLSConn":         func() *lib_tls.TLSConn { return &lib_tls.TLSConn{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -19,12 +19,16 @@ func init() {
 			// Var and consts
 
 			// Types (value type)
-			"IsVNCResponse": func() lib_vnc.IsVNCResponse { return lib_vnc.IsVNCResponse{} },
-			"VNCClient":     func() lib_vnc.VNCClient { return lib_vnc.VNCClient{} },
+			"IsVNCResponse":         func() lib_vnc.IsVNCResponse { return lib_vnc.IsVNCResponse{} },
+			"ScreenshotResponse":    func() lib_vnc.ScreenshotResponse { return lib_vnc.ScreenshotResponse{} },
+			"SecurityTypesResponse": func() lib_vnc.SecurityTypesResponse { return lib_vnc.SecurityTypesResponse{} },
+			"VNCClient":             func() lib_vnc.VNCClient { return lib_vnc.VNCClient{} },
 
 			// Types (pointer type)
-			"NewIsVNCResponse": func() *lib_vnc.IsVNCResponse { return &lib_vnc.IsVNCResponse{} },
-			"NewVNCClient":     func() *lib_vnc.VNCClient { return &lib_vnc.VNCClient{} },
+			"NewIsVNCResponse":         func() *lib_vnc.IsVNCResponse { return &lib_vnc.IsVNCResponse{} },
+			"NewScreenshotResponse":    func() *lib_vnc.ScreenshotResponse { return &lib_vnc.ScreenshotResponse{} },
+			"NewSecurityTypesResponse": func() *lib_vnc.SecurityTypesResponse { return &lib_vnc.SecurityTypesResponse{} },
+			"NewVNCClient":             func() *lib_vnc.VNCClient { return &lib_vnc.VNCClient{} },
 		},
 	).Register()
 }
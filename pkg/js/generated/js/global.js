@@ -61,6 +61,44 @@ function isPortOpen(host, port, timeout = 5) {
     // implemented in go
 };
 
+/**
+ * @function
+ * @description kvSet stores the value of the key for the other requests and templates of the scan. ttl in seconds is optional and defaults to the end of the scan
+ * @param {string} key - The key to store.
+ * @param {string} value - The value of the key.
+ * @param {number} [ttl=0] - The ttl of the value in seconds, 0 keeps the value until the end of the scan.
+ * @throws {error} - The error encountered if the value or the number of keys exceeds the limits.
+ * @example
+ * kvSet("token", token); // the token is available to the other templates of the scan
+ * kvSet("session", session, 300); // the session expires after 5 minutes
+ */
+function kvSet(key, value, ttl = 0) {
+    // implemented in go
+};
+
+/**
+ * @function
+ * @description kvGet returns the value of the key stored in the scan or null if it does not exist or is expired
+ * @param {string} key - The key to get.
+ * @returns {string|null} - The value of the key, null if it does not exist or is expired.
+ * @example
+ * let token = kvGet("token");
+ */
+function kvGet(key) {
+    // implemented in go
+};
+
+/**
+ * @function
+ * @description kvDelete removes the key stored in the scan
+ * @param {string} key - The key to remove.
+ * @example
+ * kvDelete("token");
+ */
+function kvDelete(key) {
+    // implemented in go
+};
+
 /**
  * @function
  * @description ToBytes converts given input to byte slice
@@ -0,0 +1,102 @@
+/** @module amqp */
+
+/**
+ * @class
+ * @classdesc AMQPClient is a client for AMQP 0-9-1 brokers like RabbitMQ. The connections are authenticated with the PLAIN mechanism and use the AMQP protocol instead of the management API, the broker certificate of the TLS connections is not verified.
+ * @property {bool} TLS - TLS enables TLS connections.
+ * @property {string} VHost - The virtual host of the connections, / if empty.
+ * @example
+ * let m = require('nuclei/amqp');
+ * let c = m.AMQPClient();
+ * c.VHost = '/';
+ * let isConnected = c.Connect('localhost', 5672, 'guest', 'guest');
+ */
+class AMQPClient {
+    /**
+    * @method
+    * @description IsAMQP checks if the given host and port are running AMQP broker, the product and version are read from the properties of the broker.
+    * @param {string} host - The host of the AMQP broker.
+    * @param {int} port - The port of the AMQP broker.
+    * @returns {IsAMQPResponse} - The response of the check.
+    * @throws {error} - If the connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/amqp');
+    * let c = m.AMQPClient();
+    * let response = c.IsAMQP('localhost', 5672);
+    */
+    IsAMQP(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Connect connects to AMQP broker using given credentials and opens the virtual host.
+    * @param {string} host - The host of the AMQP broker.
+    * @param {int} port - The port of the AMQP broker.
+    * @param {string} username - The username to connect with.
+    * @param {string} password - The password to connect with.
+    * @returns {bool} - If connection is successful, it returns true and false if the credentials are refused.
+    * @throws {error} - If connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/amqp');
+    * let c = m.AMQPClient();
+    * let isConnected = c.Connect('localhost', 5672, 'guest', 'guest');
+    */
+    Connect(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListVHosts returns the virtual hosts of the list the user can open, the missing ones and the ones the user is not allowed to access are skipped.
+    * @param {string} host - The host of the AMQP broker.
+    * @param {int} port - The port of the AMQP broker.
+    * @param {string} username - The username to connect with.
+    * @param {string} password - The password to connect with.
+    * @param {string[]} vhosts - The virtual hosts to probe.
+    * @returns {string[]} - The virtual hosts the user can open.
+    * @throws {error} - If connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/amqp');
+    * let c = m.AMQPClient();
+    * let vhosts = c.ListVHosts('localhost', 5672, 'guest', 'guest', ['/', 'prod', 'staging']);
+    */
+    ListVHosts(host, port, username, password, vhosts) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListQueues returns the queues of the list existing in the virtual host, the queues are declared passively so no queue is created.
+    * @param {string} host - The host of the AMQP broker.
+    * @param {int} port - The port of the AMQP broker.
+    * @param {string} username - The username to connect with.
+    * @param {string} password - The password to connect with.
+    * @param {string[]} queues - The queues to probe.
+    * @returns {Queue[]} - The existing queues.
+    * @throws {error} - If connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/amqp');
+    * let c = m.AMQPClient();
+    * let queues = c.ListQueues('localhost', 5672, 'guest', 'guest', ['orders', 'events']);
+    */
+    ListQueues(host, port, username, password, queues) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} IsAMQPResponse
+ * @description IsAMQPResponse is an object containing the IsAMQP flag, the Product, Version, Platform and ClusterName of the broker and its SASL Mechanisms.
+ */
+const IsAMQPResponse = {};
+
+/**
+ * @typedef {object} Queue
+ * @description Queue is an object containing the Name of a queue with its number of Messages and Consumers.
+ */
+const Queue = {};
+
+module.exports = {
+    AMQPClient: AMQPClient,
+};
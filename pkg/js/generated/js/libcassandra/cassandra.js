@@ -0,0 +1,71 @@
+/** @module cassandra */
+
+/**
+ * @class
+ * @classdesc CassandraClient is a client for Cassandra and ScyllaDB databases. Internally client implements the CQL native protocol v4 with the password authenticator.
+ */
+class CassandraClient {
+    /**
+    * @method
+    * @description Connect connects to Cassandra database using given credentials. The connection is successful with any credentials if authentication is disabled.
+    * @param {string} host - The host of the Cassandra database.
+    * @param {int} port - The port of the Cassandra database.
+    * @param {string} username - The username to connect to the Cassandra database.
+    * @param {string} password - The password to connect to the Cassandra database.
+    * @returns {bool} - If connection is successful, it returns true.
+    * @throws {error} - If connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/cassandra');
+    * let c = m.CassandraClient();
+    * let isConnected = c.Connect('localhost', 9042, 'cassandra', 'cassandra');
+    */
+    Connect(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ExecuteQuery connects to Cassandra database using given credentials and keyspace and executes a CQL query, returning the rows as json. The keyspace is optional if the tables of the query are qualified, only the first 5000 rows are returned.
+    * @param {string} host - The host of the Cassandra database.
+    * @param {int} port - The port of the Cassandra database.
+    * @param {string} username - The username to connect to the Cassandra database.
+    * @param {string} password - The password to connect to the Cassandra database.
+    * @param {string} keyspace - The keyspace of the query.
+    * @param {string} query - The CQL query to execute.
+    * @returns {string} - The rows of the query as json.
+    * @throws {error} - If query execution is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/cassandra');
+    * let c = m.CassandraClient();
+    * let result = c.ExecuteQuery('localhost', 9042, 'cassandra', 'cassandra', 'system_auth', 'SELECT role, is_superuser FROM roles');
+    */
+    ExecuteQuery(host, port, username, password, keyspace, query) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsCassandra checks if the given host and port are running Cassandra database.
+    * @param {string} host - The host to check.
+    * @param {int} port - The port to check.
+    * @returns {IsCassandraResponse} - The response from the Cassandra server.
+    * @throws {error} - If the check is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/cassandra');
+    * let c = m.CassandraClient();
+    * let response = c.IsCassandra('localhost', 9042);
+    */
+    IsCassandra(host, port) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} IsCassandraResponse
+ * @description IsCassandraResponse is an object containing the response from the Cassandra server with the IsCassandra flag, the Product (Cassandra or ScyllaDB), the supported CQLVersions and the Authenticator class, empty if authentication is disabled.
+ */
+const IsCassandraResponse = {};
+
+module.exports = {
+    CassandraClient: CassandraClient,
+};
@@ -0,0 +1,92 @@
+/** @module couchdb */
+
+/**
+ * @class
+ * @classdesc CouchDBClient is a client for CouchDB servers. Requests are authenticated with basic auth if the username is set and are unauthenticated otherwise, the server certificate of the HTTPS connections is not verified.
+ * @property {bool} HTTPS - HTTPS enables TLS connections.
+ * @property {string} Username - The username of the basic authentication.
+ * @property {string} Password - The password of the basic authentication.
+ * @example
+ * let m = require('nuclei/couchdb');
+ * let c = m.CouchDBClient();
+ * c.Username = 'admin';
+ * c.Password = 'password';
+ * let databases = c.ListDatabases('localhost', 5984);
+ */
+class CouchDBClient {
+    /**
+    * @method
+    * @description IsCouchDB checks if the given host and port are running CouchDB, the welcome message of the root endpoint is used.
+    * @param {string} host - The host of the CouchDB server.
+    * @param {int} port - The port of the CouchDB server.
+    * @returns {IsCouchDBResponse} - The response of the check.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/couchdb');
+    * let c = m.CouchDBClient();
+    * let response = c.IsCouchDB('localhost', 5984);
+    */
+    IsCouchDB(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsAdminParty checks if the server grants the admin role to unauthenticated users, the session is requested without the credentials of the client.
+    * @param {string} host - The host of the CouchDB server.
+    * @param {int} port - The port of the CouchDB server.
+    * @returns {bool} - If the server runs in admin party, it returns true.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/couchdb');
+    * let c = m.CouchDBClient();
+    * let isAdminParty = c.IsAdminParty('localhost', 5984);
+    */
+    IsAdminParty(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListDatabases returns the names of the databases of the server, listing the databases requires the admin role since CouchDB 3.
+    * @param {string} host - The host of the CouchDB server.
+    * @param {int} port - The port of the CouchDB server.
+    * @returns {string[]} - The names of the databases.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/couchdb');
+    * let c = m.CouchDBClient();
+    * let databases = c.ListDatabases('localhost', 5984);
+    */
+    ListDatabases(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description SampleDocuments returns at most limit documents of the database as the json response of the _all_docs endpoint, 10 documents if not positive and at most 1000.
+    * @param {string} host - The host of the CouchDB server.
+    * @param {int} port - The port of the CouchDB server.
+    * @param {string} database - The database to sample.
+    * @param {int} limit - The maximum number of documents.
+    * @returns {string} - The documents as json.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/couchdb');
+    * let c = m.CouchDBClient();
+    * let documents = JSON.parse(c.SampleDocuments('localhost', 5984, '_users', 5));
+    */
+    SampleDocuments(host, port, database, limit) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} IsCouchDBResponse
+ * @description IsCouchDBResponse is an object containing the IsCouchDB flag and the Version and Vendor of the server.
+ */
+const IsCouchDBResponse = {};
+
+module.exports = {
+    CouchDBClient: CouchDBClient,
+};
@@ -0,0 +1,177 @@
+/** @module docker */
+
+/**
+ * @class
+ * @classdesc DockerClient is a client for the Engine API of Docker daemons. The daemons listening on tcp do not authenticate the requests, the TLS ones may require a client certificate signed by their CA and their certificate is not verified.
+ * @property {bool} HTTPS - HTTPS enables TLS connections.
+ * @property {string} Certificate - The PEM encoded client certificate of the TLS connections.
+ * @property {string} Key - The PEM encoded private key of the client certificate.
+ * @example
+ * let m = require('nuclei/docker');
+ * let c = m.DockerClient();
+ * let containers = c.ListContainers('localhost', 2375);
+ */
+class DockerClient {
+    /**
+    * @method
+    * @description IsDocker checks if the given host and port are running Docker daemon, the version endpoint is used.
+    * @param {string} host - The host of the Docker daemon.
+    * @param {int} port - The port of the Docker daemon.
+    * @returns {IsDockerResponse} - The response of the check.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/docker');
+    * let c = m.DockerClient();
+    * let response = c.IsDocker('localhost', 2375);
+    */
+    IsDocker(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsUnauthenticated checks if the daemon can be controlled without client certificate, the system information is requested without the certificate of the client.
+    * @param {string} host - The host of the Docker daemon.
+    * @param {int} port - The port of the Docker daemon.
+    * @returns {bool} - If the daemon accepts the request, it returns true and false if the daemon or its authorization plugins refuse it.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/docker');
+    * let c = m.DockerClient();
+    * let isUnauthenticated = c.IsUnauthenticated('localhost', 2375);
+    */
+    IsUnauthenticated(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListContainers returns the containers of the daemon, the stopped ones included.
+    * @param {string} host - The host of the Docker daemon.
+    * @param {int} port - The port of the Docker daemon.
+    * @returns {Container[]} - The containers of the daemon.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/docker');
+    * let c = m.DockerClient();
+    * let containers = c.ListContainers('localhost', 2375);
+    */
+    ListContainers(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListImages returns the images of the daemon.
+    * @param {string} host - The host of the Docker daemon.
+    * @param {int} port - The port of the Docker daemon.
+    * @returns {Image[]} - The images of the daemon.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/docker');
+    * let c = m.DockerClient();
+    * let images = c.ListImages('localhost', 2375);
+    */
+    ListImages(host, port) {
+        // implemented in go
+    };
+};
+
+/**
+ * @class
+ * @classdesc CRIClient is a client for the CRI api of container runtimes like containerd and CRI-O. The runtimes listening on tcp do not authenticate the requests, the gRPC requests are sent over plain text HTTP/2.
+ * @example
+ * let m = require('nuclei/docker');
+ * let c = m.CRIClient();
+ * let version = c.Version('localhost', 10010);
+ */
+class CRIClient {
+    /**
+    * @method
+    * @description Version returns the name and version of the runtime.
+    * @param {string} host - The host of the runtime.
+    * @param {int} port - The port of the runtime.
+    * @returns {CRIVersionResponse} - The version of the runtime.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/docker');
+    * let c = m.CRIClient();
+    * let version = c.Version('localhost', 10010);
+    */
+    Version(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListContainers returns the containers of the runtime.
+    * @param {string} host - The host of the runtime.
+    * @param {int} port - The port of the runtime.
+    * @returns {CRIContainer[]} - The containers of the runtime.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/docker');
+    * let c = m.CRIClient();
+    * let containers = c.ListContainers('localhost', 10010);
+    */
+    ListContainers(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListImages returns the images of the runtime.
+    * @param {string} host - The host of the runtime.
+    * @param {int} port - The port of the runtime.
+    * @returns {CRIImage[]} - The images of the runtime.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/docker');
+    * let c = m.CRIClient();
+    * let images = c.ListImages('localhost', 10010);
+    */
+    ListImages(host, port) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} IsDockerResponse
+ * @description IsDockerResponse is an object containing the IsDocker flag and the Version, APIVersion, OS, Arch and KernelVersion of the daemon.
+ */
+const IsDockerResponse = {};
+
+/**
+ * @typedef {object} Container
+ * @description Container is an object containing the ID, the Names, the Image, the State and the Status of a container.
+ */
+const Container = {};
+
+/**
+ * @typedef {object} Image
+ * @description Image is an object containing the ID, the RepoTags and the Size of an image.
+ */
+const Image = {};
+
+/**
+ * @typedef {object} CRIVersionResponse
+ * @description CRIVersionResponse is an object containing the Version of the CRI api and the RuntimeName, RuntimeVersion and RuntimeAPIVersion of the runtime.
+ */
+const CRIVersionResponse = {};
+
+/**
+ * @typedef {object} CRIContainer
+ * @description CRIContainer is an object containing the ID, the Name, the Image and the State of a container, either created, running, exited or unknown.
+ */
+const CRIContainer = {};
+
+/**
+ * @typedef {object} CRIImage
+ * @description CRIImage is an object containing the ID, the RepoTags and the Size of an image.
+ */
+const CRIImage = {};
+
+module.exports = {
+    DockerClient: DockerClient,
+    CRIClient: CRIClient,
+};
@@ -0,0 +1,94 @@
+/** @module elasticsearch */
+
+/**
+ * @class
+ * @classdesc ElasticsearchClient is a client for Elasticsearch and OpenSearch clusters. Requests are authenticated with the api key if set, with basic auth if the username is set and are unauthenticated otherwise.
+ * @property {bool} HTTPS - Enables TLS connections, the server certificate is not verified.
+ * @property {string} Username - The username of the basic authentication.
+ * @property {string} Password - The password of the basic authentication.
+ * @property {string} APIKey - The base64 encoded api key sent in the ApiKey authorization header.
+ * @example
+ * let m = require('nuclei/elasticsearch');
+ * let c = m.ElasticsearchClient();
+ * c.HTTPS = true;
+ * c.Username = 'elastic';
+ * c.Password = 'changeme';
+ * let health = c.ClusterHealth('localhost', 9200);
+ */
+class ElasticsearchClient {
+    /**
+    * @method
+    * @description ClusterHealth returns the health of the cluster as json.
+    * @param {string} host - The host of the cluster.
+    * @param {int} port - The port of the cluster.
+    * @returns {string} - The json health of the cluster.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let health = c.ClusterHealth('localhost', 9200);
+    */
+    ClusterHealth(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsElasticsearch checks if the given host and port are running Elasticsearch or OpenSearch. The root endpoint is used, a cluster requiring authentication is only detected with valid credentials.
+    * @param {string} host - The host to check.
+    * @param {int} port - The port to check.
+    * @returns {IsElasticsearchResponse} - The response from the cluster.
+    * @throws {error} - If the check is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let response = c.IsElasticsearch('localhost', 9200);
+    */
+    IsElasticsearch(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListIndices returns the names of the indices of the cluster.
+    * @param {string} host - The host of the cluster.
+    * @param {int} port - The port of the cluster.
+    * @returns {string[]} - The names of the indices.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let indices = c.ListIndices('localhost', 9200);
+    */
+    ListIndices(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Search executes a search query on the index and returns the response as json. The query is the json body of the search request, all the documents are matched if empty. The index can be a comma separated list or a pattern.
+    * @param {string} host - The host of the cluster.
+    * @param {int} port - The port of the cluster.
+    * @param {string} index - The index to search.
+    * @param {string} query - The json body of the search request.
+    * @returns {string} - The json response of the search.
+    * @throws {error} - If the search is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let result = c.Search('localhost', 9200, 'users', JSON.stringify({size: 5}));
+    */
+    Search(host, port, index, query) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} IsElasticsearchResponse
+ * @description IsElasticsearchResponse is an object containing the response from the cluster with the IsElasticsearch flag, the Product (Elasticsearch or OpenSearch), the Version and the ClusterName.
+ */
+const IsElasticsearchResponse = {};
+
+module.exports = {
+    ElasticsearchClient: ElasticsearchClient,
+};
@@ -0,0 +1,92 @@
+/** @module ftp */
+
+/**
+ * @class
+ * @classdesc FTPClient is a client for FTP servers. The connections are in plain text by default, the explicit TLS mode upgrades them with AUTH TLS and the implicit one starts them with TLS, the server certificate is not verified.
+ * @property {string} TLS - The TLS mode of the connections, either explicit or implicit, plain text if empty.
+ * @example
+ * let m = require('nuclei/ftp');
+ * let c = m.FTPClient();
+ * c.TLS = 'explicit';
+ * let isAnonymous = c.IsAnonymous('localhost', 21);
+ */
+class FTPClient {
+    /**
+    * @method
+    * @description Connect connects to FTP server using given credentials.
+    * @param {string} host - The host of the FTP server.
+    * @param {int} port - The port of the FTP server.
+    * @param {string} username - The username to connect to the FTP server.
+    * @param {string} password - The password to connect to the FTP server.
+    * @returns {bool} - If connection is successful, it returns true.
+    * @throws {error} - If connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/ftp');
+    * let c = m.FTPClient();
+    * let isConnected = c.Connect('localhost', 21, 'admin', 'admin');
+    */
+    Connect(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsAnonymous checks if the server accepts the anonymous login.
+    * @param {string} host - The host of the FTP server.
+    * @param {int} port - The port of the FTP server.
+    * @returns {bool} - If the anonymous login is accepted, it returns true.
+    * @throws {error} - If the check is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/ftp');
+    * let c = m.FTPClient();
+    * let isAnonymous = c.IsAnonymous('localhost', 21);
+    */
+    IsAnonymous(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsFTP checks if the given host and port are running FTP server.
+    * @param {string} host - The host to check.
+    * @param {int} port - The port to check.
+    * @returns {IsFTPResponse} - The response from the FTP server.
+    * @throws {error} - If the check is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/ftp');
+    * let c = m.FTPClient();
+    * let response = c.IsFTP('localhost', 21);
+    */
+    IsFTP(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description List connects to FTP server using given credentials and returns the lines of the listing of the path, the current directory if empty. The listing is in the format of the server, usually as ls -l.
+    * @param {string} host - The host of the FTP server.
+    * @param {int} port - The port of the FTP server.
+    * @param {string} username - The username to connect to the FTP server.
+    * @param {string} password - The password to connect to the FTP server.
+    * @param {string} path - The path to list.
+    * @returns {string[]} - The lines of the listing.
+    * @throws {error} - If the listing is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/ftp');
+    * let c = m.FTPClient();
+    * let lines = c.List('localhost', 21, 'anonymous', 'anonymous@', '/pub');
+    */
+    List(host, port, username, password, path) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} IsFTPResponse
+ * @description IsFTPResponse is an object containing the response from the FTP server with the IsFTP flag and the Banner of the server.
+ */
+const IsFTPResponse = {};
+
+module.exports = {
+    FTPClient: FTPClient,
+};
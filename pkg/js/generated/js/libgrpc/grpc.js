@@ -0,0 +1,76 @@
+/** @module grpc */
+
+/**
+ * @class
+ * @classdesc GRPCClient is a client for gRPC servers. Internally client uses the server reflection to discover the services of the server and the types of their messages, the server certificate of the TLS connections is not verified.
+ * @property {bool} TLS - TLS enables TLS connections negotiating HTTP/2 with ALPN.
+ * @property {Object} Metadata - The headers sent with the calls, ex: authorization.
+ * @example
+ * let m = require('nuclei/grpc');
+ * let c = m.GRPCClient();
+ * c.TLS = true;
+ * c.Metadata = {'authorization': 'Bearer ' + Token};
+ * let services = c.ListServices('localhost', 443);
+ */
+class GRPCClient {
+    /**
+    * @method
+    * @description ListServices returns the sorted services of the server using the server reflection.
+    * @param {string} host - The host of the server.
+    * @param {int} port - The port of the server.
+    * @returns {string[]} - The full names of the services.
+    * @throws {error} - If the server reflection is not supported, it returns the error.
+    * @example
+    * let m = require('nuclei/grpc');
+    * let c = m.GRPCClient();
+    * let services = c.ListServices('localhost', 50051);
+    */
+    ListServices(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListMethods returns the methods of the service using the server reflection.
+    * @param {string} host - The host of the server.
+    * @param {int} port - The port of the server.
+    * @param {string} service - The full name of the service, ex: helloworld.Greeter.
+    * @returns {Method[]} - The methods of the service.
+    * @throws {error} - If the service is not found, it returns the error.
+    * @example
+    * let m = require('nuclei/grpc');
+    * let c = m.GRPCClient();
+    * let methods = c.ListMethods('localhost', 50051, 'helloworld.Greeter');
+    */
+    ListMethods(host, port, service) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Invoke calls a unary method with the JSON encoded request and returns the JSON encoded response, the request and response types are resolved with the server reflection.
+    * @param {string} host - The host of the server.
+    * @param {int} port - The port of the server.
+    * @param {string} method - The full name of the method, ex: helloworld.Greeter/SayHello.
+    * @param {string} request - The JSON encoded request.
+    * @returns {string} - The JSON encoded response.
+    * @throws {error} - If the call is unsuccessful, it returns the error with the gRPC status.
+    * @example
+    * let m = require('nuclei/grpc');
+    * let c = m.GRPCClient();
+    * let response = c.Invoke('localhost', 50051, 'helloworld.Greeter/SayHello', JSON.stringify({name: 'nuclei'}));
+    */
+    Invoke(host, port, method, request) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} Method
+ * @description Method is an object containing the Name, the InputType and OutputType full names and the ClientStreaming and ServerStreaming flags of a method.
+ */
+const Method = {};
+
+module.exports = {
+    GRPCClient: GRPCClient,
+};
@@ -0,0 +1,150 @@
+/** @module kafka */
+
+/**
+ * @class
+ * @classdesc KafkaClient is a client for Kafka brokers. The connections are authenticated with the SASL mechanism if set and unauthenticated otherwise, the broker certificate of the TLS connections is not verified.
+ * @property {bool} TLS - TLS enables TLS connections.
+ * @property {string} Mechanism - The SASL mechanism, either PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512, unauthenticated if empty.
+ * @property {string} Username - The username of the SASL authentication.
+ * @property {string} Password - The password of the SASL authentication.
+ * @example
+ * let m = require('nuclei/kafka');
+ * let c = m.KafkaClient();
+ * c.Mechanism = 'PLAIN';
+ * c.Username = 'admin';
+ * c.Password = 'admin-secret';
+ * let isConnected = c.Connect('localhost', 9092);
+ */
+class KafkaClient {
+    /**
+    * @method
+    * @description IsKafka checks if the given host and port are running Kafka broker, the SASL mechanisms enabled on the listener are returned.
+    * @param {string} host - The host of the Kafka broker.
+    * @param {int} port - The port of the Kafka broker.
+    * @returns {IsKafkaResponse} - The response of the check.
+    * @throws {error} - If the connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kafka');
+    * let c = m.KafkaClient();
+    * let response = c.IsKafka('localhost', 9092);
+    */
+    IsKafka(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Connect connects to Kafka broker and authenticates with the SASL mechanism if set, the metadata of the cluster is requested to check the access.
+    * @param {string} host - The host of the Kafka broker.
+    * @param {int} port - The port of the Kafka broker.
+    * @returns {bool} - If connection is successful, it returns true.
+    * @throws {error} - If connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kafka');
+    * let c = m.KafkaClient();
+    * let isConnected = c.Connect('localhost', 9092);
+    */
+    Connect(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Metadata returns the brokers, topics and partitions of the cluster, only the topics the user is authorized to describe are returned.
+    * @param {string} host - The host of the Kafka broker.
+    * @param {int} port - The port of the Kafka broker.
+    * @returns {MetadataResponse} - The metadata of the cluster.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kafka');
+    * let c = m.KafkaClient();
+    * let metadata = c.Metadata('localhost', 9092);
+    */
+    Metadata(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListTopics returns the sorted names of the topics of the cluster.
+    * @param {string} host - The host of the Kafka broker.
+    * @param {int} port - The port of the Kafka broker.
+    * @returns {string[]} - The names of the topics.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kafka');
+    * let c = m.KafkaClient();
+    * let topics = c.ListTopics('localhost', 9092);
+    */
+    ListTopics(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description CanProduce checks if a record can be written to the partition 0 of the topic, a record with the nuclei value is written.
+    * @param {string} host - The host of the Kafka broker.
+    * @param {int} port - The port of the Kafka broker.
+    * @param {string} topic - The topic to write to.
+    * @returns {bool} - If the record is written, it returns true and false if the user is not authorized.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kafka');
+    * let c = m.KafkaClient();
+    * let canProduce = c.CanProduce('localhost', 9092, 'events');
+    */
+    CanProduce(host, port, topic) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description CanConsume checks if the records of the partition 0 of the topic can be fetched.
+    * @param {string} host - The host of the Kafka broker.
+    * @param {int} port - The port of the Kafka broker.
+    * @param {string} topic - The topic to fetch from.
+    * @returns {bool} - If the records can be fetched, it returns true and false if the user is not authorized.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kafka');
+    * let c = m.KafkaClient();
+    * let canConsume = c.CanConsume('localhost', 9092, 'events');
+    */
+    CanConsume(host, port, topic) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} IsKafkaResponse
+ * @description IsKafkaResponse is an object containing the IsKafka flag and the SASLMechanisms enabled on the listener, empty if authentication is disabled.
+ */
+const IsKafkaResponse = {};
+
+/**
+ * @typedef {object} MetadataResponse
+ * @description MetadataResponse is an object containing the ClusterID, the ControllerID, the Brokers and the Topics of the cluster.
+ */
+const MetadataResponse = {};
+
+/**
+ * @typedef {object} Broker
+ * @description Broker is an object containing the ID, the advertised Host and Port and the Rack of a broker.
+ */
+const Broker = {};
+
+/**
+ * @typedef {object} Topic
+ * @description Topic is an object containing the Name, the Internal flag and the Partitions of a topic.
+ */
+const Topic = {};
+
+/**
+ * @typedef {object} Partition
+ * @description Partition is an object containing the ID of a partition, the ID of its Leader and the IDs of its Replicas and in sync replicas (ISR).
+ */
+const Partition = {};
+
+module.exports = {
+    KafkaClient: KafkaClient,
+};
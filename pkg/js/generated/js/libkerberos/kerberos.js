@@ -5,6 +5,23 @@
  * @classdesc KerberosClient is a kerberos client
  */
 class KerberosClient {
+    /**
+    * @method
+    * @description ASREPRoast requests an AS-REP without pre-authentication for each of the usernames and returns the hashes of the accounts not requiring pre-authentication in the hashcat format. The unknown users and the users requiring pre-authentication are skipped.
+    * @param {string} domain - The domain to check.
+    * @param {string} controller - The controller to use.
+    * @param {string[]} usernames - The usernames to check.
+    * @returns {string[]} - The AS-REP hashes in the hashcat format.
+    * @throws {error} - The error encountered during the requests.
+    * @example
+    * let m = require('nuclei/kerberos');
+    * let c = m.KerberosClient();
+    * let hashes = c.ASREPRoast('domain', 'controller', ['administrator', 'svc_backup']);
+    */
+    ASREPRoast(domain, controller, usernames) {
+        // implemented in go
+    };
+
     /**
     * @method
     * @description EnumerateUser returns true if the user exists in the domain. If the user is not found, false is returned. If the user is found, true is returned. Optionally, the AS-REP hash is also returned if discovered.
@@ -21,6 +38,25 @@ class KerberosClient {
     EnumerateUser(domain, controller, username) {
         // implemented in go
     };
+
+    /**
+    * @method
+    * @description Kerberoast logs in with the username and password, requests a service ticket for each of the SPNs and returns their hashes in the hashcat format. The RC4 tickets are requested first, the SPN is used as the username of the hashes. The unknown SPNs are skipped.
+    * @param {string} domain - The domain to log in.
+    * @param {string} controller - The controller to use.
+    * @param {string} username - The username to log in with.
+    * @param {string} password - The password to log in with.
+    * @param {string[]} spns - The SPNs to request the service tickets for.
+    * @returns {string[]} - The service ticket hashes in the hashcat format.
+    * @throws {error} - The error encountered during the requests.
+    * @example
+    * let m = require('nuclei/kerberos');
+    * let c = m.KerberosClient();
+    * let hashes = c.Kerberoast('domain', 'controller', 'username', 'password', ['MSSQLSvc/db.domain:1433']);
+    */
+    Kerberoast(domain, controller, username, password, spns) {
+        // implemented in go
+    };
 };
 
 /**
@@ -0,0 +1,155 @@
+/** @module kubernetes */
+
+/**
+ * @class
+ * @classdesc KubernetesClient is a client for Kubernetes API servers and kubelets. Requests are authenticated with the bearer token if set, like the tokens of the service accounts, and are anonymous otherwise, the server certificate of the HTTPS connections is not verified.
+ * @property {bool} HTTPS - HTTPS enables TLS connections.
+ * @property {string} Token - The bearer token of the requests.
+ * @example
+ * let m = require('nuclei/kubernetes');
+ * let c = m.KubernetesClient();
+ * c.HTTPS = true;
+ * c.Token = Token;
+ * let pods = c.ListPods('localhost', 6443, 'kube-system');
+ */
+class KubernetesClient {
+    /**
+    * @method
+    * @description Version returns the version of the API server, it is readable anonymously by default.
+    * @param {string} host - The host of the API server.
+    * @param {int} port - The port of the API server.
+    * @returns {VersionResponse} - The version of the API server.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kubernetes');
+    * let c = m.KubernetesClient();
+    * c.HTTPS = true;
+    * let version = c.Version('localhost', 6443);
+    */
+    Version(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description CheckAccess requests the path and returns if the request is authenticated and authorized, the anonymous access is checked without token.
+    * @param {string} host - The host of the API server or of the kubelet.
+    * @param {int} port - The port of the API server or of the kubelet.
+    * @param {string} path - The path to request, ex: /api/v1/namespaces or /pods.
+    * @returns {AccessResponse} - The status code of the request and the access flags.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kubernetes');
+    * let c = m.KubernetesClient();
+    * c.HTTPS = true;
+    * let access = c.CheckAccess('localhost', 6443, '/api/v1/namespaces');
+    */
+    CheckAccess(host, port, path) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description WhoAmI returns the user of the requests as seen by the API server, the self subject review is available since Kubernetes 1.27.
+    * @param {string} host - The host of the API server.
+    * @param {int} port - The port of the API server.
+    * @returns {UserInfo} - The user of the requests.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kubernetes');
+    * let c = m.KubernetesClient();
+    * c.HTTPS = true;
+    * c.Token = Token;
+    * let user = c.WhoAmI('localhost', 6443);
+    */
+    WhoAmI(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListPods returns the pods of the namespace, of all the namespaces if empty.
+    * @param {string} host - The host of the API server.
+    * @param {int} port - The port of the API server.
+    * @param {string} namespace - The namespace of the pods.
+    * @returns {Pod[]} - The pods of the namespace.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kubernetes');
+    * let c = m.KubernetesClient();
+    * c.HTTPS = true;
+    * let pods = c.ListPods('localhost', 6443, '');
+    */
+    ListPods(host, port, namespace) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListKubeletPods returns the pods of the node of a kubelet, the read-only port 10255 is not authenticated and the 10250 port allows the anonymous requests if the anonymous authentication is enabled.
+    * @param {string} host - The host of the kubelet.
+    * @param {int} port - The port of the kubelet.
+    * @returns {Pod[]} - The pods of the node.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kubernetes');
+    * let c = m.KubernetesClient();
+    * let pods = c.ListKubeletPods('localhost', 10255);
+    */
+    ListKubeletPods(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListSecrets returns the secrets of the namespace, of all the namespaces if empty, the values of the secrets are not returned.
+    * @param {string} host - The host of the API server.
+    * @param {int} port - The port of the API server.
+    * @param {string} namespace - The namespace of the secrets.
+    * @returns {Secret[]} - The secrets of the namespace.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/kubernetes');
+    * let c = m.KubernetesClient();
+    * c.HTTPS = true;
+    * c.Token = Token;
+    * let secrets = c.ListSecrets('localhost', 6443, 'default');
+    */
+    ListSecrets(host, port, namespace) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} VersionResponse
+ * @description VersionResponse is an object containing the GitVersion and the Platform of the API server.
+ */
+const VersionResponse = {};
+
+/**
+ * @typedef {object} AccessResponse
+ * @description AccessResponse is an object containing the StatusCode of the request, the Authenticated flag unset if the server refuses the credentials and the Authorized flag set if the request succeeded.
+ */
+const AccessResponse = {};
+
+/**
+ * @typedef {object} UserInfo
+ * @description UserInfo is an object containing the Username and the Groups of the user.
+ */
+const UserInfo = {};
+
+/**
+ * @typedef {object} Pod
+ * @description Pod is an object containing the Namespace, Name, NodeName, Phase, ServiceAccount and the container Images of a pod.
+ */
+const Pod = {};
+
+/**
+ * @typedef {object} Secret
+ * @description Secret is an object containing the Namespace, Name, Type and the sorted Keys of the data of a secret.
+ */
+const Secret = {};
+
+module.exports = {
+    KubernetesClient: KubernetesClient,
+};
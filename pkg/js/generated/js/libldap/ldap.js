@@ -6,9 +6,23 @@
  */
 const LDAPMetadata = {};
 
+/**
+ * @typedef {object} Entry
+ * @description Entry is an object containing the dn and the attributes of an entry returned by a search, the binary values are hex encoded with the 0x prefix.
+ */
+const Entry = {};
+
 /**
  * @class
  * @classdesc LdapClient is a client for ldap protocol in golang. It is a wrapper around the standard library ldap package.
+ * @property {boolean} TLS - TLS enables the ldaps connections, the default port is 636 if set.
+ * @property {boolean} StartTLS - StartTLS upgrades the plain connections to TLS with the StartTLS extended operation.
+ * @property {boolean} InsecureSkipVerify - InsecureSkipVerify disables the verification of the server certificate.
+ * @example
+ * let m = require('nuclei/ldap');
+ * let c = m.LdapClient();
+ * c.StartTLS = true;
+ * c.InsecureSkipVerify = true;
  */
 class LdapClient {
     /**
@@ -27,6 +41,22 @@ class LdapClient {
         // implemented in go
     };
 
+    /**
+    * @method
+    * @description IsAnonymousBindAllowed checks if the naming context of the server can be searched with an anonymous bind.
+    * @param {string} host - The host to check.
+    * @param {int} port - The port to check.
+    * @returns {boolean} - Whether the anonymous searches are allowed.
+    * @throws {error} - The error encountered during the check.
+    * @example
+    * let m = require('nuclei/ldap');
+    * let c = m.LdapClient();
+    * let allowed = c.IsAnonymousBindAllowed('localhost', 389);
+    */
+    IsAnonymousBindAllowed(host, port) {
+        // implemented in go
+    };
+
     /**
     * @method
     * @description IsLdap checks if the given host and port are running ldap server.
@@ -42,6 +72,28 @@ class LdapClient {
     IsLdap(host, port) {
         // implemented in go
     };
+
+    /**
+    * @method
+    * @description Search binds with the username and password and searches the subtree of the base DN with the filter. The naming context of the server is used if the base DN is empty, the (objectClass=*) filter if the filter is empty and all the attributes are returned if none are given. The results are fetched by pages of pageSize entries if set, at most 1000 entries are returned. An anonymous bind is used if the username is empty.
+    * @param {string} host - The host to search.
+    * @param {int} port - The port to search.
+    * @param {string} username - The username to bind with.
+    * @param {string} password - The password to bind with.
+    * @param {string} baseDN - The base DN of the search.
+    * @param {string} filter - The filter of the search.
+    * @param {string[]} attributes - The attributes to return.
+    * @param {int} pageSize - The size of the pages, 0 disables the paging.
+    * @returns {string} - The entries as a JSON array of Entry objects.
+    * @throws {error} - The error encountered during the search.
+    * @example
+    * let m = require('nuclei/ldap');
+    * let c = m.LdapClient();
+    * let entries = JSON.parse(c.Search('localhost', 389, '', '', '', '(objectClass=user)', ['sAMAccountName'], 500));
+    */
+    Search(host, port, username, password, baseDN, filter, attributes, pageSize) {
+        // implemented in go
+    };
 };
 
 module.exports = {
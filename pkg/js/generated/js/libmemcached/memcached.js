@@ -0,0 +1,90 @@
+/** @module memcached */
+
+/**
+ * @class
+ * @classdesc MemcachedClient is a client for memcached servers. The commands use the text protocol over tcp by default or over udp, which most servers only enable on old versions or by misconfiguration.
+ * @property {bool} UDP - UDP sends the commands over udp instead of tcp.
+ * @example
+ * let m = require('nuclei/memcached');
+ * let c = m.MemcachedClient();
+ * c.UDP = true;
+ * let stats = c.Stats('localhost', 11211);
+ */
+class MemcachedClient {
+    /**
+    * @method
+    * @description Version returns the version of the server.
+    * @param {string} host - The host of the memcached server.
+    * @param {int} port - The port of the memcached server.
+    * @returns {string} - The version of the server.
+    * @throws {error} - If the command is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/memcached');
+    * let c = m.MemcachedClient();
+    * let version = c.Version('localhost', 11211);
+    */
+    Version(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Stats returns the general statistics of the server.
+    * @param {string} host - The host of the memcached server.
+    * @param {int} port - The port of the memcached server.
+    * @returns {Object} - The statistics of the server by name.
+    * @throws {error} - If the command is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/memcached');
+    * let c = m.MemcachedClient();
+    * let stats = c.Stats('localhost', 11211);
+    * log(stats.curr_items);
+    */
+    Stats(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description SampleKeys returns at most limit keys of the cache, 100 keys if not positive and at most 1000. The keys are dumped by the lru crawler of the 1.4.31 and later versions and by the cachedump command of the slabs on the older ones.
+    * @param {string} host - The host of the memcached server.
+    * @param {int} port - The port of the memcached server.
+    * @param {int} limit - The maximum number of keys.
+    * @returns {string[]} - The sampled keys.
+    * @throws {error} - If the commands are unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/memcached');
+    * let c = m.MemcachedClient();
+    * let keys = c.SampleKeys('localhost', 11211, 10);
+    */
+    SampleKeys(host, port, limit) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Amplification sends the stats command over udp and returns the sizes of the request and of the response datagrams to verify the amplification vector.
+    * @param {string} host - The host of the memcached server.
+    * @param {int} port - The udp port of the memcached server.
+    * @returns {AmplificationResponse} - The sizes of the request and of the response.
+    * @throws {error} - If the command is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/memcached');
+    * let c = m.MemcachedClient();
+    * let response = c.Amplification('localhost', 11211);
+    * log(response.Factor);
+    */
+    Amplification(host, port) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} AmplificationResponse
+ * @description AmplificationResponse is an object containing the RequestSize and the ResponseSize in bytes and their ratio as Factor.
+ */
+const AmplificationResponse = {};
+
+module.exports = {
+    MemcachedClient: MemcachedClient,
+};
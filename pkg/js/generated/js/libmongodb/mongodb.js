@@ -0,0 +1,84 @@
+/** @module mongodb */
+
+/**
+ * @class
+ * @classdesc MongoDBClient is a client for MongoDB database. Internally client implements the MongoDB wire protocol, users are authenticated against the admin database with SCRAM-SHA-1 or SCRAM-SHA-256.
+ */
+class MongoDBClient {
+    /**
+    * @method
+    * @description Connect connects to MongoDB database using given credentials. If the username is empty, the connection is unauthenticated and is successful only if the database does not enforce authorization.
+    * @param {string} host - The host of the MongoDB database.
+    * @param {int} port - The port of the MongoDB database.
+    * @param {string} username - The username to connect to the MongoDB database.
+    * @param {string} password - The password to connect to the MongoDB database.
+    * @returns {bool} - If connection is successful, it returns true.
+    * @throws {error} - If connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/mongodb');
+    * let c = m.MongoDBClient();
+    * let isConnected = c.Connect('localhost', 27017, 'admin', 'password');
+    * let isUnauthenticated = c.Connect('localhost', 27017, '', '');
+    */
+    Connect(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsMongoDB checks if the given host and port are running MongoDB database.
+    * @param {string} host - The host to check.
+    * @param {int} port - The port to check.
+    * @returns {bool} - If the host and port are running MongoDB database, it returns true.
+    * @throws {error} - If the check is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/mongodb');
+    * let c = m.MongoDBClient();
+    * let isMongoDB = c.IsMongoDB('localhost', 27017);
+    */
+    IsMongoDB(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListDatabases connects to MongoDB database using given credentials and returns the names of the databases. If the username is empty, the connection is unauthenticated.
+    * @param {string} host - The host of the MongoDB database.
+    * @param {int} port - The port of the MongoDB database.
+    * @param {string} username - The username to connect to the MongoDB database.
+    * @param {string} password - The password to connect to the MongoDB database.
+    * @returns {string[]} - The names of the databases.
+    * @throws {error} - If listing is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/mongodb');
+    * let c = m.MongoDBClient();
+    * let databases = c.ListDatabases('localhost', 27017, '', '');
+    */
+    ListDatabases(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description RunCommand connects to MongoDB database using given credentials and runs a command on the db, returning the reply as extended json. The command is a json object whose first key is the command name, object ids can be given as {"$oid": "..."} objects. If the username is empty, the connection is unauthenticated.
+    * @param {string} host - The host of the MongoDB database.
+    * @param {int} port - The port of the MongoDB database.
+    * @param {string} username - The username to connect to the MongoDB database.
+    * @param {string} password - The password to connect to the MongoDB database.
+    * @param {string} dbName - The name of the database to run the command on.
+    * @param {string} command - The json command to run.
+    * @returns {string} - The json reply of the command.
+    * @throws {error} - If the command is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/mongodb');
+    * let c = m.MongoDBClient();
+    * let reply = c.RunCommand('localhost', 27017, '', '', 'users', JSON.stringify({find: 'accounts', limit: 5}));
+    */
+    RunCommand(host, port, username, password, dbName, command) {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    MongoDBClient: MongoDBClient,
+};
@@ -0,0 +1,79 @@
+/** @module mqtt */
+
+/**
+ * @class
+ * @classdesc MQTTClient is a client for MQTT brokers supporting the 3.1.1 and 5 versions of the protocol. The connections are anonymous without username, the broker certificate of the TLS connections is not verified.
+ * @property {bool} TLS - TLS enables TLS connections.
+ * @property {string} Version - The version of the protocol, either 3.1.1 or 5, 3.1.1 by default.
+ * @property {string} ClientID - The client identifier, a random one if empty.
+ * @example
+ * let m = require('nuclei/mqtt');
+ * let c = m.MQTTClient();
+ * c.Version = '5';
+ * let isConnected = c.Connect('localhost', 1883, 'admin', 'admin');
+ */
+class MQTTClient {
+    /**
+    * @method
+    * @description IsMQTT checks if the given host and port are running MQTT broker, the broker is connected anonymously to check if anonymous connections are accepted.
+    * @param {string} host - The host of the MQTT broker.
+    * @param {int} port - The port of the MQTT broker.
+    * @returns {IsMQTTResponse} - The response of the check.
+    * @throws {error} - If the connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/mqtt');
+    * let c = m.MQTTClient();
+    * let response = c.IsMQTT('localhost', 1883);
+    */
+    IsMQTT(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Connect connects to MQTT broker using given credentials, anonymously if the username is empty.
+    * @param {string} host - The host of the MQTT broker.
+    * @param {int} port - The port of the MQTT broker.
+    * @param {string} username - The username to connect with.
+    * @param {string} password - The password to connect with.
+    * @returns {bool} - If connection is successful, it returns true and false if the credentials are refused.
+    * @throws {error} - If connection is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/mqtt');
+    * let c = m.MQTTClient();
+    * let isConnected = c.Connect('localhost', 1883, 'admin', 'admin');
+    */
+    Connect(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Subscribe subscribes to the topic filters with QoS 0 and returns the messages received during the window as a json array of objects with topic, payload and retain. The window is 5 seconds if not positive and at most 30 seconds, at most 1000 messages are returned and the payloads which are not printable are returned as 0x prefixed hex.
+    * @param {string} host - The host of the MQTT broker.
+    * @param {int} port - The port of the MQTT broker.
+    * @param {string} username - The username to connect with, anonymously if empty.
+    * @param {string} password - The password to connect with.
+    * @param {string[]} topics - The topic filters to subscribe to.
+    * @param {int} window - The duration of the subscription in seconds.
+    * @returns {string} - The received messages as json.
+    * @throws {error} - If the subscription is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/mqtt');
+    * let c = m.MQTTClient();
+    * let messages = JSON.parse(c.Subscribe('localhost', 1883, '', '', ['#', '$SYS/#'], 5));
+    */
+    Subscribe(host, port, username, password, topics, window) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} IsMQTTResponse
+ * @description IsMQTTResponse is an object containing the IsMQTT flag and the Anonymous flag set if the broker accepts anonymous connections.
+ */
+const IsMQTTResponse = {};
+
+module.exports = {
+    MQTTClient: MQTTClient,
+};
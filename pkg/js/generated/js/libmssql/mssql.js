@@ -2,7 +2,13 @@
 
 /**
  * @class
- * @classdesc MSSQLClient is a client for MS SQL database. Internally client uses denisenkom/go-mssqldb driver.
+ * @classdesc MSSQLClient is a client for MS SQL database. Internally client uses denisenkom/go-mssqldb driver. The SQL Server authentication is used unless Domain is set, the Windows authentication of the domain users is then done with NTLM.
+ * @property {string} Domain - The windows domain of the users, ex: CORP or . for the local accounts.
+ * @example
+ * let m = require('nuclei/mssql');
+ * let c = m.MSSQLClient();
+ * c.Domain = 'CORP';
+ * let isConnected = c.Connect('localhost', 1433, 'username', 'password');
  */
 class MSSQLClient {
     /**
@@ -57,8 +63,50 @@ class MSSQLClient {
     IsMssql(host, port) {
         // implemented in go
     };
+
+    /**
+    * @method
+    * @description ExecuteQuery connects to MS SQL database using given credentials and database name and executes a query on the db. The rows are returned as json, the values are converted according to the type of their column and NULL values are returned as null.
+    * @param {string} host - The host of the MS SQL database.
+    * @param {int} port - The port of the MS SQL database.
+    * @param {string} username - The username to connect to the MS SQL database.
+    * @param {string} password - The password to connect to the MS SQL database.
+    * @param {string} dbName - The name of the database to connect to.
+    * @param {string} query - The query to execute on the database.
+    * @returns {string} - The JSON encoded rows of the query.
+    * @throws {error} - The error encountered during query execution.
+    * @example
+    * let m = require('nuclei/mssql');
+    * let c = m.MSSQLClient();
+    * let result = c.ExecuteQuery('localhost', 1433, 'sa', 'password', 'master', 'SELECT name FROM sys.databases');
+    */
+    ExecuteQuery(host, port, username, password, dbName, query) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListInstances returns the instances of the server using the SQL Server Browser service, the browser service listens on the udp port 1434 and returns the instances of the server along with their version and ports.
+    * @param {string} host - The host of the server.
+    * @param {int} port - The udp port of the browser service.
+    * @returns {Instance[]} - The instances of the server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('nuclei/mssql');
+    * let c = m.MSSQLClient();
+    * let instances = c.ListInstances('localhost', 1434);
+    */
+    ListInstances(host, port) {
+        // implemented in go
+    };
 };
 
+/**
+ * @typedef {object} Instance
+ * @description Instance is an object containing the ServerName, InstanceName, IsClustered flag, Version, TCPPort and NamedPipe of an instance of the server.
+ */
+const Instance = {};
+
 module.exports = {
     MSSQLClient: MSSQLClient,
 };
\ No newline at end of file
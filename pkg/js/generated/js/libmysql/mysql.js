@@ -2,7 +2,16 @@
 
 /**
  * @class
- * @classdesc MySQLClient is a client for MySQL database. Internally client uses go-sql-driver/mysql driver.
+ * @classdesc MySQLClient is a client for MySQL database. Internally client uses go-sql-driver/mysql driver. Connections are not encrypted unless TLSMode is set, the caching_sha2_password and sha256_password plugins retrieve the public key of the server to send the password on plain connections if ServerPublicKey is not set.
+ * @property {string} TLSMode - The tls mode of the connections, one of disabled, preferred, required or verify-identity, the server certificate is only verified with verify-identity.
+ * @property {string} ServerPublicKey - The PEM encoded RSA public key of the server encrypting the password on plain connections.
+ * @property {bool} AllowCleartextPasswords - AllowCleartextPasswords enables the mysql_clear_password plugin, ex: for PAM or LDAP authentication.
+ * @property {bool} AllowOldPasswords - AllowOldPasswords enables the insecure mysql_old_password plugin of the old servers.
+ * @example
+ * let m = require('nuclei/mysql');
+ * let c = m.MySQLClient();
+ * c.TLSMode = 'required';
+ * let result = c.Connect('localhost', 3306, 'root', 'password');
  */
 class MySQLClient {
     /**
@@ -44,14 +53,14 @@ class MySQLClient {
 
     /**
     * @method
-    * @description ExecuteQuery connects to Mysql database using given credentials and database name and executes a query on the db.
+    * @description ExecuteQuery connects to Mysql database using given credentials and database name and executes a query on the db. The rows are returned as json, the values are converted according to the type of their column and NULL values are returned as null.
     * @param {string} host - The host of the MySQL database.
     * @param {int} port - The port of the MySQL database.
     * @param {string} username - The username to connect to the MySQL database.
     * @param {string} password - The password to connect to the MySQL database.
     * @param {string} dbName - The name of the database to connect to.
     * @param {string} query - The query to execute on the database.
-    * @returns {string} - The result of the query execution.
+    * @returns {string} - The JSON encoded rows of the query.
     * @throws {error} - The error encountered during query execution.
     * @example
     * let m = require('nuclei/mysql');
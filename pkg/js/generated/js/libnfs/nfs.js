@@ -0,0 +1,57 @@
+/** @module nfs */
+
+/**
+ * @class
+ * @classdesc NFSClient is a client for NFS servers. The ports of the mount and nfs v3 programs are requested to the portmapper, the requests use the unix credentials of root which are squashed to the anonymous user by default. The exports with the secure option require a privileged source port and refuse the requests of unprivileged scans.
+ */
+class NFSClient {
+    /**
+    * @method
+    * @description ListExports returns the exports of the server with the clients allowed to mount them.
+    * @param {string} host - The host of the NFS server.
+    * @param {int} port - The port of the portmapper, usually 111.
+    * @returns {Export[]} - The exports of the server.
+    * @throws {error} - If the listing is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/nfs');
+    * let c = m.NFSClient();
+    * let exports = c.ListExports('localhost', 111);
+    */
+    ListExports(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Read mounts the export of the directory and reads the path relative to the export, the root of the export if empty. The entries of the directories are returned and the content of the files, at most 1MB of content and 10000 entries are returned.
+    * @param {string} host - The host of the NFS server.
+    * @param {int} port - The port of the portmapper, usually 111.
+    * @param {string} directory - The directory of the export.
+    * @param {string} filePath - The path to read relative to the export.
+    * @returns {ReadResponse} - The content or the entries of the path.
+    * @throws {error} - If the read is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/nfs');
+    * let c = m.NFSClient();
+    * let response = c.Read('localhost', 111, '/srv/share', 'etc/passwd');
+    */
+    Read(host, port, directory, filePath) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} Export
+ * @description Export is an object containing the Directory of an export and the Clients allowed to mount it, any client if empty.
+ */
+const Export = {};
+
+/**
+ * @typedef {object} ReadResponse
+ * @description ReadResponse is an object containing the IsDirectory flag, the Content of a file as text or 0x prefixed hex and the Entries of a directory.
+ */
+const ReadResponse = {};
+
+module.exports = {
+    NFSClient: NFSClient,
+};
@@ -5,6 +5,57 @@
  * @classdesc OracleClient is a minimal Oracle client for nuclei scripts.
  */
 class OracleClient {
+    /**
+    * @method
+    * @description EnumerateSIDs tries to connect to each of the SIDs and returns the ones known by the TNS listener.
+    * @param {string} host - The host to check.
+    * @param {int} port - The port to check.
+    * @param {string[]} sids - The SIDs to check.
+    * @returns {string[]} - The SIDs known by the listener.
+    * @throws {error} - The error encountered during the enumeration.
+    * @example
+    * let m = require('nuclei/oracle');
+    * let c = m.OracleClient();
+    * let sids = c.EnumerateSIDs('localhost', 1521, ['XE', 'ORCL', 'PROD']);
+    */
+    EnumerateSIDs(host, port, sids) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description EnumerateServiceNames tries to connect to each of the service names and returns the ones known by the TNS listener.
+    * @param {string} host - The host to check.
+    * @param {int} port - The port to check.
+    * @param {string[]} names - The service names to check.
+    * @returns {string[]} - The service names known by the listener.
+    * @throws {error} - The error encountered during the enumeration.
+    * @example
+    * let m = require('nuclei/oracle');
+    * let c = m.OracleClient();
+    * let names = c.EnumerateServiceNames('localhost', 1521, ['XEPDB1', 'ORCLPDB1']);
+    */
+    EnumerateServiceNames(host, port, names) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetTNSVersion sends the VERSION command to the TNS listener and returns its version.
+    * @param {string} host - The host to check.
+    * @param {int} port - The port to check.
+    * @returns {TNSVersion} - The version of the listener.
+    * @throws {error} - The error encountered during the check.
+    * @example
+    * let m = require('nuclei/oracle');
+    * let c = m.OracleClient();
+    * let version = c.GetTNSVersion('localhost', 1521);
+    * log(version.Version);
+    */
+    GetTNSVersion(host, port) {
+        // implemented in go
+    };
+
     /**
     * @method
     * @description IsOracle checks if a host is running an Oracle server.
@@ -28,6 +79,12 @@ class OracleClient {
  */
 const IsOracleResponse = {};
 
+/**
+ * @typedef {object} TNSVersion
+ * @description TNSVersion is an object containing the Version, the VSNNUM and the Banner of the TNS listener.
+ */
+const TNSVersion = {};
+
 module.exports = {
     OracleClient: OracleClient,
 };
\ No newline at end of file
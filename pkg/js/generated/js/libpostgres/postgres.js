@@ -1,13 +1,70 @@
 /** @module postgres */
 
+/**
+ * @function
+ * @description QuoteIdentifier quotes an identifier (ex: a table or column name) to be used in a query.
+ * @param {string} name - The identifier to quote.
+ * @returns {string} - The quoted identifier.
+ * @example
+ * let m = require('nuclei/postgres');
+ * let query = 'SELECT * FROM ' + m.QuoteIdentifier(table);
+ */
+function QuoteIdentifier(name) {
+    // implemented in go
+};
+
+/**
+ * @function
+ * @description QuoteLiteral quotes a string literal to be used in a query where bind parameters are not supported (ex: DDL statements).
+ * @param {string} literal - The literal to quote.
+ * @returns {string} - The quoted literal.
+ * @example
+ * let m = require('nuclei/postgres');
+ * let query = 'COMMENT ON TABLE users IS ' + m.QuoteLiteral(comment);
+ */
+function QuoteLiteral(literal) {
+    // implemented in go
+};
+
 /**
  * @class
- * @classdesc PGClient is a client for Postgres database. Internally client uses go-pg/pg driver.
+ * @classdesc PGClient is a client for Postgres database. Internally client uses go-pg/pg driver. The database handles are pooled by host, port, credentials and tls settings so that repeated calls reuse their connections. Connections are not encrypted unless SSLMode is set.
+ * @property {string} SSLMode - The sslmode of the connections, one of disable, require, verify-ca or verify-full.
+ * @property {string} RootCA - The PEM encoded root certificate verifying the server certificate, the system roots are used if empty.
+ * @property {string} ClientCert - The PEM encoded client certificate.
+ * @property {string} ClientKey - The PEM encoded private key of the client certificate.
+ * @example
+ * let m = require('nuclei/postgres');
+ * let c = m.PGClient();
+ * c.SSLMode = 'verify-full';
+ * c.RootCA = rootCA;
+ * let isConnected = c.Connect('db.example.com', 5432, 'username', 'password');
  */
 class PGClient {
     /**
     * @method
-    * @description Connect connects to Postgres database using given credentials. The connection is closed after the function returns.
+    * @description BruteForce tries the credentials on the Postgres database concurrently and returns the first valid credential along with the attempt statistics. The attempts of the host are delayed with an exponential backoff on connection errors and aborted after consecutive connection errors.
+    * @param {string} host - The host of the Postgres database.
+    * @param {int} port - The port of the Postgres database.
+    * @param {Credential[]} credentials - The credentials to try.
+    * @param {int} concurrency - The number of concurrent login attempts.
+    * @returns {BruteForceResult} - The first valid credential and the attempt statistics.
+    * @throws {error} - If the attempts are aborted due to connection errors, it returns the error.
+    * @example
+    * let m = require('nuclei/postgres');
+    * let c = m.PGClient();
+    * let result = c.BruteForce('localhost', 5432, [{Username: 'postgres', Password: 'postgres'}, {Username: 'admin', Password: 'admin'}], 5);
+    * if (result.Found) {
+    *     log(result.Credential.Username);
+    * }
+    */
+    BruteForce(host, port, credentials, concurrency) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Connect connects to Postgres database using given credentials.
     * @param {string} host - The host of the Postgres database.
     * @param {int} port - The port of the Postgres database.
     * @param {string} username - The username to connect to the Postgres database.
@@ -25,7 +82,7 @@ class PGClient {
 
     /**
     * @method
-    * @description ConnectWithDB connects to Postgres database using given credentials and database name. The connection is closed after the function returns.
+    * @description ConnectWithDB connects to Postgres database using given credentials and database name.
     * @param {string} host - The host of the Postgres database.
     * @param {int} port - The port of the Postgres database.
     * @param {string} username - The username to connect to the Postgres database.
@@ -62,6 +119,27 @@ class PGClient {
         // implemented in go
     };
 
+    /**
+    * @method
+    * @description ExecuteQueryWithArgs connects to Postgres database using given credentials and database name and executes a query with bind parameters on the db. The arguments are bound to the $1, $2... placeholders of the query, arrays are bound as postgres arrays and objects as json.
+    * @param {string} host - The host of the Postgres database.
+    * @param {int} port - The port of the Postgres database.
+    * @param {string} username - The username to connect to the Postgres database.
+    * @param {string} password - The password to connect to the Postgres database.
+    * @param {string} dbName - The name of the database to connect to.
+    * @param {string} query - The query to execute on the database.
+    * @param {...any} args - The values bound to the placeholders of the query.
+    * @returns {string} - The result of the query execution.
+    * @throws {error} - If query execution is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/postgres');
+    * let c = m.PGClient();
+    * let result = c.ExecuteQueryWithArgs('localhost', 5432, 'username', 'password', 'mydb', 'SELECT * FROM users WHERE name = $1 AND id = ANY($2)', 'admin', [1, 2]);
+    */
+    ExecuteQueryWithArgs(host, port, username, password, dbName, query, ...args) {
+        // implemented in go
+    };
+
     /**
     * @method
     * @description IsPostgres checks if the given host and port are running Postgres database.
@@ -79,6 +157,20 @@ class PGClient {
     };
 };
 
+/**
+ * @typedef {object} BruteForceResult
+ * @description BruteForceResult is the result of a credential brute force with the Found flag, the first valid Credential and the Attempts and Errors counts.
+ */
+const BruteForceResult = {};
+
+/**
+ * @typedef {object} Credential
+ * @description Credential is a username and password pair with the Username and Password fields.
+ */
+const Credential = {};
+
 module.exports = {
     PGClient: PGClient,
+    QuoteIdentifier: QuoteIdentifier,
+    QuoteLiteral: QuoteLiteral,
 };
\ No newline at end of file
@@ -21,6 +21,40 @@ class RDPClient {
         // implemented in go
     };
 
+    /**
+    * @method
+    * @description GetNTLMInfo negotiates CredSSP with the rdp server and returns the information of its NTLM challenge, ex: the hostname, domain and OS build.
+    * @param {string} host - The host to check.
+    * @param {number} port - The port to check.
+    * @returns {NTLMInfo} - The information of the NTLM challenge.
+    * @throws {error} - The error encountered during the negotiation.
+    * @example
+    * let m = require('nuclei/rdp');
+    * let c = m.RDPClient();
+    * let info = c.GetNTLMInfo('localhost', 3389);
+    * log(to_json(info));
+    */
+    GetNTLMInfo(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetSecurityLayer checks the security protocols accepted by the rdp server and if the network level authentication is enforced. A connection is made for each of the protocols.
+    * @param {string} host - The host to check.
+    * @param {number} port - The port to check.
+    * @returns {SecurityLayer} - The security layer of the server.
+    * @throws {error} - The error encountered during the negotiation.
+    * @example
+    * let m = require('nuclei/rdp');
+    * let c = m.RDPClient();
+    * let layer = c.GetSecurityLayer('localhost', 3389);
+    * log(layer.NLA);
+    */
+    GetSecurityLayer(host, port) {
+        // implemented in go
+    };
+
     /**
     * @method
     * @description IsRDP checks if the given host and port are running rdp server. If connection is successful, it returns true. If connection is unsuccessful, it returns false and error. The Name of the OS is also returned if the connection is successful.
@@ -50,6 +84,18 @@ const CheckRDPAuthResponse = {};
  */
 const IsRDPResponse = {};
 
+/**
+ * @typedef {object} NTLMInfo
+ * @description NTLMInfo is an object containing the TargetName, NetBIOSComputerName, NetBIOSDomainName, DNSComputerName, DNSDomainName, ForestName and OSVersion leaked by the NTLM challenge.
+ */
+const NTLMInfo = {};
+
+/**
+ * @typedef {object} SecurityLayer
+ * @description SecurityLayer is an object containing NLA, true if the network level authentication is enforced, and the accepted Protocols, ex: PROTOCOL_SSL or PROTOCOL_HYBRID.
+ */
+const SecurityLayer = {};
+
 module.exports = {
     RDPClient: RDPClient,
 };
\ No newline at end of file
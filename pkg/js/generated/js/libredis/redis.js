@@ -78,7 +78,96 @@ function RunLuaScript(host, port, password, script) {
     // implemented in go
 };
 
+/**
+ * @class
+ * @classdesc RedisClient is a client for Redis servers. The connections are authenticated with the ACL username and the password of the calls if Username is set, with the legacy password authentication otherwise.
+ * @property {string} Username - The ACL username of the Redis 6 servers.
+ * @property {bool} TLS - TLS enables TLS connections.
+ * @property {string} RootCA - The PEM encoded root certificate verifying the server certificate, the system roots are used if empty.
+ * @property {bool} InsecureSkipVerify - InsecureSkipVerify disables the verification of the server certificate.
+ * @example
+ * let m = require('nuclei/redis');
+ * let c = m.RedisClient();
+ * c.Username = 'admin';
+ * c.TLS = true;
+ * c.InsecureSkipVerify = true;
+ * let status = c.Connect('localhost', 6380, 'password');
+ */
+class RedisClient {
+    /**
+    * @method
+    * @description Connect tries to connect redis server with the username of the client and the password.
+    * @param {string} host - The host of the redis server.
+    * @param {int} port - The port of the redis server.
+    * @param {string} password - The password for the redis server.
+    * @returns {bool} - The status of the connection.
+    * @throws {error} - The error encountered during connection.
+    * @example
+    * let m = require('nuclei/redis');
+    * let c = m.RedisClient();
+    * c.Username = 'admin';
+    * let status = c.Connect('localhost', 6379, 'password');
+    */
+    Connect(host, port, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetServerInfo returns the server info for a redis server
+    * @param {string} host - The host of the redis server.
+    * @param {int} port - The port of the redis server.
+    * @param {string} password - The password for the redis server.
+    * @returns {string} - The server info.
+    * @throws {error} - The error encountered during getting server info.
+    * @example
+    * let m = require('nuclei/redis');
+    * let c = m.RedisClient();
+    * let info = c.GetServerInfo('localhost', 6379, '');
+    */
+    GetServerInfo(host, port, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description RunLuaScript runs a lua script on the redis server
+    * @param {string} host - The host of the redis server.
+    * @param {int} port - The port of the redis server.
+    * @param {string} password - The password for the redis server.
+    * @param {string} script - The lua script to run.
+    * @returns {Object} - The reply of the script.
+    * @throws {error} - The error encountered during running the lua script.
+    * @example
+    * let m = require('nuclei/redis');
+    * let c = m.RedisClient();
+    * let reply = c.RunLuaScript('localhost', 6379, 'password', 'return redis.call(\'ping\')');
+    */
+    RunLuaScript(host, port, password, script) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description RunCommand runs a command with its arguments on the redis server and returns the reply. The replies are returned as strings, numbers, arrays or objects, the nil replies are returned as null and the error replies as errors.
+    * @param {string} host - The host of the redis server.
+    * @param {int} port - The port of the redis server.
+    * @param {string} password - The password for the redis server.
+    * @param {...any} args - The command and its arguments.
+    * @returns {Object} - The reply of the command.
+    * @throws {error} - The error reply of the command or the error encountered during connection.
+    * @example
+    * let m = require('nuclei/redis');
+    * let c = m.RedisClient();
+    * let dir = c.RunCommand('localhost', 6379, '', 'CONFIG', 'GET', 'dir');
+    */
+    RunCommand(host, port, password, ...args) {
+        // implemented in go
+    };
+};
+
 module.exports = {
+    RedisClient: RedisClient,
     Connect: Connect,
     GetServerInfo: GetServerInfo,
     GetServerInfoAuth: GetServerInfoAuth,
@@ -13,6 +13,18 @@ const SMBLog = {};
 
 const ServiceSMB = {};
 
+/**
+ * @typedef {object} Share
+ * @description Share is an object containing the Name of the share and Readable, true if the files of the share can be listed.
+ */
+const Share = {};
+
+/**
+ * @typedef {object} File
+ * @description File is an object containing the Share, Path, Name, Size, IsDir and ModTime of a file or a directory of a share.
+ */
+const File = {};
+
 /**
  * @class
  * @classdesc SMBClient is a client for SMB servers.
@@ -50,6 +62,45 @@ class SMBClient {
         // implemented in go
     };
 
+    /**
+    * @method
+    * @description EnumerateShares tries to connect to provided host and port and list the shares along with their readability by using given credentials. A null session is used if the credentials are blank.
+    * @param {string} host - The host to connect to.
+    * @param {string} port - The port to connect to.
+    * @param {string} user - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @returns {Share[]} - The list of shares.
+    * @throws {error} - The error encountered during the listing.
+    * @example
+    * let m = require('nuclei/smb');
+    * let c = m.SMBClient();
+    * let shares = c.EnumerateShares('localhost', '445', 'user', 'password');
+    */
+    EnumerateShares(host, port, user, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListFiles tries to connect to provided host and port and list the files of the directory of the share by using given credentials. The subdirectories are listed up to depth levels, at most 1000 files are listed.
+    * @param {string} host - The host to connect to.
+    * @param {string} port - The port to connect to.
+    * @param {string} user - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} share - The share to list.
+    * @param {string} dir - The directory of the share to list.
+    * @param {number} depth - The depth of the listed subdirectories, 0 lists the directory only.
+    * @returns {File[]} - The list of files.
+    * @throws {error} - The error encountered during the listing.
+    * @example
+    * let m = require('nuclei/smb');
+    * let c = m.SMBClient();
+    * let files = c.ListFiles('localhost', '445', '', '', 'backups', '', 2);
+    */
+    ListFiles(host, port, user, password, share, dir, depth) {
+        // implemented in go
+    };
+
     /**
     * @method
     * @description ListSMBv2Metadata tries to connect to provided host and port and list SMBv2 metadata.
@@ -83,6 +134,43 @@ class SMBClient {
     ListShares(host, port, user, password) {
         // implemented in go
     };
+
+    /**
+    * @method
+    * @description ListSharesNullSession tries to connect to provided host and port with a null session and list the shares along with their readability.
+    * @param {string} host - The host to connect to.
+    * @param {string} port - The port to connect to.
+    * @returns {Share[]} - The list of shares.
+    * @throws {error} - The error encountered during the listing.
+    * @example
+    * let m = require('nuclei/smb');
+    * let c = m.SMBClient();
+    * let shares = c.ListSharesNullSession('localhost', '445');
+    */
+    ListSharesNullSession(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ReadFile tries to connect to provided host and port and read the file of the share by using given credentials. At most size bytes are read, the size is capped to 1MB and defaults to it if 0.
+    * @param {string} host - The host to connect to.
+    * @param {string} port - The port to connect to.
+    * @param {string} user - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} share - The share of the file.
+    * @param {string} name - The path of the file in the share.
+    * @param {number} size - The maximum number of bytes to read.
+    * @returns {string} - The content of the file.
+    * @throws {error} - The error encountered during the reading.
+    * @example
+    * let m = require('nuclei/smb');
+    * let c = m.SMBClient();
+    * let content = c.ReadFile('localhost', '445', '', '', 'backups', 'web.config', 4096);
+    */
+    ReadFile(host, port, user, password, share, name, size) {
+        // implemented in go
+    };
 };
 
 module.exports = {
@@ -0,0 +1,80 @@
+/** @module snmp */
+
+/**
+ * @class
+ * @classdesc SNMPClient is a client for SNMP agents. The v1 and v2c requests are authenticated with the community and the v3 ones with the user-based security model, internally client implements the protocol over udp.
+ * @property {string} Version - The version of the requests, either 1, 2c or 3, 2c by default.
+ * @property {string} Community - The community of the v1 and v2c requests, public by default.
+ * @property {string} Username - The user name of the v3 requests.
+ * @property {string} AuthProtocol - The v3 authentication protocol, either MD5, SHA, SHA224, SHA256, SHA384 or SHA512, the requests are not authenticated if empty.
+ * @property {string} AuthPassword - The v3 authentication password.
+ * @property {string} PrivProtocol - The v3 privacy protocol, either DES or AES, the requests are not encrypted if empty.
+ * @property {string} PrivPassword - The v3 privacy password.
+ * @property {string} ContextName - The v3 context name, empty by default.
+ * @example
+ * let m = require('nuclei/snmp');
+ * let c = m.SNMPClient();
+ * c.Version = '3';
+ * c.Username = 'admin';
+ * c.AuthProtocol = 'SHA';
+ * c.AuthPassword = 'authpassword';
+ * c.PrivProtocol = 'AES';
+ * c.PrivPassword = 'privpassword';
+ * let values = JSON.parse(c.Get('localhost', 161, '1.3.6.1.2.1.1.1.0'));
+ */
+class SNMPClient {
+    /**
+    * @method
+    * @description BruteForceCommunity returns the communities accepted by the agent. The requests of all the communities are sent at once with the version of the client, either 1 or 2c, as the agents only respond to the valid ones.
+    * @param {string} host - The host of the agent.
+    * @param {int} port - The port of the agent.
+    * @param {string[]} communities - The communities to check.
+    * @returns {string[]} - The valid communities.
+    * @throws {error} - If the check is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/snmp');
+    * let c = m.SNMPClient();
+    * let communities = c.BruteForceCommunity('localhost', 161, ['public', 'private', 'cisco']);
+    */
+    BruteForceCommunity(host, port, communities) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Get returns the values of the oids as a json object of the oids to their values. The variables missing on the agent are not returned, printable octet strings are returned as strings and the other ones as 0x prefixed hex.
+    * @param {string} host - The host of the agent.
+    * @param {int} port - The port of the agent.
+    * @param {...string} oids - The oids to get.
+    * @returns {string} - The json object of the values.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/snmp');
+    * let c = m.SNMPClient();
+    * let values = JSON.parse(c.Get('localhost', 161, '1.3.6.1.2.1.1.1.0', '1.3.6.1.2.1.1.5.0'));
+    */
+    Get(host, port, ...oids) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Walk returns the values of the subtree of the oid as a json object of the oids to their values. The v1 walks use get next requests and the other ones bulk requests, at most 10000 variables are returned.
+    * @param {string} host - The host of the agent.
+    * @param {int} port - The port of the agent.
+    * @param {string} root - The oid of the subtree.
+    * @returns {string} - The json object of the values.
+    * @throws {error} - If the request is unsuccessful, it returns the error.
+    * @example
+    * let m = require('nuclei/snmp');
+    * let c = m.SNMPClient();
+    * let system = JSON.parse(c.Walk('localhost', 161, '1.3.6.1.2.1.1'));
+    */
+    Walk(host, port, root) {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    SNMPClient: SNMPClient,
+};
@@ -2,7 +2,7 @@
 
 /**
  * @class
- * @classdesc SSHClient is a client for SSH servers. Internally client uses github.com/zmap/zgrab2/lib/ssh driver.
+ * @classdesc SSHClient is a client for SSH servers. Internally client uses github.com/zmap/zgrab2/lib/ssh driver for the handshake logs and golang.org/x/crypto/ssh for the authentication.
  */
 class SSHClient {
     /**
@@ -41,7 +41,7 @@ class SSHClient {
 
     /**
     * @method
-    * @description ConnectWithKey tries to connect to provided host and port with provided username and private_key. Returns state of connection and error. If error is not nil, state will be false.
+    * @description ConnectWithKey tries to connect to provided host and port with provided username and private_key. The PEM and OpenSSH private key formats are supported. Returns state of connection and error. If error is not nil, state will be false.
     * @param {string} host - The host to connect to.
     * @param {number} port - The port to connect to.
     * @param {string} username - The username to use for connection.
@@ -56,6 +56,59 @@ class SSHClient {
     ConnectWithKey(host, port, username, key) {
         // implemented in go
     };
+
+    /**
+    * @method
+    * @description ConnectWithKeyPassphrase tries to connect to provided host and port with provided username and private_key encrypted with the passphrase. Returns state of connection and error. If error is not nil, state will be false.
+    * @param {string} host - The host to connect to.
+    * @param {number} port - The port to connect to.
+    * @param {string} username - The username to use for connection.
+    * @param {string} key - The encrypted private key to use for connection.
+    * @param {string} passphrase - The passphrase of the private key.
+    * @returns {boolean} - The state of the connection.
+    * @throws {error} - The error encountered during connection.
+    * @example
+    * let m = require('nuclei/ssh');
+    * let c = m.SSHClient();
+    * let state = c.ConnectWithKeyPassphrase('localhost', 22, 'user', 'key', 'passphrase');
+    */
+    ConnectWithKeyPassphrase(host, port, username, key, passphrase) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetAlgorithms returns the key exchange, host key, cipher, mac and compression algorithms offered by the server. The algorithms are returned even if the client does not support them.
+    * @param {string} host - The host to connect to.
+    * @param {number} port - The port to connect to.
+    * @returns {Algorithms} - The algorithms offered by the server.
+    * @throws {error} - The error encountered during connection.
+    * @example
+    * let m = require('nuclei/ssh');
+    * let c = m.SSHClient();
+    * let algorithms = c.GetAlgorithms('localhost', 22);
+    * log(algorithms.Weak);
+    */
+    GetAlgorithms(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetHostKey returns the host key of the server along with its fingerprints in the format of ssh-keygen.
+    * @param {string} host - The host to connect to.
+    * @param {number} port - The port to connect to.
+    * @returns {HostKey} - The host key of the server.
+    * @throws {error} - The error encountered during connection.
+    * @example
+    * let m = require('nuclei/ssh');
+    * let c = m.SSHClient();
+    * let key = c.GetHostKey('localhost', 22);
+    * log(key.FingerprintSHA256);
+    */
+    GetHostKey(host, port) {
+        // implemented in go
+    };
 };
 
 /**
@@ -64,6 +117,18 @@ class SSHClient {
  */
 const HandshakeLog = {};
 
+/**
+ * @typedef {object} Algorithms
+ * @description Algorithms is a object containing the Kex, HostKey, Ciphers, MACs and Compressions algorithms offered by the server, Weak contains the offered algorithms considered weak.
+ */
+const Algorithms = {};
+
+/**
+ * @typedef {object} HostKey
+ * @description HostKey is a object containing the Type, the Key in the authorized_keys format, the FingerprintSHA256 and the FingerprintMD5 of the host key.
+ */
+const HostKey = {};
+
 module.exports = {
     SSHClient: SSHClient,
 };
\ No newline at end of file
@@ -3,6 +3,7 @@
 /**
  * @class
  * @classdesc TelnetClient is a minimal Telnet client for nuclei scripts
+ * @property {number} Timeout - The timeout of the prompts in seconds, 5 seconds by default.
  */
 class TelnetClient {
     /**
@@ -20,6 +21,27 @@ class TelnetClient {
     IsTelnet(host, port) {
         // implemented in go
     };
+
+    /**
+    * @method
+    * @description Login negotiates the telnet options and submits the username and the password to the prompts of the server. The login succeeds if a shell prompt (ex: $, # or >) is detected, a failure message, a prompt asked again or the timeout fails the login.
+    * @param {string} host - The host of the Telnet server.
+    * @param {int} port - The port of the Telnet server.
+    * @param {string} username - The username submitted to the login prompt.
+    * @param {string} password - The password submitted to the password prompt.
+    * @returns {LoginResponse} - The result of the login along with the banner and the output of the server.
+    * @throws {error} - The error encountered if the connection fails or no login prompt is detected.
+    * @example
+    * let m = require('nuclei/telnet');
+    * let c = m.TelnetClient();
+    * let response = c.Login('localhost', 23, 'admin', 'admin');
+    * if (response.Success) {
+    *     log(response.Banner);
+    * }
+    */
+    Login(host, port, username, password) {
+        // implemented in go
+    };
 };
 
 /**
@@ -28,6 +50,12 @@ class TelnetClient {
  */
 const IsTelnetResponse = {};
 
+/**
+ * @typedef {object} LoginResponse
+ * @description LoginResponse is an object containing the result of the Login method along with the banner and the output of the server.
+ */
+const LoginResponse = {};
+
 module.exports = {
     TelnetClient: TelnetClient,
 };
\ No newline at end of file
@@ -0,0 +1,193 @@
+/** @module tls */
+
+/**
+ * @class
+ * @classdesc TLSClient opens raw TLS connections with custom handshake parameters. The certificates are not verified, the cipher suites apply to TLS 1.2 and the lower versions only since the TLS 1.3 cipher suites are not configurable.
+ * @property {string} ServerName - The SNI of the handshakes, the host is used if empty.
+ * @property {string[]} ALPN - The application protocols offered to the server, ex: h2 or http/1.1.
+ * @property {string} MinVersion - The minimum TLS version, ex: tls10, tls11, tls12 or tls13.
+ * @property {string} MaxVersion - The maximum TLS version, ex: tls10, tls11, tls12 or tls13.
+ * @property {string[]} Ciphers - The names of the cipher suites offered to the server, ex: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, the defaults are used if empty.
+ * @example
+ * let m = require('nuclei/tls');
+ * let c = m.TLSClient();
+ * c.ServerName = 'internal.example.com';
+ * c.ALPN = ['h2', 'http/1.1'];
+ * c.MaxVersion = 'tls11';
+ */
+class TLSClient {
+    /**
+    * @method
+    * @description Connect opens a TLS connection to the host and port with the parameters of the client, an error is returned if the handshake fails.
+    * @param {string} host - The host to connect to.
+    * @param {number} port - The port to connect to.
+    * @returns {TLSConn} - The TLS connection.
+    * @throws {error} - The error encountered during the connection or the handshake.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    */
+    Connect(host, port) {
+        // implemented in go
+    };
+};
+
+/**
+ * @class
+ * @classdesc TLSConn is a TLS connection to a remote host.
+ */
+class TLSConn {
+    /**
+    * @method
+    * @description Close closes the connection.
+    * @throws {error} - The error encountered during connection closing.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    * conn.Close();
+    */
+    Close() {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Recv receives data from the connection with a timeout. If N is 0, it will read all data sent by the server with 8MB limit.
+    * @param {number} [N=0] - The number of bytes to receive.
+    * @returns {Uint8Array} - The received data in an array.
+    * @throws {error} - The error encountered during data receiving.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    * let data = conn.Recv(1024);
+    */
+    Recv(N) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description RecvHex receives data from the connection with a timeout in hex format. If N is 0, it will read all data sent by the server with 8MB limit.
+    * @param {number} [N=0] - The number of bytes to receive.
+    * @returns {string} - The received data in hex format.
+    * @throws {error} - The error encountered during data receiving.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    * let data = conn.RecvHex(1024);
+    */
+    RecvHex(N) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description RecvString receives data from the connection with a timeout. Output is returned as a string. If N is 0, it will read all data sent by the server with 8MB limit.
+    * @param {number} [N=0] - The number of bytes to receive.
+    * @returns {string} - The received data as a string.
+    * @throws {error} - The error encountered during data receiving.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    * let data = conn.RecvString(1024);
+    */
+    RecvString(N) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Send sends data to the connection with a timeout.
+    * @param {string} data - The data to send.
+    * @throws {error} - The error encountered during data sending.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    * conn.Send('GET / HTTP/1.1\r\nHost: localhost\r\n\r\n');
+    */
+    Send(data) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description SendArray sends array data to connection.
+    * @param {Uint8Array} data - The array data to send.
+    * @throws {error} - The error encountered during data sending.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    * conn.SendArray(new Uint8Array([1, 2, 3]));
+    */
+    SendArray(data) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description SendHex sends hex data to connection.
+    * @param {string} data - The hex data to send.
+    * @throws {error} - The error encountered during data sending.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    * conn.SendHex('010203');
+    */
+    SendHex(data) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description SetTimeout sets read/write timeout for the connection (in seconds).
+    * @param {number} value - The timeout value in seconds.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    * conn.SetTimeout(5);
+    */
+    SetTimeout(value) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description State returns the parameters negotiated by the handshake of the connection.
+    * @returns {ConnectionState} - The negotiated version, cipher, ALPN, server name and the certificates of the server.
+    * @example
+    * let m = require('nuclei/tls');
+    * let c = m.TLSClient();
+    * let conn = c.Connect('localhost', 443);
+    * let state = conn.State();
+    * log(to_json(state));
+    */
+    State() {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} ConnectionState
+ * @description ConnectionState is an object containing the negotiated Version, ex: TLS 1.2, the Cipher, ex: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, the negotiated ALPN protocol, the ServerName sent and the Certificates of the server, the leaf certificate first.
+ */
+const ConnectionState = {};
+
+/**
+ * @typedef {object} Certificate
+ * @description Certificate is an object containing the Subject, Issuer, DNSNames, NotBefore and NotAfter of a certificate of the server.
+ */
+const Certificate = {};
+
+module.exports = {
+    TLSClient: TLSClient,
+    TLSConn: TLSConn,
+};
@@ -20,6 +20,57 @@ class VNCClient {
     IsVNC(host, port) {
         // implemented in go
     };
+
+    /**
+    * @method
+    * @description GetSecurityTypes returns the protocol version and the security types offered by a VNC server, ex: None, VNCAuth or VeNCrypt.
+    * @param {string} host - The host of the VNC server.
+    * @param {number} port - The port of the VNC server.
+    * @returns {SecurityTypesResponse} - The protocol version and the names of the security types offered by the server.
+    * @throws {error} - The error encountered during the handshake.
+    * @example
+    * let m = require('nuclei/vnc');
+    * let c = m.VNCClient();
+    * let response = c.GetSecurityTypes('localhost', 5900);
+    * log(response.SecurityTypes);
+    */
+    GetSecurityTypes(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsPasswordless checks if a VNC server accepts connections without authentication.
+    * @param {string} host - The host of the VNC server.
+    * @param {number} port - The port of the VNC server.
+    * @returns {boolean} - True if the server offers and accepts the None security type.
+    * @throws {error} - The error encountered during the handshake.
+    * @example
+    * let m = require('nuclei/vnc');
+    * let c = m.VNCClient();
+    * let passwordless = c.IsPasswordless('localhost', 5900);
+    */
+    IsPasswordless(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Screenshot captures the framebuffer of a VNC server as a png image, the VNC authentication is used if a password is given.
+    * @param {string} host - The host of the VNC server.
+    * @param {number} port - The port of the VNC server.
+    * @param {string} password - The password of the VNC authentication, empty for passwordless servers.
+    * @returns {ScreenshotResponse} - The name and the size of the desktop along with the png image.
+    * @throws {error} - The error encountered during the authentication or the capture.
+    * @example
+    * let m = require('nuclei/vnc');
+    * let c = m.VNCClient();
+    * let screenshot = c.Screenshot('localhost', 5900, '');
+    * saveArtifact('screenshot.png', screenshot.PNG); // the image is attached to the results
+    */
+    Screenshot(host, port, password) {
+        // implemented in go
+    };
 };
 
 /**
@@ -28,6 +79,18 @@ class VNCClient {
  */
 const IsVNCResponse = {};
 
+/**
+ * @typedef {object} SecurityTypesResponse
+ * @description SecurityTypesResponse is an object containing the protocol version and the security types of the GetSecurityTypes method.
+ */
+const SecurityTypesResponse = {};
+
+/**
+ * @typedef {object} ScreenshotResponse
+ * @description ScreenshotResponse is an object containing the name, the width, the height and the png image of the Screenshot method.
+ */
+const ScreenshotResponse = {};
+
 module.exports = {
     VNCClient: VNCClient,
 };
\ No newline at end of file
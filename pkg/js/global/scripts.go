@@ -2,6 +2,7 @@ package global
 
 import (
 	"bytes"
+	"context"
 	"embed"
 	"math/rand"
 	"net"
@@ -12,6 +13,8 @@ import (
 	"github.com/logrusorgru/aurora"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/kvstore"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	errorutil "github.com/projectdiscovery/utils/errors"
@@ -115,7 +118,9 @@ func initBuiltInFunc(runtime *goja.Runtime) {
 			if len(timeout) > 0 {
 				timeoutInSec = timeout[0]
 			}
-			conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), time.Duration(timeoutInSec)*time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutInSec)*time.Second)
+			defer cancel()
+			conn, err := protocolstate.Dialer.Dial(ctx, "tcp", net.JoinHostPort(host, port))
 			if err != nil {
 				return false, err
 			}
@@ -172,6 +177,50 @@ func initBuiltInFunc(runtime *goja.Runtime) {
 			return runtime.ToValue(buff.String())
 		},
 	})
+
+	// the key-value store is shared by the templates of the scan
+	// it can be invoked as kvSet(key, value, [ttl])
+	// where ttl is optional and values are kept until the end of the scan by default
+	_ = gojs.RegisterFuncWithSignature(runtime, gojs.FuncOpts{
+		Name: "kvSet",
+		Signatures: []string{
+			"kvSet(key string, value string, [ttl int])",
+		},
+		Description: "kvSet stores the value of the key for the other requests and templates of the scan. ttl in seconds is optional and defaults to the end of the scan",
+		FuncDecl: func(key string, value string, ttl ...int) error {
+			ttlInSec := 0
+			if len(ttl) > 0 {
+				ttlInSec = ttl[0]
+			}
+			return kvstore.Default.Set(key, value, time.Duration(ttlInSec)*time.Second)
+		},
+	})
+
+	_ = gojs.RegisterFuncWithSignature(runtime, gojs.FuncOpts{
+		Name: "kvGet",
+		Signatures: []string{
+			"kvGet(key string) string",
+		},
+		Description: "kvGet returns the value of the key stored in the scan or null if it does not exist or is expired",
+		FuncDecl: func(key string) goja.Value {
+			value, ok := kvstore.Default.Get(key)
+			if !ok {
+				return goja.Null()
+			}
+			return runtime.ToValue(value)
+		},
+	})
+
+	_ = gojs.RegisterFuncWithSignature(runtime, gojs.FuncOpts{
+		Name: "kvDelete",
+		Signatures: []string{
+			"kvDelete(key string)",
+		},
+		Description: "kvDelete removes the key stored in the scan",
+		FuncDecl: func(key string) {
+			kvstore.Default.Delete(key)
+		},
+	})
 }
 
 // RegisterNativeScripts are js scripts that were added for convenience
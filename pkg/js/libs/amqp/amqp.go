@@ -0,0 +1,292 @@
+package amqp
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// timeout is the timeout of the connections to the broker
+const timeout = 10 * time.Second
+
+// clientProperties are the properties of the client sent to the broker,
+// the broker reports the authentication failures instead of closing the connection
+var clientProperties = map[string]interface{}{
+	"product": "nuclei",
+	"capabilities": map[string]interface{}{
+		"authentication_failure_close": true,
+	},
+}
+
+// AMQPClient is a client for AMQP 0-9-1 brokers like RabbitMQ.
+//
+// The connections are authenticated with the PLAIN mechanism, internally
+// client implements the AMQP 0-9-1 protocol without the management API.
+type AMQPClient struct {
+	// TLS enables TLS connections, the broker certificate is not verified
+	TLS bool
+	// VHost is the virtual host of the connections, / if empty
+	VHost string
+}
+
+// IsAMQPResponse is the response from the IsAMQP function.
+type IsAMQPResponse struct {
+	IsAMQP      bool
+	Product     string
+	Version     string
+	Platform    string
+	ClusterName string
+	// Mechanisms are the SASL mechanisms supported by the broker
+	Mechanisms []string
+}
+
+// Queue is a queue of a virtual host with its number of messages and consumers.
+type Queue struct {
+	Name      string
+	Messages  uint32
+	Consumers uint32
+}
+
+// serverInfo is the content of the start method of the broker
+type serverInfo struct {
+	properties map[string]interface{}
+	mechanisms []string
+}
+
+// IsAMQP checks if the given host and port are running AMQP broker.
+//
+// The product and version are read from the properties of the broker, no credential is sent.
+func (c *AMQPClient) IsAMQP(host string, port int) (IsAMQPResponse, error) {
+	resp := IsAMQPResponse{}
+
+	conn, err := c.dial(host, port)
+	if err != nil {
+		return resp, err
+	}
+	defer func() { _ = conn.conn.Close() }()
+
+	info, err := conn.start()
+	if errors.Is(err, errProtocolVersion) {
+		resp.IsAMQP = true
+		return resp, nil
+	}
+	if err != nil {
+		return resp, nil
+	}
+	resp.IsAMQP = true
+	resp.Mechanisms = info.mechanisms
+	resp.Product, _ = info.properties["product"].(string)
+	resp.Version, _ = info.properties["version"].(string)
+	resp.Platform, _ = info.properties["platform"].(string)
+	resp.ClusterName, _ = info.properties["cluster_name"].(string)
+	return resp, nil
+}
+
+// Connect connects to AMQP broker using given credentials and opens the virtual host.
+//
+// If connection is successful, it returns true.
+// If the credentials are refused, it returns false.
+// If connection is unsuccessful, it returns false and error.
+func (c *AMQPClient) Connect(host string, port int, username, password string) (bool, error) {
+	conn, err := c.open(host, port, username, password, c.vhost())
+	if err != nil {
+		var amqpErr *amqpError
+		if errors.As(err, &amqpErr) && amqpErr.Code == replyAccessRefused {
+			return false, nil
+		}
+		return false, err
+	}
+	conn.close()
+	return true, nil
+}
+
+// ListVHosts returns the virtual hosts of the list the user can open.
+//
+// The virtual hosts are probed with a connection each, the missing ones
+// and the ones the user is not allowed to access are skipped.
+func (c *AMQPClient) ListVHosts(host string, port int, username, password string, vhosts []string) ([]string, error) {
+	var allowed []string
+	for _, vhost := range vhosts {
+		conn, err := c.open(host, port, username, password, vhost)
+		if err != nil {
+			var amqpErr *amqpError
+			if errors.As(err, &amqpErr) && amqpErr.Code == replyNotAllowed {
+				continue
+			}
+			return nil, err
+		}
+		conn.close()
+		allowed = append(allowed, vhost)
+	}
+	return allowed, nil
+}
+
+// ListQueues returns the queues of the list existing in the virtual host.
+//
+// The queues are declared passively so no queue is created, the missing
+// ones and the ones the user is not allowed to access are skipped.
+func (c *AMQPClient) ListQueues(host string, port int, username, password string, queues []string) ([]Queue, error) {
+	conn, err := c.open(host, port, username, password, c.vhost())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	var found []Queue
+	var opened bool
+	for _, name := range queues {
+		// the channel is opened again after being closed by an error
+		if !opened {
+			if err := conn.writeMethod(1, classChannel, methodChannelOpen, appendShortString(nil, "")); err != nil {
+				return nil, err
+			}
+			if _, err := conn.readMethod(1, classChannel, methodChannelOpenOk); err != nil {
+				return nil, err
+			}
+			opened = true
+		}
+
+		args := appendShortString(binary.BigEndian.AppendUint16(nil, 0), name)
+		args = append(args, 1) // passive
+		args = appendTable(args, nil)
+		if err := conn.writeMethod(1, classQueue, methodDeclare, args); err != nil {
+			return nil, err
+		}
+		r, err := conn.readMethod(1, classQueue, methodDeclareOk)
+		if err != nil {
+			var amqpErr *amqpError
+			if errors.As(err, &amqpErr) && amqpErr.Channel != 0 {
+				opened = false
+				continue
+			}
+			return nil, err
+		}
+		queue := Queue{}
+		if err := r.decode(func() { queue.Name, queue.Messages, queue.Consumers = r.shortString(), r.long(), r.long() }); err != nil {
+			return nil, err
+		}
+		found = append(found, queue)
+	}
+	return found, nil
+}
+
+func (c *AMQPClient) vhost() string {
+	if c.VHost == "" {
+		return "/"
+	}
+	return c.VHost
+}
+
+func (c *AMQPClient) dial(host string, port int) (*amqpConn, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	var conn net.Conn
+	var err error
+	if c.TLS {
+		conn, err = protocolstate.Dialer.DialTLSWithConfig(context.TODO(), "tcp", address, &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10})
+	} else {
+		conn, err = protocolstate.Dialer.Dial(context.TODO(), "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return &amqpConn{conn: conn}, nil
+}
+
+// open dials the broker, authenticates with the credentials and opens the virtual host
+func (c *AMQPClient) open(host string, port int, username, password, vhost string) (*amqpConn, error) {
+	conn, err := c.dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.login(username, password, vhost); err != nil {
+		_ = conn.conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// start sends the protocol header and returns the content of the start method
+func (c *amqpConn) start() (serverInfo, error) {
+	info := serverInfo{}
+	if _, err := c.conn.Write(protocolHeader); err != nil {
+		return info, err
+	}
+	r, err := c.readMethod(0, classConnection, methodStart)
+	if err != nil {
+		return info, err
+	}
+	err = r.decode(func() {
+		if major, minor := r.octet(), r.octet(); major != 0 || minor != 9 {
+			r.fail("unexpected version %d-%d", major, minor)
+		}
+		info.properties = r.table()
+		info.mechanisms = strings.Fields(r.longString())
+	})
+	return info, err
+}
+
+func (c *amqpConn) login(username, password, vhost string) error {
+	info, err := c.start()
+	if err != nil {
+		return err
+	}
+	var plain bool
+	for _, mechanism := range info.mechanisms {
+		plain = plain || mechanism == "PLAIN"
+	}
+	if !plain {
+		return fmt.Errorf("PLAIN mechanism is not supported by the broker")
+	}
+
+	args := appendTable(nil, clientProperties)
+	args = appendShortString(args, "PLAIN")
+	args = appendLongString(args, "\x00"+username+"\x00"+password)
+	args = appendShortString(args, "en_US")
+	if err := c.writeMethod(0, classConnection, methodStartOk, args); err != nil {
+		return err
+	}
+
+	r, err := c.readMethod(0, classConnection, methodTune)
+	if err != nil {
+		return err
+	}
+	var channelMax uint16
+	var frameMax uint32
+	if err := r.decode(func() { channelMax, frameMax = r.short(), r.long() }); err != nil {
+		return err
+	}
+	if frameMax == 0 || frameMax > maxFrameSize {
+		frameMax = maxFrameSize
+	}
+	// the heartbeats are disabled
+	args = binary.BigEndian.AppendUint16(nil, channelMax)
+	args = binary.BigEndian.AppendUint32(args, frameMax)
+	args = binary.BigEndian.AppendUint16(args, 0)
+	if err := c.writeMethod(0, classConnection, methodTuneOk, args); err != nil {
+		return err
+	}
+
+	args = appendShortString(appendShortString(nil, vhost), "")
+	if err := c.writeMethod(0, classConnection, methodOpen, append(args, 0)); err != nil {
+		return err
+	}
+	_, err = c.readMethod(0, classConnection, methodOpenOk)
+	return err
+}
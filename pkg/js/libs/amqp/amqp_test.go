@@ -0,0 +1,201 @@
+package amqp
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker accepts guest:guest on the / and prod virtual hosts,
+// the orders queue exists and the private one is refused
+type fakeBroker struct {
+	t    *testing.T
+	conn *amqpConn
+}
+
+func newFakeBroker(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go (&fakeBroker{t: t, conn: &amqpConn{conn: conn}}).handle()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// next returns the class, method and arguments of the next method
+func (b *fakeBroker) next() (uint16, uint16, uint16, *reader) {
+	kind, channel, payload, err := b.conn.readFrame()
+	if err != nil {
+		return 0, 0, 0, nil
+	}
+	require.Equal(b.t, byte(frameMethod), kind)
+	r := &reader{data: payload}
+	var class, method uint16
+	require.Nil(b.t, r.decode(func() { class, method = r.short(), r.short() }))
+	return channel, class, method, r
+}
+
+func (b *fakeBroker) close(channel, classID, methodID, code uint16, text string) {
+	args := appendShortString(binary.BigEndian.AppendUint16(nil, code), text)
+	require.Nil(b.t, b.conn.writeMethod(channel, classID, methodID, binary.BigEndian.AppendUint32(args, 0)))
+	_, class, method, _ := b.next()
+	require.Equal(b.t, classID, class)
+	require.Equal(b.t, methodID+1, method)
+}
+
+func (b *fakeBroker) handle() {
+	defer b.conn.conn.Close()
+	header := make([]byte, len(protocolHeader))
+	if _, err := io.ReadFull(b.conn.conn, header); err != nil {
+		return
+	}
+	require.Equal(b.t, protocolHeader, header)
+
+	properties := map[string]interface{}{
+		"product":      "RabbitMQ",
+		"version":      "3.13.0",
+		"platform":     "Erlang/OTP 26.2",
+		"cluster_name": "rabbit@test",
+		"capabilities": map[string]interface{}{"publisher_confirms": true},
+	}
+	args := appendTable([]byte{0, 9}, properties)
+	args = appendLongString(appendLongString(args, "PLAIN AMQPLAIN"), "en_US")
+	require.Nil(b.t, b.conn.writeMethod(0, classConnection, methodStart, args))
+
+	_, class, method, r := b.next()
+	if r == nil {
+		return
+	}
+	require.Equal(b.t, [2]uint16{classConnection, methodStartOk}, [2]uint16{class, method})
+	var response string
+	require.Nil(b.t, r.decode(func() {
+		capabilities := r.table()["capabilities"].(map[string]interface{})
+		require.Equal(b.t, true, capabilities["authentication_failure_close"])
+		require.Equal(b.t, "PLAIN", r.shortString())
+		response = r.longString()
+	}))
+	if response != "\x00guest\x00guest" {
+		b.close(0, classConnection, methodClose, replyAccessRefused, "ACCESS_REFUSED - Login was refused")
+		return
+	}
+
+	args = binary.BigEndian.AppendUint16(binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint16(nil, 2047), 131072), 60)
+	require.Nil(b.t, b.conn.writeMethod(0, classConnection, methodTune, args))
+	_, _, method, r = b.next()
+	require.Equal(b.t, uint16(methodTuneOk), method)
+	require.Nil(b.t, r.decode(func() {
+		require.Equal(b.t, uint16(2047), r.short())
+		require.Equal(b.t, uint32(131072), r.long())
+		require.Equal(b.t, uint16(0), r.short())
+	}))
+
+	_, _, method, r = b.next()
+	require.Equal(b.t, uint16(methodOpen), method)
+	var vhost string
+	require.Nil(b.t, r.decode(func() { vhost = r.shortString() }))
+	if vhost != "/" && vhost != "prod" {
+		b.close(0, classConnection, methodClose, replyNotAllowed, "NOT_ALLOWED - vhost "+vhost+" not found")
+		return
+	}
+	require.Nil(b.t, b.conn.writeMethod(0, classConnection, methodOpenOk, appendShortString(nil, "")))
+
+	for {
+		channel, class, method, r := b.next()
+		switch {
+		case r == nil:
+			return
+		case class == classConnection && method == methodClose:
+			require.Nil(b.t, b.conn.writeMethod(0, classConnection, methodCloseOk, nil))
+			return
+		case class == classChannel && method == methodChannelOpen:
+			require.Nil(b.t, b.conn.writeMethod(channel, classChannel, methodChannelOpenOk, appendLongString(nil, "")))
+		case class == classQueue && method == methodDeclare:
+			var queue string
+			require.Nil(b.t, r.decode(func() {
+				r.short()
+				queue = r.shortString()
+				require.Equal(b.t, byte(1), r.octet())
+			}))
+			switch queue {
+			case "orders":
+				args := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(appendShortString(nil, queue), 3), 1)
+				require.Nil(b.t, b.conn.writeMethod(channel, classQueue, methodDeclareOk, args))
+			case "private":
+				b.close(channel, classChannel, methodChannelClose, replyAccessRefused, "ACCESS_REFUSED - access to queue 'private' refused")
+			default:
+				b.close(channel, classChannel, methodChannelClose, 404, "NOT_FOUND - no queue '"+queue+"'")
+			}
+		default:
+			b.t.Errorf("unexpected method %d.%d", class, method)
+			return
+		}
+	}
+}
+
+func TestAMQPClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	port := newFakeBroker(t)
+	client := &AMQPClient{}
+
+	resp, err := client.IsAMQP("127.0.0.1", port)
+	require.Nil(t, err)
+	require.Equal(t, IsAMQPResponse{
+		IsAMQP:      true,
+		Product:     "RabbitMQ",
+		Version:     "3.13.0",
+		Platform:    "Erlang/OTP 26.2",
+		ClusterName: "rabbit@test",
+		Mechanisms:  []string{"PLAIN", "AMQPLAIN"},
+	}, resp)
+
+	ok, err := client.Connect("127.0.0.1", port, "guest", "guest")
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	ok, err = client.Connect("127.0.0.1", port, "guest", "invalid")
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	vhosts, err := client.ListVHosts("127.0.0.1", port, "guest", "guest", []string{"/", "staging", "prod"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"/", "prod"}, vhosts)
+
+	queues, err := client.ListQueues("127.0.0.1", port, "guest", "guest", []string{"missing", "orders", "private", "orders"})
+	require.Nil(t, err)
+	require.Equal(t, []Queue{{Name: "orders", Messages: 3, Consumers: 1}, {Name: "orders", Messages: 3, Consumers: 1}}, queues)
+
+	_, err = (&AMQPClient{VHost: "staging"}).Connect("127.0.0.1", port, "guest", "guest")
+	require.EqualError(t, err, "NOT_ALLOWED - vhost staging not found")
+}
+
+func TestReaderTable(t *testing.T) {
+	fields := appendLongString(append(appendShortString(nil, "name"), 'S'), "value")
+	fields = append(append(appendShortString(fields, "count"), 'I'), 0xff, 0xff, 0xff, 0xfe)
+	fields = append(append(appendShortString(fields, "list"), 'A'), 0, 0, 0, 4, 'b', 1, 't', 1)
+	fields = append(append(appendShortString(fields, "price"), 'D'), 2, 0, 0, 0x01, 0x2c)
+	r := &reader{data: append(binary.BigEndian.AppendUint32(nil, uint32(len(fields))), fields...)}
+
+	var table map[string]interface{}
+	require.Nil(t, r.decode(func() { table = r.table() }))
+	require.Equal(t, map[string]interface{}{
+		"name":  "value",
+		"count": int64(-2),
+		"list":  []interface{}{int64(1), true},
+		"price": 3.0,
+	}, table)
+
+	r = &reader{data: []byte{0, 0, 0, 3, 1, 'k', 'Z'}}
+	require.EqualError(t, r.decode(func() { r.table() }), "could not decode method: unknown field type 'Z'")
+}
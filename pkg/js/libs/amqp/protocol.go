@@ -0,0 +1,321 @@
+package amqp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// protocolHeader is the header of the AMQP 0-9-1 connections
+var protocolHeader = []byte("AMQP\x00\x00\x09\x01")
+
+// frame types
+const (
+	frameMethod = 1
+	frameEnd    = 0xce
+)
+
+// classes and methods of the requests
+const (
+	classConnection = 10
+	classChannel    = 20
+	classQueue      = 50
+
+	methodStart          = 10
+	methodStartOk        = 11
+	methodTune           = 30
+	methodTuneOk         = 31
+	methodOpen           = 40
+	methodOpenOk         = 41
+	methodClose          = 50
+	methodCloseOk        = 51
+	methodChannelOpen    = 10
+	methodChannelOpenOk  = 11
+	methodChannelClose   = 40
+	methodChannelCloseOk = 41
+	methodDeclare        = 10
+	methodDeclareOk      = 11
+)
+
+// reply codes of the close methods
+const (
+	replySuccess       = 200
+	replyAccessRefused = 403
+	replyNotAllowed    = 530
+)
+
+// errProtocolVersion is returned when the broker answers with its protocol header
+var errProtocolVersion = errors.New("unsupported protocol version")
+
+// maxFrameSize is the maximum size of a frame (1MB)
+const maxFrameSize = 1024 * 1024
+
+// amqpError is the reply of a close method of the connection or of a channel
+type amqpError struct {
+	Channel uint16
+	Code    uint16
+	Text    string
+}
+
+func (e *amqpError) Error() string {
+	if e.Text == "" {
+		return fmt.Sprintf("amqp error %d", e.Code)
+	}
+	return e.Text
+}
+
+// amqpConn is a connection to a broker
+type amqpConn struct {
+	conn net.Conn
+}
+
+// writeMethod writes a method frame with the arguments to the channel
+func (c *amqpConn) writeMethod(channel, classID, methodID uint16, args []byte) error {
+	payload := binary.BigEndian.AppendUint16(nil, classID)
+	payload = binary.BigEndian.AppendUint16(payload, methodID)
+	payload = append(payload, args...)
+
+	frame := []byte{frameMethod}
+	frame = binary.BigEndian.AppendUint16(frame, channel)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(payload)))
+	frame = append(append(frame, payload...), frameEnd)
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// readMethod returns the reader of the arguments of the next method of the channel,
+// the close of the connection or of the channel is acknowledged and returned as error
+func (c *amqpConn) readMethod(channel, classID, methodID uint16) (*reader, error) {
+	for {
+		kind, frameChannel, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		// the heartbeats and the methods of the other channels are skipped
+		if kind != frameMethod || (frameChannel != channel && frameChannel != 0) {
+			continue
+		}
+		r := &reader{data: payload}
+		var class, method uint16
+		if err := r.decode(func() { class, method = r.short(), r.short() }); err != nil {
+			return nil, err
+		}
+		switch {
+		case class == classID && method == methodID && frameChannel == channel:
+			return r, nil
+		case class == classConnection && method == methodClose:
+			_ = c.writeMethod(0, classConnection, methodCloseOk, nil)
+			return nil, c.closeError(0, r)
+		case class == classChannel && method == methodChannelClose && frameChannel == channel:
+			if err := c.writeMethod(channel, classChannel, methodChannelCloseOk, nil); err != nil {
+				return nil, err
+			}
+			return nil, c.closeError(channel, r)
+		}
+	}
+}
+
+func (c *amqpConn) closeError(channel uint16, r *reader) error {
+	amqpErr := &amqpError{Channel: channel}
+	if err := r.decode(func() { amqpErr.Code, amqpErr.Text = r.short(), r.shortString() }); err != nil {
+		return err
+	}
+	return amqpErr
+}
+
+func (c *amqpConn) readFrame() (kind byte, channel uint16, payload []byte, err error) {
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return 0, 0, nil, err
+	}
+	// the brokers answer with their protocol header to the unsupported versions
+	if string(header[:4]) == "AMQP" {
+		return 0, 0, nil, errProtocolVersion
+	}
+	size := binary.BigEndian.Uint32(header[3:])
+	if size > maxFrameSize {
+		return 0, 0, nil, fmt.Errorf("invalid frame size %d", size)
+	}
+	payload = make([]byte, size+1)
+	if _, err := io.ReadFull(c.conn, payload); err != nil {
+		return 0, 0, nil, err
+	}
+	if payload[size] != frameEnd {
+		return 0, 0, nil, errors.New("invalid frame end")
+	}
+	return header[0], binary.BigEndian.Uint16(header[1:]), payload[:size], nil
+}
+
+// close closes the connection gracefully
+func (c *amqpConn) close() {
+	args := appendShortString(binary.BigEndian.AppendUint16(nil, replySuccess), "")
+	_ = c.writeMethod(0, classConnection, methodClose, binary.BigEndian.AppendUint32(args, 0))
+	_ = c.conn.Close()
+}
+
+func appendShortString(buf []byte, value string) []byte {
+	if len(value) > math.MaxUint8 {
+		value = value[:math.MaxUint8]
+	}
+	return append(append(buf, byte(len(value))), value...)
+}
+
+func appendLongString(buf []byte, value string) []byte {
+	return append(binary.BigEndian.AppendUint32(buf, uint32(len(value))), value...)
+}
+
+// appendTable appends a field table of strings, booleans and nested tables
+func appendTable(buf []byte, table map[string]interface{}) []byte {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields []byte
+	for _, key := range keys {
+		fields = appendShortString(fields, key)
+		switch value := table[key].(type) {
+		case string:
+			fields = appendLongString(append(fields, 'S'), value)
+		case bool:
+			fields = append(fields, 't', 0)
+			if value {
+				fields[len(fields)-1] = 1
+			}
+		case map[string]interface{}:
+			fields = appendTable(append(fields, 'F'), value)
+		}
+	}
+	return append(binary.BigEndian.AppendUint32(buf, uint32(len(fields))), fields...)
+}
+
+// reader reads the arguments of a method panicking on malformed data
+type reader struct {
+	data []byte
+	pos  int
+}
+
+type decodeError struct {
+	message string
+}
+
+func (e *decodeError) Error() string {
+	return e.message
+}
+
+// decode runs the function and returns its decode error
+func (r *reader) decode(fn func()) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			decodeErr, ok := recovered.(*decodeError)
+			if !ok {
+				panic(recovered)
+			}
+			err = errors.Wrap(decodeErr, "could not decode method")
+		}
+	}()
+	fn()
+	return nil
+}
+
+func (r *reader) fail(format string, args ...interface{}) {
+	panic(&decodeError{message: fmt.Sprintf(format, args...)})
+}
+
+func (r *reader) read(n int) []byte {
+	if n < 0 || len(r.data)-r.pos < n {
+		r.fail("unexpected end of method")
+	}
+	value := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return value
+}
+
+func (r *reader) octet() byte {
+	return r.read(1)[0]
+}
+
+func (r *reader) short() uint16 {
+	return binary.BigEndian.Uint16(r.read(2))
+}
+
+func (r *reader) long() uint32 {
+	return binary.BigEndian.Uint32(r.read(4))
+}
+
+func (r *reader) longLong() uint64 {
+	return binary.BigEndian.Uint64(r.read(8))
+}
+
+func (r *reader) shortString() string {
+	return string(r.read(int(r.octet())))
+}
+
+func (r *reader) longString() string {
+	return string(r.read(int(r.long())))
+}
+
+// table returns the fields of a field table
+func (r *reader) table() map[string]interface{} {
+	fields := &reader{data: r.read(int(r.long()))}
+	table := make(map[string]interface{})
+	for fields.pos < len(fields.data) {
+		key := fields.shortString()
+		table[key] = fields.value()
+	}
+	return table
+}
+
+// value returns the value of a field with the numbers converted to int64 or float64
+func (r *reader) value() interface{} {
+	switch kind := r.octet(); kind {
+	case 't':
+		return r.octet() != 0
+	case 'b':
+		return int64(int8(r.octet()))
+	case 'B':
+		return int64(r.octet())
+	case 's':
+		return int64(int16(r.short()))
+	case 'u':
+		return int64(r.short())
+	case 'I':
+		return int64(int32(r.long()))
+	case 'i':
+		return int64(r.long())
+	case 'l':
+		return int64(r.longLong())
+	// the timestamps are seconds since the epoch
+	case 'T':
+		return int64(r.longLong())
+	case 'f':
+		return float64(math.Float32frombits(r.long()))
+	case 'd':
+		return math.Float64frombits(r.longLong())
+	case 'D':
+		scale := r.octet()
+		return float64(int32(r.long())) / math.Pow10(int(scale))
+	case 'S', 'x':
+		return r.longString()
+	case 'A':
+		values := &reader{data: r.read(int(r.long()))}
+		var array []interface{}
+		for values.pos < len(values.data) {
+			array = append(array, values.value())
+		}
+		return array
+	case 'F':
+		return r.table()
+	case 'V':
+		return nil
+	default:
+		r.fail("unknown field type %q", kind)
+		return nil
+	}
+}
@@ -0,0 +1,181 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout is the timeout of the connections to the database
+	timeout = 10 * time.Second
+	// consistencyOne is the consistency level of the queries
+	consistencyOne = 0x0001
+	// pageSize is the maximum number of rows returned by a query
+	pageSize = 5000
+	// flagPageSize is the query flag of the page size
+	flagPageSize = 0x04
+)
+
+// CassandraClient is a client for Cassandra and ScyllaDB databases.
+//
+// Internally client implements the CQL native protocol v4 with
+// the password authenticator.
+type CassandraClient struct{}
+
+// IsCassandraResponse is the response from the IsCassandra function.
+type IsCassandraResponse struct {
+	IsCassandra bool
+	// Product is either Cassandra or ScyllaDB
+	Product string
+	// CQLVersions are the CQL versions supported by the server
+	CQLVersions []string
+	// Authenticator is the authenticator class of the server, empty if authentication is disabled
+	Authenticator string
+}
+
+// IsCassandra checks if the given host and port are running Cassandra database.
+//
+// If connection is successful, it returns the server information.
+// If connection is unsuccessful, it returns error.
+func (c *CassandraClient) IsCassandra(host string, port int) (IsCassandraResponse, error) {
+	resp := IsCassandraResponse{}
+
+	conn, err := dial(host, port)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.close()
+
+	supported, err := conn.options()
+	if err != nil {
+		return resp, nil
+	}
+	versions, ok := supported["CQL_VERSION"]
+	if !ok {
+		return resp, nil
+	}
+	resp.IsCassandra = true
+	resp.CQLVersions = versions
+	resp.Product = "Cassandra"
+	for key := range supported {
+		if strings.HasPrefix(key, "SCYLLA_") {
+			resp.Product = "ScyllaDB"
+			break
+		}
+	}
+
+	// the authenticator is only known once the connection is started, no credential is sent
+	opCode, r, err := conn.request(opStartup, startupBody())
+	if err == nil && opCode == opAuthenticate {
+		_ = r.decode(func() { resp.Authenticator = r.string() })
+	}
+	return resp, nil
+}
+
+// Connect connects to Cassandra database using given credentials.
+//
+// The connection is successful with any credentials if authentication is disabled.
+//
+// If connection is successful, it returns true.
+// If connection is unsuccessful, it returns false and error.
+func (c *CassandraClient) Connect(host string, port int, username, password string) (bool, error) {
+	conn, err := connect(host, port, username, password)
+	if err != nil {
+		var cqlErr *cqlError
+		if errors.As(err, &cqlErr) && cqlErr.Code == codeBadCredentials {
+			return false, nil
+		}
+		return false, err
+	}
+	conn.close()
+	return true, nil
+}
+
+// ExecuteQuery connects to Cassandra database using given credentials and keyspace
+// and executes a CQL query, returning the rows as json.
+//
+// The keyspace is optional if the tables of the query are qualified,
+// only the first 5000 rows are returned.
+func (c *CassandraClient) ExecuteQuery(host string, port int, username, password, keyspace, query string) (string, error) {
+	conn, err := connect(host, port, username, password)
+	if err != nil {
+		return "", err
+	}
+	defer conn.close()
+
+	if keyspace != "" {
+		if _, err := conn.query(fmt.Sprintf(`USE "%s"`, strings.ReplaceAll(keyspace, `"`, `""`))); err != nil {
+			return "", err
+		}
+	}
+	rows, err := conn.query(query)
+	if err != nil {
+		return "", err
+	}
+	data, err := jsoniter.Marshal(rows)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// query executes the query and returns its rows
+func (c *frameConn) query(query string) ([]interface{}, error) {
+	body := appendLongString(nil, query)
+	body = appendShort(body, consistencyOne)
+	body = append(body, flagPageSize)
+	body = appendInt(body, pageSize)
+
+	opCode, r, err := c.request(opQuery, body)
+	if err != nil {
+		return nil, err
+	}
+	if opCode != opResult {
+		return nil, fmt.Errorf("unexpected query response with op code 0x%02x", opCode)
+	}
+	var rows []interface{}
+	err = r.decode(func() { rows = r.rows() })
+	return rows, err
+}
+
+func dial(host string, port int) (*frameConn, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return &frameConn{conn: conn}, nil
+}
+
+// connect dials the database and starts the connection with the credentials
+func connect(host string, port int, username, password string) (*frameConn, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.startup(username, password); err != nil {
+		conn.close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c *frameConn) close() {
+	_ = c.conn.Close()
+}
@@ -0,0 +1,172 @@
+package cassandra
+
+import (
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a ScyllaDB server with the cassandra:cassandra user
+type fakeServer struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+func (s *fakeServer) serve() {
+	defer s.conn.Close()
+	for {
+		header := make([]byte, 9)
+		if _, err := io.ReadFull(s.conn, header); err != nil {
+			return
+		}
+		body := make([]byte, binary.BigEndian.Uint32(header[5:]))
+		if _, err := io.ReadFull(s.conn, body); err != nil {
+			return
+		}
+		require.Equal(s.t, byte(protocolVersion), header[0])
+
+		opCode, response := s.handle(header[4], &reader{data: body})
+		// responses carry a warning to check that it is skipped
+		frame := []byte{0x80 | protocolVersion, flagWarning, header[2], header[3], opCode, 0, 0, 0, 0}
+		payload := append(appendString(appendShort(nil, 1), "warning"), response...)
+		binary.BigEndian.PutUint32(frame[5:], uint32(len(payload)))
+		if _, err := s.conn.Write(append(frame, payload...)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) handle(opCode byte, r *reader) (byte, []byte) {
+	switch opCode {
+	case opOptions:
+		supported := appendShort(nil, 2)
+		supported = appendShort(appendString(supported, "CQL_VERSION"), 1)
+		supported = appendString(supported, "3.3.1")
+		supported = appendShort(appendString(supported, "SCYLLA_SHARD"), 1)
+		return opSupported, appendString(supported, "0")
+	case opStartup:
+		return opAuthenticate, appendString(nil, "org.apache.cassandra.auth.PasswordAuthenticator")
+	case opAuthResponse:
+		if string(r.bytes()) != "\x00cassandra\x00cassandra" {
+			return opError, appendString(appendInt(nil, codeBadCredentials), "Provided username and/or password are incorrect")
+		}
+		return opAuthSuccess, appendBytes(nil, nil)
+	case opQuery:
+		query := string(r.read(int(r.int())))
+		if query == `USE "system_auth"` {
+			return opResult, appendString(appendInt(nil, 0x0003), "system_auth")
+		}
+		require.Equal(s.t, "SELECT * FROM roles", query)
+		return opResult, rowsResult()
+	}
+	return opError, appendString(appendInt(nil, 0x000A), "unsupported op code")
+}
+
+// rowsResult returns a result of two rows with text, boolean, int, list and map columns
+func rowsResult() []byte {
+	result := appendInt(nil, resultRows)
+	result = appendInt(result, flagGlobalTablesSpec)
+	result = appendInt(result, 5)
+	result = appendString(appendString(result, "system_auth"), "roles")
+	result = appendShort(appendString(result, "role"), typeVarchar)
+	result = appendShort(appendString(result, "is_superuser"), typeBoolean)
+	result = appendShort(appendString(result, "salted"), typeInt)
+	result = appendShort(appendShort(appendString(result, "member_of"), typeSet), typeVarchar)
+	result = appendShort(appendShort(appendShort(appendString(result, "options"), typeMap), typeVarchar), typeBigint)
+
+	set := appendBytes(appendInt(nil, 1), []byte("admins"))
+	options := appendBytes(appendBytes(appendInt(nil, 1), []byte("limit")), binary.BigEndian.AppendUint64(nil, 10))
+
+	result = appendInt(result, 2)
+	result = appendBytes(result, []byte("cassandra"))
+	result = appendBytes(result, []byte{1})
+	result = appendBytes(result, binary.BigEndian.AppendUint32(nil, 42))
+	result = appendBytes(result, set)
+	result = appendBytes(result, options)
+	for i := 0; i < 5; i++ {
+		result = appendBytes(result, nil)
+	}
+	return result
+}
+
+func newFakeConn(t *testing.T) *frameConn {
+	client, server := net.Pipe()
+	go (&fakeServer{t: t, conn: server}).serve()
+	t.Cleanup(func() { _ = client.Close() })
+	return &frameConn{conn: client}
+}
+
+func TestFrameConn(t *testing.T) {
+	conn := newFakeConn(t)
+	supported, err := conn.options()
+	require.Nil(t, err)
+	require.Equal(t, []string{"3.3.1"}, supported["CQL_VERSION"])
+
+	authenticator, err := conn.startup("cassandra", "cassandra")
+	require.Nil(t, err)
+	require.Equal(t, "org.apache.cassandra.auth.PasswordAuthenticator", authenticator)
+
+	rows, err := conn.query(`USE "system_auth"`)
+	require.Nil(t, err)
+	require.Empty(t, rows)
+
+	rows, err = conn.query("SELECT * FROM roles")
+	require.Nil(t, err)
+	data, err := jsoniter.Marshal(rows)
+	require.Nil(t, err)
+	require.JSONEq(t, `[
+		{"role": "cassandra", "is_superuser": true, "salted": 42, "member_of": ["admins"], "options": {"limit": 10}},
+		{"role": "", "is_superuser": false, "salted": 0, "member_of": null, "options": null}
+	]`, string(data))
+
+	conn = newFakeConn(t)
+	_, err = conn.startup("cassandra", "invalid")
+	var cqlErr *cqlError
+	require.ErrorAs(t, err, &cqlErr)
+	require.Equal(t, int32(codeBadCredentials), cqlErr.Code)
+}
+
+func TestDecodeValue(t *testing.T) {
+	tests := []struct {
+		kind     cqlType
+		data     []byte
+		expected interface{}
+	}{
+		{kind: cqlType{id: typeBigint}, data: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}, expected: int64(-2)},
+		{kind: cqlType{id: typeBlob}, data: []byte{0xca, 0xfe}, expected: "0xcafe"},
+		{kind: cqlType{id: typeDecimal}, data: []byte{0, 0, 0, 2, 0x04, 0xd2}, expected: "12.34"},
+		{kind: cqlType{id: typeDecimal}, data: []byte{0, 0, 0, 3, 0xfb}, expected: "-0.005"},
+		{kind: cqlType{id: typeUUID}, data: []byte{0x55, 0x0e, 0x84, 0x00, 0xe2, 0x9b, 0x41, 0xd4, 0xa7, 0x16, 0x44, 0x66, 0x55, 0x44, 0x00, 0x00}, expected: "550e8400-e29b-41d4-a716-446655440000"},
+		{kind: cqlType{id: typeVarint}, data: []byte{0xff, 0x7f}, expected: int64(-129)},
+		{kind: cqlType{id: typeInet}, data: []byte{10, 0, 0, 1}, expected: "10.0.0.1"},
+		{kind: cqlType{id: typeDate}, data: []byte{0x80, 0x00, 0x00, 0x01}, expected: "1970-01-02"},
+		{kind: cqlType{id: typeTime}, data: binary.BigEndian.AppendUint64(nil, 3723000000001), expected: "01:02:03.000000001"},
+		{kind: cqlType{id: typeTimestamp}, data: binary.BigEndian.AppendUint64(nil, 1700000000123), expected: "2023-11-14T22:13:20.123Z"},
+		{kind: cqlType{id: typeDuration}, data: []byte{0x02, 0x03, 0x81, 0x00}, expected: "1mo-2d128ns"},
+		{
+			kind:     cqlType{id: typeTuple, elements: []cqlType{{id: typeTinyint}, {id: typeVarchar}}},
+			data:     appendBytes(appendBytes(nil, []byte{0xff}), []byte("a")),
+			expected: []interface{}{int8(-1), "a"},
+		},
+		{
+			kind:     cqlType{id: typeUDT, fields: []string{"street", "zip"}, elements: []cqlType{{id: typeVarchar}, {id: typeInt}}},
+			data:     appendBytes(nil, []byte("main")),
+			expected: map[string]interface{}{"street": "main"},
+		},
+	}
+	for _, test := range tests {
+		r := &reader{}
+		var value interface{}
+		require.Nil(t, r.decode(func() { value = decodeValue(r, test.kind, test.data) }))
+		require.Equal(t, test.expected, value, "could not decode type 0x%04x", test.kind.id)
+	}
+
+	r := &reader{}
+	require.Error(t, r.decode(func() { decodeValue(r, cqlType{id: typeInt}, []byte{1}) }), "could decode invalid int")
+	require.Equal(t, "15E2", formatDecimal(-2, big.NewInt(15)))
+}
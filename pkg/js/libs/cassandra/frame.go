@@ -0,0 +1,273 @@
+package cassandra
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// protocolVersion is the native protocol version of the requests (Cassandra 2.2+, ScyllaDB)
+const protocolVersion = 0x04
+
+// maxFrameSize is the maximum size of a response body (256MB as the server default)
+const maxFrameSize = 256 * 1024 * 1024
+
+// frame op codes
+const (
+	opError         = 0x00
+	opStartup       = 0x01
+	opReady         = 0x02
+	opAuthenticate  = 0x03
+	opOptions       = 0x05
+	opSupported     = 0x06
+	opQuery         = 0x07
+	opResult        = 0x08
+	opAuthChallenge = 0x0E
+	opAuthResponse  = 0x0F
+	opAuthSuccess   = 0x10
+)
+
+// response frame flags
+const (
+	flagTracing       = 0x02
+	flagCustomPayload = 0x04
+	flagWarning       = 0x08
+)
+
+// codeBadCredentials is the error code of an authentication failure
+const codeBadCredentials = 0x0100
+
+// cqlError is an error returned by the server
+type cqlError struct {
+	Code    int32
+	Message string
+}
+
+func (e *cqlError) Error() string {
+	return fmt.Sprintf("server error 0x%04x: %s", e.Code, e.Message)
+}
+
+// frameConn is a connection speaking the CQL native protocol
+type frameConn struct {
+	conn   net.Conn
+	stream int16
+}
+
+// request sends a request frame and returns the op code and body of the response,
+// error responses are returned as cqlError
+func (c *frameConn) request(opCode byte, body []byte) (byte, *reader, error) {
+	c.stream++
+	header := make([]byte, 9, 9+len(body))
+	header[0], header[4] = protocolVersion, opCode
+	binary.BigEndian.PutUint16(header[2:], uint16(c.stream))
+	binary.BigEndian.PutUint32(header[5:], uint32(len(body)))
+	if _, err := c.conn.Write(append(header, body...)); err != nil {
+		return 0, nil, err
+	}
+
+	if _, err := io.ReadFull(c.conn, header[:9]); err != nil {
+		return 0, nil, err
+	}
+	if header[0] != 0x80|protocolVersion {
+		return 0, nil, errors.Errorf("unsupported protocol version 0x%02x", header[0])
+	}
+	if stream := int16(binary.BigEndian.Uint16(header[2:])); stream != c.stream {
+		return 0, nil, errors.Errorf("unexpected response to stream %d", stream)
+	}
+	length := binary.BigEndian.Uint32(header[5:])
+	if length > maxFrameSize {
+		return 0, nil, errors.Errorf("invalid frame length %d", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.conn, data); err != nil {
+		return 0, nil, err
+	}
+
+	r := &reader{data: data}
+	err := r.decode(func() {
+		flags := header[1]
+		if flags&flagTracing != 0 {
+			r.read(16)
+		}
+		if flags&flagWarning != 0 {
+			r.stringList()
+		}
+		if flags&flagCustomPayload != 0 {
+			for i := r.short(); i > 0; i-- {
+				r.string()
+				r.bytes()
+			}
+		}
+		if header[4] == opError {
+			code := r.int()
+			panic(&cqlError{Code: code, Message: r.string()})
+		}
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return header[4], r, nil
+}
+
+// startup initializes the connection and authenticates with the password
+// authenticator if required by the server. It returns the authenticator
+// class of the server, empty if authentication is not required.
+func (c *frameConn) startup(username, password string) (string, error) {
+	opCode, r, err := c.request(opStartup, startupBody())
+	if err != nil {
+		return "", err
+	}
+	switch opCode {
+	case opReady:
+		return "", nil
+	case opAuthenticate:
+	default:
+		return "", errors.Errorf("unexpected startup response with op code 0x%02x", opCode)
+	}
+	var authenticator string
+	if err := r.decode(func() { authenticator = r.string() }); err != nil {
+		return "", err
+	}
+
+	// the sasl plain token is supported by the password authenticators of cassandra, scylla and dse
+	token := []byte("\x00" + username + "\x00" + password)
+	opCode, _, err = c.request(opAuthResponse, appendBytes(nil, token))
+	if err != nil {
+		return authenticator, err
+	}
+	if opCode != opAuthSuccess {
+		return authenticator, errors.Errorf("unexpected authentication response with op code 0x%02x", opCode)
+	}
+	return authenticator, nil
+}
+
+func startupBody() []byte {
+	return appendStringMap(nil, map[string]string{"CQL_VERSION": "3.0.0"})
+}
+
+// options returns the supported options of the server
+func (c *frameConn) options() (map[string][]string, error) {
+	opCode, r, err := c.request(opOptions, nil)
+	if err != nil {
+		return nil, err
+	}
+	if opCode != opSupported {
+		return nil, errors.Errorf("unexpected options response with op code 0x%02x", opCode)
+	}
+	supported := make(map[string][]string)
+	err = r.decode(func() {
+		for i := r.short(); i > 0; i-- {
+			key := r.string()
+			supported[key] = r.stringList()
+		}
+	})
+	return supported, err
+}
+
+func appendShort(buf []byte, value uint16) []byte {
+	return binary.BigEndian.AppendUint16(buf, value)
+}
+
+func appendInt(buf []byte, value int32) []byte {
+	return binary.BigEndian.AppendUint32(buf, uint32(value))
+}
+
+func appendString(buf []byte, value string) []byte {
+	return append(appendShort(buf, uint16(len(value))), value...)
+}
+
+func appendLongString(buf []byte, value string) []byte {
+	return append(appendInt(buf, int32(len(value))), value...)
+}
+
+func appendBytes(buf []byte, value []byte) []byte {
+	if value == nil {
+		return appendInt(buf, -1)
+	}
+	return append(appendInt(buf, int32(len(value))), value...)
+}
+
+func appendStringMap(buf []byte, values map[string]string) []byte {
+	buf = appendShort(buf, uint16(len(values)))
+	for key, value := range values {
+		buf = appendString(appendString(buf, key), value)
+	}
+	return buf
+}
+
+// reader reads the values of a frame body panicking on malformed data
+type reader struct {
+	data []byte
+	pos  int
+}
+
+type decodeError struct {
+	message string
+}
+
+func (e *decodeError) Error() string {
+	return e.message
+}
+
+// decode runs the function and returns its decode or server error
+func (r *reader) decode(fn func()) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			switch value := recovered.(type) {
+			case *decodeError:
+				err = errors.Wrap(value, "could not decode frame")
+			case *cqlError:
+				err = value
+			default:
+				panic(recovered)
+			}
+		}
+	}()
+	fn()
+	return nil
+}
+
+func (r *reader) fail(format string, args ...interface{}) {
+	panic(&decodeError{message: fmt.Sprintf(format, args...)})
+}
+
+func (r *reader) read(n int) []byte {
+	if n < 0 || len(r.data)-r.pos < n {
+		r.fail("unexpected end of frame")
+	}
+	value := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return value
+}
+
+func (r *reader) short() int {
+	return int(binary.BigEndian.Uint16(r.read(2)))
+}
+
+func (r *reader) int() int32 {
+	return int32(binary.BigEndian.Uint32(r.read(4)))
+}
+
+func (r *reader) string() string {
+	return string(r.read(r.short()))
+}
+
+func (r *reader) stringList() []string {
+	values := make([]string, r.short())
+	for i := range values {
+		values[i] = r.string()
+	}
+	return values
+}
+
+// bytes returns the value of a [bytes], nil if null
+func (r *reader) bytes() []byte {
+	length := r.int()
+	if length < 0 {
+		return nil
+	}
+	return r.read(int(length))
+}
@@ -0,0 +1,292 @@
+package cassandra
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+// cql type ids
+const (
+	typeCustom    = 0x0000
+	typeASCII     = 0x0001
+	typeBigint    = 0x0002
+	typeBlob      = 0x0003
+	typeBoolean   = 0x0004
+	typeCounter   = 0x0005
+	typeDecimal   = 0x0006
+	typeDouble    = 0x0007
+	typeFloat     = 0x0008
+	typeInt       = 0x0009
+	typeText      = 0x000A
+	typeTimestamp = 0x000B
+	typeUUID      = 0x000C
+	typeVarchar   = 0x000D
+	typeVarint    = 0x000E
+	typeTimeUUID  = 0x000F
+	typeInet      = 0x0010
+	typeDate      = 0x0011
+	typeTime      = 0x0012
+	typeSmallint  = 0x0013
+	typeTinyint   = 0x0014
+	typeDuration  = 0x0015
+	typeList      = 0x0020
+	typeMap       = 0x0021
+	typeSet       = 0x0022
+	typeUDT       = 0x0030
+	typeTuple     = 0x0031
+)
+
+// result kinds
+const (
+	resultRows = 0x0002
+)
+
+// rows metadata flags
+const (
+	flagGlobalTablesSpec = 0x0001
+	flagHasMorePages     = 0x0002
+	flagNoMetadata       = 0x0004
+)
+
+// maxTypeDepth is the maximum nesting of the column types
+const maxTypeDepth = 32
+
+// cqlType is the type of a column
+type cqlType struct {
+	id uint16
+	// elements are the types of the collection, tuple or udt elements
+	elements []cqlType
+	// fields are the field names of the udt
+	fields []string
+}
+
+type column struct {
+	name string
+	kind cqlType
+}
+
+func (r *reader) option(depth int) cqlType {
+	if depth > maxTypeDepth {
+		r.fail("type nesting exceeds %d", maxTypeDepth)
+	}
+	t := cqlType{id: uint16(r.short())}
+	switch t.id {
+	case typeCustom:
+		r.string()
+	case typeList, typeSet:
+		t.elements = []cqlType{r.option(depth + 1)}
+	case typeMap:
+		t.elements = []cqlType{r.option(depth + 1), r.option(depth + 1)}
+	case typeUDT:
+		r.string()
+		r.string()
+		for i := r.short(); i > 0; i-- {
+			t.fields = append(t.fields, r.string())
+			t.elements = append(t.elements, r.option(depth+1))
+		}
+	case typeTuple:
+		for i := r.short(); i > 0; i-- {
+			t.elements = append(t.elements, r.option(depth+1))
+		}
+	}
+	return t
+}
+
+// rows decodes the rows of a result, other results have no rows
+func (r *reader) rows() []interface{} {
+	rows := []interface{}{}
+	if r.int() != resultRows {
+		return rows
+	}
+
+	flags := r.int()
+	count := r.int()
+	// the count is bounded by the frame size as each column takes several bytes
+	if count < 0 || int(count) > len(r.data)-r.pos {
+		r.fail("invalid columns count %d", count)
+	}
+	columns := make([]column, count)
+	if flags&flagHasMorePages != 0 {
+		r.bytes()
+	}
+	if flags&flagNoMetadata != 0 {
+		r.fail("rows without metadata")
+	}
+	if flags&flagGlobalTablesSpec != 0 {
+		r.string()
+		r.string()
+	}
+	for i := range columns {
+		if flags&flagGlobalTablesSpec == 0 {
+			r.string()
+			r.string()
+		}
+		columns[i] = column{name: r.string(), kind: r.option(0)}
+	}
+
+	for i := r.int(); i > 0; i-- {
+		row := make(map[string]interface{}, len(columns))
+		for _, column := range columns {
+			row[column.name] = decodeValue(r, column.kind, r.bytes())
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// decodeValue decodes a value to a json value, null scalars are decoded as zero values
+func decodeValue(r *reader, t cqlType, data []byte) interface{} {
+	if data == nil {
+		switch t.id {
+		case typeASCII, typeText, typeVarchar, typeUUID, typeTimeUUID, typeTimestamp, typeInet, typeDate, typeTime, typeBlob, typeDecimal, typeDuration, typeCustom:
+			return ""
+		case typeBoolean:
+			return false
+		case typeBigint, typeCounter, typeInt, typeSmallint, typeTinyint, typeVarint, typeDouble, typeFloat:
+			return 0
+		}
+		return nil
+	}
+
+	fixed := func(size int) []byte {
+		if len(data) != size {
+			r.fail("invalid value length %d for type 0x%04x", len(data), t.id)
+		}
+		return data
+	}
+	switch t.id {
+	case typeASCII, typeText, typeVarchar:
+		return string(data)
+	case typeBigint, typeCounter:
+		return int64(binary.BigEndian.Uint64(fixed(8)))
+	case typeBoolean:
+		return fixed(1)[0] != 0
+	case typeDecimal:
+		if len(data) < 4 {
+			r.fail("invalid decimal length %d", len(data))
+		}
+		return formatDecimal(int32(binary.BigEndian.Uint32(data)), varint(data[4:]))
+	case typeDouble:
+		return math.Float64frombits(binary.BigEndian.Uint64(fixed(8)))
+	case typeFloat:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(fixed(4))))
+	case typeInt:
+		return int32(binary.BigEndian.Uint32(fixed(4)))
+	case typeTimestamp:
+		return time.UnixMilli(int64(binary.BigEndian.Uint64(fixed(8)))).UTC().Format(time.RFC3339Nano)
+	case typeUUID, typeTimeUUID:
+		value := hex.EncodeToString(fixed(16))
+		return strings.Join([]string{value[:8], value[8:12], value[12:16], value[16:20], value[20:]}, "-")
+	case typeVarint:
+		value := varint(data)
+		if value.IsInt64() {
+			return value.Int64()
+		}
+		return value.String()
+	case typeInet:
+		if len(data) != net.IPv4len && len(data) != net.IPv6len {
+			r.fail("invalid inet length %d", len(data))
+		}
+		return net.IP(data).String()
+	case typeDate:
+		days := int64(binary.BigEndian.Uint32(fixed(4))) - 1<<31
+		return time.Unix(days*24*60*60, 0).UTC().Format("2006-01-02")
+	case typeTime:
+		nanoseconds := int64(binary.BigEndian.Uint64(fixed(8)))
+		return time.Unix(0, nanoseconds).UTC().Format("15:04:05.000000000")
+	case typeSmallint:
+		return int16(binary.BigEndian.Uint16(fixed(2)))
+	case typeTinyint:
+		return int8(fixed(1)[0])
+	case typeDuration:
+		values := &reader{data: data}
+		months, days, nanoseconds := values.vint(), values.vint(), values.vint()
+		return fmt.Sprintf("%dmo%dd%dns", months, days, nanoseconds)
+	case typeList, typeSet:
+		values := &reader{data: data}
+		items := make([]interface{}, 0)
+		for i := values.int(); i > 0; i-- {
+			items = append(items, decodeValue(values, t.elements[0], values.bytes()))
+		}
+		return items
+	case typeMap:
+		values := &reader{data: data}
+		items := make(map[string]interface{})
+		for i := values.int(); i > 0; i-- {
+			key := decodeValue(values, t.elements[0], values.bytes())
+			items[fmt.Sprint(key)] = decodeValue(values, t.elements[1], values.bytes())
+		}
+		return items
+	case typeUDT:
+		values := &reader{data: data}
+		fields := make(map[string]interface{}, len(t.fields))
+		// the serialized value may omit the trailing fields
+		for i, name := range t.fields {
+			if values.pos == len(data) {
+				break
+			}
+			fields[name] = decodeValue(values, t.elements[i], values.bytes())
+		}
+		return fields
+	case typeTuple:
+		values := &reader{data: data}
+		items := make([]interface{}, len(t.elements))
+		for i, element := range t.elements {
+			items[i] = decodeValue(values, element, values.bytes())
+		}
+		return items
+	}
+	// custom types are returned as cqlsh formats blobs
+	return "0x" + hex.EncodeToString(data)
+}
+
+// varint decodes a two's complement big endian integer
+func varint(data []byte) *big.Int {
+	value := new(big.Int).SetBytes(data)
+	if len(data) > 0 && data[0]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(len(data)*8)))
+	}
+	return value
+}
+
+// maxDecimalZeros is the maximum number of leading zeros of a decimal before the scientific notation
+const maxDecimalZeros = 20
+
+func formatDecimal(scale int32, unscaled *big.Int) string {
+	sign := ""
+	if unscaled.Sign() < 0 {
+		sign = "-"
+		unscaled = new(big.Int).Neg(unscaled)
+	}
+	digits := unscaled.String()
+	switch {
+	case scale == 0:
+		return sign + digits
+	case scale < 0 || int(scale) > len(digits)+maxDecimalZeros:
+		return fmt.Sprintf("%s%sE%d", sign, digits, -scale)
+	case int(scale) >= len(digits):
+		return sign + "0." + strings.Repeat("0", int(scale)-len(digits)) + digits
+	}
+	point := len(digits) - int(scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// vint reads a zigzag encoded variable length integer
+func (r *reader) vint() int64 {
+	first := r.read(1)[0]
+	extra := 0
+	for mask := byte(0x80); first&mask != 0 && extra < 8; mask >>= 1 {
+		extra++
+	}
+	value := uint64(first) & (0xff >> uint(extra))
+	for _, b := range r.read(extra) {
+		value = value<<8 | uint64(b)
+	}
+	return int64(value>>1) ^ -int64(value&1)
+}
@@ -0,0 +1,159 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/httpapi"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// defaultSampleSize is the default number of documents sampled
+	defaultSampleSize = 10
+	// maxSampleSize is the maximum number of documents sampled
+	maxSampleSize = 1000
+)
+
+// CouchDBClient is a client for CouchDB servers.
+//
+// Requests are authenticated with basic auth if the username
+// is set and are unauthenticated otherwise.
+type CouchDBClient struct {
+	// HTTPS enables TLS connections, the server certificate is not verified
+	HTTPS bool
+	// Username is the username of the basic authentication
+	Username string
+	// Password is the password of the basic authentication
+	Password string
+}
+
+// IsCouchDBResponse is the response from the IsCouchDB function.
+type IsCouchDBResponse struct {
+	IsCouchDB bool
+	Version   string
+	Vendor    string
+}
+
+// IsCouchDB checks if the given host and port are running CouchDB.
+//
+// The welcome message of the root endpoint is used, it does not require authentication.
+func (c *CouchDBClient) IsCouchDB(host string, port int) (IsCouchDBResponse, error) {
+	resp := IsCouchDBResponse{}
+
+	data, err := c.request(host, port, "/", true)
+	if err != nil {
+		return resp, err
+	}
+	var info struct {
+		CouchDB string `json:"couchdb"`
+		Version string `json:"version"`
+		Vendor  struct {
+			Name string `json:"name"`
+		} `json:"vendor"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil || info.CouchDB != "Welcome" {
+		return resp, nil
+	}
+	resp.IsCouchDB = true
+	resp.Version = info.Version
+	resp.Vendor = info.Vendor.Name
+	return resp, nil
+}
+
+// IsAdminParty checks if the server grants the admin role to unauthenticated users.
+//
+// The servers without admin account before CouchDB 3 run in admin party,
+// the session is requested without the credentials of the client.
+func (c *CouchDBClient) IsAdminParty(host string, port int) (bool, error) {
+	data, err := (&CouchDBClient{HTTPS: c.HTTPS}).request(host, port, "/_session", true)
+	if err != nil {
+		return false, err
+	}
+	var session struct {
+		UserCtx struct {
+			Roles []string `json:"roles"`
+		} `json:"userCtx"`
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return false, fmt.Errorf("could not parse session: %w", err)
+	}
+	for _, role := range session.UserCtx.Roles {
+		if role == "_admin" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListDatabases returns the names of the databases of the server.
+//
+// Listing the databases requires the admin role since CouchDB 3.
+func (c *CouchDBClient) ListDatabases(host string, port int) ([]string, error) {
+	data, err := c.request(host, port, "/_all_dbs", false)
+	if err != nil {
+		return nil, err
+	}
+	var databases []string
+	if err := json.Unmarshal(data, &databases); err != nil {
+		return nil, fmt.Errorf("could not parse databases: %w", err)
+	}
+	return databases, nil
+}
+
+// SampleDocuments returns at most limit documents of the database as json,
+// 10 documents if not positive and at most 1000.
+//
+// The response of the _all_docs endpoint is returned with the documents included.
+func (c *CouchDBClient) SampleDocuments(host string, port int, database string, limit int) (string, error) {
+	if database == "" {
+		return "", fmt.Errorf("database is required")
+	}
+	if limit <= 0 {
+		limit = defaultSampleSize
+	}
+	limit = min(limit, maxSampleSize)
+	data, err := c.request(host, port, "/"+url.PathEscape(database)+"/_all_docs?include_docs=true&limit="+strconv.Itoa(limit), false)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// request sends a get request to the server and returns the response body,
+// the status code is not checked if anyStatus is true
+func (c *CouchDBClient) request(host string, port int, path string, anyStatus bool) ([]byte, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	scheme := "http"
+	if c.HTTPS {
+		scheme = "https"
+	}
+	target := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)), path)
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := httpapi.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return httpapi.ReadBody(resp, anyStatus)
+}
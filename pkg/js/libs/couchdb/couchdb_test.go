@@ -0,0 +1,87 @@
+package couchdb
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T, adminParty bool) (string, int) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		admin := adminParty || (username == "admin" && password == "password")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"couchdb": "Welcome", "version": "3.3.2", "vendor": {"name": "The Apache Software Foundation"}}`))
+			return
+		case "/_session":
+			if admin {
+				_, _ = w.Write([]byte(`{"ok": true, "userCtx": {"name": null, "roles": ["_admin"]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok": true, "userCtx": {"name": null, "roles": []}}`))
+			return
+		}
+		if !admin {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "unauthorized", "reason": "Authentication required."}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/_all_dbs":
+			_, _ = w.Write([]byte(`["_users", "orders"]`))
+		case "/orders/_all_docs":
+			require.Equal(t, "true", r.URL.Query().Get("include_docs"))
+			require.Equal(t, "2", r.URL.Query().Get("limit"))
+			_, _ = w.Write([]byte(`{"total_rows": 1, "offset": 0, "rows": [{"id": "1", "doc": {"_id": "1", "total": 5}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "not_found", "reason": "Database does not exist."}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	host, portValue, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	return host, port
+}
+
+func TestCouchDBClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	host, port := newServer(t, false)
+
+	client := &CouchDBClient{Username: "admin", Password: "password"}
+	info, err := client.IsCouchDB(host, port)
+	require.Nil(t, err)
+	require.Equal(t, IsCouchDBResponse{IsCouchDB: true, Version: "3.3.2", Vendor: "The Apache Software Foundation"}, info)
+
+	adminParty, err := client.IsAdminParty(host, port)
+	require.Nil(t, err)
+	require.False(t, adminParty)
+
+	databases, err := client.ListDatabases(host, port)
+	require.Nil(t, err)
+	require.Equal(t, []string{"_users", "orders"}, databases)
+
+	documents, err := client.SampleDocuments(host, port, "orders", 2)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"total_rows": 1, "offset": 0, "rows": [{"id": "1", "doc": {"_id": "1", "total": 5}}]}`, documents)
+
+	_, err = client.SampleDocuments(host, port, "missing", 2)
+	require.EqualError(t, err, `unexpected status code 404: {"error": "not_found", "reason": "Database does not exist."}`)
+
+	_, err = (&CouchDBClient{}).ListDatabases(host, port)
+	require.EqualError(t, err, `unexpected status code 401: {"error": "unauthorized", "reason": "Authentication required."}`)
+
+	host, port = newServer(t, true)
+	adminParty, err = (&CouchDBClient{}).IsAdminParty(host, port)
+	require.Nil(t, err)
+	require.True(t, adminParty)
+}
@@ -0,0 +1,240 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/httpapi"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"golang.org/x/net/http2"
+)
+
+// criVersion is the version of the CRI api of the requests
+const criVersion = "v1"
+
+// containerStates are the names of the CRI container states
+var containerStates = map[uint64]string{
+	0: "created",
+	1: "running",
+	2: "exited",
+	3: "unknown",
+}
+
+// CRIClient is a client for the CRI api of container runtimes like containerd and CRI-O.
+//
+// The runtimes listening on tcp do not authenticate the requests,
+// internally client sends the gRPC requests over plain text HTTP/2.
+type CRIClient struct{}
+
+// CRIVersionResponse is the response from the Version function.
+type CRIVersionResponse struct {
+	Version           string
+	RuntimeName       string
+	RuntimeVersion    string
+	RuntimeAPIVersion string
+}
+
+// CRIContainer is a container of the runtime.
+type CRIContainer struct {
+	ID    string
+	Name  string
+	Image string
+	// State is either created, running, exited or unknown
+	State string
+}
+
+// CRIImage is an image of the runtime.
+type CRIImage struct {
+	ID       string
+	RepoTags []string
+	Size     uint64
+}
+
+// Version returns the name and version of the runtime.
+func (c *CRIClient) Version(host string, port int) (CRIVersionResponse, error) {
+	resp := CRIVersionResponse{}
+	data, err := c.call(host, port, "runtime.v1.RuntimeService/Version", appendProtoString(nil, 1, criVersion))
+	if err != nil {
+		return resp, err
+	}
+	fields, err := decodeProto(data)
+	if err != nil {
+		return resp, err
+	}
+	for _, field := range fields {
+		switch field.number {
+		case 1:
+			resp.Version = string(field.bytes)
+		case 2:
+			resp.RuntimeName = string(field.bytes)
+		case 3:
+			resp.RuntimeVersion = string(field.bytes)
+		case 4:
+			resp.RuntimeAPIVersion = string(field.bytes)
+		}
+	}
+	return resp, nil
+}
+
+// ListContainers returns the containers of the runtime.
+func (c *CRIClient) ListContainers(host string, port int) ([]CRIContainer, error) {
+	data, err := c.call(host, port, "runtime.v1.RuntimeService/ListContainers", nil)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := decodeProto(data)
+	if err != nil {
+		return nil, err
+	}
+	containers := []CRIContainer{}
+	for _, field := range fields {
+		if field.number != 1 {
+			continue
+		}
+		values, err := decodeProto(field.bytes)
+		if err != nil {
+			return nil, err
+		}
+		container := CRIContainer{State: containerStates[0]}
+		for _, value := range values {
+			switch value.number {
+			case 1:
+				container.ID = string(value.bytes)
+			case 3:
+				// the name is the first field of the metadata
+				container.Name, err = firstString(value.bytes)
+			case 4:
+				// the image is the first field of the image spec
+				container.Image, err = firstString(value.bytes)
+			case 6:
+				if state, ok := containerStates[value.varint]; ok {
+					container.State = state
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// ListImages returns the images of the runtime.
+func (c *CRIClient) ListImages(host string, port int) ([]CRIImage, error) {
+	data, err := c.call(host, port, "runtime.v1.ImageService/ListImages", nil)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := decodeProto(data)
+	if err != nil {
+		return nil, err
+	}
+	images := []CRIImage{}
+	for _, field := range fields {
+		if field.number != 1 {
+			continue
+		}
+		values, err := decodeProto(field.bytes)
+		if err != nil {
+			return nil, err
+		}
+		image := CRIImage{}
+		for _, value := range values {
+			switch value.number {
+			case 1:
+				image.ID = string(value.bytes)
+			case 2:
+				image.RepoTags = append(image.RepoTags, string(value.bytes))
+			case 4:
+				image.Size = value.varint
+			}
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// firstString returns the string of the field 1 of the message
+func firstString(data []byte) (string, error) {
+	fields, err := decodeProto(data)
+	if err != nil {
+		return "", err
+	}
+	for _, field := range fields {
+		if field.number == 1 {
+			return string(field.bytes), nil
+		}
+	}
+	return "", nil
+}
+
+// call sends the gRPC request of the method and returns the response message
+func (c *CRIClient) call(host string, port int, method string, message []byte) ([]byte, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return protocolstate.Dialer.Dial(ctx, network, addr)
+		},
+	}
+	defer transport.CloseIdleConnections()
+	httpClient := httpapi.NewClient(transport)
+
+	// the messages are prefixed with the compression flag and their length
+	body := binary.BigEndian.AppendUint32([]byte{0}, uint32(len(message)))
+	target := fmt.Sprintf("http://%s/%s", net.JoinHostPort(host, strconv.Itoa(port)), method)
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(append(body, message...)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := httpapi.ReadBody(resp, true)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	// the status of the responses without message is sent in the headers
+	status, statusMessage := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, statusMessage = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if status != "0" {
+		if unescaped, err := url.PathUnescape(statusMessage); err == nil {
+			statusMessage = unescaped
+		}
+		return nil, fmt.Errorf("grpc error %s: %s", status, statusMessage)
+	}
+	if len(data) < 5 || data[0] != 0 {
+		return nil, fmt.Errorf("invalid grpc response")
+	}
+	length := binary.BigEndian.Uint32(data[1:])
+	if uint64(length) > uint64(len(data)-5) {
+		return nil, fmt.Errorf("invalid grpc message length %d", length)
+	}
+	return data[5 : 5+length], nil
+}
@@ -0,0 +1,94 @@
+package docker
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func appendProtoMessage(buf []byte, number int, message []byte) []byte {
+	return appendProtoString(buf, number, string(message))
+}
+
+func appendProtoVarint(buf []byte, number int, value uint64) []byte {
+	return binary.AppendUvarint(binary.AppendUvarint(buf, uint64(number)<<3|wireVarint), value)
+}
+
+func TestCRIClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/grpc", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.Equal(t, byte(0), body[0])
+		require.Equal(t, uint32(len(body)-5), binary.BigEndian.Uint32(body[1:]))
+
+		var message []byte
+		switch r.URL.Path {
+		case "/runtime.v1.RuntimeService/Version":
+			require.Equal(t, appendProtoString(nil, 1, criVersion), body[5:])
+			message = appendProtoString(nil, 1, "0.1.0")
+			message = appendProtoString(message, 2, "containerd")
+			message = appendProtoString(message, 3, "v1.7.2")
+			message = appendProtoString(message, 4, "v1")
+		case "/runtime.v1.RuntimeService/ListContainers":
+			container := appendProtoString(nil, 1, "8d1a")
+			container = appendProtoString(container, 2, "pod")
+			container = appendProtoMessage(container, 3, appendProtoVarint(appendProtoString(nil, 1, "etcd"), 2, 1))
+			container = appendProtoMessage(container, 4, appendProtoString(nil, 1, "registry.k8s.io/etcd:3.5.9"))
+			container = appendProtoVarint(container, 6, 1)
+			container = binary.LittleEndian.AppendUint64(binary.AppendUvarint(container, 7<<3|wireFixed64), 0)
+			message = appendProtoMessage(appendProtoMessage(nil, 1, container), 1, appendProtoString(nil, 1, "created"))
+		default:
+			// a trailers only response
+			w.Header().Set("Content-Type", "application/grpc")
+			w.Header().Set("Grpc-Status", "12")
+			w.Header().Set("Grpc-Message", "unknown%20service")
+			return
+		}
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		_, _ = w.Write(append(binary.BigEndian.AppendUint32([]byte{0}, uint32(len(message))), message...))
+		w.Header().Set("Grpc-Status", "0")
+	})
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer server.Close()
+
+	host, portValue, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	client := &CRIClient{}
+
+	version, err := client.Version(host, port)
+	require.Nil(t, err)
+	require.Equal(t, CRIVersionResponse{Version: "0.1.0", RuntimeName: "containerd", RuntimeVersion: "v1.7.2", RuntimeAPIVersion: "v1"}, version)
+
+	containers, err := client.ListContainers(host, port)
+	require.Nil(t, err)
+	require.Equal(t, []CRIContainer{
+		{ID: "8d1a", Name: "etcd", Image: "registry.k8s.io/etcd:3.5.9", State: "running"},
+		{ID: "created", State: "created"},
+	}, containers)
+
+	_, err = client.ListImages(host, port)
+	require.EqualError(t, err, "grpc error 12: unknown service")
+}
+
+func TestDecodeProto(t *testing.T) {
+	_, err := decodeProto([]byte{1<<3 | wireBytes, 10, 'a'})
+	require.EqualError(t, err, "invalid field length")
+
+	_, err = decodeProto([]byte{1<<3 | 3})
+	require.EqualError(t, err, "unsupported wire type 3")
+}
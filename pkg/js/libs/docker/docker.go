@@ -0,0 +1,187 @@
+package docker
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/httpapi"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// DockerClient is a client for the Engine API of Docker daemons.
+//
+// The daemons listening on tcp do not authenticate the requests,
+// the TLS ones may require a client certificate signed by their CA.
+type DockerClient struct {
+	// HTTPS enables TLS connections, the daemon certificate is not verified
+	HTTPS bool
+	// Certificate is the PEM encoded client certificate of the TLS connections
+	Certificate string
+	// Key is the PEM encoded private key of the client certificate
+	Key string
+}
+
+// IsDockerResponse is the response from the IsDocker function.
+type IsDockerResponse struct {
+	IsDocker      bool
+	Version       string
+	APIVersion    string
+	OS            string
+	Arch          string
+	KernelVersion string
+}
+
+// Container is a container of the daemon.
+type Container struct {
+	ID     string
+	Names  []string
+	Image  string
+	State  string
+	Status string
+}
+
+// Image is an image of the daemon.
+type Image struct {
+	ID       string
+	RepoTags []string
+	Size     int64
+}
+
+// IsDocker checks if the given host and port are running Docker daemon.
+//
+// The version endpoint is used, it does not require the authorization of the plugins.
+func (c *DockerClient) IsDocker(host string, port int) (IsDockerResponse, error) {
+	resp := IsDockerResponse{}
+
+	data, err := c.request(host, port, "/version", true)
+	if err != nil {
+		return resp, err
+	}
+	var version struct {
+		Version       string `json:"Version"`
+		APIVersion    string `json:"ApiVersion"`
+		OS            string `json:"Os"`
+		Arch          string `json:"Arch"`
+		KernelVersion string `json:"KernelVersion"`
+	}
+	if err := json.Unmarshal(data, &version); err != nil || version.APIVersion == "" {
+		return resp, nil
+	}
+	resp.IsDocker = true
+	resp.Version = version.Version
+	resp.APIVersion = version.APIVersion
+	resp.OS = version.OS
+	resp.Arch = version.Arch
+	resp.KernelVersion = version.KernelVersion
+	return resp, nil
+}
+
+// IsUnauthenticated checks if the daemon can be controlled without client certificate.
+//
+// The system information is requested without the certificate of the client,
+// it returns false if the daemon or its authorization plugins refuse the request.
+func (c *DockerClient) IsUnauthenticated(host string, port int) (bool, error) {
+	client := &DockerClient{HTTPS: c.HTTPS}
+	data, err := client.request(host, port, "/info", true)
+	if err != nil {
+		return false, err
+	}
+	var info struct {
+		ID string `json:"ID"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, nil
+	}
+	return info.ID != "", nil
+}
+
+// ListContainers returns the containers of the daemon, the stopped ones included.
+func (c *DockerClient) ListContainers(host string, port int) ([]Container, error) {
+	data, err := c.request(host, port, "/containers/json?all=1", false)
+	if err != nil {
+		return nil, err
+	}
+	var containers []struct {
+		ID     string   `json:"Id"`
+		Names  []string `json:"Names"`
+		Image  string   `json:"Image"`
+		State  string   `json:"State"`
+		Status string   `json:"Status"`
+	}
+	if err := json.Unmarshal(data, &containers); err != nil {
+		return nil, fmt.Errorf("could not parse containers: %w", err)
+	}
+	result := make([]Container, 0, len(containers))
+	for _, container := range containers {
+		result = append(result, Container(container))
+	}
+	return result, nil
+}
+
+// ListImages returns the images of the daemon.
+func (c *DockerClient) ListImages(host string, port int) ([]Image, error) {
+	data, err := c.request(host, port, "/images/json", false)
+	if err != nil {
+		return nil, err
+	}
+	var images []struct {
+		ID       string   `json:"Id"`
+		RepoTags []string `json:"RepoTags"`
+		Size     int64    `json:"Size"`
+	}
+	if err := json.Unmarshal(data, &images); err != nil {
+		return nil, fmt.Errorf("could not parse images: %w", err)
+	}
+	result := make([]Image, 0, len(images))
+	for _, image := range images {
+		result = append(result, Image(image))
+	}
+	return result, nil
+}
+
+// request sends a get request to the daemon and returns the response body,
+// the status code is not checked if anyStatus is true
+func (c *DockerClient) request(host string, port int, path string, anyStatus bool) ([]byte, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if c.Certificate != "" {
+		certificate, err := tls.X509KeyPair([]byte(c.Certificate), []byte(c.Key))
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	// the transport is not shared as the client certificate is specific to the client,
+	// redirects are not followed to stay on the target host
+	transport := httpapi.NewTransport(tlsConfig)
+	transport.DisableKeepAlives = true
+	httpClient := httpapi.NewClient(transport)
+
+	scheme := "http"
+	if c.HTTPS {
+		scheme = "https"
+	}
+	target := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)), path)
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return httpapi.ReadBody(resp, anyStatus)
+}
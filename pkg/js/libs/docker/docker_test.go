@@ -0,0 +1,99 @@
+package docker
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newCertificate returns a self signed PEM certificate and its key
+func newCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	keyData, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyData}))
+}
+
+func TestDockerClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	// the daemon refuses the requests without client certificate except the version
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/version" {
+			_, _ = w.Write([]byte(`{"Version": "24.0.7", "ApiVersion": "1.43", "Os": "linux", "Arch": "amd64", "KernelVersion": "6.1.0"}`))
+			return
+		}
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "authorization denied"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/info":
+			_, _ = w.Write([]byte(`{"ID": "b4a5c8", "Containers": 1}`))
+		case "/containers/json":
+			require.Equal(t, "1", r.URL.Query().Get("all"))
+			_, _ = w.Write([]byte(`[{"Id": "1f0c", "Names": ["/web"], "Image": "nginx:latest", "State": "running", "Status": "Up 2 hours"}]`))
+		case "/images/json":
+			_, _ = w.Write([]byte(`[{"Id": "sha256:a6bd", "RepoTags": ["nginx:latest"], "Size": 187000000}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	host, portValue, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+
+	certificate, key := newCertificate(t)
+	client := &DockerClient{HTTPS: true, Certificate: certificate, Key: key}
+
+	info, err := client.IsDocker(host, port)
+	require.Nil(t, err)
+	require.Equal(t, IsDockerResponse{IsDocker: true, Version: "24.0.7", APIVersion: "1.43", OS: "linux", Arch: "amd64", KernelVersion: "6.1.0"}, info)
+
+	unauthenticated, err := client.IsUnauthenticated(host, port)
+	require.Nil(t, err)
+	require.False(t, unauthenticated)
+
+	containers, err := client.ListContainers(host, port)
+	require.Nil(t, err)
+	require.Equal(t, []Container{{ID: "1f0c", Names: []string{"/web"}, Image: "nginx:latest", State: "running", Status: "Up 2 hours"}}, containers)
+
+	images, err := client.ListImages(host, port)
+	require.Nil(t, err)
+	require.Equal(t, []Image{{ID: "sha256:a6bd", RepoTags: []string{"nginx:latest"}, Size: 187000000}}, images)
+
+	_, err = (&DockerClient{HTTPS: true}).ListImages(host, port)
+	require.EqualError(t, err, `unexpected status code 403: {"message": "authorization denied"}`)
+
+	_, err = (&DockerClient{HTTPS: true, Certificate: certificate}).ListImages(host, port)
+	require.ErrorContains(t, err, "could not load client certificate")
+}
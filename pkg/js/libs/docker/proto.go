@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// protoField is a field of a protobuf message, the value is in varint for the
+// numeric wire types and in bytes for the length delimited one
+type protoField struct {
+	number int
+	varint uint64
+	bytes  []byte
+}
+
+// appendProtoString appends a length delimited field
+func appendProtoString(buf []byte, number int, value string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(number)<<3|wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+// decodeProto returns the fields of a protobuf message
+func decodeProto(data []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("invalid field key")
+		}
+		data = data[n:]
+		field := protoField{number: int(key >> 3)}
+		switch key & 0x07 {
+		case wireVarint:
+			field.varint, n = binary.Uvarint(data)
+			if n <= 0 {
+				return nil, errors.New("invalid varint")
+			}
+			data = data[n:]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if key&0x07 == wireFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return nil, errors.New("unexpected end of message")
+			}
+			data = data[size:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return nil, errors.New("invalid field length")
+			}
+			field.bytes = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d", key&0x07)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
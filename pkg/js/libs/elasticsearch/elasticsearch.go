@@ -0,0 +1,155 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/httpapi"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// ElasticsearchClient is a client for Elasticsearch and OpenSearch clusters.
+//
+// Requests are authenticated with the api key if set, with basic
+// auth if the username is set and are unauthenticated otherwise.
+type ElasticsearchClient struct {
+	// HTTPS enables TLS connections, the server certificate is not verified
+	HTTPS bool
+	// Username is the username of the basic authentication
+	Username string
+	// Password is the password of the basic authentication
+	Password string
+	// APIKey is the base64 encoded api key sent in the ApiKey authorization header
+	APIKey string
+}
+
+// IsElasticsearchResponse is the response from the IsElasticsearch function.
+type IsElasticsearchResponse struct {
+	IsElasticsearch bool
+	// Product is either Elasticsearch or OpenSearch
+	Product     string
+	Version     string
+	ClusterName string
+}
+
+// IsElasticsearch checks if the given host and port are running Elasticsearch or OpenSearch.
+//
+// The root endpoint is used, a cluster requiring authentication
+// is only detected with valid credentials.
+func (c *ElasticsearchClient) IsElasticsearch(host string, port int) (IsElasticsearchResponse, error) {
+	resp := IsElasticsearchResponse{}
+
+	data, err := c.request(host, port, http.MethodGet, "/", "")
+	if err != nil {
+		return resp, err
+	}
+	var info struct {
+		ClusterName string `json:"cluster_name"`
+		Tagline     string `json:"tagline"`
+		Version     struct {
+			Number       string `json:"number"`
+			Distribution string `json:"distribution"`
+		} `json:"version"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil || info.Version.Number == "" {
+		return resp, nil
+	}
+	resp.Version = info.Version.Number
+	resp.ClusterName = info.ClusterName
+	switch {
+	case info.Version.Distribution == "opensearch":
+		resp.Product, resp.IsElasticsearch = "OpenSearch", true
+	case strings.Contains(info.Tagline, "You Know, for Search"):
+		resp.Product, resp.IsElasticsearch = "Elasticsearch", true
+	}
+	return resp, nil
+}
+
+// ClusterHealth returns the health of the cluster as json.
+func (c *ElasticsearchClient) ClusterHealth(host string, port int) (string, error) {
+	data, err := c.request(host, port, http.MethodGet, "/_cluster/health", "")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ListIndices returns the names of the indices of the cluster.
+func (c *ElasticsearchClient) ListIndices(host string, port int) ([]string, error) {
+	data, err := c.request(host, port, http.MethodGet, "/_cat/indices?format=json&h=index", "")
+	if err != nil {
+		return nil, err
+	}
+	var indices []struct {
+		Index string `json:"index"`
+	}
+	if err := json.Unmarshal(data, &indices); err != nil {
+		return nil, fmt.Errorf("could not parse indices: %w", err)
+	}
+	names := make([]string, 0, len(indices))
+	for _, index := range indices {
+		names = append(names, index.Index)
+	}
+	return names, nil
+}
+
+// Search executes a search query on the index and returns the response as json.
+//
+// The query is the json body of the search request, ex: {"size": 5, "query": {"match_all": {}}},
+// all the documents are matched if empty. The index can be a comma separated list or a pattern.
+func (c *ElasticsearchClient) Search(host string, port int, index, query string) (string, error) {
+	if index == "" {
+		return "", fmt.Errorf("index is required")
+	}
+	if query == "" {
+		query = `{"query": {"match_all": {}}}`
+	}
+	data, err := c.request(host, port, http.MethodPost, "/"+url.PathEscape(index)+"/_search", query)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// request sends a request to the cluster and returns the response body
+func (c *ElasticsearchClient) request(host string, port int, method, path, body string) ([]byte, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	scheme := "http"
+	if c.HTTPS {
+		scheme = "https"
+	}
+	target := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)), path)
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.APIKey)
+	case c.Username != "":
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := httpapi.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return httpapi.ReadBody(resp, false)
+}
@@ -0,0 +1,68 @@
+package elasticsearch
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticsearchClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if r.Header.Get("Authorization") != "ApiKey a2V5" && (username != "elastic" || password != "changeme") {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "missing authentication credentials"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"cluster_name": "docker-cluster", "version": {"number": "8.11.1"}, "tagline": "You Know, for Search"}`))
+		case "/_cluster/health":
+			_, _ = w.Write([]byte(`{"cluster_name": "docker-cluster", "status": "green"}`))
+		case "/_cat/indices":
+			require.Equal(t, "json", r.URL.Query().Get("format"))
+			_, _ = w.Write([]byte(`[{"index": "users"}, {"index": ".security"}]`))
+		case "/users/_search":
+			body, _ := io.ReadAll(r.Body)
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, `{"size": 1}`, string(body))
+			_, _ = w.Write([]byte(`{"hits": {"hits": [{"_source": {"name": "admin"}}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	host, portValue, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+
+	client := &ElasticsearchClient{Username: "elastic", Password: "changeme"}
+	info, err := client.IsElasticsearch(host, port)
+	require.Nil(t, err)
+	require.Equal(t, IsElasticsearchResponse{IsElasticsearch: true, Product: "Elasticsearch", Version: "8.11.1", ClusterName: "docker-cluster"}, info)
+
+	health, err := client.ClusterHealth(host, port)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"cluster_name": "docker-cluster", "status": "green"}`, health)
+
+	indices, err := (&ElasticsearchClient{APIKey: "a2V5"}).ListIndices(host, port)
+	require.Nil(t, err)
+	require.Equal(t, []string{"users", ".security"}, indices)
+
+	result, err := client.Search(host, port, "users", `{"size": 1}`)
+	require.Nil(t, err)
+	require.Contains(t, result, `"admin"`)
+
+	_, err = (&ElasticsearchClient{}).ClusterHealth(host, port)
+	require.ErrorContains(t, err, "unexpected status code 401")
+}
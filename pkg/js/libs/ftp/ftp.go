@@ -0,0 +1,302 @@
+package ftp
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout is the timeout of the connections to the server
+	timeout = 10 * time.Second
+	// maxListSize is the maximum size of a directory listing (10MB)
+	maxListSize = 10 * 1024 * 1024
+	// codeNotLoggedIn is the reply code of the rejected credentials
+	codeNotLoggedIn = 530
+)
+
+// TLS modes of the connections
+const (
+	tlsExplicit = "explicit"
+	tlsImplicit = "implicit"
+)
+
+// pasvAddress matches the address of the passive mode replies, ex: (192,168,1,2,19,137)
+var pasvAddress = regexp.MustCompile(`(\d+),(\d+),(\d+),(\d+),(\d+),(\d+)`)
+
+// FTPClient is a client for FTP servers.
+//
+// The connections are in plain text by default, the explicit TLS mode
+// upgrades them with AUTH TLS and the implicit one starts them with TLS,
+// the server certificate is not verified.
+type FTPClient struct {
+	// TLS is the TLS mode of the connections, either explicit or implicit, plain text if empty
+	TLS string
+}
+
+// IsFTPResponse is the response from the IsFTP function.
+type IsFTPResponse struct {
+	IsFTP  bool
+	Banner string
+}
+
+// IsFTP checks if the given host and port are running FTP server.
+//
+// If connection is successful, it returns the banner of the server.
+// If connection is unsuccessful, it returns error.
+func (c *FTPClient) IsFTP(host string, port int) (IsFTPResponse, error) {
+	resp := IsFTPResponse{}
+
+	conn, err := c.dial(host, port)
+	if err != nil {
+		var ftpErr *textproto.Error
+		var protoErr textproto.ProtocolError
+		switch {
+		case errors.As(err, &ftpErr):
+			// the server rejects the client, ex: too many connections
+			resp.IsFTP, resp.Banner = true, ftpErr.Msg
+			return resp, nil
+		case errors.As(err, &protoErr):
+			return resp, nil
+		}
+		return resp, err
+	}
+	defer conn.close()
+
+	resp.IsFTP = true
+	resp.Banner = conn.banner
+	return resp, nil
+}
+
+// IsAnonymous checks if the server accepts the anonymous login.
+func (c *FTPClient) IsAnonymous(host string, port int) (bool, error) {
+	return c.Connect(host, port, "anonymous", "anonymous@")
+}
+
+// Connect connects to FTP server using given credentials.
+//
+// If connection is successful, it returns true.
+// If connection is unsuccessful, it returns false and error.
+func (c *FTPClient) Connect(host string, port int, username, password string) (bool, error) {
+	conn, err := c.dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.close()
+
+	if err := conn.login(username, password); err != nil {
+		var ftpErr *textproto.Error
+		if errors.As(err, &ftpErr) && ftpErr.Code == codeNotLoggedIn {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// List connects to FTP server using given credentials and returns
+// the lines of the listing of the path, the current directory if empty.
+//
+// The listing is in the format of the server, usually as ls -l.
+func (c *FTPClient) List(host string, port int, username, password, path string) ([]string, error) {
+	conn, err := c.dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	if err := conn.login(username, password); err != nil {
+		return nil, err
+	}
+	data, err := conn.list(path)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// ftpConn is a control connection to the server
+type ftpConn struct {
+	host   string
+	conn   net.Conn
+	text   *textproto.Conn
+	banner string
+	// tlsConfig is the config of the data connections, nil in plain text
+	tlsConfig *tls.Config
+}
+
+func (c *FTPClient) dial(host string, port int) (*ftpConn, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	mode := strings.ToLower(c.TLS)
+	if mode != "" && mode != tlsExplicit && mode != tlsImplicit {
+		return nil, fmt.Errorf("unsupported tls mode %s", c.TLS)
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	f := &ftpConn{host: host, conn: conn}
+	if mode != "" {
+		// the session is resumed by the data connections as required by some servers
+		f.tlsConfig = &tls.Config{
+			InsecureSkipVerify: true,
+			MinVersion:         tls.VersionTLS10,
+			ServerName:         host,
+			ClientSessionCache: tls.NewLRUClientSessionCache(1),
+		}
+	}
+	if mode == tlsImplicit {
+		f.conn = tls.Client(conn, f.tlsConfig)
+	}
+	f.text = textproto.NewConn(f.conn)
+
+	_, f.banner, err = f.text.ReadResponse(220)
+	if err != nil {
+		f.close()
+		return nil, err
+	}
+	if mode == tlsExplicit {
+		if _, _, err := f.cmd(234, "AUTH TLS"); err != nil {
+			f.close()
+			return nil, err
+		}
+		f.conn = tls.Client(conn, f.tlsConfig)
+		f.text = textproto.NewConn(f.conn)
+	}
+	return f, nil
+}
+
+// cmd sends the command and reads its reply, the code is not checked if zero
+func (f *ftpConn) cmd(expectCode int, format string, args ...interface{}) (int, string, error) {
+	for _, arg := range args {
+		if value, ok := arg.(string); ok && strings.ContainsAny(value, "\r\n") {
+			return 0, "", fmt.Errorf("invalid command argument %q", value)
+		}
+	}
+	if err := f.text.PrintfLine(format, args...); err != nil {
+		return 0, "", err
+	}
+	return f.text.ReadResponse(expectCode)
+}
+
+func (f *ftpConn) login(username, password string) error {
+	code, message, err := f.cmd(0, "USER %s", username)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 230:
+		return nil
+	case 331:
+		if _, _, err := f.cmd(230, "PASS %s", password); err != nil {
+			return err
+		}
+		return nil
+	}
+	return &textproto.Error{Code: code, Msg: message}
+}
+
+// list returns the listing of the path through a passive data connection
+func (f *ftpConn) list(path string) (string, error) {
+	if f.tlsConfig != nil {
+		if _, _, err := f.cmd(200, "PBSZ 0"); err != nil {
+			return "", err
+		}
+		if _, _, err := f.cmd(200, "PROT P"); err != nil {
+			return "", err
+		}
+	}
+	data, err := f.openData()
+	if err != nil {
+		return "", err
+	}
+	defer data.Close()
+
+	command := "LIST"
+	if path != "" {
+		command += " " + path
+	}
+	if _, _, err := f.cmd(1, "%s", command); err != nil {
+		return "", err
+	}
+	listing, err := io.ReadAll(io.LimitReader(data, maxListSize))
+	if err != nil {
+		return "", err
+	}
+	_ = data.Close()
+	if _, _, err := f.text.ReadResponse(2); err != nil {
+		return "", err
+	}
+	return string(listing), nil
+}
+
+// openData opens a data connection in extended or regular passive mode,
+// the address of the regular passive replies is ignored for the host of
+// the control connection as it is often private or spoofed
+func (f *ftpConn) openData() (net.Conn, error) {
+	port := 0
+	code, message, err := f.cmd(0, "EPSV")
+	if err != nil {
+		return nil, err
+	}
+	if code == 229 {
+		start, end := strings.Index(message, "(|||"), strings.LastIndex(message, "|)")
+		if start != -1 && end > start+4 {
+			port, _ = strconv.Atoi(message[start+4 : end])
+		}
+	} else {
+		_, message, err = f.cmd(227, "PASV")
+		if err != nil {
+			return nil, err
+		}
+		if match := pasvAddress.FindStringSubmatch(message); match != nil {
+			high, _ := strconv.Atoi(match[5])
+			low, _ := strconv.Atoi(match[6])
+			port = high<<8 | low
+		}
+	}
+	if port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid passive mode reply: %s", message)
+	}
+
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(f.host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if f.tlsConfig != nil {
+		conn = tls.Client(conn, f.tlsConfig)
+	}
+	return conn, nil
+}
+
+func (f *ftpConn) close() {
+	_ = f.text.PrintfLine("QUIT")
+	_ = f.conn.Close()
+}
@@ -0,0 +1,185 @@
+package ftp
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+const listing = "drwxr-xr-x    2 0        0            4096 Jan 01 00:00 pub\r\n-rw-r--r--    1 0        0              12 Jan 01 00:00 readme.txt\r\n"
+
+// fakeServer is a server accepting the anonymous and admin:admin users,
+// the extended passive mode is only supported with TLS
+type fakeServer struct {
+	t        *testing.T
+	listener net.Listener
+	config   *tls.Config
+	implicit bool
+}
+
+func newFakeServer(t *testing.T, implicit bool) int {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	server := &fakeServer{
+		t:        t,
+		listener: listener,
+		config:   &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}},
+		implicit: implicit,
+	}
+	go server.serve()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	secure := s.implicit
+	if secure {
+		conn = tls.Server(conn, s.config)
+	}
+	reader := bufio.NewReader(conn)
+	reply := func(format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+	reply("220-Welcome\r\n220 (vsFTPd 3.0.3)")
+
+	var username string
+	var data net.Listener
+	protected := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		switch command {
+		case "AUTH":
+			reply("234 Proceed with negotiation.")
+			conn = tls.Server(conn, s.config)
+			reader = bufio.NewReader(conn)
+			secure = true
+		case "USER":
+			username = arg
+			reply("331 Please specify the password.")
+		case "PASS":
+			if (username == "anonymous") || (username == "admin" && arg == "admin") {
+				reply("230 Login successful.")
+			} else {
+				reply("530 Login incorrect.")
+			}
+		case "PBSZ":
+			reply("200 PBSZ set to 0.")
+		case "PROT":
+			protected = arg == "P"
+			reply("200 PROT now Private.")
+		case "EPSV", "PASV":
+			if command == "EPSV" && !secure {
+				reply("500 Unknown command.")
+				continue
+			}
+			data, err = net.Listen("tcp", "127.0.0.1:0")
+			require.Nil(s.t, err)
+			port := data.Addr().(*net.TCPAddr).Port
+			if command == "EPSV" {
+				reply("229 Entering Extended Passive Mode (|||%d|)", port)
+			} else {
+				// the address is ignored by the client
+				reply("227 Entering Passive Mode (10,0,0,1,%d,%d).", port>>8, port&0xff)
+			}
+		case "LIST":
+			require.Equal(s.t, "/pub", arg)
+			require.Equal(s.t, secure, protected, "data connections must be protected with tls")
+			dataConn, err := data.Accept()
+			require.Nil(s.t, err)
+			_ = data.Close()
+			if protected {
+				dataConn = tls.Server(dataConn, s.config)
+			}
+			reply("150 Here comes the directory listing.")
+			_, _ = dataConn.Write([]byte(listing))
+			_ = dataConn.Close()
+			reply("226 Directory send OK.")
+		case "QUIT":
+			reply("221 Goodbye.")
+			return
+		default:
+			reply("500 Unknown command.")
+		}
+	}
+}
+
+func TestFTPClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	tests := []struct {
+		mode     string
+		implicit bool
+	}{
+		{mode: ""},
+		{mode: "explicit"},
+		{mode: "implicit", implicit: true},
+	}
+	for _, test := range tests {
+		port := newFakeServer(t, test.implicit)
+		client := &FTPClient{TLS: test.mode}
+
+		resp, err := client.IsFTP("127.0.0.1", port)
+		require.Nil(t, err)
+		require.True(t, resp.IsFTP)
+		require.Equal(t, "Welcome\n(vsFTPd 3.0.3)", resp.Banner)
+
+		anonymous, err := client.IsAnonymous("127.0.0.1", port)
+		require.Nil(t, err)
+		require.True(t, anonymous)
+
+		connected, err := client.Connect("127.0.0.1", port, "admin", "invalid")
+		require.Nil(t, err)
+		require.False(t, connected)
+
+		lines, err := client.List("127.0.0.1", port, "admin", "admin", "/pub")
+		require.Nil(t, err, "could not list in %q tls mode", test.mode)
+		require.Equal(t, []string{
+			"drwxr-xr-x    2 0        0            4096 Jan 01 00:00 pub",
+			"-rw-r--r--    1 0        0              12 Jan 01 00:00 readme.txt",
+		}, lines)
+	}
+
+	port := newFakeServer(t, false)
+	_, err := (&FTPClient{}).Connect("127.0.0.1", port, "admin\r\nDELE x", "admin")
+	require.EqualError(t, err, `invalid command argument "admin\r\nDELE x"`)
+}
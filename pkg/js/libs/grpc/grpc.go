@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GRPCClient is a client for gRPC servers.
+//
+// Internally client uses the server reflection to discover the services
+// of the server and the types of their messages.
+type GRPCClient struct {
+	// TLS enables TLS connections negotiating HTTP/2 with ALPN, the server certificate is not verified
+	TLS bool
+	// Metadata are the headers sent with the calls, ex: authorization
+	Metadata map[string]string
+}
+
+// Method is a method of a service.
+type Method struct {
+	Name            string
+	InputType       string
+	OutputType      string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// ListServices returns the sorted services of the server using the server reflection.
+func (c *GRPCClient) ListServices(host string, port int) ([]string, error) {
+	resp, err := c.reflectFields(host, port, requestListServices, "")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(resp.services)
+	return resp.services, nil
+}
+
+// ListMethods returns the methods of the service using the server reflection.
+func (c *GRPCClient) ListMethods(host string, port int, service string) ([]Method, error) {
+	files, err := c.files(host, port, service)
+	if err != nil {
+		return nil, err
+	}
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("could not find service %s", service)
+	}
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+
+	methods := make([]Method, 0, serviceDescriptor.Methods().Len())
+	for i := 0; i < serviceDescriptor.Methods().Len(); i++ {
+		method := serviceDescriptor.Methods().Get(i)
+		methods = append(methods, Method{
+			Name:            string(method.Name()),
+			InputType:       string(method.Input().FullName()),
+			OutputType:      string(method.Output().FullName()),
+			ClientStreaming: method.IsStreamingClient(),
+			ServerStreaming: method.IsStreamingServer(),
+		})
+	}
+	return methods, nil
+}
+
+// Invoke calls a unary method with the JSON encoded request and returns the JSON encoded response.
+//
+// The method is the full name of the method, ex: helloworld.Greeter/SayHello,
+// the request and response types are resolved with the server reflection.
+func (c *GRPCClient) Invoke(host string, port int, method, request string) (string, error) {
+	separator := strings.LastIndexAny(method, "/.")
+	if separator <= 0 || separator == len(method)-1 {
+		return "", fmt.Errorf("invalid method %s", method)
+	}
+	service, name := strings.TrimPrefix(method[:separator], "/"), method[separator+1:]
+
+	files, err := c.files(host, port, service)
+	if err != nil {
+		return "", err
+	}
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return "", fmt.Errorf("could not find service %s", service)
+	}
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return "", fmt.Errorf("%s is not a service", service)
+	}
+	methodDescriptor := serviceDescriptor.Methods().ByName(protoreflect.Name(name))
+	if methodDescriptor == nil {
+		return "", fmt.Errorf("could not find method %s of service %s", name, service)
+	}
+	if methodDescriptor.IsStreamingClient() || methodDescriptor.IsStreamingServer() {
+		return "", fmt.Errorf("method %s is not unary", name)
+	}
+
+	types := dynamicpb.NewTypes(files)
+	input := dynamicpb.NewMessage(methodDescriptor.Input())
+	if strings.TrimSpace(request) != "" {
+		if err := (protojson.UnmarshalOptions{Resolver: types}).Unmarshal([]byte(request), input); err != nil {
+			return "", errors.Wrap(err, "could not encode request")
+		}
+	}
+	message, err := proto.Marshal(input)
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode request")
+	}
+
+	responses, err := c.call(host, port, string(serviceDescriptor.FullName())+"/"+name, [][]byte{message})
+	if err != nil {
+		return "", err
+	}
+	if len(responses) != 1 {
+		return "", fmt.Errorf("unexpected number of responses %d", len(responses))
+	}
+	output := dynamicpb.NewMessage(methodDescriptor.Output())
+	if err := (proto.UnmarshalOptions{Resolver: types}).Unmarshal(responses[0], output); err != nil {
+		return "", errors.Wrap(err, "could not decode response")
+	}
+	data, err := (protojson.MarshalOptions{Resolver: types}).Marshal(output)
+	if err != nil {
+		return "", errors.Wrap(err, "could not decode response")
+	}
+	return string(data), nil
+}
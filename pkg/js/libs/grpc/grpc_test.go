@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// testFiles returns the descriptors of the test service, the messages of
+// the greeter file depend on the common file
+func testFiles(t *testing.T) (greeter, common []byte) {
+	common, err := proto.Marshal(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("common.proto"),
+		Package: proto.String("test.common"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Name"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("value"), JsonName: proto.String("value"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+			},
+		}},
+	})
+	require.Nil(t, err)
+	greeter, err = proto.Marshal(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("greeter.proto"),
+		Package:    proto.String("test.greeter"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"common.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("HelloRequest"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("name"), JsonName: proto.String("name"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".test.common.Name"), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("count"), JsonName: proto.String("count"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+			},
+		}, {
+			Name: proto.String("HelloReply"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("message"), JsonName: proto.String("message"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+			},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Greeter"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("SayHello"), InputType: proto.String(".test.greeter.HelloRequest"), OutputType: proto.String(".test.greeter.HelloReply")},
+				{Name: proto.String("Chat"), InputType: proto.String(".test.greeter.HelloRequest"), OutputType: proto.String(".test.greeter.HelloReply"), ClientStreaming: proto.Bool(true), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	})
+	require.Nil(t, err)
+	return greeter, common
+}
+
+func appendMessage(buf []byte, number protowire.Number, message []byte) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(buf, number, protowire.BytesType), message)
+}
+
+func appendString(buf []byte, number protowire.Number, value string) []byte {
+	return protowire.AppendString(protowire.AppendTag(buf, number, protowire.BytesType), value)
+}
+
+func TestGRPCClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	greeter, common := testFiles(t)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/grpc", r.Header.Get("Content-Type"))
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.Equal(t, byte(0), body[0])
+		require.Equal(t, uint32(len(body)-5), binary.BigEndian.Uint32(body[1:]))
+
+		var message []byte
+		switch r.URL.Path {
+		case "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo":
+			require.Nil(t, walkMessage(body[5:], func(number protowire.Number, value []byte, _ uint64) error {
+				switch {
+				case number == requestListServices:
+					services := appendMessage(nil, 1, appendString(nil, 1, "test.greeter.Greeter"))
+					services = appendMessage(services, 1, appendString(nil, 1, "grpc.reflection.v1alpha.ServerReflection"))
+					message = appendMessage(nil, responseListServices, services)
+				case number == requestFileContainingName && string(value) == "test.greeter.Greeter":
+					message = appendMessage(nil, responseFileDescriptor, appendMessage(nil, 1, greeter))
+				case number == requestFileByFilename && string(value) == "common.proto":
+					message = appendMessage(nil, responseFileDescriptor, appendMessage(nil, 1, common))
+				default:
+					errorResponse := protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 5)
+					message = appendMessage(nil, responseError, appendString(errorResponse, 2, "symbol not found"))
+				}
+				return nil
+			}))
+		case "/test.greeter.Greeter/SayHello":
+			var name string
+			var count uint64
+			require.Nil(t, walkMessage(body[5:], func(number protowire.Number, value []byte, varint uint64) error {
+				switch number {
+				case 1:
+					return walkMessage(value, func(_ protowire.Number, value []byte, _ uint64) error {
+						name = string(value)
+						return nil
+					})
+				case 2:
+					count = varint
+				}
+				return nil
+			}))
+			message = appendString(nil, 1, fmt.Sprintf("hello %s x%d", name, count))
+		default:
+			// a trailers only response
+			w.Header().Set("Content-Type", "application/grpc")
+			w.Header().Set("Grpc-Status", "12")
+			w.Header().Set("Grpc-Message", "unknown%20service")
+			return
+		}
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		_, _ = w.Write(append(binary.BigEndian.AppendUint32([]byte{0}, uint32(len(message))), message...))
+		w.Header().Set("Grpc-Status", "0")
+	})
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer server.Close()
+
+	host, portValue, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	client := &GRPCClient{Metadata: map[string]string{"Authorization": "Bearer token"}}
+
+	services, err := client.ListServices(host, port)
+	require.Nil(t, err)
+	require.Equal(t, []string{"grpc.reflection.v1alpha.ServerReflection", "test.greeter.Greeter"}, services)
+
+	methods, err := client.ListMethods(host, port, "test.greeter.Greeter")
+	require.Nil(t, err)
+	require.Equal(t, []Method{
+		{Name: "SayHello", InputType: "test.greeter.HelloRequest", OutputType: "test.greeter.HelloReply"},
+		{Name: "Chat", InputType: "test.greeter.HelloRequest", OutputType: "test.greeter.HelloReply", ClientStreaming: true, ServerStreaming: true},
+	}, methods)
+
+	_, err = client.ListMethods(host, port, "test.Unknown")
+	require.EqualError(t, err, "NOT_FOUND: symbol not found")
+
+	response, err := client.Invoke(host, port, "test.greeter.Greeter/SayHello", `{"name": {"value": "nuclei"}, "count": 2}`)
+	require.Nil(t, err)
+	var reply map[string]string
+	require.Nil(t, json.Unmarshal([]byte(response), &reply))
+	require.Equal(t, map[string]string{"message": "hello nuclei x2"}, reply)
+
+	_, err = client.Invoke(host, port, "test.greeter.Greeter.Chat", "")
+	require.EqualError(t, err, "method Chat is not unary")
+
+	_, err = client.Invoke(host, port, "test.greeter.Greeter/SayHello", `{"unknown": 1}`)
+	require.ErrorContains(t, err, "could not encode request")
+}
+
+func TestGRPCClientWithoutReflection(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "12")
+		w.Header().Set("Grpc-Message", "unknown%20service")
+	})
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer server.Close()
+
+	host, portValue, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	client := &GRPCClient{}
+
+	_, err = client.ListServices(host, port)
+	require.EqualError(t, err, "server reflection is not supported: UNIMPLEMENTED: unknown service")
+}
@@ -0,0 +1,224 @@
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectionMethods are the methods of the reflection service, the
+// v1alpha one is used by the servers without the stable version
+var reflectionMethods = []string{
+	"grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+	"grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+}
+
+// maxFiles is the maximum number of files resolved for a symbol
+const maxFiles = 100
+
+// fields of the reflection request
+const (
+	requestFileByFilename     protowire.Number = 3
+	requestFileContainingName protowire.Number = 4
+	requestListServices       protowire.Number = 7
+)
+
+// fields of the reflection response
+const (
+	responseFileDescriptor protowire.Number = 4
+	responseListServices   protowire.Number = 6
+	responseError          protowire.Number = 7
+)
+
+// reflect sends a reflection request with the string field and returns the response
+func (c *GRPCClient) reflect(host string, port int, number protowire.Number, value string) ([]byte, error) {
+	request := protowire.AppendTag(nil, number, protowire.BytesType)
+	request = protowire.AppendString(request, value)
+
+	var err error
+	for _, method := range reflectionMethods {
+		var responses [][]byte
+		responses, err = c.call(host, port, method, [][]byte{request})
+		var status *statusError
+		if errors.As(err, &status) && status.Code == codeUnimplemented {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(responses) == 0 {
+			return nil, fmt.Errorf("empty reflection response")
+		}
+		return responses[0], nil
+	}
+	return nil, errors.Wrap(err, "server reflection is not supported")
+}
+
+// reflectionResponse is the decoded response of a reflection request
+type reflectionResponse struct {
+	files    [][]byte
+	services []string
+}
+
+// reflectFields sends the reflection request and decodes the response
+func (c *GRPCClient) reflectFields(host string, port int, number protowire.Number, value string) (*reflectionResponse, error) {
+	data, err := c.reflect(host, port, number, value)
+	if err != nil {
+		return nil, err
+	}
+	resp := &reflectionResponse{}
+	err = walkMessage(data, func(number protowire.Number, value []byte, _ uint64) error {
+		switch number {
+		case responseFileDescriptor:
+			return walkMessage(value, func(number protowire.Number, value []byte, _ uint64) error {
+				if number == 1 {
+					resp.files = append(resp.files, value)
+				}
+				return nil
+			})
+		case responseListServices:
+			return walkMessage(value, func(number protowire.Number, value []byte, _ uint64) error {
+				if number != 1 {
+					return nil
+				}
+				return walkMessage(value, func(number protowire.Number, value []byte, _ uint64) error {
+					if number == 1 {
+						resp.services = append(resp.services, string(value))
+					}
+					return nil
+				})
+			})
+		case responseError:
+			status := &statusError{}
+			if err := walkMessage(value, func(number protowire.Number, value []byte, varint uint64) error {
+				switch number {
+				case 1:
+					status.Code = int(varint)
+				case 2:
+					status.Message = string(value)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			return status
+		}
+		return nil
+	})
+	if err != nil {
+		var status *statusError
+		if errors.As(err, &status) {
+			return nil, err
+		}
+		return nil, errors.Wrap(err, "could not decode reflection response")
+	}
+	return resp, nil
+}
+
+// walkMessage calls fn with the fields of a protobuf message, the value is
+// in bytes for the length delimited fields and in varint for the varint ones
+func walkMessage(data []byte, fn func(number protowire.Number, value []byte, varint uint64) error) error {
+	for len(data) > 0 {
+		number, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		var value []byte
+		var varint uint64
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(data)
+		default:
+			n = protowire.ConsumeFieldValue(number, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if err := fn(number, value, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileDescriptors returns the files defining the symbol and their dependencies
+func (c *GRPCClient) fileDescriptors(host string, port int, symbol string) ([]*descriptorpb.FileDescriptorProto, error) {
+	resp, err := c.reflectFields(host, port, requestFileContainingName, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.files) == 0 {
+		return nil, fmt.Errorf("could not find symbol %s", symbol)
+	}
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	requested := make(map[string]bool)
+	var ordered []*descriptorpb.FileDescriptorProto
+	queue := resp.files
+	for len(queue) > 0 {
+		data := queue[0]
+		queue = queue[1:]
+
+		file := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(data, file); err != nil {
+			return nil, errors.Wrap(err, "could not decode file descriptor")
+		}
+		if _, ok := files[file.GetName()]; ok {
+			continue
+		}
+		if len(files) >= maxFiles {
+			return nil, fmt.Errorf("too many files for symbol %s", symbol)
+		}
+		files[file.GetName()] = file
+		ordered = append(ordered, file)
+
+		// the dependencies not sent with the file are requested by name
+		for _, dependency := range file.GetDependency() {
+			if _, ok := files[dependency]; ok || requested[dependency] {
+				continue
+			}
+			requested[dependency] = true
+			dependencyResp, err := c.reflectFields(host, port, requestFileByFilename, dependency)
+			if err == nil && len(dependencyResp.files) > 0 {
+				queue = append(queue, dependencyResp.files...)
+				continue
+			}
+			// the well known types are used if the server does not send them
+			global, globalErr := protoregistry.GlobalFiles.FindFileByPath(dependency)
+			if globalErr != nil {
+				if err == nil {
+					err = fmt.Errorf("file not found")
+				}
+				return nil, errors.Wrapf(err, "could not resolve dependency %s", dependency)
+			}
+			globalFile, err := proto.Marshal(protodesc.ToFileDescriptorProto(global))
+			if err != nil {
+				return nil, err
+			}
+			queue = append(queue, globalFile)
+		}
+	}
+	return ordered, nil
+}
+
+// files returns the registry of the files defining the symbol
+func (c *GRPCClient) files(host string, port int, symbol string) (*protoregistry.Files, error) {
+	descriptors, err := c.fileDescriptors(host, port, symbol)
+	if err != nil {
+		return nil, err
+	}
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: descriptors})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build file descriptors")
+	}
+	return files, nil
+}
@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// timeout is the timeout of the calls
+	timeout = 10 * time.Second
+	// maxResponseSize is the maximum size of the response bodies (10MB)
+	maxResponseSize = 10 * 1024 * 1024
+)
+
+// status codes of the calls
+const (
+	codeUnimplemented = 12
+)
+
+// codeNames are the names of the status codes
+var codeNames = map[int]string{
+	1:  "CANCELLED",
+	2:  "UNKNOWN",
+	3:  "INVALID_ARGUMENT",
+	4:  "DEADLINE_EXCEEDED",
+	5:  "NOT_FOUND",
+	6:  "ALREADY_EXISTS",
+	7:  "PERMISSION_DENIED",
+	8:  "RESOURCE_EXHAUSTED",
+	9:  "FAILED_PRECONDITION",
+	10: "ABORTED",
+	11: "OUT_OF_RANGE",
+	12: "UNIMPLEMENTED",
+	13: "INTERNAL",
+	14: "UNAVAILABLE",
+	15: "DATA_LOSS",
+	16: "UNAUTHENTICATED",
+}
+
+// statusError is a non OK status of a call
+type statusError struct {
+	Code    int
+	Message string
+}
+
+func (e *statusError) Error() string {
+	name, ok := codeNames[e.Code]
+	if !ok {
+		name = fmt.Sprintf("code %d", e.Code)
+	}
+	if e.Message != "" {
+		return name + ": " + e.Message
+	}
+	return name
+}
+
+// call sends the request messages of the method on a stream and returns the response messages
+func (c *GRPCClient) call(host string, port int, method string, messages [][]byte) ([][]byte, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	transport := &http2.Transport{}
+	scheme := "https"
+	if c.TLS {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"h2"}}
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return protocolstate.Dialer.DialTLSWithConfig(ctx, network, addr, cfg)
+		}
+	} else {
+		scheme = "http"
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return protocolstate.Dialer.Dial(ctx, network, addr)
+		}
+	}
+	defer transport.CloseIdleConnections()
+	httpClient := &http.Client{Timeout: timeout, Transport: transport}
+
+	// the messages are prefixed with the compression flag and their length
+	var body []byte
+	for _, message := range messages {
+		body = append(binary.BigEndian.AppendUint32(append(body, 0), uint32(len(message))), message...)
+	}
+	target := fmt.Sprintf("%s://%s/%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)), strings.TrimPrefix(method, "/"))
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range c.Metadata {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	// the status of the responses without message is sent in the headers
+	status, statusMessage := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, statusMessage = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grpc status %q", status)
+	}
+	if code != 0 {
+		if unescaped, err := url.PathUnescape(statusMessage); err == nil {
+			statusMessage = unescaped
+		}
+		return nil, &statusError{Code: code, Message: statusMessage}
+	}
+
+	var responses [][]byte
+	for len(data) > 0 {
+		if len(data) < 5 {
+			return nil, fmt.Errorf("invalid grpc response")
+		}
+		if data[0] != 0 {
+			return nil, fmt.Errorf("compressed grpc messages are not supported")
+		}
+		length := binary.BigEndian.Uint32(data[1:])
+		if uint64(length) > uint64(len(data)-5) {
+			return nil, fmt.Errorf("invalid grpc message length %d", length)
+		}
+		responses = append(responses, data[5:5+length])
+		data = data[5+length:]
+	}
+	return responses, nil
+}
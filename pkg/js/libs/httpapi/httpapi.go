@@ -0,0 +1,67 @@
+// Package httpapi implements the http client of the libraries of services
+// exposing an http api, ex: elasticsearch, couchdb, docker and kubernetes.
+package httpapi
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// Timeout is the timeout of the requests
+	Timeout = 10 * time.Second
+	// MaxResponseSize is the maximum size of the response bodies (10MB)
+	MaxResponseSize = 10 * 1024 * 1024
+	// MaxErrorSize is the maximum size of the response body included in errors
+	MaxErrorSize = 1024
+)
+
+// Client is the client of the requests without client certificate, the
+// server certificates are not verified
+var Client = NewClient(NewTransport(&tls.Config{InsecureSkipVerify: true}))
+
+// NewTransport returns a transport dialing with the nuclei dialer
+func NewTransport(tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return protocolstate.Dialer.Dial(ctx, network, addr)
+		},
+		TLSClientConfig: tlsConfig,
+	}
+}
+
+// NewClient returns a client of the transport, redirects are not
+// followed to stay on the target host
+func NewClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Timeout:   Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// ReadBody reads the response body up to MaxResponseSize, an error with the
+// start of the body is returned for the non 2xx status codes unless anyStatus is true
+func ReadBody(resp *http.Response, anyStatus bool) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if !anyStatus && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		if len(data) > MaxErrorSize {
+			data = data[:MaxErrorSize]
+		}
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
@@ -0,0 +1,30 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBody(t *testing.T) {
+	response := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	data, err := ReadBody(response(http.StatusOK, "{}"), false)
+	require.Nil(t, err)
+	require.Equal(t, "{}", string(data))
+
+	_, err = ReadBody(response(http.StatusUnauthorized, strings.Repeat("a", MaxErrorSize)+"b"), false)
+	require.EqualError(t, err, "unexpected status code 401: "+strings.Repeat("a", MaxErrorSize), "could not truncate error body")
+
+	data, err = ReadBody(response(http.StatusForbidden, "denied"), true)
+	require.Nil(t, err, "could not ignore status code")
+	require.Equal(t, "denied", string(data))
+
+	redirect := NewClient(http.DefaultTransport).CheckRedirect(nil, nil)
+	require.ErrorIs(t, redirect, http.ErrUseLastResponse, "could not refuse redirects")
+}
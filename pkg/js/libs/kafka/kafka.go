@@ -0,0 +1,436 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout is the timeout of the connections to the broker
+	timeout = 10 * time.Second
+	// maxFetchSize is the maximum size of the records fetched by CanConsume (1MB)
+	maxFetchSize = 1024 * 1024
+)
+
+// produceValue is the value of the record written by CanProduce
+var produceValue = []byte("nuclei")
+
+// KafkaClient is a client for Kafka brokers.
+//
+// The connections are authenticated with the SASL mechanism if set and
+// unauthenticated otherwise, internally client implements the Kafka protocol
+// with the request versions supported from Kafka 1.0 to Kafka 4.
+type KafkaClient struct {
+	// TLS enables TLS connections, the broker certificate is not verified
+	TLS bool
+	// Mechanism is the SASL mechanism, either PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512
+	Mechanism string
+	// Username is the username of the SASL authentication
+	Username string
+	// Password is the password of the SASL authentication
+	Password string
+}
+
+// IsKafkaResponse is the response from the IsKafka function.
+type IsKafkaResponse struct {
+	IsKafka bool
+	// SASLMechanisms are the SASL mechanisms enabled on the listener, empty if authentication is disabled
+	SASLMechanisms []string
+}
+
+// MetadataResponse is the metadata of the cluster.
+type MetadataResponse struct {
+	ClusterID    string
+	ControllerID int32
+	Brokers      []Broker
+	Topics       []Topic
+}
+
+// Broker is a broker of the cluster with its advertised address.
+type Broker struct {
+	ID   int32
+	Host string
+	Port int32
+	Rack string
+}
+
+// Topic is a topic of the cluster.
+type Topic struct {
+	Name       string
+	Internal   bool
+	Partitions []Partition
+}
+
+// Partition is a partition of a topic with the ids of its leader and replicas.
+type Partition struct {
+	ID       int32
+	Leader   int32
+	Replicas []int32
+	ISR      []int32
+}
+
+// IsKafka checks if the given host and port are running Kafka broker.
+//
+// The SASL mechanisms enabled on the listener are returned, no credential is sent.
+func (c *KafkaClient) IsKafka(host string, port int) (IsKafkaResponse, error) {
+	resp := IsKafkaResponse{}
+
+	conn, err := c.dial(host, port)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.close()
+
+	r, err := conn.request(apiVersions, versionApiVersions, nil)
+	if err != nil {
+		return resp, nil
+	}
+	err = r.decode(func() {
+		// the unsupported versions are reported with the supported ones
+		if code := r.int16(); code != 0 && code != 35 {
+			r.fail("unexpected error code %d", code)
+		}
+		for i := r.array(); i > 0; i-- {
+			r.read(6)
+		}
+	})
+	if err != nil {
+		return resp, nil
+	}
+	resp.IsKafka = true
+
+	// the enabled mechanisms are listed in the rejection of an empty mechanism
+	r, err = conn.request(apiSaslHandshake, versionSaslHandshake, appendString(nil, ""))
+	if err == nil {
+		var mechanisms []string
+		_ = r.decode(func() {
+			code := r.int16()
+			for i := r.array(); i > 0; i-- {
+				mechanisms = append(mechanisms, r.string())
+			}
+			if code == errUnsupportedSaslMechanism {
+				resp.SASLMechanisms = mechanisms
+			}
+		})
+	}
+	return resp, nil
+}
+
+// Connect connects to Kafka broker and authenticates with the SASL mechanism if set.
+//
+// The metadata of the cluster is requested to check the access.
+// If connection is successful, it returns true.
+// If connection is unsuccessful, it returns false and error.
+func (c *KafkaClient) Connect(host string, port int) (bool, error) {
+	conn, err := c.connect(host, port)
+	if err != nil {
+		var kafkaErr *kafkaError
+		if errors.As(err, &kafkaErr) && kafkaErr.Code == errSaslAuthenticationFailed {
+			return false, nil
+		}
+		return false, err
+	}
+	defer conn.close()
+
+	if _, err := conn.metadata(nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Metadata returns the brokers, topics and partitions of the cluster.
+//
+// Only the topics the user is authorized to describe are returned.
+func (c *KafkaClient) Metadata(host string, port int) (MetadataResponse, error) {
+	conn, err := c.connect(host, port)
+	if err != nil {
+		return MetadataResponse{}, err
+	}
+	defer conn.close()
+
+	return conn.metadata(nil)
+}
+
+// ListTopics returns the sorted names of the topics of the cluster.
+func (c *KafkaClient) ListTopics(host string, port int) ([]string, error) {
+	metadata, err := c.Metadata(host, port)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		names = append(names, topic.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CanProduce checks if a record can be written to the partition 0 of the topic.
+//
+// A record with a null key and the nuclei value is written to the topic.
+// If the record is written, it returns true.
+// If the user is not authorized, it returns false.
+func (c *KafkaClient) CanProduce(host string, port int, topic string) (bool, error) {
+	conn, err := c.connect(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.close()
+
+	body := appendInt16(nil, -1) // null transactional id
+	body = appendInt16(body, 1)  // acks of the leader
+	body = appendInt32(body, int32(timeout/time.Millisecond))
+	body = appendString(appendInt32(body, 1), topic)
+	body = appendInt32(appendInt32(body, 1), 0)
+	body = appendBytes(body, recordBatch(produceValue, time.Now()))
+
+	code, err := c.partitionRequest(conn, topic, apiProduce, versionProduce, body, func(r *reader) int16 {
+		r.array()
+		r.string()
+		r.array()
+		r.int32()
+		return r.int16()
+	})
+	return authorized(code, err)
+}
+
+// CanConsume checks if the records of the partition 0 of the topic can be fetched.
+//
+// If the records can be fetched, it returns true.
+// If the user is not authorized, it returns false.
+func (c *KafkaClient) CanConsume(host string, port int, topic string) (bool, error) {
+	conn, err := c.connect(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.close()
+
+	body := appendInt32(nil, -1) // replica id of the consumers
+	body = appendInt32(body, 0)  // max wait
+	body = appendInt32(body, 0)  // min bytes
+	body = appendInt32(body, maxFetchSize)
+	body = append(body, 0) // isolation level
+	body = appendString(appendInt32(body, 1), topic)
+	body = appendInt32(appendInt32(body, 1), 0)
+	body = appendInt32(appendInt64(body, 0), maxFetchSize)
+
+	code, err := c.partitionRequest(conn, topic, apiFetch, versionFetch, body, func(r *reader) int16 {
+		r.int32()
+		r.array()
+		r.string()
+		r.array()
+		r.int32()
+		return r.int16()
+	})
+	// the offset 0 is out of range once deleted by the retention, the records are readable
+	if code == errOffsetOutOfRange {
+		return true, nil
+	}
+	return authorized(code, err)
+}
+
+// authorized returns true if the request succeeded and false if the user is not authorized
+func authorized(code int16, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	switch code {
+	case 0:
+		return true, nil
+	case errTopicAuthorizationFailed, errClusterAuthorizationFailed:
+		return false, nil
+	}
+	return false, &kafkaError{Code: code}
+}
+
+// partitionRequest sends a request of the partition 0 of the topic and returns its error code,
+// the request is sent again to the leader of the partition if the broker is not the leader
+func (c *KafkaClient) partitionRequest(conn *kafkaConn, topic string, apiKey, version int16, body []byte, parse func(*reader) int16) (int16, error) {
+	send := func(conn *kafkaConn) (int16, error) {
+		r, err := conn.request(apiKey, version, body)
+		if err != nil {
+			return 0, err
+		}
+		var code int16
+		err = r.decode(func() { code = parse(r) })
+		return code, err
+	}
+	code, err := send(conn)
+	if err != nil || code != errNotLeaderOrFollower {
+		return code, err
+	}
+
+	metadata, err := conn.metadata([]string{topic})
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range metadata.Topics {
+		for _, partition := range t.Partitions {
+			if t.Name != topic || partition.ID != 0 {
+				continue
+			}
+			for _, broker := range metadata.Brokers {
+				if broker.ID != partition.Leader {
+					continue
+				}
+				leader, err := c.connect(broker.Host, int(broker.Port))
+				if err != nil {
+					return 0, err
+				}
+				defer leader.close()
+				return send(leader)
+			}
+		}
+	}
+	return code, nil
+}
+
+// metadata returns the metadata of the topics, all the topics if nil
+func (c *kafkaConn) metadata(topics []string) (MetadataResponse, error) {
+	resp := MetadataResponse{}
+
+	var body []byte
+	if topics == nil {
+		body = appendInt32(nil, -1)
+	} else {
+		body = appendInt32(nil, int32(len(topics)))
+		for _, topic := range topics {
+			body = appendString(body, topic)
+		}
+	}
+	// the topics are not created if missing
+	body = append(body, 0)
+
+	r, err := c.request(apiMetadata, versionMetadata, body)
+	if err != nil {
+		return resp, err
+	}
+	err = r.decode(func() {
+		r.int32()
+		for i := r.array(); i > 0; i-- {
+			resp.Brokers = append(resp.Brokers, Broker{ID: r.int32(), Host: r.string(), Port: r.int32(), Rack: r.string()})
+		}
+		resp.ClusterID = r.string()
+		resp.ControllerID = r.int32()
+		for i := r.array(); i > 0; i-- {
+			r.int16()
+			topic := Topic{Name: r.string(), Internal: r.int8() != 0}
+			for j := r.array(); j > 0; j-- {
+				r.int16()
+				partition := Partition{ID: r.int32(), Leader: r.int32()}
+				partition.Replicas = r.int32Array()
+				partition.ISR = r.int32Array()
+				topic.Partitions = append(topic.Partitions, partition)
+			}
+			resp.Topics = append(resp.Topics, topic)
+		}
+	})
+	return resp, err
+}
+
+func (r *reader) int32Array() []int32 {
+	values := make([]int32, r.array())
+	for i := range values {
+		values[i] = r.int32()
+	}
+	return values
+}
+
+func (c *KafkaClient) dial(host string, port int) (*kafkaConn, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	var conn net.Conn
+	var err error
+	if c.TLS {
+		conn, err = protocolstate.Dialer.DialTLSWithConfig(context.TODO(), "tcp", address, &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10})
+	} else {
+		conn, err = protocolstate.Dialer.Dial(context.TODO(), "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return &kafkaConn{conn: conn}, nil
+}
+
+// connect dials the broker and authenticates with the SASL mechanism if set
+func (c *KafkaClient) connect(host string, port int) (*kafkaConn, error) {
+	conn, err := c.dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	if c.Mechanism != "" {
+		if err := c.authenticate(conn); err != nil {
+			conn.close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (c *KafkaClient) authenticate(conn *kafkaConn) error {
+	mechanism := strings.ToUpper(c.Mechanism)
+	if mechanism != mechanismPlain && mechanism != scramSHA256 && mechanism != scramSHA512 {
+		return fmt.Errorf("unsupported mechanism %s", c.Mechanism)
+	}
+	r, err := conn.request(apiSaslHandshake, versionSaslHandshake, appendString(nil, mechanism))
+	if err != nil {
+		return err
+	}
+	if err := r.decode(func() { r.checkError(r.int16(), "") }); err != nil {
+		return err
+	}
+
+	if mechanism == mechanismPlain {
+		_, err := conn.saslAuthenticate([]byte("\x00" + c.Username + "\x00" + c.Password))
+		return err
+	}
+	client, err := newScramClient(mechanism, c.Username, c.Password)
+	if err != nil {
+		return err
+	}
+	serverFirst, err := conn.saslAuthenticate([]byte(client.first()))
+	if err != nil {
+		return err
+	}
+	final, err := client.final(string(serverFirst))
+	if err != nil {
+		return err
+	}
+	serverFinal, err := conn.saslAuthenticate([]byte(final))
+	if err != nil {
+		return err
+	}
+	return client.verify(string(serverFinal))
+}
+
+// saslAuthenticate sends the SASL message and returns the message of the broker
+func (c *kafkaConn) saslAuthenticate(message []byte) ([]byte, error) {
+	r, err := c.request(apiSaslAuthenticate, versionSaslAuthenticate, appendBytes(nil, message))
+	if err != nil {
+		return nil, err
+	}
+	var response []byte
+	err = r.decode(func() {
+		code, errorMessage := r.int16(), r.string()
+		r.checkError(code, errorMessage)
+		response = r.bytes()
+	})
+	return response, err
+}
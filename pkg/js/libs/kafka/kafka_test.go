@@ -0,0 +1,319 @@
+package kafka
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker is a broker of a two brokers cluster with the events, secret
+// and moved topics, the leader of the moved topic is the broker 2
+type fakeBroker struct {
+	t          *testing.T
+	id         int32
+	mechanisms []string
+	brokers    []Broker
+}
+
+func newFakeCluster(t *testing.T, mechanisms ...string) int {
+	listeners := make([]net.Listener, 2)
+	brokers := make([]Broker, 2)
+	for i := range listeners {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err)
+		t.Cleanup(func() { _ = listener.Close() })
+		listeners[i] = listener
+		brokers[i] = Broker{ID: int32(i + 1), Host: "127.0.0.1", Port: int32(listener.Addr().(*net.TCPAddr).Port)}
+	}
+	for i, listener := range listeners {
+		broker := &fakeBroker{t: t, id: int32(i + 1), mechanisms: mechanisms, brokers: brokers}
+		go func(listener net.Listener) {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				go broker.handle(conn)
+			}
+		}(listener)
+	}
+	return int(brokers[0].Port)
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	authenticated := len(b.mechanisms) == 0
+	var mechanism string
+	var scram *scramServer
+	for {
+		size := make([]byte, 4)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return
+		}
+		request := make([]byte, binary.BigEndian.Uint32(size))
+		if _, err := io.ReadFull(conn, request); err != nil {
+			return
+		}
+		r := &reader{data: request}
+		var apiKey, version int16
+		var correlationID int32
+		require.Nil(b.t, r.decode(func() {
+			apiKey, version, correlationID = r.int16(), r.int16(), r.int32()
+			require.Equal(b.t, clientID, r.string())
+		}))
+
+		var body []byte
+		switch apiKey {
+		case apiVersions:
+			body = appendInt32(appendInt16(nil, 0), 1)
+			body = appendInt16(appendInt16(appendInt16(body, apiMetadata), 0), 12)
+		case apiSaslHandshake:
+			require.Equal(b.t, int16(versionSaslHandshake), version)
+			require.Nil(b.t, r.decode(func() { mechanism = r.string() }))
+			code := int16(errUnsupportedSaslMechanism)
+			if len(b.mechanisms) == 0 {
+				code = errIllegalSaslState
+			}
+			for _, enabled := range b.mechanisms {
+				if enabled == mechanism {
+					code = 0
+				}
+			}
+			body = appendInt32(appendInt16(nil, code), int32(len(b.mechanisms)))
+			for _, enabled := range b.mechanisms {
+				body = appendString(body, enabled)
+			}
+		case apiSaslAuthenticate:
+			var message []byte
+			require.Nil(b.t, r.decode(func() { message = r.bytes() }))
+			var response []byte
+			var ok bool
+			if mechanism == mechanismPlain {
+				ok = string(message) == "\x00admin\x00secret"
+			} else if scram == nil {
+				scram = newScramServer(string(message))
+				response = []byte(scram.first())
+				ok = true
+			} else {
+				response, ok = scram.final(string(message))
+				authenticated = ok
+			}
+			if mechanism == mechanismPlain {
+				authenticated = ok
+			}
+			if !ok {
+				body = appendString(appendInt16(nil, errSaslAuthenticationFailed), "invalid credentials")
+				body = appendBytes(body, nil)
+				break
+			}
+			body = appendBytes(appendString(appendInt16(nil, 0), ""), response)
+		default:
+			// the broker closes the unauthenticated connections
+			if !authenticated {
+				return
+			}
+			body = b.response(apiKey, version, r)
+		}
+		response := append(appendInt32(nil, correlationID), body...)
+		if _, err := conn.Write(append(appendInt32(nil, int32(len(response))), response...)); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) response(apiKey, version int16, r *reader) []byte {
+	var topic string
+	switch apiKey {
+	case apiMetadata:
+		require.Equal(b.t, int16(versionMetadata), version)
+		var topics []string
+		require.Nil(b.t, r.decode(func() {
+			for i := r.array(); i > 0; i-- {
+				topics = append(topics, r.string())
+			}
+			require.Equal(b.t, int8(0), r.int8())
+		}))
+		if topics == nil {
+			topics = []string{"events", "moved", "__consumer_offsets"}
+		}
+		body := appendInt32(appendInt32(nil, 0), int32(len(b.brokers)))
+		for _, broker := range b.brokers {
+			body = appendInt32(appendString(appendInt32(body, broker.ID), broker.Host), broker.Port)
+			body = appendInt16(body, -1)
+		}
+		body = appendInt32(appendString(body, "cluster"), 1)
+		body = appendInt32(body, int32(len(topics)))
+		for _, name := range topics {
+			leader, internal := int32(1), byte(0)
+			if name == "moved" {
+				leader = 2
+			}
+			if strings.HasPrefix(name, "__") {
+				internal = 1
+			}
+			body = append(appendString(appendInt16(body, 0), name), internal)
+			body = appendInt32(appendInt32(appendInt16(appendInt32(body, 1), 0), 0), leader)
+			body = appendInt32(appendInt32(appendInt32(body, 2), 1), 2)
+			body = appendInt32(appendInt32(body, 1), leader)
+		}
+		return body
+	case apiProduce:
+		require.Equal(b.t, int16(versionProduce), version)
+		require.Nil(b.t, r.decode(func() {
+			r.string()
+			require.Equal(b.t, int16(1), r.int16())
+			r.int32()
+			require.Equal(b.t, 1, r.array())
+			topic = r.string()
+			require.Equal(b.t, 1, r.array())
+			require.Equal(b.t, int32(0), r.int32())
+			records := r.bytes()
+			require.Equal(b.t, byte(2), records[16])
+			require.Equal(b.t, crc32.Checksum(records[21:], castagnoli), binary.BigEndian.Uint32(records[17:]))
+			require.True(b.t, strings.HasSuffix(string(records), string(produceValue)+"\x00"))
+		}))
+		body := appendString(appendInt32(nil, 1), topic)
+		body = appendInt16(appendInt32(appendInt32(body, 1), 0), b.errorCode(topic, 0))
+		return appendInt32(appendInt64(appendInt64(body, 0), -1), 0)
+	case apiFetch:
+		require.Equal(b.t, int16(versionFetch), version)
+		require.Nil(b.t, r.decode(func() {
+			require.Equal(b.t, int32(-1), r.int32())
+			r.read(13)
+			require.Equal(b.t, 1, r.array())
+			topic = r.string()
+		}))
+		body := appendString(appendInt32(appendInt32(nil, 0), 1), topic)
+		body = appendInt16(appendInt32(appendInt32(body, 1), 0), b.errorCode(topic, errOffsetOutOfRange))
+		body = appendInt64(appendInt64(body, 0), 0)
+		return appendBytes(appendInt32(body, -1), nil)
+	}
+	b.t.Errorf("unexpected api key %d", apiKey)
+	return nil
+}
+
+// errorCode returns the error code of a request of the topic
+func (b *fakeBroker) errorCode(topic string, code int16) int16 {
+	switch {
+	case topic == "secret":
+		return errTopicAuthorizationFailed
+	case topic == "moved" && b.id != 2:
+		return errNotLeaderOrFollower
+	case topic == "missing":
+		return 3
+	}
+	return code
+}
+
+// scramServer is the server side of a SCRAM-SHA-256 conversation for admin:secret
+type scramServer struct {
+	client      *scramClient
+	clientFirst string
+	serverFirst string
+}
+
+func newScramServer(clientFirst string) *scramServer {
+	client := &scramClient{hash: sha256.New, password: "secret"}
+	return &scramServer{client: client, clientFirst: strings.TrimPrefix(clientFirst, "n,,")}
+}
+
+func (s *scramServer) first() string {
+	salt := base64.StdEncoding.EncodeToString([]byte("salt"))
+	s.serverFirst = "r=" + parseScramAttributes(s.clientFirst)["r"] + "server,s=" + salt + ",i=4096"
+	return s.serverFirst
+}
+
+func (s *scramServer) final(clientFinal string) ([]byte, bool) {
+	withoutProof, proof, _ := strings.Cut(clientFinal, ",p=")
+	authMessage := s.clientFirst + "," + s.serverFirst + "," + withoutProof
+	saltedPassword := s.client.hi([]byte(s.client.password), []byte("salt"), 4096)
+	clientKey := s.client.hmac(saltedPassword, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	clientSignature := s.client.hmac(storedKey[:], authMessage)
+	expected := make([]byte, len(clientKey))
+	for i := range clientKey {
+		expected[i] = clientKey[i] ^ clientSignature[i]
+	}
+	if parseScramAttributes(s.clientFirst)["n"] != "admin" || proof != base64.StdEncoding.EncodeToString(expected) {
+		return nil, false
+	}
+	signature := s.client.hmac(s.client.hmac(saltedPassword, "Server Key"), authMessage)
+	return []byte("v=" + base64.StdEncoding.EncodeToString(signature)), true
+}
+
+func TestKafkaClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	port := newFakeCluster(t)
+	client := &KafkaClient{}
+
+	resp, err := client.IsKafka("127.0.0.1", port)
+	require.Nil(t, err)
+	require.Equal(t, IsKafkaResponse{IsKafka: true}, resp)
+
+	metadata, err := client.Metadata("127.0.0.1", port)
+	require.Nil(t, err)
+	require.Equal(t, "cluster", metadata.ClusterID)
+	require.Equal(t, int32(1), metadata.ControllerID)
+	require.Len(t, metadata.Brokers, 2)
+	require.Equal(t, Topic{Name: "moved", Partitions: []Partition{{ID: 0, Leader: 2, Replicas: []int32{1, 2}, ISR: []int32{2}}}}, metadata.Topics[1])
+	require.True(t, metadata.Topics[2].Internal)
+
+	topics, err := client.ListTopics("127.0.0.1", port)
+	require.Nil(t, err)
+	require.Equal(t, []string{"__consumer_offsets", "events", "moved"}, topics)
+
+	ok, err := client.Connect("127.0.0.1", port)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	for _, check := range []func(string, int, string) (bool, error){client.CanProduce, client.CanConsume} {
+		ok, err = check("127.0.0.1", port, "events")
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		ok, err = check("127.0.0.1", port, "moved")
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		ok, err = check("127.0.0.1", port, "secret")
+		require.Nil(t, err)
+		require.False(t, ok)
+
+		_, err = check("127.0.0.1", port, "missing")
+		require.EqualError(t, err, "UNKNOWN_TOPIC_OR_PARTITION")
+	}
+}
+
+func TestKafkaClientSASL(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	port := newFakeCluster(t, mechanismPlain, scramSHA256)
+
+	resp, err := (&KafkaClient{}).IsKafka("127.0.0.1", port)
+	require.Nil(t, err)
+	require.Equal(t, IsKafkaResponse{IsKafka: true, SASLMechanisms: []string{mechanismPlain, scramSHA256}}, resp)
+
+	_, err = (&KafkaClient{}).Connect("127.0.0.1", port)
+	require.NotNil(t, err)
+
+	for _, mechanism := range []string{"plain", scramSHA256} {
+		ok, err := (&KafkaClient{Mechanism: mechanism, Username: "admin", Password: "secret"}).Connect("127.0.0.1", port)
+		require.Nil(t, err, mechanism)
+		require.True(t, ok, mechanism)
+
+		ok, err = (&KafkaClient{Mechanism: mechanism, Username: "admin", Password: "invalid"}).Connect("127.0.0.1", port)
+		require.Nil(t, err, mechanism)
+		require.False(t, ok, mechanism)
+	}
+
+	_, err = (&KafkaClient{Mechanism: scramSHA512, Username: "admin", Password: "secret"}).Connect("127.0.0.1", port)
+	require.EqualError(t, err, "UNSUPPORTED_SASL_MECHANISM")
+}
@@ -0,0 +1,244 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// api keys of the requests
+const (
+	apiProduce          = 0
+	apiFetch            = 1
+	apiMetadata         = 3
+	apiSaslHandshake    = 17
+	apiVersions         = 18
+	apiSaslAuthenticate = 36
+)
+
+// versions of the requests, the oldest non flexible ones supported by Kafka 4
+const (
+	versionProduce          = 3
+	versionFetch            = 4
+	versionMetadata         = 4
+	versionSaslHandshake    = 1
+	versionApiVersions      = 0
+	versionSaslAuthenticate = 0
+)
+
+// clientID is the client id of the requests
+const clientID = "nuclei"
+
+// maxResponseSize is the maximum size of a response (16MB)
+const maxResponseSize = 16 * 1024 * 1024
+
+// error codes of the responses
+const (
+	errOffsetOutOfRange           = 1
+	errNotLeaderOrFollower        = 6
+	errTopicAuthorizationFailed   = 29
+	errClusterAuthorizationFailed = 31
+	errIllegalSaslState           = 34
+	errUnsupportedSaslMechanism   = 33
+	errSaslAuthenticationFailed   = 58
+)
+
+// errorNames are the names of the common error codes
+var errorNames = map[int16]string{
+	-1: "UNKNOWN_SERVER_ERROR",
+	1:  "OFFSET_OUT_OF_RANGE",
+	2:  "CORRUPT_MESSAGE",
+	3:  "UNKNOWN_TOPIC_OR_PARTITION",
+	5:  "LEADER_NOT_AVAILABLE",
+	6:  "NOT_LEADER_OR_FOLLOWER",
+	7:  "REQUEST_TIMED_OUT",
+	10: "MESSAGE_TOO_LARGE",
+	29: "TOPIC_AUTHORIZATION_FAILED",
+	31: "CLUSTER_AUTHORIZATION_FAILED",
+	33: "UNSUPPORTED_SASL_MECHANISM",
+	34: "ILLEGAL_SASL_STATE",
+	35: "UNSUPPORTED_VERSION",
+	58: "SASL_AUTHENTICATION_FAILED",
+	87: "INVALID_RECORD",
+}
+
+// kafkaError is an error code of a response
+type kafkaError struct {
+	Code    int16
+	Message string
+}
+
+func (e *kafkaError) Error() string {
+	name, ok := errorNames[e.Code]
+	if !ok {
+		name = fmt.Sprintf("kafka error %d", e.Code)
+	}
+	if e.Message != "" {
+		return name + ": " + e.Message
+	}
+	return name
+}
+
+// kafkaConn is a connection to a broker
+type kafkaConn struct {
+	conn          net.Conn
+	correlationID int32
+}
+
+// request sends the request and returns the reader of the response body
+func (c *kafkaConn) request(apiKey, version int16, body []byte) (*reader, error) {
+	c.correlationID++
+	header := appendInt16(nil, apiKey)
+	header = appendInt16(header, version)
+	header = appendInt32(header, c.correlationID)
+	header = appendString(header, clientID)
+
+	request := appendInt32(nil, int32(len(header)+len(body)))
+	request = append(append(request, header...), body...)
+	if _, err := c.conn.Write(request); err != nil {
+		return nil, err
+	}
+
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(c.conn, size); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(size)
+	if length < 4 || length > maxResponseSize {
+		return nil, fmt.Errorf("invalid response size %d", length)
+	}
+	response := make([]byte, length)
+	if _, err := io.ReadFull(c.conn, response); err != nil {
+		return nil, err
+	}
+	if correlationID := int32(binary.BigEndian.Uint32(response)); correlationID != c.correlationID {
+		return nil, fmt.Errorf("unexpected correlation id %d", correlationID)
+	}
+	return &reader{data: response[4:]}, nil
+}
+
+func (c *kafkaConn) close() {
+	_ = c.conn.Close()
+}
+
+func appendInt16(buf []byte, value int16) []byte {
+	return binary.BigEndian.AppendUint16(buf, uint16(value))
+}
+
+func appendInt32(buf []byte, value int32) []byte {
+	return binary.BigEndian.AppendUint32(buf, uint32(value))
+}
+
+func appendInt64(buf []byte, value int64) []byte {
+	return binary.BigEndian.AppendUint64(buf, uint64(value))
+}
+
+func appendString(buf []byte, value string) []byte {
+	return append(appendInt16(buf, int16(len(value))), value...)
+}
+
+func appendBytes(buf []byte, value []byte) []byte {
+	return append(appendInt32(buf, int32(len(value))), value...)
+}
+
+// reader reads the values of a response body panicking on malformed data
+type reader struct {
+	data []byte
+	pos  int
+}
+
+type decodeError struct {
+	message string
+}
+
+func (e *decodeError) Error() string {
+	return e.message
+}
+
+// decode runs the function and returns its decode or kafka error
+func (r *reader) decode(fn func()) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			switch value := recovered.(type) {
+			case *decodeError:
+				err = errors.Wrap(value, "could not decode response")
+			case *kafkaError:
+				err = value
+			default:
+				panic(recovered)
+			}
+		}
+	}()
+	fn()
+	return nil
+}
+
+func (r *reader) fail(format string, args ...interface{}) {
+	panic(&decodeError{message: fmt.Sprintf(format, args...)})
+}
+
+func (r *reader) read(n int) []byte {
+	if n < 0 || len(r.data)-r.pos < n {
+		r.fail("unexpected end of response")
+	}
+	value := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return value
+}
+
+func (r *reader) int8() int8 {
+	return int8(r.read(1)[0])
+}
+
+func (r *reader) int16() int16 {
+	return int16(binary.BigEndian.Uint16(r.read(2)))
+}
+
+func (r *reader) int32() int32 {
+	return int32(binary.BigEndian.Uint32(r.read(4)))
+}
+
+func (r *reader) int64() int64 {
+	return int64(binary.BigEndian.Uint64(r.read(8)))
+}
+
+// string returns the value of a nullable string, empty if null
+func (r *reader) string() string {
+	length := r.int16()
+	if length < 0 {
+		return ""
+	}
+	return string(r.read(int(length)))
+}
+
+// bytes returns the value of nullable bytes, nil if null
+func (r *reader) bytes() []byte {
+	length := r.int32()
+	if length < 0 {
+		return nil
+	}
+	return r.read(int(length))
+}
+
+// array returns the length of an array, zero if null
+func (r *reader) array() int {
+	length := r.int32()
+	if length < 0 {
+		return 0
+	}
+	// each element is at least a byte long
+	if int(length) > len(r.data)-r.pos {
+		r.fail("invalid array length %d", length)
+	}
+	return int(length)
+}
+
+// checkError panics with the kafka error of the code
+func (r *reader) checkError(code int16, message string) {
+	if code != 0 {
+		panic(&kafkaError{Code: code, Message: message})
+	}
+}
@@ -0,0 +1,43 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"time"
+)
+
+// castagnoli is the crc table of the record batches
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// recordBatch returns a record batch of the magic version 2 with a single
+// record of the value and a null key, the batch is not transactional
+func recordBatch(value []byte, timestamp time.Time) []byte {
+	record := []byte{0}                     // attributes
+	record = binary.AppendVarint(record, 0) // timestamp delta
+	record = binary.AppendVarint(record, 0) // offset delta
+	record = binary.AppendVarint(record, -1)
+	record = binary.AppendVarint(record, int64(len(value)))
+	record = append(record, value...)
+	record = binary.AppendVarint(record, 0) // headers
+	record = append(binary.AppendVarint(nil, int64(len(record))), record...)
+
+	millis := timestamp.UnixMilli()
+	// the crc covers the batch from the attributes
+	batch := appendInt16(nil, 0)  // attributes
+	batch = appendInt32(batch, 0) // last offset delta
+	batch = appendInt64(batch, millis)
+	batch = appendInt64(batch, millis)
+	batch = appendInt64(batch, -1) // producer id
+	batch = appendInt16(batch, -1) // producer epoch
+	batch = appendInt32(batch, -1) // base sequence
+	batch = appendInt32(batch, 1)  // records
+	batch = append(batch, record...)
+
+	header := appendInt64(nil, 0) // base offset
+	// the length covers the batch from the partition leader epoch
+	header = appendInt32(header, int32(4+1+4+len(batch)))
+	header = appendInt32(header, -1) // partition leader epoch
+	header = append(header, 2)       // magic
+	header = binary.BigEndian.AppendUint32(header, crc32.Checksum(batch, castagnoli))
+	return append(header, batch...)
+}
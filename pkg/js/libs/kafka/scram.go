@@ -0,0 +1,137 @@
+package kafka
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sasl mechanisms supported by the client
+const (
+	mechanismPlain  = "PLAIN"
+	scramSHA256     = "SCRAM-SHA-256"
+	scramSHA512     = "SCRAM-SHA-512"
+	minIterations   = 4096
+	scramNonceBytes = 24
+)
+
+// scramClient is the client side of a SCRAM conversation (RFC 5802)
+type scramClient struct {
+	hash     func() hash.Hash
+	username string
+	password string
+	nonce    string
+
+	clientFirstBare string
+	serverSignature []byte
+}
+
+func newScramClient(mechanism, username, password string) (*scramClient, error) {
+	nonce := make([]byte, scramNonceBytes)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	client := &scramClient{username: username, password: password, nonce: base64.StdEncoding.EncodeToString(nonce)}
+	switch mechanism {
+	case scramSHA256:
+		client.hash = sha256.New
+	case scramSHA512:
+		client.hash = sha512.New
+	default:
+		return nil, errors.Errorf("unsupported mechanism %s", mechanism)
+	}
+	return client, nil
+}
+
+// first returns the client first message
+func (s *scramClient) first() string {
+	username := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(s.username)
+	s.clientFirstBare = "n=" + username + ",r=" + s.nonce
+	return "n,," + s.clientFirstBare
+}
+
+// final returns the client final message answering the server first message
+func (s *scramClient) final(serverFirst string) (string, error) {
+	attributes := parseScramAttributes(serverFirst)
+	nonce, salt, iterations := attributes["r"], attributes["s"], attributes["i"]
+	if !strings.HasPrefix(nonce, s.nonce) || len(nonce) == len(s.nonce) {
+		return "", errors.New("invalid server nonce")
+	}
+	decodedSalt, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid server salt")
+	}
+	count, err := strconv.Atoi(iterations)
+	if err != nil || count < minIterations {
+		return "", errors.Errorf("invalid iteration count %s", iterations)
+	}
+
+	saltedPassword := s.hi([]byte(s.password), decodedSalt, count)
+	clientKey := s.hmac(saltedPassword, "Client Key")
+	storedKey := s.hash()
+	storedKey.Write(clientKey)
+
+	clientFinal := "c=biws,r=" + nonce
+	authMessage := s.clientFirstBare + "," + serverFirst + "," + clientFinal
+	clientSignature := s.hmac(storedKey.Sum(nil), authMessage)
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ clientSignature[i]
+	}
+	s.serverSignature = s.hmac(s.hmac(saltedPassword, "Server Key"), authMessage)
+	return clientFinal + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+// verify verifies the server signature of the server final message
+func (s *scramClient) verify(serverFinal string) error {
+	attributes := parseScramAttributes(serverFinal)
+	if message, ok := attributes["e"]; ok {
+		return errors.Errorf("authentication failed: %s", message)
+	}
+	signature, err := base64.StdEncoding.DecodeString(attributes["v"])
+	if err != nil || !hmac.Equal(signature, s.serverSignature) {
+		return errors.New("invalid server signature")
+	}
+	return nil
+}
+
+func (s *scramClient) hmac(key []byte, message string) []byte {
+	mac := hmac.New(s.hash, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// hi is the PBKDF2 function of the RFC with the hmac of the hash
+func (s *scramClient) hi(password, salt []byte, iterations int) []byte {
+	mac := hmac.New(s.hash, password)
+	mac.Write(salt)
+	mac.Write([]byte{0, 0, 0, 1})
+	previous := mac.Sum(nil)
+	result := append([]byte(nil), previous...)
+	for i := 1; i < iterations; i++ {
+		mac.Reset()
+		mac.Write(previous)
+		previous = mac.Sum(previous[:0])
+		for j := range result {
+			result[j] ^= previous[j]
+		}
+	}
+	return result
+}
+
+func parseScramAttributes(message string) map[string]string {
+	attributes := make(map[string]string)
+	for _, attribute := range strings.Split(message, ",") {
+		if key, value, ok := strings.Cut(attribute, "="); ok {
+			attributes[key] = value
+		}
+	}
+	return attributes
+}
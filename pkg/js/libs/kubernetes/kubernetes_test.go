@@ -0,0 +1,88 @@
+package kubernetes
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+const pods = `{"items": [{"metadata": {"namespace": "kube-system", "name": "etcd"}, "spec": {"nodeName": "node-1", "serviceAccountName": "default", "containers": [{"image": "registry.k8s.io/etcd:3.5.9"}]}, "status": {"phase": "Running"}}]}`
+
+func TestKubernetesClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	// the anonymous requests are authenticated but only allowed on the version and the kubelet pods,
+	// the token of the service account is allowed on the secrets of the default namespace
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		switch {
+		case r.URL.Path == "/version":
+			_, _ = w.Write([]byte(`{"gitVersion": "v1.28.2", "platform": "linux/amd64"}`))
+		case r.URL.Path == "/pods":
+			_, _ = w.Write([]byte(pods))
+		case authorization != "" && authorization != "Bearer token":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"kind": "Status", "reason": "Unauthorized"}`))
+		case authorization == "":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"kind": "Status", "reason": "Forbidden"}`))
+		case r.URL.Path == "/api/v1/namespaces/default/secrets":
+			_, _ = w.Write([]byte(`{"items": [{"metadata": {"namespace": "default", "name": "db"}, "type": "Opaque", "data": {"password": "cGFzcw==", "username": "dXNlcg=="}}]}`))
+		case r.URL.Path == "/api/v1/pods":
+			_, _ = w.Write([]byte(pods))
+		case r.URL.Path == "/apis/authentication.k8s.io/v1beta1/selfsubjectreviews":
+			body, _ := io.ReadAll(r.Body)
+			require.JSONEq(t, `{"apiVersion": "authentication.k8s.io/v1beta1", "kind": "SelfSubjectReview"}`, string(body))
+			_, _ = w.Write([]byte(`{"status": {"userInfo": {"username": "system:serviceaccount:default:reader", "groups": ["system:serviceaccounts"]}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind": "Status", "reason": "NotFound"}`))
+		}
+	}))
+	defer server.Close()
+
+	host, portValue, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+
+	anonymous := &KubernetesClient{HTTPS: true}
+	version, err := anonymous.Version(host, port)
+	require.Nil(t, err)
+	require.Equal(t, VersionResponse{GitVersion: "v1.28.2", Platform: "linux/amd64"}, version)
+
+	access, err := anonymous.CheckAccess(host, port, "api/v1/namespaces")
+	require.Nil(t, err)
+	require.Equal(t, AccessResponse{StatusCode: http.StatusForbidden, Authenticated: true}, access)
+
+	access, err = (&KubernetesClient{HTTPS: true, Token: "invalid"}).CheckAccess(host, port, "/api/v1/namespaces")
+	require.Nil(t, err)
+	require.Equal(t, AccessResponse{StatusCode: http.StatusUnauthorized}, access)
+
+	expected := []Pod{{Namespace: "kube-system", Name: "etcd", NodeName: "node-1", Phase: "Running", ServiceAccount: "default", Images: []string{"registry.k8s.io/etcd:3.5.9"}}}
+	kubeletPods, err := anonymous.ListKubeletPods(host, port)
+	require.Nil(t, err)
+	require.Equal(t, expected, kubeletPods)
+
+	_, err = anonymous.ListPods(host, port, "")
+	require.EqualError(t, err, `unexpected status code 403: {"kind": "Status", "reason": "Forbidden"}`)
+
+	client := &KubernetesClient{HTTPS: true, Token: "token"}
+	listedPods, err := client.ListPods(host, port, "")
+	require.Nil(t, err)
+	require.Equal(t, expected, listedPods)
+
+	secrets, err := client.ListSecrets(host, port, "default")
+	require.Nil(t, err)
+	require.Equal(t, []Secret{{Namespace: "default", Name: "db", Type: "Opaque", Keys: []string{"password", "username"}}}, secrets)
+
+	user, err := client.WhoAmI(host, port)
+	require.Nil(t, err)
+	require.Equal(t, UserInfo{Username: "system:serviceaccount:default:reader", Groups: []string{"system:serviceaccounts"}}, user)
+}
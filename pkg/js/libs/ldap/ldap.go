@@ -2,7 +2,9 @@ package ldap
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -16,7 +18,14 @@ import (
 // Client is a client for ldap protocol in golang.
 //
 // It is a wrapper around the standard library ldap package.
-type LdapClient struct{}
+type LdapClient struct {
+	// TLS enables the ldaps connections, the default port is 636 if set
+	TLS bool
+	// StartTLS upgrades the plain connections to TLS with the StartTLS extended operation
+	StartTLS bool
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool
+}
 
 // IsLdap checks if the given host and port are running ldap server.
 func (c *LdapClient) IsLdap(host string, port int) (bool, error) {
@@ -83,16 +92,33 @@ func (c *LdapClient) newLdapSession(opts *ldapSessionOptions) (*ldap.Conn, error
 	dc := opts.domainController
 	if port == 0 {
 		port = 389
+		if c.TLS {
+			port = 636
+		}
 	}
 
-	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", dc, port))
+	var conn net.Conn
+	var err error
+	tlsConfig := &tls.Config{ServerName: dc, InsecureSkipVerify: c.InsecureSkipVerify, MinVersion: tls.VersionTLS10}
+	if c.TLS {
+		conn, err = protocolstate.Dialer.DialTLSWithConfig(context.TODO(), "tcp", fmt.Sprintf("%s:%d", dc, port), tlsConfig)
+	} else {
+		conn, err = protocolstate.Dialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", dc, port))
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	lConn := ldap.NewConn(conn, false)
+	lConn := ldap.NewConn(conn, c.TLS)
+	lConn.SetTimeout(10 * time.Second)
 	lConn.Start()
 
+	if c.StartTLS && !c.TLS {
+		if err := lConn.StartTLS(tlsConfig); err != nil {
+			lConn.Close()
+			return nil, err
+		}
+	}
 	return lConn, nil
 }
 
@@ -114,13 +140,7 @@ type LDAPMetadata struct {
 func (c *LdapClient) collectLdapMetadata(lConn *ldap.Conn, opts *ldapSessionOptions) (LDAPMetadata, error) {
 	metadata := LDAPMetadata{}
 
-	var err error
-	if opts.username == "" {
-		err = lConn.UnauthenticatedBind("")
-	} else {
-		err = lConn.Bind(opts.username, opts.password)
-	}
-	if err != nil {
+	if err := bind(lConn, opts); err != nil {
 		return metadata, err
 	}
 
@@ -187,7 +207,7 @@ func getBaseNamingContext(opts *ldapSessionOptions, conn *ldap.Conn) (string, er
 		ldap.NeverDerefAliases,
 		0, 0, false,
 		"(objectClass=*)",
-		[]string{"defaultNamingContext"},
+		[]string{"defaultNamingContext", "namingContexts"},
 		nil)
 	res, err := conn.Search(sr)
 	if err != nil {
@@ -197,6 +217,10 @@ func getBaseNamingContext(opts *ldapSessionOptions, conn *ldap.Conn) (string, er
 		return "", fmt.Errorf("error getting metadata: No LDAP responses from server")
 	}
 	defaultNamingContext := res.Entries[0].GetAttributeValue("defaultNamingContext")
+	if defaultNamingContext == "" {
+		// the servers other than active directory only list their naming contexts
+		defaultNamingContext = res.Entries[0].GetAttributeValue("namingContexts")
+	}
 	if defaultNamingContext == "" {
 		return "", fmt.Errorf("error getting metadata: attribute defaultNamingContext missing")
 	}
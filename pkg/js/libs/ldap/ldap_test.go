@@ -0,0 +1,259 @@
+package ldap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// ldapServer is a ldap server with the dc=example,dc=com naming context,
+// the cn=admin,dc=example,dc=com user has the secret password
+type ldapServer struct {
+	// anonymous allows the anonymous searches of the naming context
+	anonymous bool
+	tlsConfig *tls.Config
+	entries   []*ldap.Entry
+	// pages is the number of the paged searches
+	pages int32
+}
+
+func (s *ldapServer) listen(t *testing.T) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	host, portValue, err := net.SplitHostPort(listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	return host, port
+}
+
+func (s *ldapServer) serve(conn net.Conn) {
+	defer func() {
+		conn.Close()
+	}()
+	bound := false
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			username := request.Children[1].Value.(string)
+			password := request.Children[2].Data.String()
+			code := uint16(ldap.LDAPResultInvalidCredentials)
+			if username == "" || (username == "cn=admin,dc=example,dc=com" && password == "secret") {
+				code = ldap.LDAPResultSuccess
+				bound = username != ""
+			}
+			s.write(conn, id, result(ldap.ApplicationBindResponse, code), nil)
+		case ldap.ApplicationExtendedRequest:
+			s.write(conn, id, result(ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess), nil)
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+		case ldap.ApplicationSearchRequest:
+			s.search(conn, id, request, packet, bound)
+		default:
+			return
+		}
+	}
+}
+
+func (s *ldapServer) search(conn net.Conn, id int64, request, packet *ber.Packet, bound bool) {
+	baseDN := request.Children[0].Value.(string)
+	if baseDN == "" {
+		rootDSE := ldap.NewEntry("", map[string][]string{"namingContexts": {"dc=example,dc=com"}})
+		s.write(conn, id, entry(rootDSE), nil)
+		s.write(conn, id, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess), nil)
+		return
+	}
+	if !bound && !s.anonymous {
+		s.write(conn, id, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultOperationsError), nil)
+		return
+	}
+	filter, _ := ldap.DecompileFilter(request.Children[6])
+	entries := s.entries
+	if filter == "(objectClass=person)" {
+		entries = entries[1:]
+	}
+
+	var paging *ldap.ControlPaging
+	if len(packet.Children) > 2 {
+		control, err := ldap.DecodeControl(packet.Children[2].Children[0])
+		if err == nil {
+			paging, _ = control.(*ldap.ControlPaging)
+		}
+	}
+	var controls *ber.Packet
+	if paging != nil && paging.PagingSize > 0 {
+		atomic.AddInt32(&s.pages, 1)
+		offset, _ := strconv.Atoi(string(paging.Cookie))
+		end := offset + int(paging.PagingSize)
+		cookie := strconv.Itoa(end)
+		if end >= len(entries) {
+			end, cookie = len(entries), ""
+		}
+		entries = entries[offset:end]
+		controls = ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+		controls.AppendChild((&ldap.ControlPaging{Cookie: []byte(cookie)}).Encode())
+	}
+	for _, item := range entries {
+		s.write(conn, id, entry(item), nil)
+	}
+	s.write(conn, id, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess), controls)
+}
+
+func (s *ldapServer) write(conn net.Conn, id int64, response, controls *ber.Packet) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	packet.AppendChild(response)
+	if controls != nil {
+		packet.AppendChild(controls)
+	}
+	_, _ = conn.Write(packet.Bytes())
+}
+
+func result(tag ber.Tag, code uint16) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return packet
+}
+
+func entry(item *ldap.Entry) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Entry")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, item.DN, "DN"))
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for _, attribute := range item.Attributes {
+		value := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		value.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attribute.Name, "Type"))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, item := range attribute.Values {
+			values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, item, "Value"))
+		}
+		value.AppendChild(values)
+		attributes.AppendChild(value)
+	}
+	packet.AppendChild(attributes)
+	return packet
+}
+
+func newLdapServer() *ldapServer {
+	return &ldapServer{
+		entries: []*ldap.Entry{
+			ldap.NewEntry("dc=example,dc=com", map[string][]string{"objectClass": {"domain"}}),
+			ldap.NewEntry("cn=alice,dc=example,dc=com", map[string][]string{"cn": {"alice"}, "objectSid": {"\x01\x05\xff"}}),
+			ldap.NewEntry("cn=bob,dc=example,dc=com", map[string][]string{"cn": {"bob"}}),
+			ldap.NewEntry("cn=carol,dc=example,dc=com", map[string][]string{"cn": {"carol"}}),
+		},
+	}
+}
+
+func TestLdapClientSearch(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	server := newLdapServer()
+	host, port := server.listen(t)
+	client := &LdapClient{}
+
+	data, err := client.Search(host, port, "cn=admin,dc=example,dc=com", "secret", "", "(objectClass=person)", nil, 0)
+	require.Nil(t, err)
+	entries := []Entry{}
+	require.Nil(t, json.Unmarshal([]byte(data), &entries))
+	require.Equal(t, []Entry{
+		{DN: "cn=alice,dc=example,dc=com", Attributes: map[string][]string{"cn": {"alice"}, "objectSid": {"0x0105ff"}}},
+		{DN: "cn=bob,dc=example,dc=com", Attributes: map[string][]string{"cn": {"bob"}}},
+		{DN: "cn=carol,dc=example,dc=com", Attributes: map[string][]string{"cn": {"carol"}}},
+	}, entries)
+
+	data, err = client.Search(host, port, "cn=admin,dc=example,dc=com", "secret", "dc=example,dc=com", "", []string{"cn"}, 3)
+	require.Nil(t, err)
+	require.Nil(t, json.Unmarshal([]byte(data), &entries))
+	require.Len(t, entries, 4)
+	require.Equal(t, int32(2), atomic.LoadInt32(&server.pages))
+
+	_, err = client.Search(host, port, "cn=admin,dc=example,dc=com", "wrong", "", "", nil, 0)
+	require.True(t, ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials))
+
+	_, err = client.Search(host, port, "", "", "", "(cn=alice", nil, 0)
+	require.NotNil(t, err, "the filter should be invalid")
+}
+
+func TestLdapClientIsAnonymousBindAllowed(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	server := newLdapServer()
+	host, port := server.listen(t)
+	client := &LdapClient{}
+
+	allowed, err := client.IsAnonymousBindAllowed(host, port)
+	require.Nil(t, err)
+	require.False(t, allowed)
+
+	server.anonymous = true
+	allowed, err = client.IsAnonymousBindAllowed(host, port)
+	require.Nil(t, err)
+	require.True(t, allowed)
+}
+
+func TestLdapClientStartTLS(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ldap"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+
+	server := newLdapServer()
+	server.anonymous = true
+	server.tlsConfig = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	host, port := server.listen(t)
+
+	client := &LdapClient{StartTLS: true}
+	_, err = client.IsAnonymousBindAllowed(host, port)
+	require.NotNil(t, err, "the self signed certificate should not be trusted")
+
+	client.InsecureSkipVerify = true
+	allowed, err := client.IsAnonymousBindAllowed(host, port)
+	require.Nil(t, err)
+	require.True(t, allowed)
+}
@@ -0,0 +1,177 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// maxEntries is the maximum number of entries returned by a search
+const maxEntries = 1000
+
+// Entry is an entry returned by a search.
+type Entry struct {
+	DN string `json:"dn"`
+	// Attributes are the values of the attributes, the binary values are hex encoded with the 0x prefix
+	Attributes map[string][]string `json:"attributes"`
+}
+
+// Search tries to connect to provided host and port, binds with the
+// username and password and searches the subtree of the base DN with the filter.
+//
+// The naming context of the server is used if the base DN is empty, the
+// (objectClass=*) filter if the filter is empty and all the attributes
+// are returned if none are given. The results are fetched by pages of
+// pageSize entries if set, at most 1000 entries are returned.
+// An anonymous bind is used if the username is empty.
+//
+// Returns the entries as a JSON array of objects with the dn and attributes keys.
+func (c *LdapClient) Search(host string, port int, username, password, baseDN, filter string, attributes []string, pageSize int) (string, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return "", protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port < 0 {
+		return "", fmt.Errorf("invalid host or port")
+	}
+	if filter == "" {
+		filter = "(objectClass=*)"
+	}
+	if _, err := ldap.CompileFilter(filter); err != nil {
+		return "", err
+	}
+
+	opts := &ldapSessionOptions{
+		domainController: host,
+		port:             port,
+		username:         username,
+		password:         password,
+		baseDN:           baseDN,
+	}
+	conn, err := c.newLdapSession(opts)
+	if err != nil {
+		return "", err
+	}
+	defer c.close(conn)
+
+	if err := bind(conn, opts); err != nil {
+		return "", err
+	}
+	baseDN, err = getBaseNamingContext(opts, conn)
+	if err != nil {
+		return "", err
+	}
+
+	request := ldap.NewSearchRequest(
+		baseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		maxEntries, 0, false,
+		filter,
+		attributes,
+		nil)
+	var result *ldap.SearchResult
+	if pageSize > 0 {
+		result, err = conn.SearchWithPaging(request, uint32(pageSize))
+	} else {
+		result, err = conn.Search(request)
+	}
+	// the entries received before the size limit is exceeded are returned
+	if err != nil && !(ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) && result != nil) {
+		return "", err
+	}
+
+	entries := make([]Entry, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		if len(entries) >= maxEntries {
+			break
+		}
+		entries = append(entries, newEntry(entry))
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// IsAnonymousBindAllowed tries to connect to provided host and port
+// and checks if the naming context of the server can be searched
+// with an anonymous bind.
+func (c *LdapClient) IsAnonymousBindAllowed(host string, port int) (bool, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return false, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port < 0 {
+		return false, fmt.Errorf("invalid host or port")
+	}
+
+	opts := &ldapSessionOptions{
+		domainController: host,
+		port:             port,
+	}
+	conn, err := c.newLdapSession(opts)
+	if err != nil {
+		return false, err
+	}
+	defer c.close(conn)
+
+	if err := bind(conn, opts); err != nil {
+		if ldap.IsErrorAnyOf(err, ldap.LDAPResultInvalidCredentials, ldap.LDAPResultInappropriateAuthentication, ldap.LDAPResultUnwillingToPerform) {
+			return false, nil
+		}
+		return false, err
+	}
+	// the root DSE is readable by anyone, the naming context is searched instead
+	baseDN, err := getBaseNamingContext(opts, conn)
+	if err != nil {
+		return false, err
+	}
+	request := ldap.NewSearchRequest(
+		baseDN,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		1, 0, false,
+		"(objectClass=*)",
+		[]string{"objectClass"},
+		nil)
+	if _, err := conn.Search(request); err != nil {
+		var ldapErr *ldap.Error
+		if errors.As(err, &ldapErr) && ldapErr.ResultCode != ldap.ErrorNetwork {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// bind binds with the username and password of the options, anonymously if the username is empty
+func bind(conn *ldap.Conn, opts *ldapSessionOptions) error {
+	if opts.username == "" {
+		return conn.UnauthenticatedBind("")
+	}
+	return conn.Bind(opts.username, opts.password)
+}
+
+// newEntry returns the entry of a search result entry
+func newEntry(entry *ldap.Entry) Entry {
+	result := Entry{DN: entry.DN, Attributes: make(map[string][]string, len(entry.Attributes))}
+	for _, attribute := range entry.Attributes {
+		values := make([]string, 0, len(attribute.ByteValues))
+		for _, value := range attribute.ByteValues {
+			if utf8.Valid(value) {
+				values = append(values, string(value))
+			} else {
+				values = append(values, "0x"+hex.EncodeToString(value))
+			}
+		}
+		result.Attributes[attribute.Name] = values
+	}
+	return result
+}
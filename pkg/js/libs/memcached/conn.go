@@ -0,0 +1,143 @@
+package memcached
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout is the timeout of the connections to the server
+	timeout = 10 * time.Second
+	// udpHeaderSize is the size of the frame header of the udp datagrams
+	udpHeaderSize = 8
+	// maxResponseSize is the maximum size of an udp response (1MB)
+	maxResponseSize = 1024 * 1024
+)
+
+// commandError is the refusal of a command by the server
+type commandError struct {
+	message string
+}
+
+func (e *commandError) Error() string {
+	return e.message
+}
+
+// memcachedConn is a connection to a server over tcp or udp
+type memcachedConn struct {
+	conn      net.Conn
+	udp       bool
+	requestID uint16
+}
+
+func dial(host string, port int, udp bool) (*memcachedConn, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	network := "tcp"
+	if udp {
+		network = "udp"
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), network, net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return &memcachedConn{conn: conn, udp: udp}, nil
+}
+
+// command sends the command and calls the function with the lines of the response
+// until the END line or until the function returns false
+func (c *memcachedConn) command(command string, fn func(line string) bool) error {
+	var scanner *bufio.Scanner
+	if c.udp {
+		response, _, err := c.udpRequest(command)
+		if err != nil {
+			return err
+		}
+		scanner = bufio.NewScanner(bytes.NewReader(response))
+	} else {
+		if _, err := c.conn.Write([]byte(command + "\r\n")); err != nil {
+			return err
+		}
+		scanner = bufio.NewScanner(c.conn)
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		switch {
+		case line == "END":
+			return nil
+		case line == "ERROR":
+			return &commandError{message: "unknown command"}
+		case strings.HasPrefix(line, "CLIENT_ERROR "), strings.HasPrefix(line, "SERVER_ERROR "), strings.HasPrefix(line, "BUSY "):
+			return &commandError{message: strings.ToLower(line)}
+		}
+		if !fn(line) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// udpRequest sends the command in a datagram and returns the response reassembled
+// from its datagrams with the total size of the datagrams
+func (c *memcachedConn) udpRequest(command string) ([]byte, int, error) {
+	c.requestID++
+	request := binary.BigEndian.AppendUint16(nil, c.requestID)
+	request = append(request, 0, 0, 0, 1, 0, 0)
+	if _, err := c.conn.Write(append(request, command+"\r\n"...)); err != nil {
+		return nil, 0, err
+	}
+
+	var parts [][]byte
+	var received, size int
+	buf := make([]byte, 65535)
+	for parts == nil || received < len(parts) {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			return nil, 0, err
+		}
+		if n < udpHeaderSize || binary.BigEndian.Uint16(buf) != c.requestID {
+			continue
+		}
+		sequence, total := int(binary.BigEndian.Uint16(buf[2:])), int(binary.BigEndian.Uint16(buf[4:]))
+		if parts == nil {
+			if total == 0 {
+				return nil, 0, errors.New("invalid datagram count")
+			}
+			parts = make([][]byte, total)
+		}
+		if sequence >= len(parts) || parts[sequence] != nil {
+			continue
+		}
+		if size += n; size > maxResponseSize {
+			return nil, 0, fmt.Errorf("response of more than %d bytes", maxResponseSize)
+		}
+		parts[sequence] = append([]byte{}, buf[udpHeaderSize:n]...)
+		received++
+	}
+	return bytes.Join(parts, nil), size, nil
+}
+
+func (c *memcachedConn) close() {
+	_ = c.conn.Close()
+}
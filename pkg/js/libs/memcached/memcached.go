@@ -0,0 +1,148 @@
+package memcached
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultSampleSize is the default number of keys sampled
+	defaultSampleSize = 100
+	// maxSampleSize is the maximum number of keys sampled
+	maxSampleSize = 1000
+)
+
+// MemcachedClient is a client for memcached servers.
+//
+// The commands use the text protocol over tcp by default or over udp,
+// which most servers only enable on old versions or by misconfiguration.
+type MemcachedClient struct {
+	// UDP sends the commands over udp instead of tcp
+	UDP bool
+}
+
+// AmplificationResponse is the response from the Amplification function.
+type AmplificationResponse struct {
+	RequestSize  int
+	ResponseSize int
+	// Factor is the ratio of the response size to the request size
+	Factor float64
+}
+
+// Version returns the version of the server.
+func (c *MemcachedClient) Version(host string, port int) (string, error) {
+	var version string
+	err := c.command(host, port, "version", func(line string) bool {
+		version = strings.TrimPrefix(line, "VERSION ")
+		return false
+	})
+	return version, err
+}
+
+// Stats returns the general statistics of the server.
+func (c *MemcachedClient) Stats(host string, port int) (map[string]string, error) {
+	stats := make(map[string]string)
+	err := c.command(host, port, "stats", func(line string) bool {
+		if fields := strings.SplitN(line, " ", 3); len(fields) == 3 && fields[0] == "STAT" {
+			stats[fields[1]] = fields[2]
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// SampleKeys returns at most limit keys of the cache, 100 keys if not positive and at most 1000.
+//
+// The keys are dumped by the lru crawler of the 1.4.31 and later versions
+// and by the cachedump command of the slabs on the older ones.
+func (c *MemcachedClient) SampleKeys(host string, port int, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = defaultSampleSize
+	}
+	limit = min(limit, maxSampleSize)
+
+	var keys []string
+	err := c.command(host, port, "lru_crawler metadump all", func(line string) bool {
+		if key, ok := strings.CutPrefix(strings.Fields(line + " ")[0], "key="); ok {
+			if unescaped, err := url.QueryUnescape(key); err == nil {
+				key = unescaped
+			}
+			keys = append(keys, key)
+		}
+		return len(keys) < limit
+	})
+	if err == nil {
+		return keys, nil
+	}
+	// the lru crawler is missing or disabled
+	var commandErr *commandError
+	if !errors.As(err, &commandErr) {
+		return nil, err
+	}
+
+	var slabs []string
+	err = c.command(host, port, "stats items", func(line string) bool {
+		// STAT items:<slab>:number <count>
+		if fields := strings.Split(line, ":"); len(fields) == 3 && strings.HasPrefix(fields[2], "number ") {
+			slabs = append(slabs, fields[1])
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys = nil
+	for _, slab := range slabs {
+		if _, err := strconv.Atoi(slab); err != nil {
+			continue
+		}
+		err := c.command(host, port, "stats cachedump "+slab+" "+strconv.Itoa(limit-len(keys)), func(line string) bool {
+			// ITEM <key> [<size> b; <expiration> s]
+			if fields := strings.Fields(line); len(fields) > 1 && fields[0] == "ITEM" {
+				keys = append(keys, fields[1])
+			}
+			return len(keys) < limit
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) >= limit {
+			break
+		}
+	}
+	return keys, nil
+}
+
+// Amplification sends the stats command over udp and returns the sizes of
+// the request and of the response datagrams to verify the amplification vector.
+func (c *MemcachedClient) Amplification(host string, port int) (AmplificationResponse, error) {
+	resp := AmplificationResponse{}
+	conn, err := dial(host, port, true)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.close()
+
+	_, size, err := conn.udpRequest("stats")
+	if err != nil {
+		return resp, err
+	}
+	resp.RequestSize = udpHeaderSize + len("stats\r\n")
+	resp.ResponseSize = size
+	resp.Factor = float64(resp.ResponseSize) / float64(resp.RequestSize)
+	return resp, nil
+}
+
+func (c *MemcachedClient) command(host string, port int, command string, fn func(line string) bool) error {
+	conn, err := dial(host, port, c.UDP)
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+	return conn.command(command, fn)
+}
@@ -0,0 +1,136 @@
+package memcached
+
+import (
+	"bufio"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// responses returns the response of the command, the lru crawler is missing on the legacy servers
+func responses(command string, legacy bool) string {
+	switch command {
+	case "version":
+		return "VERSION 1.6.21\r\n"
+	case "stats":
+		return "STAT pid 1\r\nSTAT version 1.6.21\r\nSTAT curr_items 3\r\nEND\r\n"
+	case "lru_crawler metadump all":
+		if legacy {
+			return "ERROR\r\n"
+		}
+		return "key=session%3A1 exp=-1 la=1700000000 cas=1 fetch=no cls=1 size=63\r\nkey=user exp=-1 la=1700000000 cas=2 fetch=no cls=1 size=60\r\nkey=token exp=-1 la=1700000000 cas=3 fetch=no cls=2 size=90\r\nEND\r\n"
+	case "stats items":
+		return "STAT items:1:number 2\r\nSTAT items:1:age 10\r\nSTAT items:2:number 1\r\nEND\r\n"
+	case "stats cachedump 1 2", "stats cachedump 1 100":
+		return "ITEM session:1 [3 b; 0 s]\r\nITEM user [3 b; 0 s]\r\nEND\r\n"
+	case "stats cachedump 2 98":
+		return "ITEM token [5 b; 0 s]\r\nEND\r\n"
+	}
+	return "ERROR\r\n"
+}
+
+func newFakeServer(t *testing.T, legacy bool) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					if _, err := conn.Write([]byte(responses(strings.TrimSuffix(scanner.Text(), "\r"), legacy))); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// newFakeUDPServer answers the commands with the responses split in two datagrams, the last one first
+func newFakeUDPServer(t *testing.T) int {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			require.Equal(t, []byte{0, 0, 0, 1, 0, 0}, buf[2:udpHeaderSize])
+			response := responses(strings.TrimSuffix(string(buf[udpHeaderSize:n]), "\r\n"), false)
+			parts := []string{response[:len(response)/2], response[len(response)/2:]}
+			for _, sequence := range []int{1, 0} {
+				header := append(append([]byte{}, buf[:2]...), 0, byte(sequence), 0, 2, 0, 0)
+				_, _ = conn.WriteTo(append(header, parts[sequence]...), addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestMemcachedClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	tcpPort, udpPort := newFakeServer(t, false), newFakeUDPServer(t)
+
+	for _, client := range []*MemcachedClient{{}, {UDP: true}} {
+		port := tcpPort
+		if client.UDP {
+			port = udpPort
+		}
+
+		version, err := client.Version("127.0.0.1", port)
+		require.Nil(t, err)
+		require.Equal(t, "1.6.21", version)
+
+		stats, err := client.Stats("127.0.0.1", port)
+		require.Nil(t, err)
+		require.Equal(t, map[string]string{"pid": "1", "version": "1.6.21", "curr_items": "3"}, stats)
+
+		keys, err := client.SampleKeys("127.0.0.1", port, 2)
+		require.Nil(t, err)
+		require.Equal(t, []string{"session:1", "user"}, keys)
+	}
+
+	keys, err := (&MemcachedClient{}).SampleKeys("127.0.0.1", newFakeServer(t, true), 0)
+	require.Nil(t, err)
+	require.Equal(t, []string{"session:1", "user", "token"}, keys)
+
+	resp, err := (&MemcachedClient{}).Amplification("127.0.0.1", udpPort)
+	require.Nil(t, err)
+	require.Equal(t, udpHeaderSize+len("stats\r\n"), resp.RequestSize)
+	require.Equal(t, 2*udpHeaderSize+len(responses("stats", false)), resp.ResponseSize)
+	require.InDelta(t, float64(resp.ResponseSize)/15, resp.Factor, 0.001)
+}
+
+func TestUDPRequestSize(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		request := make([]byte, 64)
+		_, _ = server.Read(request)
+		datagram := binary.BigEndian.AppendUint16(append(request[:2:2], 0, 0), 0xffff)
+		datagram = append(append(datagram, 0, 0), make([]byte, 60000)...)
+		for i := 0; i < 20; i++ {
+			binary.BigEndian.PutUint16(datagram[2:], uint16(i))
+			if _, err := server.Write(datagram); err != nil {
+				return
+			}
+		}
+	}()
+	_, _, err := (&memcachedConn{conn: client, udp: true}).udpRequest("stats")
+	require.EqualError(t, err, "response of more than 1048576 bytes")
+}
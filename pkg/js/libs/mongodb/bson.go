@@ -0,0 +1,544 @@
+package mongodb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// bson element types
+const (
+	typeDouble     = 0x01
+	typeString     = 0x02
+	typeDocument   = 0x03
+	typeArray      = 0x04
+	typeBinary     = 0x05
+	typeUndefined  = 0x06
+	typeObjectID   = 0x07
+	typeBool       = 0x08
+	typeDateTime   = 0x09
+	typeNull       = 0x0A
+	typeRegex      = 0x0B
+	typeDBPointer  = 0x0C
+	typeJavascript = 0x0D
+	typeSymbol     = 0x0E
+	typeCodeScope  = 0x0F
+	typeInt32      = 0x10
+	typeTimestamp  = 0x11
+	typeInt64      = 0x12
+	typeDecimal128 = 0x13
+	typeMinKey     = 0xFF
+	typeMaxKey     = 0x7F
+)
+
+// maxDocumentDepth is the maximum nesting of the decoded documents
+const maxDocumentDepth = 100
+
+// element is a key value pair of a bson document
+type element struct {
+	key   string
+	value interface{}
+}
+
+// document is an ordered bson document, the order of the keys
+// matters as the first key of a command is its name
+type document []element
+
+// lookup returns the value of the key in the document
+func (d document) lookup(key string) (interface{}, bool) {
+	for _, e := range d {
+		if e.key == key {
+			return e.value, true
+		}
+	}
+	return nil, false
+}
+
+// bson values without a native go type
+type (
+	objectID   [12]byte
+	timestamp  struct{ t, i uint32 }
+	regex      struct{ pattern, options string }
+	binaryData struct {
+		subtype byte
+		data    []byte
+	}
+	decimal128 struct{ high, low uint64 }
+	minKey     struct{}
+	maxKey     struct{}
+)
+
+// marshal encodes the document to bson
+func (d document) marshal() ([]byte, error) {
+	return appendDocument(nil, d)
+}
+
+func appendDocument(buf []byte, d document) ([]byte, error) {
+	start := len(buf)
+	buf = append(buf, 0, 0, 0, 0)
+	for _, e := range d {
+		var err error
+		if buf, err = appendElement(buf, e.key, e.value); err != nil {
+			return nil, err
+		}
+	}
+	buf = append(buf, 0)
+	binary.LittleEndian.PutUint32(buf[start:], uint32(len(buf)-start))
+	return buf, nil
+}
+
+func appendCString(buf []byte, value string) ([]byte, error) {
+	if strings.IndexByte(value, 0) != -1 {
+		return nil, errors.Errorf("invalid null byte in %q", value)
+	}
+	return append(append(buf, value...), 0), nil
+}
+
+func appendString(buf []byte, value string) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)+1))
+	return append(append(buf, value...), 0)
+}
+
+func appendElement(buf []byte, key string, value interface{}) ([]byte, error) {
+	var valueType byte
+	var encoded []byte
+	switch v := value.(type) {
+	case float64:
+		valueType, encoded = typeDouble, binary.LittleEndian.AppendUint64(nil, math.Float64bits(v))
+	case string:
+		valueType, encoded = typeString, appendString(nil, v)
+	case document:
+		doc, err := appendDocument(nil, v)
+		if err != nil {
+			return nil, err
+		}
+		valueType, encoded = typeDocument, doc
+	case []interface{}:
+		array := make(document, len(v))
+		for i, item := range v {
+			array[i] = element{key: strconv.Itoa(i), value: item}
+		}
+		doc, err := appendDocument(nil, array)
+		if err != nil {
+			return nil, err
+		}
+		valueType, encoded = typeArray, doc
+	case binaryData:
+		encoded = binary.LittleEndian.AppendUint32(nil, uint32(len(v.data)))
+		valueType, encoded = typeBinary, append(append(encoded, v.subtype), v.data...)
+	case objectID:
+		valueType, encoded = typeObjectID, v[:]
+	case bool:
+		valueType, encoded = typeBool, []byte{0}
+		if v {
+			encoded[0] = 1
+		}
+	case time.Time:
+		valueType, encoded = typeDateTime, binary.LittleEndian.AppendUint64(nil, uint64(v.UnixMilli()))
+	case nil:
+		valueType = typeNull
+	case int32:
+		valueType, encoded = typeInt32, binary.LittleEndian.AppendUint32(nil, uint32(v))
+	case int:
+		if v >= math.MinInt32 && v <= math.MaxInt32 {
+			valueType, encoded = typeInt32, binary.LittleEndian.AppendUint32(nil, uint32(v))
+		} else {
+			valueType, encoded = typeInt64, binary.LittleEndian.AppendUint64(nil, uint64(v))
+		}
+	case int64:
+		valueType, encoded = typeInt64, binary.LittleEndian.AppendUint64(nil, uint64(v))
+	default:
+		return nil, errors.Errorf("unsupported bson value %T", value)
+	}
+	buf = append(buf, valueType)
+	buf, err := appendCString(buf, key)
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, encoded...), nil
+}
+
+// decoder decodes bson documents panicking on malformed data
+type decoder struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+type decodeError struct {
+	message string
+}
+
+func (e *decodeError) Error() string {
+	return e.message
+}
+
+// unmarshal decodes a bson document
+func unmarshal(data []byte) (doc document, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			decodeErr, ok := recovered.(*decodeError)
+			if !ok {
+				panic(recovered)
+			}
+			doc, err = nil, errors.Wrap(decodeErr, "could not decode bson")
+		}
+	}()
+
+	d := &decoder{data: data}
+	doc = d.document()
+	if d.pos != len(data) {
+		d.fail("trailing data after document")
+	}
+	return doc, nil
+}
+
+func (d *decoder) fail(format string, args ...interface{}) {
+	panic(&decodeError{message: fmt.Sprintf(format, args...)})
+}
+
+func (d *decoder) read(n int) []byte {
+	if n < 0 || len(d.data)-d.pos < n {
+		d.fail("unexpected end of data")
+	}
+	value := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return value
+}
+
+func (d *decoder) int32() int32 {
+	return int32(binary.LittleEndian.Uint32(d.read(4)))
+}
+
+func (d *decoder) uint64() uint64 {
+	return binary.LittleEndian.Uint64(d.read(8))
+}
+
+func (d *decoder) cstring() string {
+	end := bytes.IndexByte(d.data[d.pos:], 0)
+	if end == -1 {
+		d.fail("unterminated cstring")
+	}
+	value := string(d.data[d.pos : d.pos+end])
+	d.pos += end + 1
+	return value
+}
+
+func (d *decoder) string() string {
+	length := int(d.int32())
+	if length < 1 {
+		d.fail("invalid string length %d", length)
+	}
+	value := d.read(length)
+	if value[length-1] != 0 {
+		d.fail("unterminated string")
+	}
+	return string(value[:length-1])
+}
+
+func (d *decoder) document() document {
+	d.depth++
+	if d.depth > maxDocumentDepth {
+		d.fail("document nesting exceeds %d", maxDocumentDepth)
+	}
+	defer func() { d.depth-- }()
+
+	start := d.pos
+	length := int(d.int32())
+	if length < 5 || len(d.data)-start < length {
+		d.fail("invalid document length %d", length)
+	}
+	end := start + length
+
+	doc := document{}
+	for {
+		if d.pos >= end {
+			d.fail("unterminated document")
+		}
+		valueType := d.read(1)[0]
+		if valueType == 0 {
+			break
+		}
+		key := d.cstring()
+		doc = append(doc, element{key: key, value: d.value(valueType)})
+	}
+	if d.pos != end {
+		d.fail("invalid document length %d", length)
+	}
+	return doc
+}
+
+func (d *decoder) value(valueType byte) interface{} {
+	switch valueType {
+	case typeDouble:
+		return math.Float64frombits(d.uint64())
+	case typeString, typeJavascript, typeSymbol:
+		return d.string()
+	case typeDocument:
+		return d.document()
+	case typeArray:
+		doc := d.document()
+		array := make([]interface{}, len(doc))
+		for i, e := range doc {
+			array[i] = e.value
+		}
+		return array
+	case typeBinary:
+		length := int(d.int32())
+		subtype := d.read(1)[0]
+		data := d.read(length)
+		return binaryData{subtype: subtype, data: append([]byte(nil), data...)}
+	case typeUndefined, typeNull:
+		return nil
+	case typeObjectID:
+		var id objectID
+		copy(id[:], d.read(12))
+		return id
+	case typeBool:
+		return d.read(1)[0] != 0
+	case typeDateTime:
+		return time.UnixMilli(int64(d.uint64())).UTC()
+	case typeRegex:
+		return regex{pattern: d.cstring(), options: d.cstring()}
+	case typeDBPointer:
+		namespace := d.string()
+		var id objectID
+		copy(id[:], d.read(12))
+		return document{{key: "$ref", value: namespace}, {key: "$id", value: id}}
+	case typeCodeScope:
+		start := d.pos
+		length := int(d.int32())
+		code := d.string()
+		scope := d.document()
+		if d.pos-start != length {
+			d.fail("invalid code with scope length %d", length)
+		}
+		return document{{key: "$code", value: code}, {key: "$scope", value: scope}}
+	case typeInt32:
+		return d.int32()
+	case typeTimestamp:
+		value := d.uint64()
+		return timestamp{t: uint32(value >> 32), i: uint32(value)}
+	case typeInt64:
+		return int64(d.uint64())
+	case typeDecimal128:
+		low := d.uint64()
+		return decimal128{high: d.uint64(), low: low}
+	case typeMinKey:
+		return minKey{}
+	case typeMaxKey:
+		return maxKey{}
+	}
+	d.fail("unknown bson type 0x%02x", valueType)
+	return nil
+}
+
+// MarshalJSON encodes the document to relaxed extended json keeping the order of the keys
+func (d document) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, e := range d {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(e.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(extendedJSON(e.value))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// extendedJSON returns the json representation of bson values without a json type
+func extendedJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, item := range v {
+			array[i] = extendedJSON(item)
+		}
+		return array
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return document{{key: "$numberDouble", value: strconv.FormatFloat(v, 'f', -1, 64)}}
+		}
+		return v
+	case binaryData:
+		return document{{key: "$binary", value: document{
+			{key: "base64", value: base64.StdEncoding.EncodeToString(v.data)},
+			{key: "subType", value: fmt.Sprintf("%02x", v.subtype)},
+		}}}
+	case objectID:
+		return document{{key: "$oid", value: hex.EncodeToString(v[:])}}
+	case time.Time:
+		return document{{key: "$date", value: v.Format(time.RFC3339Nano)}}
+	case regex:
+		return document{{key: "$regularExpression", value: document{{key: "pattern", value: v.pattern}, {key: "options", value: v.options}}}}
+	case timestamp:
+		return document{{key: "$timestamp", value: document{{key: "t", value: int64(v.t)}, {key: "i", value: int64(v.i)}}}}
+	case decimal128:
+		return document{{key: "$numberDecimal", value: v.String()}}
+	case minKey:
+		return document{{key: "$minKey", value: int32(1)}}
+	case maxKey:
+		return document{{key: "$maxKey", value: int32(1)}}
+	}
+	return value
+}
+
+// String returns the scientific string representation of the decimal
+func (d decimal128) String() string {
+	sign := ""
+	if d.high>>63 == 1 {
+		sign = "-"
+	}
+	var exponent int
+	coefficient := new(big.Int)
+	switch {
+	case (d.high>>58)&0x1f == 0x1f:
+		return "NaN"
+	case (d.high>>58)&0x1f == 0x1e:
+		return sign + "Infinity"
+	case (d.high>>61)&0x3 == 0x3:
+		// the coefficient of the large form is always out of range and read as zero
+		exponent = int((d.high >> 47) & 0x3fff)
+	default:
+		exponent = int((d.high >> 49) & 0x3fff)
+		coefficient.SetUint64(d.high & 0x1ffffffffffff)
+		coefficient.Lsh(coefficient, 64)
+		coefficient.Or(coefficient, new(big.Int).SetUint64(d.low))
+	}
+	exponent -= 6176
+
+	digits := coefficient.String()
+	adjusted := exponent + len(digits) - 1
+	switch {
+	case exponent == 0:
+		return sign + digits
+	case exponent < 0 && adjusted >= -6:
+		if point := len(digits) + exponent; point > 0 {
+			return sign + digits[:point] + "." + digits[point:]
+		}
+		return sign + "0." + strings.Repeat("0", -(len(digits)+exponent)) + digits
+	}
+	mantissa := digits[:1]
+	if len(digits) > 1 {
+		mantissa += "." + digits[1:]
+	}
+	return fmt.Sprintf("%s%sE%+d", sign, mantissa, adjusted)
+}
+
+// parseJSONDocument decodes a json object to a document keeping the order of the keys,
+// integers are decoded as int32 or int64 and {"$oid": "..."} objects as object ids.
+func parseJSONDocument(data string) (document, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse json")
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("json value is not an object")
+	}
+	doc, err := parseJSONObject(decoder)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse json")
+	}
+	if _, err := decoder.Token(); err == nil {
+		return nil, errors.New("trailing data after json object")
+	}
+	return doc, nil
+}
+
+func parseJSONObject(decoder *json.Decoder) (document, error) {
+	doc := document{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		value, err := parseJSONValue(decoder)
+		if err != nil {
+			return nil, err
+		}
+		doc = append(doc, element{key: key, value: value})
+	}
+	// consume the closing delimiter
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// parseObjectID returns the object id of an {"$oid": "..."} object
+func parseObjectID(doc document) (objectID, bool) {
+	var id objectID
+	if len(doc) != 1 || doc[0].key != "$oid" {
+		return id, false
+	}
+	value, ok := doc[0].value.(string)
+	if !ok {
+		return id, false
+	}
+	decoded, err := hex.DecodeString(value)
+	if err != nil || len(decoded) != len(id) {
+		return id, false
+	}
+	copy(id[:], decoded)
+	return id, true
+}
+
+func parseJSONValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '[' {
+			array := []interface{}{}
+			for decoder.More() {
+				item, err := parseJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, item)
+			}
+			_, err := decoder.Token()
+			return array, err
+		}
+		doc, err := parseJSONObject(decoder)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := parseObjectID(doc); ok {
+			return id, nil
+		}
+		return doc, nil
+	case json.Number:
+		if integer, err := value.Int64(); err == nil {
+			if integer >= math.MinInt32 && integer <= math.MaxInt32 {
+				return int32(integer), nil
+			}
+			return integer, nil
+		}
+		return value.Float64()
+	}
+	return token, nil
+}
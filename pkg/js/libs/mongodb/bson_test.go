@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBSON(t *testing.T) {
+	doc := document{
+		{key: "find", value: "users"},
+		{key: "filter", value: document{{key: "age", value: int32(42)}, {key: "score", value: 1.5}}},
+		{key: "tags", value: []interface{}{"a", int64(1) << 40, nil, true}},
+		{key: "_id", value: objectID{0x65, 0x1f}},
+		{key: "payload", value: binaryData{subtype: 4, data: []byte("data")}},
+		{key: "created", value: time.UnixMilli(1700000000123).UTC()},
+	}
+	data, err := doc.marshal()
+	require.Nil(t, err)
+	decoded, err := unmarshal(data)
+	require.Nil(t, err)
+	require.Equal(t, doc, decoded)
+
+	encoded, err := decoded.MarshalJSON()
+	require.Nil(t, err)
+	require.JSONEq(t, `{
+		"find": "users",
+		"filter": {"age": 42, "score": 1.5},
+		"tags": ["a", 1099511627776, null, true],
+		"_id": {"$oid": "651f00000000000000000000"},
+		"payload": {"$binary": {"base64": "ZGF0YQ==", "subType": "04"}},
+		"created": {"$date": "2023-11-14T22:13:20.123Z"}
+	}`, string(encoded))
+	require.Regexp(t, `^\{"find":"users","filter":\{"age":42,"score":1.5\}`, string(encoded), "could not keep the order of the keys")
+
+	_, err = document{{key: "invalid\x00key", value: int32(1)}}.marshal()
+	require.Error(t, err)
+	_, err = document{{key: "unsupported", value: struct{}{}}}.marshal()
+	require.Error(t, err)
+
+	for _, invalid := range [][]byte{
+		nil,
+		{5, 0, 0, 0},
+		{6, 0, 0, 0, 0x10, 0},
+		{12, 0, 0, 0, 0x02, 'a', 0, 0xff, 0xff, 0xff, 0x7f, 0},
+		{8, 0, 0, 0, 0x42, 'a', 0, 0},
+		append(data, 0),
+	} {
+		_, err := unmarshal(invalid)
+		require.Error(t, err, "could decode invalid bson %v", invalid)
+	}
+}
+
+func TestDecimal128(t *testing.T) {
+	tests := []struct {
+		value    decimal128
+		expected string
+	}{
+		{value: decimal128{high: 0x3040000000000000, low: 1}, expected: "1"},
+		{value: decimal128{high: 0x303e000000000000, low: 15}, expected: "1.5"},
+		{value: decimal128{high: 0xb03c000000000000, low: 5}, expected: "-0.05"},
+		{value: decimal128{high: 0x3042000000000000, low: 12}, expected: "1.2E+2"},
+		{value: decimal128{high: 0x7800000000000000}, expected: "Infinity"},
+		{value: decimal128{high: 0x7c00000000000000}, expected: "NaN"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, test.value.String())
+	}
+}
+
+func TestParseJSONDocument(t *testing.T) {
+	doc, err := parseJSONDocument(`{"find": "users", "filter": {"_id": {"$oid": "651f00000000000000000000"}, "age": {"$gt": 3000000000}}, "limit": 1.5, "names": ["a", {}]}`)
+	require.Nil(t, err)
+	require.Equal(t, document{
+		{key: "find", value: "users"},
+		{key: "filter", value: document{
+			{key: "_id", value: objectID{0x65, 0x1f}},
+			{key: "age", value: document{{key: "$gt", value: int64(3000000000)}}},
+		}},
+		{key: "limit", value: 1.5},
+		{key: "names", value: []interface{}{"a", document{}}},
+	}, doc)
+
+	for _, invalid := range []string{``, `[]`, `{"a": 1} {}`, `{"a": }`} {
+		_, err := parseJSONDocument(invalid)
+		require.Error(t, err, "could parse invalid json %s", invalid)
+	}
+}
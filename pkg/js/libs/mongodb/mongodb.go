@@ -0,0 +1,189 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// timeout is the timeout of the connections to the database
+const timeout = 10 * time.Second
+
+// MongoDBClient is a client for MongoDB database.
+//
+// Internally client implements the MongoDB wire protocol, users
+// are authenticated against the admin database with SCRAM-SHA-1
+// or SCRAM-SHA-256.
+type MongoDBClient struct{}
+
+// IsMongoDB checks if the given host and port are running MongoDB database.
+//
+// If connection is successful, it returns true.
+// If connection is unsuccessful, it returns false and error.
+func (c *MongoDBClient) IsMongoDB(host string, port int) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.close()
+
+	hello, err := conn.handshake("")
+	if err != nil {
+		return false, nil
+	}
+	_, hasWireVersion := hello.lookup("maxWireVersion")
+	_, hasMaster := hello.lookup("ismaster")
+	return hasWireVersion || hasMaster, nil
+}
+
+// Connect connects to MongoDB database using given credentials.
+//
+// If the username is empty, the connection is unauthenticated and
+// is successful only if the database does not enforce authorization.
+//
+// If connection is successful, it returns true.
+// If connection is unsuccessful, it returns false and error.
+func (c *MongoDBClient) Connect(host string, port int, username, password string) (bool, error) {
+	conn, err := connect(host, port, username, password)
+	if err != nil {
+		if isAuthError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer conn.close()
+
+	if username != "" {
+		return true, nil
+	}
+	if _, err := conn.runCommand("admin", listDatabasesCommand()); err != nil {
+		if isAuthError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ListDatabases connects to MongoDB database using given credentials
+// and returns the names of the databases.
+//
+// If the username is empty, the connection is unauthenticated.
+func (c *MongoDBClient) ListDatabases(host string, port int, username, password string) ([]string, error) {
+	conn, err := connect(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	reply, err := conn.runCommand("admin", listDatabasesCommand())
+	if err != nil {
+		return nil, err
+	}
+	databases, _ := reply.lookup("databases")
+	items, _ := databases.([]interface{})
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if database, ok := item.(document); ok {
+			names = append(names, toString(database, "name"))
+		}
+	}
+	return names, nil
+}
+
+// RunCommand connects to MongoDB database using given credentials and
+// runs a command on the db, returning the reply as extended json.
+//
+// The command is a json object whose first key is the command name,
+// ex: {"find": "users", "limit": 10}. Object ids can be given as
+// {"$oid": "..."} objects.
+//
+// If the username is empty, the connection is unauthenticated.
+func (c *MongoDBClient) RunCommand(host string, port int, username, password, dbName, command string) (string, error) {
+	parsed, err := parseJSONDocument(command)
+	if err != nil {
+		return "", err
+	}
+	if len(parsed) == 0 {
+		return "", errors.New("empty command")
+	}
+
+	conn, err := connect(host, port, username, password)
+	if err != nil {
+		return "", err
+	}
+	defer conn.close()
+
+	reply, err := conn.runCommand(dbName, parsed)
+	if err != nil {
+		return "", err
+	}
+	data, err := reply.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func listDatabasesCommand() document {
+	return document{{key: "listDatabases", value: int32(1)}, {key: "nameOnly", value: true}}
+}
+
+// isAuthError returns true if the error is an authentication or authorization failure
+func isAuthError(err error) bool {
+	var cmdErr *commandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == codeAuthenticationFailed || cmdErr.Code == codeUnauthorized)
+}
+
+func dial(host string, port int) (*wireConn, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return &wireConn{conn: conn}, nil
+}
+
+// connect dials the database and authenticates the user if the username is not empty
+func connect(host string, port int, username, password string) (*wireConn, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.login(username, password); err != nil {
+		conn.close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// login runs the handshake and authenticates the user if the username is not empty
+func (c *wireConn) login(username, password string) error {
+	hello, err := c.handshake(username)
+	if err != nil {
+		return err
+	}
+	if username == "" {
+		return nil
+	}
+	value, _ := hello.lookup("saslSupportedMechs")
+	mechanisms, _ := value.([]interface{})
+	return c.authenticate(username, password, mechanisms)
+}
+
+func (c *wireConn) close() {
+	_ = c.conn.Close()
+}
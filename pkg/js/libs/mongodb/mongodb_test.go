@@ -0,0 +1,185 @@
+package mongodb
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScramClient(t *testing.T) {
+	// test vectors of RFC 5802 and RFC 7677
+	tests := []struct {
+		client      *scramClient
+		serverFirst string
+		clientFinal string
+		serverFinal string
+	}{
+		{
+			client:      &scramClient{hash: sha1.New, username: "user", password: "pencil", nonce: "fyko+d2lbbFgONRv9qkxdawL"},
+			serverFirst: "r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,s=QSXCR+Q6sek8bf92,i=4096",
+			clientFinal: "c=biws,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,p=v0X8v3Bz2T0CJGbJQyF0X+HI4Ts=",
+			serverFinal: "v=rmF9pqV8S7suAoZWja4dJRkFsKQ=",
+		},
+		{
+			client:      &scramClient{hash: sha256.New, username: "user", password: "pencil", nonce: "rOprNGfwEbeRWgbNEkqO"},
+			serverFirst: "r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096",
+			clientFinal: "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+			serverFinal: "v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=",
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, "n,,n=user,r="+test.client.nonce, test.client.first())
+		final, err := test.client.final(test.serverFirst)
+		require.Nil(t, err)
+		require.Equal(t, test.clientFinal, final)
+		require.Nil(t, test.client.verify(test.serverFinal))
+		require.Error(t, test.client.verify("v=aW52YWxpZA=="), "could verify invalid server signature")
+	}
+
+	client := &scramClient{hash: sha256.New, username: "user", password: "pencil", nonce: "nonce"}
+	client.first()
+	_, err := client.final("r=other,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+	require.Error(t, err, "could accept invalid server nonce")
+	_, err = client.final("r=nonceserver,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=1")
+	require.Error(t, err, "could accept low iteration count")
+}
+
+// fakeServer is a MongoDB server with the admin:secret user speaking OP_QUERY
+// for the handshake and OP_MSG for the commands
+type fakeServer struct {
+	t             *testing.T
+	conn          net.Conn
+	authenticated bool
+	scram         *scramClient
+	serverFirst   string
+	clientFirst   string
+}
+
+func (s *fakeServer) serve() {
+	defer s.conn.Close()
+	for {
+		header := make([]byte, 16)
+		if _, err := io.ReadFull(s.conn, header); err != nil {
+			return
+		}
+		body := make([]byte, binary.LittleEndian.Uint32(header)-16)
+		if _, err := io.ReadFull(s.conn, body); err != nil {
+			return
+		}
+		requestID := binary.LittleEndian.Uint32(header[4:])
+
+		var reply []byte
+		switch binary.LittleEndian.Uint32(header[12:]) {
+		case opQuery:
+			collection := body[4 : 4+strings.IndexByte(string(body[4:]), 0)]
+			require.Equal(s.t, "admin.$cmd", string(collection))
+			command, err := unmarshal(body[4+len(collection)+1+8:])
+			require.Nil(s.t, err)
+			encoded, err := s.handle(command).marshal()
+			require.Nil(s.t, err)
+			// response flags, cursor id, starting from and number returned
+			reply = append(make([]byte, 16), 1, 0, 0, 0)
+			reply = writeMessage(reply, opReply, requestID, encoded)
+		case opMsg:
+			command, err := unmarshal(body[5:])
+			require.Nil(s.t, err)
+			encoded, err := s.handle(command).marshal()
+			require.Nil(s.t, err)
+			reply = writeMessage([]byte{0, 0, 0, 0, 0}, opMsg, requestID, encoded)
+		}
+		if _, err := s.conn.Write(reply); err != nil {
+			return
+		}
+	}
+}
+
+func writeMessage(prefix []byte, opCode, responseTo uint32, encoded []byte) []byte {
+	body := append(prefix, encoded...)
+	message := make([]byte, 16, 16+len(body))
+	binary.LittleEndian.PutUint32(message, uint32(16+len(body)))
+	binary.LittleEndian.PutUint32(message[8:], responseTo)
+	binary.LittleEndian.PutUint32(message[12:], opCode)
+	return append(message, body...)
+}
+
+func (s *fakeServer) handle(command document) document {
+	failure := func(code int32, message string) document {
+		return document{{key: "ok", value: 0.0}, {key: "errmsg", value: message}, {key: "code", value: code}}
+	}
+	switch command[0].key {
+	case "isMaster":
+		reply := document{{key: "ismaster", value: true}, {key: "maxWireVersion", value: int32(17)}, {key: "ok", value: 1.0}}
+		if user, ok := command.lookup("saslSupportedMechs"); ok && user == "admin.admin" {
+			reply = append(reply, element{key: "saslSupportedMechs", value: []interface{}{scramSHA1, scramSHA256}})
+		}
+		return reply
+	case "saslStart":
+		require.Equal(s.t, scramSHA256, toString(command, "mechanism"))
+		s.clientFirst = strings.TrimPrefix(scramPayload(command), "n,,")
+		attributes := parseScramAttributes(s.clientFirst)
+		s.scram = &scramClient{hash: sha256.New, username: attributes["n"], password: "secret"}
+		s.serverFirst = "r=" + attributes["r"] + "server,s=" + base64.StdEncoding.EncodeToString([]byte("salt")) + ",i=4096"
+		return document{{key: "conversationId", value: int32(1)}, {key: "done", value: false}, {key: "payload", value: binaryData{data: []byte(s.serverFirst)}}, {key: "ok", value: 1.0}}
+	case "saslContinue":
+		// the expected client final message is computed with the client of the password
+		s.scram.clientFirstBare = s.clientFirst
+		s.scram.nonce = parseScramAttributes(s.clientFirst)["r"]
+		expected, err := s.scram.final(s.serverFirst)
+		require.Nil(s.t, err)
+		if s.scram.username != "admin" || !hmac.Equal([]byte(expected), []byte(scramPayload(command))) {
+			return failure(codeAuthenticationFailed, "Authentication failed.")
+		}
+		s.authenticated = true
+		serverFinal := "v=" + base64.StdEncoding.EncodeToString(s.scram.serverSignature)
+		return document{{key: "conversationId", value: int32(1)}, {key: "done", value: true}, {key: "payload", value: binaryData{data: []byte(serverFinal)}}, {key: "ok", value: 1.0}}
+	case "listDatabases":
+		require.Equal(s.t, "admin", toString(command, "$db"))
+		if !s.authenticated {
+			return failure(codeUnauthorized, "command listDatabases requires authentication")
+		}
+		return document{{key: "databases", value: []interface{}{
+			document{{key: "name", value: "admin"}},
+			document{{key: "name", value: "users"}},
+		}}, {key: "ok", value: 1.0}}
+	}
+	return failure(59, "no such command: '"+command[0].key+"'")
+}
+
+func newFakeConn(t *testing.T) *wireConn {
+	client, server := net.Pipe()
+	go (&fakeServer{t: t, conn: server}).serve()
+	t.Cleanup(func() { _ = client.Close() })
+	return &wireConn{conn: client}
+}
+
+func TestWireConn(t *testing.T) {
+	conn := newFakeConn(t)
+	hello, err := conn.handshake("")
+	require.Nil(t, err)
+	require.True(t, conn.opMsg, "could not detect op msg support")
+	require.Equal(t, int64(17), toInt64(hello, "maxWireVersion"))
+
+	_, err = conn.runCommand("admin", listDatabasesCommand())
+	require.True(t, isAuthError(err), "could run command without authentication")
+	_, err = conn.runCommand("admin", document{{key: "unknown", value: int32(1)}})
+	require.EqualError(t, err, "command failed with code 59: no such command: 'unknown'")
+
+	conn = newFakeConn(t)
+	require.Nil(t, conn.login("admin", "secret"))
+	reply, err := conn.runCommand("admin", listDatabasesCommand())
+	require.Nil(t, err)
+	databases, _ := reply.lookup("databases")
+	require.Len(t, databases, 2)
+
+	conn = newFakeConn(t)
+	err = conn.login("admin", "invalid")
+	require.True(t, isAuthError(err), "could authenticate with invalid password")
+}
@@ -0,0 +1,200 @@
+package mongodb
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// scram mechanisms supported by the client
+const (
+	scramSHA1   = "SCRAM-SHA-1"
+	scramSHA256 = "SCRAM-SHA-256"
+)
+
+// minIterations is the minimum iteration count accepted from the server
+const minIterations = 4096
+
+// scramClient is the client side of a SCRAM conversation (RFC 5802)
+type scramClient struct {
+	hash     func() hash.Hash
+	username string
+	password string
+	nonce    string
+
+	clientFirstBare string
+	serverSignature []byte
+}
+
+// newScramClient returns a client of the mechanism, MongoDB hashes the
+// password of SCRAM-SHA-1 with the username before the conversation
+func newScramClient(mechanism, username, password string) (*scramClient, error) {
+	nonce := make([]byte, 24)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	client := &scramClient{username: username, password: password, nonce: base64.StdEncoding.EncodeToString(nonce)}
+	switch mechanism {
+	case scramSHA1:
+		digest := md5.Sum([]byte(username + ":mongo:" + password))
+		client.hash, client.password = sha1.New, hex.EncodeToString(digest[:])
+	case scramSHA256:
+		client.hash = sha256.New
+	default:
+		return nil, errors.Errorf("unsupported mechanism %s", mechanism)
+	}
+	return client, nil
+}
+
+// first returns the client first message
+func (s *scramClient) first() string {
+	username := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(s.username)
+	s.clientFirstBare = "n=" + username + ",r=" + s.nonce
+	return "n,," + s.clientFirstBare
+}
+
+// final returns the client final message answering the server first message
+func (s *scramClient) final(serverFirst string) (string, error) {
+	attributes := parseScramAttributes(serverFirst)
+	nonce, salt, iterations := attributes["r"], attributes["s"], attributes["i"]
+	if !strings.HasPrefix(nonce, s.nonce) || len(nonce) == len(s.nonce) {
+		return "", errors.New("invalid server nonce")
+	}
+	decodedSalt, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid server salt")
+	}
+	count, err := strconv.Atoi(iterations)
+	if err != nil || count < minIterations {
+		return "", errors.Errorf("invalid iteration count %s", iterations)
+	}
+
+	saltedPassword := s.hi([]byte(s.password), decodedSalt, count)
+	clientKey := s.hmac(saltedPassword, "Client Key")
+	storedKey := s.hash()
+	storedKey.Write(clientKey)
+
+	clientFinal := "c=biws,r=" + nonce
+	authMessage := s.clientFirstBare + "," + serverFirst + "," + clientFinal
+	clientSignature := s.hmac(storedKey.Sum(nil), authMessage)
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ clientSignature[i]
+	}
+	s.serverSignature = s.hmac(s.hmac(saltedPassword, "Server Key"), authMessage)
+	return clientFinal + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+// verify verifies the server signature of the server final message
+func (s *scramClient) verify(serverFinal string) error {
+	attributes := parseScramAttributes(serverFinal)
+	if message, ok := attributes["e"]; ok {
+		return errors.Errorf("authentication failed: %s", message)
+	}
+	signature, err := base64.StdEncoding.DecodeString(attributes["v"])
+	if err != nil || !hmac.Equal(signature, s.serverSignature) {
+		return errors.New("invalid server signature")
+	}
+	return nil
+}
+
+func (s *scramClient) hmac(key []byte, message string) []byte {
+	mac := hmac.New(s.hash, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// hi is the PBKDF2 function of the RFC with the hmac of the hash
+func (s *scramClient) hi(password, salt []byte, iterations int) []byte {
+	mac := hmac.New(s.hash, password)
+	mac.Write(salt)
+	mac.Write([]byte{0, 0, 0, 1})
+	previous := mac.Sum(nil)
+	result := append([]byte(nil), previous...)
+	for i := 1; i < iterations; i++ {
+		mac.Reset()
+		mac.Write(previous)
+		previous = mac.Sum(previous[:0])
+		for j := range result {
+			result[j] ^= previous[j]
+		}
+	}
+	return result
+}
+
+func parseScramAttributes(message string) map[string]string {
+	attributes := make(map[string]string)
+	for _, attribute := range strings.Split(message, ",") {
+		if key, value, ok := strings.Cut(attribute, "="); ok {
+			attributes[key] = value
+		}
+	}
+	return attributes
+}
+
+// authenticate authenticates the user against the admin database with the
+// strongest SCRAM mechanism supported by the user
+func (c *wireConn) authenticate(username, password string, mechanisms []interface{}) error {
+	mechanism := scramSHA1
+	for _, supported := range mechanisms {
+		if supported == scramSHA256 {
+			mechanism = scramSHA256
+		}
+	}
+	client, err := newScramClient(mechanism, username, password)
+	if err != nil {
+		return err
+	}
+
+	reply, err := c.runCommand("admin", document{
+		{key: "saslStart", value: int32(1)},
+		{key: "mechanism", value: mechanism},
+		{key: "payload", value: binaryData{data: []byte(client.first())}},
+		{key: "autoAuthorize", value: int32(1)},
+		{key: "options", value: document{{key: "skipEmptyExchange", value: true}}},
+	})
+	if err != nil {
+		return err
+	}
+	final, err := client.final(scramPayload(reply))
+	if err != nil {
+		return err
+	}
+	conversationID, _ := reply.lookup("conversationId")
+	if reply, err = c.runCommand("admin", document{
+		{key: "saslContinue", value: int32(1)},
+		{key: "conversationId", value: conversationID},
+		{key: "payload", value: binaryData{data: []byte(final)}},
+	}); err != nil {
+		return err
+	}
+	if err := client.verify(scramPayload(reply)); err != nil {
+		return err
+	}
+	// servers not supporting skipEmptyExchange expect an empty message to complete the conversation
+	if done, _ := reply.lookup("done"); done != true {
+		if _, err = c.runCommand("admin", document{
+			{key: "saslContinue", value: int32(1)},
+			{key: "conversationId", value: conversationID},
+			{key: "payload", value: binaryData{data: []byte{}}},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scramPayload(reply document) string {
+	value, _ := reply.lookup("payload")
+	payload, _ := value.(binaryData)
+	return string(payload.data)
+}
@@ -0,0 +1,193 @@
+package mongodb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// wire protocol op codes
+const (
+	opReply = 1
+	opQuery = 2004
+	opMsg   = 2013
+)
+
+const (
+	// maxMessageSize is the maximum size of a server message (48MB)
+	maxMessageSize = 48 * 1000 * 1000
+	// opMsgWireVersion is the wire version supporting OP_MSG (MongoDB 3.6)
+	opMsgWireVersion = 6
+)
+
+// commandError is the error of a command failed on the server
+type commandError struct {
+	Code    int32
+	Message string
+}
+
+func (e *commandError) Error() string {
+	return fmt.Sprintf("command failed with code %d: %s", e.Code, e.Message)
+}
+
+// server error codes
+const (
+	codeUnauthorized         = 13
+	codeAuthenticationFailed = 18
+)
+
+// wireConn is a connection speaking the MongoDB wire protocol
+type wireConn struct {
+	conn      net.Conn
+	requestID int32
+	// opMsg is true if the server supports OP_MSG, legacy OP_QUERY commands are used otherwise
+	opMsg bool
+}
+
+// handshake sends the hello command which is supported as OP_QUERY by all
+// the server versions and returns the reply
+func (c *wireConn) handshake(username string) (document, error) {
+	command := document{{key: "isMaster", value: int32(1)}}
+	if username != "" {
+		// asks the authentication mechanisms of the user
+		command = append(command, element{key: "saslSupportedMechs", value: "admin." + username})
+	}
+	reply, err := c.runCommand("admin", command)
+	if err != nil {
+		return nil, err
+	}
+	c.opMsg = toInt64(reply, "maxWireVersion") >= opMsgWireVersion
+	return reply, nil
+}
+
+// runCommand runs the command on the database and returns its reply
+func (c *wireConn) runCommand(db string, command document) (document, error) {
+	var reply document
+	var err error
+	if c.opMsg {
+		reply, err = c.runOpMsg(db, command)
+	} else {
+		reply, err = c.runOpQuery(db, command)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ok, _ := toFloat64(reply, "ok"); ok != 1 {
+		return reply, &commandError{Code: int32(toInt64(reply, "code")), Message: toString(reply, "errmsg")}
+	}
+	return reply, nil
+}
+
+func (c *wireConn) runOpMsg(db string, command document) (document, error) {
+	command = append(command[:len(command):len(command)], element{key: "$db", value: db})
+	encoded, err := command.marshal()
+	if err != nil {
+		return nil, err
+	}
+	// flag bits followed by a single body section
+	body := append([]byte{0, 0, 0, 0, 0}, encoded...)
+
+	opCode, reply, err := c.roundTrip(opMsg, body)
+	if err != nil {
+		return nil, err
+	}
+	if opCode != opMsg || len(reply) < 5 || reply[4] != 0 {
+		return nil, errors.Errorf("unexpected reply with op code %d", opCode)
+	}
+	// the reply only contains the body section as no document sequence is requested
+	return unmarshal(reply[5:])
+}
+
+func (c *wireConn) runOpQuery(db string, command document) (document, error) {
+	encoded, err := command.marshal()
+	if err != nil {
+		return nil, err
+	}
+	body := []byte{0, 0, 0, 0}
+	if body, err = appendCString(body, db+".$cmd"); err != nil {
+		return nil, err
+	}
+	// skips no document and returns a single one
+	body = binary.LittleEndian.AppendUint32(body, 0)
+	body = binary.LittleEndian.AppendUint32(body, math.MaxUint32)
+	body = append(body, encoded...)
+
+	opCode, reply, err := c.roundTrip(opQuery, body)
+	if err != nil {
+		return nil, err
+	}
+	// response flags, cursor id, starting from and number returned precede the documents
+	if opCode != opReply || len(reply) < 20 {
+		return nil, errors.Errorf("unexpected reply with op code %d", opCode)
+	}
+	if binary.LittleEndian.Uint32(reply[16:]) != 1 {
+		return nil, errors.New("unexpected number of documents in reply")
+	}
+	return unmarshal(reply[20:])
+}
+
+// roundTrip sends a message and returns the op code and body of the reply
+func (c *wireConn) roundTrip(opCode int32, body []byte) (int32, []byte, error) {
+	c.requestID++
+	message := make([]byte, 16, 16+len(body))
+	binary.LittleEndian.PutUint32(message[0:], uint32(16+len(body)))
+	binary.LittleEndian.PutUint32(message[4:], uint32(c.requestID))
+	binary.LittleEndian.PutUint32(message[12:], uint32(opCode))
+	message = append(message, body...)
+	if _, err := c.conn.Write(message); err != nil {
+		return 0, nil, err
+	}
+
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return 0, nil, err
+	}
+	length := binary.LittleEndian.Uint32(header[0:])
+	if length < 16 || length > maxMessageSize {
+		return 0, nil, errors.Errorf("invalid message length %d", length)
+	}
+	if responseTo := int32(binary.LittleEndian.Uint32(header[8:])); responseTo != c.requestID {
+		return 0, nil, errors.Errorf("unexpected reply to request %d", responseTo)
+	}
+	reply := make([]byte, length-16)
+	if _, err := io.ReadFull(c.conn, reply); err != nil {
+		return 0, nil, err
+	}
+	return int32(binary.LittleEndian.Uint32(header[12:])), reply, nil
+}
+
+// toString returns the string value of the key of the document, empty if missing
+func toString(doc document, key string) string {
+	value, _ := doc.lookup(key)
+	str, _ := value.(string)
+	return str
+}
+
+// toFloat64 returns the numeric value of the key of the document
+func toFloat64(doc document, key string) (float64, bool) {
+	value, _ := doc.lookup(key)
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// toInt64 returns the integer value of the key of the document, 0 if missing
+func toInt64(doc document, key string) int64 {
+	value, _ := toFloat64(doc, key)
+	return int64(value)
+}
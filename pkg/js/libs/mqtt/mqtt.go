@@ -0,0 +1,296 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout is the timeout of the connections to the broker
+	timeout = 10 * time.Second
+	// defaultWindow is the default duration of the subscriptions
+	defaultWindow = 5 * time.Second
+	// maxWindow is the maximum duration of the subscriptions
+	maxWindow = 30 * time.Second
+	// maxMessages is the maximum number of messages returned by a subscription
+	maxMessages = 1000
+	// keepAlive is the keep alive of the connections in seconds
+	keepAlive = 60
+)
+
+// MQTTClient is a client for MQTT brokers.
+//
+// The connections are anonymous without username, internally client
+// implements the 3.1.1 and 5 versions of the protocol.
+type MQTTClient struct {
+	// TLS enables TLS connections, the broker certificate is not verified
+	TLS bool
+	// Version is the version of the protocol, either 3.1.1 or 5, 3.1.1 by default
+	Version string
+	// ClientID is the client identifier, a random one if empty
+	ClientID string
+}
+
+// IsMQTTResponse is the response from the IsMQTT function.
+type IsMQTTResponse struct {
+	IsMQTT bool
+	// Anonymous is true if the broker accepts anonymous connections
+	Anonymous bool
+}
+
+// Message is a message received by a subscription.
+type Message struct {
+	Topic   string `json:"topic"`
+	Payload string `json:"payload"`
+	Retain  bool   `json:"retain"`
+}
+
+// IsMQTT checks if the given host and port are running MQTT broker.
+//
+// The broker is connected anonymously to check if anonymous connections are accepted.
+func (c *MQTTClient) IsMQTT(host string, port int) (IsMQTTResponse, error) {
+	resp := IsMQTTResponse{}
+
+	conn, err := c.dial(host, port)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.close()
+
+	err = c.handshake(conn, "", "")
+	var connectErr *connectError
+	switch {
+	case err == nil:
+		resp.IsMQTT, resp.Anonymous = true, true
+	case errors.As(err, &connectErr):
+		resp.IsMQTT = true
+	}
+	return resp, nil
+}
+
+// Connect connects to MQTT broker using given credentials, anonymously if the username is empty.
+//
+// If connection is successful, it returns true.
+// If the credentials are refused, it returns false.
+// If connection is unsuccessful, it returns false and error.
+func (c *MQTTClient) Connect(host string, port int, username, password string) (bool, error) {
+	conn, err := c.connect(host, port, username, password)
+	if err != nil {
+		var connectErr *connectError
+		if errors.As(err, &connectErr) && connectErr.unauthorized() {
+			return false, nil
+		}
+		return false, err
+	}
+	conn.close()
+	return true, nil
+}
+
+// Subscribe subscribes to the topic filters and returns the messages received
+// during the window as a json array of objects with topic, payload and retain.
+//
+// The window is in seconds, 5 seconds if not positive and at most 30 seconds,
+// at most 1000 messages are returned. The subscriptions are of QoS 0, printable
+// payloads are returned as strings and the other ones as 0x prefixed hex.
+func (c *MQTTClient) Subscribe(host string, port int, username, password string, topics []string, window int) (string, error) {
+	if len(topics) == 0 {
+		return "", fmt.Errorf("no topic to subscribe")
+	}
+	conn, err := c.connect(host, port, username, password)
+	if err != nil {
+		return "", err
+	}
+	defer conn.close()
+
+	body := binary.BigEndian.AppendUint16(nil, 1) // packet identifier
+	if conn.level == level5 {
+		body = append(body, 0)
+	}
+	for _, topic := range topics {
+		body = append(appendString(body, topic), 0)
+	}
+	if err := conn.writePacket(packetSubscribe, 0x02, body); err != nil {
+		return "", err
+	}
+
+	duration := defaultWindow
+	if window > 0 {
+		duration = min(time.Duration(window)*time.Second, maxWindow)
+	}
+	_ = conn.conn.SetDeadline(time.Now().Add(duration))
+
+	messages := make([]Message, 0)
+	for len(messages) < maxMessages {
+		kind, flags, r, err := conn.readPacket()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return "", err
+		}
+		switch kind {
+		case packetSuback:
+			var refused int
+			err = r.decode(func() {
+				r.uint16()
+				r.properties(conn.level)
+				for _, code := range r.rest() {
+					if code >= 0x80 {
+						refused++
+					}
+				}
+			})
+			if err == nil && refused == len(topics) {
+				return "", fmt.Errorf("subscription refused")
+			}
+		case packetPublish:
+			message := Message{Retain: flags&0x01 != 0}
+			err = r.decode(func() {
+				message.Topic = r.string()
+				if flags&0x06 != 0 {
+					r.uint16()
+				}
+				r.properties(conn.level)
+				message.Payload = payloadString(r.rest())
+			})
+			messages = append(messages, message)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	data, err := jsoniter.Marshal(messages)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (c *MQTTClient) dial(host string, port int) (*mqttConn, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	level := byte(level311)
+	switch c.Version {
+	case "", "3.1.1":
+	case "5", "5.0":
+		level = level5
+	default:
+		return nil, fmt.Errorf("unsupported version %s", c.Version)
+	}
+
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	var conn net.Conn
+	var err error
+	if c.TLS {
+		conn, err = protocolstate.Dialer.DialTLSWithConfig(context.TODO(), "tcp", address, &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10})
+	} else {
+		conn, err = protocolstate.Dialer.Dial(context.TODO(), "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return &mqttConn{conn: conn, level: level}, nil
+}
+
+// connect dials the broker and connects with the credentials
+func (c *MQTTClient) connect(host string, port int, username, password string) (*mqttConn, error) {
+	conn, err := c.dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.handshake(conn, username, password); err != nil {
+		_ = conn.conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// handshake sends the connect packet and reads the connack of the broker
+func (c *MQTTClient) handshake(conn *mqttConn, username, password string) error {
+	clientID := c.ClientID
+	if clientID == "" {
+		// the 3.1.1 brokers may only accept identifiers of up to 23 characters
+		random := make([]byte, 8)
+		if _, err := rand.Read(random); err != nil {
+			return err
+		}
+		clientID = "nuclei-" + hex.EncodeToString(random)
+	}
+
+	flags := byte(flagCleanSession)
+	// the 3.1.1 password requires a username
+	if username != "" {
+		flags |= flagUsername
+		if password != "" {
+			flags |= flagPassword
+		}
+	}
+	body := append(appendString(nil, "MQTT"), conn.level, flags)
+	body = binary.BigEndian.AppendUint16(body, keepAlive)
+	if conn.level == level5 {
+		body = append(body, 0)
+	}
+	body = appendString(body, clientID)
+	if flags&flagUsername != 0 {
+		body = appendString(body, username)
+	}
+	if flags&flagPassword != 0 {
+		body = appendString(body, password)
+	}
+	if err := conn.writePacket(packetConnect, 0, body); err != nil {
+		return err
+	}
+
+	kind, _, r, err := conn.readPacket()
+	if err != nil {
+		return err
+	}
+	if kind != packetConnack {
+		return fmt.Errorf("unexpected packet type %d", kind)
+	}
+	var code byte
+	if err := r.decode(func() { r.byte(); code = r.byte() }); err != nil {
+		return err
+	}
+	if code != 0 {
+		return &connectError{Code: code}
+	}
+	return nil
+}
+
+func payloadString(payload []byte) string {
+	if utf8.Valid(payload) {
+		printable := true
+		for _, r := range string(payload) {
+			if !unicode.IsPrint(r) && r != '\r' && r != '\n' && r != '\t' {
+				printable = false
+				break
+			}
+		}
+		if printable {
+			return string(payload)
+		}
+	}
+	return "0x" + hex.EncodeToString(payload)
+}
@@ -0,0 +1,166 @@
+package mqtt
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker accepts admin:secret and the anonymous connections if enabled,
+// the subscriptions of # receive two retained messages and the $SYS ones are refused
+type fakeBroker struct {
+	t         *testing.T
+	anonymous bool
+}
+
+func newFakeBroker(t *testing.T, anonymous bool) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	broker := &fakeBroker{t: t, anonymous: anonymous}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go broker.handle(&mqttConn{conn: conn})
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func (b *fakeBroker) handle(conn *mqttConn) {
+	defer conn.conn.Close()
+	kind, _, r, err := conn.readPacket()
+	if err != nil {
+		return
+	}
+	require.Equal(b.t, byte(packetConnect), kind)
+	var username, password string
+	require.Nil(b.t, r.decode(func() {
+		require.Equal(b.t, "MQTT", r.string())
+		conn.level = r.byte()
+		flags := r.byte()
+		require.Equal(b.t, uint16(keepAlive), r.uint16())
+		r.properties(conn.level)
+		require.True(b.t, strings.HasPrefix(r.string(), "nuclei-"))
+		if flags&flagUsername != 0 {
+			username = r.string()
+		}
+		if flags&flagPassword != 0 {
+			password = r.string()
+		}
+	}))
+
+	code := byte(0)
+	if !(username == "admin" && password == "secret") && !(username == "" && b.anonymous) {
+		code = 5
+		if conn.level == level5 {
+			code = 0x86
+		}
+	}
+	connack := []byte{0, code}
+	if conn.level == level5 {
+		connack = append(connack, 0)
+	}
+	require.Nil(b.t, conn.writePacket(packetConnack, 0, connack))
+	if code != 0 {
+		return
+	}
+
+	for {
+		kind, flags, r, err := conn.readPacket()
+		if err != nil || kind == packetDisconnect {
+			return
+		}
+		require.Equal(b.t, byte(packetSubscribe), kind)
+		require.Equal(b.t, byte(0x02), flags)
+		suback := []byte{0, 1}
+		if conn.level == level5 {
+			suback = append(suback, 0)
+		}
+		var wildcard bool
+		require.Nil(b.t, r.decode(func() {
+			require.Equal(b.t, uint16(1), r.uint16())
+			r.properties(conn.level)
+			for r.pos < len(r.data) {
+				topic := r.string()
+				require.Equal(b.t, byte(0), r.byte())
+				if strings.HasPrefix(topic, "$SYS") {
+					suback = append(suback, 0x80)
+					continue
+				}
+				wildcard = wildcard || topic == "#"
+				suback = append(suback, 0)
+			}
+		}))
+		require.Nil(b.t, conn.writePacket(packetSuback, 0, suback))
+		if !wildcard {
+			continue
+		}
+		for _, message := range []Message{{Topic: "sensors/temp", Payload: "21.5"}, {Topic: "sensors/raw", Payload: "\x00\xff"}} {
+			body := appendString(nil, message.Topic)
+			if conn.level == level5 {
+				// a message expiry interval property
+				body = append(body, 5, 0x02, 0, 0, 0, 60)
+			}
+			require.Nil(b.t, conn.writePacket(packetPublish, 0x01, append(body, message.Payload...)))
+		}
+	}
+}
+
+func TestMQTTClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	anonymous, authenticated := newFakeBroker(t, true), newFakeBroker(t, false)
+
+	for _, version := range []string{"", "5"} {
+		client := &MQTTClient{Version: version}
+
+		resp, err := client.IsMQTT("127.0.0.1", anonymous)
+		require.Nil(t, err)
+		require.Equal(t, IsMQTTResponse{IsMQTT: true, Anonymous: true}, resp)
+
+		resp, err = client.IsMQTT("127.0.0.1", authenticated)
+		require.Nil(t, err)
+		require.Equal(t, IsMQTTResponse{IsMQTT: true}, resp)
+
+		ok, err := client.Connect("127.0.0.1", authenticated, "admin", "secret")
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		ok, err = client.Connect("127.0.0.1", authenticated, "admin", "invalid")
+		require.Nil(t, err)
+		require.False(t, ok)
+
+		messages, err := client.Subscribe("127.0.0.1", anonymous, "", "", []string{"#", "$SYS/#"}, 1)
+		require.Nil(t, err)
+		require.JSONEq(t, `[{"topic":"sensors/temp","payload":"21.5","retain":true},{"topic":"sensors/raw","payload":"0x00ff","retain":true}]`, messages)
+
+		messages, err = client.Subscribe("127.0.0.1", authenticated, "admin", "secret", []string{"sensors/none"}, 1)
+		require.Nil(t, err)
+		require.Equal(t, "[]", messages)
+
+		_, err = client.Subscribe("127.0.0.1", anonymous, "", "", []string{"$SYS/#"}, 1)
+		require.EqualError(t, err, "subscription refused")
+	}
+
+	_, err := (&MQTTClient{}).Subscribe("127.0.0.1", authenticated, "", "", []string{"#"}, 1)
+	require.EqualError(t, err, "connection refused: not authorized")
+}
+
+func TestReadPacket(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		_, _ = server.Write(binary.BigEndian.AppendUint32([]byte{packetPublish << 4}, 0xffffffff))
+		_ = server.Close()
+	}()
+	_, _, _, err := (&mqttConn{conn: client}).readPacket()
+	require.EqualError(t, err, "invalid remaining length")
+}
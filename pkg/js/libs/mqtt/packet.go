@@ -0,0 +1,223 @@
+package mqtt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// packet types
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetSubscribe  = 8
+	packetSuback     = 9
+	packetDisconnect = 14
+)
+
+// protocol levels of the versions
+const (
+	level311 = 4
+	level5   = 5
+)
+
+// connect flags
+const (
+	flagCleanSession = 0x02
+	flagPassword     = 0x40
+	flagUsername     = 0x80
+)
+
+// maxPacketSize is the maximum size of a packet (1MB)
+const maxPacketSize = 1024 * 1024
+
+// connectCodes are the names of the refusal codes of the 3.1.1 and 5 connacks
+var connectCodes = map[byte]string{
+	1:    "unacceptable protocol version",
+	2:    "identifier rejected",
+	3:    "server unavailable",
+	4:    "bad user name or password",
+	5:    "not authorized",
+	0x80: "unspecified error",
+	0x81: "malformed packet",
+	0x82: "protocol error",
+	0x84: "unsupported protocol version",
+	0x85: "client identifier not valid",
+	0x86: "bad user name or password",
+	0x87: "not authorized",
+	0x88: "server unavailable",
+	0x89: "server busy",
+	0x8a: "banned",
+	0x8c: "bad authentication method",
+}
+
+// connectError is a refusal code of a connack
+type connectError struct {
+	Code byte
+}
+
+func (e *connectError) Error() string {
+	if name, ok := connectCodes[e.Code]; ok {
+		return "connection refused: " + name
+	}
+	return fmt.Sprintf("connection refused: code %d", e.Code)
+}
+
+// unauthorized returns true if the code refuses the credentials
+func (e *connectError) unauthorized() bool {
+	switch e.Code {
+	case 4, 5, 0x86, 0x87, 0x8c:
+		return true
+	}
+	return false
+}
+
+// mqttConn is a connection to a broker
+type mqttConn struct {
+	conn  net.Conn
+	level byte
+}
+
+// writePacket writes a packet of the type with its flags
+func (c *mqttConn) writePacket(kind, flags byte, body []byte) error {
+	packet := appendLength([]byte{kind<<4 | flags}, len(body))
+	_, err := c.conn.Write(append(packet, body...))
+	return err
+}
+
+// readPacket returns the type, the flags and the reader of the body of the next packet
+func (c *mqttConn) readPacket() (byte, byte, *reader, error) {
+	header := make([]byte, 1)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return 0, 0, nil, err
+	}
+	// the remaining length is a variable byte integer of at most 4 bytes
+	length, shift := 0, 0
+	for {
+		b := make([]byte, 1)
+		if _, err := io.ReadFull(c.conn, b); err != nil {
+			return 0, 0, nil, err
+		}
+		length |= int(b[0]&0x7f) << shift
+		if b[0]&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			return 0, 0, nil, errors.New("invalid remaining length")
+		}
+	}
+	if length > maxPacketSize {
+		return 0, 0, nil, fmt.Errorf("invalid packet size %d", length)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.conn, body); err != nil {
+		return 0, 0, nil, err
+	}
+	return header[0] >> 4, header[0] & 0x0f, &reader{data: body}, nil
+}
+
+func (c *mqttConn) close() {
+	_ = c.writePacket(packetDisconnect, 0, nil)
+	_ = c.conn.Close()
+}
+
+func appendLength(buf []byte, length int) []byte {
+	for {
+		b := byte(length & 0x7f)
+		if length >>= 7; length > 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+		if length == 0 {
+			return buf
+		}
+	}
+}
+
+func appendString(buf []byte, value string) []byte {
+	return append(binary.BigEndian.AppendUint16(buf, uint16(len(value))), value...)
+}
+
+// reader reads the fields of a packet panicking on malformed data
+type reader struct {
+	data []byte
+	pos  int
+}
+
+type decodeError struct {
+	message string
+}
+
+func (e *decodeError) Error() string {
+	return e.message
+}
+
+// decode runs the function and returns its decode error
+func (r *reader) decode(fn func()) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			decodeErr, ok := recovered.(*decodeError)
+			if !ok {
+				panic(recovered)
+			}
+			err = errors.Wrap(decodeErr, "could not decode packet")
+		}
+	}()
+	fn()
+	return nil
+}
+
+func (r *reader) fail(format string, args ...interface{}) {
+	panic(&decodeError{message: fmt.Sprintf(format, args...)})
+}
+
+func (r *reader) read(n int) []byte {
+	if n < 0 || len(r.data)-r.pos < n {
+		r.fail("unexpected end of packet")
+	}
+	value := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return value
+}
+
+func (r *reader) byte() byte {
+	return r.read(1)[0]
+}
+
+func (r *reader) uint16() uint16 {
+	return binary.BigEndian.Uint16(r.read(2))
+}
+
+func (r *reader) string() string {
+	return string(r.read(int(r.uint16())))
+}
+
+// length returns the value of a variable byte integer
+func (r *reader) length() int {
+	length := 0
+	for shift := 0; shift <= 21; shift += 7 {
+		b := r.byte()
+		length |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return length
+		}
+	}
+	r.fail("invalid variable byte integer")
+	return 0
+}
+
+// properties skips the properties of a version 5 packet
+func (r *reader) properties(level byte) {
+	if level == level5 {
+		r.read(r.length())
+	}
+}
+
+// rest returns the remaining bytes of the packet
+func (r *reader) rest() []byte {
+	return r.read(len(r.data) - r.pos)
+}
@@ -0,0 +1,105 @@
+package mssql
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// browserTimeout is the timeout of the SQL Server Browser requests
+	browserTimeout = 5 * time.Second
+	// browserUnicastEx is the request of the instances of a server (CLNT_UCAST_EX)
+	browserUnicastEx = 0x03
+	// browserResponse is the type of the responses (SVR_RESP)
+	browserResponse = 0x05
+)
+
+// Instance is an instance of a server returned by the SQL Server Browser.
+type Instance struct {
+	ServerName   string
+	InstanceName string
+	IsClustered  bool
+	Version      string
+	// TCPPort is the tcp port of the instance, 0 if the tcp protocol is disabled
+	TCPPort int
+	// NamedPipe is the named pipe of the instance, empty if the named pipes are disabled
+	NamedPipe string
+}
+
+// ListInstances returns the instances of the server using the SQL Server Browser service.
+//
+// The browser service listens on the udp port 1434, it returns the
+// instances of the server along with their version and ports.
+func (c *MSSQLClient) ListInstances(host string, port int) ([]Instance, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(browserTimeout))
+
+	if _, err := conn.Write([]byte{browserUnicastEx}); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 3+65535)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return parseBrowserResponse(buf[:n])
+}
+
+// parseBrowserResponse returns the instances of a browser response, the
+// instances are separated by ;; and are made of ; separated keys and values
+func parseBrowserResponse(data []byte) ([]Instance, error) {
+	if len(data) < 3 || data[0] != browserResponse {
+		return nil, fmt.Errorf("invalid browser response")
+	}
+	size := int(binary.LittleEndian.Uint16(data[1:]))
+	if size > len(data)-3 {
+		return nil, fmt.Errorf("invalid browser response size %d", size)
+	}
+
+	instances := []Instance{}
+	for _, entry := range strings.Split(string(data[3:3+size]), ";;") {
+		fields := strings.Split(entry, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		instance := Instance{}
+		for i := 0; i+1 < len(fields); i += 2 {
+			value := fields[i+1]
+			switch strings.ToLower(fields[i]) {
+			case "servername":
+				instance.ServerName = value
+			case "instancename":
+				instance.InstanceName = value
+			case "isclustered":
+				instance.IsClustered = strings.EqualFold(value, "yes")
+			case "version":
+				instance.Version = value
+			case "tcp":
+				instance.TCPPort, _ = strconv.Atoi(value)
+			case "np":
+				instance.NamedPipe = value
+			}
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
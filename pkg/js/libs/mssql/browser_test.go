@@ -0,0 +1,47 @@
+package mssql
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMSSQLClientListInstances(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer conn.Close()
+
+	go func() {
+		buf := make([]byte, 16)
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil || n != 1 || buf[0] != browserUnicastEx {
+			return
+		}
+		data := "ServerName;SQL01;InstanceName;MSSQLSERVER;IsClustered;No;Version;15.0.2000.5;tcp;1433;;" +
+			"ServerName;SQL01;InstanceName;SQLEXPRESS;IsClustered;Yes;Version;16.0.1000.6;np;\\\\SQL01\\pipe\\MSSQL$SQLEXPRESS\\sql\\query;;"
+		response := binary.LittleEndian.AppendUint16([]byte{browserResponse}, uint16(len(data)))
+		_, _ = conn.WriteTo(append(response, data...), addr)
+	}()
+
+	client := &MSSQLClient{}
+	instances, err := client.ListInstances("127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port)
+	require.Nil(t, err)
+	require.Equal(t, []Instance{
+		{ServerName: "SQL01", InstanceName: "MSSQLSERVER", Version: "15.0.2000.5", TCPPort: 1433},
+		{ServerName: "SQL01", InstanceName: "SQLEXPRESS", IsClustered: true, Version: "16.0.1000.6", NamedPipe: `\\SQL01\pipe\MSSQL$SQLEXPRESS\sql\query`},
+	}, instances)
+}
+
+func TestParseBrowserResponse(t *testing.T) {
+	_, err := parseBrowserResponse([]byte{0x04, 0, 0})
+	require.EqualError(t, err, "invalid browser response")
+
+	_, err = parseBrowserResponse([]byte{browserResponse, 10, 0, 'a'})
+	require.EqualError(t, err, "invalid browser response size 10")
+}
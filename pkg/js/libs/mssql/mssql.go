@@ -6,10 +6,11 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
-	_ "github.com/denisenkom/go-mssqldb"
+	mssqldb "github.com/denisenkom/go-mssqldb"
 	"github.com/praetorian-inc/fingerprintx/pkg/plugins/services/mssql"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
@@ -17,7 +18,13 @@ import (
 // Client is a client for MS SQL database.
 //
 // Internally client uses denisenkom/go-mssqldb driver.
-type MSSQLClient struct{}
+//
+// The SQL Server authentication is used unless Domain is set, the
+// Windows authentication of the domain users is then done with NTLM.
+type MSSQLClient struct {
+	// Domain is the windows domain of the users, ex: CORP or . for the local accounts
+	Domain string
+}
 
 // Connect connects to MS SQL database using given credentials.
 //
@@ -26,7 +33,7 @@ type MSSQLClient struct{}
 //
 // The connection is closed after the function returns.
 func (c *MSSQLClient) Connect(host string, port int, username, password string) (bool, error) {
-	return connect(host, port, username, password, "master")
+	return c.connect(host, port, username, password, "master")
 }
 
 // ConnectWithDB connects to MS SQL database using given credentials and database name.
@@ -36,10 +43,10 @@ func (c *MSSQLClient) Connect(host string, port int, username, password string)
 //
 // The connection is closed after the function returns.
 func (c *MSSQLClient) ConnectWithDB(host string, port int, username, password, dbName string) (bool, error) {
-	return connect(host, port, username, password, dbName)
+	return c.connect(host, port, username, password, dbName)
 }
 
-func connect(host string, port int, username, password, dbName string) (bool, error) {
+func (c *MSSQLClient) connect(host string, port int, username, password, dbName string) (bool, error) {
 	if host == "" || port <= 0 {
 		return false, fmt.Errorf("invalid host or port")
 	}
@@ -48,15 +55,7 @@ func connect(host string, port int, username, password, dbName string) (bool, er
 		return false, protocolstate.ErrHostDenied.Msgf(host)
 	}
 
-	target := net.JoinHostPort(host, fmt.Sprintf("%d", port))
-
-	connString := fmt.Sprintf("sqlserver://%s:%s@%s?database=%s&connection+timeout=30",
-		url.PathEscape(username),
-		url.PathEscape(password),
-		target,
-		dbName)
-
-	db, err := sql.Open("sqlserver", connString)
+	db, err := c.open(host, port, username, password, dbName)
 	if err != nil {
 		return false, err
 	}
@@ -81,6 +80,70 @@ func connect(host string, port int, username, password, dbName string) (bool, er
 	return true, nil
 }
 
+// ExecuteQuery connects to MS SQL database using given credentials and database name
+// and executes a query on the db.
+//
+// The rows are returned as json, the values are converted according to the
+// type of their column and NULL values are returned as null.
+func (c *MSSQLClient) ExecuteQuery(host string, port int, username, password, dbName, query string) (string, error) {
+	if host == "" || port <= 0 {
+		return "", fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return "", protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	db, err := c.open(host, port, username, password, dbName)
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	resp, err := unmarshalRows(rows)
+	if err != nil {
+		return "", err
+	}
+	return string(resp), nil
+}
+
+// open returns a database handle of the client dialing with the nuclei dialer
+func (c *MSSQLClient) open(host string, port int, username, password, dbName string) (*sql.DB, error) {
+	connector, err := mssqldb.NewConnector(c.connString(host, port, username, password, dbName))
+	if err != nil {
+		return nil, err
+	}
+	connector.Dialer = dialer{}
+	return sql.OpenDB(connector), nil
+}
+
+// connString returns the connection string of the driver
+func (c *MSSQLClient) connString(host string, port int, username, password, dbName string) string {
+	if c.Domain != "" {
+		// the driver uses the NTLM authentication for the domain users
+		username = c.Domain + `\` + username
+	}
+	connString := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(username, password),
+		Host:     net.JoinHostPort(host, strconv.Itoa(port)),
+		RawQuery: url.Values{"database": []string{dbName}, "connection timeout": []string{"30"}}.Encode(),
+	}
+	return connString.String()
+}
+
+// dialer is the dialer of the driver using the nuclei dialer
+type dialer struct{}
+
+func (dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return protocolstate.Dialer.Dial(ctx, network, addr)
+}
+
 // IsMssql checks if the given host is running MS SQL database.
 //
 // If the host is running MS SQL database, it returns true.
@@ -0,0 +1,76 @@
+package mssql
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// unmarshalRows unmarshals MS SQL rows to json
+//
+// The values are converted according to their column type, NULL values
+// are returned as null and binary values as 0x prefixed hex.
+func unmarshalRows(rows *sql.Rows) ([]byte, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	finalRows := []interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columnTypes))
+		scanArgs := make([]interface{}, len(columnTypes))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columnTypes))
+		for i, columnType := range columnTypes {
+			row[columnType.Name()] = columnValue(columnType.DatabaseTypeName(), values[i])
+		}
+		finalRows = append(finalRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jsoniter.Marshal(finalRows)
+}
+
+// columnValue returns the json value of a column value returned by the driver
+func columnValue(typeName string, value interface{}) interface{} {
+	switch value := value.(type) {
+	case []byte:
+		switch typeName {
+		case "UNIQUEIDENTIFIER":
+			if len(value) == 16 {
+				return formatGUID(value)
+			}
+		case "DECIMAL", "MONEY", "SMALLMONEY":
+			// the decimals are returned as text to not lose their precision
+			return string(value)
+		case "VARBINARY", "BINARY", "IMAGE":
+		default:
+			if utf8.Valid(value) {
+				return string(value)
+			}
+		}
+		return "0x" + hex.EncodeToString(value)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	default:
+		return value
+	}
+}
+
+// formatGUID formats a uniqueidentifier, the first three groups are little endian
+func formatGUID(b []byte) string {
+	return fmt.Sprintf("%02X%02X%02X%02X-%02X%02X-%02X%02X-%X-%X",
+		b[3], b[2], b[1], b[0], b[5], b[4], b[7], b[6], b[8:10], b[10:])
+}
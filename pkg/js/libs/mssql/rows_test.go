@@ -0,0 +1,95 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver returns the columns and the values of fakeRows for all the queries
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+var fakeColumns = []struct {
+	name, typeName string
+	value          driver.Value
+}{
+	{"id", "BIGINT", int64(1)},
+	{"name", "NVARCHAR", "sa"},
+	{"disabled", "BIT", false},
+	{"balance", "MONEY", []byte("10.5000")},
+	{"guid", "UNIQUEIDENTIFIER", []byte{0x78, 0x56, 0x34, 0x12, 0x34, 0x12, 0x34, 0x12, 0x12, 0x34, 0x56, 0x78, 0x90, 0xab, 0xcd, 0xef}},
+	{"hash", "VARBINARY", []byte("abc")},
+	{"created", "DATETIME", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	{"deleted", "DATETIME", nil},
+}
+
+type fakeRows struct {
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	names := make([]string, len(fakeColumns))
+	for i, column := range fakeColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(index int) string {
+	return fakeColumns[index].typeName
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	for i, column := range fakeColumns {
+		dest[i] = column.value
+	}
+	return nil
+}
+
+func TestUnmarshalRows(t *testing.T) {
+	sql.Register("mssql-fake", fakeDriver{})
+	db, err := sql.Open("mssql-fake", "")
+	require.Nil(t, err)
+	defer db.Close()
+
+	rows, err := db.Query("select")
+	require.Nil(t, err)
+	defer rows.Close()
+
+	data, err := unmarshalRows(rows)
+	require.Nil(t, err)
+	require.JSONEq(t, `[{
+		"id": 1,
+		"name": "sa",
+		"disabled": false,
+		"balance": "10.5000",
+		"guid": "12345678-1234-1234-1234-567890ABCDEF",
+		"hash": "0x616263",
+		"created": "2024-01-02T03:04:05Z",
+		"deleted": null
+	}]`, string(data))
+}
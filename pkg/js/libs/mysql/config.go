@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"database/sql"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// dialNetwork is the network of the driver dialing with the nuclei dialer
+const dialNetwork = "nuclei-tcp"
+
+// timeout is the dial timeout of the connections
+const timeout = 10 * time.Second
+
+// tlsModes are the driver tls values of the supported tls modes
+var tlsModes = map[string]string{
+	"":                "false",
+	"disabled":        "false",
+	"preferred":       "preferred",
+	"required":        "skip-verify",
+	"verify-identity": "true",
+}
+
+func init() {
+	mysql.RegisterDialContext(dialNetwork, func(ctx context.Context, addr string) (net.Conn, error) {
+		return protocolstate.Dialer.Dial(ctx, "tcp", addr)
+	})
+}
+
+// config returns the driver config of the client
+func (c *MySQLClient) config(host string, port int, username, password, dbName string) (*mysql.Config, error) {
+	tlsConfig, ok := tlsModes[c.TLSMode]
+	if !ok {
+		return nil, fmt.Errorf("unsupported tls mode %s", c.TLSMode)
+	}
+
+	config := mysql.NewConfig()
+	config.User = username
+	config.Passwd = password
+	config.Net = dialNetwork
+	config.Addr = net.JoinHostPort(host, strconv.Itoa(port))
+	config.DBName = dbName
+	config.Timeout = timeout
+	config.TLSConfig = tlsConfig
+	config.AllowCleartextPasswords = c.AllowCleartextPasswords
+	config.AllowOldPasswords = c.AllowOldPasswords
+	if c.ServerPublicKey != "" {
+		name, err := registerServerPublicKey(c.ServerPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		config.ServerPubKey = name
+	}
+	return config, nil
+}
+
+// open returns a database handle of the client
+func (c *MySQLClient) open(host string, port int, username, password, dbName string) (*sql.DB, error) {
+	config, err := c.config(host, port, username, password, dbName)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(config)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// registerServerPublicKey registers the pem encoded public key in the driver
+// and returns its name, the keys are named after their contents
+func registerServerPublicKey(contents string) (string, error) {
+	block, _ := pem.Decode([]byte(contents))
+	if block == nil {
+		return "", errors.New("could not parse server public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("could not parse server public key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return "", errors.New("server public key is not a rsa key")
+	}
+	hash := sha256.Sum256([]byte(contents))
+	name := hex.EncodeToString(hash[:])
+	mysql.RegisterServerPubKey(name, rsaKey)
+	return name, nil
+}
@@ -0,0 +1,49 @@
+package mysql
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQLClientConfig(t *testing.T) {
+	client := &MySQLClient{}
+	config, err := client.config("localhost", 3306, "root", "p@ss:word/", "mysql")
+	require.Nil(t, err)
+	require.Equal(t, "p@ss:word/", config.Passwd)
+	require.Equal(t, "nuclei-tcp", config.Net)
+	require.Equal(t, "localhost:3306", config.Addr)
+	require.Equal(t, "false", config.TLSConfig)
+
+	client = &MySQLClient{TLSMode: "required", AllowCleartextPasswords: true, AllowOldPasswords: true}
+	config, err = client.config("::1", 3306, "root", "", "")
+	require.Nil(t, err)
+	require.Equal(t, "[::1]:3306", config.Addr)
+	require.Equal(t, "skip-verify", config.TLSConfig)
+	require.True(t, config.AllowCleartextPasswords)
+	require.True(t, config.AllowOldPasswords)
+
+	client = &MySQLClient{TLSMode: "verify-ca"}
+	_, err = client.config("localhost", 3306, "root", "", "")
+	require.EqualError(t, err, "unsupported tls mode verify-ca")
+}
+
+func TestMySQLClientServerPublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.Nil(t, err)
+
+	client := &MySQLClient{ServerPublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))}
+	config, err := client.config("localhost", 3306, "root", "", "")
+	require.Nil(t, err)
+	require.Len(t, config.ServerPubKey, 64)
+
+	client = &MySQLClient{ServerPublicKey: "invalid"}
+	_, err = client.config("localhost", 3306, "root", "", "")
+	require.EqualError(t, err, "could not parse server public key")
+}
@@ -2,23 +2,33 @@ package mysql
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"net"
-	"net/url"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
 	mysqlplugin "github.com/praetorian-inc/fingerprintx/pkg/plugins/services/mysql"
-	utils "github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
 // MySQLClient is a client for MySQL database.
 //
 // Internally client uses go-sql-driver/mysql driver.
-type MySQLClient struct{}
+//
+// Connections are not encrypted unless TLSMode is set, the caching_sha2_password
+// and sha256_password plugins retrieve the public key of the server to send the
+// password on plain connections if ServerPublicKey is not set.
+type MySQLClient struct {
+	// TLSMode is the tls mode of the connections, one of disabled, preferred, required or verify-identity,
+	// the server certificate is only verified with verify-identity
+	TLSMode string
+	// ServerPublicKey is the PEM encoded RSA public key of the server encrypting the password on plain connections
+	ServerPublicKey string
+	// AllowCleartextPasswords enables the mysql_clear_password plugin, ex: for PAM or LDAP authentication
+	AllowCleartextPasswords bool
+	// AllowOldPasswords enables the insecure mysql_old_password plugin of the old servers
+	AllowOldPasswords bool
+}
 
 // Connect connects to MySQL database using given credentials.
 //
@@ -27,7 +37,7 @@ type MySQLClient struct{}
 //
 // The connection is closed after the function returns.
 func (c *MySQLClient) Connect(host string, port int, username, password string) (bool, error) {
-	return connect(host, port, username, password, "INFORMATION_SCHEMA")
+	return c.connect(host, port, username, password, "INFORMATION_SCHEMA")
 }
 
 // IsMySQL checks if the given host is running MySQL database.
@@ -63,10 +73,10 @@ func (c *MySQLClient) IsMySQL(host string, port int) (bool, error) {
 //
 // The connection is closed after the function returns.
 func (c *MySQLClient) ConnectWithDB(host string, port int, username, password, dbName string) (bool, error) {
-	return connect(host, port, username, password, dbName)
+	return c.connect(host, port, username, password, dbName)
 }
 
-func connect(host string, port int, username, password, dbName string) (bool, error) {
+func (c *MySQLClient) connect(host string, port int, username, password, dbName string) (bool, error) {
 	if host == "" || port <= 0 {
 		return false, fmt.Errorf("invalid host or port")
 	}
@@ -76,13 +86,7 @@ func connect(host string, port int, username, password, dbName string) (bool, er
 		return false, protocolstate.ErrHostDenied.Msgf(host)
 	}
 
-	target := net.JoinHostPort(host, fmt.Sprintf("%d", port))
-
-	db, err := sql.Open("mysql", fmt.Sprintf("%v:%v@tcp(%v)/%s",
-		url.PathEscape(username),
-		url.PathEscape(password),
-		target,
-		dbName))
+	db, err := c.open(host, port, username, password, dbName)
 	if err != nil {
 		return false, err
 	}
@@ -97,20 +101,20 @@ func connect(host string, port int, username, password, dbName string) (bool, er
 
 // ExecuteQuery connects to Mysql database using given credentials and database name.
 // and executes a query on the db.
+//
+// The rows are returned as json, the values are converted according to the
+// type of their column and NULL values are returned as null.
 func (c *MySQLClient) ExecuteQuery(host string, port int, username, password, dbName, query string) (string, error) {
+	if host == "" || port <= 0 {
+		return "", fmt.Errorf("invalid host or port")
+	}
 
 	if !protocolstate.IsHostAllowed(host) {
 		// host is not valid according to network policy
 		return "", protocolstate.ErrHostDenied.Msgf(host)
 	}
 
-	target := net.JoinHostPort(host, fmt.Sprintf("%d", port))
-
-	db, err := sql.Open("mysql", fmt.Sprintf("%v:%v@tcp(%v)/%s",
-		url.PathEscape(username),
-		url.PathEscape(password),
-		target,
-		dbName))
+	db, err := c.open(host, port, username, password, dbName)
 	if err != nil {
 		return "", err
 	}
@@ -120,7 +124,8 @@ func (c *MySQLClient) ExecuteQuery(host string, port int, username, password, db
 	if err != nil {
 		return "", err
 	}
-	resp, err := utils.UnmarshalSQLRows(rows)
+	defer rows.Close()
+	resp, err := unmarshalRows(rows)
 	if err != nil {
 		return "", err
 	}
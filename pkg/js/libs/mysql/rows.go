@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// unmarshalRows unmarshals mysql rows to json
+//
+// The values are converted according to their column type, NULL values
+// are returned as null and binary values not valid as UTF-8 as 0x prefixed hex.
+func unmarshalRows(rows *sql.Rows) ([]byte, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	finalRows := []interface{}{}
+	for rows.Next() {
+		// the values of the text protocol are all returned as bytes
+		values := make([]sql.RawBytes, len(columnTypes))
+		scanArgs := make([]interface{}, len(columnTypes))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columnTypes))
+		for i, columnType := range columnTypes {
+			row[columnType.Name()] = columnValue(columnType.DatabaseTypeName(), values[i])
+		}
+		finalRows = append(finalRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jsoniter.Marshal(finalRows)
+}
+
+// columnValue returns the json value of a column, the value is returned
+// as string if it can't be converted to the type of the column
+func columnValue(typeName string, value sql.RawBytes) interface{} {
+	if value == nil {
+		return nil
+	}
+	text := string(value)
+	switch typeName {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "YEAR":
+		if number, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return number
+		}
+		// the unsigned values may not fit in an int64
+		if number, err := strconv.ParseUint(text, 10, 64); err == nil {
+			return number
+		}
+	case "FLOAT", "DOUBLE":
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return number
+		}
+	case "BIT":
+		var number uint64
+		for _, b := range value {
+			number = number<<8 | uint64(b)
+		}
+		return number
+	case "JSON":
+		if json.Valid(value) {
+			return json.RawMessage(text)
+		}
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "GEOMETRY":
+		if !utf8.Valid(value) {
+			return "0x" + hex.EncodeToString(value)
+		}
+	}
+	// the decimals are kept as strings to not lose their precision
+	return text
+}
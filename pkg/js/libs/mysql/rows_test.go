@@ -0,0 +1,98 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver returns the columns and the text values of fakeRows for all the queries
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+var fakeColumns = []struct {
+	name, typeName string
+	value          driver.Value
+}{
+	{"id", "BIGINT", []byte("18446744073709551615")},
+	{"age", "TINYINT", []byte("-3")},
+	{"score", "DOUBLE", []byte("1.5")},
+	{"price", "DECIMAL", []byte("10.10")},
+	{"name", "VARCHAR", []byte("admin")},
+	{"deleted", "DATETIME", nil},
+	{"flags", "BIT", []byte{0x01, 0x02}},
+	{"settings", "JSON", []byte(`{"admin": true}`)},
+	{"token", "VARBINARY", []byte{0xff, 0x00}},
+	{"avatar", "BLOB", []byte("png")},
+}
+
+type fakeRows struct {
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	names := make([]string, len(fakeColumns))
+	for i, column := range fakeColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(index int) string {
+	return fakeColumns[index].typeName
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	for i, column := range fakeColumns {
+		dest[i] = column.value
+	}
+	return nil
+}
+
+func TestUnmarshalRows(t *testing.T) {
+	sql.Register("mysql-fake", fakeDriver{})
+	db, err := sql.Open("mysql-fake", "")
+	require.Nil(t, err)
+	defer db.Close()
+
+	rows, err := db.Query("select")
+	require.Nil(t, err)
+	defer rows.Close()
+
+	data, err := unmarshalRows(rows)
+	require.Nil(t, err)
+	require.JSONEq(t, `[{
+		"id": 18446744073709551615,
+		"age": -3,
+		"score": 1.5,
+		"price": "10.10",
+		"name": "admin",
+		"deleted": null,
+		"flags": 258,
+		"settings": {"admin": true},
+		"token": "0xff00",
+		"avatar": "png"
+	}]`, string(data))
+}
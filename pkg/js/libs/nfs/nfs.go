@@ -0,0 +1,343 @@
+package nfs
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// rpc programs and procedures of the portmapper, mount and nfs v3 protocols
+const (
+	programPortmapper = 100000
+	programNFS        = 100003
+	programMount      = 100005
+
+	portmapperGetPort = 3
+
+	mountMnt    = 1
+	mountUmnt   = 3
+	mountExport = 5
+
+	nfsLookup  = 3
+	nfsRead    = 6
+	nfsReaddir = 16
+
+	// protocolTCP is the protocol of the ports requested to the portmapper
+	protocolTCP = 6
+	// defaultNFSPort is the port of nfs used if not registered on the portmapper
+	defaultNFSPort = 2049
+)
+
+const (
+	// maxReadSize is the maximum size of a file content returned by Read (1MB)
+	maxReadSize = 1024 * 1024
+	// readChunkSize is the size of the chunks of the read requests
+	readChunkSize = 32 * 1024
+	// maxEntries is the maximum number of directory entries returned by Read
+	maxEntries = 10000
+	// fattrSize is the size of the file attributes
+	fattrSize = 84
+	// statusIsDir is the status of the reads of a directory
+	statusIsDir = 21
+)
+
+// statuses are the names of the mount and nfs v3 statuses
+var statuses = map[uint32]string{
+	1:     "NFS3ERR_PERM",
+	2:     "NFS3ERR_NOENT",
+	5:     "NFS3ERR_IO",
+	6:     "NFS3ERR_NXIO",
+	13:    "NFS3ERR_ACCES",
+	17:    "NFS3ERR_EXIST",
+	20:    "NFS3ERR_NOTDIR",
+	21:    "NFS3ERR_ISDIR",
+	22:    "NFS3ERR_INVAL",
+	27:    "NFS3ERR_FBIG",
+	63:    "NFS3ERR_NAMETOOLONG",
+	70:    "NFS3ERR_STALE",
+	10001: "NFS3ERR_BADHANDLE",
+	10004: "NFS3ERR_NOTSUPP",
+	10006: "NFS3ERR_SERVERFAULT",
+}
+
+// statusError is an error status of a mount or nfs reply
+type statusError struct {
+	status uint32
+}
+
+func (e *statusError) Error() string {
+	if name, ok := statuses[e.status]; ok {
+		return name
+	}
+	return fmt.Sprintf("nfs error %d", e.status)
+}
+
+// NFSClient is a client for NFS servers.
+//
+// The ports of the mount and nfs v3 programs are requested to the portmapper,
+// the requests use the unix credentials of root which are squashed to the
+// anonymous user by default. The exports with the secure option require a
+// privileged source port and refuse the requests of unprivileged scans.
+type NFSClient struct{}
+
+// Export is an export of the server.
+type Export struct {
+	Directory string
+	// Clients are the hosts, networks and netgroups allowed to mount the export, any client if empty
+	Clients []string
+}
+
+// ReadResponse is the response from the Read function.
+type ReadResponse struct {
+	// IsDirectory is true if the path is a directory, its entries are then returned instead of the content
+	IsDirectory bool
+	// Content is the content of the file, printable as text or 0x prefixed hex
+	Content string
+	// Entries are the names of the entries of the directory
+	Entries []string
+}
+
+// ListExports returns the exports of the server with the clients allowed to mount them.
+//
+// The port is the port of the portmapper, usually 111.
+func (c *NFSClient) ListExports(host string, port int) ([]Export, error) {
+	conn, err := dialProgram(host, port, programMount, 3)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	r, err := conn.call(programMount, 3, mountExport, nil)
+	if err != nil {
+		return nil, err
+	}
+	var exports []Export
+	err = r.decode(func() {
+		for r.bool() {
+			export := Export{Directory: r.string()}
+			for r.bool() {
+				export.Clients = append(export.Clients, r.string())
+			}
+			exports = append(exports, export)
+		}
+	})
+	return exports, err
+}
+
+// Read mounts the export of the directory and reads the path relative to the export,
+// the root of the export if empty.
+//
+// The entries of the directories are returned and the content of the files,
+// at most 1MB of content and 10000 entries are returned.
+// The port is the port of the portmapper, usually 111.
+func (c *NFSClient) Read(host string, port int, directory, filePath string) (ReadResponse, error) {
+	resp := ReadResponse{}
+
+	mount, err := dialProgram(host, port, programMount, 3)
+	if err != nil {
+		return resp, err
+	}
+	defer mount.close()
+	handle, err := mount.mount(directory)
+	if err != nil {
+		return resp, err
+	}
+	defer mount.unmount(directory)
+
+	nfsPort, err := getPort(host, port, programNFS, 3)
+	if err != nil || nfsPort == 0 {
+		nfsPort = defaultNFSPort
+	}
+	conn, err := dial(host, nfsPort)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.close()
+
+	for _, name := range strings.Split(path.Clean("/"+filePath), "/") {
+		if name == "" {
+			continue
+		}
+		if handle, err = conn.lookup(handle, name); err != nil {
+			return resp, err
+		}
+	}
+	if path.Clean("/"+filePath) != "/" {
+		content, err := conn.read(handle)
+		if err == nil {
+			resp.Content = formatContent(content)
+			return resp, nil
+		}
+		var status *statusError
+		if !errors.As(err, &status) || status.status != statusIsDir {
+			return resp, err
+		}
+	}
+	resp.IsDirectory = true
+	resp.Entries, err = conn.readdir(handle)
+	return resp, err
+}
+
+// getPort returns the tcp port of the program registered on the portmapper, zero if not registered
+func getPort(host string, port int, program, version uint32) (int, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.close()
+
+	args := appendUint32(nil, program)
+	args = appendUint32(args, version)
+	args = appendUint32(args, protocolTCP)
+	args = appendUint32(args, 0)
+	r, err := conn.call(programPortmapper, 2, portmapperGetPort, args)
+	if err != nil {
+		return 0, err
+	}
+	var programPort uint32
+	err = r.decode(func() { programPort = r.uint32() })
+	if programPort > 65535 {
+		return 0, fmt.Errorf("invalid port %d", programPort)
+	}
+	return int(programPort), err
+}
+
+// dialProgram dials the program registered on the portmapper
+func dialProgram(host string, port int, program, version uint32) (*rpcConn, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	programPort, err := getPort(host, port, program, version)
+	if err != nil {
+		return nil, err
+	}
+	if programPort == 0 {
+		return nil, fmt.Errorf("program %d version %d is not registered", program, version)
+	}
+	return dial(host, programPort)
+}
+
+// mount returns the file handle of the root of the export
+func (c *rpcConn) mount(export string) ([]byte, error) {
+	r, err := c.call(programMount, 3, mountMnt, appendString(nil, export))
+	if err != nil {
+		return nil, err
+	}
+	var handle []byte
+	err = r.decode(func() {
+		r.checkStatus()
+		handle = r.opaque()
+	})
+	return handle, err
+}
+
+// unmount removes the export from the mounts of the server
+func (c *rpcConn) unmount(export string) {
+	_, _ = c.call(programMount, 3, mountUmnt, appendString(nil, export))
+}
+
+// lookup returns the file handle of the name in the directory
+func (c *rpcConn) lookup(dir []byte, name string) ([]byte, error) {
+	r, err := c.call(programNFS, 3, nfsLookup, appendString(appendOpaque(nil, dir), name))
+	if err != nil {
+		return nil, err
+	}
+	var handle []byte
+	err = r.decode(func() {
+		r.checkStatus()
+		handle = r.opaque()
+	})
+	return handle, err
+}
+
+// read returns the content of the file
+func (c *rpcConn) read(handle []byte) ([]byte, error) {
+	var content []byte
+	for eof := false; !eof && len(content) < maxReadSize; {
+		args := appendUint64(appendOpaque(nil, handle), uint64(len(content)))
+		args = appendUint32(args, readChunkSize)
+		r, err := c.call(programNFS, 3, nfsRead, args)
+		if err != nil {
+			return nil, err
+		}
+		err = r.decode(func() {
+			r.checkStatus()
+			r.postOpAttr()
+			r.uint32()
+			eof = r.bool()
+			data := r.opaque()
+			content = append(content, data...)
+			// the servers returning no data are not expected to reach the end
+			eof = eof || len(data) == 0
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(content) > maxReadSize {
+		content = content[:maxReadSize]
+	}
+	return content, nil
+}
+
+// readdir returns the names of the entries of the directory
+func (c *rpcConn) readdir(handle []byte) ([]string, error) {
+	var entries []string
+	var cookie uint64
+	verifier := make([]byte, 8)
+	for eof := false; !eof && len(entries) < maxEntries; {
+		args := appendUint64(appendOpaque(nil, handle), cookie)
+		args = append(args, verifier...)
+		args = appendUint32(args, readChunkSize)
+		r, err := c.call(programNFS, 3, nfsReaddir, args)
+		if err != nil {
+			return nil, err
+		}
+		err = r.decode(func() {
+			r.checkStatus()
+			r.postOpAttr()
+			verifier = r.read(8)
+			found := false
+			for r.bool() {
+				r.uint64()
+				name := r.string()
+				cookie = r.uint64()
+				found = true
+				if name != "." && name != ".." && len(entries) < maxEntries {
+					entries = append(entries, name)
+				}
+			}
+			eof = r.bool() || !found
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// checkStatus checks the status of a mount or nfs reply, the attributes
+// of the failed nfs replies are not read
+func (r *reader) checkStatus() {
+	if status := r.uint32(); status != 0 {
+		panic(&statusError{status: status})
+	}
+}
+
+// postOpAttr skips the optional file attributes
+func (r *reader) postOpAttr() {
+	if r.bool() {
+		r.read(fattrSize)
+	}
+}
+
+// formatContent returns the content as text if printable or as 0x prefixed hex
+func formatContent(content []byte) string {
+	if utf8.Valid(content) && !strings.ContainsRune(string(content), 0) {
+		return string(content)
+	}
+	return "0x" + hex.EncodeToString(content)
+}
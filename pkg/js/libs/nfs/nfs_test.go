@@ -0,0 +1,181 @@
+package nfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer serves the portmapper, mount and nfs programs on the same port
+// with the /srv/share export containing a docs directory and a readme file
+type fakeServer struct {
+	t    *testing.T
+	port int
+}
+
+func newFakeServer(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	server := &fakeServer{t: t, port: listener.Addr().(*net.TCPAddr).Port}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.handle(conn)
+		}
+	}()
+	return server.port
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	c := &rpcConn{conn: conn}
+	for {
+		data, err := c.readRecord()
+		if err != nil {
+			return
+		}
+		r := &reader{data: data}
+		var xid, program, procedure uint32
+		require.Nil(s.t, r.decode(func() {
+			xid = r.uint32()
+			require.Equal(s.t, uint32(msgCall), r.uint32())
+			require.Equal(s.t, uint32(2), r.uint32())
+			program = r.uint32()
+			r.uint32()
+			procedure = r.uint32()
+			require.Equal(s.t, uint32(authUnix), r.uint32())
+			r.opaque()
+			r.uint32()
+			r.opaque()
+		}))
+
+		reply := appendUint32(nil, xid)
+		reply = appendUint32(reply, msgReply)
+		reply = appendUint32(reply, replyAccepted)
+		reply = appendOpaque(appendUint32(reply, authNull), nil)
+		reply = appendUint32(reply, 0)
+		reply = append(reply, s.results(program, procedure, r)...)
+
+		// the reply is split in two fragments
+		first, second := reply[:len(reply)/2], reply[len(reply)/2:]
+		record := append(appendUint32(nil, uint32(len(first))), first...)
+		record = append(append(record, appendUint32(nil, lastFragment|uint32(len(second)))...), second...)
+		if _, err := conn.Write(record); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) results(program, procedure uint32, r *reader) []byte {
+	var args struct {
+		handle []byte
+		name   string
+		offset uint64
+	}
+	switch {
+	case program == programPortmapper && procedure == portmapperGetPort:
+		return appendUint32(nil, uint32(s.port))
+	case program == programMount && procedure == mountExport:
+		exports := appendString(appendUint32(nil, 1), "/srv/share")
+		exports = appendString(appendUint32(exports, 1), "10.0.0.0/8")
+		exports = appendString(appendUint32(exports, 1), "*.example.com")
+		exports = appendUint32(exports, 0)
+		exports = appendString(appendUint32(exports, 1), "/srv/private")
+		return appendUint32(appendUint32(exports, 0), 0)
+	case program == programMount && procedure == mountMnt:
+		if r.string() != "/srv/share" {
+			return appendUint32(nil, 13)
+		}
+		return appendOpaque(appendUint32(nil, 0), []byte("root"))
+	case program == programMount && procedure == mountUmnt:
+		return nil
+	case program == programNFS && procedure == nfsLookup:
+		args.handle, args.name = r.opaque(), r.string()
+		handles := map[string]string{"root/docs": "docs", "docs/readme.txt": "readme"}
+		handle, ok := handles[string(args.handle)+"/"+args.name]
+		if !ok {
+			return appendUint32(appendUint32(nil, 2), 0)
+		}
+		return appendUint32(appendUint32(appendOpaque(appendUint32(nil, 0), []byte(handle)), 0), 0)
+	case program == programNFS && procedure == nfsRead:
+		args.handle, args.offset = r.opaque(), r.uint64()
+		if string(args.handle) != "readme" {
+			return appendUint32(appendUint32(nil, statusIsDir), 0)
+		}
+		// the content is read in two chunks
+		content := []byte("hello ")
+		if args.offset > 0 {
+			content = []byte("world")
+		}
+		result := appendUint32(appendUint32(nil, 0), 1)
+		result = append(result, make([]byte, fattrSize)...)
+		result = appendUint32(result, uint32(len(content)))
+		result = appendUint32(result, uint32(args.offset))
+		return appendOpaque(result, content)
+	case program == programNFS && procedure == nfsReaddir:
+		args.handle, args.offset = r.opaque(), r.uint64()
+		require.Equal(s.t, "docs", string(args.handle))
+		result := append(appendUint32(appendUint32(nil, 0), 0), "verifier"...)
+		names, eof := []string{".", "..", "readme.txt"}, uint32(0)
+		if args.offset > 0 {
+			require.Equal(s.t, []byte("verifier"), r.read(8))
+			names, eof = []string{"notes"}, 1
+		}
+		for i, name := range names {
+			result = appendUint64(appendUint32(result, 1), uint64(i))
+			result = appendUint64(appendString(result, name), args.offset+uint64(i)+1)
+		}
+		return appendUint32(appendUint32(result, 0), eof)
+	}
+	return nil
+}
+
+func TestNFSClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	port := newFakeServer(t)
+	client := &NFSClient{}
+
+	exports, err := client.ListExports("127.0.0.1", port)
+	require.Nil(t, err)
+	require.Equal(t, []Export{
+		{Directory: "/srv/share", Clients: []string{"10.0.0.0/8", "*.example.com"}},
+		{Directory: "/srv/private"},
+	}, exports)
+
+	resp, err := client.Read("127.0.0.1", port, "/srv/share", "docs/readme.txt")
+	require.Nil(t, err)
+	require.Equal(t, ReadResponse{Content: "hello world"}, resp)
+
+	resp, err = client.Read("127.0.0.1", port, "/srv/share", "/docs/")
+	require.Nil(t, err)
+	require.Equal(t, ReadResponse{IsDirectory: true, Entries: []string{"readme.txt", "notes"}}, resp)
+
+	_, err = client.Read("127.0.0.1", port, "/srv/share", "missing")
+	require.EqualError(t, err, "NFS3ERR_NOENT")
+
+	_, err = client.Read("127.0.0.1", port, "/srv/private", "")
+	require.EqualError(t, err, "NFS3ERR_ACCES")
+}
+
+func TestReadRecord(t *testing.T) {
+	header := binary.BigEndian.AppendUint32(nil, maxRecordSize+1)
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		_, _ = io.Copy(server, bytes.NewReader(header))
+		_ = server.Close()
+	}()
+	_, err := (&rpcConn{conn: client}).readRecord()
+	require.True(t, strings.HasPrefix(err.Error(), "record of more than"))
+}
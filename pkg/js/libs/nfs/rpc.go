@@ -0,0 +1,284 @@
+package nfs
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout is the timeout of the connections to the server
+	timeout = 10 * time.Second
+	// maxRecordSize is the maximum size of a reply (4MB)
+	maxRecordSize = 4 * 1024 * 1024
+	// lastFragment is the record marking bit of the last fragment of a record
+	lastFragment = 0x80000000
+)
+
+// rpc message types, stats and authentication flavors (RFC 5531)
+const (
+	msgCall  = 0
+	msgReply = 1
+
+	replyAccepted = 0
+	replyDenied   = 1
+
+	authNull = 0
+	authUnix = 1
+)
+
+// acceptStats are the errors of the accepted replies
+var acceptStats = map[uint32]string{
+	1: "program unavailable",
+	2: "program version mismatch",
+	3: "procedure unavailable",
+	4: "garbage arguments",
+	5: "system error",
+}
+
+// authStats are the errors of the replies denied for authentication
+var authStats = map[uint32]string{
+	1: "bad credentials",
+	2: "rejected credentials",
+	3: "bad verifier",
+	4: "rejected verifier",
+	5: "too weak credentials",
+}
+
+// rpcConn is a connection to an rpc program over tcp
+type rpcConn struct {
+	conn net.Conn
+	xid  uint32
+}
+
+func dial(host string, port int) (*rpcConn, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return &rpcConn{conn: conn, xid: rand.Uint32()}, nil
+}
+
+func (c *rpcConn) close() {
+	_ = c.conn.Close()
+}
+
+// call calls the procedure of the program with the unix credentials
+// of root and returns the reader of the results
+func (c *rpcConn) call(program, version, procedure uint32, args []byte) (*reader, error) {
+	c.xid++
+	call := appendUint32(nil, c.xid)
+	call = appendUint32(call, msgCall)
+	call = appendUint32(call, 2)
+	call = appendUint32(call, program)
+	call = appendUint32(call, version)
+	call = appendUint32(call, procedure)
+	call = appendUint32(call, authUnix)
+	call = appendOpaque(call, unixCredentials())
+	call = appendUint32(call, authNull)
+	call = appendOpaque(call, nil)
+	call = append(call, args...)
+
+	record := appendUint32(nil, lastFragment|uint32(len(call)))
+	if _, err := c.conn.Write(append(record, call...)); err != nil {
+		return nil, err
+	}
+
+	for {
+		data, err := c.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		r := &reader{data: data}
+		var replied bool
+		err = r.decode(func() {
+			if r.uint32() != c.xid {
+				return
+			}
+			replied = true
+			if kind := r.uint32(); kind != msgReply {
+				r.fail("unexpected message type %d", kind)
+			}
+			c.checkReply(r)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if replied {
+			return r, nil
+		}
+	}
+}
+
+// checkReply checks that the call was accepted and successful
+func (c *rpcConn) checkReply(r *reader) {
+	if r.uint32() == replyDenied {
+		if r.uint32() == 0 {
+			panic(&rpcError{message: "rpc version mismatch"})
+		}
+		stat := r.uint32()
+		message, ok := authStats[stat]
+		if !ok {
+			message = fmt.Sprintf("authentication error %d", stat)
+		}
+		panic(&rpcError{message: message})
+	}
+	// the verifier of the server is ignored
+	r.uint32()
+	r.opaque()
+	if stat := r.uint32(); stat != 0 {
+		message, ok := acceptStats[stat]
+		if !ok {
+			message = fmt.Sprintf("rpc error %d", stat)
+		}
+		panic(&rpcError{message: message})
+	}
+}
+
+// readRecord reads the fragments of a record
+func (c *rpcConn) readRecord() ([]byte, error) {
+	var record []byte
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(c.conn, header); err != nil {
+			return nil, err
+		}
+		length := binary.BigEndian.Uint32(header)
+		size := int(length &^ lastFragment)
+		if len(record)+size > maxRecordSize {
+			return nil, fmt.Errorf("record of more than %d bytes", maxRecordSize)
+		}
+		fragment := make([]byte, size)
+		if _, err := io.ReadFull(c.conn, fragment); err != nil {
+			return nil, err
+		}
+		record = append(record, fragment...)
+		if length&lastFragment != 0 {
+			return record, nil
+		}
+	}
+}
+
+// unixCredentials returns the unix credentials of root, squashed
+// to the anonymous user by the default exports
+func unixCredentials() []byte {
+	credentials := appendUint32(nil, uint32(time.Now().Unix()))
+	credentials = appendString(credentials, "nuclei")
+	credentials = appendUint32(credentials, 0)
+	credentials = appendUint32(credentials, 0)
+	return appendUint32(credentials, 0)
+}
+
+// rpcError is an error of the rpc layer
+type rpcError struct {
+	message string
+}
+
+func (e *rpcError) Error() string {
+	return e.message
+}
+
+func appendUint32(buf []byte, value uint32) []byte {
+	return binary.BigEndian.AppendUint32(buf, value)
+}
+
+func appendUint64(buf []byte, value uint64) []byte {
+	return binary.BigEndian.AppendUint64(buf, value)
+}
+
+// appendOpaque appends variable length opaque data padded to a multiple of 4 bytes
+func appendOpaque(buf []byte, value []byte) []byte {
+	buf = appendUint32(buf, uint32(len(value)))
+	buf = append(buf, value...)
+	return append(buf, make([]byte, (4-len(value)%4)%4)...)
+}
+
+func appendString(buf []byte, value string) []byte {
+	return appendOpaque(buf, []byte(value))
+}
+
+// reader reads the xdr values of a reply panicking on malformed data
+type reader struct {
+	data []byte
+	pos  int
+}
+
+type decodeError struct {
+	message string
+}
+
+func (e *decodeError) Error() string {
+	return e.message
+}
+
+// decode runs the function and returns its decode, rpc or status error
+func (r *reader) decode(fn func()) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			switch value := recovered.(type) {
+			case *decodeError:
+				err = errors.Wrap(value, "could not decode reply")
+			case *rpcError:
+				err = value
+			case *statusError:
+				err = value
+			default:
+				panic(recovered)
+			}
+		}
+	}()
+	fn()
+	return nil
+}
+
+func (r *reader) fail(format string, args ...interface{}) {
+	panic(&decodeError{message: fmt.Sprintf(format, args...)})
+}
+
+func (r *reader) read(n int) []byte {
+	if n < 0 || len(r.data)-r.pos < n {
+		r.fail("unexpected end of reply")
+	}
+	value := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return value
+}
+
+func (r *reader) uint32() uint32 {
+	return binary.BigEndian.Uint32(r.read(4))
+}
+
+func (r *reader) uint64() uint64 {
+	return binary.BigEndian.Uint64(r.read(8))
+}
+
+func (r *reader) bool() bool {
+	return r.uint32() != 0
+}
+
+func (r *reader) opaque() []byte {
+	length := r.uint32()
+	if length > uint32(len(r.data)-r.pos) {
+		r.fail("unexpected end of reply")
+	}
+	value := r.read(int(length))
+	r.read(int((4 - length%4) % 4))
+	return value
+}
+
+func (r *reader) string() string {
+	return string(r.opaque())
+}
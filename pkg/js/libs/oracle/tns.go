@@ -0,0 +1,203 @@
+package oracle
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// timeout is the timeout of the listener connections
+const timeout = 5 * time.Second
+
+// types of the TNS packets
+const (
+	packetConnect  = 1
+	packetAccept   = 2
+	packetRefuse   = 4
+	packetRedirect = 5
+	packetResend   = 11
+)
+
+// listener errors of the unknown SIDs and service names
+const (
+	errUnknownSID     = 12505
+	errUnknownService = 12514
+)
+
+// maxPackets is the maximum number of packets read from a listener response
+const maxPackets = 8
+
+var (
+	vsnnumRegex = regexp.MustCompile(`VSNNUM=(\d+)`)
+	errRegex    = regexp.MustCompile(`ERR=(\d+)`)
+	bannerRegex = regexp.MustCompile(`TNSLSNR for [^\r\n]*`)
+)
+
+// TNSVersion is the version of a TNS listener.
+type TNSVersion struct {
+	// Version is the version of the listener, ex: 19.0.0.0.0
+	Version string
+	VSNNUM  int
+	// Banner is the banner of the listener if disclosed, ex: TNSLSNR for Linux: Version 19.0.0.0.0 - Production
+	Banner string
+}
+
+// GetTNSVersion sends the VERSION command to the TNS listener and returns its version.
+func (c *OracleClient) GetTNSVersion(host string, port int) (TNSVersion, error) {
+	resp := TNSVersion{}
+	_, data, err := sendConnect(host, port, "(CONNECT_DATA=(COMMAND=VERSION))")
+	if err != nil {
+		return resp, err
+	}
+	match := vsnnumRegex.FindStringSubmatch(data)
+	if match == nil {
+		return resp, errors.New("could not get listener version")
+	}
+	resp.VSNNUM, _ = strconv.Atoi(match[1])
+	resp.Version = parseVSNNUM(resp.VSNNUM)
+	resp.Banner = strings.TrimSpace(bannerRegex.FindString(data))
+	return resp, nil
+}
+
+// EnumerateSIDs tries to connect to each of the SIDs and returns the ones known by the TNS listener.
+func (c *OracleClient) EnumerateSIDs(host string, port int, sids []string) ([]string, error) {
+	return enumerate(host, port, "SID", sids)
+}
+
+// EnumerateServiceNames tries to connect to each of the service names and returns the ones known by the TNS listener.
+func (c *OracleClient) EnumerateServiceNames(host string, port int, names []string) ([]string, error) {
+	return enumerate(host, port, "SERVICE_NAME", names)
+}
+
+// enumerate returns the values of the connect data key known by the listener
+func enumerate(host string, port int, key string, values []string) ([]string, error) {
+	found := []string{}
+	for _, value := range values {
+		if value == "" || strings.ContainsAny(value, "()=") {
+			return found, fmt.Errorf("invalid %s %q", strings.ToLower(key), value)
+		}
+		connectData := fmt.Sprintf("(DESCRIPTION=(CONNECT_DATA=(%s=%s)(CID=(PROGRAM=nuclei)(HOST=nuclei)(USER=nuclei)))(ADDRESS=(PROTOCOL=TCP)(HOST=%s)(PORT=%d)))", key, value, host, port)
+		packetType, data, err := sendConnect(host, port, connectData)
+		if err != nil {
+			return found, err
+		}
+		if packetType == packetRefuse {
+			// the listener refuses the known names too when no handler is available
+			if match := errRegex.FindStringSubmatch(data); match != nil {
+				if code, _ := strconv.Atoi(match[1]); code == errUnknownSID || code == errUnknownService {
+					continue
+				}
+			}
+		}
+		found = append(found, value)
+	}
+	return found, nil
+}
+
+// sendConnect sends a connect packet with the connect data to the listener and
+// returns the type of the response packet along with the data of the response
+func sendConnect(host string, port int, connectData string) (int, string, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return 0, "", protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port <= 0 {
+		return 0, "", fmt.Errorf("invalid host or port")
+	}
+	if len(connectData) > 0xffff-58 {
+		return 0, "", errors.New("connect data is too long")
+	}
+
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return 0, "", err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	packet := connectPacket(connectData)
+	if _, err := conn.Write(packet); err != nil {
+		return 0, "", err
+	}
+	// the responses of the listener commands are sent until the connection is closed
+	command := strings.Contains(connectData, "(COMMAND=")
+	responseType := 0
+	data := &strings.Builder{}
+	for i := 0; i < maxPackets; i++ {
+		packetType, body, err := readPacket(conn)
+		if err != nil {
+			// the listener closes the connection once the response is sent
+			if responseType != 0 {
+				break
+			}
+			return 0, "", err
+		}
+		if packetType == packetResend {
+			if _, err := conn.Write(packet); err != nil {
+				return 0, "", err
+			}
+			continue
+		}
+		if responseType == 0 {
+			responseType = packetType
+		}
+		data.Write(body)
+		if !command && (packetType == packetAccept || packetType == packetRefuse || packetType == packetRedirect) {
+			break
+		}
+	}
+	return responseType, data.String(), nil
+}
+
+// connectPacket returns a TNS connect packet with the connect data
+func connectPacket(connectData string) []byte {
+	packet := make([]byte, 58, 58+len(connectData))
+	// header
+	binary.BigEndian.PutUint16(packet[0:], uint16(58+len(connectData)))
+	packet[4] = packetConnect
+	// version, compatible version and service options
+	binary.BigEndian.PutUint16(packet[8:], 0x013c)
+	binary.BigEndian.PutUint16(packet[10:], 0x012c)
+	// session data unit size, maximum transmission data unit size and protocol characteristics
+	binary.BigEndian.PutUint16(packet[14:], 0x0800)
+	binary.BigEndian.PutUint16(packet[16:], 0x7fff)
+	binary.BigEndian.PutUint16(packet[18:], 0x7f08)
+	// value of 1 in hardware
+	binary.BigEndian.PutUint16(packet[22:], 0x0001)
+	// connect data length, offset and maximum receivable connect data
+	binary.BigEndian.PutUint16(packet[24:], uint16(len(connectData)))
+	binary.BigEndian.PutUint16(packet[26:], 58)
+	binary.BigEndian.PutUint32(packet[28:], 0x00000800)
+	return append(packet, connectData...)
+}
+
+// readPacket reads a TNS packet and returns its type and the body after the header
+func readPacket(conn net.Conn) (int, []byte, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, nil, err
+	}
+	length := int(binary.BigEndian.Uint16(header[0:2]))
+	if length < 8 {
+		return 0, nil, errors.New("invalid tns packet length")
+	}
+	body := make([]byte, length-8)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return 0, nil, err
+	}
+	return int(header[4]), body, nil
+}
+
+// parseVSNNUM returns the version encoded in a VSNNUM, ex: 318767104 returns 19.0.0.0.0
+func parseVSNNUM(vsnnum int) string {
+	return fmt.Sprintf("%d.%d.%d.%d.%d", vsnnum>>24&0xff, vsnnum>>20&0xf, vsnnum>>12&0xff, vsnnum>>8&0xf, vsnnum&0xff)
+}
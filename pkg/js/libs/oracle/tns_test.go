@@ -0,0 +1,113 @@
+package oracle
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// writePacket writes a TNS packet with the body
+func writePacket(conn net.Conn, packetType byte, body []byte) {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint16(header, uint16(8+len(body)))
+	header[4] = packetType
+	_, _ = conn.Write(append(header, body...))
+}
+
+// refuse returns the body of a refuse packet with the error
+func refuse(err int) []byte {
+	data := "(DESCRIPTION=(TMP=)(VSNNUM=318767104)(ERR=" + strconv.Itoa(err) + ")(ERROR_STACK=(ERROR=(CODE=" + strconv.Itoa(err) + ")(EMFI=4))))"
+	body := []byte{0x22, 0x00, 0x00, 0x00}
+	binary.BigEndian.PutUint16(body[2:], uint16(len(data)))
+	return append(body, data...)
+}
+
+// listenTNS serves a TNS listener of the ORCL SID and the orclpdb service, the connections are resent once
+func listenTNS(t *testing.T) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				for resent := false; ; resent = true {
+					packetType, body, err := readPacket(conn)
+					if err != nil || packetType != packetConnect {
+						return
+					}
+					offset := int(binary.BigEndian.Uint16(body[18:20])) - 8
+					connectData := string(body[offset:])
+					switch {
+					case !resent:
+						writePacket(conn, packetResend, nil)
+						continue
+					case strings.Contains(connectData, "COMMAND=VERSION"):
+						writePacket(conn, packetAccept, append(make([]byte, 16), refuse(0)[4:]...))
+						banner := "TNSLSNR for Linux: Version 19.0.0.0.0 - Production\n\tTNS for Linux: Version 19.0.0.0.0 - Production\n"
+						writePacket(conn, 6, append([]byte{0, 0}, banner...))
+					case strings.Contains(connectData, "(SID=ORCL)"):
+						writePacket(conn, packetAccept, make([]byte, 16))
+					case strings.Contains(connectData, "(SERVICE_NAME=orclpdb)"):
+						// blocked by the listener since no handler is available
+						writePacket(conn, packetRefuse, refuse(12516))
+					case strings.Contains(connectData, "(SID="):
+						writePacket(conn, packetRefuse, refuse(errUnknownSID))
+					default:
+						writePacket(conn, packetRefuse, refuse(errUnknownService))
+					}
+					return
+				}
+			}()
+		}
+	}()
+
+	host, portValue, err := net.SplitHostPort(listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	return host, port
+}
+
+func TestOracleClientGetTNSVersion(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	host, port := listenTNS(t)
+	client := &OracleClient{}
+	version, err := client.GetTNSVersion(host, port)
+	require.Nil(t, err)
+	require.Equal(t, TNSVersion{Version: "19.0.0.0.0", VSNNUM: 318767104, Banner: "TNSLSNR for Linux: Version 19.0.0.0.0 - Production"}, version)
+}
+
+func TestOracleClientEnumerate(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	host, port := listenTNS(t)
+	client := &OracleClient{}
+	sids, err := client.EnumerateSIDs(host, port, []string{"XE", "ORCL", "PROD"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"ORCL"}, sids)
+
+	names, err := client.EnumerateServiceNames(host, port, []string{"orclpdb", "xepdb1"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"orclpdb"}, names)
+
+	_, err = client.EnumerateSIDs(host, port, []string{"ORCL)(SERVICE_NAME=x"})
+	require.EqualError(t, err, `invalid sid "ORCL)(SERVICE_NAME=x"`)
+}
+
+func TestParseVSNNUM(t *testing.T) {
+	require.Equal(t, "11.2.0.2.0", parseVSNNUM(186647040))
+	require.Equal(t, "12.1.0.2.0", parseVSNNUM(202375680))
+	require.Equal(t, "19.0.0.0.0", parseVSNNUM(318767104))
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// bruteForceBackoff is the initial delay of the host after a connection error
+	bruteForceBackoff = 500 * time.Millisecond
+	// bruteForceMaxBackoff is the maximum delay of the host after connection errors
+	bruteForceMaxBackoff = 8 * time.Second
+	// bruteForceMaxErrors is the number of consecutive connection errors aborting the attempts
+	bruteForceMaxErrors = 5
+)
+
+// Credential is a username and password pair
+type Credential struct {
+	Username string
+	Password string
+}
+
+// BruteForceResult is the result of a credential brute force
+type BruteForceResult struct {
+	// Found is true if a credential is valid
+	Found bool
+	// Credential is the first valid credential
+	Credential Credential
+	// Attempts is the number of login attempts
+	Attempts int
+	// Errors is the number of attempts failed due to connection errors
+	Errors int
+}
+
+// BruteForce tries the credentials on the Postgres database concurrently
+// and returns the first valid credential along with the attempt statistics.
+//
+// The attempts of the host are delayed with an exponential backoff on connection
+// errors and aborted after consecutive connection errors.
+func (c *PGClient) BruteForce(host string, port int, credentials []Credential, concurrency int) (BruteForceResult, error) {
+	if host == "" || port <= 0 {
+		return BruteForceResult{}, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return BruteForceResult{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	return bruteForce(credentials, concurrency, &backoff{delay: bruteForceBackoff, max: bruteForceMaxBackoff}, func(credential Credential) (bool, error) {
+		return c.connect(host, port, credential.Username, credential.Password, "postgres")
+	})
+}
+
+// bruteForce runs the login attempts of the credentials with the concurrency
+func bruteForce(credentials []Credential, concurrency int, hostBackoff *backoff, attempt func(Credential) (bool, error)) (BruteForceResult, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mutex   sync.Mutex
+		result  BruteForceResult
+		lastErr error
+	)
+	queue := make(chan Credential)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for credential := range queue {
+				// the credential is retried until it is tested or the attempts are aborted
+				for ctx.Err() == nil {
+					hostBackoff.wait(ctx)
+					if ctx.Err() != nil {
+						break
+					}
+					valid, err := attempt(credential)
+
+					mutex.Lock()
+					result.Attempts++
+					if err != nil {
+						result.Errors++
+						lastErr = err
+						if hostBackoff.failure() >= bruteForceMaxErrors {
+							cancel()
+						}
+						mutex.Unlock()
+						continue
+					}
+					hostBackoff.success()
+					if valid && !result.Found {
+						result.Found = true
+						result.Credential = credential
+						cancel()
+					}
+					mutex.Unlock()
+					break
+				}
+			}
+		}()
+	}
+
+	for _, credential := range credentials {
+		select {
+		case queue <- credential:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if !result.Found && hostBackoff.errors() >= bruteForceMaxErrors {
+		return result, fmt.Errorf("aborted after %d consecutive connection errors: %w", bruteForceMaxErrors, lastErr)
+	}
+	return result, nil
+}
+
+// backoff delays the attempts of a host exponentially after connection errors
+type backoff struct {
+	mutex   sync.Mutex
+	delay   time.Duration
+	max     time.Duration
+	current time.Duration
+	until   time.Time
+	// consecutive is the number of consecutive connection errors
+	consecutive int
+}
+
+// wait blocks until the host can be tried again
+func (b *backoff) wait(ctx context.Context) {
+	b.mutex.Lock()
+	remaining := time.Until(b.until)
+	b.mutex.Unlock()
+	if remaining <= 0 {
+		return
+	}
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// failure increases the delay of the host and returns the consecutive errors
+func (b *backoff) failure() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.current *= 2
+	if b.current == 0 {
+		b.current = b.delay
+	}
+	if b.current > b.max {
+		b.current = b.max
+	}
+	b.until = time.Now().Add(b.current)
+	b.consecutive++
+	return b.consecutive
+}
+
+// success resets the delay of the host
+func (b *backoff) success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.current, b.consecutive = 0, 0
+	b.until = time.Time{}
+}
+
+func (b *backoff) errors() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.consecutive
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBruteForce(t *testing.T) {
+	credentials := []Credential{
+		{Username: "postgres", Password: "postgres"},
+		{Username: "postgres", Password: "password"},
+		{Username: "admin", Password: "admin"},
+		{Username: "root", Password: "root"},
+	}
+	newBackoff := func() *backoff {
+		return &backoff{delay: time.Millisecond, max: 4 * time.Millisecond}
+	}
+
+	t.Run("found", func(t *testing.T) {
+		var mutex sync.Mutex
+		failed := false
+		result, err := bruteForce(credentials, 1, newBackoff(), func(credential Credential) (bool, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			// a transient connection error retries the credential
+			if credential.Username == "admin" && !failed {
+				failed = true
+				return false, errors.New("connection reset")
+			}
+			return credential == Credential{Username: "admin", Password: "admin"}, nil
+		})
+		require.Nil(t, err)
+		require.True(t, result.Found)
+		require.Equal(t, Credential{Username: "admin", Password: "admin"}, result.Credential)
+		require.Equal(t, 1, result.Errors)
+		require.Equal(t, 4, result.Attempts)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		result, err := bruteForce(credentials, 3, newBackoff(), func(Credential) (bool, error) {
+			return false, nil
+		})
+		require.Nil(t, err)
+		require.False(t, result.Found)
+		require.Equal(t, len(credentials), result.Attempts)
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		result, err := bruteForce(credentials, 2, newBackoff(), func(Credential) (bool, error) {
+			return false, errors.New("connection refused")
+		})
+		require.ErrorContains(t, err, "connection refused")
+		require.False(t, result.Found)
+		require.Equal(t, bruteForceMaxErrors, result.Errors)
+	})
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// poolIdleTimeout is the duration after which unused handles are closed
+const poolIdleTimeout = 30 * time.Second
+
+type pooledHandle[T io.Closer] struct {
+	value    T
+	lastUsed time.Time
+	// borrowers is the number of calls using the handle
+	borrowers int
+	// removed is true once the handle is no longer pooled, it is closed by the last borrower
+	removed bool
+}
+
+// pool is a size bounded pool of database handles keyed by host:port and credentials,
+// the least recently used idle handle is closed when the pool is full
+type pool[T io.Closer] struct {
+	mutex   sync.Mutex
+	handles map[string]*pooledHandle[T]
+	janitor sync.Once
+	// size returns the maximum number of handles
+	size func() int
+}
+
+func newPool[T io.Closer]() *pool[T] {
+	return &pool[T]{handles: make(map[string]*pooledHandle[T]), size: protocolstate.JSPoolSize}
+}
+
+// get returns the pooled handle of the key creating it if missing, the
+// returned release function must be called once the handle is no longer used
+func (p *pool[T]) get(key string, create func() (T, error)) (T, func(), error) {
+	p.janitor.Do(func() {
+		go func() {
+			for range time.Tick(poolIdleTimeout / 2) {
+				p.evictIdle(time.Now())
+			}
+		}()
+	})
+
+	p.mutex.Lock()
+	if handle, ok := p.handles[key]; ok {
+		value := p.borrow(handle)
+		p.mutex.Unlock()
+		return value, p.releaser(handle), nil
+	}
+	p.mutex.Unlock()
+
+	// the handle is created without holding the lock as it may block on the network
+	value, err := create()
+	if err != nil {
+		return value, func() {}, err
+	}
+
+	p.mutex.Lock()
+	if handle, ok := p.handles[key]; ok {
+		// the handle was created concurrently by another call
+		existing := p.borrow(handle)
+		p.mutex.Unlock()
+		_ = value.Close()
+		return existing, p.releaser(handle), nil
+	}
+	handle := &pooledHandle[T]{value: value}
+	p.handles[key] = handle
+	p.borrow(handle)
+	p.evictOverflow()
+	p.mutex.Unlock()
+	return value, p.releaser(handle), nil
+}
+
+// borrow marks the handle as used, the mutex must be held
+func (p *pool[T]) borrow(handle *pooledHandle[T]) T {
+	handle.borrowers++
+	handle.lastUsed = time.Now()
+	return handle.value
+}
+
+// releaser returns the function releasing a borrowed handle
+func (p *pool[T]) releaser(handle *pooledHandle[T]) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+
+			handle.borrowers--
+			handle.lastUsed = time.Now()
+			if handle.removed && handle.borrowers == 0 {
+				_ = handle.value.Close()
+				return
+			}
+			p.evictOverflow()
+		})
+	}
+}
+
+// remove removes the handle of the key, ex: after a failed login. The handle
+// is closed once it is released by its borrowers.
+func (p *pool[T]) remove(key string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if handle, ok := p.handles[key]; ok {
+		delete(p.handles, key)
+		handle.removed = true
+		if handle.borrowers == 0 {
+			_ = handle.value.Close()
+		}
+	}
+}
+
+// evictOverflow closes the least recently used idle handles while the pool
+// is full, the mutex must be held
+func (p *pool[T]) evictOverflow() {
+	for len(p.handles) > p.size() {
+		var oldestKey string
+		var oldest *pooledHandle[T]
+		for key, handle := range p.handles {
+			if handle.borrowers > 0 {
+				continue
+			}
+			if oldest == nil || handle.lastUsed.Before(oldest.lastUsed) {
+				oldestKey, oldest = key, handle
+			}
+		}
+		if oldest == nil {
+			// the handles in use are evicted once released
+			return
+		}
+		_ = oldest.value.Close()
+		delete(p.handles, oldestKey)
+	}
+}
+
+// evictIdle closes the handles unused for more than the idle timeout
+func (p *pool[T]) evictIdle(now time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for key, handle := range p.handles {
+		if handle.borrowers == 0 && now.Sub(handle.lastUsed) > poolIdleTimeout {
+			_ = handle.value.Close()
+			delete(p.handles, key)
+		}
+	}
+}
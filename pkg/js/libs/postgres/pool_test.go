@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHandle struct {
+	closed bool
+}
+
+func (h *fakeHandle) Close() error {
+	h.closed = true
+	return nil
+}
+
+func TestPool(t *testing.T) {
+	p := newPool[*fakeHandle]()
+	p.size = func() int { return 2 }
+
+	created := 0
+	borrow := func(key string) (*fakeHandle, func()) {
+		handle, release, err := p.get(key, func() (*fakeHandle, error) {
+			created++
+			return &fakeHandle{}, nil
+		})
+		require.Nil(t, err)
+		return handle, release
+	}
+	get := func(key string) *fakeHandle {
+		handle, release := borrow(key)
+		release()
+		return handle
+	}
+
+	first := get("a")
+	require.Equal(t, first, get("a"), "could not reuse pooled handle")
+	require.Equal(t, 1, created)
+
+	second := get("b")
+	time.Sleep(time.Millisecond)
+	get("a")
+	third := get("c")
+	require.True(t, second.closed, "could not evict least recently used handle")
+	require.False(t, first.closed)
+	require.Len(t, p.handles, 2)
+
+	p.remove("c")
+	require.True(t, third.closed, "could not close removed handle")
+
+	p.evictIdle(time.Now().Add(poolIdleTimeout + time.Second))
+	require.True(t, first.closed, "could not evict idle handle")
+	require.Empty(t, p.handles)
+
+	// the handles in use are neither evicted nor closed until released
+	used, release := borrow("a")
+	get("b")
+	get("c")
+	require.False(t, used.closed, "could not keep borrowed handle on overflow")
+	p.evictIdle(time.Now().Add(poolIdleTimeout + time.Second))
+	require.False(t, used.closed, "could not keep borrowed idle handle")
+	p.remove("a")
+	require.False(t, used.closed, "could not keep removed borrowed handle")
+	release()
+	release()
+	require.True(t, used.closed, "could not close removed handle once released")
+}
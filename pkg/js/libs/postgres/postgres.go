@@ -3,23 +3,47 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/go-pg/pg"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
 	postgres "github.com/praetorian-inc/fingerprintx/pkg/plugins/services/postgresql"
 	utils "github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
+var (
+	// pgPool and sqlPool reuse the database handles across calls and template executions
+	pgPool  = newPool[*pg.DB]()
+	sqlPool = newPool[*sql.DB]()
+)
+
 // PGClient is a client for Postgres database.
 //
 // Internally client uses go-pg/pg driver.
-type PGClient struct{}
+//
+// The database handles are pooled by host, port, credentials and tls settings
+// so that repeated calls reuse their connections.
+//
+// Connections are not encrypted unless SSLMode is set.
+type PGClient struct {
+	// SSLMode is the sslmode of the connections, one of disable, require, verify-ca or verify-full
+	SSLMode string
+	// RootCA is the PEM encoded root certificate verifying the server certificate,
+	// the system roots are used if empty
+	RootCA string
+	// ClientCert is the PEM encoded client certificate
+	ClientCert string
+	// ClientKey is the PEM encoded private key of the client certificate
+	ClientKey string
+}
 
 // IsPostgres checks if the given host and port are running Postgres database.
 //
@@ -51,16 +75,58 @@ func (c *PGClient) IsPostgres(host string, port int) (bool, error) {
 //
 // If connection is successful, it returns true.
 // If connection is unsuccessful, it returns false and error.
-//
-// The connection is closed after the function returns.
 func (c *PGClient) Connect(host string, port int, username, password string) (bool, error) {
-	return connect(host, port, username, password, "postgres")
+	return c.connect(host, port, username, password, "postgres")
 }
 
 // ExecuteQuery connects to Postgres database using given credentials and database name.
 // and executes a query on the db.
 func (c *PGClient) ExecuteQuery(host string, port int, username, password, dbName, query string) (string, error) {
+	return c.executeQuery(host, port, username, password, dbName, query)
+}
 
+// ExecuteQueryWithArgs connects to Postgres database using given credentials and database name
+// and executes a query with bind parameters on the db.
+//
+// The arguments are bound to the $1, $2... placeholders of the query, arrays are
+// bound as postgres arrays and objects as json.
+func (c *PGClient) ExecuteQueryWithArgs(host string, port int, username, password, dbName, query string, args ...interface{}) (string, error) {
+	return c.executeQuery(host, port, username, password, dbName, query, queryArgs(args)...)
+}
+
+// QuoteIdentifier quotes an identifier (ex: a table or column name) to be used in a query.
+func QuoteIdentifier(name string) string {
+	return pq.QuoteIdentifier(name)
+}
+
+// QuoteLiteral quotes a string literal to be used in a query
+// where bind parameters are not supported (ex: DDL statements).
+func QuoteLiteral(literal string) string {
+	return pq.QuoteLiteral(literal)
+}
+
+// queryArgs converts the javascript values of the arguments to values supported by the driver
+func queryArgs(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case []interface{}:
+			converted[i] = pq.Array(value)
+		case map[string]interface{}:
+			data, err := json.Marshal(value)
+			if err != nil {
+				converted[i] = fmt.Sprint(value)
+			} else {
+				converted[i] = string(data)
+			}
+		default:
+			converted[i] = value
+		}
+	}
+	return converted
+}
+
+func (c *PGClient) executeQuery(host string, port int, username, password, dbName, query string, args ...interface{}) (string, error) {
 	if !protocolstate.IsHostAllowed(host) {
 		// host is not valid according to network policy
 		return "", protocolstate.ErrHostDenied.Msgf(host)
@@ -68,16 +134,43 @@ func (c *PGClient) ExecuteQuery(host string, port int, username, password, dbNam
 
 	target := net.JoinHostPort(host, fmt.Sprintf("%d", port))
 
-	connStr := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", username, password, target, dbName)
-	db, err := sql.Open("postgres", connStr)
+	params, err := c.sslParams()
+	if err != nil {
+		return "", err
+	}
+
+	key := c.handleKey(target, username, password, dbName)
+	db, release, err := sqlPool.get(key, func() (*sql.DB, error) {
+		connStr := &url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(username, password),
+			Host:     target,
+			Path:     dbName,
+			RawQuery: params.Encode(),
+		}
+		db, err := sql.Open("postgres", connStr.String())
+		if err != nil {
+			return nil, err
+		}
+		db.SetMaxOpenConns(1)
+		db.SetConnMaxIdleTime(poolIdleTimeout)
+		return db, nil
+	})
 	if err != nil {
 		return "", err
 	}
+	defer release()
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, args...)
 	if err != nil {
+		var pqErr *pq.Error
+		// keep the handle on query errors, connection and login failures discard it
+		if !errors.As(err, &pqErr) || pqErr.Code.Class() == "28" || pqErr.Code.Class() == "3D" {
+			sqlPool.remove(key)
+		}
 		return "", err
 	}
+	defer rows.Close()
 	resp, err := utils.UnmarshalSQLRows(rows)
 	if err != nil {
 		return "", err
@@ -89,13 +182,11 @@ func (c *PGClient) ExecuteQuery(host string, port int, username, password, dbNam
 //
 // If connection is successful, it returns true.
 // If connection is unsuccessful, it returns false and error.
-//
-// The connection is closed after the function returns.
 func (c *PGClient) ConnectWithDB(host string, port int, username, password, dbName string) (bool, error) {
-	return connect(host, port, username, password, dbName)
+	return c.connect(host, port, username, password, dbName)
 }
 
-func connect(host string, port int, username, password, dbName string) (bool, error) {
+func (c *PGClient) connect(host string, port int, username, password, dbName string) (bool, error) {
 	if host == "" || port <= 0 {
 		return false, fmt.Errorf("invalid host or port")
 	}
@@ -107,14 +198,29 @@ func connect(host string, port int, username, password, dbName string) (bool, er
 
 	target := net.JoinHostPort(host, fmt.Sprintf("%d", port))
 
-	db := pg.Connect(&pg.Options{
-		Addr:     target,
-		User:     username,
-		Password: password,
-		Database: dbName,
+	tlsConfig, err := c.tlsConfig(host)
+	if err != nil {
+		return false, err
+	}
+
+	key := c.handleKey(target, username, password, dbName)
+	db, release, _ := pgPool.get(key, func() (*pg.DB, error) {
+		return pg.Connect(&pg.Options{
+			Addr:      target,
+			User:      username,
+			Password:  password,
+			Database:  dbName,
+			TLSConfig: tlsConfig,
+			// a single connection is enough as the calls of a template are sequential
+			PoolSize:    1,
+			IdleTimeout: poolIdleTimeout,
+		}), nil
 	})
-	_, err := db.Exec("select 1")
+	defer release()
+	_, err = db.Exec("select 1")
 	if err != nil {
+		// postgres authenticates connections so failed logins can't be reused
+		pgPool.remove(key)
 		switch true {
 		case strings.Contains(err.Error(), "connect: connection refused"):
 			fallthrough
@@ -131,3 +237,8 @@ func connect(host string, port int, username, password, dbName string) (bool, er
 	}
 	return true, nil
 }
+
+// handleKey returns the pool key of a database handle
+func (c *PGClient) handleKey(target, username, password, dbName string) string {
+	return strings.Join([]string{target, username, password, dbName, c.SSLMode, c.RootCA, c.ClientCert, c.ClientKey}, "\x00")
+}
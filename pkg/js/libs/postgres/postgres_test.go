@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryArgs(t *testing.T) {
+	args := queryArgs([]interface{}{"admin", int64(1), nil, []interface{}{int64(1), int64(2)}, map[string]interface{}{"role": "admin"}})
+	require.Equal(t, "admin", args[0])
+	require.Equal(t, int64(1), args[1])
+	require.Nil(t, args[2])
+	require.Equal(t, pq.Array([]interface{}{int64(1), int64(2)}), args[3], "could not convert array argument")
+	require.Equal(t, `{"role":"admin"}`, args[4], "could not convert object argument")
+}
+
+func TestQuote(t *testing.T) {
+	require.Equal(t, `"users"" --"`, QuoteIdentifier(`users" --`))
+	require.Equal(t, `'it''s'`, QuoteLiteral(`it's`))
+	require.Equal(t, ` E'back\\slash'`, QuoteLiteral(`back\slash`))
+}
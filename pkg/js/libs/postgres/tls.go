@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// sslModes are the supported sslmode values of the client
+var sslModes = map[string]struct{}{
+	"disable":     {},
+	"require":     {},
+	"verify-ca":   {},
+	"verify-full": {},
+}
+
+// sslMode returns the sslmode of the client, disable if unset
+func (c *PGClient) sslMode() (string, error) {
+	if c.SSLMode == "" {
+		return "disable", nil
+	}
+	if _, ok := sslModes[c.SSLMode]; !ok {
+		return "", fmt.Errorf("unsupported sslmode %s", c.SSLMode)
+	}
+	if (c.ClientCert == "") != (c.ClientKey == "") {
+		return "", errors.New("client certificate and key must be both set")
+	}
+	return c.SSLMode, nil
+}
+
+// tlsConfig returns the go-pg tls config of the client following the libpq sslmode semantics,
+// require only verifies the server certificate when a root CA is set.
+func (c *PGClient) tlsConfig(host string) (*tls.Config, error) {
+	mode, err := c.sslMode()
+	if err != nil || mode == "disable" {
+		return nil, err
+	}
+
+	config := &tls.Config{Renegotiation: tls.RenegotiateFreelyAsClient}
+	if c.RootCA != "" {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM([]byte(c.RootCA)) {
+			return nil, errors.New("could not parse root ca")
+		}
+	}
+	if c.ClientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(c.ClientCert), []byte(c.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	switch {
+	case mode == "verify-full":
+		config.ServerName = host
+	case mode == "verify-ca" || config.RootCAs != nil:
+		// the default verification also checks the host name so the chain is verified manually
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = verifyCertificateAuthority(config.RootCAs)
+	default:
+		config.InsecureSkipVerify = true
+	}
+	return config, nil
+}
+
+// verifyCertificateAuthority verifies the server certificate chain against the roots
+// or the system roots if nil, without checking the host name
+func verifyCertificateAuthority(roots *x509.CertPool) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return errors.New("server did not present a certificate")
+		}
+		options := x509.VerifyOptions{Roots: roots, Intermediates: x509.NewCertPool()}
+		for _, cert := range state.PeerCertificates[1:] {
+			options.Intermediates.AddCert(cert)
+		}
+		_, err := state.PeerCertificates[0].Verify(options)
+		return err
+	}
+}
+
+// sslParams returns the lib/pq connection string parameters of the client.
+//
+// lib/pq only accepts inline certificates along with a client certificate
+// so the certificates are written to files named after their contents.
+func (c *PGClient) sslParams() (url.Values, error) {
+	mode, err := c.sslMode()
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{"sslmode": []string{mode}}
+	if mode == "disable" {
+		return params, nil
+	}
+	for name, contents := range map[string]string{"sslrootcert": c.RootCA, "sslcert": c.ClientCert, "sslkey": c.ClientKey} {
+		if contents == "" {
+			continue
+		}
+		path, err := writeCertificateFile(contents)
+		if err != nil {
+			return nil, err
+		}
+		params.Set(name, path)
+	}
+	return params, nil
+}
+
+// writeCertificateFile writes the pem contents to a file only readable by the user
+// as required by lib/pq for keys and returns its path
+func writeCertificateFile(contents string) (string, error) {
+	directory := filepath.Join(os.TempDir(), "nuclei-postgres")
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(contents))
+	path := filepath.Join(directory, hex.EncodeToString(hash[:])+".pem")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	// the file is renamed once written as concurrent connections may read it
+	file, err := os.CreateTemp(directory, "*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(contents); err != nil {
+		_ = file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
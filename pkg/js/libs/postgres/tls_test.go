@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newCertificate returns a certificate signed by the parent or self signed if nil
+func newCertificate(t *testing.T, name string, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	signer, signerKey := template, interface{}(key)
+	if parent == nil {
+		template.IsCA, template.BasicConstraintsValid = true, true
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.Nil(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func certificatePEM(cert tls.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}))
+}
+
+func handshake(t *testing.T, server tls.Certificate, config *tls.Config) error {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{server}})
+	require.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.Nil(t, err)
+	defer conn.Close()
+	return tls.Client(conn, config).Handshake()
+}
+
+func TestTLSConfig(t *testing.T) {
+	ca := newCertificate(t, "ca", nil)
+	other := newCertificate(t, "other", nil)
+	server := newCertificate(t, "db.local", &ca)
+
+	tests := []struct {
+		name    string
+		client  PGClient
+		host    string
+		success bool
+	}{
+		{name: "require", client: PGClient{SSLMode: "require"}, host: "127.0.0.1", success: true},
+		{name: "require-root-ca", client: PGClient{SSLMode: "require", RootCA: certificatePEM(other)}, host: "db.local", success: false},
+		{name: "verify-ca", client: PGClient{SSLMode: "verify-ca", RootCA: certificatePEM(ca)}, host: "127.0.0.1", success: true},
+		{name: "verify-ca-invalid", client: PGClient{SSLMode: "verify-ca", RootCA: certificatePEM(other)}, host: "127.0.0.1", success: false},
+		{name: "verify-full", client: PGClient{SSLMode: "verify-full", RootCA: certificatePEM(ca)}, host: "db.local", success: true},
+		{name: "verify-full-host-mismatch", client: PGClient{SSLMode: "verify-full", RootCA: certificatePEM(ca)}, host: "127.0.0.1", success: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := test.client.tlsConfig(test.host)
+			require.Nil(t, err)
+			err = handshake(t, server, config)
+			if test.success {
+				require.Nil(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+
+	config, err := (&PGClient{}).tlsConfig("db.local")
+	require.Nil(t, err)
+	require.Nil(t, config, "could not disable tls by default")
+
+	for _, client := range []PGClient{
+		{SSLMode: "prefer"},
+		{SSLMode: "verify-ca", RootCA: "invalid"},
+		{SSLMode: "require", ClientCert: certificatePEM(ca)},
+	} {
+		_, err := client.tlsConfig("db.local")
+		require.Error(t, err, "could build invalid tls config %+v", client)
+	}
+}
+
+func TestSSLParams(t *testing.T) {
+	params, err := (&PGClient{}).sslParams()
+	require.Nil(t, err)
+	require.Equal(t, "sslmode=disable", params.Encode())
+
+	ca := certificatePEM(newCertificate(t, "ca", nil))
+	params, err = (&PGClient{SSLMode: "verify-full", RootCA: ca}).sslParams()
+	require.Nil(t, err)
+	require.Equal(t, "verify-full", params.Get("sslmode"))
+	require.Empty(t, params.Get("sslcert"))
+
+	data, err := os.ReadFile(params.Get("sslrootcert"))
+	require.Nil(t, err)
+	require.Equal(t, ca, string(data))
+}
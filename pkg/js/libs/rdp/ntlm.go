@@ -0,0 +1,108 @@
+package rdp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+)
+
+// ntlmSignature is the signature of the NTLM messages
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+// ntlmNegotiate is a NTLM negotiate message requesting the target info and the version
+//
+// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-nlmp/b34032e5-3aae-4bc6-84c3-c6d80eadf7f2
+var ntlmNegotiate = []byte{
+	// Signature
+	'N', 'T', 'L', 'M', 'S', 'S', 'P', 0x00,
+	// Message Type
+	0x01, 0x00, 0x00, 0x00,
+	// Negotiate Flags
+	0xb7, 0x82, 0x08, 0xe2,
+	// Domain Name Fields
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	// Workstation Fields
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	// Version
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+}
+
+// ntlmNegotiateVersion is the flag of the challenges containing the version
+const ntlmNegotiateVersion = 0x02000000
+
+// parseNTLMChallenge parses the target name, target info and version of a NTLM challenge message
+//
+// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-nlmp/801a4681-8809-4be9-ab0d-61dcfe762786
+func parseNTLMChallenge(data []byte) (NTLMInfo, error) {
+	info := NTLMInfo{}
+	start := bytes.Index(data, ntlmSignature)
+	if start == -1 {
+		return info, errors.New("invalid ntlm challenge")
+	}
+	data = data[start:]
+	if len(data) < 48 || binary.LittleEndian.Uint32(data[8:12]) != 2 {
+		return info, errors.New("invalid ntlm challenge")
+	}
+
+	targetName, err := ntlmField(data, 12)
+	if err != nil {
+		return info, err
+	}
+	info.TargetName = decodeUTF16(targetName)
+
+	flags := binary.LittleEndian.Uint32(data[20:24])
+	if flags&ntlmNegotiateVersion != 0 && len(data) >= 56 {
+		info.OSVersion = fmt.Sprintf("%d.%d.%d", data[48], data[49], binary.LittleEndian.Uint16(data[50:52]))
+	}
+
+	targetInfo, err := ntlmField(data, 40)
+	if err != nil {
+		return info, err
+	}
+	for len(targetInfo) >= 4 {
+		id := binary.LittleEndian.Uint16(targetInfo[0:2])
+		length := int(binary.LittleEndian.Uint16(targetInfo[2:4]))
+		if id == 0 || len(targetInfo) < 4+length {
+			break
+		}
+		value := decodeUTF16(targetInfo[4 : 4+length])
+		switch id {
+		case 1:
+			info.NetBIOSComputerName = value
+		case 2:
+			info.NetBIOSDomainName = value
+		case 3:
+			info.DNSComputerName = value
+		case 4:
+			info.DNSDomainName = value
+		case 5:
+			info.ForestName = value
+		}
+		targetInfo = targetInfo[4+length:]
+	}
+	return info, nil
+}
+
+// ntlmField returns the payload of the field described at the offset of the message
+func ntlmField(data []byte, offset int) ([]byte, error) {
+	length := int(binary.LittleEndian.Uint16(data[offset : offset+2]))
+	start := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+	if length == 0 {
+		return nil, nil
+	}
+	if start+length > len(data) {
+		return nil, errors.New("invalid ntlm challenge field")
+	}
+	return data[start : start+length], nil
+}
+
+// decodeUTF16 decodes the little endian utf16 strings of the NTLM messages
+func decodeUTF16(data []byte) string {
+	values := make([]uint16, len(data)/2)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(values))
+}
@@ -0,0 +1,245 @@
+package rdp
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// timeout is the timeout of the negotiations
+const timeout = 5 * time.Second
+
+// security protocols of the RDP negotiation requests
+const (
+	protocolRDP      uint32 = 0x00000000
+	protocolSSL      uint32 = 0x00000001
+	protocolHybrid   uint32 = 0x00000002
+	protocolRDSTLS   uint32 = 0x00000004
+	protocolHybridEx uint32 = 0x00000008
+)
+
+// securityProtocols are the probed security protocols along with their names
+var securityProtocols = []struct {
+	name     string
+	protocol uint32
+}{
+	{"PROTOCOL_RDP", protocolRDP},
+	{"PROTOCOL_SSL", protocolSSL},
+	{"PROTOCOL_HYBRID", protocolHybrid},
+	{"PROTOCOL_RDSTLS", protocolRDSTLS},
+	{"PROTOCOL_HYBRID_EX", protocolHybridEx},
+}
+
+// SecurityLayer is the security layer negotiated by a rdp server.
+type SecurityLayer struct {
+	// NLA is true if the server enforces the network level authentication
+	// through CredSSP, the standard RDP security and TLS are refused
+	NLA bool
+	// Protocols are the accepted security protocols, ex: PROTOCOL_SSL or PROTOCOL_HYBRID
+	Protocols []string
+}
+
+// NTLMInfo is the information leaked by the NTLM challenge of a rdp server.
+type NTLMInfo struct {
+	TargetName          string
+	NetBIOSComputerName string
+	NetBIOSDomainName   string
+	DNSComputerName     string
+	DNSDomainName       string
+	ForestName          string
+	// OSVersion is the version of the OS in the major.minor.build format, ex: 10.0.17763
+	OSVersion string
+}
+
+// GetSecurityLayer checks the security protocols accepted by the rdp server
+// and if the network level authentication is enforced.
+//
+// A connection is made for each of the protocols.
+func (c *RDPClient) GetSecurityLayer(host string, port int) (SecurityLayer, error) {
+	resp := SecurityLayer{Protocols: []string{}}
+	accepted := map[uint32]bool{}
+	for _, item := range securityProtocols {
+		selected, ok, err := negotiate(host, port, item.protocol)
+		if err != nil {
+			return resp, err
+		}
+		if ok && selected == item.protocol {
+			accepted[item.protocol] = true
+			resp.Protocols = append(resp.Protocols, item.name)
+		}
+	}
+	resp.NLA = !accepted[protocolRDP] && !accepted[protocolSSL] && (accepted[protocolHybrid] || accepted[protocolHybridEx])
+	return resp, nil
+}
+
+// GetNTLMInfo negotiates CredSSP with the rdp server and returns the
+// information of its NTLM challenge, ex: the hostname, domain and OS build.
+func (c *RDPClient) GetNTLMInfo(host string, port int) (NTLMInfo, error) {
+	resp := NTLMInfo{}
+	conn, err := dial(host, port)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.Close()
+
+	selected, ok, err := sendNegotiationRequest(conn, protocolSSL|protocolHybrid|protocolHybridEx)
+	if err != nil {
+		return resp, err
+	}
+	if !ok || (selected != protocolHybrid && selected != protocolHybridEx) {
+		return resp, errors.New("server does not support credssp")
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10})
+	if err := tlsConn.Handshake(); err != nil {
+		return resp, err
+	}
+	request, err := asn1.Marshal(tsRequest{Version: 6, NegoTokens: []negoToken{{Token: ntlmNegotiate}}})
+	if err != nil {
+		return resp, err
+	}
+	if _, err := tlsConn.Write(request); err != nil {
+		return resp, err
+	}
+	response, err := readTSRequest(tlsConn)
+	if err != nil {
+		return resp, err
+	}
+	if len(response.NegoTokens) == 0 {
+		return resp, errors.New("no ntlm challenge received")
+	}
+	return parseNTLMChallenge(response.NegoTokens[0].Token)
+}
+
+// dial connects to the rdp server with the nuclei dialer
+func dial(host string, port int) (net.Conn, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return conn, nil
+}
+
+// negotiate requests the protocols on a new connection
+func negotiate(host string, port int, protocols uint32) (uint32, bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Close()
+
+	return sendNegotiationRequest(conn, protocols)
+}
+
+// sendNegotiationRequest sends a X.224 connection request with the requested
+// protocols and returns the protocol selected by the server, ok is false
+// if the server refused the protocols.
+//
+// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-rdpbcgr/18a27ef9-6f9a-4501-b000-94b1fe3c2c10
+func sendNegotiationRequest(conn net.Conn, protocols uint32) (uint32, bool, error) {
+	request := []byte{
+		// TPKT header
+		0x03, 0x00, 0x00, 0x13,
+		// X.224 connection request
+		0x0e, 0xe0, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// RDP negotiation request
+		0x01, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	binary.LittleEndian.PutUint32(request[15:], protocols)
+	if _, err := conn.Write(request); err != nil {
+		return 0, false, err
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, false, err
+	}
+	length := int(binary.BigEndian.Uint16(header[2:]))
+	if header[0] != 0x03 || length < 11 {
+		return 0, false, errors.New("invalid tpkt header")
+	}
+	response := make([]byte, length-4)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return 0, false, err
+	}
+	if response[1]&0xf0 != 0xd0 {
+		return 0, false, errors.New("invalid x.224 connection confirm")
+	}
+	// the servers without negotiation support only the standard RDP security
+	if len(response) < 15 {
+		return protocolRDP, true, nil
+	}
+	switch response[7] {
+	case 0x02: // RDP negotiation response
+		return binary.LittleEndian.Uint32(response[11:15]), true, nil
+	case 0x03: // RDP negotiation failure
+		return 0, false, nil
+	default:
+		return 0, false, errors.New("invalid rdp negotiation response")
+	}
+}
+
+// tsRequest is the CredSSP message carrying the SPNEGO tokens
+//
+// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-cssp/6aac4dea-08ef-47a6-8747-22ea7f6d8685
+type tsRequest struct {
+	Version    int         `asn1:"explicit,tag:0"`
+	NegoTokens []negoToken `asn1:"optional,explicit,tag:1"`
+}
+
+type negoToken struct {
+	Token []byte `asn1:"explicit,tag:0"`
+}
+
+// readTSRequest reads a DER encoded TSRequest
+func readTSRequest(conn net.Conn) (tsRequest, error) {
+	request := tsRequest{}
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return request, err
+	}
+	if header[0] != 0x30 {
+		return request, errors.New("invalid credssp response")
+	}
+	length := int(header[1])
+	var lengthBytes []byte
+	if length&0x80 != 0 {
+		lengthBytes = make([]byte, length&0x7f)
+		if len(lengthBytes) > 2 {
+			return request, errors.New("invalid credssp response length")
+		}
+		if _, err := io.ReadFull(conn, lengthBytes); err != nil {
+			return request, err
+		}
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return request, err
+	}
+	data := append(append(header, lengthBytes...), body...)
+	if _, err := asn1.Unmarshal(data, &request); err != nil {
+		return request, err
+	}
+	return request, nil
+}
@@ -0,0 +1,178 @@
+package rdp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeUTF16(value string) []byte {
+	data := []byte{}
+	for _, item := range utf16.Encode([]rune(value)) {
+		data = binary.LittleEndian.AppendUint16(data, item)
+	}
+	return data
+}
+
+// ntlmChallenge returns a challenge of the WIN-DC01 computer of the EXAMPLE domain
+func ntlmChallenge() []byte {
+	targetName := encodeUTF16("EXAMPLE")
+	targetInfo := []byte{}
+	for _, pair := range []struct {
+		id    uint16
+		value string
+	}{{2, "EXAMPLE"}, {1, "WIN-DC01"}, {4, "example.com"}, {3, "win-dc01.example.com"}, {5, "example.com"}} {
+		value := encodeUTF16(pair.value)
+		targetInfo = binary.LittleEndian.AppendUint16(targetInfo, pair.id)
+		targetInfo = binary.LittleEndian.AppendUint16(targetInfo, uint16(len(value)))
+		targetInfo = append(targetInfo, value...)
+	}
+	targetInfo = append(targetInfo, 0, 0, 0, 0)
+
+	challenge := append([]byte{}, ntlmSignature...)
+	challenge = binary.LittleEndian.AppendUint32(challenge, 2)
+	challenge = binary.LittleEndian.AppendUint16(challenge, uint16(len(targetName)))
+	challenge = binary.LittleEndian.AppendUint16(challenge, uint16(len(targetName)))
+	challenge = binary.LittleEndian.AppendUint32(challenge, 56)
+	challenge = binary.LittleEndian.AppendUint32(challenge, 0xe2898215)
+	challenge = append(challenge, make([]byte, 16)...)
+	challenge = binary.LittleEndian.AppendUint16(challenge, uint16(len(targetInfo)))
+	challenge = binary.LittleEndian.AppendUint16(challenge, uint16(len(targetInfo)))
+	challenge = binary.LittleEndian.AppendUint32(challenge, uint32(56+len(targetName)))
+	challenge = append(challenge, 10, 0, 0x63, 0x45, 0, 0, 0, 0x0f)
+	challenge = append(challenge, targetName...)
+	return append(challenge, targetInfo...)
+}
+
+// listenRDP serves a rdp server accepting the protocols, NLA is enforced if hybrid only is accepted
+func listenRDP(t *testing.T, protocols uint32) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "WIN-DC01"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				request := make([]byte, 19)
+				if _, err := io.ReadFull(conn, request); err != nil {
+					return
+				}
+				requested := binary.LittleEndian.Uint32(request[15:])
+				response := []byte{0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00, 0x02, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00}
+				selected := uint32(0)
+				for _, protocol := range []uint32{protocolHybridEx, protocolHybrid, protocolSSL} {
+					if requested&protocol != 0 && protocols&protocol != 0 {
+						selected = protocol
+						break
+					}
+				}
+				switch {
+				case selected != 0:
+					binary.LittleEndian.PutUint32(response[15:], selected)
+				case requested == protocolRDP && protocols&0x80000000 != 0:
+				default:
+					// HYBRID_REQUIRED_BY_SERVER
+					response[11] = 0x03
+					binary.LittleEndian.PutUint32(response[15:], 5)
+				}
+				if _, err := conn.Write(response); err != nil || (selected != protocolHybrid && selected != protocolHybridEx) {
+					return
+				}
+
+				tlsConn := tls.Server(conn, tlsConfig)
+				if _, err := readTSRequest(tlsConn); err != nil {
+					return
+				}
+				challenge, _ := asn1.Marshal(tsRequest{Version: 6, NegoTokens: []negoToken{{Token: ntlmChallenge()}}})
+				_, _ = tlsConn.Write(challenge)
+			}()
+		}
+	}()
+
+	host, portValue, err := net.SplitHostPort(listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	return host, port
+}
+
+func TestRDPClientGetSecurityLayer(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	client := &RDPClient{}
+
+	host, port := listenRDP(t, protocolHybrid)
+	layer, err := client.GetSecurityLayer(host, port)
+	require.Nil(t, err)
+	require.Equal(t, SecurityLayer{NLA: true, Protocols: []string{"PROTOCOL_HYBRID"}}, layer)
+
+	// 0x80000000 accepts the standard RDP security
+	host, port = listenRDP(t, 0x80000000|protocolSSL|protocolHybrid)
+	layer, err = client.GetSecurityLayer(host, port)
+	require.Nil(t, err)
+	require.Equal(t, SecurityLayer{NLA: false, Protocols: []string{"PROTOCOL_RDP", "PROTOCOL_SSL", "PROTOCOL_HYBRID"}}, layer)
+}
+
+func TestRDPClientGetNTLMInfo(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	client := &RDPClient{}
+
+	host, port := listenRDP(t, protocolSSL|protocolHybrid)
+	info, err := client.GetNTLMInfo(host, port)
+	require.Nil(t, err)
+	require.Equal(t, NTLMInfo{
+		TargetName:          "EXAMPLE",
+		NetBIOSComputerName: "WIN-DC01",
+		NetBIOSDomainName:   "EXAMPLE",
+		DNSComputerName:     "win-dc01.example.com",
+		DNSDomainName:       "example.com",
+		ForestName:          "example.com",
+		OSVersion:           "10.0.17763",
+	}, info)
+
+	host, port = listenRDP(t, protocolSSL)
+	_, err = client.GetNTLMInfo(host, port)
+	require.EqualError(t, err, "server does not support credssp")
+}
+
+func TestParseNTLMChallenge(t *testing.T) {
+	_, err := parseNTLMChallenge([]byte("invalid"))
+	require.EqualError(t, err, "invalid ntlm challenge")
+
+	challenge := ntlmChallenge()
+	// the target info is out of the message
+	binary.LittleEndian.PutUint32(challenge[44:], 1024)
+	_, err = parseNTLMChallenge(challenge)
+	require.EqualError(t, err, "invalid ntlm challenge field")
+}
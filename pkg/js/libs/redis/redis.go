@@ -2,7 +2,12 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
@@ -12,129 +17,200 @@ import (
 	pluginsredis "github.com/praetorian-inc/fingerprintx/pkg/plugins/services/redis"
 )
 
+// RedisClient is a client for Redis servers.
+//
+// The connections are authenticated with the ACL username and the password
+// of the calls if Username is set, with the legacy password authentication otherwise.
+type RedisClient struct {
+	// Username is the ACL username of the Redis 6 servers
+	Username string
+	// TLS enables TLS connections
+	TLS bool
+	// RootCA is the PEM encoded root certificate verifying the server certificate,
+	// the system roots are used if empty
+	RootCA string
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool
+}
+
 // GetServerInfo returns the server info for a redis server
 func GetServerInfo(host string, port int) (string, error) {
-	if !protocolstate.IsHostAllowed(host) {
-		// host is not valid according to network policy
-		return "", protocolstate.ErrHostDenied.Msgf(host)
-	}
-	// create a new client
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", host, port),
-		Password: "", // no password set
-		DB:       0,  // use default DB
-	})
+	client := &RedisClient{}
+	return client.GetServerInfo(host, port, "")
+}
 
-	// Ping the Redis server
-	_, err := client.Ping(context.TODO()).Result()
+// Connect tries to connect redis server with password
+func Connect(host string, port int, password string) (bool, error) {
+	client := &RedisClient{}
+	return client.Connect(host, port, password)
+}
+
+// GetServerInfoAuth returns the server info for a redis server
+func GetServerInfoAuth(host string, port int, password string) (string, error) {
+	client := &RedisClient{}
+	return client.GetServerInfo(host, port, password)
+}
+
+// IsAuthenticated checks if the redis server requires authentication
+func IsAuthenticated(host string, port int) (bool, error) {
+	plugin := pluginsredis.REDISPlugin{}
+	timeout := 5 * time.Second
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", host, port))
 	if err != nil {
-		return "", err
+		return false, err
 	}
+	defer conn.Close()
 
-	// Get Redis server info
-	infoCmd := client.Info(context.TODO())
-	if infoCmd.Err() != nil {
-		return "", infoCmd.Err()
+	_, err = plugin.Run(conn, timeout, plugins.Target{Host: host})
+	if err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	return infoCmd.Val(), nil
+// RunLuaScript runs a lua script on
+func RunLuaScript(host string, port int, password string, script string) (interface{}, error) {
+	client := &RedisClient{}
+	return client.RunLuaScript(host, port, password, script)
 }
 
-// Connect tries to connect redis server with password
-func Connect(host string, port int, password string) (bool, error) {
-	if !protocolstate.IsHostAllowed(host) {
-		// host is not valid according to network policy
-		return false, protocolstate.ErrHostDenied.Msgf(host)
-	}
-	// create a new client
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", host, port),
-		Password: password, // no password set
-		DB:       0,        // use default DB
-	})
-	_, err := client.Ping(context.TODO()).Result()
+// Connect tries to connect redis server with the username of the client and the password.
+func (c *RedisClient) Connect(host string, port int, password string) (bool, error) {
+	client, err := c.connect(host, port, password)
 	if err != nil {
 		return false, err
 	}
+	defer client.Close()
+
 	// Get Redis server info
 	infoCmd := client.Info(context.TODO())
 	if infoCmd.Err() != nil {
 		return false, infoCmd.Err()
 	}
-
 	return true, nil
 }
 
-// GetServerInfoAuth returns the server info for a redis server
-func GetServerInfoAuth(host string, port int, password string) (string, error) {
-	if !protocolstate.IsHostAllowed(host) {
-		// host is not valid according to network policy
-		return "", protocolstate.ErrHostDenied.Msgf(host)
-	}
-	// create a new client
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", host, port),
-		Password: password, // no password set
-		DB:       0,        // use default DB
-	})
-
-	// Ping the Redis server
-	_, err := client.Ping(context.TODO()).Result()
+// GetServerInfo returns the server info for a redis server
+func (c *RedisClient) GetServerInfo(host string, port int, password string) (string, error) {
+	client, err := c.connect(host, port, password)
 	if err != nil {
 		return "", err
 	}
+	defer client.Close()
 
 	// Get Redis server info
 	infoCmd := client.Info(context.TODO())
 	if infoCmd.Err() != nil {
 		return "", infoCmd.Err()
 	}
+	return infoCmd.Val(), nil
+}
+
+// RunLuaScript runs a lua script on the redis server
+func (c *RedisClient) RunLuaScript(host string, port int, password string, script string) (interface{}, error) {
+	client, err := c.connect(host, port, password)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
 
+	infoCmd := client.Eval(context.Background(), script, []string{})
+	if infoCmd.Err() != nil {
+		return "", infoCmd.Err()
+	}
 	return infoCmd.Val(), nil
 }
 
-// IsAuthenticated checks if the redis server requires authentication
-func IsAuthenticated(host string, port int) (bool, error) {
-	plugin := pluginsredis.REDISPlugin{}
-	timeout := 5 * time.Second
-	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", host, port))
+// RunCommand runs a command with its arguments on the redis server and returns the reply.
+//
+// The replies are returned as strings, numbers, arrays or objects,
+// the nil replies are returned as null and the error replies as errors.
+func (c *RedisClient) RunCommand(host string, port int, password string, args ...interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+	client, err := c.connect(host, port, password)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	defer conn.Close()
+	defer client.Close()
 
-	_, err = plugin.Run(conn, timeout, plugins.Target{Host: host})
+	value, err := client.Do(context.TODO(), args...).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return true, nil
+	return replyValue(value), nil
 }
 
-// RunLuaScript runs a lua script on
-func RunLuaScript(host string, port int, password string, script string) (interface{}, error) {
+// replyValue converts the maps of the RESP3 replies to objects
+func replyValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case []interface{}:
+		for i, item := range value {
+			value[i] = replyValue(item)
+		}
+		return value
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			object[fmt.Sprint(key)] = replyValue(item)
+		}
+		return object
+	default:
+		return value
+	}
+}
+
+// connect returns a client connected to the server
+func (c *RedisClient) connect(host string, port int, password string) (*redis.Client, error) {
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
 	if !protocolstate.IsHostAllowed(host) {
 		// host is not valid according to network policy
-		return false, protocolstate.ErrHostDenied.Msgf(host)
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+
+	tlsConfig, err := c.tlsConfig(host)
+	if err != nil {
+		return nil, err
 	}
-	// create a new client
 	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", host, port),
+		Addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		Username: c.Username,
 		Password: password,
 		DB:       0, // use default DB
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if tlsConfig != nil {
+				return protocolstate.Dialer.DialTLSWithConfig(ctx, network, addr, tlsConfig)
+			}
+			return protocolstate.Dialer.Dial(ctx, network, addr)
+		},
 	})
 
 	// Ping the Redis server
-	_, err := client.Ping(context.TODO()).Result()
+	_, err = client.Ping(context.TODO()).Result()
 	if err != nil {
-		return "", err
+		_ = client.Close()
+		return nil, err
 	}
+	return client, nil
+}
 
-	// Get Redis server info
-	infoCmd := client.Eval(context.Background(), script, []string{})
-
-	if infoCmd.Err() != nil {
-		return "", infoCmd.Err()
+// tlsConfig returns the tls config of the client, nil if TLS is disabled
+func (c *RedisClient) tlsConfig(host string) (*tls.Config, error) {
+	if !c.TLS {
+		return nil, nil
 	}
-
-	return infoCmd.Val(), nil
+	config := &tls.Config{ServerName: host, InsecureSkipVerify: c.InsecureSkipVerify, MinVersion: tls.VersionTLS10}
+	if c.RootCA != "" {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM([]byte(c.RootCA)) {
+			return nil, errors.New("could not parse root ca")
+		}
+	}
+	return config, nil
 }
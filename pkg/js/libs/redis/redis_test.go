@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// serveRedis serves a redis 6 server without RESP3 support, the admin user has the secret password
+func serveRedis(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			authenticated := false
+			for {
+				args, err := readCommand(reader)
+				if err != nil {
+					return
+				}
+				var reply string
+				switch strings.ToUpper(args[0]) {
+				case "HELLO":
+					reply = "-ERR unknown command 'HELLO'\r\n"
+				case "AUTH":
+					if len(args) == 3 && args[1] == "admin" && args[2] == "secret" {
+						authenticated = true
+						reply = "+OK\r\n"
+					} else {
+						reply = "-WRONGPASS invalid username-password pair or user is disabled.\r\n"
+					}
+				case "PING":
+					reply = "+PONG\r\n"
+				case "CLIENT":
+					reply = "+OK\r\n"
+				default:
+					if !authenticated {
+						reply = "-NOAUTH Authentication required.\r\n"
+						break
+					}
+					switch strings.ToUpper(args[0]) {
+					case "INFO":
+						reply = "$21\r\n# Server\r\nversion:7\r\n\r\n"
+					case "CONFIG":
+						reply = "*2\r\n$3\r\ndir\r\n$4\r\n/tmp\r\n"
+					case "DBSIZE":
+						reply = ":42\r\n"
+					case "GET":
+						reply = "$-1\r\n"
+					default:
+						reply = "-ERR unknown command\r\n"
+					}
+				}
+				if _, err := conn.Write([]byte(reply)); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("invalid command %q", line)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+	return args, nil
+}
+
+func listenRedis(t *testing.T, listener net.Listener) (string, int) {
+	go serveRedis(listener)
+	host, portValue, err := net.SplitHostPort(listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	return host, port
+}
+
+func TestRedisClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+	host, port := listenRedis(t, listener)
+
+	client := &RedisClient{Username: "admin"}
+	connected, err := client.Connect(host, port, "secret")
+	require.Nil(t, err)
+	require.True(t, connected)
+
+	_, err = client.Connect(host, port, "wrong")
+	require.ErrorContains(t, err, "WRONGPASS")
+
+	info, err := client.GetServerInfo(host, port, "secret")
+	require.Nil(t, err)
+	require.Equal(t, "# Server\r\nversion:7\r\n", info)
+
+	reply, err := client.RunCommand(host, port, "secret", "CONFIG", "GET", "dir")
+	require.Nil(t, err)
+	require.Equal(t, []interface{}{"dir", "/tmp"}, reply)
+
+	reply, err = client.RunCommand(host, port, "secret", "DBSIZE")
+	require.Nil(t, err)
+	require.Equal(t, int64(42), reply)
+
+	reply, err = client.RunCommand(host, port, "secret", "GET", "missing")
+	require.Nil(t, err)
+	require.Nil(t, reply)
+
+	_, err = client.RunCommand(host, port, "secret", "FLUSHALL")
+	require.EqualError(t, err, "ERR unknown command")
+
+	_, err = client.RunCommand(host, port, "secret")
+	require.EqualError(t, err, "empty command")
+}
+
+func TestRedisClientTLS(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}})
+	require.Nil(t, err)
+	defer listener.Close()
+	host, port := listenRedis(t, listener)
+
+	client := &RedisClient{Username: "admin", TLS: true}
+	_, err = client.Connect(host, port, "secret")
+	require.NotNil(t, err, "the self signed certificate should not be trusted")
+
+	client.InsecureSkipVerify = true
+	connected, err := client.Connect(host, port, "secret")
+	require.Nil(t, err)
+	require.True(t, connected)
+
+	client = &RedisClient{Username: "admin", TLS: true, RootCA: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+	connected, err = client.Connect(host, port, "secret")
+	require.Nil(t, err)
+	require.True(t, connected)
+
+	client.RootCA = "invalid"
+	_, err = client.Connect(host, port, "secret")
+	require.EqualError(t, err, "could not parse root ca")
+}
@@ -0,0 +1,228 @@
+package smb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hirochachacha/go-smb2"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// maxDepth is the maximum depth of the listed directories
+	maxDepth = 10
+	// maxFiles is the maximum number of listed files
+	maxFiles = 1000
+	// maxFileSize is the maximum number of bytes read from a file
+	maxFileSize = 1024 * 1024
+)
+
+// Share is a share of a SMB server.
+type Share struct {
+	Name string
+	// Readable is true if the files of the share can be listed
+	Readable bool
+}
+
+// File is a file or a directory of a share.
+type File struct {
+	Share string
+	// Path is the path of the file in the share, ex: dir/file.txt
+	Path  string
+	Name  string
+	Size  int64
+	IsDir bool
+	// ModTime is the modification time in the RFC3339 format
+	ModTime string
+}
+
+// ListSharesNullSession tries to connect to provided host and port
+// with a null session and list the shares along with their readability.
+func (c *SMBClient) ListSharesNullSession(host string, port int) ([]Share, error) {
+	return c.EnumerateShares(host, port, "", "")
+}
+
+// EnumerateShares tries to connect to provided host and port
+// with given credentials and list the shares along with their readability.
+//
+// A null session is used if the credentials are blank.
+func (c *SMBClient) EnumerateShares(host string, port int, user, password string) ([]Share, error) {
+	session, err := dialSession(host, port, user, password)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = session.Logoff()
+	}()
+
+	names, err := session.ListSharenames()
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]Share, 0, len(names))
+	for _, name := range names {
+		share := Share{Name: name}
+		if mounted, err := session.Mount(name); err == nil {
+			_, err = fs.ReadDir(mounted.DirFS(""), ".")
+			share.Readable = err == nil
+			_ = mounted.Umount()
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+// ListFiles tries to connect to provided host and port with given
+// credentials and list the files of the directory of the share.
+//
+// The subdirectories are listed up to depth levels, the files of the
+// directory only are listed if depth is 0. At most 1000 files are listed.
+// A null session is used if the credentials are blank.
+func (c *SMBClient) ListFiles(host string, port int, user, password, share, dir string, depth int) ([]File, error) {
+	session, err := dialSession(host, port, user, password)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = session.Logoff()
+	}()
+
+	mounted, err := session.Mount(share)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = mounted.Umount()
+	}()
+	return listFiles(mounted.DirFS(""), share, cleanPath(dir), depth)
+}
+
+// ReadFile tries to connect to provided host and port with given
+// credentials and read the file of the share.
+//
+// At most size bytes are read, the size is capped to 1MB and
+// defaults to it if 0. A null session is used if the credentials are blank.
+func (c *SMBClient) ReadFile(host string, port int, user, password, share, name string, size int) (string, error) {
+	session, err := dialSession(host, port, user, password)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = session.Logoff()
+	}()
+
+	mounted, err := session.Mount(share)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = mounted.Umount()
+	}()
+	return readFile(mounted.DirFS(""), cleanPath(name), size)
+}
+
+// dialSession returns a session of the server authenticated with the credentials
+func dialSession(host string, port int, user, password string) (*smb2.Session, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	d := &smb2.Dialer{
+		Initiator: &smb2.NTLMInitiator{
+			User:     user,
+			Password: password,
+		},
+	}
+	session, err := d.Dial(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return session, nil
+}
+
+// cleanPath returns the slash separated path of a share path, ex: \dir\file.txt returns dir/file.txt
+func cleanPath(name string) string {
+	name = strings.ReplaceAll(name, `\`, "/")
+	return path.Clean("/" + name)[1:]
+}
+
+// listFiles lists the files of the directory of fsys up to depth levels
+func listFiles(fsys fs.FS, share, dir string, depth int) ([]File, error) {
+	if dir == "" {
+		dir = "."
+	}
+	if depth > maxDepth {
+		depth = maxDepth
+	}
+	files := []File{}
+	var walk func(dir string, depth int) error
+	walk = func(dir string, depth int) error {
+		entries, err := fs.ReadDir(fsys, dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if len(files) >= maxFiles {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			file := File{
+				Share:   share,
+				Path:    path.Join(dir, entry.Name()),
+				Name:    entry.Name(),
+				Size:    info.Size(),
+				IsDir:   entry.IsDir(),
+				ModTime: info.ModTime().UTC().Format(time.RFC3339),
+			}
+			files = append(files, file)
+			if file.IsDir && depth > 0 {
+				// the unreadable subdirectories are skipped
+				_ = walk(file.Path, depth-1)
+			}
+		}
+		return nil
+	}
+	if err := walk(dir, depth); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// readFile reads at most size bytes of the file of fsys
+func readFile(fsys fs.FS, name string, size int) (string, error) {
+	if size <= 0 || size > maxFileSize {
+		size = maxFileSize
+	}
+	file, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, int64(size)))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,61 @@
+package smb
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanPath(t *testing.T) {
+	require.Equal(t, "", cleanPath(""))
+	require.Equal(t, "", cleanPath(`\`))
+	require.Equal(t, "dir/file.txt", cleanPath(`\dir\file.txt`))
+	require.Equal(t, "file.txt", cleanPath("../dir/../file.txt"))
+}
+
+func TestListFiles(t *testing.T) {
+	modTime := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"backup.zip":           {Data: []byte("zip"), ModTime: modTime},
+		"config/web.config":    {Data: []byte("<configuration/>"), ModTime: modTime},
+		"config/old/app.ini":   {Data: []byte("[app]"), ModTime: modTime},
+		"config/old/deep/x.db": {Data: []byte("x"), ModTime: modTime},
+	}
+
+	files, err := listFiles(fsys, "data", "", 0)
+	require.Nil(t, err)
+	require.Equal(t, []File{
+		{Share: "data", Path: "backup.zip", Name: "backup.zip", Size: 3, ModTime: "2023-10-01T12:00:00Z"},
+		{Share: "data", Path: "config", Name: "config", IsDir: true, ModTime: "0001-01-01T00:00:00Z"},
+	}, files)
+
+	files, err = listFiles(fsys, "data", "config", 1)
+	require.Nil(t, err)
+	paths := []string{}
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	require.Equal(t, []string{"config/old", "config/old/app.ini", "config/old/deep", "config/web.config"}, paths)
+
+	_, err = listFiles(fsys, "data", "missing", 0)
+	require.NotNil(t, err)
+}
+
+func TestReadFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"web.config": {Data: []byte("<configuration/>")},
+	}
+
+	data, err := readFile(fsys, "web.config", 0)
+	require.Nil(t, err)
+	require.Equal(t, "<configuration/>", data)
+
+	data, err = readFile(fsys, "web.config", 5)
+	require.Nil(t, err)
+	require.Equal(t, "<conf", data)
+
+	_, err = readFile(fsys, "missing", 0)
+	require.NotNil(t, err)
+}
@@ -3,10 +3,8 @@ package smb
 import (
 	"context"
 	"fmt"
-	"net"
 	"time"
 
-	"github.com/hirochachacha/go-smb2"
 	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/zmap/zgrab2/lib/smb/smb"
@@ -36,7 +34,9 @@ func (c *SMBClient) ConnectSMBInfoMode(host string, port int) (*smb.SMBLog, erro
 	result, err := smb.GetSMBLog(conn, setupSession, false, false)
 	if err != nil {
 		conn.Close()
-		conn, err = net.DialTimeout("tcp", fmt.Sprintf("%s:%d", host, port), 10*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		conn, err = protocolstate.Dialer.Dial(ctx, "tcp", fmt.Sprintf("%s:%d", host, port))
 		if err != nil {
 			return nil, err
 		}
@@ -64,22 +64,10 @@ func (c *SMBClient) ListSMBv2Metadata(host string, port int) (*plugins.ServiceSM
 // ListShares tries to connect to provided host and port
 // and list shares by using given credentials.
 //
-// Credentials cannot be blank. guest or anonymous credentials
-// can be used by providing empty password.
+// guest or anonymous credentials can be used by providing empty
+// password, a null session is used if the credentials are blank.
 func (c *SMBClient) ListShares(host string, port int, user, password string) ([]string, error) {
-	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", host, port))
-	if err != nil {
-		return nil, err
-	}
-	defer conn.Close()
-
-	d := &smb2.Dialer{
-		Initiator: &smb2.NTLMInitiator{
-			User:     user,
-			Password: password,
-		},
-	}
-	s, err := d.Dial(conn)
+	s, err := dialSession(host, port, user, password)
 	if err != nil {
 		return nil, err
 	}
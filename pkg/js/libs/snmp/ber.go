@@ -0,0 +1,315 @@
+package snmp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// BER tags of the SNMP types
+const (
+	tagInteger        = 0x02
+	tagOctetString    = 0x04
+	tagNull           = 0x05
+	tagOID            = 0x06
+	tagSequence       = 0x30
+	tagIPAddress      = 0x40
+	tagCounter32      = 0x41
+	tagGauge32        = 0x42
+	tagTimeTicks      = 0x43
+	tagOpaque         = 0x44
+	tagCounter64      = 0x46
+	tagNoSuchObject   = 0x80
+	tagNoSuchInstance = 0x81
+	tagEndOfMibView   = 0x82
+)
+
+// oid is an object identifier
+type oid []uint32
+
+// parseOID parses a dotted object identifier, the leading dot is optional
+func parseOID(value string) (oid, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(value), "."), ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid oid %q", value)
+	}
+	parsed := make(oid, len(parts))
+	for i, part := range parts {
+		number, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oid %q", value)
+		}
+		parsed[i] = uint32(number)
+	}
+	if parsed[0] > 2 || (parsed[0] < 2 && parsed[1] >= 40) {
+		return nil, fmt.Errorf("invalid oid %q", value)
+	}
+	return parsed, nil
+}
+
+func (o oid) String() string {
+	parts := make([]string, len(o))
+	for i, number := range o {
+		parts[i] = strconv.FormatUint(uint64(number), 10)
+	}
+	return strings.Join(parts, ".")
+}
+
+// hasPrefix returns true if the oid is in the subtree of the root
+func (o oid) hasPrefix(root oid) bool {
+	if len(o) < len(root) {
+		return false
+	}
+	for i := range root {
+		if o[i] != root[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// after returns true if the oid follows the other in lexicographic order
+func (o oid) after(other oid) bool {
+	for i := 0; i < len(o) && i < len(other); i++ {
+		if o[i] != other[i] {
+			return o[i] > other[i]
+		}
+	}
+	return len(o) > len(other)
+}
+
+func appendLength(buf []byte, length int) []byte {
+	if length < 0x80 {
+		return append(buf, byte(length))
+	}
+	var encoded []byte
+	for ; length > 0; length >>= 8 {
+		encoded = append([]byte{byte(length)}, encoded...)
+	}
+	return append(append(buf, 0x80|byte(len(encoded))), encoded...)
+}
+
+func appendTLV(buf []byte, tag byte, value []byte) []byte {
+	return append(appendLength(append(buf, tag), len(value)), value...)
+}
+
+// appendInteger appends the minimal two's complement encoding of the value
+func appendInteger(buf []byte, tag byte, value int64) []byte {
+	encoded := binary.BigEndian.AppendUint64(nil, uint64(value))
+	for len(encoded) > 1 && ((encoded[0] == 0x00 && encoded[1]&0x80 == 0) || (encoded[0] == 0xff && encoded[1]&0x80 != 0)) {
+		encoded = encoded[1:]
+	}
+	return appendTLV(buf, tag, encoded)
+}
+
+func appendOctetString(buf []byte, value []byte) []byte {
+	return appendTLV(buf, tagOctetString, value)
+}
+
+func appendOID(buf []byte, value oid) []byte {
+	var encoded []byte
+	encoded = appendBase128(encoded, value[0]*40+value[1])
+	for _, number := range value[2:] {
+		encoded = appendBase128(encoded, number)
+	}
+	return appendTLV(buf, tagOID, encoded)
+}
+
+func appendBase128(buf []byte, value uint32) []byte {
+	var encoded []byte
+	encoded = append(encoded, byte(value&0x7f))
+	for value >>= 7; value > 0; value >>= 7 {
+		encoded = append([]byte{0x80 | byte(value&0x7f)}, encoded...)
+	}
+	return append(buf, encoded...)
+}
+
+// reader reads the BER elements of a message panicking on malformed data
+type reader struct {
+	data []byte
+}
+
+type decodeError struct {
+	message string
+}
+
+func (e *decodeError) Error() string {
+	return e.message
+}
+
+// decode runs the function and returns its decode error
+func decode(fn func()) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			value, ok := recovered.(*decodeError)
+			if !ok {
+				panic(recovered)
+			}
+			err = errors.Wrap(value, "could not decode message")
+		}
+	}()
+	fn()
+	return nil
+}
+
+func fail(format string, args ...interface{}) {
+	panic(&decodeError{message: fmt.Sprintf(format, args...)})
+}
+
+func (r *reader) empty() bool {
+	return len(r.data) == 0
+}
+
+// next returns the tag and the value of the next element
+func (r *reader) next() (byte, []byte) {
+	if len(r.data) < 2 {
+		fail("unexpected end of message")
+	}
+	tag, length, pos := r.data[0], int(r.data[1]), 2
+	if tag&0x1f == 0x1f {
+		fail("unsupported multi-byte tag")
+	}
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 || size > 4 || len(r.data) < pos+size {
+			fail("invalid length")
+		}
+		length = 0
+		for _, b := range r.data[pos : pos+size] {
+			length = length<<8 | int(b)
+		}
+		pos += size
+	}
+	if length < 0 || len(r.data)-pos < length {
+		fail("unexpected end of message")
+	}
+	value := r.data[pos : pos+length]
+	r.data = r.data[pos+length:]
+	return tag, value
+}
+
+// expect returns the value of the next element which must have the tag
+func (r *reader) expect(tag byte) []byte {
+	actual, value := r.next()
+	if actual != tag {
+		fail("unexpected tag 0x%02x instead of 0x%02x", actual, tag)
+	}
+	return value
+}
+
+func (r *reader) sequence() *reader {
+	return &reader{data: r.expect(tagSequence)}
+}
+
+func (r *reader) integer() int64 {
+	return decodeInteger(r.expect(tagInteger))
+}
+
+func (r *reader) octetString() []byte {
+	return r.expect(tagOctetString)
+}
+
+func decodeInteger(value []byte) int64 {
+	if len(value) == 0 || len(value) > 8 {
+		fail("invalid integer of %d bytes", len(value))
+	}
+	result := int64(int8(value[0]))
+	for _, b := range value[1:] {
+		result = result<<8 | int64(b)
+	}
+	return result
+}
+
+// decodeUnsigned decodes the counters, gauges and time ticks
+func decodeUnsigned(value []byte) uint64 {
+	if len(value) == 9 && value[0] == 0 {
+		value = value[1:]
+	}
+	if len(value) == 0 || len(value) > 8 {
+		fail("invalid unsigned integer of %d bytes", len(value))
+	}
+	var result uint64
+	for _, b := range value {
+		result = result<<8 | uint64(b)
+	}
+	return result
+}
+
+func decodeOID(value []byte) oid {
+	var numbers []uint64
+	var current uint64
+	for i, b := range value {
+		current = current<<7 | uint64(b&0x7f)
+		if current > 0xffffffff {
+			fail("invalid oid component")
+		}
+		if b&0x80 == 0 {
+			numbers = append(numbers, current)
+			current = 0
+		} else if i == len(value)-1 {
+			fail("truncated oid")
+		}
+	}
+	if len(numbers) == 0 {
+		fail("empty oid")
+	}
+	decoded := make(oid, 0, len(numbers)+1)
+	switch first := numbers[0]; {
+	case first < 40:
+		decoded = append(decoded, 0, uint32(first))
+	case first < 80:
+		decoded = append(decoded, 1, uint32(first-40))
+	default:
+		decoded = append(decoded, 2, uint32(first-80))
+	}
+	for _, number := range numbers[1:] {
+		decoded = append(decoded, uint32(number))
+	}
+	return decoded
+}
+
+// decodeValue converts the value of a variable binding to a json value,
+// printable octet strings are returned as strings and the others as 0x prefixed hex
+func decodeValue(tag byte, value []byte) interface{} {
+	switch tag {
+	case tagInteger:
+		return decodeInteger(value)
+	case tagOctetString:
+		if isPrintable(value) {
+			return string(value)
+		}
+		return "0x" + hex.EncodeToString(value)
+	case tagNull:
+		return nil
+	case tagOID:
+		return decodeOID(value).String()
+	case tagIPAddress:
+		if len(value) != net.IPv4len && len(value) != net.IPv6len {
+			fail("invalid ip address of %d bytes", len(value))
+		}
+		return net.IP(value).String()
+	case tagCounter32, tagGauge32, tagTimeTicks, tagCounter64:
+		return decodeUnsigned(value)
+	default:
+		return "0x" + hex.EncodeToString(value)
+	}
+}
+
+func isPrintable(value []byte) bool {
+	if !utf8.Valid(value) {
+		return false
+	}
+	for _, r := range string(value) {
+		if !unicode.IsPrint(r) && r != '\r' && r != '\n' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
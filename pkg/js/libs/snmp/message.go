@@ -0,0 +1,234 @@
+package snmp
+
+// SNMP versions as encoded in the messages
+const (
+	version1  = 0
+	version2c = 1
+	version3  = 3
+)
+
+// PDU tags
+const (
+	pduGetRequest     = 0xa0
+	pduGetNextRequest = 0xa1
+	pduResponse       = 0xa2
+	pduGetBulkRequest = 0xa5
+	pduReport         = 0xa8
+)
+
+// v3 message flags
+const (
+	flagAuth       = 0x01
+	flagPriv       = 0x02
+	flagReportable = 0x04
+)
+
+const (
+	// securityModelUSM is the user-based security model of the v3 messages
+	securityModelUSM = 3
+	// maxMessageSize is the maximum size of the messages
+	maxMessageSize = 65507
+)
+
+// errorStatuses are the names of the error statuses of the responses
+var errorStatuses = []string{
+	"noError", "tooBig", "noSuchName", "badValue", "readOnly", "genErr", "noAccess", "wrongType",
+	"wrongLength", "wrongEncoding", "wrongValue", "noCreation", "inconsistentValue",
+	"resourceUnavailable", "commitFailed", "undoFailed", "authorizationError", "notWritable", "inconsistentName",
+}
+
+// errorNoSuchName is the error status of the v1 responses for missing variables
+const errorNoSuchName = 2
+
+// varbind is a variable binding of a pdu, the value is
+// encoded and null in the requests
+type varbind struct {
+	oid   oid
+	tag   byte
+	value []byte
+}
+
+// pdu is a protocol data unit, the error status and index are
+// the non repeaters and max repetitions of the bulk requests
+type pdu struct {
+	tag         byte
+	requestID   int32
+	errorStatus int64
+	errorIndex  int64
+	varbinds    []varbind
+}
+
+// newPDU returns a request pdu for the oids
+func newPDU(tag byte, oids ...oid) pdu {
+	p := pdu{tag: tag}
+	for _, value := range oids {
+		p.varbinds = append(p.varbinds, varbind{oid: value, tag: tagNull})
+	}
+	return p
+}
+
+func (p *pdu) marshal() []byte {
+	var varbinds []byte
+	for _, v := range p.varbinds {
+		varbinds = appendTLV(varbinds, tagSequence, appendTLV(appendOID(nil, v.oid), v.tag, v.value))
+	}
+	body := appendInteger(nil, tagInteger, int64(p.requestID))
+	body = appendInteger(body, tagInteger, p.errorStatus)
+	body = appendInteger(body, tagInteger, p.errorIndex)
+	body = appendTLV(body, tagSequence, varbinds)
+	return appendTLV(nil, p.tag, body)
+}
+
+func parsePDU(r *reader) pdu {
+	tag, value := r.next()
+	if tag&0xe0 != 0xa0 {
+		fail("unexpected pdu tag 0x%02x", tag)
+	}
+	body := &reader{data: value}
+	p := pdu{tag: tag}
+	p.requestID = int32(body.integer())
+	p.errorStatus = body.integer()
+	p.errorIndex = body.integer()
+	list := body.sequence()
+	for !list.empty() {
+		v := list.sequence()
+		binding := varbind{oid: decodeOID(v.expect(tagOID))}
+		binding.tag, binding.value = v.next()
+		p.varbinds = append(p.varbinds, binding)
+	}
+	return p
+}
+
+// statusError returns the error of the error status of a response
+func (p *pdu) statusError() string {
+	if p.errorStatus >= 0 && p.errorStatus < int64(len(errorStatuses)) {
+		return errorStatuses[p.errorStatus]
+	}
+	return "unknown error"
+}
+
+// marshalCommunity returns a v1 or v2c message
+func marshalCommunity(version int, community string, p pdu) []byte {
+	body := appendInteger(nil, tagInteger, int64(version))
+	body = appendOctetString(body, []byte(community))
+	body = append(body, p.marshal()...)
+	return appendTLV(nil, tagSequence, body)
+}
+
+// unmarshalCommunity parses a v1 or v2c message
+func unmarshalCommunity(data []byte) (p pdu, err error) {
+	err = decode(func() {
+		msg := (&reader{data: data}).sequence()
+		if version := msg.integer(); version != version1 && version != version2c {
+			fail("unexpected version %d", version)
+		}
+		msg.octetString()
+		p = parsePDU(msg)
+	})
+	return p, err
+}
+
+// securityParameters are the user-based security parameters of a v3 message
+type securityParameters struct {
+	engineID    []byte
+	engineBoots int64
+	engineTime  int64
+	userName    string
+	authParams  []byte
+	privParams  []byte
+}
+
+// marshal returns the encoded parameters and the offset of the authentication parameters
+func (s *securityParameters) marshal() ([]byte, int) {
+	body := appendOctetString(nil, s.engineID)
+	body = appendInteger(body, tagInteger, s.engineBoots)
+	body = appendInteger(body, tagInteger, s.engineTime)
+	body = appendOctetString(body, []byte(s.userName))
+	body = appendOctetString(body, s.authParams)
+	offset := len(body) - len(s.authParams)
+	body = appendOctetString(body, s.privParams)
+	data := appendTLV(nil, tagSequence, body)
+	return data, offset + len(data) - len(body)
+}
+
+// messageV3 is a v3 message, the data is the encoded scoped pdu
+// or the encrypted one if the privacy flag is set
+type messageV3 struct {
+	msgID    int32
+	flags    byte
+	security securityParameters
+	data     []byte
+}
+
+// marshal returns the encoded message and the offset of the authentication parameters
+func (m *messageV3) marshal() ([]byte, int) {
+	header := appendInteger(nil, tagInteger, int64(m.msgID))
+	header = appendInteger(header, tagInteger, maxMessageSize)
+	header = appendOctetString(header, []byte{m.flags})
+	header = appendInteger(header, tagInteger, securityModelUSM)
+
+	body := appendInteger(nil, tagInteger, version3)
+	body = appendTLV(body, tagSequence, header)
+	security, offset := m.security.marshal()
+	body = appendOctetString(body, security)
+	offset += len(body) - len(security)
+	if m.flags&flagPriv != 0 {
+		body = appendOctetString(body, m.data)
+	} else {
+		body = append(body, m.data...)
+	}
+	data := appendTLV(nil, tagSequence, body)
+	return data, offset + len(data) - len(body)
+}
+
+func unmarshalV3(data []byte) (m messageV3, err error) {
+	err = decode(func() {
+		msg := (&reader{data: data}).sequence()
+		if version := msg.integer(); version != version3 {
+			fail("unexpected version %d", version)
+		}
+		header := msg.sequence()
+		m.msgID = int32(header.integer())
+		header.integer()
+		flags := header.octetString()
+		if len(flags) != 1 {
+			fail("invalid message flags")
+		}
+		m.flags = flags[0]
+		if model := header.integer(); model != securityModelUSM {
+			fail("unsupported security model %d", model)
+		}
+		security := (&reader{data: msg.octetString()}).sequence()
+		m.security.engineID = security.octetString()
+		m.security.engineBoots = security.integer()
+		m.security.engineTime = security.integer()
+		m.security.userName = string(security.octetString())
+		m.security.authParams = security.octetString()
+		m.security.privParams = security.octetString()
+		if m.flags&flagPriv != 0 {
+			m.data = msg.octetString()
+		} else {
+			m.data = msg.data
+		}
+	})
+	return m, err
+}
+
+// marshalScopedPDU returns the scoped pdu of a v3 message
+func marshalScopedPDU(contextEngineID []byte, contextName string, p pdu) []byte {
+	body := appendOctetString(nil, contextEngineID)
+	body = appendOctetString(body, []byte(contextName))
+	body = append(body, p.marshal()...)
+	return appendTLV(nil, tagSequence, body)
+}
+
+// parseScopedPDU parses a scoped pdu, the trailing padding of the decrypted ones is ignored
+func parseScopedPDU(data []byte) (p pdu, err error) {
+	err = decode(func() {
+		scoped := (&reader{data: data}).sequence()
+		scoped.octetString()
+		scoped.octetString()
+		p = parsePDU(scoped)
+	})
+	return p, err
+}
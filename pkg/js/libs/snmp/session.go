@@ -0,0 +1,322 @@
+package snmp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// timeout is the timeout of a request attempt
+var timeout = 5 * time.Second
+
+// retries is the number of times a request is sent again without response
+const retries = 1
+
+// errNoResponse is returned when the agent does not respond to a request
+var errNoResponse = errors.New("no response from agent")
+
+// reports are the reasons of the usm statistics reported by the agents
+var reports = map[string]string{
+	"1.3.6.1.6.3.15.1.1.1.0": "unsupported security level",
+	"1.3.6.1.6.3.15.1.1.2.0": "not in time window",
+	"1.3.6.1.6.3.15.1.1.3.0": "unknown user name",
+	"1.3.6.1.6.3.15.1.1.4.0": "unknown engine id",
+	"1.3.6.1.6.3.15.1.1.5.0": "wrong digest",
+	"1.3.6.1.6.3.15.1.1.6.0": "decryption error",
+}
+
+// notInTimeWindow is the reported oid of the messages with an outdated engine time
+const notInTimeWindow = "1.3.6.1.6.3.15.1.1.2.0"
+
+// session sends the requests of a client to an agent
+type session struct {
+	conn      net.Conn
+	version   int
+	community string
+	requestID int32
+
+	// v3 parameters with the keys localized for the engine
+	userName     string
+	contextName  string
+	flags        byte
+	auth         authProtocol
+	authPassword string
+	authKey      []byte
+	privProtocol string
+	privPassword string
+	privKey      []byte
+	salt         uint64
+
+	// engine is the authoritative engine discovered at the start of the session,
+	// its time is synchronized at the local time of the last response
+	engine     securityParameters
+	engineSync time.Time
+}
+
+// newSession validates the options of the client and dials the agent,
+// the engine of the v3 agents is discovered
+func newSession(c *SNMPClient, host string, port int) (*session, error) {
+	s := &session{requestID: rand.Int31(), salt: rand.Uint64()}
+	switch c.Version {
+	case "1":
+		s.version = version1
+	case "", "2c":
+		s.version = version2c
+	case "3":
+		s.version = version3
+	default:
+		return nil, fmt.Errorf("unsupported version %s", c.Version)
+	}
+	s.community = c.Community
+	if s.community == "" {
+		s.community = "public"
+	}
+	if s.version == version3 {
+		if err := s.setSecurity(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	s.conn = conn
+
+	if s.version == version3 {
+		if err := s.discover(); err != nil {
+			s.close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// setSecurity sets the security level of the v3 options
+func (s *session) setSecurity(c *SNMPClient) error {
+	if c.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	s.userName = c.Username
+	s.contextName = c.ContextName
+	if c.AuthProtocol != "" {
+		auth, ok := authProtocols[strings.ToUpper(c.AuthProtocol)]
+		if !ok {
+			return fmt.Errorf("unsupported authentication protocol %s", c.AuthProtocol)
+		}
+		if c.AuthPassword == "" {
+			return fmt.Errorf("authentication password is required")
+		}
+		s.flags |= flagAuth
+		s.auth, s.authPassword = auth, c.AuthPassword
+	}
+	if c.PrivProtocol != "" {
+		protocol := strings.ToUpper(c.PrivProtocol)
+		if protocol == "AES128" {
+			protocol = privAES
+		}
+		if protocol != privDES && protocol != privAES {
+			return fmt.Errorf("unsupported privacy protocol %s", c.PrivProtocol)
+		}
+		if s.flags&flagAuth == 0 {
+			return fmt.Errorf("privacy requires an authentication protocol")
+		}
+		if c.PrivPassword == "" {
+			return fmt.Errorf("privacy password is required")
+		}
+		s.flags |= flagPriv
+		s.privProtocol, s.privPassword = protocol, c.PrivPassword
+	}
+	return nil
+}
+
+func (s *session) close() {
+	_ = s.conn.Close()
+}
+
+func (s *session) nextRequestID() int32 {
+	s.requestID = (s.requestID + 1) & 0x7fffffff
+	return s.requestID
+}
+
+// request sends the pdu and returns the response
+func (s *session) request(p pdu) (pdu, error) {
+	if s.version == version3 {
+		return s.requestV3(p)
+	}
+	p.requestID = s.nextRequestID()
+	var response pdu
+	err := s.exchange(marshalCommunity(s.version, s.community, p), func(data []byte) (bool, error) {
+		parsed, err := unmarshalCommunity(data)
+		if err != nil || parsed.requestID != p.requestID {
+			return false, err
+		}
+		response = parsed
+		return true, nil
+	})
+	return response, err
+}
+
+// requestV3 sends the pdu with the security level of the session,
+// the request is sent again once if the engine time was outdated
+func (s *session) requestV3(p pdu) (pdu, error) {
+	for attempt := 0; ; attempt++ {
+		p.requestID = s.nextRequestID()
+		response, err := s.sendV3(p)
+		if err != nil {
+			return pdu{}, err
+		}
+		if response.tag != pduReport {
+			return response, nil
+		}
+		reported := ""
+		if len(response.varbinds) > 0 {
+			reported = response.varbinds[0].oid.String()
+		}
+		if reported == notInTimeWindow && attempt == 0 {
+			continue
+		}
+		if reason, ok := reports[reported]; ok {
+			return pdu{}, fmt.Errorf("agent reported %s", reason)
+		}
+		return pdu{}, fmt.Errorf("agent reported %s", reported)
+	}
+}
+
+func (s *session) sendV3(p pdu) (pdu, error) {
+	msg := messageV3{
+		msgID: p.requestID,
+		flags: s.flags | flagReportable,
+		security: securityParameters{
+			engineID:    s.engine.engineID,
+			engineBoots: s.engine.engineBoots,
+			engineTime:  s.engineTime(),
+			userName:    s.userName,
+		},
+		data: marshalScopedPDU(s.engine.engineID, s.contextName, p),
+	}
+	if s.flags&flagPriv != 0 {
+		s.salt++
+		ciphertext, privParams, err := encrypt(s.privProtocol, s.privKey, msg.security.engineBoots, msg.security.engineTime, s.salt, msg.data)
+		if err != nil {
+			return pdu{}, err
+		}
+		msg.data, msg.security.privParams = ciphertext, privParams
+	}
+	if s.flags&flagAuth != 0 {
+		msg.security.authParams = make([]byte, s.auth.truncation)
+	}
+	data, offset := msg.marshal()
+	if s.flags&flagAuth != 0 {
+		copy(data[offset:], s.auth.sign(s.authKey, data))
+	}
+
+	var response pdu
+	err := s.exchange(data, func(data []byte) (bool, error) {
+		parsed, err := unmarshalV3(data)
+		if err != nil || parsed.msgID != msg.msgID {
+			return false, err
+		}
+		if parsed.security.engineBoots != 0 || parsed.security.engineTime != 0 {
+			s.setEngineTime(parsed.security)
+		}
+		scoped := parsed.data
+		if parsed.flags&flagPriv != 0 {
+			if scoped, err = decrypt(s.privProtocol, s.privKey, parsed.security.engineBoots, parsed.security.engineTime, parsed.security.privParams, parsed.data); err != nil {
+				return false, err
+			}
+		}
+		response, err = parseScopedPDU(scoped)
+		return true, err
+	})
+	return response, err
+}
+
+// discover discovers the engine of the agent with an unauthenticated
+// request and localizes the keys for the engine
+func (s *session) discover() error {
+	msg := messageV3{
+		msgID: s.nextRequestID(),
+		flags: flagReportable,
+		data:  marshalScopedPDU(nil, "", newPDU(pduGetRequest)),
+	}
+	data, _ := msg.marshal()
+	err := s.exchange(data, func(data []byte) (bool, error) {
+		parsed, err := unmarshalV3(data)
+		if err != nil || parsed.msgID != msg.msgID {
+			return false, err
+		}
+		s.engine.engineID = parsed.security.engineID
+		s.setEngineTime(parsed.security)
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(s.engine.engineID) == 0 {
+		return fmt.Errorf("could not discover engine id")
+	}
+	if s.flags&flagAuth != 0 {
+		s.authKey = s.auth.localizeKey(s.authPassword, s.engine.engineID)
+	}
+	if s.flags&flagPriv != 0 {
+		s.privKey = s.auth.localizeKey(s.privPassword, s.engine.engineID)
+	}
+	return nil
+}
+
+func (s *session) setEngineTime(params securityParameters) {
+	s.engine.engineBoots = params.engineBoots
+	s.engine.engineTime = params.engineTime
+	s.engineSync = time.Now()
+}
+
+// engineTime returns the estimated time of the engine
+func (s *session) engineTime() int64 {
+	return s.engine.engineTime + int64(time.Since(s.engineSync)/time.Second)
+}
+
+// exchange sends the message until the handler accepts a response or the retries are exhausted
+func (s *session) exchange(data []byte, handle func([]byte) (bool, error)) error {
+	for attempt := 0; attempt <= retries; attempt++ {
+		if _, err := s.conn.Write(data); err != nil {
+			return err
+		}
+		err := s.read(time.Now().Add(timeout), handle)
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			return err
+		}
+	}
+	return errNoResponse
+}
+
+// read calls the handler with the received messages until it accepts one or the deadline is exceeded
+func (s *session) read(deadline time.Time, handle func([]byte) (bool, error)) error {
+	_ = s.conn.SetReadDeadline(deadline)
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, err := s.conn.Read(buf)
+		if err != nil {
+			return err
+		}
+		done, err := handle(buf[:n])
+		if done || err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package snmp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	// maxRepetitions is the number of variables requested by the bulk requests of a walk
+	maxRepetitions = 25
+	// maxWalkResults is the maximum number of variables returned by a walk
+	maxWalkResults = 10000
+)
+
+// sysDescr is the oid requested to check the communities
+var sysDescr = oid{1, 3, 6, 1, 2, 1, 1, 1, 0}
+
+// SNMPClient is a client for SNMP agents.
+//
+// The v1 and v2c requests are authenticated with the community and
+// the v3 ones with the user-based security model, internally client
+// implements the protocol over udp.
+type SNMPClient struct {
+	// Version is the version of the requests, either 1, 2c or 3, 2c by default
+	Version string
+	// Community is the community of the v1 and v2c requests, public by default
+	Community string
+	// Username is the user name of the v3 requests
+	Username string
+	// AuthProtocol is the v3 authentication protocol, either MD5, SHA, SHA224, SHA256, SHA384 or SHA512,
+	// the requests are not authenticated if empty
+	AuthProtocol string
+	// AuthPassword is the v3 authentication password
+	AuthPassword string
+	// PrivProtocol is the v3 privacy protocol, either DES or AES,
+	// the requests are not encrypted if empty
+	PrivProtocol string
+	// PrivPassword is the v3 privacy password
+	PrivPassword string
+	// ContextName is the v3 context name, empty by default
+	ContextName string
+}
+
+// Get returns the values of the oids as a json object of the oids to their values.
+//
+// The variables missing on the agent are not returned, printable octet
+// strings are returned as strings and the other ones as 0x prefixed hex.
+func (c *SNMPClient) Get(host string, port int, oids ...string) (string, error) {
+	if len(oids) == 0 {
+		return "", fmt.Errorf("no oid to get")
+	}
+	parsed := make([]oid, 0, len(oids))
+	for _, value := range oids {
+		o, err := parseOID(value)
+		if err != nil {
+			return "", err
+		}
+		parsed = append(parsed, o)
+	}
+
+	s, err := newSession(c, host, port)
+	if err != nil {
+		return "", err
+	}
+	defer s.close()
+
+	response, err := s.request(newPDU(pduGetRequest, parsed...))
+	if err != nil {
+		return "", err
+	}
+	values := make(map[string]interface{})
+	if response.errorStatus == errorNoSuchName && s.version == version1 {
+		return marshalValues(values)
+	}
+	if response.errorStatus != 0 {
+		return "", fmt.Errorf("agent returned %s error", response.statusError())
+	}
+	err = decode(func() {
+		for _, v := range response.varbinds {
+			if v.tag == tagNoSuchObject || v.tag == tagNoSuchInstance || v.tag == tagEndOfMibView {
+				continue
+			}
+			values[v.oid.String()] = decodeValue(v.tag, v.value)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return marshalValues(values)
+}
+
+// Walk returns the values of the subtree of the oid as a json object of the oids to their values.
+//
+// The v1 walks use get next requests and the other ones bulk requests,
+// at most 10000 variables are returned.
+func (c *SNMPClient) Walk(host string, port int, root string) (string, error) {
+	rootOID, err := parseOID(root)
+	if err != nil {
+		return "", err
+	}
+
+	s, err := newSession(c, host, port)
+	if err != nil {
+		return "", err
+	}
+	defer s.close()
+
+	values := make(map[string]interface{})
+	current := rootOID
+	for done := false; !done && len(values) < maxWalkResults; {
+		request := newPDU(pduGetNextRequest, current)
+		if s.version != version1 {
+			request.tag, request.errorIndex = pduGetBulkRequest, maxRepetitions
+		}
+		response, err := s.request(request)
+		if err != nil {
+			return "", err
+		}
+		if response.errorStatus == errorNoSuchName && s.version == version1 {
+			break
+		}
+		if response.errorStatus != 0 {
+			return "", fmt.Errorf("agent returned %s error", response.statusError())
+		}
+		done = len(response.varbinds) == 0
+		err = decode(func() {
+			for _, v := range response.varbinds {
+				// the walk stops at the end of the subtree and on agents returning unordered oids
+				if v.tag == tagEndOfMibView || !v.oid.hasPrefix(rootOID) || !v.oid.after(current) || len(values) >= maxWalkResults {
+					done = true
+					return
+				}
+				values[v.oid.String()] = decodeValue(v.tag, v.value)
+				current = v.oid
+			}
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return marshalValues(values)
+}
+
+// BruteForceCommunity returns the communities accepted by the agent.
+//
+// The requests of all the communities are sent at once with the version
+// of the client, either 1 or 2c, as the agents only respond to the valid ones.
+func (c *SNMPClient) BruteForceCommunity(host string, port int, communities []string) ([]string, error) {
+	if c.Version == "3" {
+		return nil, fmt.Errorf("communities are not supported by version 3")
+	}
+	s, err := newSession(c, host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer s.close()
+
+	// pending are the indexes of the communities by request id
+	pending := make(map[int32]int, len(communities))
+	for i := range communities {
+		pending[s.nextRequestID()] = i
+	}
+	valid := make([]bool, len(communities))
+	for attempt := 0; attempt <= retries && len(pending) > 0; attempt++ {
+		for id, i := range pending {
+			request := newPDU(pduGetRequest, sysDescr)
+			request.requestID = id
+			if _, err := s.conn.Write(marshalCommunity(s.version, communities[i], request)); err != nil {
+				return nil, err
+			}
+		}
+		err := s.read(time.Now().Add(timeout), func(data []byte) (bool, error) {
+			response, err := unmarshalCommunity(data)
+			if err != nil {
+				return false, nil
+			}
+			if i, ok := pending[response.requestID]; ok {
+				valid[i] = true
+				delete(pending, response.requestID)
+			}
+			return len(pending) == 0, nil
+		})
+		if err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+			return nil, err
+		}
+	}
+
+	var found []string
+	for i, community := range communities {
+		if valid[i] {
+			found = append(found, community)
+		}
+	}
+	return found, nil
+}
+
+func marshalValues(values map[string]interface{}) (string, error) {
+	data, err := jsoniter.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
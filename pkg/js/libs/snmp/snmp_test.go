@@ -0,0 +1,234 @@
+package snmp
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgent is an agent accepting the private community and the
+// admin and legacy users with sha authentication and aes or des privacy
+type fakeAgent struct {
+	t        *testing.T
+	conn     net.PacketConn
+	engineID []byte
+	mib      []varbind
+}
+
+func newFakeAgent(t *testing.T) (*fakeAgent, int) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	mib := []varbind{
+		{oid: oid{1, 3, 6, 1, 2, 1, 1, 1, 0}, tag: tagOctetString, value: []byte("Linux router")},
+		{oid: oid{1, 3, 6, 1, 2, 1, 1, 3, 0}, tag: tagTimeTicks, value: []byte{0x30, 0x39}},
+		{oid: oid{1, 3, 6, 1, 2, 1, 1, 5, 0}, tag: tagOctetString, value: []byte("router")},
+		{oid: oid{1, 3, 6, 1, 2, 1, 2, 2, 1, 6, 1}, tag: tagOctetString, value: []byte{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}},
+		{oid: oid{1, 3, 6, 1, 2, 1, 4, 20, 1, 1, 10, 0, 0, 1}, tag: tagIPAddress, value: []byte{10, 0, 0, 1}},
+	}
+	agent := &fakeAgent{t: t, conn: conn, engineID: []byte{0x80, 0x00, 0x1f, 0x88, 0x04, 'n', 'u', 'c'}, mib: mib}
+	go agent.serve()
+	return agent, conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func (a *fakeAgent) serve() {
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, addr, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		data := append([]byte(nil), buf[:n]...)
+		var version int64
+		require.Nil(a.t, decode(func() { version = (&reader{data: data}).sequence().integer() }))
+		var response []byte
+		if version == version3 {
+			response = a.handleV3(data)
+		} else {
+			response = a.handleCommunity(data)
+		}
+		if response != nil {
+			_, _ = a.conn.WriteTo(response, addr)
+		}
+	}
+}
+
+func (a *fakeAgent) handleCommunity(data []byte) []byte {
+	var version int64
+	var community string
+	var request pdu
+	require.Nil(a.t, decode(func() {
+		msg := (&reader{data: data}).sequence()
+		version = msg.integer()
+		community = string(msg.octetString())
+		request = parsePDU(msg)
+	}))
+	if community != "private" {
+		return nil
+	}
+	response := a.respond(request)
+	return marshalCommunity(int(version), community, response)
+}
+
+func (a *fakeAgent) handleV3(data []byte) []byte {
+	msg, err := unmarshalV3(data)
+	require.Nil(a.t, err)
+	reply := messageV3{msgID: msg.msgID, security: securityParameters{engineID: a.engineID, engineBoots: 1, engineTime: 100}}
+	if len(msg.security.engineID) == 0 {
+		reply.data = marshalScopedPDU(a.engineID, "", a.report("1.3.6.1.6.3.15.1.1.4.0"))
+		data, _ := reply.marshal()
+		return data
+	}
+
+	require.Equal(a.t, a.engineID, msg.security.engineID)
+	protocols := map[string]string{"admin": privAES, "legacy": privDES}
+	protocol, ok := protocols[msg.security.userName]
+	if !ok {
+		reply.data = marshalScopedPDU(a.engineID, "", a.report("1.3.6.1.6.3.15.1.1.3.0"))
+		data, _ := reply.marshal()
+		return data
+	}
+	require.Equal(a.t, byte(flagAuth|flagPriv|flagReportable), msg.flags)
+	auth := authProtocols["SHA"]
+	authKey := auth.localizeKey("maplesyrup", a.engineID)
+	unsigned := append([]byte(nil), data...)
+	offset := bytes.Index(unsigned, msg.security.authParams)
+	copy(unsigned[offset:], make([]byte, auth.truncation))
+	if !bytes.Equal(auth.sign(authKey, unsigned), msg.security.authParams) {
+		reply.data = marshalScopedPDU(a.engineID, "", a.report("1.3.6.1.6.3.15.1.1.5.0"))
+		data, _ := reply.marshal()
+		return data
+	}
+
+	privKey := auth.localizeKey("privpassword", a.engineID)
+	scoped, err := decrypt(protocol, privKey, msg.security.engineBoots, msg.security.engineTime, msg.security.privParams, msg.data)
+	require.Nil(a.t, err)
+	request, err := parseScopedPDU(scoped)
+	require.Nil(a.t, err)
+
+	reply.flags = flagAuth | flagPriv
+	reply.security.userName = msg.security.userName
+	reply.data, reply.security.privParams, err = encrypt(protocol, privKey, 1, 100, 42, marshalScopedPDU(a.engineID, "", a.respond(request)))
+	require.Nil(a.t, err)
+	reply.security.authParams = make([]byte, auth.truncation)
+	response, offset := reply.marshal()
+	copy(response[offset:], auth.sign(authKey, response))
+	return response
+}
+
+func (a *fakeAgent) report(reported string) pdu {
+	o, _ := parseOID(reported)
+	return pdu{tag: pduReport, varbinds: []varbind{{oid: o, tag: tagCounter32, value: []byte{1}}}}
+}
+
+// respond returns the response of the get, get next and bulk requests
+func (a *fakeAgent) respond(request pdu) pdu {
+	response := pdu{tag: pduResponse, requestID: request.requestID}
+	for _, v := range request.varbinds {
+		switch request.tag {
+		case pduGetRequest:
+			binding := varbind{oid: v.oid, tag: tagNoSuchInstance}
+			for _, entry := range a.mib {
+				if entry.oid.String() == v.oid.String() {
+					binding = entry
+				}
+			}
+			response.varbinds = append(response.varbinds, binding)
+		case pduGetNextRequest, pduGetBulkRequest:
+			count := 1
+			if request.tag == pduGetBulkRequest {
+				count = int(request.errorIndex)
+			}
+			index := sort.Search(len(a.mib), func(i int) bool { return a.mib[i].oid.after(v.oid) })
+			for i := 0; i < count; i++ {
+				if index+i >= len(a.mib) {
+					response.varbinds = append(response.varbinds, varbind{oid: v.oid, tag: tagEndOfMibView})
+					break
+				}
+				response.varbinds = append(response.varbinds, a.mib[index+i])
+			}
+		}
+	}
+	return response
+}
+
+func TestSNMPClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	_, port := newFakeAgent(t)
+
+	client := &SNMPClient{Community: "private"}
+	values, err := client.Get("127.0.0.1", port, "1.3.6.1.2.1.1.1.0", ".1.3.6.1.2.1.1.3.0", "1.3.6.1.2.1.1.9.0")
+	require.Nil(t, err)
+	require.JSONEq(t, `{"1.3.6.1.2.1.1.1.0": "Linux router", "1.3.6.1.2.1.1.3.0": 12345}`, values)
+
+	values, err = client.Walk("127.0.0.1", port, "1.3.6.1.2.1.1")
+	require.Nil(t, err)
+	require.JSONEq(t, `{"1.3.6.1.2.1.1.1.0": "Linux router", "1.3.6.1.2.1.1.3.0": 12345, "1.3.6.1.2.1.1.5.0": "router"}`, values)
+
+	client.Version = "1"
+	values, err = client.Walk("127.0.0.1", port, "1.3.6.1.2.1")
+	require.Nil(t, err)
+	require.JSONEq(t, `{
+		"1.3.6.1.2.1.1.1.0": "Linux router",
+		"1.3.6.1.2.1.1.3.0": 12345,
+		"1.3.6.1.2.1.1.5.0": "router",
+		"1.3.6.1.2.1.2.2.1.6.1": "0x001a2b3c4d5e",
+		"1.3.6.1.2.1.4.20.1.1.10.0.0.1": "10.0.0.1"
+	}`, values)
+
+	// the invalid communities are only known once the timeout is exceeded
+	timeout = 500 * time.Millisecond
+	defer func() { timeout = 5 * time.Second }()
+	client.Version = "2c"
+	communities, err := client.BruteForceCommunity("127.0.0.1", port, []string{"public", "private", "admin"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"private"}, communities)
+
+	for username, protocol := range map[string]string{"admin": "AES", "legacy": "DES"} {
+		client = &SNMPClient{Version: "3", Username: username, AuthProtocol: "SHA", AuthPassword: "maplesyrup", PrivProtocol: protocol, PrivPassword: "privpassword"}
+		values, err = client.Get("127.0.0.1", port, "1.3.6.1.2.1.1.5.0")
+		require.Nil(t, err, "could not get with %s privacy", protocol)
+		require.JSONEq(t, `{"1.3.6.1.2.1.1.5.0": "router"}`, values)
+	}
+
+	client.AuthPassword = "invalid-password"
+	_, err = client.Get("127.0.0.1", port, "1.3.6.1.2.1.1.5.0")
+	require.EqualError(t, err, "agent reported wrong digest")
+
+	client.Username = "unknown"
+	_, err = client.Get("127.0.0.1", port, "1.3.6.1.2.1.1.5.0")
+	require.EqualError(t, err, "agent reported unknown user name")
+
+	client.PrivProtocol = "3DES"
+	_, err = client.Get("127.0.0.1", port, "1.3.6.1.2.1.1.5.0")
+	require.EqualError(t, err, "unsupported privacy protocol 3DES")
+}
+
+func TestBER(t *testing.T) {
+	for _, value := range []int64{0, 127, 128, -1, -128, -129, 65535, 1 << 40} {
+		var decoded int64
+		require.Nil(t, decode(func() { decoded = (&reader{data: appendInteger(nil, tagInteger, value)}).integer() }))
+		require.Equal(t, value, decoded)
+	}
+	require.Equal(t, []byte{0x02, 0x02, 0x00, 0x80}, appendInteger(nil, tagInteger, 128))
+
+	o, err := parseOID("1.3.6.1.4.1.2021.4294967295")
+	require.Nil(t, err)
+	encoded := appendOID(nil, o)
+	require.Equal(t, []byte{0x06, 0x0c, 0x2b, 0x06, 0x01, 0x04, 0x01, 0x8f, 0x65, 0x8f, 0xff, 0xff, 0xff, 0x7f}, encoded)
+	var decoded oid
+	require.Nil(t, decode(func() { decoded = decodeOID((&reader{data: encoded}).expect(tagOID)) }))
+	require.Equal(t, o, decoded)
+
+	_, err = parseOID("4.1")
+	require.Error(t, err, "could parse invalid oid")
+	require.Error(t, decode(func() { (&reader{data: []byte{0x30, 0x84, 0x7f, 0xff, 0xff, 0xff}}).sequence() }), "could decode truncated sequence")
+	require.Equal(t, []byte{0x04, 0x81, 0x80}, appendLength([]byte{0x04}, 128)[:3])
+}
@@ -0,0 +1,155 @@
+package snmp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// authProtocol is an authentication protocol of the user-based security model
+type authProtocol struct {
+	hash func() hash.Hash
+	// truncation is the length of the authentication parameters
+	truncation int
+}
+
+// authProtocols are the supported authentication protocols (RFC 3414 and RFC 7860)
+var authProtocols = map[string]authProtocol{
+	"MD5":    {hash: md5.New, truncation: 12},
+	"SHA":    {hash: sha1.New, truncation: 12},
+	"SHA224": {hash: sha256.New224, truncation: 16},
+	"SHA256": {hash: sha256.New, truncation: 24},
+	"SHA384": {hash: sha512.New384, truncation: 32},
+	"SHA512": {hash: sha512.New, truncation: 48},
+}
+
+// privacy protocols of the user-based security model (RFC 3414 and RFC 3826)
+const (
+	privDES = "DES"
+	privAES = "AES"
+)
+
+// passwordExpansion is the number of password bytes hashed by the key derivation
+const passwordExpansion = 1048576
+
+// localizeKey derives the key of the password localized for the engine (RFC 3414 A.2)
+func (a authProtocol) localizeKey(password string, engineID []byte) []byte {
+	h := a.hash()
+	buf := make([]byte, 64)
+	for count, index := 0, 0; count < passwordExpansion; count += len(buf) {
+		for i := range buf {
+			buf[i] = password[index%len(password)]
+			index++
+		}
+		h.Write(buf)
+	}
+	key := h.Sum(nil)
+
+	h.Reset()
+	h.Write(key)
+	h.Write(engineID)
+	h.Write(key)
+	return h.Sum(nil)
+}
+
+// sign returns the authentication parameters of the message
+func (a authProtocol) sign(key, message []byte) []byte {
+	mac := hmac.New(a.hash, key)
+	mac.Write(message)
+	return mac.Sum(nil)[:a.truncation]
+}
+
+// encrypt encrypts the scoped pdu and returns the privacy parameters,
+// the salt must be unique for each message
+func encrypt(protocol string, key []byte, engineBoots, engineTime int64, salt uint64, plaintext []byte) ([]byte, []byte, error) {
+	switch protocol {
+	case privDES:
+		privParams := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, uint32(engineBoots)), uint32(salt))
+		block, iv, err := desCipher(key, privParams)
+		if err != nil {
+			return nil, nil, err
+		}
+		// the padding is ignored when decoding, the pdu has an explicit length
+		if padding := len(plaintext) % des.BlockSize; padding != 0 {
+			plaintext = append(plaintext, make([]byte, des.BlockSize-padding)...)
+		}
+		ciphertext := make([]byte, len(plaintext))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+		return ciphertext, privParams, nil
+	case privAES:
+		privParams := binary.BigEndian.AppendUint64(nil, salt)
+		block, iv, err := aesCipher(key, engineBoots, engineTime, privParams)
+		if err != nil {
+			return nil, nil, err
+		}
+		ciphertext := make([]byte, len(plaintext))
+		cipher.NewCFBEncrypter(block, iv).XORKeyStream(ciphertext, plaintext) //nolint:staticcheck // mandated by RFC 3826
+		return ciphertext, privParams, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported privacy protocol %s", protocol)
+}
+
+// decrypt decrypts the scoped pdu with the privacy parameters of the message
+func decrypt(protocol string, key []byte, engineBoots, engineTime int64, privParams, ciphertext []byte) ([]byte, error) {
+	switch protocol {
+	case privDES:
+		if len(ciphertext)%des.BlockSize != 0 {
+			return nil, fmt.Errorf("invalid encrypted pdu length %d", len(ciphertext))
+		}
+		block, iv, err := desCipher(key, privParams)
+		if err != nil {
+			return nil, err
+		}
+		plaintext := make([]byte, len(ciphertext))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+		return plaintext, nil
+	case privAES:
+		block, iv, err := aesCipher(key, engineBoots, engineTime, privParams)
+		if err != nil {
+			return nil, err
+		}
+		plaintext := make([]byte, len(ciphertext))
+		cipher.NewCFBDecrypter(block, iv).XORKeyStream(plaintext, ciphertext) //nolint:staticcheck // mandated by RFC 3826
+		return plaintext, nil
+	}
+	return nil, fmt.Errorf("unsupported privacy protocol %s", protocol)
+}
+
+// desCipher returns the cipher of the first half of the key and the iv
+// of the salt xored with the second half
+func desCipher(key, privParams []byte) (cipher.Block, []byte, error) {
+	if len(key) < 16 || len(privParams) != 8 {
+		return nil, nil, fmt.Errorf("invalid des privacy parameters")
+	}
+	block, err := des.NewCipher(key[:8])
+	if err != nil {
+		return nil, nil, err
+	}
+	iv := make([]byte, des.BlockSize)
+	for i := range iv {
+		iv[i] = key[8+i] ^ privParams[i]
+	}
+	return block, iv, nil
+}
+
+// aesCipher returns the cipher of the key and the iv of the engine boots, time and salt
+func aesCipher(key []byte, engineBoots, engineTime int64, privParams []byte) (cipher.Block, []byte, error) {
+	if len(key) < 16 || len(privParams) != 8 {
+		return nil, nil, fmt.Errorf("invalid aes privacy parameters")
+	}
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return nil, nil, err
+	}
+	iv := binary.BigEndian.AppendUint32(nil, uint32(engineBoots))
+	iv = binary.BigEndian.AppendUint32(iv, uint32(engineTime))
+	return block, append(iv, privParams...), nil
+}
@@ -0,0 +1,32 @@
+package snmp
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalizeKey(t *testing.T) {
+	// test vectors of RFC 3414 A.3
+	engineID, _ := hex.DecodeString("000000000000000000000002")
+	require.Equal(t, "526f5eed9fcce26f8964c2930787d82b", hex.EncodeToString(authProtocols["MD5"].localizeKey("maplesyrup", engineID)))
+	require.Equal(t, "6695febc9288e36282235fc7151f128497b38f3f", hex.EncodeToString(authProtocols["SHA"].localizeKey("maplesyrup", engineID)))
+}
+
+func TestEncrypt(t *testing.T) {
+	key := authProtocols["SHA"].localizeKey("privpassword", []byte("engine"))
+	plaintext := marshalScopedPDU([]byte("engine"), "", newPDU(pduGetRequest, sysDescr))
+	for _, protocol := range []string{privDES, privAES} {
+		ciphertext, privParams, err := encrypt(protocol, key, 3, 1000, 7, plaintext)
+		require.Nil(t, err)
+		require.Len(t, privParams, 8)
+		require.NotEqual(t, plaintext, ciphertext[:len(plaintext)])
+
+		decrypted, err := decrypt(protocol, key, 3, 1000, privParams, ciphertext)
+		require.Nil(t, err)
+		p, err := parseScopedPDU(decrypted)
+		require.Nil(t, err, "could not parse %s decrypted pdu", protocol)
+		require.Equal(t, sysDescr, p.varbinds[0].oid)
+	}
+}
@@ -0,0 +1,128 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/zmap/zgrab2/lib/ssh"
+)
+
+// weakAlgorithms are the algorithms considered weak, they use broken
+// primitives like sha1 signatures, small groups, cbc modes or md5
+var weakAlgorithms = map[string]struct{}{
+	// key exchanges
+	"diffie-hellman-group1-sha1":         {},
+	"diffie-hellman-group14-sha1":        {},
+	"diffie-hellman-group-exchange-sha1": {},
+	// host keys
+	"ssh-dss":                      {},
+	"ssh-rsa":                      {},
+	"ssh-dss-cert-v01@openssh.com": {},
+	"ssh-rsa-cert-v01@openssh.com": {},
+	// ciphers
+	"none":                        {},
+	"des-cbc":                     {},
+	"3des-cbc":                    {},
+	"blowfish-cbc":                {},
+	"cast128-cbc":                 {},
+	"arcfour":                     {},
+	"arcfour128":                  {},
+	"arcfour256":                  {},
+	"aes128-cbc":                  {},
+	"aes192-cbc":                  {},
+	"aes256-cbc":                  {},
+	"rijndael-cbc@lysator.liu.se": {},
+	// macs
+	"hmac-md5":                     {},
+	"hmac-md5-96":                  {},
+	"hmac-sha1-96":                 {},
+	"hmac-md5-etm@openssh.com":     {},
+	"hmac-md5-96-etm@openssh.com":  {},
+	"hmac-sha1-96-etm@openssh.com": {},
+	"umac-64@openssh.com":          {},
+	"umac-64-etm@openssh.com":      {},
+}
+
+// Algorithms are the algorithms offered by a server.
+type Algorithms struct {
+	Kex          []string
+	HostKey      []string
+	Ciphers      []string
+	MACs         []string
+	Compressions []string
+	// Weak are the offered algorithms considered weak, ex: diffie-hellman-group1-sha1 or 3des-cbc
+	Weak []string
+}
+
+// GetAlgorithms returns the key exchange, host key, cipher, mac and
+// compression algorithms offered by the server.
+//
+// The algorithms are read from the key exchange init message of the
+// server, they are returned even if the client does not support them.
+func (c *SSHClient) GetAlgorithms(host string, port int) (Algorithms, error) {
+	resp := Algorithms{}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port <= 0 {
+		return resp, errors.New("invalid host or port")
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", addr)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	data := new(ssh.HandshakeLog)
+	config := ssh.MakeSSHConfig()
+	config.ConnLog = data
+	config.DontAuthenticate = true
+	clientConn, _, _, err := ssh.NewClientConn(conn, addr, config)
+	if err == nil {
+		clientConn.Close()
+	}
+	// the negotiation fails on the servers only offering unsupported algorithms
+	if data.ServerKex == nil {
+		if err == nil {
+			err = errors.New("could not get server algorithms")
+		}
+		return resp, err
+	}
+
+	kex := data.ServerKex
+	resp.Kex = kex.KexAlgos
+	resp.HostKey = kex.ServerHostKeyAlgos
+	resp.Ciphers = union(kex.CiphersClientServer, kex.CiphersServerClient)
+	resp.MACs = union(kex.MACsClientServer, kex.MACsServerClient)
+	resp.Compressions = union(kex.CompressionClientServer, kex.CompressionServerClient)
+	for _, algorithms := range [][]string{resp.Kex, resp.HostKey, resp.Ciphers, resp.MACs} {
+		for _, algorithm := range algorithms {
+			if _, ok := weakAlgorithms[algorithm]; ok {
+				resp.Weak = append(resp.Weak, algorithm)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// union returns the algorithms of both directions without duplicates
+func union(a, b []string) []string {
+	seen := make(map[string]struct{}, len(a))
+	result := []string{}
+	for _, algorithm := range append(append([]string{}, a...), b...) {
+		if _, ok := seen[algorithm]; ok {
+			continue
+		}
+		seen[algorithm] = struct{}{}
+		result = append(result, algorithm)
+	}
+	return result
+}
@@ -1,27 +1,48 @@
 package ssh
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/zmap/zgrab2/lib/ssh"
+	xssh "golang.org/x/crypto/ssh"
 )
 
+// timeout is the timeout of the handshakes
+const timeout = 10 * time.Second
+
+// errHostKeyCaptured stops the handshakes once the host key is received
+var errHostKeyCaptured = errors.New("host key captured")
+
 // SSHClient is a client for SSH servers.
 //
-// Internally client uses github.com/zmap/zgrab2/lib/ssh driver.
+// Internally client uses github.com/zmap/zgrab2/lib/ssh driver for the
+// handshake logs and golang.org/x/crypto/ssh for the authentication, the
+// rsa keys are signed with the sha2 algorithms required by the recent servers.
 type SSHClient struct{}
 
+// HostKey is the host key of a server.
+type HostKey struct {
+	Type string
+	// Key is the key in the authorized_keys format
+	Key               string
+	FingerprintSHA256 string
+	FingerprintMD5    string
+}
+
 // Connect tries to connect to provided host and port
 // with provided username and password with ssh.
 //
 // Returns state of connection and error. If error is not nil,
 // state will be false
 func (c *SSHClient) Connect(host string, port int, username, password string) (bool, error) {
-	conn, err := connect(host, port, username, password, "")
+	conn, err := connect(host, port, username, password, "", "")
 	if err != nil {
 		return false, err
 	}
@@ -33,10 +54,27 @@ func (c *SSHClient) Connect(host string, port int, username, password string) (b
 // ConnectWithKey tries to connect to provided host and port
 // with provided username and private_key.
 //
+// The PEM and OpenSSH private key formats are supported.
+//
 // Returns state of connection and error. If error is not nil,
 // state will be false
 func (c *SSHClient) ConnectWithKey(host string, port int, username, key string) (bool, error) {
-	conn, err := connect(host, port, username, "", key)
+	conn, err := connect(host, port, username, "", key, "")
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	return true, nil
+}
+
+// ConnectWithKeyPassphrase tries to connect to provided host and port
+// with provided username and private_key encrypted with the passphrase.
+//
+// Returns state of connection and error. If error is not nil,
+// state will be false
+func (c *SSHClient) ConnectWithKeyPassphrase(host string, port int, username, key, passphrase string) (bool, error) {
+	conn, err := connect(host, port, username, "", key, passphrase)
 	if err != nil {
 		return false, err
 	}
@@ -57,6 +95,36 @@ func (c *SSHClient) ConnectSSHInfoMode(host string, port int) (*ssh.HandshakeLog
 	return connectSSHInfoMode(host, port)
 }
 
+// GetHostKey returns the host key of the server along with its fingerprints.
+//
+// The fingerprints are in the format of ssh-keygen, ex: SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8
+func (c *SSHClient) GetHostKey(host string, port int) (HostKey, error) {
+	resp := HostKey{}
+	var hostKey xssh.PublicKey
+	config := &xssh.ClientConfig{
+		HostKeyCallback: func(hostname string, remote net.Addr, key xssh.PublicKey) error {
+			hostKey = key
+			return errHostKeyCaptured
+		},
+		Timeout: timeout,
+	}
+	client, err := dial(host, port, config)
+	if err == nil {
+		client.Close()
+	}
+	if hostKey == nil {
+		if err == nil {
+			err = errors.New("could not get host key")
+		}
+		return resp, err
+	}
+	resp.Type = hostKey.Type()
+	resp.Key = strings.TrimSpace(string(xssh.MarshalAuthorizedKey(hostKey)))
+	resp.FingerprintSHA256 = xssh.FingerprintSHA256(hostKey)
+	resp.FingerprintMD5 = "MD5:" + xssh.FingerprintLegacyMD5(hostKey)
+	return resp, nil
+}
+
 func connectSSHInfoMode(host string, port int) (*ssh.HandshakeLog, error) {
 	if !protocolstate.IsHostAllowed(host) {
 		// host is not valid according to network policy
@@ -82,33 +150,55 @@ func connectSSHInfoMode(host string, port int) (*ssh.HandshakeLog, error) {
 	return data, nil
 }
 
-func connect(host string, port int, user, password, privateKey string) (*ssh.Client, error) {
-	if !protocolstate.IsHostAllowed(host) {
-		// host is not valid according to network policy
-		return nil, protocolstate.ErrHostDenied.Msgf(host)
-	}
-	if host == "" || port <= 0 {
-		return nil, errors.New("invalid host or port")
-	}
-
-	conf := &ssh.ClientConfig{
-		User: user,
-		Auth: []ssh.AuthMethod{},
+func connect(host string, port int, user, password, privateKey, passphrase string) (*xssh.Client, error) {
+	conf := &xssh.ClientConfig{
+		User:            user,
+		Auth:            []xssh.AuthMethod{},
+		HostKeyCallback: xssh.InsecureIgnoreHostKey(),
+		Timeout:         timeout,
 	}
 	if len(password) > 0 {
-		conf.Auth = append(conf.Auth, ssh.Password(password))
+		conf.Auth = append(conf.Auth, xssh.Password(password))
 	}
 	if len(privateKey) > 0 {
-		signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+		signer, err := parsePrivateKey(privateKey, passphrase)
 		if err != nil {
 			return nil, err
 		}
-		conf.Auth = append(conf.Auth, ssh.PublicKeys(signer))
+		conf.Auth = append(conf.Auth, xssh.PublicKeys(signer))
 	}
+	return dial(host, port, conf)
+}
 
-	client, err := ssh.Dial("tcp", fmt.Sprintf("%s:%d", host, port), conf)
+// dial connects to the server with the nuclei dialer and performs the handshake
+func dial(host string, port int, config *xssh.ClientConfig) (*xssh.Client, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port <= 0 {
+		return nil, errors.New("invalid host or port")
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", addr)
 	if err != nil {
 		return nil, err
 	}
-	return client, nil
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	clientConn, chans, reqs, err := xssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return xssh.NewClient(clientConn, chans, reqs), nil
+}
+
+// parsePrivateKey returns the signer of a private key, the key is decrypted with the passphrase if set
+func parsePrivateKey(privateKey, passphrase string) (xssh.Signer, error) {
+	if passphrase != "" {
+		return xssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(passphrase))
+	}
+	return xssh.ParsePrivateKey([]byte(privateKey))
 }
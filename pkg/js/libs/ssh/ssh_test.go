@@ -0,0 +1,134 @@
+package ssh
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+	xssh "golang.org/x/crypto/ssh"
+)
+
+// listenSSH serves a ssh server with the config, the connections are closed once authenticated
+func listenSSH(t *testing.T, config *xssh.ServerConfig) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serverConn, _, _, err := xssh.NewServerConn(conn, config)
+				if err == nil {
+					serverConn.Close()
+				}
+			}()
+		}
+	}()
+
+	host, portValue, err := net.SplitHostPort(listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	return host, port
+}
+
+func TestSSHClient(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	hostKey, err := xssh.NewSignerFromKey(hostPrivateKey)
+	require.Nil(t, err)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	authorizedKey, err := xssh.NewPublicKey(publicKey)
+	require.Nil(t, err)
+
+	config := &xssh.ServerConfig{
+		PasswordCallback: func(conn xssh.ConnMetadata, password []byte) (*xssh.Permissions, error) {
+			if conn.User() == "root" && string(password) == "toor" {
+				return nil, nil
+			}
+			return nil, errors.New("invalid password")
+		},
+		PublicKeyCallback: func(conn xssh.ConnMetadata, key xssh.PublicKey) (*xssh.Permissions, error) {
+			if conn.User() == "root" && bytes.Equal(key.Marshal(), authorizedKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("invalid key")
+		},
+	}
+	config.AddHostKey(hostKey)
+	host, port := listenSSH(t, config)
+	client := &SSHClient{}
+
+	connected, err := client.Connect(host, port, "root", "toor")
+	require.Nil(t, err)
+	require.True(t, connected)
+
+	_, err = client.Connect(host, port, "root", "wrong")
+	require.ErrorContains(t, err, "unable to authenticate")
+
+	block, err := xssh.MarshalPrivateKey(privateKey, "")
+	require.Nil(t, err)
+	connected, err = client.ConnectWithKey(host, port, "root", string(pem.EncodeToMemory(block)))
+	require.Nil(t, err)
+	require.True(t, connected)
+
+	block, err = xssh.MarshalPrivateKeyWithPassphrase(privateKey, "", []byte("secret"))
+	require.Nil(t, err)
+	encrypted := string(pem.EncodeToMemory(block))
+	connected, err = client.ConnectWithKeyPassphrase(host, port, "root", encrypted, "secret")
+	require.Nil(t, err)
+	require.True(t, connected)
+
+	_, err = client.ConnectWithKey(host, port, "root", encrypted)
+	require.EqualError(t, err, "ssh: this private key is passphrase protected")
+
+	key, err := client.GetHostKey(host, port)
+	require.Nil(t, err)
+	require.Equal(t, HostKey{
+		Type:              "ssh-ed25519",
+		Key:               string(bytes.TrimSpace(xssh.MarshalAuthorizedKey(hostKey.PublicKey()))),
+		FingerprintSHA256: xssh.FingerprintSHA256(hostKey.PublicKey()),
+		FingerprintMD5:    "MD5:" + xssh.FingerprintLegacyMD5(hostKey.PublicKey()),
+	}, key)
+}
+
+func TestSSHClientGetAlgorithms(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	hostKey, err := xssh.NewSignerFromKey(hostPrivateKey)
+	require.Nil(t, err)
+
+	config := &xssh.ServerConfig{NoClientAuth: true}
+	config.KeyExchanges = []string{"curve25519-sha256@libssh.org", "diffie-hellman-group1-sha1"}
+	config.Ciphers = []string{"aes128-ctr", "3des-cbc"}
+	config.MACs = []string{"hmac-sha2-256", "hmac-sha1-96"}
+	config.AddHostKey(hostKey)
+	host, port := listenSSH(t, config)
+	client := &SSHClient{}
+
+	algorithms, err := client.GetAlgorithms(host, port)
+	require.Nil(t, err)
+	require.Equal(t, []string{"curve25519-sha256@libssh.org", "diffie-hellman-group1-sha1"}, algorithms.Kex)
+	require.Equal(t, []string{"ssh-ed25519"}, algorithms.HostKey)
+	require.Equal(t, []string{"aes128-ctr", "3des-cbc"}, algorithms.Ciphers)
+	require.Equal(t, []string{"hmac-sha2-256", "hmac-sha1-96"}, algorithms.MACs)
+	require.Equal(t, []string{"none"}, algorithms.Compressions)
+	require.Equal(t, []string{"diffie-hellman-group1-sha1", "3des-cbc", "hmac-sha1-96"}, algorithms.Weak)
+}
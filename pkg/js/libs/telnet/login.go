@@ -0,0 +1,126 @@
+package telnet
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"regexp"
+	"time"
+)
+
+// maxOutputSize is the maximum size of the output read while waiting for a prompt
+const maxOutputSize = 64 * 1024
+
+var (
+	loginPromptRegex    = regexp.MustCompile(`(?i)(login|username|user name|user)\s*:\s*$`)
+	passwordPromptRegex = regexp.MustCompile(`(?i)(password|passcode|passwd)\s*:\s*$`)
+	shellPromptRegex    = regexp.MustCompile(`[$#>%]\s*$`)
+	failureRegex        = regexp.MustCompile(`(?i)(incorrect|invalid|fail|denied|bad password|wrong|try again)`)
+)
+
+// prompts detected in the output of the server
+const (
+	promptNone = iota
+	promptLogin
+	promptPassword
+	promptShell
+	promptFailure
+)
+
+// session is a telnet session waiting for the prompts of the server
+type session struct {
+	conn       net.Conn
+	negotiator *negotiator
+	timeout    time.Duration
+}
+
+// send sends a line to the server
+func (s *session) send(line string) error {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	_, err := s.conn.Write([]byte(line + "\r\n"))
+	return err
+}
+
+// waitPrompt reads the output of the server until a prompt is detected, promptNone is
+// returned if the server closes the connection or no prompt is detected before the timeout.
+// The failure messages are only detected once the credentials are sent.
+func (s *session) waitPrompt(credentialsSent bool) (string, int, error) {
+	output := []byte{}
+	buf := make([]byte, 4096)
+	_ = s.conn.SetReadDeadline(time.Now().Add(s.timeout))
+	for len(output) < maxOutputSize {
+		n, err := s.conn.Read(buf)
+		if n > 0 {
+			data, writeErr := s.negotiator.process(buf[:n])
+			if writeErr != nil {
+				return string(output), promptNone, writeErr
+			}
+			output = append(output, bytes.ReplaceAll(data, []byte{0}, nil)...)
+			if prompt := detectPrompt(output, credentialsSent); prompt != promptNone {
+				return string(output), prompt, nil
+			}
+		}
+		if err != nil {
+			var netErr net.Error
+			if errors.Is(err, io.EOF) || (errors.As(err, &netErr) && netErr.Timeout()) {
+				return string(output), promptNone, nil
+			}
+			return string(output), promptNone, err
+		}
+	}
+	return string(output), promptNone, nil
+}
+
+// detectPrompt returns the prompt at the end of the output
+func detectPrompt(output []byte, credentialsSent bool) int {
+	switch {
+	case passwordPromptRegex.Match(output):
+		return promptPassword
+	case loginPromptRegex.Match(output):
+		return promptLogin
+	case credentialsSent && failureRegex.Match(output):
+		return promptFailure
+	case shellPromptRegex.Match(output):
+		return promptShell
+	}
+	return promptNone
+}
+
+// login submits the credentials to the prompts of the server and returns true
+// if a shell prompt is detected, the banner is the output before the first prompt
+func (s *session) login(username, password string) (bool, string, string, error) {
+	banner, prompt, err := s.waitPrompt(false)
+	if err != nil {
+		return false, banner, "", err
+	}
+	if prompt == promptNone {
+		return false, banner, "", errors.New("no login prompt detected")
+	}
+
+	output := ""
+	usernameSent, passwordSent := false, false
+	for {
+		switch {
+		case prompt == promptShell:
+			return true, banner, output, nil
+		case prompt == promptLogin && !usernameSent:
+			if err := s.send(username); err != nil {
+				return false, banner, output, err
+			}
+			usernameSent = true
+		case prompt == promptPassword && !passwordSent:
+			if err := s.send(password); err != nil {
+				return false, banner, output, err
+			}
+			passwordSent = true
+		default:
+			// failures, prompts asked again, timeouts and closed connections
+			return false, banner, output, nil
+		}
+		output, prompt, err = s.waitPrompt(true)
+		if err != nil {
+			return false, banner, output, err
+		}
+	}
+}
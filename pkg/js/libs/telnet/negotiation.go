@@ -0,0 +1,119 @@
+package telnet
+
+import (
+	"bytes"
+	"net"
+)
+
+// telnet commands and options
+//
+// https://www.rfc-editor.org/rfc/rfc854
+const (
+	cmdSE   = 240
+	cmdSB   = 250
+	cmdWILL = 251
+	cmdWONT = 252
+	cmdDO   = 253
+	cmdDONT = 254
+	cmdIAC  = 255
+
+	optEcho            = 1
+	optSuppressGoAhead = 3
+)
+
+// parser states of the telnet stream
+const (
+	stateData = iota
+	stateIAC
+	stateOption
+	stateSubnegotiation
+	stateSubnegotiationIAC
+)
+
+// negotiator strips the telnet commands of the data received from the server
+// and answers the option negotiations, the server is allowed to echo and to
+// suppress the go-aheads while all the options of the client are refused.
+type negotiator struct {
+	conn    net.Conn
+	state   int
+	command byte
+	// replied are the options already answered to avoid negotiation loops
+	replied map[[2]byte]struct{}
+}
+
+func newNegotiator(conn net.Conn) *negotiator {
+	return &negotiator{conn: conn, replied: make(map[[2]byte]struct{})}
+}
+
+// process returns the data of the received bytes and sends the replies of the negotiations,
+// the commands split between several reads are handled by the state of the parser.
+func (n *negotiator) process(received []byte) ([]byte, error) {
+	data := make([]byte, 0, len(received))
+	replies := &bytes.Buffer{}
+	for _, b := range received {
+		switch n.state {
+		case stateData:
+			if b == cmdIAC {
+				n.state = stateIAC
+				continue
+			}
+			data = append(data, b)
+		case stateIAC:
+			switch b {
+			case cmdIAC:
+				// escaped 255 data byte
+				data = append(data, b)
+				n.state = stateData
+			case cmdWILL, cmdWONT, cmdDO, cmdDONT:
+				n.command = b
+				n.state = stateOption
+			case cmdSB:
+				n.state = stateSubnegotiation
+			default:
+				n.state = stateData
+			}
+		case stateOption:
+			n.reply(replies, n.command, b)
+			n.state = stateData
+		case stateSubnegotiation:
+			if b == cmdIAC {
+				n.state = stateSubnegotiationIAC
+			}
+		case stateSubnegotiationIAC:
+			if b == cmdSE {
+				n.state = stateData
+			} else {
+				n.state = stateSubnegotiation
+			}
+		}
+	}
+	if replies.Len() > 0 {
+		if _, err := n.conn.Write(replies.Bytes()); err != nil {
+			return data, err
+		}
+	}
+	return data, nil
+}
+
+// reply writes the answer of an option negotiation once per option, the DONT
+// and WONT commands are not answered since the options are disabled by default
+func (n *negotiator) reply(replies *bytes.Buffer, command, option byte) {
+	var answer byte
+	switch command {
+	case cmdDO:
+		answer = cmdWONT
+	case cmdWILL:
+		answer = cmdDONT
+		if option == optEcho || option == optSuppressGoAhead {
+			answer = cmdDO
+		}
+	default:
+		return
+	}
+	key := [2]byte{command, option}
+	if _, ok := n.replied[key]; ok {
+		return
+	}
+	n.replied[key] = struct{}{}
+	replies.Write([]byte{cmdIAC, answer, option})
+}
@@ -2,6 +2,7 @@ package telnet
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -11,8 +12,24 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
+// defaultTimeout is the default timeout of the prompts
+const defaultTimeout = 5 * time.Second
+
 // TelnetClient is a minimal Telnet client for nuclei scripts.
-type TelnetClient struct{}
+type TelnetClient struct {
+	// Timeout is the timeout of the prompts in seconds, 5 seconds by default
+	Timeout int
+}
+
+// LoginResponse is the response from the Login function.
+type LoginResponse struct {
+	// Success is true if a shell prompt is detected after the credentials
+	Success bool
+	// Banner is the output of the server before the first prompt
+	Banner string
+	// Output is the output of the server after the last credential sent
+	Output string
+}
 
 // IsTelnetResponse is the response from the IsTelnet function.
 type IsTelnetResponse struct {
@@ -43,3 +60,31 @@ func (c *TelnetClient) IsTelnet(host string, port int) (IsTelnetResponse, error)
 	resp.IsTelnet = true
 	return resp, nil
 }
+
+// Login negotiates the telnet options and submits the username and the password
+// to the prompts of the server. The login succeeds if a shell prompt (ex: $, # or >)
+// is detected, a failure message, a prompt asked again or the timeout fails the login.
+// The servers detecting a shell prompt without prompting for credentials succeed too.
+func (c *TelnetClient) Login(host string, port int, username, password string) (LoginResponse, error) {
+	resp := LoginResponse{}
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port <= 0 {
+		return resp, fmt.Errorf("invalid host or port")
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return resp, err
+	}
+	defer conn.Close()
+
+	timeout := defaultTimeout
+	if c.Timeout > 0 {
+		timeout = time.Duration(c.Timeout) * time.Second
+	}
+	s := &session{conn: conn, negotiator: newNegotiator(conn), timeout: timeout}
+	resp.Success, resp.Banner, resp.Output, err = s.login(username, password)
+	return resp, err
+}
@@ -0,0 +1,113 @@
+package telnet
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// recordConn records the data written to the connection
+type recordConn struct {
+	net.Conn
+	written bytes.Buffer
+}
+
+func (c *recordConn) Write(b []byte) (int, error) {
+	return c.written.Write(b)
+}
+
+func TestNegotiatorProcess(t *testing.T) {
+	conn := &recordConn{}
+	n := newNegotiator(conn)
+
+	// the commands are split between the reads
+	data, err := n.process([]byte{cmdIAC, cmdDO, 24, cmdIAC, cmdWILL, optEcho, 'o', 'k', cmdIAC})
+	require.Nil(t, err)
+	require.Equal(t, "ok", string(data))
+	data, err = n.process([]byte{cmdIAC, cmdIAC, cmdSB, 24, 1, cmdIAC, cmdSE, '!', cmdIAC, cmdDO, 24, cmdIAC, cmdWILL, 5})
+	require.Nil(t, err)
+	require.Equal(t, "\xff!", string(data))
+
+	// the replies are sent once per option
+	require.Equal(t, []byte{
+		cmdIAC, cmdWONT, 24, cmdIAC, cmdDO, optEcho,
+		cmdIAC, cmdDONT, 5,
+	}, conn.written.Bytes())
+}
+
+// listenTelnet serves a fake telnet server accepting the admin:admin credentials
+func listenTelnet(t *testing.T) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				readLine := func() string {
+					line, _ := reader.ReadString('\n')
+					// strip the negotiation replies of the client
+					for len(line) >= 3 && line[0] == cmdIAC {
+						line = line[3:]
+					}
+					return strings.TrimSpace(line)
+				}
+
+				_, _ = conn.Write([]byte{cmdIAC, cmdDO, 24, cmdIAC, cmdWILL, optEcho})
+				_, _ = conn.Write([]byte("Welcome to the router\r\nlogin: "))
+				username := readLine()
+				_, _ = conn.Write([]byte(username + "\r\nPassword: "))
+				password := readLine()
+				if username == "admin" && password == "admin" {
+					_, _ = conn.Write([]byte("\r\nrouter# "))
+				} else {
+					_, _ = conn.Write([]byte("\r\nLogin incorrect\r\n"))
+				}
+				_, _ = reader.ReadString('\n')
+			}()
+		}
+	}()
+
+	host, portValue, err := net.SplitHostPort(listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	return host, port
+}
+
+func TestTelnetClientLogin(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	host, port := listenTelnet(t)
+
+	client := &TelnetClient{Timeout: 2}
+	resp, err := client.Login(host, port, "admin", "admin")
+	require.Nil(t, err)
+	require.True(t, resp.Success)
+	require.Equal(t, "Welcome to the router\r\nlogin: ", resp.Banner)
+	require.Equal(t, "\r\nrouter# ", resp.Output)
+
+	resp, err = client.Login(host, port, "admin", "invalid")
+	require.Nil(t, err)
+	require.False(t, resp.Success)
+	require.Contains(t, resp.Output, "Login incorrect")
+}
+
+func TestDetectPrompt(t *testing.T) {
+	require.Equal(t, promptLogin, detectPrompt([]byte("Username: "), false))
+	require.Equal(t, promptPassword, detectPrompt([]byte("admin\r\nPassword:"), true))
+	require.Equal(t, promptShell, detectPrompt([]byte("BusyBox\r\n~ $ "), true))
+	require.Equal(t, promptFailure, detectPrompt([]byte("% Access denied\r\n"), true))
+	// the failures are not detected in the banners
+	require.Equal(t, promptNone, detectPrompt([]byte("invalid requests are logged\r\n"), false))
+}
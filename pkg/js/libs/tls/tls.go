@@ -0,0 +1,271 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	"github.com/projectdiscovery/utils/reader"
+)
+
+// defaultTimeout is the default timeout of the handshakes and the read/write operations
+const defaultTimeout = 5 * time.Second
+
+// versions are the TLS versions by name
+var versions = map[string]uint16{
+	"tls10": tls.VersionTLS10,
+	"tls11": tls.VersionTLS11,
+	"tls12": tls.VersionTLS12,
+	"tls13": tls.VersionTLS13,
+}
+
+// TLSClient opens raw TLS connections with custom handshake parameters.
+//
+// The certificates are not verified, the cipher suites apply to TLS 1.2 and
+// the lower versions only since the TLS 1.3 cipher suites are not configurable.
+type TLSClient struct {
+	// ServerName is the SNI of the handshakes, the host is used if empty
+	ServerName string
+	// ALPN are the application protocols offered to the server, ex: h2 or http/1.1
+	ALPN []string
+	// MinVersion is the minimum TLS version, ex: tls10, tls11, tls12 or tls13
+	MinVersion string
+	// MaxVersion is the maximum TLS version, ex: tls10, tls11, tls12 or tls13
+	MaxVersion string
+	// Ciphers are the names of the cipher suites offered to the server,
+	// ex: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, the defaults are used if empty
+	Ciphers []string
+}
+
+// ConnectionState is the state negotiated by a TLS handshake.
+type ConnectionState struct {
+	// Version is the negotiated TLS version, ex: TLS 1.2
+	Version string
+	// Cipher is the negotiated cipher suite, ex: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+	Cipher string
+	// ALPN is the negotiated application protocol, empty if the server did not select one
+	ALPN string
+	// ServerName is the SNI sent to the server
+	ServerName string
+	// Certificates are the certificates sent by the server, the leaf certificate first
+	Certificates []Certificate
+}
+
+// Certificate is a certificate sent by the server.
+type Certificate struct {
+	Subject   string
+	Issuer    string
+	DNSNames  []string
+	NotBefore string
+	NotAfter  string
+}
+
+// Connect opens a TLS connection to the host and port with the parameters
+// of the client, an error is returned if the handshake fails.
+func (c *TLSClient) Connect(host string, port int) (*TLSConn, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	config, err := c.tlsConfig(host)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.Handshake(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return &TLSConn{conn: tlsConn, timeout: defaultTimeout}, nil
+}
+
+// tlsConfig returns the tls config of the client for the host
+func (c *TLSClient) tlsConfig(host string) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         c.ServerName,
+		NextProtos:         c.ALPN,
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+	}
+	if config.ServerName == "" && net.ParseIP(host) == nil {
+		config.ServerName = host
+	}
+	if c.MinVersion != "" {
+		version, ok := versions[strings.ToLower(c.MinVersion)]
+		if !ok {
+			return nil, fmt.Errorf("invalid min version %q", c.MinVersion)
+		}
+		config.MinVersion = version
+	}
+	if c.MaxVersion != "" {
+		version, ok := versions[strings.ToLower(c.MaxVersion)]
+		if !ok {
+			return nil, fmt.Errorf("invalid max version %q", c.MaxVersion)
+		}
+		config.MaxVersion = version
+	}
+	if config.MaxVersion != 0 && config.MaxVersion < config.MinVersion {
+		return nil, fmt.Errorf("max version %q is lower than min version", c.MaxVersion)
+	}
+	for _, name := range c.Ciphers {
+		id, ok := cipherSuite(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid cipher %q", name)
+		}
+		config.CipherSuites = append(config.CipherSuites, id)
+	}
+	return config, nil
+}
+
+// cipherSuite returns the id of the cipher suite name, insecure suites included
+func cipherSuite(name string) (uint16, bool) {
+	for _, suites := range [][]*tls.CipherSuite{tls.CipherSuites(), tls.InsecureCipherSuites()} {
+		for _, suite := range suites {
+			if strings.EqualFold(suite.Name, name) {
+				return suite.ID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// TLSConn is a TLS connection to a remote host.
+type TLSConn struct {
+	conn    *tls.Conn
+	timeout time.Duration
+}
+
+// State returns the parameters negotiated by the handshake of the connection.
+func (c *TLSConn) State() ConnectionState {
+	state := c.conn.ConnectionState()
+	resp := ConnectionState{
+		Version:      tls.VersionName(state.Version),
+		Cipher:       tls.CipherSuiteName(state.CipherSuite),
+		ALPN:         state.NegotiatedProtocol,
+		ServerName:   state.ServerName,
+		Certificates: []Certificate{},
+	}
+	for _, cert := range state.PeerCertificates {
+		resp.Certificates = append(resp.Certificates, Certificate{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			DNSNames:  cert.DNSNames,
+			NotBefore: cert.NotBefore.UTC().Format(time.RFC3339),
+			NotAfter:  cert.NotAfter.UTC().Format(time.RFC3339),
+		})
+	}
+	return resp
+}
+
+// Close closes the connection.
+func (c *TLSConn) Close() error {
+	return c.conn.Close()
+}
+
+// SetTimeout sets read/write timeout for the connection (in seconds).
+func (c *TLSConn) SetTimeout(value int) {
+	c.timeout = time.Duration(value) * time.Second
+}
+
+// setDeadLine sets read/write deadline for the connection.
+// this is intended to be called before every read/write operation.
+func (c *TLSConn) setDeadLine() {
+	if c.timeout == 0 {
+		c.timeout = defaultTimeout
+	}
+	_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+}
+
+// unsetDeadLine unsets read/write deadline for the connection.
+func (c *TLSConn) unsetDeadLine() {
+	_ = c.conn.SetDeadline(time.Time{})
+}
+
+// write writes all the data to the connection with a timeout
+func (c *TLSConn) write(data []byte) error {
+	c.setDeadLine()
+	defer c.unsetDeadLine()
+	length, err := c.conn.Write(data)
+	if err != nil {
+		return err
+	}
+	if length < len(data) {
+		return fmt.Errorf("failed to write all bytes (%d bytes written, %d bytes expected)", length, len(data))
+	}
+	return nil
+}
+
+// Send sends data to the connection with a timeout.
+func (c *TLSConn) Send(data string) error {
+	return c.write([]byte(data))
+}
+
+// SendArray sends array data to connection
+func (c *TLSConn) SendArray(data []interface{}) error {
+	return c.write(types.ToByteSlice(data))
+}
+
+// SendHex sends hex data to connection
+func (c *TLSConn) SendHex(data string) error {
+	bin, err := hex.DecodeString(data)
+	if err != nil {
+		return err
+	}
+	return c.write(bin)
+}
+
+// Recv receives data from the connection with a timeout.
+// If N is 0, it will read all data sent by the server with 8MB limit.
+func (c *TLSConn) Recv(N int) ([]byte, error) {
+	c.setDeadLine()
+	defer c.unsetDeadLine()
+	if N == 0 {
+		// in utils we use -1 to indicate read all rather than 0
+		N = -1
+	}
+	bin, err := reader.ConnReadNWithTimeout(c.conn, int64(N), c.timeout)
+	if err != nil {
+		return []byte{}, errorutil.NewWithErr(err).Msgf("failed to read %d bytes", N)
+	}
+	return bin, nil
+}
+
+// RecvString receives data from the connection with a timeout
+// output is returned as a string.
+// If N is 0, it will read all data sent by the server with 8MB limit.
+func (c *TLSConn) RecvString(N int) (string, error) {
+	bin, err := c.Recv(N)
+	if err != nil {
+		return "", err
+	}
+	return string(bin), nil
+}
+
+// RecvHex receives data from the connection with a timeout
+// in hex format.
+// If N is 0, it will read all data sent by the server with 8MB limit.
+func (c *TLSConn) RecvHex(N int) (string, error) {
+	bin, err := c.Recv(N)
+	if err != nil {
+		return "", err
+	}
+	return hex.Dump(bin), nil
+}
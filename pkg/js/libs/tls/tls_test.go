@@ -0,0 +1,121 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// listenTLS serves a tls echo server supporting the h2 protocol
+func listenTLS(t *testing.T, config *tls.Config) (string, int) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	config.Certificates = []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}
+	config.NextProtos = []string{"h2", "http/1.1"}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	require.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	host, portValue, err := net.SplitHostPort(listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	return host, port
+}
+
+func TestTLSClientConnect(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	host, port := listenTLS(t, &tls.Config{})
+
+	client := &TLSClient{
+		ServerName: "example.com",
+		ALPN:       []string{"h2"},
+		MaxVersion: "tls12",
+		Ciphers:    []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+	}
+	conn, err := client.Connect(host, port)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	state := conn.State()
+	require.Equal(t, "TLS 1.2", state.Version)
+	require.Equal(t, "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", state.Cipher)
+	require.Equal(t, "h2", state.ALPN)
+	require.Equal(t, "example.com", state.ServerName)
+	require.Equal(t, []Certificate{{
+		Subject:   "CN=example.com",
+		Issuer:    "CN=example.com",
+		DNSNames:  []string{"example.com"},
+		NotBefore: "2024-01-01T00:00:00Z",
+		NotAfter:  "2034-01-01T00:00:00Z",
+	}}, state.Certificates)
+
+	require.Nil(t, conn.Send("ping"))
+	data, err := conn.RecvString(4)
+	require.Nil(t, err)
+	require.Equal(t, "ping", data)
+}
+
+func TestTLSClientConnectVersions(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	host, port := listenTLS(t, &tls.Config{MinVersion: tls.VersionTLS13})
+
+	// the handshake fails if the versions are refused
+	_, err := (&TLSClient{MaxVersion: "tls12"}).Connect(host, port)
+	require.NotNil(t, err)
+
+	conn, err := (&TLSClient{MinVersion: "TLS13"}).Connect(host, port)
+	require.Nil(t, err)
+	defer conn.Close()
+	require.Equal(t, "TLS 1.3", conn.State().Version)
+	require.Equal(t, "", conn.State().ALPN)
+}
+
+func TestTLSClientConfig(t *testing.T) {
+	_, err := (&TLSClient{MinVersion: "ssl3"}).tlsConfig("example.com")
+	require.EqualError(t, err, `invalid min version "ssl3"`)
+	_, err = (&TLSClient{MinVersion: "tls12", MaxVersion: "tls10"}).tlsConfig("example.com")
+	require.EqualError(t, err, `max version "tls10" is lower than min version`)
+	_, err = (&TLSClient{Ciphers: []string{"TLS_INVALID"}}).tlsConfig("example.com")
+	require.EqualError(t, err, `invalid cipher "TLS_INVALID"`)
+
+	config, err := (&TLSClient{Ciphers: []string{"TLS_RSA_WITH_RC4_128_SHA"}}).tlsConfig("127.0.0.1")
+	require.Nil(t, err)
+	require.Equal(t, []uint16{tls.TLS_RSA_WITH_RC4_128_SHA}, config.CipherSuites)
+	// the ip addresses are not sent as SNI
+	require.Equal(t, "", config.ServerName)
+}
@@ -0,0 +1,323 @@
+package vnc
+
+import (
+	"bytes"
+	"crypto/des"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+)
+
+// security types of the RFB protocol
+//
+// https://www.iana.org/assignments/rfb/rfb.xhtml#rfb-1
+const (
+	securityNone    = 1
+	securityVNCAuth = 2
+)
+
+// securityTypes are the names of the security types
+var securityTypes = map[byte]string{
+	1:   "None",
+	2:   "VNCAuth",
+	5:   "RA2",
+	6:   "RA2ne",
+	16:  "Tight",
+	17:  "Ultra",
+	18:  "TLS",
+	19:  "VeNCrypt",
+	20:  "SASL",
+	21:  "MD5",
+	22:  "xvp",
+	30:  "AppleRemoteDesktop",
+	113: "MSLogonII",
+}
+
+// maxPixels is the maximum number of pixels of the captured framebuffers
+const maxPixels = 4096 * 4096
+
+// maxMessageSize is the maximum size of the names and texts sent by the servers
+const maxMessageSize = 1024 * 1024
+
+var versionRegex = regexp.MustCompile(`^RFB (\d{3})\.(\d{3})\n$`)
+
+// rfbConn is a connection negotiating the RFB protocol
+type rfbConn struct {
+	conn  net.Conn
+	minor int
+	// types are the security types offered by the server
+	types []byte
+}
+
+// handshake negotiates the protocol version and reads the security types of the server
+func handshake(conn net.Conn) (*rfbConn, string, error) {
+	buf := make([]byte, 12)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, "", err
+	}
+	match := versionRegex.FindSubmatch(buf)
+	if match == nil {
+		return nil, "", errors.New("invalid rfb protocol version")
+	}
+	version := string(bytes.TrimSpace(buf))
+	c := &rfbConn{conn: conn}
+	c.minor, _ = strconv.Atoi(string(match[2]))
+	switch {
+	case c.minor >= 8:
+		c.minor = 8
+	case c.minor == 7:
+	default:
+		c.minor = 3
+	}
+	if _, err := fmt.Fprintf(conn, "RFB 003.%03d\n", c.minor); err != nil {
+		return nil, "", err
+	}
+
+	if c.minor == 3 {
+		// the server selects the security type with the 3.3 version
+		var securityType uint32
+		if err := binary.Read(conn, binary.BigEndian, &securityType); err != nil {
+			return nil, "", err
+		}
+		if securityType == 0 {
+			return nil, "", c.readFailure()
+		}
+		c.types = []byte{byte(securityType)}
+		return c, version, nil
+	}
+
+	count := make([]byte, 1)
+	if _, err := io.ReadFull(conn, count); err != nil {
+		return nil, "", err
+	}
+	if count[0] == 0 {
+		return nil, "", c.readFailure()
+	}
+	c.types = make([]byte, count[0])
+	if _, err := io.ReadFull(conn, c.types); err != nil {
+		return nil, "", err
+	}
+	return c, version, nil
+}
+
+// supports returns true if the server offers the security type
+func (c *rfbConn) supports(securityType byte) bool {
+	return bytes.IndexByte(c.types, securityType) != -1
+}
+
+// authenticate authenticates with the security type, the password is used by the VNC authentication
+func (c *rfbConn) authenticate(securityType byte, password string) error {
+	if !c.supports(securityType) {
+		return fmt.Errorf("security type %s is not supported by the server", securityTypeName(securityType))
+	}
+	if c.minor >= 7 {
+		if _, err := c.conn.Write([]byte{securityType}); err != nil {
+			return err
+		}
+	}
+	if securityType == securityVNCAuth {
+		challenge := make([]byte, 16)
+		if _, err := io.ReadFull(c.conn, challenge); err != nil {
+			return err
+		}
+		response, err := vncAuthResponse(challenge, password)
+		if err != nil {
+			return err
+		}
+		if _, err := c.conn.Write(response); err != nil {
+			return err
+		}
+	} else if c.minor < 8 {
+		// the result of the None security type is sent from the 3.8 version
+		return nil
+	}
+
+	var result uint32
+	if err := binary.Read(c.conn, binary.BigEndian, &result); err != nil {
+		return err
+	}
+	if result != 0 {
+		if c.minor >= 8 {
+			return c.readFailure()
+		}
+		return errors.New("authentication failed")
+	}
+	return nil
+}
+
+// readFailure reads the reason of a failure sent by the server
+func (c *rfbConn) readFailure() error {
+	var length uint32
+	if err := binary.Read(c.conn, binary.BigEndian, &length); err != nil || length > maxMessageSize {
+		return errors.New("connection refused by the server")
+	}
+	reason := make([]byte, length)
+	if _, err := io.ReadFull(c.conn, reason); err != nil {
+		return errors.New("connection refused by the server")
+	}
+	return fmt.Errorf("connection refused by the server: %s", reason)
+}
+
+// vncAuthResponse encrypts the challenge with the password using the bit-reversed DES key of the VNC authentication
+func vncAuthResponse(challenge []byte, password string) ([]byte, error) {
+	key := make([]byte, 8)
+	copy(key, password)
+	for i, b := range key {
+		var reversed byte
+		for bit := 0; bit < 8; bit++ {
+			reversed |= (b >> bit & 1) << (7 - bit)
+		}
+		key[i] = reversed
+	}
+	block, err := des.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]byte, len(challenge))
+	for i := 0; i < len(challenge); i += des.BlockSize {
+		block.Encrypt(response[i:], challenge[i:])
+	}
+	return response, nil
+}
+
+// captureFramebuffer initializes the session and returns the framebuffer
+// requested with the raw encoding along with the name of the desktop
+func (c *rfbConn) captureFramebuffer() (*image.RGBA, string, error) {
+	// shared session to keep the other clients connected
+	if _, err := c.conn.Write([]byte{1}); err != nil {
+		return nil, "", err
+	}
+	var serverInit struct {
+		Width, Height uint16
+		PixelFormat   [16]byte
+		NameLength    uint32
+	}
+	if err := binary.Read(c.conn, binary.BigEndian, &serverInit); err != nil {
+		return nil, "", err
+	}
+	if serverInit.NameLength > maxMessageSize {
+		return nil, "", errors.New("invalid desktop name length")
+	}
+	name := make([]byte, serverInit.NameLength)
+	if _, err := io.ReadFull(c.conn, name); err != nil {
+		return nil, "", err
+	}
+	width, height := int(serverInit.Width), int(serverInit.Height)
+	if width == 0 || height == 0 || width*height > maxPixels {
+		return nil, "", fmt.Errorf("unsupported framebuffer size %dx%d", width, height)
+	}
+
+	request := []byte{
+		// SetPixelFormat with 32 bits little endian true colour pixels
+		0, 0, 0, 0,
+		32, 24, 0, 1, 0, 255, 0, 255, 0, 255, 16, 8, 0, 0, 0, 0,
+		// SetEncodings with the raw encoding
+		2, 0, 0, 1, 0, 0, 0, 0,
+		// FramebufferUpdateRequest of the whole framebuffer
+		3, 0, 0, 0, 0, 0,
+	}
+	request = binary.BigEndian.AppendUint16(request, serverInit.Width)
+	request = binary.BigEndian.AppendUint16(request, serverInit.Height)
+	if _, err := c.conn.Write(request); err != nil {
+		return nil, "", err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for {
+		messageType := make([]byte, 1)
+		if _, err := io.ReadFull(c.conn, messageType); err != nil {
+			return nil, "", err
+		}
+		switch messageType[0] {
+		case 0: // FramebufferUpdate
+			if err := c.readFramebufferUpdate(img); err != nil {
+				return nil, "", err
+			}
+			return img, string(name), nil
+		case 1: // SetColourMapEntries
+			header := make([]byte, 5)
+			if _, err := io.ReadFull(c.conn, header); err != nil {
+				return nil, "", err
+			}
+			if _, err := io.CopyN(io.Discard, c.conn, int64(binary.BigEndian.Uint16(header[3:]))*6); err != nil {
+				return nil, "", err
+			}
+		case 2: // Bell
+		case 3: // ServerCutText
+			header := make([]byte, 7)
+			if _, err := io.ReadFull(c.conn, header); err != nil {
+				return nil, "", err
+			}
+			length := binary.BigEndian.Uint32(header[3:])
+			if length > maxMessageSize {
+				return nil, "", errors.New("invalid server cut text length")
+			}
+			if _, err := io.CopyN(io.Discard, c.conn, int64(length)); err != nil {
+				return nil, "", err
+			}
+		default:
+			return nil, "", fmt.Errorf("unknown server message type %d", messageType[0])
+		}
+	}
+}
+
+// readFramebufferUpdate reads the raw rectangles of a framebuffer update into the image
+func (c *rfbConn) readFramebufferUpdate(img *image.RGBA) error {
+	header := make([]byte, 3)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return err
+	}
+	bounds := img.Bounds()
+	for i := 0; i < int(binary.BigEndian.Uint16(header[1:])); i++ {
+		var rect struct {
+			X, Y, Width, Height uint16
+			Encoding            int32
+		}
+		if err := binary.Read(c.conn, binary.BigEndian, &rect); err != nil {
+			return err
+		}
+		if rect.Encoding != 0 {
+			return fmt.Errorf("unsupported encoding %d", rect.Encoding)
+		}
+		area := image.Rect(int(rect.X), int(rect.Y), int(rect.X)+int(rect.Width), int(rect.Y)+int(rect.Height))
+		if !area.In(bounds) {
+			return errors.New("invalid framebuffer rectangle")
+		}
+		pixels := make([]byte, area.Dx()*area.Dy()*4)
+		if _, err := io.ReadFull(c.conn, pixels); err != nil {
+			return err
+		}
+		for y := 0; y < area.Dy(); y++ {
+			for x := 0; x < area.Dx(); x++ {
+				pixel := pixels[(y*area.Dx()+x)*4:]
+				offset := img.PixOffset(area.Min.X+x, area.Min.Y+y)
+				// blue, green and red in the little endian pixels
+				img.Pix[offset], img.Pix[offset+1], img.Pix[offset+2], img.Pix[offset+3] = pixel[2], pixel[1], pixel[0], 255
+			}
+		}
+	}
+	return nil
+}
+
+// encodePNG returns the png encoded image
+func encodePNG(img image.Image) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// securityTypeName returns the name of the security type
+func securityTypeName(securityType byte) string {
+	if name, ok := securityTypes[securityType]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown(%d)", securityType)
+}
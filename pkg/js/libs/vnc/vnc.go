@@ -2,6 +2,7 @@ package vnc
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -11,6 +12,13 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
+const (
+	// defaultTimeout is the timeout of the handshakes
+	defaultTimeout = 5 * time.Second
+	// screenshotTimeout is the timeout of the framebuffer captures
+	screenshotTimeout = 30 * time.Second
+)
+
 // VNCClient is a minimal VNC client for nuclei scripts.
 type VNCClient struct{}
 
@@ -45,3 +53,120 @@ func (c *VNCClient) IsVNC(host string, port int) (IsVNCResponse, error) {
 	resp.IsVNC = true
 	return resp, nil
 }
+
+// SecurityTypesResponse is the response from the GetSecurityTypes function.
+type SecurityTypesResponse struct {
+	// Version is the protocol version sent by the server, ex: RFB 003.008
+	Version string
+	// SecurityTypes are the names of the security types offered by the server,
+	// ex: None, VNCAuth or VeNCrypt
+	SecurityTypes []string
+}
+
+// ScreenshotResponse is the response from the Screenshot function.
+type ScreenshotResponse struct {
+	// Name is the name of the desktop
+	Name   string
+	Width  int
+	Height int
+	// PNG is the png encoded framebuffer
+	PNG []byte
+}
+
+// GetSecurityTypes returns the protocol version and the security types
+// offered by a VNC server, ex: None, VNCAuth or VeNCrypt.
+func (c *VNCClient) GetSecurityTypes(host string, port int) (SecurityTypesResponse, error) {
+	resp := SecurityTypesResponse{}
+	conn, err := connect(host, port)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.Close()
+
+	rfb, version, err := handshake(conn)
+	if err != nil {
+		return resp, err
+	}
+	resp.Version = version
+	for _, securityType := range rfb.types {
+		resp.SecurityTypes = append(resp.SecurityTypes, securityTypeName(securityType))
+	}
+	return resp, nil
+}
+
+// IsPasswordless checks if a VNC server accepts connections without authentication.
+// It returns true if the server offers the None security type and accepts it.
+func (c *VNCClient) IsPasswordless(host string, port int) (bool, error) {
+	conn, err := connect(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	rfb, _, err := handshake(conn)
+	if err != nil {
+		return false, err
+	}
+	if !rfb.supports(securityNone) {
+		return false, nil
+	}
+	if err := rfb.authenticate(securityNone, ""); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Screenshot captures the framebuffer of a VNC server as a png image,
+// the VNC authentication is used if a password is given.
+// The image can be attached to the results with the saveArtifact function.
+func (c *VNCClient) Screenshot(host string, port int, password string) (ScreenshotResponse, error) {
+	resp := ScreenshotResponse{}
+	conn, err := connect(host, port)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(screenshotTimeout))
+
+	rfb, _, err := handshake(conn)
+	if err != nil {
+		return resp, err
+	}
+	securityType := byte(securityNone)
+	if password != "" {
+		securityType = securityVNCAuth
+	}
+	if err := rfb.authenticate(securityType, password); err != nil {
+		return resp, err
+	}
+	img, name, err := rfb.captureFramebuffer()
+	if err != nil {
+		return resp, err
+	}
+	data, err := encodePNG(img)
+	if err != nil {
+		return resp, err
+	}
+	resp.Name = name
+	resp.Width = img.Bounds().Dx()
+	resp.Height = img.Bounds().Dy()
+	resp.PNG = data
+	return resp, nil
+}
+
+// connect opens a connection to the VNC server with a timeout
+func connect(host string, port int) (net.Conn, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if host == "" || port <= 0 {
+		return nil, fmt.Errorf("invalid host or port")
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+	return conn, nil
+}
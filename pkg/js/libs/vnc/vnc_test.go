@@ -0,0 +1,160 @@
+package vnc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/png"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// rfbServer is a fake VNC server sending a 2x1 framebuffer
+type rfbServer struct {
+	version  string
+	types    []byte
+	password string
+}
+
+func (s *rfbServer) listen(t *testing.T) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = s.serve(conn)
+			}()
+		}
+	}()
+
+	host, portValue, err := net.SplitHostPort(listener.Addr().String())
+	require.Nil(t, err)
+	port, _ := strconv.Atoi(portValue)
+	return host, port
+}
+
+func (s *rfbServer) serve(conn net.Conn) error {
+	if _, err := conn.Write([]byte(s.version)); err != nil {
+		return err
+	}
+	version := make([]byte, 12)
+	if _, err := io.ReadFull(conn, version); err != nil {
+		return err
+	}
+	v38 := string(version) == "RFB 003.008\n"
+	if string(version) == "RFB 003.003\n" {
+		_ = binary.Write(conn, binary.BigEndian, uint32(s.types[0]))
+	} else {
+		_, _ = conn.Write(append([]byte{byte(len(s.types))}, s.types...))
+	}
+
+	selected := []byte{s.types[0]}
+	if string(version) != "RFB 003.003\n" {
+		if _, err := io.ReadFull(conn, selected); err != nil {
+			return err
+		}
+	}
+	result := uint32(0)
+	if selected[0] == securityVNCAuth {
+		challenge := bytes.Repeat([]byte{0x42}, 16)
+		_, _ = conn.Write(challenge)
+		response := make([]byte, 16)
+		if _, err := io.ReadFull(conn, response); err != nil {
+			return err
+		}
+		expected, _ := vncAuthResponse(challenge, s.password)
+		if !bytes.Equal(response, expected) {
+			result = 1
+		}
+	}
+	if v38 || selected[0] == securityVNCAuth {
+		_ = binary.Write(conn, binary.BigEndian, result)
+	}
+	if result != 0 {
+		if v38 {
+			_ = binary.Write(conn, binary.BigEndian, uint32(len("invalid password")))
+			_, _ = conn.Write([]byte("invalid password"))
+		}
+		return nil
+	}
+
+	// ClientInit, ServerInit and the client requests
+	if _, err := io.ReadFull(conn, make([]byte, 1)); err != nil {
+		return err
+	}
+	serverInit := []byte{0, 2, 0, 1}
+	serverInit = append(serverInit, make([]byte, 16)...)
+	serverInit = append(serverInit, 0, 0, 0, 4)
+	_, _ = conn.Write(append(serverInit, "test"...))
+	if _, err := io.ReadFull(conn, make([]byte, 20+8+10)); err != nil {
+		return err
+	}
+	// a bell is skipped before the framebuffer update
+	_, _ = conn.Write([]byte{2})
+	update := []byte{0, 0, 0, 1, 0, 0, 0, 0, 0, 2, 0, 1, 0, 0, 0, 0}
+	// red and blue little endian pixels
+	update = append(update, 0, 0, 255, 0, 255, 0, 0, 0)
+	_, err := conn.Write(update)
+	return err
+}
+
+func TestVNCClientSecurityTypes(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	host, port := (&rfbServer{version: "RFB 003.008\n", types: []byte{19, securityVNCAuth, 99}}).listen(t)
+
+	client := &VNCClient{}
+	resp, err := client.GetSecurityTypes(host, port)
+	require.Nil(t, err)
+	require.Equal(t, "RFB 003.008", resp.Version)
+	require.Equal(t, []string{"VeNCrypt", "VNCAuth", "Unknown(99)"}, resp.SecurityTypes)
+
+	passwordless, err := client.IsPasswordless(host, port)
+	require.Nil(t, err)
+	require.False(t, passwordless)
+}
+
+func TestVNCClientIsPasswordless(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	client := &VNCClient{}
+	for _, version := range []string{"RFB 003.003\n", "RFB 003.007\n", "RFB 003.008\n", "RFB 003.889\n"} {
+		host, port := (&rfbServer{version: version, types: []byte{securityNone}}).listen(t)
+		passwordless, err := client.IsPasswordless(host, port)
+		require.Nil(t, err, version)
+		require.True(t, passwordless, version)
+	}
+}
+
+func TestVNCClientScreenshot(t *testing.T) {
+	require.Nil(t, protocolstate.Init(types.DefaultOptions()))
+	host, port := (&rfbServer{version: "RFB 003.008\n", types: []byte{securityVNCAuth}, password: "secret"}).listen(t)
+
+	client := &VNCClient{}
+	_, err := client.Screenshot(host, port, "")
+	require.EqualError(t, err, "security type None is not supported by the server")
+	_, err = client.Screenshot(host, port, "invalid")
+	require.EqualError(t, err, "connection refused by the server: invalid password")
+
+	resp, err := client.Screenshot(host, port, "secret")
+	require.Nil(t, err)
+	require.Equal(t, "test", resp.Name)
+	require.Equal(t, 2, resp.Width)
+	require.Equal(t, 1, resp.Height)
+
+	img, err := png.Decode(bytes.NewReader(resp.PNG))
+	require.Nil(t, err)
+	r, g, b, _ := img.At(0, 0).RGBA()
+	require.Equal(t, []uint32{0xffff, 0, 0}, []uint32{r, g, b})
+	r, g, b, _ = img.At(1, 0).RGBA()
+	require.Equal(t, []uint32{0, 0, 0xffff}, []uint32{r, g, b})
+}
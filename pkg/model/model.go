@@ -119,4 +119,14 @@ type Classification struct {
 	// examples:
 	//   - value: "\"cpe:/a:vendor:product:version\""
 	CPE string `json:"cpe,omitempty" yaml:"cpe,omitempty" jsonschema:"title=cpe for the template,description=CPE for the template,example=cpe:/a:vendor:product:version"`
+	// description: |
+	//   OWASP Top 10 categories for the template.
+	// examples:
+	//   - value: "\"A03:2021-Injection\""
+	OWASP stringslice.StringSlice `json:"owasp,omitempty" yaml:"owasp,omitempty" jsonschema:"title=owasp categories for the template,description=OWASP Top 10 categories for the template,example=A03:2021-Injection"`
+	// description: |
+	//   MITRE ATT&CK technique IDs for the template.
+	// examples:
+	//   - value: "\"T1190\""
+	AttackID stringslice.StringSlice `json:"attack-id,omitempty" yaml:"attack-id,omitempty" jsonschema:"title=att&ck technique ids for the template,description=MITRE ATT&CK technique IDs for the template,example=T1190"`
 }
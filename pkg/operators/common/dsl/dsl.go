@@ -1,13 +1,20 @@
 package dsl
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Knetic/govaluate"
 	"github.com/miekg/dns"
 	"github.com/projectdiscovery/dsl"
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/kvstore"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/dns/dnsclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	sliceutil "github.com/projectdiscovery/utils/slice"
@@ -19,6 +26,15 @@ var (
 	FunctionNames   []string
 	// knownPorts is a list of known ports for protocols implemented in nuclei
 	knowPorts = []string{"80", "443", "8080", "8081", "8443", "53"}
+	// customFunctions contains the helper functions registered with AddHelperFunction
+	customFunctions   = make(map[string]govaluate.ExpressionFunction)
+	functionNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// customFunctionsMutex serializes the registrations of helper functions
+	customFunctionsMutex sync.Mutex
+	// customFunctionsSealed is set once the engine is initialized, HelperFunctions
+	// is read without locking by the expressions and must not be modified afterwards
+	customFunctionsSealed bool
 )
 
 func init() {
@@ -113,15 +129,102 @@ func init() {
 		return port, nil
 	}))
 
+	_ = dsl.AddFunction(dsl.NewWithMultipleSignatures("kv_set", []string{
+		"(key string, value string) string",
+		"(key string, value string, ttl int) string",
+	}, false, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 && len(args) != 3 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		var ttl int
+		if len(args) == 3 {
+			var err error
+			if ttl, err = strconv.Atoi(types.ToString(args[2])); err != nil {
+				return nil, fmt.Errorf("invalid ttl: %s", err)
+			}
+		}
+		value := types.ToString(args[1])
+		if err := kvstore.Default.Set(types.ToString(args[0]), value, time.Duration(ttl)*time.Second); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}))
+	_ = dsl.AddFunction(dsl.NewWithSingleSignature("kv_get", "(key string) string", false, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		value, _ := kvstore.Default.Get(types.ToString(args[0]))
+		return value, nil
+	}))
+	_ = dsl.AddFunction(dsl.NewWithSingleSignature("kv_delete", "(key string) bool", false, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		kvstore.Default.Delete(types.ToString(args[0]))
+		return true, nil
+	}))
+
 	dsl.PrintDebugCallback = func(args ...interface{}) error {
-		gologger.Info().Msgf("print_debug value: %s", fmt.Sprint(args))
+		gologger.Info().Msgf("print_debug value: %s", fmt.Sprint(args...))
 		return nil
 	}
 
 	HelperFunctions = dsl.HelperFunctions()
+	for name, function := range HelperFunctions {
+		if fips.IsRestrictedFunction(name) {
+			HelperFunctions[name] = restrictToFIPS(name, function)
+		}
+	}
 	FunctionNames = dsl.GetFunctionNames(HelperFunctions)
 }
 
+// restrictToFIPS wraps a helper function to fail when called with
+// algorithms not approved in fips mode
+func restrictToFIPS(name string, function govaluate.ExpressionFunction) govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		if err := fips.CheckFunction(name, args...); err != nil {
+			return nil, err
+		}
+		return function(args...)
+	}
+}
+
+// AddHelperFunction registers an additional helper function which can be used
+// in template expressions. It must be called before the engine is initialized,
+// registering the same name again replaces the previously registered function.
+func AddHelperFunction(name string, function govaluate.ExpressionFunction) error {
+	customFunctionsMutex.Lock()
+	defer customFunctionsMutex.Unlock()
+
+	if customFunctionsSealed {
+		return fmt.Errorf("helper function %q must be registered before the engine is initialized", name)
+	}
+	if function == nil {
+		return errors.New("helper function implementation is required")
+	}
+	if !functionNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid helper function name %q", name)
+	}
+	if _, ok := customFunctions[name]; !ok {
+		if _, ok := HelperFunctions[name]; ok {
+			return fmt.Errorf("helper function %q is already defined", name)
+		}
+	}
+	customFunctions[name] = function
+	HelperFunctions[name] = function
+	FunctionNames = dsl.GetFunctionNames(HelperFunctions)
+	return nil
+}
+
+// SealHelperFunctions prevents the registration of helper functions, it is
+// called when the engine is initialized
+func SealHelperFunctions() {
+	customFunctionsMutex.Lock()
+	defer customFunctionsMutex.Unlock()
+
+	customFunctionsSealed = true
+}
+
 type CompilationError struct {
 	DslSignature string
 	WrappedError error
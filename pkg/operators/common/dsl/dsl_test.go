@@ -48,3 +48,36 @@ func testDslExpressionScenarios(t *testing.T, dslExpressions map[string]interfac
 		})
 	}
 }
+
+func TestAddHelperFunction(t *testing.T) {
+	err := AddHelperFunction("internal_asset", func(args ...interface{}) (interface{}, error) {
+		return fmt.Sprintf("asset-%v", args[0]), nil
+	})
+	require.Nil(t, err, "could not add helper function")
+	require.Equal(t, "asset-1", evaluateExpression(t, `internal_asset(1)`))
+	require.Contains(t, FunctionNames, "internal_asset")
+
+	err = AddHelperFunction("internal_asset", func(args ...interface{}) (interface{}, error) {
+		return "replaced", nil
+	})
+	require.Nil(t, err, "could not replace helper function")
+	require.Equal(t, "replaced", evaluateExpression(t, `internal_asset(1)`))
+
+	require.NotNil(t, AddHelperFunction("md5", func(args ...interface{}) (interface{}, error) { return nil, nil }), "could not get error for builtin function")
+	require.NotNil(t, AddHelperFunction("invalid-name", func(args ...interface{}) (interface{}, error) { return nil, nil }), "could not get error for invalid name")
+	require.NotNil(t, AddHelperFunction("empty", nil), "could not get error for nil function")
+
+	SealHelperFunctions()
+	defer func() { customFunctionsSealed = false }()
+	require.NotNil(t, AddHelperFunction("late_asset", func(args ...interface{}) (interface{}, error) { return nil, nil }), "could not get error for registration after init")
+}
+
+func TestKVExpressions(t *testing.T) {
+	require.Equal(t, "secret", evaluateExpression(t, `kv_set("token", "secret")`))
+	require.Equal(t, "secret", evaluateExpression(t, `kv_get("token")`))
+	require.Equal(t, true, evaluateExpression(t, `kv_delete("token")`))
+	require.Equal(t, "", evaluateExpression(t, `kv_get("token")`))
+
+	require.Equal(t, "value", evaluateExpression(t, `kv_set("session", "value", 60)`))
+	require.Equal(t, "value", evaluateExpression(t, `kv_get("session")`))
+}
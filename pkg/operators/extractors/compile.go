@@ -8,6 +8,7 @@ import (
 	"github.com/Knetic/govaluate"
 	"github.com/itchyny/gojq"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/plugins"
 )
 
 // CompileExtractors performs the initial setup operation on an extractor
@@ -18,6 +19,9 @@ func (e *Extractor) CompileExtractors() error {
 		return fmt.Errorf("unknown extractor type specified: %s", e.Type)
 	}
 	e.extractorType = computedType
+	if computedType == PluginExtractor && !plugins.HasExtractor(e.Plugin) {
+		return fmt.Errorf("plugin extractor %q is not loaded", e.Plugin)
+	}
 	// Compile the regexes
 	for _, regex := range e.Regex {
 		compiled, err := regexp.Compile(regex)
@@ -8,6 +8,8 @@ import (
 	"github.com/antchfx/htmlquery"
 	"github.com/antchfx/xmlquery"
 
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/plugins"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
 
@@ -190,3 +192,19 @@ func (e *Extractor) ExtractDSL(data map[string]interface{}) map[string]struct{}
 	e.SaveToFile(results)
 	return results
 }
+
+// ExtractPlugin extracts values from a corpus with a plugin extractor
+func (e *Extractor) ExtractPlugin(corpus string) map[string]struct{} {
+	results := make(map[string]struct{})
+
+	values, err := plugins.Extract(e.Plugin, corpus, e.PluginArgs)
+	if err != nil {
+		gologger.Warning().Msgf("plugin extractor %s failed: %s\n", e.Plugin, err)
+		return results
+	}
+	for _, value := range values {
+		results[value] = struct{}{}
+	}
+	e.SaveToFile(results)
+	return results
+}
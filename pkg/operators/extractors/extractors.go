@@ -120,6 +120,14 @@ type Extractor struct {
 	// description: |
 	//  ToFile (to) saves extracted requests to file and if file is present values are appended to file.
 	ToFile string `yaml:"to,omitempty" json:"to,omitempty" jsonschema:"title=save extracted values to file,description=save extracted values to file"`
+	// description: |
+	//   Plugin is the name of the plugin extractor to extract the part with.
+	//
+	//   Plugin extractors are provided by the plugins loaded with -plugin.
+	Plugin string `yaml:"plugin,omitempty" json:"plugin,omitempty" jsonschema:"title=plugin extractor to use,description=Name of the plugin extractor to extract the part with"`
+	// description: |
+	//   PluginArgs contains the arguments passed to the plugin extractor.
+	PluginArgs map[string]string `yaml:"plugin-args,omitempty" json:"plugin-args,omitempty" jsonschema:"title=arguments of the plugin extractor,description=Arguments passed to the plugin extractor"`
 }
 
 // SaveToFile saves extracted values to file if `to` is present and valid
@@ -9,6 +9,8 @@ import (
 	"github.com/Knetic/govaluate"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/plugins"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yara"
 )
 
 // CompileMatchers performs the initial setup operation on a matcher
@@ -37,6 +39,10 @@ func (matcher *Matcher) CompileMatchers() error {
 		return err
 	}
 
+	if matcher.GetType() == PluginMatcher && !plugins.HasMatcher(matcher.Plugin) {
+		return fmt.Errorf("plugin matcher %q is not loaded", matcher.Plugin)
+	}
+
 	// By default, match on body if user hasn't provided any specific items
 	if matcher.Part == "" && matcher.GetType() != DSLMatcher {
 		matcher.Part = "body"
@@ -60,6 +66,15 @@ func (matcher *Matcher) CompileMatchers() error {
 		}
 	}
 
+	// Compile the yara rules
+	for _, source := range matcher.Yara {
+		compiled, err := yara.Compile(source)
+		if err != nil {
+			return fmt.Errorf("could not compile yara rules: %s", err)
+		}
+		matcher.yaraCompiled = append(matcher.yaraCompiled, compiled)
+	}
+
 	// Compile the dsl expressions
 	for _, dslExpression := range matcher.DSL {
 		compiledExpression, err := govaluate.NewEvaluableExpressionWithFunctions(dslExpression, dsl.HelperFunctions)
@@ -11,6 +11,7 @@ import (
 	dslRepo "github.com/projectdiscovery/dsl"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/plugins"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 )
@@ -236,6 +237,59 @@ func (matcher *Matcher) MatchXPath(corpus string) bool {
 	return matcher.MatchHTML(corpus)
 }
 
+// MatchPlugin matches a corpus with a plugin matcher
+func (matcher *Matcher) MatchPlugin(corpus string) (bool, []string) {
+	matched, snippets, err := plugins.Match(matcher.Plugin, corpus, matcher.PluginArgs)
+	if err != nil {
+		gologger.Warning().Msgf("plugin matcher %s failed: %s\n", matcher.Plugin, err)
+		return false, []string{}
+	}
+	return matched, snippets
+}
+
+// MatchYara matches the yara rules against a corpus
+func (matcher *Matcher) MatchYara(corpus string) (bool, []string) {
+	var matchedStrings []string
+	var matchedSources int
+	// Iterate over all the compiled rules sources
+	for i, rules := range matcher.yaraCompiled {
+		matches := rules.Scan([]byte(corpus))
+		if len(matches) == 0 {
+			// If we are in an AND request and a match failed,
+			// return false as the AND condition fails on any single mismatch.
+			switch matcher.condition {
+			case ANDCondition:
+				return false, []string{}
+			case ORCondition:
+				continue
+			}
+		}
+
+		var currentMatches []string
+		for _, match := range matches {
+			for _, matchString := range match.Strings {
+				currentMatches = append(currentMatches, string(matchString.Data))
+			}
+		}
+		// If the condition was an OR, return on the first match.
+		if matcher.condition == ORCondition && !matcher.MatchAll {
+			return true, currentMatches
+		}
+
+		matchedStrings = append(matchedStrings, currentMatches...)
+		matchedSources++
+
+		// If we are at the end of the rules, return with true
+		if len(matcher.yaraCompiled)-1 == i && !matcher.MatchAll {
+			return true, matchedStrings
+		}
+	}
+	if matchedSources > 0 && matcher.MatchAll {
+		return true, matchedStrings
+	}
+	return false, []string{}
+}
+
 // MatchHTML matches items from HTML using XPath selectors
 func (matcher *Matcher) MatchHTML(corpus string) bool {
 	doc, err := htmlquery.Parse(strings.NewReader(corpus))
@@ -209,3 +209,20 @@ func TestMatcher_MatchXPath_XML(t *testing.T) {
 	isMatched = m.MatchXPath("<h1> not right <q id=2/>notvalid")
 	require.False(t, isMatched, "Invalid xpath did not return false")
 }
+
+func TestMatcher_MatchYara(t *testing.T) {
+	webshell := `rule webshell { strings: $eval = "eval(" nocase $input = /\$_(GET|POST|REQUEST)/ condition: all of them }`
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: YaraMatcher}, Condition: "and", Yara: []string{webshell, `rule php { condition: uint32be(0) == 0x3c3f7068 }`}}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+
+	isMatched, matched := m.MatchYara("<?php @EVAL($_POST['cmd']); ?>")
+	require.True(t, isMatched, "Could not match valid yara AND condition")
+	require.Equal(t, []string{"EVAL(", "$_POST"}, matched)
+
+	isMatched, _ = m.MatchYara("<?php echo $_GET['name']; ?>")
+	require.False(t, isMatched, "Could match invalid yara AND condition")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: YaraMatcher}, Yara: []string{"rule invalid { condition: $a }"}}
+	require.Error(t, m.CompileMatchers(), "could compile invalid yara rules")
+}
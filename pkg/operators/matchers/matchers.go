@@ -4,6 +4,8 @@ import (
 	"regexp"
 
 	"github.com/Knetic/govaluate"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yara"
 )
 
 // Matcher is used to match a part in the output from a protocol.
@@ -120,6 +122,24 @@ type Matcher struct {
 	//   - false
 	//   - true
 	MatchAll bool `yaml:"match-all,omitempty" json:"match-all,omitempty" jsonschema:"title=match all values,description=match all matcher values ignoring condition"`
+	// description: |
+	//   Plugin is the name of the plugin matcher to match the part with.
+	//
+	//   Plugin matchers are provided by the plugins loaded with -plugin.
+	Plugin string `yaml:"plugin,omitempty" json:"plugin,omitempty" jsonschema:"title=plugin matcher to use,description=Name of the plugin matcher to match the part with"`
+	// description: |
+	//   PluginArgs contains the arguments passed to the plugin matcher.
+	PluginArgs map[string]string `yaml:"plugin-args,omitempty" json:"plugin-args,omitempty" jsonschema:"title=arguments of the plugin matcher,description=Arguments passed to the plugin matcher"`
+	// description: |
+	//   Yara contains YARA rules sources matched against the response part.
+	//
+	//   A source matches if any of its rules matches. Text, hex and regex strings
+	//   and conditions without modules are supported.
+	// examples:
+	//   - name: Match php webshells evaluating request parameters
+	//     value: >
+	//       []string{"rule webshell { strings: $eval = \"eval(\" nocase $input = /\\$_(GET|POST|REQUEST)/ condition: all of them }"}
+	Yara []string `yaml:"yara,omitempty" json:"yara,omitempty" jsonschema:"title=yara rules to match in response,description=Yara contains YARA rules sources matched against the response part"`
 
 	// cached data for the compiled matcher
 	condition     ConditionType // todo: this field should be the one used for overridden marshal ops
@@ -127,6 +147,7 @@ type Matcher struct {
 	binaryDecoded []string
 	regexCompiled []*regexp.Regexp
 	dslCompiled   []*govaluate.EvaluableExpression
+	yaraCompiled  []*yara.Rules
 }
 
 // ConditionType is the type of condition for matcher
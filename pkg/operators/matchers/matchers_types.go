@@ -27,6 +27,10 @@ const (
 	DSLMatcher
 	// name:xpath
 	XPathMatcher
+	// name:plugin
+	PluginMatcher
+	// name:yara
+	YaraMatcher
 	limit
 )
 
@@ -39,6 +43,8 @@ var MatcherTypes = map[MatcherType]string{
 	BinaryMatcher: "binary",
 	DSLMatcher:    "dsl",
 	XPathMatcher:  "xpath",
+	PluginMatcher: "plugin",
+	YaraMatcher:   "yara",
 }
 
 // GetType returns the type of the matcher
@@ -41,6 +41,10 @@ func (matcher *Matcher) Validate() error {
 		expectedFields = append(commonExpectedFields, "Regex", "Part", "Encoding", "CaseInsensitive")
 	case XPathMatcher:
 		expectedFields = append(commonExpectedFields, "XPath", "Part")
+	case PluginMatcher:
+		expectedFields = append(commonExpectedFields, "Plugin", "PluginArgs", "Part")
+	case YaraMatcher:
+		expectedFields = append(commonExpectedFields, "Yara", "Part")
 	}
 
 	if err = checkFields(matcher, matcherMap, expectedFields...); err != nil {
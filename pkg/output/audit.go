@@ -0,0 +1,193 @@
+package output
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// maxAuditEntrySize is the maximum size of an audit log entry read back from disk
+const maxAuditEntrySize = 1024 * 1024
+
+// RequestEvent is a request made by a template
+type RequestEvent struct {
+	// TemplateID is the id of the template making the request
+	TemplateID string
+	// Template is the template (path or id) written in the trace log
+	Template string
+	// Input is the target of the request
+	Input string
+	// Type is the protocol of the request
+	Type string
+	// BytesSent is the number of bytes sent by the request if known
+	BytesSent int
+	// BytesReceived is the number of bytes received in response if known
+	BytesReceived int
+	// Error is the error returned by the request if any
+	Error error
+	// Request is the raw request if available
+	Request string
+	// Response is the raw response to the request if available
+	Response string
+}
+
+// templateWriter is an output writer setting the template id of the requests it logs
+type templateWriter struct {
+	Writer
+
+	templateID string
+}
+
+// WithTemplateID returns an output writer logging the requests of a template with its id
+func WithTemplateID(writer Writer, templateID string) Writer {
+	return &templateWriter{Writer: writer, templateID: templateID}
+}
+
+// Request logs the request with the template id to the underlying writer
+func (w *templateWriter) Request(templatePath, input, requestType string, err error) {
+	w.LogRequest(&RequestEvent{Template: templatePath, Input: input, Type: requestType, Error: err})
+}
+
+// LogRequest logs the request with the template id to the underlying writer
+func (w *templateWriter) LogRequest(event *RequestEvent) {
+	if event.TemplateID == "" {
+		event.TemplateID = w.templateID
+	}
+	w.Writer.LogRequest(event)
+}
+
+// AuditEntry is an outgoing request written in the audit log
+type AuditEntry struct {
+	Timestamp     time.Time `json:"timestamp"`
+	TemplateID    string    `json:"template-id"`
+	Type          string    `json:"type"`
+	Target        string    `json:"target"`
+	BytesSent     int       `json:"bytes-sent"`
+	BytesReceived int       `json:"bytes-received"`
+	Error         string    `json:"error,omitempty"`
+	// Request and Response are the raw request and response, only written with audit-log-raw
+	Request  string `json:"request,omitempty"`
+	Response string `json:"response,omitempty"`
+	// Signature is the hmac-sha256 of the entry chained with the
+	// signature of the previous one, empty if the log is not signed
+	Signature string `json:"signature,omitempty"`
+}
+
+// auditLog is an append-only audit log of the outgoing requests
+type auditLog struct {
+	mu       sync.Mutex
+	file     *os.File
+	key      []byte
+	previous string
+}
+
+// newAuditLog opens the audit log appending to it if it exists, entries are signed
+// if a key is given continuing the signature chain of the existing entries
+func newAuditLog(file string, key string) (*auditLog, error) {
+	log := &auditLog{key: []byte(key)}
+	if key != "" {
+		if err := ReadAuditLog(file, func(entry *AuditEntry) error {
+			log.previous = entry.Signature
+			return nil
+		}); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	output, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	log.file = output
+	return log, nil
+}
+
+// Write appends an entry to the audit log
+func (a *auditLog) Write(entry *AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.key) > 0 {
+		signature, err := signAuditEntry(a.key, a.previous, entry)
+		if err != nil {
+			return err
+		}
+		entry.Signature = signature
+		a.previous = signature
+	}
+	data, err := jsoniter.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = a.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close syncs and closes the audit log
+func (a *auditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	//nolint:errcheck // we don't care whether sync failed or succeeded.
+	a.file.Sync()
+	return a.file.Close()
+}
+
+// signAuditEntry returns the signature of an entry chained with the previous signature
+func signAuditEntry(key []byte, previous string, entry *AuditEntry) (string, error) {
+	unsigned := *entry
+	unsigned.Signature = ""
+	data, err := jsoniter.Marshal(&unsigned)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(previous))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// ReadAuditLog calls callback for each entry of an audit log
+func ReadAuditLog(file string, callback func(entry *AuditEntry) error) error {
+	input, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditEntrySize)
+	for line := 1; scanner.Scan(); line++ {
+		entry := &AuditEntry{}
+		if err := jsoniter.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return errors.Wrapf(err, "could not parse audit log entry %d", line)
+		}
+		if err := callback(entry); err != nil {
+			return errors.Wrapf(err, "invalid audit log entry %d", line)
+		}
+	}
+	return scanner.Err()
+}
+
+// VerifyAuditLog verifies the signature chain of a signed audit log, an error is
+// returned if any entry was modified, removed or inserted before the last one
+func VerifyAuditLog(file string, key string) error {
+	var previous string
+	return ReadAuditLog(file, func(entry *AuditEntry) error {
+		signature, err := signAuditEntry([]byte(key), previous, entry)
+		if err != nil {
+			return err
+		}
+		if !hmac.Equal([]byte(signature), []byte(entry.Signature)) {
+			return fmt.Errorf("signature mismatch for request to %s", entry.Target)
+		}
+		previous = entry.Signature
+		return nil
+	})
+}
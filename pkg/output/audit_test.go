@@ -0,0 +1,50 @@
+package output
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+	options := &types.Options{AuditLogFile: file, AuditLogKey: "secret"}
+
+	writer, err := NewStandardWriter(options)
+	require.Nil(t, err, "could not create writer")
+	WithTemplateID(writer, "tech-detect").LogRequest(&RequestEvent{Template: "/templates/tech-detect.yaml", Input: "https://example.com", Type: "http", BytesSent: 120, BytesReceived: 2048})
+	writer.Request("/templates/dns.yaml", "example.com", "dns", errors.New("timeout"))
+	writer.Close()
+
+	// entries written by a new scan continue the signature chain
+	writer, err = NewStandardWriter(options)
+	require.Nil(t, err, "could not reopen writer")
+	writer.Request("ssl-dns-names", "example.com:443", "ssl", nil)
+	writer.Close()
+
+	var entries []*AuditEntry
+	require.Nil(t, ReadAuditLog(file, func(entry *AuditEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	require.Len(t, entries, 3, "could not append entries")
+	require.Equal(t, "tech-detect", entries[0].TemplateID)
+	require.Equal(t, 120, entries[0].BytesSent)
+	require.Equal(t, 2048, entries[0].BytesReceived)
+	require.Equal(t, "/templates/dns.yaml", entries[1].TemplateID, "could not fallback to template path")
+	require.Equal(t, "timeout", entries[1].Error)
+
+	require.Nil(t, VerifyAuditLog(file, "secret"), "could not verify audit log")
+	require.NotNil(t, VerifyAuditLog(file, "other"), "could not detect wrong key")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err)
+	tampered := strings.Replace(string(data), `"bytes-sent":120`, `"bytes-sent":12`, 1)
+	require.Nil(t, os.WriteFile(file, []byte(tampered), 0600))
+	require.NotNil(t, VerifyAuditLog(file, "secret"), "could not detect modified entry")
+}
@@ -0,0 +1,122 @@
+package output
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// EvidenceStep is a request/response pair sent while executing a template on an input
+type EvidenceStep struct {
+	// Type is the protocol type of the step
+	Type string
+	// Target is the url or address the request was sent to
+	Target string
+	// Request is the dumped request of the step
+	Request string
+	// Response is the dumped response of the step
+	Response string
+}
+
+// EvidenceChain records the steps of a template execution on an input
+// so that findings can be written with all the requests that led to them
+type EvidenceChain struct {
+	mutex sync.Mutex
+	steps []EvidenceStep
+}
+
+// Add records the request and response of an event and attaches
+// the chain recorded so far to it
+func (c *EvidenceChain) Add(event *InternalWrappedEvent) {
+	event.RLock()
+	step := EvidenceStep{
+		Type:     types.ToString(event.InternalEvent["type"]),
+		Target:   types.ToString(event.InternalEvent["matched"]),
+		Request:  types.ToString(event.InternalEvent["request"]),
+		Response: types.ToString(event.InternalEvent["response"]),
+	}
+	if step.Target == "" {
+		step.Target = types.ToString(event.InternalEvent["host"])
+	}
+	event.RUnlock()
+
+	c.mutex.Lock()
+	if step.Request != "" || step.Response != "" {
+		c.steps = append(c.steps, step)
+	}
+	steps := make([]EvidenceStep, len(c.steps))
+	copy(steps, c.steps)
+	c.mutex.Unlock()
+
+	event.Lock()
+	event.Evidence = steps
+	event.Unlock()
+}
+
+// writeEvidence writes the evidence file of a finding to the evidence
+// directory and returns its path
+func (w *StandardWriter) writeEvidence(event *ResultEvent) (string, error) {
+	data := formatEvidence(event)
+	hash := sha1.Sum(data)
+	name := fmt.Sprintf("%s_%s_%s.txt", sanitizeFileName(event.TemplateID), sanitizeFileName(event.Host), hex.EncodeToString(hash[:])[:10])
+	path := filepath.Join(w.evidenceDir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// formatEvidence formats the request/response chain and interaction of a finding
+func formatEvidence(event *ResultEvent) []byte {
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("Template: %s\nMatched: %s\n", event.TemplateID, event.Matched))
+	if event.MatcherName != "" {
+		builder.WriteString(fmt.Sprintf("Matcher: %s\n", event.MatcherName))
+	}
+	if event.ExtractorName != "" {
+		builder.WriteString(fmt.Sprintf("Extractor: %s\n", event.ExtractorName))
+	}
+	if event.Timestamp.IsZero() {
+		builder.WriteString("\n")
+	} else {
+		builder.WriteString(fmt.Sprintf("Timestamp: %s\n\n", event.Timestamp.Format("2006-01-02 15:04:05 -0700")))
+	}
+
+	steps := event.Evidence
+	// events written without a recorded chain only have the matched pair
+	if len(steps) == 0 && (event.Request != "" || event.Response != "") {
+		steps = []EvidenceStep{{Type: event.Type, Target: event.Matched, Request: event.Request, Response: event.Response}}
+	}
+	var previousRequest string
+	for i, step := range steps {
+		builder.WriteString(fmt.Sprintf("[Step %d] [%s] %s\n\n", i+1, step.Type, step.Target))
+		// responses of a redirect chain share the same request
+		if step.Request != "" && step.Request != previousRequest {
+			builder.WriteString(strings.TrimRight(step.Request, "\r\n"))
+			builder.WriteString("\n\n")
+		}
+		previousRequest = step.Request
+		if step.Response != "" {
+			builder.WriteString(strings.TrimRight(step.Response, "\r\n"))
+			builder.WriteString("\n\n")
+		}
+	}
+	if interaction := event.Interaction; interaction != nil {
+		builder.WriteString(fmt.Sprintf("[Interaction] [%s] %s at %s\n\n", interaction.Protocol, interaction.RemoteAddress, interaction.Timestamp.Format("2006-01-02 15:04:05 -0700")))
+		if interaction.RawRequest != "" {
+			builder.WriteString(strings.TrimRight(interaction.RawRequest, "\r\n"))
+			builder.WriteString("\n\n")
+		}
+		if interaction.RawResponse != "" {
+			builder.WriteString(strings.TrimRight(interaction.RawResponse, "\r\n"))
+			builder.WriteString("\n\n")
+		}
+	}
+	return []byte(builder.String())
+}
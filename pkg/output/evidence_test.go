@@ -0,0 +1,41 @@
+package output
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvidenceFile(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewStandardWriter(&types.Options{EvidenceDirectory: dir, JSONL: true, OmitRawRequests: true})
+	require.Nil(t, err, "could not create writer")
+
+	chain := &EvidenceChain{}
+	login := &InternalWrappedEvent{InternalEvent: InternalEvent{"type": "http", "matched": "https://example.com/login", "request": "POST /login HTTP/1.1\r\n", "response": "HTTP/1.1 302 Found\r\n"}}
+	chain.Add(login)
+	redirect := &InternalWrappedEvent{InternalEvent: InternalEvent{"type": "http", "matched": "https://example.com/admin", "request": "POST /login HTTP/1.1\r\n", "response": "HTTP/1.1 200 OK\r\n"}}
+	chain.Add(redirect)
+	require.Len(t, login.Evidence, 1, "could not snapshot chain for event")
+	require.Len(t, redirect.Evidence, 2, "could not record chain")
+
+	event := &ResultEvent{TemplateID: "admin-panel", Host: "https://example.com", Matched: "https://example.com/admin", Type: "http", Request: "POST /login HTTP/1.1\r\n", MatcherStatus: true, Evidence: redirect.Evidence}
+	require.Nil(t, w.Write(event), "could not write event")
+	require.NotEmpty(t, event.EvidenceFile, "could not reference evidence file")
+
+	data, err := os.ReadFile(event.EvidenceFile)
+	require.Nil(t, err, "could not read evidence file")
+	content := string(data)
+	require.Contains(t, content, "[Step 1] [http] https://example.com/login")
+	require.Contains(t, content, "[Step 2] [http] https://example.com/admin")
+	require.Contains(t, content, "HTTP/1.1 302 Found")
+	require.Contains(t, content, "HTTP/1.1 200 OK")
+	require.Equal(t, 1, strings.Count(content, "POST /login"), "could not skip repeated redirect request")
+
+	failure := &ResultEvent{TemplateID: "admin-panel", Host: "https://example.com", Request: "GET / HTTP/1.1\r\n"}
+	require.Nil(t, w.Write(failure), "could not write event")
+	require.Empty(t, failure.EvidenceFile, "could not skip evidence for failure events")
+}
@@ -10,5 +10,8 @@ func (w *StandardWriter) formatJSON(output *ResultEvent) ([]byte, error) {
 		output.Request = ""
 		output.Response = ""
 	}
+	if w.schemaVersion != SchemaVersion {
+		return formatSchemaCompatible(output, w.schemaVersion)
+	}
 	return jsoniter.Marshal(output)
 }
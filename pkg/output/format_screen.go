@@ -29,7 +29,8 @@ func (w *StandardWriter) formatScreen(output *ResultEvent) []byte {
 			builder.WriteString(w.aurora.BrightGreen(output.ExtractorName).Bold().String())
 		}
 
-		if w.matcherStatus {
+		// the errors are failures written without -matcher-status
+		if w.matcherStatus || output.Error != "" {
 			builder.WriteString("] [")
 			if !output.MatcherStatus {
 				builder.WriteString(w.aurora.Red("failed").String())
@@ -98,5 +99,11 @@ func (w *StandardWriter) formatScreen(output *ResultEvent) []byte {
 		}
 		builder.WriteString("]")
 	}
+
+	if output.Error != "" {
+		builder.WriteString(" [")
+		builder.WriteString(w.aurora.Red(output.Error).String())
+		builder.WriteString("]")
+	}
 	return builder.Bytes()
 }
@@ -20,6 +20,8 @@ import (
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/interactsh/pkg/server"
 	"github.com/projectdiscovery/nuclei/v3/internal/colorizer"
+	"github.com/projectdiscovery/nuclei/v3/pkg/compliance"
+	"github.com/projectdiscovery/nuclei/v3/pkg/cvedb"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
@@ -41,6 +43,8 @@ type Writer interface {
 	WriteFailure(*InternalWrappedEvent) error
 	// Request logs a request in the trace log
 	Request(templateID, url, requestType string, err error)
+	// LogRequest logs a request with the transferred bytes in the trace and audit logs
+	LogRequest(event *RequestEvent)
 	//  WriteStoreDebugData writes the request/response debug data to file
 	WriteStoreDebugData(host, templateID, eventType string, data string)
 }
@@ -57,9 +61,13 @@ type StandardWriter struct {
 	outputFile       io.WriteCloser
 	traceFile        io.WriteCloser
 	errorFile        io.WriteCloser
+	auditLog         *auditLog
+	auditRaw         bool
 	severityColors   func(severity.Severity) string
 	storeResponse    bool
 	storeResponseDir string
+	evidenceDir      string
+	schemaVersion    string
 }
 
 var decolorizerRegex = regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
@@ -81,6 +89,9 @@ type InternalWrappedEvent struct {
 	// Only applicable if interactsh is used
 	// This is used to avoid duplicate successful interactsh events
 	InteractshMatched atomic.Bool
+	// Evidence is the request/response chain recorded up to this event
+	// Only applicable if evidence files are enabled
+	Evidence []EvidenceStep
 }
 
 func (iwe *InternalWrappedEvent) HasOperatorResult() bool {
@@ -106,6 +117,8 @@ func (iwe *InternalWrappedEvent) SetOperatorResult(operatorResult *operators.Res
 
 // ResultEvent is a wrapped result event for a single nuclei output.
 type ResultEvent struct {
+	// SchemaVersion is the version of the json output schema of the result
+	SchemaVersion string `json:"schema_version,omitempty"`
 	// Template is the relative filename for the template
 	Template string `json:"template,omitempty"`
 	// TemplateURL is the URL of the template for the result inside the nuclei
@@ -139,6 +152,8 @@ type ResultEvent struct {
 	Metadata map[string]interface{} `json:"meta,omitempty"`
 	// IP is the IP address for the found result event.
 	IP string `json:"ip,omitempty"`
+	// ResolvedIPs contains the resolved addresses of the host allowed by ip-version
+	ResolvedIPs []string `json:"resolved-ips,omitempty"`
 	// Timestamp is the time the result was found at.
 	Timestamp time.Time `json:"timestamp"`
 	// Interaction is the full details of interactsh interaction.
@@ -148,8 +163,20 @@ type ResultEvent struct {
 	CURLCommand string `json:"curl-command,omitempty"`
 	// MatcherStatus is the status of the match
 	MatcherStatus bool `json:"matcher-status"`
+	// Error is the error of the failed request, ex: the interruption of the javascript code by its limits
+	Error string `json:"error,omitempty"`
 	// Lines is the line count for the specified match
 	Lines []int `json:"matched-line,omitempty"`
+	// Artifacts contains paths of files generated for the match (ex: headless screenshots and traces)
+	Artifacts []string `json:"artifacts,omitempty"`
+	// EvidenceFile is the path of the file containing the request/response chain of the match
+	EvidenceFile string `json:"evidence-file,omitempty"`
+	// Compliance contains the OWASP, CWE and ATT&CK classification of the template
+	Compliance *compliance.Mapping `json:"compliance,omitempty"`
+	// CVEDetails contains the offline cve database records of the cve ids of the template
+	CVEDetails []*cvedb.Record `json:"cve-details,omitempty"`
+	// Evidence is the request/response chain written to the evidence file
+	Evidence []EvidenceStep `json:"-"`
 
 	FileToIndexPosition map[string]int `json:"-"`
 }
@@ -186,12 +213,25 @@ func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 		}
 		errorOutput = output
 	}
+	var audit *auditLog
+	if options.AuditLogFile != "" {
+		output, err := newAuditLog(options.AuditLogFile, options.AuditLogKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create audit log file")
+		}
+		audit = output
+	}
 	// Try to create output folder if it doesn't exist
 	if options.StoreResponse && !fileutil.FolderExists(options.StoreResponseDir) {
 		if err := fileutil.CreateFolder(options.StoreResponseDir); err != nil {
 			gologger.Fatal().Msgf("Could not create output directory '%s': %s\n", options.StoreResponseDir, err)
 		}
 	}
+	if options.EvidenceDirectory != "" && !fileutil.FolderExists(options.EvidenceDirectory) {
+		if err := fileutil.CreateFolder(options.EvidenceDirectory); err != nil {
+			return nil, errors.Wrap(err, "could not create evidence directory")
+		}
+	}
 
 	writer := &StandardWriter{
 		json:             options.JSONL,
@@ -204,9 +244,16 @@ func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 		outputFile:       outputFile,
 		traceFile:        traceOutput,
 		errorFile:        errorOutput,
+		auditLog:         audit,
+		auditRaw:         options.AuditLogRaw,
 		severityColors:   colorizer.New(auroraColorizer),
 		storeResponse:    options.StoreResponse,
 		storeResponseDir: options.StoreResponseDir,
+		evidenceDir:      options.EvidenceDirectory,
+		schemaVersion:    SchemaVersion,
+	}
+	if options.OutputSchemaVersion != "" {
+		writer.schemaVersion = options.OutputSchemaVersion
 	}
 	return writer, nil
 }
@@ -218,6 +265,16 @@ func (w *StandardWriter) Write(event *ResultEvent) error {
 		event.Template, event.TemplateURL = utils.TemplatePathURL(types.ToString(event.TemplatePath), types.ToString(event.TemplateID))
 	}
 	event.Timestamp = time.Now()
+	event.SchemaVersion = w.schemaVersion
+
+	if w.evidenceDir != "" && event.MatcherStatus {
+		evidenceFile, err := w.writeEvidence(event)
+		if err != nil {
+			gologger.Warning().Msgf("Could not write evidence file: %s\n", err)
+		} else {
+			event.EvidenceFile = evidenceFile
+		}
+	}
 
 	var data []byte
 	var err error
@@ -260,15 +317,42 @@ type JSONLogRequest struct {
 
 // Request writes a log the requests trace log
 func (w *StandardWriter) Request(templatePath, input, requestType string, requestErr error) {
+	w.LogRequest(&RequestEvent{Template: templatePath, Input: input, Type: requestType, Error: requestErr})
+}
+
+// LogRequest writes a request to the trace, error and audit logs
+func (w *StandardWriter) LogRequest(event *RequestEvent) {
+	if w.auditLog != nil {
+		entry := &AuditEntry{
+			Timestamp:     time.Now(),
+			TemplateID:    event.TemplateID,
+			Type:          event.Type,
+			Target:        event.Input,
+			BytesSent:     event.BytesSent,
+			BytesReceived: event.BytesReceived,
+		}
+		if entry.TemplateID == "" {
+			entry.TemplateID = event.Template
+		}
+		if event.Error != nil {
+			entry.Error = event.Error.Error()
+		}
+		if w.auditRaw {
+			entry.Request, entry.Response = event.Request, event.Response
+		}
+		if err := w.auditLog.Write(entry); err != nil {
+			gologger.Warning().Msgf("Could not write audit log entry: %s\n", err)
+		}
+	}
 	if w.traceFile == nil && w.errorFile == nil {
 		return
 	}
 	request := &JSONLogRequest{
-		Template: templatePath,
-		Input:    input,
-		Type:     requestType,
+		Template: event.Template,
+		Input:    event.Input,
+		Type:     event.Type,
 	}
-	if unwrappedErr := utils.UnwrapError(requestErr); unwrappedErr != nil {
+	if unwrappedErr := utils.UnwrapError(event.Error); unwrappedErr != nil {
 		request.Error = unwrappedErr.Error()
 	} else {
 		request.Error = "none"
@@ -283,7 +367,7 @@ func (w *StandardWriter) Request(templatePath, input, requestType string, reques
 		_, _ = w.traceFile.Write(data)
 	}
 
-	if requestErr != nil && w.errorFile != nil {
+	if event.Error != nil && w.errorFile != nil {
 		_, _ = w.errorFile.Write(data)
 	}
 }
@@ -304,6 +388,9 @@ func (w *StandardWriter) Close() {
 	if w.errorFile != nil {
 		w.errorFile.Close()
 	}
+	if w.auditLog != nil {
+		w.auditLog.Close()
+	}
 }
 
 // WriteFailure writes the failure event for template to file and/or screen.
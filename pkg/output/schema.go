@@ -0,0 +1,82 @@
+package output
+
+import (
+	jsoniter "github.com/json-iterator/go"
+)
+
+// SchemaVersion is the version of the json output schema written by nuclei
+const SchemaVersion = "1.5"
+
+// schemaVersions contains the top level fields added to the json output by each
+// schema version in release order. Fields must only be added in a new schema version
+// so that parsers pinned to older versions keep receiving the layout they expect.
+var schemaVersions = []struct {
+	version string
+	fields  []string
+}{
+	// 1.0 is the layout of nuclei v3.0 results, written without schema_version
+	{version: "1.0", fields: []string{
+		"template", "template-url", "template-id", "template-path", "info",
+		"matcher-name", "extractor-name", "type", "host", "path", "matched-at", "extracted-results",
+		"request", "response", "meta", "ip", "timestamp", "interaction", "curl-command",
+		"matcher-status", "matched-line",
+	}},
+	{version: "1.1", fields: []string{"schema_version", "artifacts", "evidence-file"}},
+	{version: "1.2", fields: []string{"resolved-ips"}},
+	{version: "1.3", fields: []string{"compliance"}},
+	{version: "1.4", fields: []string{"cve-details"}},
+	{version: "1.5", fields: []string{"error"}},
+}
+
+// IsSupportedSchemaVersion returns true if records can be written in version layout
+func IsSupportedSchemaVersion(version string) bool {
+	for _, v := range schemaVersions {
+		if v.version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedSchemaVersions returns the supported json output schema versions
+func SupportedSchemaVersions() []string {
+	versions := make([]string, 0, len(schemaVersions))
+	for _, v := range schemaVersions {
+		versions = append(versions, v.version)
+	}
+	return versions
+}
+
+// schemaFieldsOf returns the fields of a schema version including
+// the ones added by previous versions
+func schemaFieldsOf(version string) map[string]struct{} {
+	fields := make(map[string]struct{})
+	for _, v := range schemaVersions {
+		for _, field := range v.fields {
+			fields[field] = struct{}{}
+		}
+		if v.version == version {
+			break
+		}
+	}
+	return fields
+}
+
+// formatSchemaCompatible marshals a result keeping only the fields of an older schema version
+func formatSchemaCompatible(output *ResultEvent, version string) ([]byte, error) {
+	data, err := jsoniter.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+	record := make(map[string]jsoniter.RawMessage)
+	if err := jsoniter.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	fields := schemaFieldsOf(version)
+	for field := range record {
+		if _, ok := fields[field]; !ok {
+			delete(record, field)
+		}
+	}
+	return jsoniter.Marshal(record)
+}
@@ -0,0 +1,61 @@
+package output
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaFieldsCoverResultEvent(t *testing.T) {
+	fields := schemaFieldsOf(SchemaVersion)
+	eventType := reflect.TypeOf(ResultEvent{})
+	for i := 0; i < eventType.NumField(); i++ {
+		name, _, _ := strings.Cut(eventType.Field(i).Tag.Get("json"), ",")
+		if name == "-" || name == "" {
+			continue
+		}
+		_, ok := fields[name]
+		require.True(t, ok, "json field %s is not part of schema version %s", name, SchemaVersion)
+	}
+	require.Equal(t, SchemaVersion, SupportedSchemaVersions()[len(SupportedSchemaVersions())-1], "could not get current version as latest")
+}
+
+func TestFormatSchemaCompatible(t *testing.T) {
+	event := &ResultEvent{TemplateID: "test", Artifacts: []string{"screenshot.png"}, EvidenceFile: "evidence.txt", MatcherStatus: true}
+
+	current, err := NewStandardWriter(&types.Options{JSONL: true})
+	require.Nil(t, err)
+	event.SchemaVersion = current.schemaVersion
+	data, err := current.formatJSON(event)
+	require.Nil(t, err)
+	record := map[string]interface{}{}
+	require.Nil(t, jsoniter.Unmarshal(data, &record))
+	require.Equal(t, SchemaVersion, record["schema_version"])
+	require.Contains(t, record, "evidence-file")
+
+	legacy, err := NewStandardWriter(&types.Options{JSONL: true, OutputSchemaVersion: "1.0"})
+	require.Nil(t, err)
+	event.SchemaVersion = legacy.schemaVersion
+	data, err = legacy.formatJSON(event)
+	require.Nil(t, err)
+	record = map[string]interface{}{}
+	require.Nil(t, jsoniter.Unmarshal(data, &record))
+	require.NotContains(t, record, "schema_version", "could not drop schema version of 1.0 layout")
+	require.Equal(t, "test", record["template-id"])
+	require.NotContains(t, record, "artifacts", "could not drop fields added in later versions")
+	require.NotContains(t, record, "evidence-file", "could not drop fields added in later versions")
+
+	compatible, err := NewStandardWriter(&types.Options{JSONL: true, OutputSchemaVersion: "1.1"})
+	require.Nil(t, err)
+	event.SchemaVersion = compatible.schemaVersion
+	data, err = compatible.formatJSON(event)
+	require.Nil(t, err)
+	record = map[string]interface{}{}
+	require.Nil(t, jsoniter.Unmarshal(data, &record))
+	require.Equal(t, "1.1", record["schema_version"])
+	require.Contains(t, record, "evidence-file")
+}
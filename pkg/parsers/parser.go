@@ -35,6 +35,10 @@ func LoadTemplate(templatePath string, tagFilter *filter.TagFilter, extraTags []
 	if len(template.Workflows) > 0 {
 		return false, nil
 	}
+	if override, ok := templates.GetOverride(template.ID); ok && override.Suppress {
+		stats.Increment(SuppressedTemplatesStats)
+		return false, nil
+	}
 
 	validationError := validateTemplateMandatoryFields(template)
 	if validationError != nil {
@@ -145,6 +149,7 @@ const (
 	UnsignedWarning          = "unsigned-warnings"
 	HeadlessFlagWarningStats = "headless-flag-missing-warnings"
 	TemplatesExecutedStats   = "templates-executed"
+	SuppressedTemplatesStats = "suppressed-templates"
 )
 
 func init() {
@@ -156,6 +161,7 @@ func init() {
 	stats.NewEntry(UnsignedWarning, "Found %d unsigned or tampered code template (carefully examine before using it & use -sign flag to sign them)")
 	stats.NewEntry(HeadlessFlagWarningStats, "Excluded %d headless templates (disabled as default), use -headless option to run headless templates.")
 	stats.NewEntry(TemplatesExecutedStats, "Excluded %d templates with known weak matchers / tags excluded from default run using .nuclei-ignore")
+	stats.NewEntry(SuppressedTemplatesStats, "Excluded %d templates suppressed by template overrides")
 }
 
 // ParseTemplate parses a template and returns a *templates.Template structure
@@ -185,6 +191,7 @@ func ParseTemplate(templatePath string, catalog catalog.Catalog) (*templates.Tem
 	if err != nil {
 		return nil, err
 	}
+	template.ApplyOverride()
 
 	parsedTemplatesCache.Store(templatePath, template, nil)
 	return template, nil
@@ -0,0 +1,206 @@
+package plugins
+
+import (
+	"fmt"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// client is a started plugin executable
+type client struct {
+	path string
+	info Info
+	cmd  *exec.Cmd
+	rpc  *rpc.Client
+}
+
+// HandshakeTimeout is the time a started plugin has to answer with its info
+var HandshakeTimeout = 10 * time.Second
+
+var (
+	mutex      sync.RWMutex
+	clients    = make(map[string]*client)
+	matchers   = make(map[string]*client)
+	extractors = make(map[string]*client)
+)
+
+// Load starts the plugin executables at paths and registers their matchers
+// and extractors. Directories load all the executables they contain.
+func Load(paths []string) error {
+	for _, path := range paths {
+		executables, err := executablesOf(path)
+		if err != nil {
+			return err
+		}
+		for _, executable := range executables {
+			if err := load(executable); err != nil {
+				return errorutil.NewWithTag("plugins", "could not load plugin %s", executable).Wrap(err)
+			}
+		}
+	}
+	return nil
+}
+
+// executablesOf returns the executable at path or the ones in the directory at path
+func executablesOf(path string) ([]string, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !stat.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var executables []string
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+			continue
+		}
+		executables = append(executables, filepath.Join(path, entry.Name()))
+	}
+	return executables, nil
+}
+
+func load(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if _, ok := clients[path]; ok {
+		return nil
+	}
+	cmd := exec.Command(path)
+	cmd.Env = append(os.Environ(), MagicCookieKey+"="+MagicCookieValue)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	c := &client{path: path, cmd: cmd, rpc: jsonrpc.NewClient(&stdio{reader: stdout, writer: stdin})}
+	select {
+	case call := <-c.rpc.Go(serviceName+".Info", Empty{}, &c.info, nil).Done:
+		if call.Error != nil {
+			c.close()
+			return call.Error
+		}
+	case <-time.After(HandshakeTimeout):
+		_ = cmd.Process.Kill()
+		c.close()
+		return fmt.Errorf("no handshake after %s", HandshakeTimeout)
+	}
+	if err := register(c); err != nil {
+		c.close()
+		return err
+	}
+	clients[path] = c
+	return nil
+}
+
+// register registers the matchers and extractors of a plugin
+// failing if another plugin already registered one of them
+func register(c *client) error {
+	for _, name := range c.info.Matchers {
+		if other, ok := matchers[name]; ok {
+			return fmt.Errorf("matcher %s is already registered by %s", name, other.info.Name)
+		}
+	}
+	for _, name := range c.info.Extractors {
+		if other, ok := extractors[name]; ok {
+			return fmt.Errorf("extractor %s is already registered by %s", name, other.info.Name)
+		}
+	}
+	for _, name := range c.info.Matchers {
+		matchers[name] = c
+	}
+	for _, name := range c.info.Extractors {
+		extractors[name] = c
+	}
+	return nil
+}
+
+func (c *client) close() {
+	_ = c.rpc.Close()
+	_ = c.cmd.Wait()
+}
+
+// Close stops all the started plugins
+func Close() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for _, c := range clients {
+		c.close()
+	}
+	clients = make(map[string]*client)
+	matchers = make(map[string]*client)
+	extractors = make(map[string]*client)
+}
+
+// HasMatcher returns true if a plugin registered the matcher
+func HasMatcher(name string) bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	_, ok := matchers[name]
+	return ok
+}
+
+// HasExtractor returns true if a plugin registered the extractor
+func HasExtractor(name string) bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	_, ok := extractors[name]
+	return ok
+}
+
+// Match matches a response part with a plugin matcher
+func Match(name, corpus string, args map[string]string) (bool, []string, error) {
+	mutex.RLock()
+	c, ok := matchers[name]
+	mutex.RUnlock()
+	if !ok {
+		return false, nil, fmt.Errorf("plugin matcher %s is not loaded", name)
+	}
+	var response MatchResponse
+	if err := c.rpc.Call(serviceName+".Match", Request{Name: name, Corpus: corpus, Args: args}, &response); err != nil {
+		return false, nil, err
+	}
+	return response.Matched, response.Snippets, nil
+}
+
+// Extract extracts values from a response part with a plugin extractor
+func Extract(name, corpus string, args map[string]string) ([]string, error) {
+	mutex.RLock()
+	c, ok := extractors[name]
+	mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("plugin extractor %s is not loaded", name)
+	}
+	var response ExtractResponse
+	if err := c.rpc.Call(serviceName+".Extract", Request{Name: name, Corpus: corpus, Args: args}, &response); err != nil {
+		return nil, err
+	}
+	return response.Values, nil
+}
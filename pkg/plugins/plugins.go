@@ -0,0 +1,54 @@
+// Package plugins implements external matcher and extractor plugins.
+//
+// A plugin is an executable started by nuclei at startup which serves its
+// matchers and extractors over json-rpc on its stdin/stdout. Plugins are
+// written with Serve and can be used in templates with the plugin matcher
+// and extractor types.
+package plugins
+
+// MagicCookieKey and MagicCookieValue are set in the environment of plugins started
+// by nuclei so that plugin executables refuse to run when started directly
+const (
+	MagicCookieKey   = "NUCLEI_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "b7c4e3a0f7d54f0c9a2e6d1b8f3c5a94"
+)
+
+// serviceName is the name of the rpc service served by plugins
+const serviceName = "Plugin"
+
+// Empty is the argument of rpc methods without arguments
+type Empty struct{}
+
+// Info contains the name and the matchers and extractors of a plugin
+type Info struct {
+	// Name is the name of the plugin
+	Name string
+	// Matchers contains the names of the matchers of the plugin
+	Matchers []string
+	// Extractors contains the names of the extractors of the plugin
+	Extractors []string
+}
+
+// Request is a request to match or extract a response part with a plugin
+type Request struct {
+	// Name is the name of the matcher or extractor
+	Name string
+	// Corpus is the response part to match or extract
+	Corpus string
+	// Args contains the template arguments of the matcher or extractor
+	Args map[string]string
+}
+
+// MatchResponse is the result of a match request
+type MatchResponse struct {
+	// Matched is true if the corpus matched
+	Matched bool
+	// Snippets contains the matched snippets of the corpus
+	Snippets []string
+}
+
+// ExtractResponse is the result of an extract request
+type ExtractResponse struct {
+	// Values contains the extracted values
+	Values []string
+}
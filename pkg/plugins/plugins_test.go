@@ -0,0 +1,73 @@
+package plugins
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testPlugin = &Plugin{
+	Name: "test",
+	Matchers: map[string]MatchFunc{
+		"contains": func(corpus string, args map[string]string) (bool, []string, error) {
+			if args["value"] == "" {
+				return false, nil, errors.New("no value")
+			}
+			if strings.Contains(corpus, args["value"]) {
+				return true, []string{args["value"]}, nil
+			}
+			return false, nil, nil
+		},
+	},
+	Extractors: map[string]ExtractFunc{
+		"fields": func(corpus string, _ map[string]string) ([]string, error) {
+			return strings.Fields(corpus), nil
+		},
+	},
+}
+
+// TestMain serves the test plugin when the test binary is started as a plugin
+func TestMain(m *testing.M) {
+	if os.Getenv(MagicCookieKey) == MagicCookieValue {
+		if err := Serve(testPlugin); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestPlugins(t *testing.T) {
+	require.Nil(t, Load([]string{os.Args[0]}), "could not load plugin")
+	defer Close()
+	require.Nil(t, Load([]string{os.Args[0]}), "could not load already loaded plugin")
+
+	require.True(t, HasMatcher("contains"))
+	require.True(t, HasExtractor("fields"))
+	require.False(t, HasMatcher("fields"))
+
+	matched, snippets, err := Match("contains", "hello plugin world", map[string]string{"value": "plugin"})
+	require.Nil(t, err, "could not match")
+	require.True(t, matched)
+	require.Equal(t, []string{"plugin"}, snippets)
+
+	_, _, err = Match("contains", "hello", nil)
+	require.NotNil(t, err, "could not get plugin error")
+
+	values, err := Extract("fields", "a b  c", nil)
+	require.Nil(t, err, "could not extract")
+	require.Equal(t, []string{"a", "b", "c"}, values)
+
+	_, err = Extract("unknown", "", nil)
+	require.NotNil(t, err, "could not get error for unknown extractor")
+
+	Close()
+	require.False(t, HasMatcher("contains"))
+}
+
+func TestServeNotStartedByNuclei(t *testing.T) {
+	require.ErrorIs(t, Serve(testPlugin), ErrNotStartedByNuclei)
+}
@@ -0,0 +1,115 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"sort"
+)
+
+// MatchFunc matches a response part with the template arguments of the
+// matcher and returns the matched snippets
+type MatchFunc func(corpus string, args map[string]string) (bool, []string, error)
+
+// ExtractFunc extracts values from a response part with the template
+// arguments of the extractor
+type ExtractFunc func(corpus string, args map[string]string) ([]string, error)
+
+// Plugin contains the matchers and extractors served by a plugin
+type Plugin struct {
+	// Name is the name of the plugin
+	Name string
+	// Matchers contains the matchers of the plugin by name
+	Matchers map[string]MatchFunc
+	// Extractors contains the extractors of the plugin by name
+	Extractors map[string]ExtractFunc
+}
+
+// ErrNotStartedByNuclei is returned by Serve when the plugin is not started by nuclei
+var ErrNotStartedByNuclei = errors.New("this executable is a nuclei plugin and must be loaded with -plugin")
+
+// Serve serves the matchers and extractors of a plugin to nuclei on stdin/stdout
+// until nuclei exits. Plugins must not write anything else to stdout.
+func Serve(plugin *Plugin) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return ErrNotStartedByNuclei
+	}
+	return serve(plugin, &stdio{reader: os.Stdin, writer: os.Stdout})
+}
+
+func serve(plugin *Plugin, conn io.ReadWriteCloser) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &service{plugin: plugin}); err != nil {
+		return err
+	}
+	server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return nil
+}
+
+// service is the rpc service of a plugin
+type service struct {
+	plugin *Plugin
+}
+
+// Info returns the name and the matchers and extractors of the plugin
+func (s *service) Info(_ Empty, info *Info) error {
+	info.Name = s.plugin.Name
+	for name := range s.plugin.Matchers {
+		info.Matchers = append(info.Matchers, name)
+	}
+	for name := range s.plugin.Extractors {
+		info.Extractors = append(info.Extractors, name)
+	}
+	sort.Strings(info.Matchers)
+	sort.Strings(info.Extractors)
+	return nil
+}
+
+// Match matches a response part with a matcher of the plugin
+func (s *service) Match(request Request, response *MatchResponse) error {
+	match, ok := s.plugin.Matchers[request.Name]
+	if !ok {
+		return fmt.Errorf("unknown matcher %s", request.Name)
+	}
+	matched, snippets, err := match(request.Corpus, request.Args)
+	if err != nil {
+		return err
+	}
+	response.Matched, response.Snippets = matched, snippets
+	return nil
+}
+
+// Extract extracts values from a response part with an extractor of the plugin
+func (s *service) Extract(request Request, response *ExtractResponse) error {
+	extract, ok := s.plugin.Extractors[request.Name]
+	if !ok {
+		return fmt.Errorf("unknown extractor %s", request.Name)
+	}
+	values, err := extract(request.Corpus, request.Args)
+	if err != nil {
+		return err
+	}
+	response.Values = values
+	return nil
+}
+
+// stdio is a connection over a reader and a writer
+type stdio struct {
+	reader io.ReadCloser
+	writer io.WriteCloser
+}
+
+func (s *stdio) Read(p []byte) (int, error) {
+	return s.reader.Read(p)
+}
+
+func (s *stdio) Write(p []byte) (int, error) {
+	return s.writer.Write(p)
+}
+
+func (s *stdio) Close() error {
+	return errors.Join(s.writer.Close(), s.reader.Close())
+}
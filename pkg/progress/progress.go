@@ -32,6 +32,9 @@ type Progress interface {
 	// IncrementFailedRequestsBy increments the number of requests counter by count
 	// along with errors.
 	IncrementFailedRequestsBy(count int64)
+	// IncrementConnections increments the connections counter by 1
+	// along with the reused connections counter if reused.
+	IncrementConnections(reused bool)
 }
 
 var _ Progress = &StatsTicker{}
@@ -85,6 +88,8 @@ func (p *StatsTicker) Init(hostCount int64, rulesCount int, requestCount int64)
 	p.stats.AddCounter("errors", uint64(0))
 	p.stats.AddCounter("matched", uint64(0))
 	p.stats.AddCounter("total", uint64(requestCount))
+	p.stats.AddCounter("connections", uint64(0))
+	p.stats.AddCounter("reused", uint64(0))
 
 	if p.active {
 		var printCallbackFunc clistats.DynamicCallback
@@ -142,6 +147,15 @@ func (p *StatsTicker) IncrementFailedRequestsBy(count int64) {
 	p.stats.IncrementCounter("errors", int(count))
 }
 
+// IncrementConnections increments the connections counter by 1
+// along with the reused connections counter if reused.
+func (p *StatsTicker) IncrementConnections(reused bool) {
+	p.stats.IncrementCounter("connections", 1)
+	if reused {
+		p.stats.IncrementCounter("reused", 1)
+	}
+}
+
 func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient) interface{} {
 	return func(stats clistats.StatisticsClient) interface{} {
 		builder := &strings.Builder{}
@@ -187,6 +201,15 @@ func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient)
 			builder.WriteString(clistats.String(errors))
 		}
 
+		// the connections are only counted with the connections reuse
+		if connections, ok := stats.GetCounter("connections"); ok && connections > 0 {
+			reused, _ := stats.GetCounter("reused")
+			builder.WriteString(" | Reused: ")
+			builder.WriteString(clistats.String(reused))
+			builder.WriteRune('/')
+			builder.WriteString(clistats.String(connections))
+		}
+
 		if okRequests && okTotal {
 			if p.cloud {
 				builder.WriteString(" | Task: ")
@@ -246,6 +269,10 @@ func metricsMap(stats clistats.StatisticsClient) map[string]interface{} {
 	results["rps"] = clistats.String(uint64(float64(requests) / duration.Seconds()))
 	errors, _ := stats.GetCounter("errors")
 	results["errors"] = clistats.String(errors)
+	connections, _ := stats.GetCounter("connections")
+	results["connections"] = clistats.String(connections)
+	reused, _ := stats.GetCounter("reused")
+	results["reused"] = clistats.String(reused)
 
 	// nolint:gomnd // this is not a magic number
 	percentData := (float64(requests) * float64(100)) / float64(total)
@@ -17,6 +17,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
@@ -48,30 +49,88 @@ type Request struct {
 	// description: |
 	//   Source File/Snippet
 	Source string `yaml:"source,omitempty" jsonschema:"title=source file/snippet,description=Source snippet"`
+	// description: |
+	//   Runtime is the managed runtime executing the source.
+	//
+	//   The interpreter is detected on the host unless engine is specified, deno
+	//   scripts are allowed network and environment access.
+	// values:
+	//   - "python"
+	//   - "deno"
+	Runtime string `yaml:"runtime,omitempty" jsonschema:"title=runtime,description=Managed runtime executing the source,enum=python,enum=deno"`
+	// description: |
+	//   ScriptArgs are the arguments passed to the source, variables are supported.
+	// examples:
+	//   - value: >
+	//       []string{"--target", "{{Host}}"}
+	ScriptArgs []string `yaml:"script-args,omitempty" jsonschema:"title=script arguments,description=Arguments passed to the source"`
+	// description: |
+	//   Stdin is the data passed on the standard input of the source, variables are supported.
+	//
+	//   By default the input is passed.
+	Stdin string `yaml:"stdin,omitempty" jsonschema:"title=stdin of the source,description=Data passed on the standard input of the source"`
+	// description: |
+	//   Timeout is the maximum execution time of the source.
+	// examples:
+	//   - value: "\"30s\""
+	Timeout string `yaml:"timeout,omitempty" jsonschema:"title=execution timeout,description=Maximum execution time of the source"`
+	// description: |
+	//   JSON parses the json object printed by the source and exposes its fields
+	//   for matching and extraction.
+	//
+	//   The object can be the whole output or its last line.
+	JSON bool `yaml:"json,omitempty" jsonschema:"title=capture json output,description=Parses the json object printed by the source and exposes its fields"`
 
 	options *protocols.ExecutorOptions
 	gozero  *gozero.Gozero
 	src     *gozero.Source
+	timeout time.Duration
 }
 
 // Compile compiles the request generators preparing any requests possible.
 func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	request.options = options
+	if options.Options.DisallowPrivateIPs {
+		// connections made by the code can't be restricted
+		return errorutil.New("[%s] code templates can't be run with disallow-private-ips", options.TemplateID)
+	}
 
 	gozeroOptions := &gozero.Options{
 		Engines:                  request.Engine,
 		Args:                     request.Args,
 		EarlyCloseFileDescriptor: true,
 	}
+	pattern := request.Pattern
+	if request.Runtime != "" {
+		managed, err := getRuntime(request.Runtime)
+		if err != nil {
+			return errorutil.NewWithErr(err).Msgf("[%s] could not compile code request", options.TemplateID)
+		}
+		if len(gozeroOptions.Engines) == 0 {
+			gozeroOptions.Engines = managed.engines
+		}
+		gozeroOptions.Args = append(append([]string{}, managed.args...), request.Args...)
+		if pattern == "" {
+			pattern = managed.pattern
+		}
+	}
+	if request.Timeout != "" {
+		timeout, err := time.ParseDuration(request.Timeout)
+		if err != nil {
+			return errorutil.NewWithErr(err).Msgf("[%s] invalid timeout %s", options.TemplateID, request.Timeout)
+		}
+		request.timeout = timeout
+	}
+
 	engine, err := gozero.New(gozeroOptions)
 	if err != nil {
-		return errorutil.NewWithErr(err).Msgf("[%s] engines '%s' not available on host", options.TemplateID, strings.Join(request.Engine, ","))
+		return errorutil.NewWithErr(err).Msgf("[%s] engines '%s' not available on host", options.TemplateID, strings.Join(gozeroOptions.Engines, ","))
 	}
 	request.gozero = engine
 
 	var src *gozero.Source
 
-	src, err = gozero.NewSourceWithString(request.Source, request.Pattern)
+	src, err = gozero.NewSourceWithString(request.Source, pattern)
 	if err != nil {
 		return err
 	}
@@ -113,16 +172,6 @@ func (request *Request) GetID() string {
 
 // ExecuteWithResults executes the protocol requests and returns results instead of writing them.
 func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
-	metaSrc, err := gozero.NewSourceWithString(input.MetaInput.Input, "")
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err := metaSrc.Cleanup(); err != nil {
-			gologger.Warning().Msgf("%s\n", err)
-		}
-	}()
-
 	var interactshURLs []string
 
 	// inject all template context values as gozero env variables
@@ -133,13 +182,51 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 	optionVars := generators.BuildPayloadFromOptions(request.options.Options)
 	variablesMap := request.options.Variables.Evaluate(variables)
 	variables = generators.MergeMaps(variablesMap, variables, optionVars, request.options.Constants)
+
+	stdin := input.MetaInput.Input
+	if request.Stdin != "" {
+		evaluated, err := expressions.Evaluate(request.Stdin, variables)
+		if err != nil {
+			return errors.Wrap(err, "could not evaluate stdin")
+		}
+		stdin, interactshURLs = request.options.Interactsh.Replace(evaluated, interactshURLs)
+	}
+	metaSrc, err := gozero.NewSourceWithString(stdin, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := metaSrc.Cleanup(); err != nil {
+			gologger.Warning().Msgf("%s\n", err)
+		}
+	}()
+
 	for name, value := range variables {
 		v := fmt.Sprint(value)
 		v, interactshURLs = request.options.Interactsh.Replace(v, interactshURLs)
 		metaSrc.AddVariable(gozerotypes.Variable{Name: name, Value: v})
 	}
-	gOutput, err := request.gozero.Eval(context.Background(), request.src, metaSrc)
+	scriptArgs := make([]string, 0, len(request.ScriptArgs))
+	for _, arg := range request.ScriptArgs {
+		evaluated, err := expressions.Evaluate(arg, variables)
+		if err != nil {
+			return errors.Wrap(err, "could not evaluate script argument")
+		}
+		evaluated, interactshURLs = request.options.Interactsh.Replace(evaluated, interactshURLs)
+		scriptArgs = append(scriptArgs, evaluated)
+	}
+
+	ctx := input.Context()
+	if request.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, request.timeout)
+		defer cancel()
+	}
+	gOutput, err := request.gozero.Eval(ctx, request.src, metaSrc, scriptArgs...)
 	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errorutil.NewWithErr(err).Msgf("[%s] code execution timed out after %s", request.options.TemplateID, request.timeout)
+		}
 		return err
 	}
 	gologger.Verbose().Msgf("[%s] Executed code on local machine %v", request.options.TemplateID, input.MetaInput.Input)
@@ -165,6 +252,18 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 	if gOutput.Stderr.Len() > 0 {
 		data["stderr"] = fmtStdout(gOutput.Stderr.String())
 	}
+	if request.JSON {
+		fields, err := parseJSONOutput(dataOutputString)
+		if err != nil {
+			gologger.Warning().Msgf("[%s] %s\n", request.options.TemplateID, err)
+		}
+		for name, value := range fields {
+			// the fields can't override the event values
+			if _, ok := data[name]; !ok {
+				data[name] = value
+			}
+		}
+	}
 
 	// expose response variables in proto_var format
 	// this is no-op if the template is not a multi protocol template
@@ -3,7 +3,9 @@
 package code
 
 import (
+	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -38,3 +40,47 @@ func TestCodeProtocol(t *testing.T) {
 	require.Nil(t, err, "could not run code request")
 	require.NotEmpty(t, gotEvent, "could not get event items")
 }
+
+func TestCodeProtocolRuntime(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-code-runtime"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+
+	request := &Request{
+		Runtime:    "python",
+		ScriptArgs: []string{"--target", "{{Host}}"},
+		Stdin:      "token-{{Port}}",
+		JSON:       true,
+		Source: `import json, sys
+print("checking", sys.argv[2])
+print(json.dumps({"target": sys.argv[2], "token": sys.stdin.read(), "vulnerable": True, "type": "overridden"}))`,
+	}
+	require.Nil(t, request.Compile(executerOpts), "could not compile code request")
+
+	var gotEvent output.InternalEvent
+	err := request.ExecuteWithResults(contextargs.NewWithInput("example.com:8080"), nil, nil, func(event *output.InternalWrappedEvent) {
+		gotEvent = event.InternalEvent
+	})
+	require.Nil(t, err, "could not run code request")
+	require.Equal(t, "example.com", gotEvent["target"])
+	require.Equal(t, "token-8080", gotEvent["token"])
+	require.Equal(t, true, gotEvent["vulnerable"])
+	require.Equal(t, "code", gotEvent["type"], "json fields overrode event values")
+
+	request = &Request{Runtime: "python", Timeout: "100ms", Source: "import time\ntime.sleep(5)"}
+	require.Nil(t, request.Compile(executerOpts), "could not compile code request")
+	started := time.Now()
+	err = request.ExecuteWithResults(contextargs.NewWithInput(""), nil, nil, func(event *output.InternalWrappedEvent) {})
+	require.ErrorContains(t, err, "timed out")
+	require.Less(t, time.Since(started), 5*time.Second)
+
+	require.Error(t, (&Request{Runtime: "perl", Source: "print 1"}).Compile(executerOpts), "could compile unknown runtime")
+}
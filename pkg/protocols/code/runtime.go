@@ -0,0 +1,55 @@
+package code
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// runtime is a managed runtime executing the source of a code request
+type runtime struct {
+	// engines are the interpreters of the runtime in detection order
+	engines []string
+	// args are the interpreter arguments preceding the engine args
+	args []string
+	// pattern is the source file name pattern required by the interpreter
+	pattern string
+}
+
+// runtimes contains the managed runtimes of the code protocol
+var runtimes = map[string]runtime{
+	"python": {
+		engines: []string{"python3", "python", "py"},
+		pattern: "*.py",
+	},
+	"deno": {
+		engines: []string{"deno"},
+		// scripts can't prompt for permissions as they are executed non-interactively
+		args:    []string{"run", "--quiet", "--no-prompt", "--allow-net", "--allow-env"},
+		pattern: "*.ts",
+	},
+}
+
+// getRuntime returns the managed runtime with the name
+func getRuntime(name string) (runtime, error) {
+	value, ok := runtimes[strings.ToLower(name)]
+	if !ok {
+		return runtime{}, errors.Errorf("unknown runtime %s", name)
+	}
+	return value, nil
+}
+
+// parseJSONOutput parses the json object printed by the source, either as the whole
+// output or as its last line so that scripts can print progress before the result
+func parseJSONOutput(data string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &result); err == nil {
+		return result, nil
+	}
+	lastLine := data[strings.LastIndex(data, "\n")+1:]
+	if err := json.Unmarshal([]byte(strings.TrimSpace(lastLine)), &result); err != nil {
+		return nil, errors.Wrap(err, "could not parse json output")
+	}
+	return result, nil
+}
@@ -179,7 +179,7 @@ func (s *Service) processWappalyzerInputPair(input *contextargs.MetaInput) {
 	}
 
 	if s.opts.Options.Verbose {
-		gologger.Verbose().Msgf("Wappalyzer fingerprints %v for %s\n", normalized, input)
+		gologger.Verbose().Msgf("Wappalyzer fingerprints %v for %s\n", normalized, input.Input)
 	}
 
 	for k := range normalized {
@@ -205,7 +205,7 @@ func (s *Service) processWappalyzerInputPair(input *contextargs.MetaInput) {
 	uniqueTags := sliceutil.Dedupe(items)
 
 	templatesList := s.store.LoadTemplatesWithTags(s.allTemplates, uniqueTags)
-	gologger.Info().Msgf("Executing tags (%v) for host %s (%d templates)", strings.Join(uniqueTags, ","), input, len(templatesList))
+	gologger.Info().Msgf("Executing tags (%v) for host %s (%d templates)", strings.Join(uniqueTags, ","), input.Input, len(templatesList))
 	for _, t := range templatesList {
 		s.opts.Progress.AddToTotal(int64(t.Executer.Requests()))
 
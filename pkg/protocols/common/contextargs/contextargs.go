@@ -1,6 +1,8 @@
 package contextargs
 
 import (
+	"context"
+	"errors"
 	"net/http/cookiejar"
 	"strings"
 	"sync/atomic"
@@ -15,6 +17,10 @@ import (
 var (
 	// reservedPorts contains list of reserved ports for non-network requests in nuclei
 	reservedPorts = []string{"80", "443", "8080", "8443", "8081", "53"}
+
+	// ErrPortNotOpen is returned when the network port of a template
+	// was not found open on a port scanned input
+	ErrPortNotOpen = errors.New("template port is not open on port scanned input")
 )
 
 // Context implements a shared context struct to share information across multiple templates within a workflow
@@ -24,9 +30,14 @@ type Context struct {
 
 	// CookieJar shared within workflow's http templates
 	CookieJar *cookiejar.Jar
+	// CookieReuse makes all the templates executed with the context use the cookie jar
+	CookieReuse bool
 
 	// Args is a workflow shared key-value store
 	args *mapsutil.SyncLockMap[string, interface{}]
+
+	// execCtx is cancelled when the execution of the input is aborted
+	execCtx context.Context
 }
 
 // Create a new contextargs instance
@@ -50,6 +61,20 @@ func NewWithInput(input string) *Context {
 	}
 }
 
+// SetContext sets the go context used to abort the execution of the input
+func (ctx *Context) SetContext(execCtx context.Context) {
+	ctx.execCtx = execCtx
+}
+
+// Context returns the go context of the execution, protocols use it
+// to stop in-flight requests when the scan is cancelled
+func (ctx *Context) Context() context.Context {
+	if ctx.execCtx == nil {
+		return context.Background()
+	}
+	return ctx.execCtx
+}
+
 // Set the specific key-value pair
 func (ctx *Context) Set(key string, value interface{}) {
 	_ = ctx.args.Set(key, value)
@@ -106,6 +131,14 @@ func (ctx *Context) UseNetworkPort(port string, excludePorts string) error {
 		return err
 	}
 	inputPort := target.Port()
+	if ctx.MetaInput.PortScanned {
+		// port of a port scanned input is known to be open
+		// so only templates for that port are executed
+		if !sliceutil.Contains(strings.Split(strings.ReplaceAll(port, " ", ""), ","), inputPort) {
+			return ErrPortNotOpen
+		}
+		return nil
+	}
 	if inputPort == "" || stringsutil.EqualFoldAny(inputPort, ignorePorts...) {
 		// replace port with networkPort
 		target.UpdatePort(port)
@@ -158,9 +191,11 @@ func (ctx *Context) HasArgs() bool {
 
 func (ctx *Context) Clone() *Context {
 	newCtx := &Context{
-		MetaInput: ctx.MetaInput.Clone(),
-		args:      ctx.args.Clone(),
-		CookieJar: ctx.CookieJar,
+		MetaInput:   ctx.MetaInput.Clone(),
+		args:        ctx.args.Clone(),
+		CookieJar:   ctx.CookieJar,
+		CookieReuse: ctx.CookieReuse,
+		execCtx:     ctx.execCtx,
 	}
 	return newCtx
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/md5"
 	"fmt"
+	"maps"
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
@@ -15,6 +16,13 @@ type MetaInput struct {
 	Input string `json:"input,omitempty"`
 	// CustomIP to use for connection
 	CustomIP string `json:"customIP,omitempty"`
+	// Service is the service detected on the port of the input by a port scanner
+	Service string `json:"service,omitempty"`
+	// PortScanned is true when the port of the input was found open by a port scanner.
+	// templates using a different network port are not executed against such inputs
+	PortScanned bool `json:"portScanned,omitempty"`
+	// Metadata contains the variables of the input provided by its source (ex: uncover_ip)
+	Metadata map[string]string `json:"metadata,omitempty"`
 	// hash of the input
 	hash string `json:"-"`
 }
@@ -59,11 +67,23 @@ func (metaInput *MetaInput) Unmarshal(data string) error {
 
 func (metaInput *MetaInput) Clone() *MetaInput {
 	return &MetaInput{
-		Input:    metaInput.Input,
-		CustomIP: metaInput.CustomIP,
+		Input:       metaInput.Input,
+		CustomIP:    metaInput.CustomIP,
+		Service:     metaInput.Service,
+		PortScanned: metaInput.PortScanned,
+		Metadata:    maps.Clone(metaInput.Metadata),
 	}
 }
 
+// SupportsHTTP returns false if a non http service was detected on
+// the port of the input by a port scanner
+func (metaInput *MetaInput) SupportsHTTP() bool {
+	if !metaInput.PortScanned || metaInput.Service == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(metaInput.Service), "http")
+}
+
 func (metaInput *MetaInput) PrettyPrint() string {
 	if metaInput.CustomIP != "" {
 		return fmt.Sprintf("%s [%s]", metaInput.Input, metaInput.CustomIP)
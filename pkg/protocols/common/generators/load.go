@@ -22,7 +22,13 @@ func (generator *PayloadGenerator) loadPayloads(payloads map[string]interface{},
 			if len(elements) >= 2 {
 				loadedPayloads[name] = elements
 			} else {
-				file, err := generator.options.LoadHelperFile(pt, templatePath, generator.catalog)
+				var file io.ReadCloser
+				var err error
+				if isRemotePayload(pt) {
+					file, err = remotePayloads.open(pt)
+				} else {
+					file, err = generator.options.LoadHelperFile(pt, templatePath, generator.catalog)
+				}
 				if err != nil {
 					return nil, errors.Wrap(err, "could not load payload file")
 				}
@@ -0,0 +1,164 @@
+package generators
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/retryablehttp-go"
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// integrityFragment is the url fragment prefix of the expected sha256 checksum of a remote payload file
+// ex: https://example.com/wordlists/users.txt#sha256=<hex checksum>
+const integrityFragment = "sha256="
+
+// remotePayloadsDirName is the cache directory name of remote payload files
+const remotePayloadsDirName = "payloads"
+
+// maxRemotePayloadSize is the maximum size of a remote payload file
+const maxRemotePayloadSize = 64 * 1024 * 1024
+
+// remotePayloads is the cache of the remote payload files fetched during the scan
+var remotePayloads = &remotePayloadCache{files: make(map[string]string)}
+
+// InitRemotePayloads sets the http client fetching the remote payload files, the
+// client of the scan is used for the proxy and network policy of the options.
+func InitRemotePayloads(client *retryablehttp.Client) {
+	remotePayloads.mutex.Lock()
+	defer remotePayloads.mutex.Unlock()
+
+	remotePayloads.client = client
+}
+
+// remotePayloadCache fetches remote payload files once per scan and caches them
+// on disk by checksum, files with an expected checksum are reused across scans
+type remotePayloadCache struct {
+	client    *retryablehttp.Client
+	directory string
+
+	mutex sync.Mutex
+	files map[string]string
+}
+
+// isRemotePayload returns true if the payload references a remote payload file
+func isRemotePayload(payload string) bool {
+	return strings.HasPrefix(payload, "https://")
+}
+
+// parseRemotePayload returns the url of a remote payload file and its expected sha256 checksum
+func parseRemotePayload(payload string) (string, string, error) {
+	parsed, err := url.Parse(payload)
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not parse remote payload url")
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return "", "", fmt.Errorf("remote payload %s must be an https url", payload)
+	}
+	var checksum string
+	if parsed.Fragment != "" {
+		if !strings.HasPrefix(parsed.Fragment, integrityFragment) {
+			return "", "", fmt.Errorf("remote payload %s has invalid integrity %s", payload, parsed.Fragment)
+		}
+		checksum = strings.ToLower(strings.TrimPrefix(parsed.Fragment, integrityFragment))
+		if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+			return "", "", fmt.Errorf("remote payload %s has invalid sha256 checksum", payload)
+		}
+	}
+	parsed.Fragment = ""
+	return parsed.String(), checksum, nil
+}
+
+// open returns the cached file of a remote payload, fetching it if required
+func (c *remotePayloadCache) open(payload string) (io.ReadCloser, error) {
+	path, err := c.path(payload)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// path returns the cache file path of a remote payload, fetching it if required
+func (c *remotePayloadCache) path(payload string) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if path, ok := c.files[payload]; ok {
+		return path, nil
+	}
+	location, checksum, err := parseRemotePayload(payload)
+	if err != nil {
+		return "", err
+	}
+	directory := c.directory
+	if directory == "" {
+		directory = filepath.Join(config.DefaultConfig.GetCacheDir(), remotePayloadsDirName)
+	}
+	if checksum != "" {
+		if path := filepath.Join(directory, checksum); fileutil.FileExists(path) {
+			c.files[payload] = path
+			return path, nil
+		}
+	}
+
+	path, err := c.fetch(location, checksum, directory)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not fetch remote payload %s", location)
+	}
+	c.files[payload] = path
+	return path, nil
+}
+
+// fetch downloads a remote payload file to the cache directory, verifying its checksum if expected
+func (c *remotePayloadCache) fetch(location, checksum, directory string) (string, error) {
+	if c.client == nil {
+		return "", errors.New("http client is not initialized")
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return "", err
+	}
+	resp, err := c.client.Get(location)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	file, err := os.CreateTemp(directory, "payload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(file, hash), io.LimitReader(resp.Body, maxRemotePayloadSize+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if written > maxRemotePayloadSize {
+		return "", fmt.Errorf("file is larger than %d bytes", maxRemotePayloadSize)
+	}
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if checksum != "" && actual != checksum {
+		return "", fmt.Errorf("integrity check failed, expected sha256 %s got %s", checksum, actual)
+	}
+	path := filepath.Join(directory, actual)
+	if err := os.Rename(file.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
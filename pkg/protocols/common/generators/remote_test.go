@@ -0,0 +1,103 @@
+package generators
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemotePayloadCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, "admin\n\nroot\n")
+	}))
+	defer server.Close()
+
+	checksum := sha256.Sum256([]byte("admin\n\nroot\n"))
+	expected := hex.EncodeToString(checksum[:])
+	client := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	client.HTTPClient = server.Client()
+	newCache := func(directory string) *remotePayloadCache {
+		return &remotePayloadCache{client: client, directory: directory, files: make(map[string]string)}
+	}
+	directory := t.TempDir()
+	cache := newCache(directory)
+
+	load := func(cache *remotePayloadCache, payload string) []string {
+		file, err := cache.open(payload)
+		require.Nil(t, err, "could not open remote payload")
+		values, err := (&PayloadGenerator{}).loadPayloadsFromFile(file)
+		require.Nil(t, err)
+		return values
+	}
+	require.Equal(t, []string{"admin", "root"}, load(cache, server.URL+"/users.txt"))
+	require.Equal(t, []string{"admin", "root"}, load(cache, server.URL+"/users.txt"))
+	require.Equal(t, int32(1), requests.Load(), "could not fetch remote payload once per scan")
+	require.FileExists(t, filepath.Join(directory, expected), "could not cache remote payload by checksum")
+
+	// payloads with an expected checksum are reused from disk across scans
+	require.Equal(t, []string{"admin", "root"}, load(newCache(directory), server.URL+"/users.txt#sha256="+expected))
+	require.Equal(t, int32(1), requests.Load(), "could not reuse cached remote payload")
+
+	_, err := newCache(t.TempDir()).open(server.URL + "/users.txt#sha256=" + hex.EncodeToString(make([]byte, sha256.Size)))
+	require.ErrorContains(t, err, "integrity check failed")
+
+	for _, payload := range []string{"https://example.com/users.txt#md5=abc", "https://example.com/users.txt#sha256=abc", "https:///users.txt"} {
+		_, _, err := parseRemotePayload(payload)
+		require.Error(t, err, "could not reject %s", payload)
+	}
+	require.False(t, isRemotePayload("http://example.com/users.txt"), "could not restrict remote payloads to https")
+}
+
+func TestLoadRemotePayloads(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "one\ntwo\n")
+	}))
+	defer server.Close()
+
+	previous := remotePayloads
+	defer func() { remotePayloads = previous }()
+	client := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	client.HTTPClient = server.Client()
+	remotePayloads = &remotePayloadCache{client: client, directory: t.TempDir(), files: make(map[string]string)}
+
+	payloads := map[string]interface{}{"values": server.URL + "/values.txt"}
+	_, err := New(payloads, BatteringRamAttack, "", nil, "", getOptions(false))
+	require.ErrorContains(t, err, "-allow-remote-payloads", "could not reject remote payload without opt-in")
+
+	options := getOptions(false)
+	options.AllowRemotePayloads = true
+	generator, err := New(payloads, BatteringRamAttack, "", nil, "", options)
+	require.Nil(t, err, "could not create generator with remote payload")
+	require.Equal(t, map[string][]string{"values": {"one", "two"}}, generator.payloads)
+}
+
+func TestRemotePayloadMaxSize(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.CopyN(w, zeroReader{}, maxRemotePayloadSize+1)
+	}))
+	defer server.Close()
+
+	client := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	client.HTTPClient = server.Client()
+	cache := &remotePayloadCache{client: client, directory: t.TempDir(), files: make(map[string]string)}
+	_, err := cache.open(server.URL + "/large.txt")
+	require.ErrorContains(t, err, "larger than", "could not limit remote payload size")
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
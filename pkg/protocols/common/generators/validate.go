@@ -22,6 +22,16 @@ func (g *PayloadGenerator) validate(payloads map[string]interface{}, templatePat
 				return errors.New("invalid number of lines in payload")
 			}
 
+			// remote payload files are fetched when loading the payloads
+			if isRemotePayload(payloadType) {
+				if g.options == nil || !g.options.AllowRemotePayloads {
+					return fmt.Errorf("remote payload %s is not allowed, use -allow-remote-payloads to enable it", payloadType)
+				}
+				if _, _, err := parseRemotePayload(payloadType); err != nil {
+					return err
+				}
+				continue
+			}
 			// check if it's a file and try to load it
 			if fileutil.FileExists(payloadType) {
 				continue
@@ -2,8 +2,11 @@ package writer
 
 import (
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/compliance"
+	"github.com/projectdiscovery/nuclei/v3/pkg/cvedb"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
 )
 
@@ -17,6 +20,18 @@ func WriteResult(data *output.InternalWrappedEvent, output output.Writer, progre
 	}
 	var matched bool
 	for _, result := range data.Results {
+		if len(result.Evidence) == 0 {
+			result.Evidence = data.Evidence
+		}
+		if len(result.ResolvedIPs) == 0 {
+			result.ResolvedIPs = protocolstate.ResolvedIPs(result.Host)
+		}
+		if result.Compliance == nil {
+			result.Compliance = compliance.Map(&result.Info)
+		}
+		if result.CVEDetails == nil && result.Info.Classification != nil {
+			result.CVEDetails = cvedb.Lookup(result.Info.Classification.CVEID.ToSlice()...)
+		}
 		if err := output.Write(result); err != nil {
 			gologger.Warning().Msgf("Could not write output event: %s\n", err)
 		}
@@ -0,0 +1,220 @@
+// Package hostratelimit implements an adaptive rate limiter pacing the
+// requests of every host according to its response latency and errors.
+package hostratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxRate is the maximum rate of the hosts when the global rate is unlimited
+	DefaultMaxRate = 150
+	// DefaultMinRate is the default minimum rate of the throttled hosts
+	DefaultMinRate = 1
+
+	// decreaseFactor divides the rate of the hosts answering with errors or overload statuses
+	decreaseFactor = 2
+	// slowdownFactor reduces the rate of the hosts getting slower
+	slowdownFactor = 0.8
+	// latencyFactor is the ratio of the smoothed latency to the baseline from which a host is slower
+	latencyFactor = 3
+	// latencyWeight is the weight of each sample in the smoothed latency
+	latencyWeight = 0.2
+	// recoverySteps is the number of successful responses to recover the maximum rate from zero
+	recoverySteps = 20
+	// maxRetryAfter caps the pause requested by the Retry-After headers
+	maxRetryAfter = time.Minute
+)
+
+// Limiter adapts the request rate of every host between a minimum and a maximum
+// rate. The rate of a host is halved on connection errors and 429/503 responses,
+// reduced when its latency grows and recovered step by step on fast successes.
+type Limiter struct {
+	maxRate float64
+	minRate float64
+
+	mutex sync.Mutex
+	hosts map[string]*host
+}
+
+type host struct {
+	// rate is the current number of requests per second of the host
+	rate float64
+	// next is the time from which the next request can be sent
+	next time.Time
+	// latency is the smoothed latency of the responses
+	latency time.Duration
+	// baseline is the lowest smoothed latency observed
+	baseline  time.Duration
+	throttled int
+}
+
+// New returns a limiter starting the hosts at maxRate requests per second
+// and throttling them down to minRate requests per second
+func New(maxRate, minRate float64) *Limiter {
+	if maxRate <= 0 {
+		maxRate = DefaultMaxRate
+	}
+	if minRate <= 0 || minRate > maxRate {
+		minRate = min(DefaultMinRate, maxRate)
+	}
+	return &Limiter{maxRate: maxRate, minRate: minRate, hosts: make(map[string]*host)}
+}
+
+// getHost returns the state of the host, the mutex must be held
+func (l *Limiter) getHost(key string) *host {
+	h, ok := l.hosts[key]
+	if !ok {
+		h = &host{rate: l.maxRate}
+		l.hosts[key] = h
+	}
+	return h
+}
+
+// Take blocks until a request can be sent to the host of the input,
+// the context error is returned if it is done before
+func (l *Limiter) Take(ctx context.Context, input string) error {
+	key := HostKey(input)
+	l.mutex.Lock()
+	h := l.getHost(key)
+	now := time.Now()
+	if h.next.Before(now) {
+		h.next = now
+	}
+	wait := h.next.Sub(now)
+	h.next = h.next.Add(time.Duration(float64(time.Second) / h.rate))
+	l.mutex.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe records the outcome of a request sent to the host of the input
+// and adapts the rate of the host accordingly
+func (l *Limiter) Observe(input string, latency time.Duration, resp *http.Response, err error) {
+	key := HostKey(input)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	h := l.getHost(key)
+
+	switch {
+	case err != nil:
+		l.decrease(h, h.rate/decreaseFactor)
+	case resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable):
+		l.decrease(h, h.rate/decreaseFactor)
+		if pause := retryAfter(resp); pause > 0 {
+			if next := time.Now().Add(pause); next.After(h.next) {
+				h.next = next
+			}
+		}
+	default:
+		if h.latency == 0 {
+			h.latency = latency
+		} else {
+			h.latency = time.Duration(float64(h.latency)*(1-latencyWeight) + float64(latency)*latencyWeight)
+		}
+		if h.baseline == 0 || h.latency < h.baseline {
+			h.baseline = h.latency
+		}
+		if h.latency > h.baseline*latencyFactor {
+			l.decrease(h, h.rate*slowdownFactor)
+			return
+		}
+		h.rate = min(l.maxRate, h.rate+l.maxRate/recoverySteps)
+	}
+}
+
+// decrease sets the rate of the host, bounded by the minimum rate
+func (l *Limiter) decrease(h *host, rate float64) {
+	if h.rate <= l.minRate {
+		return
+	}
+	h.rate = max(l.minRate, rate)
+	h.throttled++
+}
+
+// Rate returns the current rate of the host of the input in requests per second
+func (l *Limiter) Rate(input string) float64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if h, ok := l.hosts[HostKey(input)]; ok {
+		return h.rate
+	}
+	return l.maxRate
+}
+
+// HostReport contains the adaptive rate of a throttled host
+type HostReport struct {
+	Host string
+	// Rate is the current rate of the host in requests per second
+	Rate float64
+	// Throttled is the number of times the rate of the host was reduced
+	Throttled int
+}
+
+// Report returns the hosts throttled during the scan sorted by host
+func (l *Limiter) Report() []HostReport {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	report := []HostReport{}
+	for key, h := range l.hosts {
+		if h.throttled > 0 {
+			report = append(report, HostReport{Host: key, Rate: h.rate, Throttled: h.throttled})
+		}
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Host < report[j].Host })
+	return report
+}
+
+// HostKey returns the host:port of an url input, other inputs are returned as is
+func HostKey(input string) string {
+	if !strings.HasPrefix(input, "http") {
+		return input
+	}
+	parsed, err := url.Parse(input)
+	if err != nil || parsed.Host == "" {
+		return input
+	}
+	if parsed.Port() != "" {
+		return parsed.Host
+	}
+	if parsed.Scheme == "https" {
+		return net.JoinHostPort(parsed.Hostname(), "443")
+	}
+	return net.JoinHostPort(parsed.Hostname(), "80")
+}
+
+// retryAfter returns the pause requested by the Retry-After header of the response
+func retryAfter(resp *http.Response) time.Duration {
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	var pause time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		pause = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		pause = time.Until(date)
+	}
+	if pause <= 0 {
+		return 0
+	}
+	return min(pause, maxRetryAfter)
+}
@@ -0,0 +1,80 @@
+package hostratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterThrottle(t *testing.T) {
+	limiter := New(100, 10)
+	input := "https://example.com/path"
+
+	limiter.Observe(input, 10*time.Millisecond, nil, errors.New("connection refused"))
+	require.Equal(t, 50.0, limiter.Rate(input))
+	limiter.Observe(input, 10*time.Millisecond, &http.Response{StatusCode: http.StatusTooManyRequests}, nil)
+	require.Equal(t, 25.0, limiter.Rate(input))
+	limiter.Observe(input, 10*time.Millisecond, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	limiter.Observe(input, 10*time.Millisecond, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	require.Equal(t, 10.0, limiter.Rate(input), "rate should be bounded by the minimum rate")
+
+	// the other hosts are not throttled
+	require.Equal(t, 100.0, limiter.Rate("http://example.org"))
+
+	// fast responses recover the rate up to the maximum rate
+	for i := 0; i < recoverySteps; i++ {
+		limiter.Observe(input, 10*time.Millisecond, &http.Response{StatusCode: http.StatusOK}, nil)
+	}
+	require.Equal(t, 100.0, limiter.Rate(input))
+
+	require.Equal(t, []HostReport{{Host: "example.com:443", Rate: 100, Throttled: 4}}, limiter.Report())
+}
+
+func TestLimiterLatency(t *testing.T) {
+	limiter := New(100, 1)
+	input := "http://example.com"
+
+	limiter.Observe(input, 10*time.Millisecond, &http.Response{StatusCode: http.StatusOK}, nil)
+	require.Equal(t, 100.0, limiter.Rate(input))
+	for i := 0; i < 10; i++ {
+		limiter.Observe(input, time.Second, &http.Response{StatusCode: http.StatusOK}, nil)
+	}
+	require.Less(t, limiter.Rate(input), 100.0, "slow responses should reduce the rate")
+}
+
+func TestLimiterTake(t *testing.T) {
+	limiter := New(20, 1)
+	input := "http://example.com"
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.Nil(t, limiter.Take(context.Background(), input))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// the requests are paused until the time requested by the server
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"1"}}}
+	limiter.Observe(input, 0, resp, nil)
+	start = time.Now()
+	require.Nil(t, limiter.Take(context.Background(), input))
+	require.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+
+	// the pauses are interrupted by the cancellation of the scan
+	limiter.Observe(input, 0, resp, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	require.ErrorIs(t, limiter.Take(ctx, input), context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 500*time.Millisecond, "could not interrupt the pause")
+}
+
+func TestHostKey(t *testing.T) {
+	require.Equal(t, "example.com:443", HostKey("https://example.com/path"))
+	require.Equal(t, "example.com:80", HostKey("http://example.com"))
+	require.Equal(t, "example.com:8080", HostKey("http://example.com:8080/"))
+	require.Equal(t, "example.com", HostKey("example.com"))
+}
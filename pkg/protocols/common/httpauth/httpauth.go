@@ -0,0 +1,131 @@
+// Package httpauth implements the connection based and the digest authentications of the http requests
+package httpauth
+
+import (
+	"strings"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// authentication types
+const (
+	// NTLM authenticates with the NTLM scheme
+	NTLM = "ntlm"
+	// Negotiate authenticates with NTLM tokens of the Negotiate scheme
+	Negotiate = "negotiate"
+	// Kerberos authenticates with Kerberos tokens of the Negotiate scheme (SPNEGO)
+	Kerberos = "kerberos"
+	// Digest authenticates with the Digest scheme (RFC 7616)
+	Digest = "digest"
+)
+
+// Config contains the credentials of a connection based or digest http authentication
+type Config struct {
+	// description: |
+	//   Type is the authentication scheme.
+	// values:
+	//   - "ntlm"
+	//   - "negotiate"
+	//   - "kerberos"
+	//   - "digest"
+	Type string `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"title=authentication type,description=Authentication scheme of the requests,enum=ntlm,enum=negotiate,enum=kerberos,enum=digest"`
+	// description: |
+	//   Username is the username of the authentication.
+	Username string `yaml:"username,omitempty" json:"username,omitempty" jsonschema:"title=username,description=Username of the authentication"`
+	// description: |
+	//   Password is the password of the authentication.
+	Password string `yaml:"password,omitempty" json:"password,omitempty" jsonschema:"title=password,description=Password of the authentication"`
+	// description: |
+	//   Domain is the domain of the user, the domain can also be given as DOMAIN\username.
+	Domain string `yaml:"domain,omitempty" json:"domain,omitempty" jsonschema:"title=domain,description=Domain of the user"`
+	// description: |
+	//   KDC is the address of the Kerberos key distribution center, the KDCs
+	//   of the realm are looked up with DNS if empty.
+	// examples:
+	//   - value: "\"dc01.corp.local:88\""
+	KDC string `yaml:"kdc,omitempty" json:"kdc,omitempty" jsonschema:"title=kerberos kdc,description=Address of the Kerberos key distribution center"`
+	// description: |
+	//   SPN is the service principal name of the Kerberos tickets, HTTP/<hostname> is used if empty.
+	SPN string `yaml:"spn,omitempty" json:"spn,omitempty" jsonschema:"title=kerberos spn,description=Service principal name of the Kerberos tickets"`
+
+	// Keytab and CCache are the paths of the Kerberos keytab and credential cache,
+	// they are only set from the options since templates can't read local files.
+	Keytab string `yaml:"-" json:"-"`
+	CCache string `yaml:"-" json:"-"`
+}
+
+// IsEmpty returns true if no authentication is configured
+func (c *Config) IsEmpty() bool {
+	return c == nil || c.Type == ""
+}
+
+// Merge returns the config or the fallback if the config is empty
+func (c *Config) Merge(fallback *Config) *Config {
+	if c.IsEmpty() {
+		return fallback
+	}
+	return c
+}
+
+// Hash returns a key identifying the credentials of the config
+func (c *Config) Hash() string {
+	if c.IsEmpty() {
+		return ""
+	}
+	return strings.Join([]string{c.Type, c.Domain, c.Username, c.Password, c.KDC, c.SPN, c.Keytab, c.CCache}, "|")
+}
+
+// Validate validates the type and the credentials of the config
+func (c *Config) Validate() error {
+	if c.IsEmpty() {
+		return nil
+	}
+	switch strings.ToLower(c.Type) {
+	case NTLM, Negotiate, Digest:
+	case Kerberos:
+		// the principal is read from the credential cache
+		if c.CCache != "" {
+			return nil
+		}
+	default:
+		return errorutil.NewWithTag("httpauth", "invalid authentication type %s, supported: ntlm, negotiate, kerberos, digest", c.Type)
+	}
+	if c.Username == "" {
+		return errorutil.NewWithTag("httpauth", "username is required for %s authentication", c.Type)
+	}
+	if strings.EqualFold(c.Type, Kerberos) {
+		if realm, _ := c.Principal(); realm == "" {
+			return errorutil.NewWithTag("httpauth", "realm is required for kerberos authentication")
+		}
+		if c.Password == "" && c.Keytab == "" {
+			return errorutil.NewWithTag("httpauth", "password or keytab is required for kerberos authentication")
+		}
+	}
+	return nil
+}
+
+// Credentials returns the domain and the username of the config,
+// the domain is taken from the DOMAIN\username format if not set.
+func (c *Config) Credentials() (string, string) {
+	domain, username := c.Domain, c.Username
+	if before, after, ok := strings.Cut(username, `\`); ok {
+		username = after
+		if domain == "" {
+			domain = before
+		}
+	}
+	return domain, username
+}
+
+// Principal returns the uppercase realm and the username of the Kerberos principal,
+// the realm is taken from the DOMAIN\username or username@REALM formats if not set.
+func (c *Config) Principal() (string, string) {
+	realm, username := c.Credentials()
+	if before, after, ok := strings.Cut(username, "@"); ok {
+		username = before
+		if realm == "" {
+			realm = after
+		}
+	}
+	return strings.ToUpper(realm), username
+}
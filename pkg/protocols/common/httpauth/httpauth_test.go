@@ -0,0 +1,53 @@
+package httpauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	require.Nil(t, (*Config)(nil).Validate())
+	require.Nil(t, (&Config{Type: "NTLM", Username: "admin"}).Validate())
+	require.Nil(t, (&Config{Type: Digest, Username: "admin"}).Validate())
+	require.ErrorContains(t, (&Config{Type: "basic", Username: "admin"}).Validate(), "invalid authentication type basic")
+	require.ErrorContains(t, (&Config{Type: "negotiate"}).Validate(), "username is required for negotiate authentication")
+}
+
+func TestConfigCredentials(t *testing.T) {
+	domain, username := (&Config{Username: `CORP\admin`}).Credentials()
+	require.Equal(t, "CORP", domain)
+	require.Equal(t, "admin", username)
+
+	// the domain field takes precedence over the username prefix
+	domain, username = (&Config{Username: `CORP\admin`, Domain: "LAB"}).Credentials()
+	require.Equal(t, "LAB", domain)
+	require.Equal(t, "admin", username)
+}
+
+func TestConfigMerge(t *testing.T) {
+	fallback := &Config{Type: NTLM, Username: "global"}
+	require.Equal(t, fallback, (*Config)(nil).Merge(fallback))
+	config := &Config{Type: Negotiate, Username: "template"}
+	require.Equal(t, config, config.Merge(fallback))
+	require.Equal(t, "", (*Config)(nil).Hash())
+}
+
+func TestConfigValidateKerberos(t *testing.T) {
+	require.Nil(t, (&Config{Type: Kerberos, Username: "admin@corp.local", Password: "secret"}).Validate())
+	require.Nil(t, (&Config{Type: Kerberos, Username: `CORP\admin`, Keytab: "admin.keytab"}).Validate())
+	// the principal of the credential cache is used
+	require.Nil(t, (&Config{Type: Kerberos, CCache: "/tmp/krb5cc_1000"}).Validate())
+	require.ErrorContains(t, (&Config{Type: Kerberos, Username: "admin", Password: "secret"}).Validate(), "realm is required for kerberos authentication")
+	require.ErrorContains(t, (&Config{Type: Kerberos, Username: "admin", Domain: "corp.local"}).Validate(), "password or keytab is required for kerberos authentication")
+}
+
+func TestConfigPrincipal(t *testing.T) {
+	realm, username := (&Config{Username: "admin@corp.local"}).Principal()
+	require.Equal(t, "CORP.LOCAL", realm)
+	require.Equal(t, "admin", username)
+
+	realm, username = (&Config{Username: `corp\admin`}).Principal()
+	require.Equal(t, "CORP", realm)
+	require.Equal(t, "admin", username)
+}
@@ -3,6 +3,7 @@ package interactsh
 import (
 	"bytes"
 	"fmt"
+	"net/http"
 	"os"
 	"regexp"
 	"strings"
@@ -21,6 +22,8 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/writer"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/retryablehttp-go"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 )
@@ -79,13 +82,7 @@ func (c *Client) poll() error {
 		// do not init if disabled
 		return ErrInteractshClientNotInitialized
 	}
-	interactsh, err := client.New(&client.Options{
-		ServerURL:           c.options.ServerURL,
-		Token:               c.options.Authorization,
-		DisableHTTPFallback: c.options.DisableHttpFallback,
-		HTTPClient:          c.options.HTTPClient,
-		KeepAliveInterval:   time.Minute,
-	})
+	interactsh, err := c.newInteractshClient()
 	if err != nil {
 		return errorutil.NewWithErr(err).Msgf("could not create client")
 	}
@@ -207,6 +204,74 @@ func (c *Client) AlreadyMatched(data *RequestData) bool {
 	return c.matchedTemplates.Has(hash(data.Event.InternalEvent))
 }
 
+// restrictedHTTPClient returns an http client for interactsh registration and polling
+// requests which dials through the shared dialer rejecting private addresses
+func restrictedHTTPClient() *retryablehttp.Client {
+	httpClient := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	for _, client := range []*http.Client{httpClient.HTTPClient, httpClient.HTTPClient2} {
+		if transport, ok := client.Transport.(*http.Transport); ok {
+			transport.DialContext = protocolstate.Dialer.Dial
+		}
+	}
+	return httpClient
+}
+
+// newInteractshClient registers with the configured servers in order until one succeeds.
+// default servers are left to the client which registers with a random one
+func (c *Client) newInteractshClient() (*client.Client, error) {
+	servers := []string{c.options.ServerURL}
+	if c.options.ServerURL != client.DefaultOptions.ServerURL {
+		servers = splitValues(c.options.ServerURL)
+	}
+	if len(servers) == 0 {
+		return nil, errors.New("no interactsh server provided")
+	}
+	tokens := splitValues(c.options.Authorization)
+	httpClient := c.options.HTTPClient
+	if protocolstate.IsPrivateIPDisallowed() {
+		httpClient = restrictedHTTPClient()
+	}
+
+	var errs []error
+	for i, server := range servers {
+		var token string
+		switch {
+		case len(tokens) == 1:
+			token = tokens[0]
+		case i < len(tokens):
+			token = tokens[i]
+		}
+		interactsh, err := client.New(&client.Options{
+			ServerURL:                server,
+			Token:                    token,
+			DisableHTTPFallback:      c.options.DisableHttpFallback,
+			CorrelationIdLength:      c.options.CorrelationIdLength,
+			CorrelationIdNonceLength: c.options.CorrelationIdNonceLength,
+			HTTPClient:               httpClient,
+			KeepAliveInterval:        time.Minute,
+		})
+		if err == nil {
+			return interactsh, nil
+		}
+		if len(servers) > 1 {
+			gologger.Verbose().Msgf("Could not register to interactsh server %s: %s, trying next server", server, err)
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// splitValues splits comma separated values ignoring empty ones
+func splitValues(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
 // URL returns a new URL that can be interacted with
 func (c *Client) URL() (string, error) {
 	// first time initialization
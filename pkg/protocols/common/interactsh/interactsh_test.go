@@ -0,0 +1,46 @@
+package interactsh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newRegistrationServer(token string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":"registration successful"}`))
+	}))
+}
+
+func TestNewInteractshClientFallback(t *testing.T) {
+	first := newRegistrationServer("first-token")
+	defer first.Close()
+	second := newRegistrationServer("second-token")
+	defer second.Close()
+
+	c := &Client{options: &Options{
+		ServerURL:                first.URL + "," + second.URL,
+		Authorization:            "invalid-token,second-token",
+		CorrelationIdLength:      10,
+		CorrelationIdNonceLength: 5,
+		DisableHttpFallback:      true,
+	}}
+	interactsh, err := c.newInteractshClient()
+	require.Nil(t, err, "could not register with fallback server")
+	defer interactsh.Close()
+
+	url := interactsh.URL()
+	require.True(t, strings.HasSuffix(url, "."+strings.TrimPrefix(second.URL, "http://")), "could not register with second server")
+	require.Len(t, url[:strings.Index(url, ".")], 15, "could not use custom correlation id length")
+
+	c.options.Authorization = "invalid-token"
+	_, err = c.newInteractshClient()
+	require.NotNil(t, err, "could not get error when all servers fail")
+}
@@ -12,10 +12,16 @@ import (
 
 // Options contains configuration options for interactsh nuclei integration.
 type Options struct {
-	// ServerURL is the URL of the interactsh server.
+	// ServerURL is the URL of the interactsh server. multiple comma separated
+	// servers are tried in order until registration succeeds
 	ServerURL string
-	// Authorization is the Authorization header value
+	// Authorization is the Authorization header value. multiple comma separated
+	// values are used for the server at the same position
 	Authorization string
+	// CorrelationIdLength is the length of the correlation id of the server
+	CorrelationIdLength int
+	// CorrelationIdNonceLength is the length of the nonce appended to the correlation id
+	CorrelationIdNonceLength int
 	// CacheSize is the numbers of requests to keep track of at a time.
 	// Older items are discarded in LRU manner in favor of new requests.
 	CacheSize int
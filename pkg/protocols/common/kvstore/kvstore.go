@@ -0,0 +1,120 @@
+// Package kvstore implements the scan scoped key-value store shared by the
+// templates, ex: a token stored by a login request and read by other
+// requests and templates of the scan.
+package kvstore
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxKeys is the default maximum number of keys of the store
+	DefaultMaxKeys = 1024
+	// DefaultMaxValueSize is the default maximum size of a value in bytes
+	DefaultMaxValueSize = 64 * 1024
+)
+
+// Default is the store of the scan, it is reset when the scan is initialized
+var Default = New(DefaultMaxKeys, DefaultMaxValueSize)
+
+// Store is a key-value store of string values with expiration.
+type Store struct {
+	mu           sync.Mutex
+	items        map[string]item
+	maxKeys      int
+	maxValueSize int
+}
+
+type item struct {
+	value   string
+	expires time.Time
+}
+
+// expired returns true if the item has a ttl which elapsed
+func (i item) expired(now time.Time) bool {
+	return !i.expires.IsZero() && now.After(i.expires)
+}
+
+// New returns a store holding up to maxKeys values of up to maxValueSize bytes
+func New(maxKeys, maxValueSize int) *Store {
+	return &Store{
+		items:        make(map[string]item),
+		maxKeys:      maxKeys,
+		maxValueSize: maxValueSize,
+	}
+}
+
+// Set stores the value of the key, the value expires after the ttl
+// or at the end of the scan if the ttl is 0.
+func (s *Store) Set(key, value string, ttl time.Duration) error {
+	if key == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+	if ttl < 0 {
+		return fmt.Errorf("ttl cannot be negative")
+	}
+	if len(value) > s.maxValueSize {
+		return fmt.Errorf("value of %q exceeds the maximum size of %d bytes", key, s.maxValueSize)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := s.items[key]; !ok && len(s.items) >= s.maxKeys {
+		s.purge(now)
+		if len(s.items) >= s.maxKeys {
+			return fmt.Errorf("store exceeds the maximum of %d keys", s.maxKeys)
+		}
+	}
+	stored := item{value: value}
+	if ttl > 0 {
+		stored.expires = now.Add(ttl)
+	}
+	s.items[key] = stored
+	return nil
+}
+
+// Get returns the value of the key, ok is false if the key
+// does not exist or is expired.
+func (s *Store) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.items[key]
+	if !ok {
+		return "", false
+	}
+	if stored.expired(time.Now()) {
+		delete(s.items, key)
+		return "", false
+	}
+	return stored.value, true
+}
+
+// Delete removes the key from the store
+func (s *Store) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.items, key)
+}
+
+// Reset removes all the keys from the store
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items = make(map[string]item)
+}
+
+// purge removes the expired keys
+func (s *Store) purge(now time.Time) {
+	for key, stored := range s.items {
+		if stored.expired(now) {
+			delete(s.items, key)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package kvstore
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store := New(2, 8)
+
+	require.Nil(t, store.Set("token", "secret", 0))
+	value, ok := store.Get("token")
+	require.True(t, ok)
+	require.Equal(t, "secret", value)
+
+	store.Delete("token")
+	_, ok = store.Get("token")
+	require.False(t, ok)
+
+	require.EqualError(t, store.Set("", "value", 0), "key cannot be empty")
+	require.EqualError(t, store.Set("token", strings.Repeat("a", 9), 0), `value of "token" exceeds the maximum size of 8 bytes`)
+
+	store.Reset()
+	_, ok = store.Get("token")
+	require.False(t, ok)
+}
+
+func TestStoreTTL(t *testing.T) {
+	store := New(2, 8)
+
+	require.Nil(t, store.Set("session", "a", 10*time.Millisecond))
+	require.Nil(t, store.Set("token", "b", 0))
+	require.EqualError(t, store.Set("user", "c", 0), "store exceeds the maximum of 2 keys")
+	// updating an existing key does not count against the limit
+	require.Nil(t, store.Set("token", "d", 0))
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok := store.Get("session")
+	require.False(t, ok)
+	// the expired keys are purged when the store is full
+	require.Nil(t, store.Set("session", "e", 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	require.Nil(t, store.Set("user", "c", 0))
+
+	value, ok := store.Get("token")
+	require.True(t, ok)
+	require.Equal(t, "d", value)
+}
@@ -3,6 +3,10 @@ package protocolinit
 import (
 	"github.com/corpix/uarand"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/plugins"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/kvstore"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/dns/dnsclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
@@ -15,6 +19,10 @@ import (
 // Init initializes the client pools for the protocols
 func Init(options *types.Options) error {
 	uarand.Default = uarand.NewWithCustomList(userAgents)
+	// the helper functions are read concurrently by the templates from now on
+	dsl.SealHelperFunctions()
+	// the values stored by the templates are scoped to the scan
+	kvstore.Default.Reset()
 
 	if err := protocolstate.Init(options); err != nil {
 		return err
@@ -25,6 +33,13 @@ func Init(options *types.Options) error {
 	if err := httpclientpool.Init(options); err != nil {
 		return err
 	}
+	if options.AllowRemotePayloads {
+		client, err := httpclientpool.Get(options, &httpclientpool.Configuration{})
+		if err != nil {
+			return err
+		}
+		generators.InitRemotePayloads(client)
+	}
 	if err := signerpool.Init(options); err != nil {
 		return err
 	}
@@ -34,6 +49,9 @@ func Init(options *types.Options) error {
 	if err := rdapclientpool.Init(options); err != nil {
 		return err
 	}
+	if err := plugins.Load(options.Plugins); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -74,4 +92,6 @@ var userAgents = []string{
 
 func Close() {
 	protocolstate.Dialer.Close()
+	plugins.Close()
+	kvstore.Default.Reset()
 }
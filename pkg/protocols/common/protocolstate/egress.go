@@ -0,0 +1,124 @@
+package protocolstate
+
+import (
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/projectdiscovery/networkpolicy"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	iputil "github.com/projectdiscovery/utils/ip"
+)
+
+var (
+	// disallowPrivateIPs is true if connections to private and reserved addresses are rejected
+	disallowPrivateIPs bool
+
+	// privateNetworks contains the private and reserved ranges rejected by disallow-private-ips
+	privateNetworks = parseNetworks(append(append([]string{"::/128"}, networkpolicy.DefaultIPv4DenylistRanges...), networkpolicy.DefaultIPv6DenylistRanges...))
+
+	// ErrPrivateAddress is returned when dialing a private or reserved address with disallow-private-ips
+	ErrPrivateAddress = errorutil.NewWithTag("protocolstate", "private or reserved address not allowed by disallow-private-ips")
+)
+
+// parseNetworks parses the given cidr ranges skipping invalid ones
+func parseNetworks(ranges []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(ranges))
+	for _, cidr := range ranges {
+		if _, network, err := net.ParseCIDR(cidr); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// IsPrivateIPDisallowed returns true if connections to private and reserved addresses are rejected
+func IsPrivateIPDisallowed() bool {
+	return disallowPrivateIPs
+}
+
+// IsPrivateIP returns true if ip belongs to a private or reserved range,
+// ipv4-mapped ipv6 addresses are checked as ipv4 addresses
+func IsPrivateIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// controlPrivateIPs rejects connections to private and reserved addresses and is meant
+// to be used as net.Dialer.Control, the check runs on the address actually connected to
+// so that redirects and hosts re-resolving to internal addresses (dns rebinding) are covered
+func controlPrivateIPs(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if IsPrivateIP(net.ParseIP(host)) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// controlConnection applies the enabled connection restrictions
+func controlConnection(network, address string, c syscall.RawConn) error {
+	if len(ipVersions) > 0 {
+		if err := controlIPVersion(network, address, c); err != nil {
+			return err
+		}
+	}
+	if disallowPrivateIPs {
+		return controlPrivateIPs(network, address, c)
+	}
+	return nil
+}
+
+// CheckHost returns an error if private addresses are disallowed and host (ip or
+// hostname, with optional port) is or resolves to a private or reserved address,
+// it is meant for connections not made through the shared dialer
+func CheckHost(host string) error {
+	if !disallowPrivateIPs {
+		return nil
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.Trim(host, "[]")
+	if iputil.IsIP(host) {
+		if IsPrivateIP(net.ParseIP(host)) {
+			return ErrPrivateAddress
+		}
+		return nil
+	}
+	for _, ip := range resolveHost(host) {
+		if IsPrivateIP(ip) {
+			return ErrPrivateAddress
+		}
+	}
+	return nil
+}
+
+// resolveHost returns the resolved ips of hostname using the shared dialer if initialized
+func resolveHost(hostname string) []net.IP {
+	if Dialer == nil {
+		ips, _ := net.LookupIP(hostname)
+		return ips
+	}
+	data, err := Dialer.GetDNSData(hostname)
+	if err != nil || data == nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, ip := range append(append([]string{}, data.A...), data.AAAA...) {
+		ips = append(ips, net.ParseIP(ip))
+	}
+	return ips
+}
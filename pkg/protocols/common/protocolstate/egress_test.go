@@ -0,0 +1,30 @@
+package protocolstate
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateIPs(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0", "::1", "::", "fd00::1", "fe80::1", "::ffff:127.0.0.1"} {
+		require.True(t, IsPrivateIP(net.ParseIP(ip)), "could not detect private ip %s", ip)
+	}
+	for _, ip := range []string{"1.1.1.1", "8.8.8.8", "2606:4700:4700::1111"} {
+		require.False(t, IsPrivateIP(net.ParseIP(ip)), "could not allow public ip %s", ip)
+	}
+
+	defer func() {
+		disallowPrivateIPs = false
+	}()
+	require.Nil(t, CheckHost("127.0.0.1:80"), "could not allow private ips by default")
+	require.Nil(t, controlConnection("tcp4", "127.0.0.1:80", nil), "could not allow private ips by default")
+
+	disallowPrivateIPs = true
+	require.ErrorIs(t, controlConnection("tcp4", "10.0.0.1:443", nil), ErrPrivateAddress)
+	require.ErrorIs(t, controlConnection("tcp6", "[::ffff:192.168.0.1]:443", nil), ErrPrivateAddress)
+	require.Nil(t, controlConnection("tcp4", "1.1.1.1:443", nil))
+	require.ErrorIs(t, CheckHost("[::1]:8080"), ErrPrivateAddress)
+	require.Nil(t, CheckHost("1.1.1.1"))
+}
@@ -75,8 +75,11 @@ func isValidHost(targetUrl string) bool {
 		return false
 	}
 	targetUrl = urlx.Hostname()
-	_, ok := networkPolicy.ValidateHost(targetUrl)
-	return ok
+	if _, ok := networkPolicy.ValidateHost(targetUrl); !ok {
+		return false
+	}
+	// the browser resolves hostnames itself so their addresses are checked too
+	return CheckHost(targetUrl) == nil
 }
 
 // IsHostAllowed checks if the host is allowed by network policy
@@ -84,6 +87,8 @@ func IsHostAllowed(targetUrl string) bool {
 	if networkPolicy == nil {
 		return true
 	}
-	_, ok := networkPolicy.ValidateHost(targetUrl)
-	return ok
+	if _, ok := networkPolicy.ValidateHost(targetUrl); !ok {
+		return false
+	}
+	return CheckHost(targetUrl) == nil
 }
@@ -0,0 +1,227 @@
+package protocolstate
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	iputil "github.com/projectdiscovery/utils/ip"
+)
+
+var (
+	// ipVersions contains the address families allowed for connections
+	// in preference order, empty if connections are not restricted
+	ipVersions []string
+
+	// HappyEyeballsDelay is the delay after which the next address is
+	// tried while a connection to the preferred one is still pending
+	HappyEyeballsDelay = 300 * time.Millisecond
+
+	// ErrAddressFamilyNotAllowed is returned when dialing an address of an address family not allowed by ip-version
+	ErrAddressFamilyNotAllowed = errorutil.NewWithTag("protocolstate", "address family not allowed by ip-version")
+)
+
+// setIPVersions sets the allowed address families (4, 6) in preference order
+func setIPVersions(versions []string) {
+	ipVersions = nil
+	for _, version := range versions {
+		version = strings.TrimSpace(version)
+		if version == "4" || version == "6" {
+			ipVersions = append(ipVersions, version)
+		}
+	}
+}
+
+// IsIPVersionAllowed returns true if connections to ip are allowed by ip-version
+func IsIPVersionAllowed(ip string) bool {
+	if len(ipVersions) == 0 {
+		return true
+	}
+	version := "4"
+	if iputil.IsIPv6(ip) {
+		version = "6"
+	}
+	for _, allowed := range ipVersions {
+		if allowed == version {
+			return true
+		}
+	}
+	return false
+}
+
+// controlIPVersion rejects connections of address families not allowed by ip-version
+// and is meant to be used as net.Dialer.Control
+func controlIPVersion(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if !IsIPVersionAllowed(host) {
+		return ErrAddressFamilyNotAllowed
+	}
+	return nil
+}
+
+// FilterAddresses returns the host:port addresses allowed by ip-version, all of
+// them if none is allowed since they are not targets (ex: resolvers)
+func FilterAddresses(addresses []string) []string {
+	if len(ipVersions) == 0 {
+		return addresses
+	}
+	var filtered []string
+	for _, address := range addresses {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		if !iputil.IsIP(host) || IsIPVersionAllowed(host) {
+			filtered = append(filtered, address)
+		}
+	}
+	if len(filtered) == 0 {
+		return addresses
+	}
+	return filtered
+}
+
+// preferredIPs returns the resolved ips of hostname allowed by ip-version
+// in preference order, interleaving address families like RFC 8305 does
+func preferredIPs(hostname string) []string {
+	data, err := Dialer.GetDNSData(hostname)
+	if err != nil || data == nil {
+		return nil
+	}
+	families := map[string][]string{"4": data.A, "6": data.AAAA}
+	var ordered [][]string
+	for _, version := range ipVersions {
+		ordered = append(ordered, families[version])
+	}
+	var ips []string
+	for i := 0; ; i++ {
+		added := false
+		for _, family := range ordered {
+			if i < len(family) {
+				ips = append(ips, family[i])
+				added = true
+			}
+		}
+		if !added {
+			return ips
+		}
+	}
+}
+
+// DialFunc dials an address with the given context
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// WithIPVersion wraps a fastdialer dial function to race connections to the allowed
+// address families of a host in preference order (happy eyeballs) when both are allowed
+func WithIPVersion(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if len(ipVersions) < 2 || ctx.Value(fastdialer.IP) != nil {
+			return dial(ctx, network, address)
+		}
+		hostname, _, err := net.SplitHostPort(address)
+		if err != nil || iputil.IsIP(hostname) {
+			return dial(ctx, network, address)
+		}
+		ips := preferredIPs(hostname)
+		if len(ips) < 2 {
+			return dial(ctx, network, address)
+		}
+		return dialHappyEyeballs(ctx, network, address, ips, dial)
+	}
+}
+
+// dialHappyEyeballs starts a connection to the next ip every HappyEyeballsDelay
+// or as soon as the previous one fails and returns the first established one
+func dialHappyEyeballs(ctx context.Context, network, address string, ips []string, dial DialFunc) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, len(ips))
+	next := 0
+	start := func() {
+		ipCtx := context.WithValue(ctx, fastdialer.IP, ips[next])
+		next++
+		go func() {
+			conn, err := dial(ipCtx, network, address)
+			results <- result{conn: conn, err: err}
+		}()
+	}
+	start()
+
+	timer := time.NewTimer(HappyEyeballsDelay)
+	defer timer.Stop()
+	var lastErr error
+	pending := 1
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if next < len(ips) {
+				start()
+				pending++
+				timer.Reset(HappyEyeballsDelay)
+			}
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				// close connections established after the winning one
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if res := <-results; res.conn != nil {
+							_ = res.conn.Close()
+						}
+					}
+				}(pending)
+				return res.conn, nil
+			}
+			lastErr = res.err
+			if next < len(ips) {
+				start()
+				pending++
+				timer.Reset(HappyEyeballsDelay)
+			}
+		}
+	}
+	return nil, lastErr
+}
+
+// ResolvedIPs returns the cached resolved ips of the host of a target
+// (url, host:port or host) allowed by ip-version
+func ResolvedIPs(target string) []string {
+	if Dialer == nil {
+		return nil
+	}
+	hostname := target
+	if strings.Contains(target, "://") {
+		if parsed, err := url.Parse(target); err == nil {
+			hostname = parsed.Hostname()
+		}
+	} else if host, _, err := net.SplitHostPort(target); err == nil {
+		hostname = host
+	}
+	if hostname == "" || iputil.IsIP(hostname) {
+		return nil
+	}
+	data, err := Dialer.GetDNSDataFromCache(hostname)
+	if err != nil || data == nil {
+		return nil
+	}
+	var ips []string
+	for _, ip := range append(append([]string{}, data.A...), data.AAAA...) {
+		if IsIPVersionAllowed(ip) {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
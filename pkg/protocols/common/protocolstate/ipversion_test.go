@@ -0,0 +1,61 @@
+package protocolstate
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPVersionFilter(t *testing.T) {
+	defer setIPVersions(nil)
+
+	setIPVersions([]string{"6"})
+	require.False(t, IsIPVersionAllowed("1.1.1.1"))
+	require.True(t, IsIPVersionAllowed("2606:4700:4700::1111"))
+	require.ErrorIs(t, controlIPVersion("tcp4", "1.1.1.1:443", nil), ErrAddressFamilyNotAllowed)
+	require.Nil(t, controlIPVersion("tcp6", "[2606:4700:4700::1111]:443", nil))
+	require.Equal(t, []string{"[2606:4700:4700::1111]:53"}, FilterAddresses([]string{"1.1.1.1:53", "[2606:4700:4700::1111]:53"}))
+	require.Equal(t, []string{"1.1.1.1:53"}, FilterAddresses([]string{"1.1.1.1:53"}), "could not keep addresses when none is allowed")
+
+	setIPVersions(nil)
+	require.True(t, IsIPVersionAllowed("1.1.1.1"), "could not allow all families by default")
+}
+
+func TestDialHappyEyeballs(t *testing.T) {
+	conns := make(chan net.Conn, 2)
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		switch ctx.Value(fastdialer.IP) {
+		case "2001:db8::1":
+			// preferred address never answers
+			<-ctx.Done()
+			return nil, ctx.Err()
+		case "192.0.2.1":
+			client, server := net.Pipe()
+			conns <- server
+			return client, nil
+		}
+		return nil, errors.New("unexpected address")
+	}
+
+	start := time.Now()
+	conn, err := dialHappyEyeballs(context.Background(), "tcp", "example.com:443", []string{"2001:db8::1", "192.0.2.1"}, dial)
+	require.Nil(t, err, "could not fallback to next address")
+	require.NotNil(t, conn)
+	require.Less(t, time.Since(start), HappyEyeballsDelay*3, "could not race addresses")
+	_ = conn.Close()
+	close(conns)
+	for c := range conns {
+		_ = c.Close()
+	}
+
+	failing := func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	}
+	_, err = dialHappyEyeballs(context.Background(), "tcp", "example.com:443", []string{"2001:db8::1", "192.0.2.1"}, failing)
+	require.NotNil(t, err, "could not get error when all addresses fail")
+}
@@ -6,6 +6,16 @@ import (
 	"github.com/projectdiscovery/gologger"
 )
 
+// DefaultJSPoolSize is the default maximum number of connections pooled by the javascript libraries
+const DefaultJSPoolSize = 50
+
+var jsPoolSize = DefaultJSPoolSize
+
+// JSPoolSize returns the maximum number of connections pooled by the javascript libraries
+func JSPoolSize() int {
+	return jsPoolSize
+}
+
 // NewJSRuntime returns a new javascript runtime
 // with defaults set
 // i.e sourcemap parsing is disabled by default
@@ -10,6 +10,7 @@ import (
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/networkpolicy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
 
@@ -23,7 +24,11 @@ func Init(options *types.Options) error {
 	}
 	lfaAllowed = options.AllowLocalFileAccess
 	opts := fastdialer.DefaultOptions
-	InitHeadless(options.RestrictLocalNetworkAccess, options.AllowLocalFileAccess)
+	disallowPrivateIPs = options.DisallowPrivateIPs
+	if options.JSPoolSize > 0 {
+		jsPoolSize = options.JSPoolSize
+	}
+	InitHeadless(options.RestrictLocalNetworkAccess || options.DisallowPrivateIPs, options.AllowLocalFileAccess)
 
 	switch {
 	case options.SourceIP != "" && options.Interface != "":
@@ -87,15 +92,38 @@ func Init(options *types.Options) error {
 		opts.ProxyDialer = &dialer
 	}
 
+	setIPVersions(options.IPVersion)
+	if len(ipVersions) > 0 || disallowPrivateIPs {
+		// connections to targets are restricted to the allowed address families
+		// and public addresses while the proxy dialer keeps using the unrestricted one
+		dialer := net.Dialer{
+			Timeout:   opts.DialerTimeout,
+			KeepAlive: opts.DialerKeepAlive,
+			DualStack: true,
+		}
+		if opts.Dialer != nil {
+			dialer = *opts.Dialer
+		}
+		dialer.Control = controlConnection
+		opts.Dialer = &dialer
+	}
+
 	if options.SystemResolvers {
 		opts.EnableFallback = true
 	}
 	if options.ResolversFile != "" {
 		opts.BaseResolvers = options.InternalResolversList
 	}
-	if options.RestrictLocalNetworkAccess {
+	if options.RestrictLocalNetworkAccess || options.DisallowPrivateIPs {
 		opts.Deny = append(networkpolicy.DefaultIPv4DenylistRanges, networkpolicy.DefaultIPv6DenylistRanges...)
 	}
+	if options.FIPS {
+		fips.Enable()
+	}
+	if fips.IsEnabled() {
+		// ztls does not use approved crypto implementations
+		opts.DisableZtlsFallback = true
+	}
 	opts.WithDialerHistory = true
 	opts.SNIName = options.SNI
 	// fastdialer now by default fallbacks to ztls when there are tls related errors
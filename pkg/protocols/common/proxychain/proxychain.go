@@ -0,0 +1,172 @@
+// Package proxychain dials the connections through a chain of upstream
+// http(s) and socks5 proxies, each proxy connecting to the next one
+package proxychain
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	proxyutils "github.com/projectdiscovery/utils/proxy"
+	"golang.org/x/net/proxy"
+)
+
+// DialFunc is the signature of a context dialer
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Chain is a chain of proxies
+type Chain struct {
+	proxies []*url.URL
+	dial    DialFunc
+}
+
+// Parse returns the urls of the proxies of a chain
+func Parse(proxies []string) ([]*url.URL, error) {
+	if len(proxies) == 0 {
+		return nil, errors.New("no proxies given")
+	}
+	var urls []*url.URL
+	for _, value := range proxies {
+		proxyURL, err := proxyutils.GetProxyURL(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid proxy %s", value)
+		}
+		urls = append(urls, &proxyURL)
+	}
+	return urls, nil
+}
+
+// New returns a chain of the proxies, the first proxy is dialed with dial
+func New(proxies []string, dial DialFunc) (*Chain, error) {
+	urls, err := Parse(proxies)
+	if err != nil {
+		return nil, err
+	}
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return &Chain{proxies: urls, dial: dial}, nil
+}
+
+// DialContext dials addr through the proxies of the chain
+func (c *Chain) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := c.dial(ctx, "tcp", address(c.proxies[0]))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not connect to proxy %s", c.proxies[0].Redacted())
+	}
+	for i, proxyURL := range c.proxies {
+		target := addr
+		if i+1 < len(c.proxies) {
+			target = address(c.proxies[i+1])
+		}
+		conn, err = connect(ctx, conn, proxyURL, network, target)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not connect to %s through proxy %s", target, proxyURL.Redacted())
+		}
+	}
+	return conn, nil
+}
+
+// connect opens a tunnel to target through the proxy connected with conn
+func connect(ctx context.Context, conn net.Conn, proxyURL *url.URL, network, target string) (net.Conn, error) {
+	switch proxyURL.Scheme {
+	case proxyutils.SOCKS5:
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+		}
+		dialer, err := proxy.SOCKS5("tcp", address(proxyURL), auth, connDialer{conn: conn})
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		tunnel, err := dialer.(proxy.ContextDialer).DialContext(ctx, network, target)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		return tunnel, nil
+	case proxyutils.HTTPS:
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname(), InsecureSkipVerify: true})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, errors.Errorf("proxy refused the connection: %s", resp.Status)
+	}
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// address returns the host:port of a proxy url
+func address(proxyURL *url.URL) string {
+	if proxyURL.Port() != "" {
+		return proxyURL.Host
+	}
+	port := "1080"
+	switch proxyURL.Scheme {
+	case proxyutils.HTTP:
+		port = "80"
+	case proxyutils.HTTPS:
+		port = "443"
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
+
+// connDialer returns the connection to the previous proxy of the chain to the socks5 dialer
+type connDialer struct {
+	conn net.Conn
+}
+
+func (d connDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.conn, nil
+}
+
+func (d connDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return d.conn, nil
+}
+
+// bufferedConn is a connection with data read ahead by the CONNECT response reader
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(data []byte) (int, error) {
+	return c.reader.Read(data)
+}
@@ -0,0 +1,131 @@
+package proxychain
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newConnectProxy returns a http proxy tunneling the CONNECT requests
+func newConnectProxy(t *testing.T, tunnels *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect || r.Header.Get("Proxy-Authorization") != "Basic dXNlcjpwYXNz" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.Nil(t, err, "could not hijack connection")
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		atomic.AddInt32(tunnels, 1)
+		go func() { _, _ = io.Copy(target, conn) }()
+		_, _ = io.Copy(conn, target)
+		conn.Close()
+		target.Close()
+	}))
+}
+
+// newSocksProxy returns a socks5 proxy without authentication supporting the ipv4 and domain addresses
+func newSocksProxy(t *testing.T, tunnels *int32) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				greeting := make([]byte, 3)
+				if _, err := io.ReadFull(conn, greeting); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte{5, 0})
+				header := make([]byte, 4)
+				if _, err := io.ReadFull(conn, header); err != nil {
+					return
+				}
+				var host []byte
+				switch header[3] {
+				case 1:
+					host = make([]byte, net.IPv4len+2)
+				case 3:
+					length := make([]byte, 1)
+					if _, err := io.ReadFull(conn, length); err != nil {
+						return
+					}
+					host = make([]byte, int(length[0])+2)
+				default:
+					return
+				}
+				if _, err := io.ReadFull(conn, host); err != nil {
+					return
+				}
+				hostname := string(host[:len(host)-2])
+				if header[3] == 1 {
+					hostname = net.IP(host[:net.IPv4len]).String()
+				}
+				port := binary.BigEndian.Uint16(host[len(host)-2:])
+				target, err := net.Dial("tcp", net.JoinHostPort(hostname, strconv.Itoa(int(port))))
+				if err != nil {
+					_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer target.Close()
+				_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				atomic.AddInt32(tunnels, 1)
+				go func() { _, _ = io.Copy(target, conn) }()
+				_, _ = io.Copy(conn, target)
+			}(conn)
+		}
+	}()
+	return listener
+}
+
+func TestChain(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("through the chain"))
+	}))
+	defer target.Close()
+
+	var connectTunnels, socksTunnels int32
+	connectProxy := newConnectProxy(t, &connectTunnels)
+	defer connectProxy.Close()
+	socksProxy := newSocksProxy(t, &socksTunnels)
+	defer socksProxy.Close()
+
+	chain, err := New([]string{"http://user:pass@" + connectProxy.Listener.Addr().String(), "socks5://" + socksProxy.Addr().String()}, nil)
+	require.Nil(t, err, "could not create chain")
+	client := &http.Client{Transport: &http.Transport{DialContext: chain.DialContext}}
+	resp, err := client.Get(target.URL)
+	require.Nil(t, err, "could not send request through the chain")
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(t, "through the chain", string(body))
+	require.Equal(t, int32(1), atomic.LoadInt32(&connectTunnels), "could not tunnel through the http proxy")
+	require.Equal(t, int32(1), atomic.LoadInt32(&socksTunnels), "could not tunnel through the socks5 proxy")
+
+	// the refusal of a proxy fails the connection
+	chain, err = New([]string{"http://" + connectProxy.Listener.Addr().String()}, nil)
+	require.Nil(t, err, "could not create chain")
+	_, err = chain.DialContext(context.Background(), "tcp", target.Listener.Addr().String())
+	require.ErrorContains(t, err, "407 Proxy Authentication Required")
+
+	_, err = New([]string{"ftp://127.0.0.1:21"}, nil)
+	require.NotNil(t, err, "could not get error for unsupported proxy")
+	_, err = New(nil, nil)
+	require.NotNil(t, err, "could not get error for empty chain")
+}
@@ -0,0 +1,250 @@
+// Package proxypool implements rotation of requests across a pool of upstream proxies
+package proxypool
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	proxyutils "github.com/projectdiscovery/utils/proxy"
+	"golang.org/x/net/proxy"
+)
+
+// Strategy is the strategy used to select a proxy for a request
+type Strategy string
+
+const (
+	// RoundRobin rotates proxies on every request
+	RoundRobin Strategy = "round-robin"
+	// Sticky always uses the same proxy for a host
+	Sticky Strategy = "sticky"
+)
+
+var (
+	// MaxFailures is the number of consecutive failures after which a proxy is considered dead
+	MaxFailures int32 = 3
+	// Cooldown is the duration after which a dead proxy is tried again
+	Cooldown = time.Minute
+
+	// ErrNoAliveProxy is returned when all proxies of the pool are dead
+	ErrNoAliveProxy = errorutil.NewWithTag("proxypool", "no alive proxy in pool")
+)
+
+// Default is the proxy pool used by nuclei clients, nil if rotation is disabled
+var Default *Pool
+
+// DialFunc is the signature of a context dialer
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Pool is a pool of upstream proxies of the same kind (http(s) or socks5)
+type Pool struct {
+	strategy Strategy
+	socks    bool
+	proxies  []*entry
+	// addresses maps host:port of proxies to their entry for health reporting
+	addresses map[string]*entry
+	counter   atomic.Uint64
+}
+
+type entry struct {
+	url       *url.URL
+	address   string
+	dialer    proxy.ContextDialer
+	failures  atomic.Int32
+	deadUntil atomic.Int64
+	logOnce   sync.Once
+}
+
+// IsValidStrategy returns true if the strategy is supported
+func IsValidStrategy(strategy string) bool {
+	return Strategy(strategy) == RoundRobin || Strategy(strategy) == Sticky
+}
+
+// New creates a pool from proxy urls with the given selection strategy
+func New(proxies []string, strategy Strategy) (*Pool, error) {
+	if len(proxies) == 0 {
+		return nil, errorutil.NewWithTag("proxypool", "no proxies given")
+	}
+	if !IsValidStrategy(string(strategy)) {
+		return nil, errorutil.NewWithTag("proxypool", "invalid proxy rotation strategy %s", strategy)
+	}
+	pool := &Pool{strategy: strategy, addresses: make(map[string]*entry)}
+	for i, value := range proxies {
+		proxyURL, err := proxyutils.GetProxyURL(value)
+		if err != nil {
+			return nil, err
+		}
+		socks := proxyURL.Scheme == proxyutils.SOCKS5
+		if i > 0 && socks != pool.socks {
+			return nil, errorutil.NewWithTag("proxypool", "http and socks5 proxies cannot be mixed in a pool")
+		}
+		pool.socks = socks
+
+		e := &entry{url: &proxyURL, address: canonicalAddress(&proxyURL)}
+		if socks {
+			dialer, err := proxy.FromURL(&proxyURL, proxy.Direct)
+			if err != nil {
+				return nil, err
+			}
+			contextDialer, ok := dialer.(proxy.ContextDialer)
+			if !ok {
+				return nil, errorutil.NewWithTag("proxypool", "socks dialer does not support context")
+			}
+			e.dialer = contextDialer
+		}
+		pool.proxies = append(pool.proxies, e)
+		pool.addresses[e.address] = e
+	}
+	return pool, nil
+}
+
+// IsSocks returns true if the pool contains socks5 proxies
+func (p *Pool) IsSocks() bool {
+	return p.socks
+}
+
+// Len returns the number of proxies in the pool
+func (p *Pool) Len() int {
+	return len(p.proxies)
+}
+
+// Check dials all proxies of the pool marking unreachable ones as dead
+// and returns the number of alive proxies
+func (p *Pool) Check(timeout time.Duration) int {
+	var alive atomic.Int32
+	wg := &sync.WaitGroup{}
+	for _, e := range p.proxies {
+		wg.Add(1)
+		go func(e *entry) {
+			defer wg.Done()
+			conn, err := net.DialTimeout("tcp", e.address, timeout)
+			if err != nil {
+				e.markDead()
+				gologger.Warning().Msgf("Proxy %s is not reachable: %s", e.url.Redacted(), err)
+				return
+			}
+			_ = conn.Close()
+			alive.Add(1)
+		}(e)
+	}
+	wg.Wait()
+	return int(alive.Load())
+}
+
+// Get returns the proxy to use for host according to the selection strategy
+func (p *Pool) Get(host string) (*url.URL, error) {
+	e := p.pick(host)
+	if e == nil {
+		return nil, ErrNoAliveProxy
+	}
+	return e.url, nil
+}
+
+// Proxy returns the proxy of a request and is meant to be used as http.Transport.Proxy
+func (p *Pool) Proxy(req *http.Request) (*url.URL, error) {
+	return p.Get(req.URL.Host)
+}
+
+// WrapDialer wraps the dialer used by a http transport to track the health of
+// http proxies, since connections to proxies are made through it
+func (p *Pool) WrapDialer(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if e, ok := p.addresses[addr]; ok {
+			e.report(err)
+		}
+		return conn, err
+	}
+}
+
+// DialContext dials addr through a socks5 proxy of the pool
+func (p *Pool) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _, _ := net.SplitHostPort(addr)
+	e := p.pick(host)
+	if e == nil {
+		return nil, ErrNoAliveProxy
+	}
+	if e.dialer == nil {
+		return nil, errorutil.NewWithTag("proxypool", "proxy %s is not a socks5 proxy", e.url.Redacted())
+	}
+	conn, err := e.dialer.DialContext(ctx, network, addr)
+	// errors returned by the proxy for unreachable targets are not proxy failures
+	if err == nil || isProxyDialError(err) {
+		e.report(err)
+	}
+	return conn, err
+}
+
+// isProxyDialError returns true if the socks dialer could not connect to the proxy itself
+func isProxyDialError(err error) bool {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	dialErr, ok := opErr.Err.(*net.OpError)
+	return ok && dialErr.Op == "dial"
+}
+
+// pick selects an alive proxy for host, nil if all of them are dead
+func (p *Pool) pick(host string) *entry {
+	var start int
+	switch p.strategy {
+	case Sticky:
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(host))
+		start = int(hash.Sum32() % uint32(len(p.proxies)))
+	default:
+		start = int(p.counter.Add(1) % uint64(len(p.proxies)))
+	}
+	// with sticky strategy hosts of a dead proxy move to the next alive one
+	now := time.Now().UnixNano()
+	for i := 0; i < len(p.proxies); i++ {
+		e := p.proxies[(start+i)%len(p.proxies)]
+		if e.deadUntil.Load() <= now {
+			return e
+		}
+	}
+	return nil
+}
+
+// report records the result of a connection made to the proxy
+func (e *entry) report(err error) {
+	if err == nil {
+		e.failures.Store(0)
+		return
+	}
+	if e.failures.Add(1) >= MaxFailures {
+		e.markDead()
+		e.logOnce.Do(func() {
+			gologger.Warning().Msgf("Proxy %s removed from pool after %d consecutive failures: %s", e.url.Redacted(), MaxFailures, err)
+		})
+	}
+}
+
+// canonicalAddress returns the host:port of a proxy url like http.Transport dials it
+func canonicalAddress(proxyURL *url.URL) string {
+	if proxyURL.Port() != "" {
+		return proxyURL.Host
+	}
+	port := "1080"
+	switch proxyURL.Scheme {
+	case proxyutils.HTTP:
+		port = "80"
+	case proxyutils.HTTPS:
+		port = "443"
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
+
+func (e *entry) markDead() {
+	e.failures.Store(0)
+	e.deadUntil.Store(time.Now().Add(Cooldown).UnixNano())
+}
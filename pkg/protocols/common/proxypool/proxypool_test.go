@@ -0,0 +1,80 @@
+package proxypool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolStrategies(t *testing.T) {
+	proxies := []string{"http://127.0.0.1:8081", "http://127.0.0.1:8082", "http://127.0.0.1:8083"}
+
+	roundRobin, err := New(proxies, RoundRobin)
+	require.Nil(t, err, "could not create pool")
+	seen := map[string]struct{}{}
+	for i := 0; i < 3; i++ {
+		proxyURL, err := roundRobin.Get("example.com")
+		require.Nil(t, err)
+		seen[proxyURL.Host] = struct{}{}
+	}
+	require.Len(t, seen, 3, "could not rotate across all proxies")
+
+	sticky, err := New(proxies, Sticky)
+	require.Nil(t, err, "could not create pool")
+	first, err := sticky.Get("example.com")
+	require.Nil(t, err)
+	for i := 0; i < 5; i++ {
+		proxyURL, err := sticky.Get("example.com")
+		require.Nil(t, err)
+		require.Equal(t, first.Host, proxyURL.Host, "could not get same proxy for host")
+	}
+
+	_, err = New([]string{"http://127.0.0.1:8081", "socks5://127.0.0.1:1080"}, RoundRobin)
+	require.NotNil(t, err, "could not get error for mixed proxies")
+	_, err = New(proxies, "random")
+	require.NotNil(t, err, "could not get error for invalid strategy")
+}
+
+func TestPoolRemovesDeadProxies(t *testing.T) {
+	pool, err := New([]string{"http://127.0.0.1:8081", "http://127.0.0.1:8082"}, Sticky)
+	require.Nil(t, err, "could not create pool")
+
+	failing := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	}
+	dial := pool.WrapDialer(failing)
+
+	dead, err := pool.Get("example.com")
+	require.Nil(t, err)
+	for i := 0; i < int(MaxFailures); i++ {
+		_, _ = dial(context.Background(), "tcp", dead.Host)
+	}
+	alive, err := pool.Get("example.com")
+	require.Nil(t, err)
+	require.NotEqual(t, dead.Host, alive.Host, "could not remove dead proxy")
+
+	for i := 0; i < int(MaxFailures); i++ {
+		_, _ = dial(context.Background(), "tcp", alive.Host)
+	}
+	_, err = pool.Get("example.com")
+	require.ErrorIs(t, err, ErrNoAliveProxy, "could not get error when all proxies are dead")
+}
+
+func TestPoolCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+
+	pool, err := New([]string{"http://" + listener.Addr().String(), "http://127.0.0.1:1"}, RoundRobin)
+	require.Nil(t, err, "could not create pool")
+	require.Equal(t, 1, pool.Check(time.Second), "could not get alive proxies")
+	for i := 0; i < 3; i++ {
+		proxyURL, err := pool.Get("example.com")
+		require.Nil(t, err)
+		require.Equal(t, listener.Addr().String(), proxyURL.Host, "could not skip unreachable proxy")
+	}
+}
@@ -0,0 +1,297 @@
+package robots
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const (
+	// UserAgent is the product token matched against the user-agent lines of robots.txt
+	UserAgent = "nuclei"
+	// maxFileSize is the maximum size of a robots.txt or security.txt file read
+	maxFileSize = 512 * 1024
+	// maxSkippedPaths is the number of skipped paths kept per host for the report
+	maxSkippedPaths = 5
+)
+
+// Policy fetches the robots.txt and security.txt of the scanned hosts once
+// and reports the requests skipped because robots.txt disallowed their path
+type Policy struct {
+	client *retryablehttp.Client
+
+	mutex sync.Mutex
+	hosts map[string]*host
+}
+
+type host struct {
+	once        sync.Once
+	rules       *Rules
+	securityTxt *SecurityTxt
+
+	skipped      int
+	skippedPaths []string
+}
+
+// New returns a new robots.txt policy fetching files with client
+func New(client *retryablehttp.Client) *Policy {
+	return &Policy{client: client, hosts: make(map[string]*host)}
+}
+
+// Allowed returns true if robots.txt of the host allows requesting the url,
+// disallowed requests are recorded as skipped coverage in the report
+func (p *Policy) Allowed(target *url.URL) bool {
+	origin := target.Scheme + "://" + target.Host
+	p.mutex.Lock()
+	h, ok := p.hosts[origin]
+	if !ok {
+		h = &host{}
+		p.hosts[origin] = h
+	}
+	p.mutex.Unlock()
+
+	h.once.Do(func() {
+		h.rules = p.fetchRules(origin)
+		h.securityTxt = p.fetchSecurityTxt(origin)
+	})
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+	if h.rules.Allowed(path) {
+		return true
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	h.skipped++
+	if len(h.skippedPaths) < maxSkippedPaths && !containsString(h.skippedPaths, target.Path) {
+		h.skippedPaths = append(h.skippedPaths, target.Path)
+	}
+	return false
+}
+
+// fetchRules returns the rules of a host, following RFC 9309 a missing robots.txt
+// allows everything while an unreachable one disallows everything
+func (p *Policy) fetchRules(origin string) *Rules {
+	data, status, err := p.fetch(origin + "/robots.txt")
+	switch {
+	case err != nil || status >= http.StatusInternalServerError:
+		gologger.Verbose().Msgf("[robots] Could not fetch robots.txt of %s, skipping all paths\n", origin)
+		return disallowAll
+	case status >= http.StatusBadRequest:
+		return &Rules{}
+	}
+	return Parse(strings.NewReader(data), UserAgent)
+}
+
+// fetchSecurityTxt returns the security.txt of a host, nil if none is published
+func (p *Policy) fetchSecurityTxt(origin string) *SecurityTxt {
+	for _, path := range []string{"/.well-known/security.txt", "/security.txt"} {
+		data, status, err := p.fetch(origin + path)
+		if err != nil || status != http.StatusOK {
+			continue
+		}
+		if securityTxt := ParseSecurityTxt(strings.NewReader(data)); !securityTxt.IsEmpty() {
+			gologger.Verbose().Msgf("[robots] %s publishes security.txt (contact: %s, policy: %s)\n", origin, strings.Join(securityTxt.Contact, ", "), strings.Join(securityTxt.Policy, ", "))
+			return securityTxt
+		}
+	}
+	return nil
+}
+
+func (p *Policy) fetch(target string) (string, int, error) {
+	req, err := retryablehttp.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+	if err != nil {
+		return "", 0, err
+	}
+	return string(data), resp.StatusCode, nil
+}
+
+// Report contains the coverage skipped because of robots.txt
+type Report struct {
+	// Skipped is the total number of skipped requests
+	Skipped int
+	// Hosts are the hosts with skipped requests sorted by count
+	Hosts []*HostReport
+}
+
+// HostReport contains the requests skipped on a host
+type HostReport struct {
+	Host    string
+	Skipped int
+	// Paths is a sample of the disallowed paths
+	Paths []string
+	// SecurityTxt is the security.txt published by the host if any
+	SecurityTxt *SecurityTxt
+}
+
+// Report returns the requests skipped so far
+func (p *Policy) Report() *Report {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	report := &Report{}
+	for origin, h := range p.hosts {
+		if h.skipped == 0 {
+			continue
+		}
+		report.Skipped += h.skipped
+		report.Hosts = append(report.Hosts, &HostReport{
+			Host:        origin,
+			Skipped:     h.skipped,
+			Paths:       append([]string(nil), h.skippedPaths...),
+			SecurityTxt: h.securityTxt,
+		})
+	}
+	sort.Slice(report.Hosts, func(i, j int) bool {
+		if report.Hosts[i].Skipped != report.Hosts[j].Skipped {
+			return report.Hosts[i].Skipped > report.Hosts[j].Skipped
+		}
+		return report.Hosts[i].Host < report.Hosts[j].Host
+	})
+	return report
+}
+
+// Rules are the allow and disallow rules of robots.txt applying to a user-agent
+type Rules struct {
+	rules []rule
+}
+
+type rule struct {
+	allow   bool
+	pattern string
+}
+
+// disallowAll are the rules used for unreachable robots.txt files
+var disallowAll = &Rules{rules: []rule{{pattern: "/"}}}
+
+// Parse parses robots.txt returning the rules of the group matching
+// userAgent, or of the * group if no group matches it
+func Parse(reader io.Reader, userAgent string) *Rules {
+	userAgent = strings.ToLower(userAgent)
+	var matched, wildcard []rule
+	var hasMatched bool
+	var groupAgents []string
+	var groupRules []rule
+	inRules := false
+
+	flush := func() {
+		for _, agent := range groupAgents {
+			switch {
+			case agent == "*":
+				wildcard = append(wildcard, groupRules...)
+			case agent == userAgent:
+				hasMatched = true
+				matched = append(matched, groupRules...)
+			}
+		}
+		groupAgents, groupRules = nil, nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// a user-agent line after rules starts a new group
+			if inRules {
+				flush()
+				inRules = false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			// an empty disallow allows everything
+			if value == "" {
+				continue
+			}
+			groupRules = append(groupRules, rule{allow: key == "allow", pattern: value})
+		}
+	}
+	flush()
+
+	if hasMatched {
+		return &Rules{rules: matched}
+	}
+	return &Rules{rules: wildcard}
+}
+
+// Allowed returns true if the path (with query) can be requested, the longest
+// matching rule applies and allow rules win over disallow rules of the same length
+func (r *Rules) Allowed(path string) bool {
+	allowed := true
+	longest := -1
+	for _, rule := range r.rules {
+		if !matchPattern(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			longest = len(rule.pattern)
+			allowed = rule.allow
+		}
+	}
+	return allowed
+}
+
+// matchPattern matches a path against a robots.txt pattern supporting
+// the * wildcard and the $ end of path anchor
+func matchPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	position := len(parts[0])
+	for i, part := range parts[1:] {
+		// the last part of an anchored pattern has to match the end of the path
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(path[position:], part)
+		}
+		index := strings.Index(path[position:], part)
+		if index == -1 {
+			return false
+		}
+		position += index + len(part)
+	}
+	return !anchored || position == len(path)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package robots
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	rules := Parse(strings.NewReader(`# comment
+User-agent: *
+Disallow: /admin
+Allow: /admin/public
+Disallow: /*.php$
+
+User-agent: googlebot
+Disallow: /
+`), UserAgent)
+	require.False(t, rules.Allowed("/admin/login"))
+	require.True(t, rules.Allowed("/admin/public/index.html"), "could not apply longest allow rule")
+	require.False(t, rules.Allowed("/index.php"))
+	require.True(t, rules.Allowed("/index.php?id=1"), "could not apply end anchor")
+	require.True(t, rules.Allowed("/"))
+
+	rules = Parse(strings.NewReader("User-agent: *\nDisallow: /\n\nUser-agent: Nuclei\nDisallow: /private\n"), UserAgent)
+	require.True(t, rules.Allowed("/"), "could not prefer the nuclei group")
+	require.False(t, rules.Allowed("/private/keys"))
+
+	rules = Parse(strings.NewReader("User-agent: *\nDisallow:\n"), UserAgent)
+	require.True(t, rules.Allowed("/anything"), "could not allow with empty disallow")
+}
+
+func TestPolicy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /admin\n")
+		case "/.well-known/security.txt":
+			fmt.Fprint(w, "Contact: mailto:security@example.com\nPolicy: https://example.com/policy\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	policy := New(retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle))
+	allowed, _ := url.Parse(ts.URL + "/search?q=1")
+	require.True(t, policy.Allowed(allowed))
+	for _, path := range []string{"/admin/users", "/admin/users", "/admin/settings"} {
+		disallowed, _ := url.Parse(ts.URL + path)
+		require.False(t, policy.Allowed(disallowed))
+	}
+
+	report := policy.Report()
+	require.Equal(t, 3, report.Skipped)
+	require.Len(t, report.Hosts, 1)
+	require.Equal(t, []string{"/admin/users", "/admin/settings"}, report.Hosts[0].Paths)
+	require.Equal(t, []string{"mailto:security@example.com"}, report.Hosts[0].SecurityTxt.Contact)
+}
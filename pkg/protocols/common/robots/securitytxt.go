@@ -0,0 +1,46 @@
+package robots
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// SecurityTxt contains the fields of a security.txt file (RFC 9116)
+// relevant to decide whether and how a host may be tested
+type SecurityTxt struct {
+	Contact []string
+	Policy  []string
+	Expires string
+}
+
+// IsEmpty returns true if no field was found in the file
+func (s *SecurityTxt) IsEmpty() bool {
+	return len(s.Contact) == 0 && len(s.Policy) == 0 && s.Expires == ""
+}
+
+// ParseSecurityTxt parses a security.txt file
+func ParseSecurityTxt(reader io.Reader) *SecurityTxt {
+	securityTxt := &SecurityTxt{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "contact":
+			securityTxt.Contact = append(securityTxt.Contact, value)
+		case "policy":
+			securityTxt.Policy = append(securityTxt.Policy, value)
+		case "expires":
+			securityTxt.Expires = value
+		}
+	}
+	return securityTxt
+}
@@ -0,0 +1,80 @@
+// Package secrets resolves the env://VAR and file://path references of the
+// credentials, so the tokens and passwords are not stored in plaintext in the
+// command lines and the files used by the scans.
+package secrets
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// referenceRegex matches the env://VAR and file://path references, the file
+// paths end at the first whitespace, quote or separator of a header or body
+var referenceRegex = regexp.MustCompile(`env://[A-Za-z_][A-Za-z0-9_]*|file://[^\s"'&;,]+`)
+
+// Resolve replaces the references of the value with the environment variable
+// or the file content. The references can be the whole value or embedded in
+// it, like Authorization: Bearer env://API_TOKEN.
+func Resolve(value string) (string, error) {
+	if !strings.Contains(value, "://") {
+		return value, nil
+	}
+	var err error
+	resolved := referenceRegex.ReplaceAllStringFunc(value, func(reference string) string {
+		if err != nil {
+			return reference
+		}
+		var secret string
+		secret, err = resolveReference(reference)
+		return secret
+	})
+	if err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+// ResolveAll resolves the references of the values in place
+func ResolveAll(values []string) error {
+	for i, value := range values {
+		resolved, err := Resolve(value)
+		if err != nil {
+			return err
+		}
+		values[i] = resolved
+	}
+	return nil
+}
+
+// ResolveMap resolves the references of the map values in place
+func ResolveMap(values map[string]string) error {
+	for name, value := range values {
+		resolved, err := Resolve(value)
+		if err != nil {
+			return errors.Wrapf(err, "could not resolve %s", name)
+		}
+		values[name] = resolved
+	}
+	return nil
+}
+
+// resolveReference returns the environment variable of an env://VAR reference
+// or the content of the file of a file://path reference without the trailing newline
+func resolveReference(reference string) (string, error) {
+	if name, ok := strings.CutPrefix(reference, "env://"); ok {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	}
+	path := strings.TrimPrefix(reference, "file://")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not read secret file %s", path)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,41 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	password := filepath.Join(dir, "password")
+	require.Nil(t, os.WriteFile(password, []byte("s3cr3t\n"), 0600))
+	t.Setenv("SECRETS_API_TOKEN", "t0k3n")
+
+	tests := map[string]string{
+		"plain value":             "plain value",
+		"env://SECRETS_API_TOKEN": "t0k3n",
+		"Authorization: Bearer env://SECRETS_API_TOKEN":                             "Authorization: Bearer t0k3n",
+		"file://" + password:                                                        "s3cr3t",
+		`{"user":"admin","password":"file://` + password + `"}`:                     `{"user":"admin","password":"s3cr3t"}`,
+		"user=admin&password=file://" + password + "&token=env://SECRETS_API_TOKEN": "user=admin&password=s3cr3t&token=t0k3n",
+		"https://example.com/login":                                                 "https://example.com/login",
+	}
+	for value, expected := range tests {
+		resolved, err := Resolve(value)
+		require.Nil(t, err, "could not resolve %s", value)
+		require.Equal(t, expected, resolved, "could not resolve %s", value)
+	}
+
+	_, err := Resolve("Bearer env://SECRETS_MISSING_VARIABLE")
+	require.EqualError(t, err, "environment variable SECRETS_MISSING_VARIABLE is not set")
+	_, err = Resolve("file://" + filepath.Join(dir, "missing"))
+	require.ErrorContains(t, err, "could not read secret file")
+
+	headers := map[string]string{"X-Api-Key": "env://SECRETS_API_TOKEN"}
+	require.Nil(t, ResolveMap(headers))
+	require.Equal(t, "t0k3n", headers["X-Api-Key"])
+	require.EqualError(t, ResolveMap(map[string]string{"X-Api-Key": "env://SECRETS_MISSING_VARIABLE"}), "could not resolve X-Api-Key: environment variable SECRETS_MISSING_VARIABLE is not set")
+}
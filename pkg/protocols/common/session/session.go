@@ -0,0 +1,416 @@
+// Package session logs in to the targets of authenticated scans and injects
+// the session tokens in their http requests
+package session
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/publicsuffix"
+	"gopkg.in/yaml.v2"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/extractors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/secrets"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// maxBodySize is the maximum size of a login response body read
+const maxBodySize = 4 * 1024 * 1024
+
+// Config is the session file of an authenticated scan.
+//
+// The login requests run once per host before its first request, the values
+// extracted from their responses are available to the next login requests
+// and to the injected headers. The paths, bodies and header values can contain
+// env://VAR and file://path references resolved when the file is loaded, so the
+// credentials are not stored in the session file.
+type Config struct {
+	// Login is the list of requests logging in to a host
+	Login []*Request `yaml:"login"`
+	// Inject contains the session tokens added to the requests of the templates
+	Inject Inject `yaml:"inject"`
+	// Refresh contains the responses re-running the login of a host
+	Refresh Refresh `yaml:"refresh"`
+}
+
+// Request is a request of the login flow
+type Request struct {
+	// Method is the method of the request, GET if empty
+	Method string `yaml:"method"`
+	// Path is the url of the request, like {{BaseURL}}/login
+	Path string `yaml:"path"`
+	// Headers are the headers of the request
+	Headers map[string]string `yaml:"headers"`
+	// Body is the body of the request
+	Body string `yaml:"body"`
+	// Extractors extract the csrf tokens, jwts and other values of the session
+	Extractors []*extractors.Extractor `yaml:"extractors"`
+}
+
+// Inject contains the session tokens added to the requests
+type Inject struct {
+	// Headers are the headers set on the requests, like Authorization: Bearer {{token}}
+	Headers map[string]string `yaml:"headers"`
+	// NoCookies disables sending the cookies set during the login
+	NoCookies bool `yaml:"no-cookies"`
+}
+
+// Refresh contains the responses of an expired session
+type Refresh struct {
+	// Status are the status codes of an expired session, 401 if empty
+	Status []int `yaml:"status"`
+	// Location are the regexes of the login pages the expired sessions are redirected to
+	Location []string `yaml:"location"`
+}
+
+// Load reads and validates the session file
+func Load(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read session file %s", file)
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, errors.Wrapf(err, "could not parse session file %s", file)
+	}
+	if err := config.resolveSecrets(); err != nil {
+		return nil, errors.Wrapf(err, "could not resolve secrets of session file %s", file)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid session file %s", file)
+	}
+	return config, nil
+}
+
+// resolveSecrets resolves the env://VAR and file://path references of the
+// login requests and of the injected headers
+func (c *Config) resolveSecrets() error {
+	for i, request := range c.Login {
+		if request == nil {
+			continue
+		}
+		var err error
+		if request.Path, err = secrets.Resolve(request.Path); err != nil {
+			return errors.Wrapf(err, "could not resolve path of login request %d", i+1)
+		}
+		if request.Body, err = secrets.Resolve(request.Body); err != nil {
+			return errors.Wrapf(err, "could not resolve body of login request %d", i+1)
+		}
+		if err := secrets.ResolveMap(request.Headers); err != nil {
+			return errors.Wrapf(err, "could not resolve headers of login request %d", i+1)
+		}
+	}
+	if err := secrets.ResolveMap(c.Inject.Headers); err != nil {
+		return errors.Wrap(err, "could not resolve injected headers")
+	}
+	return nil
+}
+
+// Validate validates the login requests and compiles their extractors
+func (c *Config) Validate() error {
+	if len(c.Login) == 0 {
+		return errors.New("no login requests")
+	}
+	for i, request := range c.Login {
+		if request == nil || request.Path == "" {
+			return errors.Errorf("login request %d has no path", i+1)
+		}
+		for _, extractor := range request.Extractors {
+			if extractor.Name == "" {
+				return errors.Errorf("login request %d has an extractor without name", i+1)
+			}
+			if err := extractor.CompileExtractors(); err != nil {
+				return errors.Wrapf(err, "could not compile extractor %s", extractor.Name)
+			}
+		}
+	}
+	for _, location := range c.Refresh.Location {
+		if _, err := regexp.Compile(location); err != nil {
+			return errors.Wrapf(err, "invalid refresh location %q", location)
+		}
+	}
+	return nil
+}
+
+// Manager logs in to the hosts of the scan and injects their session in the requests
+type Manager struct {
+	config    *Config
+	client    *retryablehttp.Client
+	status    []int
+	locations []*regexp.Regexp
+
+	mutex sync.Mutex
+	hosts map[string]*host
+}
+
+type host struct {
+	// mutex serializes the logins of the host
+	mutex      sync.Mutex
+	generation int
+	ready      bool
+	err        error
+	headers    map[string]string
+	jar        *cookiejar.Jar
+}
+
+// New returns a session manager sending the login requests with client,
+// the client must not follow redirects to keep the cookies of the redirects
+func New(config *Config, client *retryablehttp.Client) *Manager {
+	manager := &Manager{config: config, client: client, status: config.Refresh.Status, hosts: make(map[string]*host)}
+	if len(manager.status) == 0 {
+		manager.status = []int{http.StatusUnauthorized}
+	}
+	for _, location := range config.Refresh.Location {
+		manager.locations = append(manager.locations, regexp.MustCompile(location))
+	}
+	return manager
+}
+
+// Inject logs in to the host of the request if needed and adds the session to the request,
+// the returned generation of the session is given to Refresh when the session expired
+func (m *Manager) Inject(req *http.Request) (int, error) {
+	h := m.host(req)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	// the failed logins are not retried to not lock the accounts
+	if !h.ready {
+		h.err = m.login(h, req)
+		h.ready = true
+	}
+	if h.err != nil {
+		return h.generation, h.err
+	}
+	m.apply(h, req)
+	return h.generation, nil
+}
+
+// Expired returns true if the response shows an expired session
+func (m *Manager) Expired(resp *http.Response) bool {
+	for _, status := range m.status {
+		if resp.StatusCode == status {
+			return true
+		}
+	}
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 || len(m.locations) == 0 {
+		return false
+	}
+	location := resp.Header.Get("Location")
+	for _, regex := range m.locations {
+		if regex.MatchString(location) {
+			return true
+		}
+	}
+	return false
+}
+
+// Refresh logs in again to the host of the request and updates its session,
+// the login only runs once when the requests of a generation expire concurrently
+func (m *Manager) Refresh(req *http.Request, generation int) error {
+	h := m.host(req)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.generation == generation {
+		h.err = m.login(h, req)
+		h.generation++
+	}
+	if h.err != nil {
+		return h.err
+	}
+	m.apply(h, req)
+	return nil
+}
+
+// host returns the session state of the host of the request
+func (m *Manager) host(req *http.Request) *host {
+	origin := req.URL.Scheme + "://" + req.URL.Host
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	h, ok := m.hosts[origin]
+	if !ok {
+		h = &host{}
+		m.hosts[origin] = h
+	}
+	return h
+}
+
+// apply sets the session headers and cookies on the request, replacing the existing ones
+func (m *Manager) apply(h *host, req *http.Request) {
+	for name, value := range h.headers {
+		req.Header.Set(name, value)
+	}
+	if m.config.Inject.NoCookies {
+		return
+	}
+	cookies := h.jar.Cookies(req.URL)
+	if len(cookies) == 0 {
+		return
+	}
+	session := make(map[string]struct{}, len(cookies))
+	for _, cookie := range cookies {
+		session[cookie.Name] = struct{}{}
+	}
+	existing := req.Cookies()
+	req.Header.Del("Cookie")
+	for _, cookie := range existing {
+		if _, ok := session[cookie.Name]; !ok {
+			req.AddCookie(cookie)
+		}
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+}
+
+// login runs the login requests on the origin of the request and stores the session of the host
+func (m *Manager) login(h *host, target *http.Request) error {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return errors.Wrap(err, "could not create cookiejar")
+	}
+	values := utils.GenerateVariables(target.URL.Scheme+"://"+target.URL.Host, true, nil)
+	for i, request := range m.config.Login {
+		data, err := m.send(request, values, jar)
+		if err != nil {
+			return errors.Wrapf(err, "could not send login request %d", i+1)
+		}
+		for _, extractor := range request.Extractors {
+			extracted := extract(extractor, data)
+			if len(extracted) == 0 {
+				return errors.Errorf("could not extract %s from login request %d", extractor.Name, i+1)
+			}
+			values[extractor.Name] = extracted[0]
+		}
+	}
+
+	headers := make(map[string]string, len(m.config.Inject.Headers))
+	for name, value := range m.config.Inject.Headers {
+		evaluated, err := expressions.Evaluate(value, values)
+		if err != nil {
+			return errors.Wrapf(err, "could not evaluate header %s", name)
+		}
+		if err := expressions.ContainsUnresolvedVariables(evaluated); err != nil {
+			return errors.Wrapf(err, "could not evaluate header %s", name)
+		}
+		headers[name] = evaluated
+	}
+	h.headers = headers
+	h.jar = jar
+	return nil
+}
+
+// send sends a login request and returns the dsl map of its response
+func (m *Manager) send(request *Request, values map[string]interface{}, jar *cookiejar.Jar) (map[string]interface{}, error) {
+	evaluate := func(value string) (string, error) {
+		evaluated, err := expressions.Evaluate(value, values)
+		if err != nil {
+			return "", err
+		}
+		return evaluated, expressions.ContainsUnresolvedVariables(evaluated)
+	}
+	target, err := evaluate(request.Path)
+	if err != nil {
+		return nil, err
+	}
+	body, err := evaluate(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := retryablehttp.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range request.Headers {
+		evaluated, err := evaluate(value)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(name, evaluated)
+	}
+	for _, cookie := range jar.Cookies(req.Request.URL) {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	jar.SetCookies(req.Request.URL, resp.Cookies())
+	return responseToDSLMap(resp, string(data)), nil
+}
+
+// responseToDSLMap returns the parts of a login response like the http protocol
+func responseToDSLMap(resp *http.Response, body string) map[string]interface{} {
+	data := make(map[string]interface{}, 4+len(resp.Header)+len(resp.Cookies()))
+	for _, cookie := range resp.Cookies() {
+		data[strings.ToLower(cookie.Name)] = cookie.Value
+	}
+	headers := &strings.Builder{}
+	for k, v := range resp.Header {
+		headers.WriteString(k + ": " + strings.Join(v, " ") + "\r\n")
+		k = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(k), "-", "_"))
+		data[k] = strings.Join(v, " ")
+	}
+	data["status_code"] = resp.StatusCode
+	data["body"] = body
+	data["all_headers"] = headers.String()
+	data["header"] = headers.String()
+	return data
+}
+
+// extract returns the values extracted from the part of the response
+func extract(extractor *extractors.Extractor, data map[string]interface{}) []string {
+	part := extractor.Part
+	if part == "" {
+		part = "body"
+	}
+	item, _ := data[part].(string)
+	if part == "all" {
+		item = data["body"].(string) + data["all_headers"].(string)
+	}
+
+	var results map[string]struct{}
+	switch extractor.GetType() {
+	case extractors.RegexExtractor:
+		results = extractor.ExtractRegex(item)
+	case extractors.KValExtractor:
+		results = extractor.ExtractKval(data)
+	case extractors.XPathExtractor:
+		results = extractor.ExtractXPath(item)
+	case extractors.JSONExtractor:
+		results = extractor.ExtractJSON(item)
+	case extractors.DSLExtractor:
+		results = extractor.ExtractDSL(data)
+	}
+	values := make([]string, 0, len(results))
+	for value := range results {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,184 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+const sessionFile = `login:
+  - path: "{{BaseURL}}/login"
+    extractors:
+      - type: regex
+        name: csrf
+        group: 1
+        regex:
+          - 'name="csrf" value="([a-z0-9]+)"'
+  - method: POST
+    path: "{{BaseURL}}/login"
+    headers:
+      Content-Type: application/x-www-form-urlencoded
+    body: "username=admin&csrf={{csrf}}"
+    extractors:
+      - type: json
+        name: token
+        json:
+          - ".token"
+inject:
+  headers:
+    Authorization: "Bearer {{token}}"
+refresh:
+  location:
+    - "/login"
+`
+
+// newServer returns a server expiring the session tokens on /logout
+func newServer(logins *atomic.Int32) *httptest.Server {
+	var mutex sync.Mutex
+	var token string
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/login":
+			if r.Method == http.MethodGet {
+				http.SetCookie(w, &http.Cookie{Name: "sid", Value: "s1", Path: "/"})
+				fmt.Fprint(w, `<input name="csrf" value="c1">`)
+				return
+			}
+			if cookie, err := r.Cookie("sid"); err != nil || cookie.Value != "s1" || r.PostFormValue("csrf") != "c1" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			token = fmt.Sprintf("t%d", logins.Add(1))
+			fmt.Fprintf(w, `{"token":"%s"}`, token)
+		case "/logout":
+			token = ""
+		default:
+			if cookie, err := r.Cookie("sid"); err != nil || cookie.Value != "s1" || r.Header.Get("Authorization") != "Bearer "+token {
+				http.Redirect(w, r, "/login", http.StatusFound)
+				return
+			}
+			fmt.Fprint(w, "welcome "+r.Header.Get("Authorization"))
+		}
+	}))
+}
+
+func newClient() *retryablehttp.Client {
+	return retryablehttp.NewWithHTTPClient(&http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, retryablehttp.DefaultOptionsSingle)
+}
+
+func TestManager(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "session.yaml")
+	require.Nil(t, os.WriteFile(file, []byte(sessionFile), 0644))
+	config, err := Load(file)
+	require.Nil(t, err, "could not load session file")
+
+	var logins atomic.Int32
+	ts := newServer(&logins)
+	defer ts.Close()
+
+	manager := New(config, newClient())
+	send := func(path string) (*http.Request, *http.Response, int) {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		require.Nil(t, err)
+		req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+		generation, err := manager.Inject(req)
+		require.Nil(t, err, "could not inject session")
+		resp, err := newClient().HTTPClient.Do(req)
+		require.Nil(t, err)
+		resp.Body.Close()
+		return req, resp, generation
+	}
+
+	req, resp, generation := send("/admin")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "Bearer t1", req.Header.Get("Authorization"))
+	require.Len(t, req.Cookies(), 2, "could not keep the cookies of the request")
+	require.False(t, manager.Expired(resp))
+
+	send("/logout")
+	req, resp, generation = send("/admin")
+	require.True(t, manager.Expired(resp), "could not detect redirect to login")
+	require.Equal(t, int32(1), logins.Load(), "login should run once per host")
+
+	require.Nil(t, manager.Refresh(req, generation), "could not refresh session")
+	require.Nil(t, manager.Refresh(req, generation), "could not refresh session of same generation")
+	require.Equal(t, int32(2), logins.Load(), "refresh should run once per generation")
+	require.Equal(t, "Bearer t2", req.Header.Get("Authorization"))
+
+	resp, err = newClient().HTTPClient.Do(req)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestManagerLoginError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "maintenance")
+	}))
+	defer ts.Close()
+
+	config := &Config{Login: []*Request{{Path: "{{BaseURL}}/login"}}, Inject: Inject{Headers: map[string]string{"Authorization": "Bearer {{token}}"}}}
+	require.Nil(t, config.Validate())
+	manager := New(config, newClient())
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.Nil(t, err)
+	_, err = manager.Inject(req)
+	require.ErrorContains(t, err, "could not evaluate header Authorization")
+	_, err = manager.Inject(req)
+	require.NotNil(t, err, "failed login should not be retried")
+
+	expired := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}}
+	require.True(t, manager.Expired(expired), "could not use default refresh status")
+}
+
+func TestValidate(t *testing.T) {
+	require.EqualError(t, (&Config{}).Validate(), "no login requests")
+	require.EqualError(t, (&Config{Login: []*Request{{Method: http.MethodPost}}}).Validate(), "login request 1 has no path")
+	require.EqualError(t, (&Config{Login: []*Request{{Path: "/"}}, Refresh: Refresh{Location: []string{"("}}}).Validate(), "invalid refresh location \"(\": error parsing regexp: missing closing ): `(`")
+}
+
+func TestLoadSecrets(t *testing.T) {
+	dir := t.TempDir()
+	password := filepath.Join(dir, "password")
+	require.Nil(t, os.WriteFile(password, []byte("s3cr3t\n"), 0600))
+	t.Setenv("SESSION_API_KEY", "k1")
+
+	data := fmt.Sprintf(`login:
+  - method: POST
+    path: "{{BaseURL}}/login?key=env://SESSION_API_KEY"
+    headers:
+      X-Api-Key: env://SESSION_API_KEY
+    body: '{"user":"admin","password":"file://%s"}'
+inject:
+  headers:
+    Authorization: Bearer env://SESSION_API_KEY
+`, password)
+	file := filepath.Join(dir, "session.yaml")
+	require.Nil(t, os.WriteFile(file, []byte(data), 0644))
+	config, err := Load(file)
+	require.Nil(t, err, "could not load session file")
+	require.Equal(t, "{{BaseURL}}/login?key=k1", config.Login[0].Path)
+	require.Equal(t, `{"user":"admin","password":"s3cr3t"}`, config.Login[0].Body)
+	require.Equal(t, "k1", config.Login[0].Headers["X-Api-Key"])
+	require.Equal(t, "Bearer k1", config.Inject.Headers["Authorization"])
+
+	require.Nil(t, os.WriteFile(file, []byte("login:\n  - path: env://SESSION_MISSING_VARIABLE\n"), 0644))
+	_, err = Load(file)
+	require.ErrorContains(t, err, "environment variable SESSION_MISSING_VARIABLE is not set")
+}
@@ -0,0 +1,249 @@
+// Package tlsconfig implements custom tls client parameters for protocols
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// Config contains custom tls client parameters
+type Config struct {
+	// description: |
+	//   Minimum tls version - automatic if not specified.
+	// values:
+	//   - "tls10"
+	//   - "tls11"
+	//   - "tls12"
+	//   - "tls13"
+	MinVersion string `yaml:"min_version,omitempty" json:"min_version,omitempty" jsonschema:"title=Min. TLS version,description=Minimum tls version - automatic if not specified.,enum=tls10,enum=tls11,enum=tls12,enum=tls13"`
+	// description: |
+	//   Max tls version - automatic if not specified.
+	// values:
+	//   - "tls10"
+	//   - "tls11"
+	//   - "tls12"
+	//   - "tls13"
+	MaxVersion string `yaml:"max_version,omitempty" json:"max_version,omitempty" jsonschema:"title=Max. TLS version,description=Max tls version - automatic if not specified.,enum=tls10,enum=tls11,enum=tls12,enum=tls13"`
+	// description: |
+	//   Cipher suites to offer for tls 1.0-1.2 (ex: TLS_RSA_WITH_AES_128_CBC_SHA) - automatic if not specified.
+	CipherSuites []string `yaml:"cipher_suites,omitempty" json:"cipher_suites,omitempty" jsonschema:"title=cipher suites,description=Cipher suites to offer for tls 1.0-1.2"`
+	// description: |
+	//   Elliptic curves to offer in preference order - automatic if not specified.
+	// values:
+	//   - "x25519"
+	//   - "p256"
+	//   - "p384"
+	//   - "p521"
+	Curves []string `yaml:"curves,omitempty" json:"curves,omitempty" jsonschema:"title=curves,description=Elliptic curves to offer in preference order"`
+	// description: |
+	//   ALPN protocols to offer (ex: h2, http/1.1).
+	ALPN []string `yaml:"alpn,omitempty" json:"alpn,omitempty" jsonschema:"title=alpn protocols,description=ALPN protocols to offer"`
+	// description: |
+	//   Renegotiation support of the client - once if not specified.
+	// values:
+	//   - "never"
+	//   - "once"
+	//   - "freely"
+	Renegotiation string `yaml:"renegotiation,omitempty" json:"renegotiation,omitempty" jsonschema:"title=renegotiation,description=Renegotiation support of the client,enum=never,enum=once,enum=freely"`
+	// description: |
+	//   Client certificate presented to the servers requiring mutual tls.
+	//
+	//   The certificate can be an inline PEM block, a file path or a variable (ex: {{client_cert}}).
+	// examples:
+	//   - value: "\"certs/admin.crt\""
+	ClientCert string `yaml:"client_cert,omitempty" json:"client_cert,omitempty" jsonschema:"title=client certificate,description=Client certificate (PEM block or file path) presented to the servers"`
+	// description: |
+	//   Private key of the client certificate, the key is read from the certificate if not specified.
+	//
+	//   The key can be an inline PEM block, a file path or a variable (ex: {{client_key}}).
+	ClientKey string `yaml:"client_key,omitempty" json:"client_key,omitempty" jsonschema:"title=client key,description=Private key (PEM block or file path) of the client certificate"`
+}
+
+var versions = map[string]uint16{
+	"tls10": tls.VersionTLS10,
+	"tls11": tls.VersionTLS11,
+	"tls12": tls.VersionTLS12,
+	"tls13": tls.VersionTLS13,
+}
+
+var curves = map[string]tls.CurveID{
+	"x25519": tls.X25519,
+	"p256":   tls.CurveP256,
+	"p384":   tls.CurveP384,
+	"p521":   tls.CurveP521,
+}
+
+var renegotiations = map[string]tls.RenegotiationSupport{
+	"never":  tls.RenegotiateNever,
+	"once":   tls.RenegotiateOnceAsClient,
+	"freely": tls.RenegotiateFreelyAsClient,
+}
+
+// cipherSuites returns the ids of cipher suites supported by crypto/tls including insecure ones
+func cipherSuites() map[string]uint16 {
+	suites := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		suites[suite.Name] = suite.ID
+	}
+	return suites
+}
+
+// IsEmpty returns true if no custom parameter is set
+func (c *Config) IsEmpty() bool {
+	return c == nil || (c.MinVersion == "" && c.MaxVersion == "" && len(c.CipherSuites) == 0 && len(c.Curves) == 0 && len(c.ALPN) == 0 && c.Renegotiation == "" && c.ClientCert == "" && c.ClientKey == "")
+}
+
+// Merge returns the config with unset parameters taken from fallback
+func (c *Config) Merge(fallback *Config) *Config {
+	if c.IsEmpty() {
+		return fallback
+	}
+	if fallback.IsEmpty() {
+		return c
+	}
+	merged := *c
+	if merged.MinVersion == "" {
+		merged.MinVersion = fallback.MinVersion
+	}
+	if merged.MaxVersion == "" {
+		merged.MaxVersion = fallback.MaxVersion
+	}
+	if len(merged.CipherSuites) == 0 {
+		merged.CipherSuites = fallback.CipherSuites
+	}
+	if len(merged.Curves) == 0 {
+		merged.Curves = fallback.Curves
+	}
+	if len(merged.ALPN) == 0 {
+		merged.ALPN = fallback.ALPN
+	}
+	if merged.Renegotiation == "" {
+		merged.Renegotiation = fallback.Renegotiation
+	}
+	if merged.ClientCert == "" {
+		merged.ClientCert, merged.ClientKey = fallback.ClientCert, fallback.ClientKey
+	}
+	return &merged
+}
+
+// Hash returns a key identifying the parameters of the config
+func (c *Config) Hash() string {
+	if c.IsEmpty() {
+		return ""
+	}
+	return strings.Join([]string{c.MinVersion, c.MaxVersion, strings.Join(c.CipherSuites, ","), strings.Join(c.Curves, ","), strings.Join(c.ALPN, ","), c.Renegotiation, c.ClientCert, c.ClientKey}, "|")
+}
+
+// Validate validates the parameters of the config
+func (c *Config) Validate() error {
+	config := &tls.Config{}
+	if !c.IsEmpty() {
+		if c.ClientKey != "" && c.ClientCert == "" {
+			return errorutil.NewWithTag("tlsconfig", "client key requires a client certificate")
+		}
+		// the client certificate is parsed once resolved by ResolveClientCertificate
+		params := *c
+		params.ClientCert, params.ClientKey = "", ""
+		if err := params.Apply(config); err != nil {
+			return err
+		}
+	}
+	return fips.CheckTLSConfig(config)
+}
+
+// ResolveClientCertificate replaces the client certificate and key of the config
+// with the PEM blocks returned by resolve.
+func (c *Config) ResolveClientCertificate(resolve func(value string) (string, error)) error {
+	if c.IsEmpty() || c.ClientCert == "" {
+		return nil
+	}
+	cert, err := resolve(c.ClientCert)
+	if err != nil {
+		return errorutil.NewWithTag("tlsconfig", "could not resolve client certificate").Wrap(err)
+	}
+	key, err := resolve(c.ClientKey)
+	if err != nil {
+		return errorutil.NewWithTag("tlsconfig", "could not resolve client key").Wrap(err)
+	}
+	c.ClientCert, c.ClientKey = cert, key
+	return nil
+}
+
+// ClientCertificate parses the PEM encoded client certificate and key,
+// the key is read from the certificate PEM blocks if empty.
+func ClientCertificate(cert, key string) (tls.Certificate, error) {
+	if key == "" {
+		key = cert
+	}
+	certificate, err := tls.X509KeyPair([]byte(cert), []byte(key))
+	if err != nil {
+		return tls.Certificate{}, errorutil.NewWithTag("tlsconfig", "invalid client certificate").Wrap(err)
+	}
+	return certificate, nil
+}
+
+// Apply sets the parameters of the config on a tls client config
+func (c *Config) Apply(config *tls.Config) error {
+	if c.IsEmpty() {
+		return nil
+	}
+	if c.MinVersion != "" {
+		version, ok := versions[strings.ToLower(c.MinVersion)]
+		if !ok {
+			return errorutil.NewWithTag("tlsconfig", "invalid min tls version %s", c.MinVersion)
+		}
+		config.MinVersion = version
+	}
+	if c.MaxVersion != "" {
+		version, ok := versions[strings.ToLower(c.MaxVersion)]
+		if !ok {
+			return errorutil.NewWithTag("tlsconfig", "invalid max tls version %s", c.MaxVersion)
+		}
+		config.MaxVersion = version
+	}
+	if config.MinVersion != 0 && config.MaxVersion != 0 && config.MinVersion > config.MaxVersion {
+		return errorutil.NewWithTag("tlsconfig", "min tls version %s is greater than max tls version %s", c.MinVersion, c.MaxVersion)
+	}
+	if len(c.CipherSuites) > 0 {
+		supported := cipherSuites()
+		config.CipherSuites = nil
+		for _, name := range c.CipherSuites {
+			id, ok := supported[strings.ToUpper(strings.TrimSpace(name))]
+			if !ok {
+				return errorutil.NewWithTag("tlsconfig", "unsupported cipher suite %s", name)
+			}
+			config.CipherSuites = append(config.CipherSuites, id)
+		}
+	}
+	if len(c.Curves) > 0 {
+		config.CurvePreferences = nil
+		for _, name := range c.Curves {
+			curve, ok := curves[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return errorutil.NewWithTag("tlsconfig", "unsupported curve %s", name)
+			}
+			config.CurvePreferences = append(config.CurvePreferences, curve)
+		}
+	}
+	if len(c.ALPN) > 0 {
+		config.NextProtos = append([]string{}, c.ALPN...)
+	}
+	if c.Renegotiation != "" {
+		renegotiation, ok := renegotiations[strings.ToLower(c.Renegotiation)]
+		if !ok {
+			return errorutil.NewWithTag("tlsconfig", "invalid renegotiation %s, supported: never, once, freely", c.Renegotiation)
+		}
+		config.Renegotiation = renegotiation
+	}
+	if c.ClientCert != "" {
+		certificate, err := ClientCertificate(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return nil
+}
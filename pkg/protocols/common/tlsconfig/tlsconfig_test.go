@@ -0,0 +1,96 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigApply(t *testing.T) {
+	config := &Config{
+		MinVersion:    "tls10",
+		MaxVersion:    "TLS12",
+		CipherSuites:  []string{"TLS_RSA_WITH_AES_128_CBC_SHA", "tls_rsa_with_rc4_128_sha"},
+		Curves:        []string{"p256", "X25519"},
+		ALPN:          []string{"http/1.1"},
+		Renegotiation: "freely",
+	}
+	tlsConfig := &tls.Config{}
+	require.Nil(t, config.Apply(tlsConfig), "could not apply config")
+	require.Equal(t, uint16(tls.VersionTLS10), tlsConfig.MinVersion)
+	require.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MaxVersion)
+	require.Equal(t, []uint16{tls.TLS_RSA_WITH_AES_128_CBC_SHA, tls.TLS_RSA_WITH_RC4_128_SHA}, tlsConfig.CipherSuites)
+	require.Equal(t, []tls.CurveID{tls.CurveP256, tls.X25519}, tlsConfig.CurvePreferences)
+	require.Equal(t, []string{"http/1.1"}, tlsConfig.NextProtos)
+	require.Equal(t, tls.RenegotiateFreelyAsClient, tlsConfig.Renegotiation)
+
+	var empty *Config
+	require.True(t, empty.IsEmpty())
+	require.Nil(t, empty.Apply(tlsConfig), "could not apply empty config")
+}
+
+func TestConfigValidate(t *testing.T) {
+	invalid := []*Config{
+		{MinVersion: "sslv3"},
+		{MinVersion: "tls13", MaxVersion: "tls11"},
+		{CipherSuites: []string{"TLS_UNKNOWN"}},
+		{Curves: []string{"p224"}},
+		{Renegotiation: "always"},
+	}
+	for _, config := range invalid {
+		require.NotNil(t, config.Validate(), "could not get error for %+v", config)
+	}
+	require.Nil(t, (&Config{MaxVersion: "tls11"}).Validate())
+}
+
+func TestConfigMerge(t *testing.T) {
+	global := &Config{MinVersion: "tls10", Curves: []string{"p384"}}
+	template := &Config{MinVersion: "tls12", ALPN: []string{"h2"}}
+
+	merged := template.Merge(global)
+	require.Equal(t, &Config{MinVersion: "tls12", Curves: []string{"p384"}, ALPN: []string{"h2"}}, merged)
+	require.Equal(t, global, (*Config)(nil).Merge(global))
+	require.Equal(t, template, template.Merge(&Config{}))
+	require.NotEqual(t, template.Hash(), merged.Hash())
+	require.Empty(t, (*Config)(nil).Hash())
+}
+
+func TestConfigClientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "client"}}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+
+	// the values are validated once resolved
+	config := &Config{ClientCert: "client.crt", ClientKey: "client.key"}
+	require.Nil(t, config.Validate())
+	require.NotNil(t, config.Apply(&tls.Config{}))
+	files := map[string]string{"client.crt": certPEM, "client.key": keyPEM}
+	require.Nil(t, config.ResolveClientCertificate(func(value string) (string, error) {
+		return files[value], nil
+	}))
+	tlsConfig := &tls.Config{}
+	require.Nil(t, config.Apply(tlsConfig))
+	require.Len(t, tlsConfig.Certificates, 1)
+	require.Equal(t, der, tlsConfig.Certificates[0].Certificate[0])
+
+	// the key is read from the certificate if empty
+	_, err = ClientCertificate(certPEM+keyPEM, "")
+	require.Nil(t, err)
+	_, err = ClientCertificate(certPEM, "")
+	require.ErrorContains(t, err, "invalid client certificate")
+	require.ErrorContains(t, (&Config{ClientKey: keyPEM}).Validate(), "client key requires a client certificate")
+}
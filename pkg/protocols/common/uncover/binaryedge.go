@@ -0,0 +1,116 @@
+package uncover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/projectdiscovery/uncover/sources"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// binaryEdgeURL is the binaryedge host search api endpoint
+var binaryEdgeURL = "https://api.binaryedge.io/v2/query/search"
+
+// binaryEdgeAgent is an uncover agent for the binaryedge search engine
+// which is not available in the uncover library
+type binaryEdgeAgent struct {
+	ctx    context.Context
+	apiKey string
+}
+
+type binaryEdgeResponse struct {
+	Page     int `json:"page"`
+	PageSize int `json:"pagesize"`
+	Total    int `json:"total"`
+	Events   []struct {
+		Target struct {
+			IP   string `json:"ip"`
+			Port int    `json:"port"`
+		} `json:"target"`
+	} `json:"events"`
+}
+
+// newBinaryEdgeAgent returns a binaryedge agent stopping its queries once ctx is done
+func newBinaryEdgeAgent(ctx context.Context) *binaryEdgeAgent {
+	return &binaryEdgeAgent{ctx: ctx, apiKey: os.Getenv("BINARYEDGE_API_KEY")}
+}
+
+func (agent *binaryEdgeAgent) Name() string {
+	return "binaryedge"
+}
+
+// Query pages through the search results until the limit is reached or the
+// context of the agent is done
+func (agent *binaryEdgeAgent) Query(session *sources.Session, query *sources.Query) (chan sources.Result, error) {
+	if agent.apiKey == "" {
+		return nil, errorutil.New("empty binaryedge keys, set BINARYEDGE_API_KEY")
+	}
+	results := make(chan sources.Result)
+
+	send := func(result sources.Result) bool {
+		select {
+		case results <- result:
+			return true
+		case <-agent.ctx.Done():
+			return false
+		}
+	}
+	go func() {
+		defer close(results)
+
+		numberOfResults := 0
+		for page := 1; ; page++ {
+			if agent.ctx.Err() != nil {
+				return
+			}
+			response, err := agent.query(session, query.Query, page)
+			if err != nil {
+				send(sources.Result{Source: agent.Name(), Error: err})
+				return
+			}
+			for _, event := range response.Events {
+				result := sources.Result{Source: agent.Name(), IP: event.Target.IP, Port: event.Target.Port}
+				raw, _ := json.Marshal(result)
+				result.Raw = raw
+				if !send(result) {
+					return
+				}
+				numberOfResults++
+			}
+			if len(response.Events) == 0 || numberOfResults >= query.Limit || page*response.PageSize >= response.Total {
+				return
+			}
+		}
+	}()
+	return results, nil
+}
+
+func (agent *binaryEdgeAgent) query(session *sources.Session, query string, page int) (*binaryEdgeResponse, error) {
+	requestURL := fmt.Sprintf("%s?query=%s&page=%d", binaryEdgeURL, url.QueryEscape(query), page)
+	request, err := retryablehttp.NewRequestWithContext(agent.ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Key", agent.apiKey)
+	request.Header.Set("Accept", "application/json")
+
+	resp, err := session.Do(request, agent.Name())
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := &binaryEdgeResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
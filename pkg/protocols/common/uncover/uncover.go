@@ -4,33 +4,106 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+	"github.com/projectdiscovery/ratelimit"
 	"github.com/projectdiscovery/uncover"
 	"github.com/projectdiscovery/uncover/sources"
+	errorutil "github.com/projectdiscovery/utils/errors"
 	mapsutil "github.com/projectdiscovery/utils/maps"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 )
 
+// customAgents contains the agents implemented in nuclei that are not available in uncover
+var customAgents = map[string]func(ctx context.Context) sources.Agent{
+	"binaryedge": func(ctx context.Context) sources.Agent { return newBinaryEdgeAgent(ctx) },
+}
+
+// Target is a target returned by an uncover engine
+type Target struct {
+	// Value is the target in the requested output format
+	Value string
+	// Metadata contains the fields of the engine result (uncover_source, uncover_ip,
+	// uncover_port, uncover_host and uncover_url) exposed as template variables
+	Metadata map[string]string
+}
+
 // returns csv string of uncover supported agents
 func GetUncoverSupportedAgents() string {
 	u, _ := uncover.New(&uncover.Options{})
-	return strings.Join(u.AllAgents(), ",")
+	agents := append(u.AllAgents(), mapsutil.GetKeys(customAgents)...)
+	return strings.Join(agents, ",")
 }
 
-// GetTargetsFromUncover returns targets from uncover
-func GetTargetsFromUncover(ctx context.Context, outputFormat string, opts *uncover.Options) (chan string, error) {
+// ParseEngineRateLimits parses per engine ratelimits in engine=count format
+func ParseEngineRateLimits(values []string) (map[string]uint, error) {
+	rateLimits := make(map[string]uint)
+	for _, value := range values {
+		engine, count, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, errorutil.New("invalid engine ratelimit %s, expected engine=count", value)
+		}
+		parsed, err := strconv.ParseUint(strings.TrimSpace(count), 10, 32)
+		if err != nil || parsed == 0 {
+			return nil, errorutil.New("invalid ratelimit count for engine %s: %s", engine, count)
+		}
+		rateLimits[strings.TrimSpace(engine)] = uint(parsed)
+	}
+	return rateLimits, nil
+}
+
+// GetTargetsFromUncover returns targets from uncover.
+// engineRateLimits optionally overrides the ratelimit of engines in requests per opts.RateLimitUnit
+func GetTargetsFromUncover(ctx context.Context, outputFormat string, opts *uncover.Options, engineRateLimits map[string]uint) (chan Target, error) {
 	u, err := uncover.New(opts)
 	if err != nil {
 		return nil, err
 	}
-	resChan, err := u.Execute(ctx)
+	rateLimits, err := newRateLimiter(ctx, opts, engineRateLimits)
 	if err != nil {
 		return nil, err
 	}
-	outputChan := make(chan string) // buffered channel
+	u.Session.RateLimits.Stop()
+	u.Session.RateLimits = rateLimits
+
+	var channels []<-chan sources.Result
+	var agents []sources.Agent
+	for _, name := range opts.Agents {
+		if newAgent, ok := customAgents[name]; ok {
+			agents = append(agents, newAgent(ctx))
+		}
+	}
+	if len(u.Agents) > 0 {
+		ch, err := u.Execute(ctx)
+		if err != nil {
+			if len(agents) == 0 {
+				return nil, err
+			}
+			gologger.Warning().Msgf("uncover: %v", err)
+		} else {
+			channels = append(channels, ch)
+		}
+	}
+	for _, agent := range agents {
+		for _, query := range opts.Queries {
+			ch, err := agent.Query(u.Session, &sources.Query{Query: query, Limit: opts.Limit})
+			if err != nil {
+				gologger.Error().Msgf("uncover: %v", err)
+				continue
+			}
+			channels = append(channels, ch)
+		}
+	}
+	if len(channels) == 0 {
+		return nil, errorutil.New("no uncover agents available for %v", opts.Agents)
+	}
+	resChan := mergeResults(ctx, channels)
+
+	outputChan := make(chan Target) // buffered channel
 	go func() {
 		defer close(outputChan)
 		for {
@@ -46,13 +119,67 @@ func GetTargetsFromUncover(ctx context.Context, outputFormat string, opts *uncov
 					gologger.Verbose().Msgf("uncover: %v", res.Error)
 					continue
 				}
-				outputChan <- processUncoverOutput(res, outputFormat)
+				select {
+				case outputChan <- Target{Value: processUncoverOutput(res, outputFormat), Metadata: resultMetadata(res)}:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
 	return outputChan, nil
 }
 
+// newRateLimiter returns a ratelimiter for the given engines using the default ratelimits
+// of uncover unless they are overridden, engines without a ratelimit use opts.RateLimit
+func newRateLimiter(ctx context.Context, opts *uncover.Options, engineRateLimits map[string]uint) (*ratelimit.MultiLimiter, error) {
+	defaultRateLimit := &ratelimit.Options{Key: "default", MaxCount: opts.RateLimit, Duration: opts.RateLimitUnit}
+	if opts.RateLimit == 0 {
+		defaultRateLimit = &ratelimit.Options{Key: "default", IsUnlimited: true}
+	}
+	rateLimits, err := ratelimit.NewMultiLimiter(ctx, defaultRateLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, engine := range opts.Agents {
+		engineRateLimit := *defaultRateLimit
+		if count, ok := engineRateLimits[engine]; ok {
+			engineRateLimit = ratelimit.Options{MaxCount: count, Duration: opts.RateLimitUnit}
+		} else if defaultOpts, ok := sources.DefaultRateLimits[engine]; ok {
+			engineRateLimit = *defaultOpts
+		}
+		engineRateLimit.Key = engine
+		if err := rateLimits.Add(&engineRateLimit); err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not setup ratelimit of %v", engine)
+		}
+	}
+	return rateLimits, nil
+}
+
+// mergeResults merges the results of multiple agents into a single channel
+func mergeResults(ctx context.Context, channels []<-chan sources.Result) <-chan sources.Result {
+	results := make(chan sources.Result)
+	wg := &sync.WaitGroup{}
+	for _, ch := range channels {
+		wg.Add(1)
+		go func(ch <-chan sources.Result) {
+			defer wg.Done()
+			for result := range ch {
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
 // processUncoverOutput returns output string depending on uncover field
 func processUncoverOutput(result sources.Result, outputFormat string) string {
 	if (result.IP == "" || result.Port == 0) && stringsutil.ContainsAny(outputFormat, "ip", "port") {
@@ -68,8 +195,26 @@ func processUncoverOutput(result sources.Result, outputFormat string) string {
 	return replacer.Replace(outputFormat)
 }
 
+// resultMetadata returns the template variables of the fields of an uncover result
+func resultMetadata(result sources.Result) map[string]string {
+	metadata := map[string]string{"uncover_source": result.Source}
+	if result.IP != "" {
+		metadata["uncover_ip"] = result.IP
+	}
+	if result.Port != 0 {
+		metadata["uncover_port"] = strconv.Itoa(result.Port)
+	}
+	if result.Host != "" {
+		metadata["uncover_host"] = result.Host
+	}
+	if result.Url != "" {
+		metadata["uncover_url"] = result.Url
+	}
+	return metadata
+}
+
 // GetUncoverTargetsFromMetadata returns targets from uncover metadata
-func GetUncoverTargetsFromMetadata(ctx context.Context, templates []*templates.Template, outputFormat string, opts *uncover.Options) chan string {
+func GetUncoverTargetsFromMetadata(ctx context.Context, templates []*templates.Template, outputFormat string, opts *uncover.Options, engineRateLimits map[string]uint) chan Target {
 	// contains map[engine]queries
 	queriesMap := make(map[string][]string)
 	for _, template := range templates {
@@ -89,15 +234,13 @@ func GetUncoverTargetsFromMetadata(ctx context.Context, templates []*templates.T
 	}
 	keys := mapsutil.GetKeys(queriesMap)
 	gologger.Info().Msgf("Running uncover queries from template against: %s", strings.Join(keys, ","))
-	result := make(chan string, runtime.NumCPU())
+	result := make(chan Target, runtime.NumCPU())
 	go func() {
 		defer close(result)
 		// unfortunately uncover doesn't support execution of map[engine]queries
 		// if queries are given they are executed against all engines which is not what we want
 		// TODO: add support for map[engine]queries in uncover
 		// Note below implementation is intentionally sequential to avoid burning all the API keys
-		counter := 0
-
 		for eng, queries := range queriesMap {
 			// create new uncover options for each engine
 			uncoverOpts := &uncover.Options{
@@ -109,26 +252,34 @@ func GetUncoverTargetsFromMetadata(ctx context.Context, templates []*templates.T
 				RateLimit:     opts.RateLimit,
 				RateLimitUnit: opts.RateLimitUnit,
 			}
-			ch, err := GetTargetsFromUncover(ctx, outputFormat, uncoverOpts)
+			engineCtx, cancel := context.WithCancel(ctx)
+			ch, err := GetTargetsFromUncover(engineCtx, outputFormat, uncoverOpts, engineRateLimits)
 			if err != nil {
+				cancel()
 				gologger.Error().Msgf("Could not get targets using %v engine from uncover: %s", eng, err)
-				return
+				continue
 			}
+			// limit is applied per engine so that results of an engine do not
+			// prevent the queries of remaining engines from being executed
+			counter := 0
+		resultLoop:
 			for {
 				select {
 				case <-ctx.Done():
+					cancel()
 					return
 				case res, ok := <-ch:
 					if !ok {
-						return
+						break resultLoop
 					}
 					result <- res
 					counter++
 					if opts.Limit > 0 && counter >= opts.Limit {
-						return
+						break resultLoop
 					}
 				}
 			}
+			cancel()
 		}
 	}()
 	return result
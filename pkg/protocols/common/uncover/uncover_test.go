@@ -0,0 +1,94 @@
+package uncover
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/uncover/sources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEngineRateLimits(t *testing.T) {
+	rateLimits, err := ParseEngineRateLimits([]string{"shodan=120", "binaryedge = 10"})
+	require.Nil(t, err, "could not parse engine ratelimits")
+	require.Equal(t, map[string]uint{"shodan": 120, "binaryedge": 10}, rateLimits)
+
+	_, err = ParseEngineRateLimits([]string{"shodan"})
+	require.NotNil(t, err, "could not get error for missing count")
+	_, err = ParseEngineRateLimits([]string{"shodan=0"})
+	require.NotNil(t, err, "could not get error for zero count")
+}
+
+func TestBinaryEdgeAgentPagination(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "key", r.Header.Get("X-Key"))
+		page := r.URL.Query().Get("page")
+		_, _ = fmt.Fprintf(w, `{"page":%s,"pagesize":2,"total":3,"events":[{"target":{"ip":"10.0.0.%s","port":80}},{"target":{"ip":"10.0.1.%s","port":443}}]}`, page, page, page)
+	}))
+	defer ts.Close()
+
+	original := binaryEdgeURL
+	binaryEdgeURL = ts.URL
+	defer func() { binaryEdgeURL = original }()
+
+	session, err := sources.NewSession(&sources.Keys{}, 0, 5, 0, []string{"binaryedge"}, time.Second)
+	require.Nil(t, err, "could not create session")
+
+	agent := &binaryEdgeAgent{ctx: context.Background(), apiKey: "key"}
+	ch, err := agent.Query(session, &sources.Query{Query: "product:nginx", Limit: 10})
+	require.Nil(t, err, "could not query binaryedge")
+
+	got := []string{}
+	for result := range ch {
+		require.Nil(t, result.Error)
+		got = append(got, result.IpPort())
+	}
+	require.Equal(t, []string{"10.0.0.1:80", "10.0.1.1:443", "10.0.0.2:80", "10.0.1.2:443"}, got, "could not get paginated results")
+}
+
+func TestBinaryEdgeAgentCancel(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"page":1,"pagesize":1,"total":1000000,"events":[{"target":{"ip":"10.0.0.1","port":80}}]}`)
+	}))
+	defer ts.Close()
+
+	original := binaryEdgeURL
+	binaryEdgeURL = ts.URL
+	defer func() { binaryEdgeURL = original }()
+
+	session, err := sources.NewSession(&sources.Keys{}, 0, 5, 0, []string{"binaryedge"}, time.Second)
+	require.Nil(t, err, "could not create session")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	agent := &binaryEdgeAgent{ctx: ctx, apiKey: "key"}
+	ch, err := agent.Query(session, &sources.Query{Query: "product:nginx", Limit: 1000000})
+	require.Nil(t, err, "could not query binaryedge")
+	<-ch
+	cancel()
+
+	select {
+	case <-drain(ch):
+	case <-time.After(5 * time.Second):
+		t.Fatal("could not stop query on cancelled context")
+	}
+}
+
+// drain returns a channel closed once all the results are read
+func drain(ch chan sources.Result) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range ch {
+		}
+	}()
+	return done
+}
+
+func TestResultMetadata(t *testing.T) {
+	metadata := resultMetadata(sources.Result{Source: "shodan", IP: "10.0.0.1", Port: 443, Host: "example.com"})
+	require.Equal(t, map[string]string{"uncover_source": "shodan", "uncover_ip": "10.0.0.1", "uncover_port": "443", "uncover_host": "example.com"}, metadata)
+}
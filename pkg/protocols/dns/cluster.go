@@ -12,7 +12,9 @@ func (request *Request) CanCluster(other *Request) bool {
 	if request.Name != other.Name ||
 		request.class != other.class ||
 		request.Retries != other.Retries ||
-		request.question != other.question {
+		request.question != other.question ||
+		request.MaxRecords != other.MaxRecords ||
+		request.DNSSEC != other.DNSSEC {
 		return false
 	}
 	if request.Recursion != nil {
@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"net/url"
 	"strings"
 
 	"github.com/miekg/dns"
@@ -10,10 +11,12 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/replacer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/dns/dnsclientpool"
 	"github.com/projectdiscovery/retryabledns"
 	fileutil "github.com/projectdiscovery/utils/file"
+	stringsutil "github.com/projectdiscovery/utils/strings"
 )
 
 // Request contains a DNS protocol request to be made from a template
@@ -74,6 +77,10 @@ type Request struct {
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Files can also be https urls fetched once per scan, an expected sha256
+	//   checksum can be set with a #sha256=hex url fragment. The urls are
+	//   only fetched with the -allow-remote-payloads option.
 	Payloads  map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the network request,description=Payloads contains any payloads for the current request"`
 	generator *generators.PayloadGenerator
 
@@ -88,28 +95,56 @@ type Request struct {
 	// description: |
 	//   Recursion determines if resolver should recurse all records to get fresh results.
 	Recursion *bool `yaml:"recursion,omitempty" json:"recursion,omitempty" jsonschema:"title=recurse all servers,description=Recursion determines if resolver should recurse all records to get fresh results"`
-	// Resolvers to use for the dns requests
-	Resolvers []string `yaml:"resolvers,omitempty" json:"resolvers,omitempty" jsonschema:"title=Resolvers,description=Define resolvers to use within the template"`
+	// description: |
+	//   DNSSEC requests the DNSSEC records of the response and validates them.
+	//
+	//   The signatures of the answer and the chain of trust of the signer zone are validated
+	//   up to the root trust anchors, the validation is returned in the dnssec_* fields.
+	DNSSEC bool `yaml:"dnssec,omitempty" json:"dnssec,omitempty" jsonschema:"title=validate dnssec of the response,description=DNSSEC requests the DNSSEC records of the response and validates them"`
+	// description: |
+	//   MaxRecords is the maximum number of records of the AXFR and IXFR zone transfers.
+	//
+	//   The transfer is stopped once the records are received, the default is 10000.
+	// examples:
+	//   - name: Stop the zone transfers at 500 records
+	//     value: 500
+	MaxRecords int `yaml:"max-records,omitempty" json:"max-records,omitempty" jsonschema:"title=max records of zone transfers,description=MaxRecords is the maximum number of records of the AXFR and IXFR zone transfers"`
+	// description: |
+	//   Resolvers to use for the dns requests.
+	//
+	//   The resolvers are udp by default, tcp: resolvers use tcp, https:// urls are
+	//   DNS-over-HTTPS resolvers and tls:// addresses are DNS-over-TLS resolvers.
+	// examples:
+	//   - name: Query a DNS-over-HTTPS and a DNS-over-TLS resolver
+	//     value: >
+	//       []string{"https://dns.google/dns-query", "tls://1.1.1.1"}
+	Resolvers []string `yaml:"resolvers,omitempty" json:"resolvers,omitempty" jsonschema:"title=Resolvers,description=Define udp or tcp: or https:// DNS-over-HTTPS or tls:// DNS-over-TLS resolvers to use within the template"`
 }
 
 // RequestPartDefinitions contains a mapping of request part definitions and their
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":   "ID of the template executed",
-	"template-info": "Info Block of the template executed",
-	"template-path": "Path of the template executed",
-	"host":          "Host is the input to the template",
-	"matched":       "Matched is the input which was matched upon",
-	"request":       "Request contains the DNS request in text format",
-	"type":          "Type is the type of request made",
-	"rcode":         "Rcode field returned for the DNS request",
-	"question":      "Question contains the DNS question field",
-	"extra":         "Extra contains the DNS response extra field",
-	"answer":        "Answer contains the DNS response answer field",
-	"ns":            "NS contains the DNS response NS field",
-	"raw,body,all":  "Raw contains the raw DNS response (default)",
-	"trace":         "Trace contains trace data for DNS request if enabled",
+	"template-id":       "ID of the template executed",
+	"template-info":     "Info Block of the template executed",
+	"template-path":     "Path of the template executed",
+	"host":              "Host is the input to the template",
+	"matched":           "Matched is the input which was matched upon",
+	"request":           "Request contains the DNS request in text format",
+	"type":              "Type is the type of request made",
+	"rcode":             "Rcode field returned for the DNS request",
+	"question":          "Question contains the DNS question field",
+	"extra":             "Extra contains the DNS response extra field",
+	"answer":            "Answer contains the DNS response answer field",
+	"ns":                "NS contains the DNS response NS field",
+	"raw,body,all":      "Raw contains the raw DNS response (default)",
+	"trace":             "Trace contains trace data for DNS request if enabled",
+	"dnssec_status":     "Validation status of the DNSSEC response (secure, insecure or bogus) if enabled",
+	"dnssec_reason":     "Reason of the insecure or bogus DNSSEC validation status",
+	"dnssec_signer":     "Zone signing the answer of the DNSSEC response",
+	"dnssec_algorithms": "Algorithms of the signatures of the DNSSEC response",
+	"dnssec_dnskey":     "DNSKEY records of the zone signing the DNSSEC response",
+	"dnssec_ds":         "DS records of the zone signing the DNSSEC response",
 }
 
 func (request *Request) GetCompiledOperators() []*operators.Operators {
@@ -184,24 +219,45 @@ func (request *Request) getDnsClient(options *protocols.ExecutorOptions, metadat
 	dnsClientOptions := &dnsclientpool.Configuration{
 		Retries: request.Retries,
 	}
-	if len(request.Resolvers) > 0 {
-		if len(request.Resolvers) > 0 {
-			for _, resolver := range request.Resolvers {
-				if expressions.ContainsUnresolvedVariables(resolver) != nil {
-					var err error
-					resolver, err = expressions.Evaluate(resolver, metadata)
-					if err != nil {
-						return nil, errors.Wrap(err, "could not resolve resolvers expressions")
-					}
-					dnsClientOptions.Resolvers = append(dnsClientOptions.Resolvers, resolver)
-				}
+	for _, resolver := range request.Resolvers {
+		if expressions.ContainsUnresolvedVariables(resolver) != nil {
+			var err error
+			resolver, err = expressions.Evaluate(resolver, metadata)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not resolve resolvers expressions")
 			}
 		}
-		dnsClientOptions.Resolvers = request.Resolvers
+		// doh and dot resolvers can be given as https:// urls and tls:// addresses
+		resolver, err := dnsclientpool.NormalizeResolver(resolver)
+		if err != nil {
+			return nil, err
+		}
+		dnsClientOptions.Resolvers = append(dnsClientOptions.Resolvers, resolver)
+	}
+	if err := checkResolvers(dnsClientOptions.Resolvers); err != nil {
+		return nil, err
 	}
 	return dnsclientpool.Get(options.Options, dnsClientOptions)
 }
 
+// checkResolvers rejects template resolvers with private or reserved addresses
+// when disallowed since dns queries are not sent through the shared dialer
+func checkResolvers(resolvers []string) error {
+	for _, resolver := range resolvers {
+		host := resolver
+		if len(host) > 4 && host[3] == ':' && stringsutil.HasPrefixAny(host, "udp", "tcp", "dot", "doh") {
+			host = host[4:]
+		}
+		if parsed, err := url.Parse(host); err == nil && parsed.Host != "" {
+			host = parsed.Host
+		}
+		if err := protocolstate.CheckHost(host); err != nil {
+			return errors.Wrapf(err, "could not use resolver %s", resolver)
+		}
+	}
+	return nil
+}
+
 // Requests returns the total number of requests the YAML rule will perform
 func (request *Request) Requests() int {
 	if request.generator != nil {
@@ -222,12 +278,23 @@ func (request *Request) Make(host string, vars map[string]interface{}) (*dns.Msg
 	var q dns.Question
 	final := replacer.Replace(request.Name, vars)
 
+	// zone transfers are requested for the zone of the name
+	switch request.question {
+	case dns.TypeAXFR:
+		return req.SetAxfr(dns.Fqdn(final)), nil
+	case dns.TypeIXFR:
+		// the changes since the serial 0 are the complete zone
+		return req.SetIxfr(dns.Fqdn(final), 0, ".", "."), nil
+	}
+
 	q.Name = dns.Fqdn(final)
 	q.Qclass = request.class
 	q.Qtype = request.question
 	req.Question = append(req.Question, q)
 
-	req.SetEdns0(4096, false)
+	// the DO bit requests the signatures, checking is disabled to get the bogus responses
+	req.SetEdns0(4096, request.DNSSEC)
+	req.CheckingDisabled = request.DNSSEC
 
 	switch request.question {
 	case dns.TypeTXT:
@@ -267,6 +334,10 @@ func questionTypeToInt(questionType string) uint16 {
 		question = dns.TypeTLSA
 	case "ANY":
 		question = dns.TypeANY
+	case "AXFR":
+		question = dns.TypeAXFR
+	case "IXFR":
+		question = dns.TypeIXFR
 	}
 	return question
 }
@@ -37,6 +37,10 @@ const (
 	TLSA
 	// name:ANY
 	ANY
+	// name:AXFR
+	AXFR
+	// name:IXFR
+	IXFR
 	limit
 )
 
@@ -54,6 +58,8 @@ var DNSRequestTypeMapping = map[DNSRequestType]string{
 	CAA:   "CAA",
 	TLSA:  "TLSA",
 	ANY:   "ANY",
+	AXFR:  "AXFR",
+	IXFR:  "IXFR",
 }
 
 // GetSupportedDNSRequestTypes returns list of supported types
@@ -4,8 +4,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/retryabledns"
 )
@@ -35,10 +37,11 @@ func Init(options *types.Options) error {
 
 	resolvers := defaultResolvers
 	if options.ResolversFile != "" {
-		resolvers = options.InternalResolversList
+		// custom resolvers of address families not allowed by ip-version are skipped
+		resolvers = protocolstate.FilterAddresses(options.InternalResolversList)
 	}
 	var err error
-	normalClient, err = retryabledns.New(resolvers, 1)
+	normalClient, err = newClient(options, resolvers, 1)
 	if err != nil {
 		return errors.Wrap(err, "could not create dns client")
 	}
@@ -79,11 +82,11 @@ func Get(options *types.Options, configuration *Configuration) (*retryabledns.Cl
 
 	resolvers := defaultResolvers
 	if options.ResolversFile != "" {
-		resolvers = options.InternalResolversList
+		resolvers = protocolstate.FilterAddresses(options.InternalResolversList)
 	} else if len(configuration.Resolvers) > 0 {
-		resolvers = configuration.Resolvers
+		resolvers = protocolstate.FilterAddresses(configuration.Resolvers)
 	}
-	client, err := retryabledns.New(resolvers, configuration.Retries)
+	client, err := newClient(options, resolvers, configuration.Retries)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create dns client")
 	}
@@ -93,3 +96,13 @@ func Get(options *types.Options, configuration *Configuration) (*retryabledns.Cl
 	poolMutex.Unlock()
 	return client, nil
 }
+
+// newClient creates a dns client for the resolvers with the timeout of the options,
+// the DNS-over-HTTPS queries have no timeout otherwise
+func newClient(options *types.Options, resolvers []string, retries int) (*retryabledns.Client, error) {
+	return retryabledns.NewWithOptions(retryabledns.Options{
+		BaseResolvers: resolvers,
+		MaxRetries:    retries,
+		Timeout:       time.Duration(options.Timeout) * time.Second,
+	})
+}
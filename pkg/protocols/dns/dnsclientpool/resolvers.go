@@ -0,0 +1,68 @@
+package dnsclientpool
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+const (
+	// dohPrefix is the prefix of the DNS-over-HTTPS resolvers (ex: doh:https://dns.google/dns-query)
+	dohPrefix = "doh:"
+	// dotPrefix is the prefix of the DNS-over-TLS resolvers (ex: dot:1.1.1.1:853)
+	dotPrefix = "dot:"
+	// dotPort is the default port of the DNS-over-TLS resolvers
+	dotPort = "853"
+)
+
+// NormalizeResolver returns a resolver in the format of the dns client.
+//
+// The https:// urls are DNS-over-HTTPS resolvers and the tls:// addresses are
+// DNS-over-TLS resolvers, ex: https://dns.google/dns-query is doh:https://dns.google/dns-query
+// and tls://1.1.1.1 is dot:1.1.1.1:853. The doh: resolvers can be suffixed by :get,
+// :post (default) or :jsonapi to select the method of the queries.
+func NormalizeResolver(resolver string) (string, error) {
+	resolver = strings.TrimSpace(resolver)
+	lowered := strings.ToLower(resolver)
+	switch {
+	case strings.HasPrefix(lowered, "https://"):
+		resolver = dohPrefix + resolver
+	case strings.HasPrefix(lowered, "tls://"):
+		resolver = dotPrefix + resolver[len("tls://"):]
+	}
+
+	switch {
+	case strings.HasPrefix(resolver, dohPrefix):
+		value := stringsutil.TrimSuffixAny(strings.TrimPrefix(resolver, dohPrefix), ":get", ":post", ":jsonapi")
+		parsed, err := url.Parse(value)
+		if err != nil || !stringsutil.EqualFoldAny(parsed.Scheme, "http", "https") || parsed.Host == "" {
+			return "", errors.Errorf("invalid doh resolver %s: expected an http(s) url", resolver)
+		}
+	case strings.HasPrefix(resolver, dotPrefix):
+		host := strings.TrimSuffix(strings.TrimPrefix(resolver, dotPrefix), "/")
+		if host == "" {
+			return "", errors.Errorf("invalid dot resolver %s: expected a host", resolver)
+		}
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(strings.Trim(host, "[]"), dotPort)
+		}
+		resolver = dotPrefix + host
+	}
+	return resolver, nil
+}
+
+// NormalizeResolvers returns the resolvers in the format of the dns client
+func NormalizeResolvers(resolvers []string) ([]string, error) {
+	normalized := make([]string, 0, len(resolvers))
+	for _, resolver := range resolvers {
+		value, err := NormalizeResolver(resolver)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, value)
+	}
+	return normalized, nil
+}
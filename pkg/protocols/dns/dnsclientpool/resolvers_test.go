@@ -0,0 +1,30 @@
+package dnsclientpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeResolver(t *testing.T) {
+	tests := map[string]string{
+		"1.1.1.1:53":                            "1.1.1.1:53",
+		"tcp:8.8.8.8:53":                        "tcp:8.8.8.8:53",
+		"https://dns.google/dns-query":          "doh:https://dns.google/dns-query",
+		"doh:https://dns.google/resolve:get":    "doh:https://dns.google/resolve:get",
+		"tls://1.1.1.1":                         "dot:1.1.1.1:853",
+		"TLS://dns.quad9.net:8853":              "dot:dns.quad9.net:8853",
+		"dot:[2606:4700:4700::1111]":            "dot:[2606:4700:4700::1111]:853",
+		" https://cloudflare-dns.com/dns-query": "doh:https://cloudflare-dns.com/dns-query",
+	}
+	for resolver, expected := range tests {
+		normalized, err := NormalizeResolver(resolver)
+		require.Nil(t, err, "could not normalize %v", resolver)
+		require.Equal(t, expected, normalized, "could not normalize %v", resolver)
+	}
+
+	for _, resolver := range []string{"doh:dns.google", "doh:ftp://dns.google", "tls://", "dot:"} {
+		_, err := NormalizeResolver(resolver)
+		require.NotNil(t, err, "could not get error for %v", resolver)
+	}
+}
@@ -0,0 +1,283 @@
+package dns
+
+import (
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/retryabledns"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+const (
+	// DNSSECSecure is the status of the responses validated up to a trust anchor
+	DNSSECSecure = "secure"
+	// DNSSECInsecure is the status of the unsigned responses or of the zones without DS records in their parent
+	DNSSECInsecure = "insecure"
+	// DNSSECBogus is the status of the responses failing the validation
+	DNSSECBogus = "bogus"
+)
+
+// rootTrustAnchors are the DS records of the root zone KSKs (KSK-2017 and KSK-2024)
+var rootTrustAnchors = []string{
+	". 86400 IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D",
+	". 86400 IN DS 38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+}
+
+// trustAnchors are the DS records the chains of trust are validated against
+var trustAnchors = parseTrustAnchors(rootTrustAnchors)
+
+func parseTrustAnchors(records []string) []*dns.DS {
+	var anchors []*dns.DS
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			continue
+		}
+		if ds, ok := rr.(*dns.DS); ok {
+			anchors = append(anchors, ds)
+		}
+	}
+	return anchors
+}
+
+// maxChainLength is the maximum number of zones of a chain of trust
+const maxChainLength = 16
+
+// dnssecResult is the result of the DNSSEC validation of a response
+type dnssecResult struct {
+	status     string
+	reason     string
+	signer     string
+	algorithms []string
+	dnskey     []dns.RR
+	ds         []dns.RR
+}
+
+// toDSLMap returns the fields of the validation exposed to the matchers and extractors
+func (result *dnssecResult) toDSLMap() output.InternalEvent {
+	return output.InternalEvent{
+		"dnssec_status":     result.status,
+		"dnssec_reason":     result.reason,
+		"dnssec_signer":     strings.TrimSuffix(result.signer, "."),
+		"dnssec_algorithms": strings.Join(result.algorithms, ","),
+		"dnssec_dnskey":     rrToString(result.dnskey),
+		"dnssec_ds":         rrToString(result.ds),
+	}
+}
+
+// signedRRSet is an rrset with the signatures covering it
+type signedRRSet struct {
+	rrset      []dns.RR
+	signatures []*dns.RRSIG
+}
+
+// validateDNSSEC validates the signatures of the answer of resp and the chain of
+// trust of the signer zone up to a trust anchor, the DNSKEY and DS records of each
+// zone are queried with the dns client.
+func (request *Request) validateDNSSEC(dnsClient *retryabledns.Client, resp *dns.Msg) *dnssecResult {
+	result := &dnssecResult{status: DNSSECInsecure}
+	answer := signedRRSets(resp.Answer)
+	if len(answer) == 0 {
+		result.reason = "response is not signed"
+		return result
+	}
+	for _, set := range answer {
+		for _, signature := range set.signatures {
+			result.algorithms = sliceutil.Dedupe(append(result.algorithms, dns.AlgorithmToString[signature.Algorithm]))
+		}
+	}
+	result.signer = answer[0].signatures[0].SignerName
+	// the rrsets signed by other zones of the answer are not validated
+	var signedBySigner []*signedRRSet
+	for _, set := range answer {
+		for _, signature := range set.signatures {
+			if strings.EqualFold(signature.SignerName, result.signer) {
+				signedBySigner = append(signedBySigner, set)
+				break
+			}
+		}
+	}
+
+	bogus := func(err error) *dnssecResult {
+		result.status = DNSSECBogus
+		result.reason = err.Error()
+		return result
+	}
+	// the rrsets signed by the current zone, verified with its keys
+	pending := signedBySigner
+	zone := result.signer
+	for i := 0; i < maxChainLength; i++ {
+		keys, err := querySigned(dnsClient, zone, dns.TypeDNSKEY)
+		if err != nil {
+			return bogus(err)
+		}
+		if len(keys) == 0 {
+			return bogus(errors.Errorf("no signed dnskey records for %s", zone))
+		}
+		if result.dnskey == nil {
+			result.dnskey = keys[0].rrset
+		}
+		// the dnskey rrset is self-signed by the keys of the zone
+		for _, set := range append(pending, keys...) {
+			if err := verifyRRSet(set, keys[0].rrset, zone); err != nil {
+				return bogus(err)
+			}
+		}
+		if anchors := anchorsOf(zone); len(anchors) > 0 {
+			if !matchDS(anchors, keys[0].rrset) {
+				return bogus(errors.Errorf("no dnskey of %s matches the trust anchor", zone))
+			}
+			result.status = DNSSECSecure
+			return result
+		}
+		if zone == "." {
+			return bogus(errors.New("no trust anchor for the root zone"))
+		}
+
+		ds, err := querySigned(dnsClient, zone, dns.TypeDS)
+		if err != nil {
+			return bogus(err)
+		}
+		if len(ds) == 0 {
+			result.reason = "no ds records for " + zone + " in the parent zone"
+			return result
+		}
+		if result.ds == nil {
+			result.ds = ds[0].rrset
+		}
+		var dsRecords []*dns.DS
+		for _, rr := range ds[0].rrset {
+			if record, ok := rr.(*dns.DS); ok {
+				dsRecords = append(dsRecords, record)
+			}
+		}
+		if !matchDS(dsRecords, keys[0].rrset) {
+			return bogus(errors.Errorf("no dnskey of %s matches the ds records", zone))
+		}
+		if len(ds[0].signatures) == 0 {
+			return bogus(errors.Errorf("ds records of %s are not signed", zone))
+		}
+		// the ds records are signed by the parent zone
+		pending = ds
+		zone = ds[0].signatures[0].SignerName
+	}
+	return bogus(errors.New("chain of trust is too long"))
+}
+
+// querySigned returns the signed rrsets of the records of name with the type
+func querySigned(dnsClient *retryabledns.Client, name string, qtype uint16) ([]*signedRRSet, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.SetEdns0(4096, true)
+	msg.CheckingDisabled = true
+	resp, err := dnsClient.Do(msg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not query %s records of %s", dns.TypeToString[qtype], name)
+	}
+	var sets []*signedRRSet
+	for _, set := range signedRRSets(resp.Answer) {
+		if set.rrset[0].Header().Rrtype == qtype {
+			sets = append(sets, set)
+		}
+	}
+	return sets, nil
+}
+
+// signedRRSets groups the records by name and type with their signatures,
+// the unsigned rrsets are not returned
+func signedRRSets(records []dns.RR) []*signedRRSet {
+	var sets []*signedRRSet
+	find := func(name string, rrtype uint16) *signedRRSet {
+		for _, set := range sets {
+			header := set.rrset[0].Header()
+			if strings.EqualFold(header.Name, name) && header.Rrtype == rrtype {
+				return set
+			}
+		}
+		return nil
+	}
+	for _, rr := range records {
+		if _, ok := rr.(*dns.RRSIG); ok {
+			continue
+		}
+		if set := find(rr.Header().Name, rr.Header().Rrtype); set != nil {
+			set.rrset = append(set.rrset, rr)
+			continue
+		}
+		sets = append(sets, &signedRRSet{rrset: []dns.RR{rr}})
+	}
+	var signed []*signedRRSet
+	for _, set := range sets {
+		for _, rr := range records {
+			if signature, ok := rr.(*dns.RRSIG); ok && strings.EqualFold(signature.Header().Name, set.rrset[0].Header().Name) && signature.TypeCovered == set.rrset[0].Header().Rrtype {
+				set.signatures = append(set.signatures, signature)
+			}
+		}
+		if len(set.signatures) > 0 {
+			signed = append(signed, set)
+		}
+	}
+	return signed
+}
+
+// verifyRRSet verifies that a signature of the zone covering the rrset is valid for one of the keys
+func verifyRRSet(set *signedRRSet, keys []dns.RR, zone string) error {
+	name := set.rrset[0].Header().Name
+	rrtype := dns.TypeToString[set.rrset[0].Header().Rrtype]
+	err := errors.Errorf("no signature of %s for the %s records of %s", zone, rrtype, name)
+	for _, signature := range set.signatures {
+		if !strings.EqualFold(signature.SignerName, zone) {
+			continue
+		}
+		if !signature.ValidityPeriod(time.Now()) {
+			err = errors.Errorf("signature of the %s records of %s is expired", rrtype, name)
+			continue
+		}
+		for _, rr := range keys {
+			key, ok := rr.(*dns.DNSKEY)
+			if !ok || key.KeyTag() != signature.KeyTag || key.Algorithm != signature.Algorithm {
+				continue
+			}
+			if verifyErr := signature.Verify(key, set.rrset); verifyErr != nil {
+				err = errors.Wrapf(verifyErr, "invalid signature of the %s records of %s", rrtype, name)
+				continue
+			}
+			return nil
+		}
+	}
+	return err
+}
+
+// matchDS returns true if one of the keys has the digest of one of the ds records
+func matchDS(records []*dns.DS, keys []dns.RR) bool {
+	for _, rr := range keys {
+		key, ok := rr.(*dns.DNSKEY)
+		if !ok {
+			continue
+		}
+		for _, record := range records {
+			if key.KeyTag() != record.KeyTag || key.Algorithm != record.Algorithm {
+				continue
+			}
+			if ds := key.ToDS(record.DigestType); ds != nil && strings.EqualFold(ds.Digest, record.Digest) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// anchorsOf returns the trust anchors of the zone
+func anchorsOf(zone string) []*dns.DS {
+	var anchors []*dns.DS
+	for _, anchor := range trustAnchors {
+		if strings.EqualFold(anchor.Hdr.Name, zone) {
+			anchors = append(anchors, anchor)
+		}
+	}
+	return anchors
+}
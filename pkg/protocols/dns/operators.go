@@ -42,6 +42,10 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(types.ToString(item)))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
+	case matchers.PluginMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchPlugin(types.ToString(item)))
+	case matchers.YaraMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchYara(types.ToString(item)))
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(types.ToString(item))), []string{}
 	}
@@ -62,6 +66,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractKval(data)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.PluginExtractor:
+		return extractor.ExtractPlugin(types.ToString(item))
 	}
 	return nil
 }
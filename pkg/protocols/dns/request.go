@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/miekg/dns"
 	"github.com/pkg/errors"
@@ -123,9 +124,26 @@ func (request *Request) execute(input *contextargs.Context, domain string, metad
 	}
 
 	request.options.RateLimiter.Take()
+	if request.options.HostRateLimiter != nil {
+		if err := request.options.HostRateLimiter.Take(input.Context(), domain); err != nil {
+			return err
+		}
+	}
 
 	// Send the request to the target servers
-	response, err := dnsClient.Do(compiledRequest)
+	timeStart := time.Now()
+	var response *dns.Msg
+	if isTransfer(request.question) {
+		response, err = request.transfer(input.Context(), dnsClient, compiledRequest, vars)
+	} else {
+		response, err = dnsClient.Do(compiledRequest)
+	}
+	if request.options.HostRateLimiter != nil {
+		request.options.HostRateLimiter.Observe(domain, time.Since(timeStart), nil, err)
+	}
+	if request.options.ScanStats != nil && err == nil {
+		request.options.ScanStats.Latency(request.Type().String(), time.Since(timeStart))
+	}
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, domain, request.Type().String(), err)
 		request.options.Progress.IncrementFailedRequestsBy(1)
@@ -136,7 +154,14 @@ func (request *Request) execute(input *contextargs.Context, domain string, metad
 		return errors.Wrap(err, "could not send dns request")
 	}
 
-	request.options.Output.Request(request.options.TemplatePath, domain, request.Type().String(), err)
+	request.options.Output.LogRequest(&output.RequestEvent{
+		Template:      request.options.TemplatePath,
+		Input:         domain,
+		Type:          request.Type().String(),
+		BytesSent:     compiledRequest.Len(),
+		BytesReceived: response.Len(),
+		Error:         err,
+	})
 	gologger.Verbose().Msgf("[%s] Sent DNS request to %s\n", request.options.TemplateID, question)
 
 	// perform trace if necessary
@@ -150,6 +175,9 @@ func (request *Request) execute(input *contextargs.Context, domain string, metad
 
 	// Create the output event
 	outputEvent := request.responseToDSLMap(compiledRequest, response, domain, question, traceData)
+	if request.DNSSEC && !isTransfer(request.question) {
+		outputEvent = generators.MergeMaps(outputEvent, request.validateDNSSEC(dnsClient, response).toDSLMap())
+	}
 	// expose response variables in proto_var format
 	// this is no-op if the template is not a multi protocol template
 	request.options.AddTemplateVars(input.MetaInput, request.Type(), request.ID, outputEvent)
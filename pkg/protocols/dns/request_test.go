@@ -1,8 +1,18 @@
 package dns
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/miekg/dns"
 	"github.com/stretchr/testify/require"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
@@ -13,6 +23,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
 
 func TestDNSExecuteWithResults(t *testing.T) {
@@ -82,3 +93,267 @@ func TestDNSExecuteWithResults(t *testing.T) {
 	require.Equal(t, "93.184.216.34", finalEvent.Results[0].ExtractedResults[0], "could not get correct extracted results")
 	finalEvent = nil
 }
+
+func TestDNSOverHTTPS(t *testing.T) {
+	var queries int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		msg := new(dns.Msg)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/dns-message" || msg.Unpack(body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(&queries, 1)
+		reply := new(dns.Msg)
+		reply.SetReply(msg)
+		reply.Answer = append(reply.Answer, &dns.A{
+			Hdr: dns.RR_Header{Name: msg.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+			A:   net.ParseIP("10.1.2.3"),
+		})
+		packed, _ := reply.Pack()
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(packed)
+	}))
+	defer server.Close()
+
+	options := testutils.DefaultOptions
+
+	recursion := false
+	testutils.Init(options)
+	templateID := "testing-dns-doh"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	request := &Request{
+		RequestType: DNSRequestTypeHolder{DNSRequestType: A},
+		Class:       "INET",
+		Retries:     1,
+		ID:          templateID,
+		Recursion:   &recursion,
+		Name:        "{{FQDN}}",
+		Resolvers:   []string{server.URL + "/dns-query"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Part:  "raw",
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"10.1.2.3"},
+			}},
+		},
+		options: executerOpts,
+	}
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile dns request")
+
+	var finalEvent *output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput("doh.example.com"), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute dns request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.Equal(t, 1, len(finalEvent.Results), "could not get correct number of results")
+	require.Equal(t, int32(1), atomic.LoadInt32(&queries), "could not query the doh resolver")
+}
+
+func TestDNSZoneTransfer(t *testing.T) {
+	records := []string{
+		"transfer.example. 3600 IN SOA ns.transfer.example. admin.transfer.example. 1 3600 600 86400 60",
+		"transfer.example. 3600 IN NS ns.transfer.example.",
+		"ns.transfer.example. 3600 IN A 10.0.0.1",
+		"internal.transfer.example. 3600 IN A 10.0.0.2",
+		"vpn.transfer.example. 3600 IN A 10.0.0.3",
+		"transfer.example. 3600 IN SOA ns.transfer.example. admin.transfer.example. 1 3600 600 86400 60",
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	server := &dns.Server{Listener: listener, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		if r.Question[0].Qtype != dns.TypeAXFR || r.Question[0].Name != "transfer.example." {
+			reply := new(dns.Msg)
+			reply.SetRcode(r, dns.RcodeRefused)
+			_ = w.WriteMsg(reply)
+			return
+		}
+		envelopes := make(chan *dns.Envelope)
+		transfer := new(dns.Transfer)
+		go func() {
+			// the records are sent in envelopes of two records
+			for i := 0; i < len(records); i += 2 {
+				var rrs []dns.RR
+				for _, record := range records[i : i+2] {
+					rr, _ := dns.NewRR(record)
+					rrs = append(rrs, rr)
+				}
+				envelopes <- &dns.Envelope{RR: rrs}
+			}
+			close(envelopes)
+		}()
+		_ = transfer.Out(w, r, envelopes)
+	})}
+	go func() { _ = server.ActivateAndServe() }()
+	defer func() { _ = server.Shutdown() }()
+
+	options := testutils.DefaultOptions
+
+	recursion := false
+	testutils.Init(options)
+	templateID := "testing-dns-axfr"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	newRequest := func(maxRecords int) *Request {
+		request := &Request{
+			RequestType: DNSRequestTypeHolder{DNSRequestType: AXFR},
+			Class:       "INET",
+			ID:          templateID,
+			Recursion:   &recursion,
+			Name:        "{{FQDN}}",
+			MaxRecords:  maxRecords,
+			Resolvers:   []string{"tcp:" + listener.Addr().String()},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Part:  "answer",
+					Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+					Words: []string{"internal.transfer.example."},
+				}},
+				Extractors: []*extractors.Extractor{{
+					Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.DSLExtractor},
+					DSL:  []string{"len(a)"},
+				}},
+			},
+			options: executerOpts,
+		}
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile dns request")
+		return request
+	}
+
+	var finalEvent *output.InternalWrappedEvent
+	err = newRequest(0).ExecuteWithResults(contextargs.NewWithInput("transfer.example"), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute dns request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.Equal(t, 1, len(finalEvent.Results), "could not match the transferred records")
+	require.Equal(t, []string{"3"}, finalEvent.Results[0].ExtractedResults, "could not extract the transferred records")
+
+	// the transfer is stopped at max-records
+	finalEvent = nil
+	err = newRequest(3).ExecuteWithResults(contextargs.NewWithInput("transfer.example"), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute dns request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.Equal(t, 0, len(finalEvent.Results), "could not stop the transfer at max-records")
+	require.Equal(t, 3, strings.Count(types.ToString(finalEvent.InternalEvent["answer"]), "\tIN\t"), "could not get the first records")
+
+	// the transfer is stopped with the scan
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	msg := new(dns.Msg)
+	msg.SetAxfr("transfer.example.")
+	_, err = newRequest(0).transfer(ctx, nil, msg, nil)
+	require.ErrorIs(t, err, context.Canceled, "could not stop the transfer with the context")
+}
+
+func TestDNSSECValidation(t *testing.T) {
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: "signed.example.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	privateKey, err := key.Generate(256)
+	require.Nil(t, err, "could not generate key")
+	sign := func(rrset ...dns.RR) dns.RR {
+		signature := &dns.RRSIG{
+			Hdr:        dns.RR_Header{Name: rrset[0].Header().Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 3600},
+			KeyTag:     key.KeyTag(),
+			SignerName: key.Hdr.Name,
+			Algorithm:  key.Algorithm,
+			Inception:  uint32(time.Now().Add(-time.Hour).Unix()),
+			Expiration: uint32(time.Now().Add(time.Hour).Unix()),
+		}
+		require.Nil(t, signature.Sign(privateKey.(*ecdsa.PrivateKey), rrset), "could not sign rrset")
+		return signature
+	}
+	record := func(value string) dns.RR {
+		rr, _ := dns.NewRR(value)
+		return rr
+	}
+	answers := map[string][]dns.RR{
+		"signed.example.":          {record("signed.example. 3600 IN A 10.0.0.1"), sign(record("signed.example. 3600 IN A 10.0.0.1"))},
+		"tampered.signed.example.": {record("tampered.signed.example. 3600 IN A 10.0.0.2"), sign(record("tampered.signed.example. 3600 IN A 10.0.0.3"))},
+		"unsigned.example.":        {record("unsigned.example. 3600 IN A 10.0.0.4")},
+	}
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		reply := new(dns.Msg)
+		reply.SetReply(r)
+		question := r.Question[0]
+		switch {
+		case question.Qtype == dns.TypeDNSKEY && question.Name == key.Hdr.Name:
+			reply.Answer = []dns.RR{key, sign(key)}
+		case question.Qtype == dns.TypeA:
+			for _, rr := range answers[question.Name] {
+				if _, ok := rr.(*dns.RRSIG); !ok || r.IsEdns0().Do() {
+					reply.Answer = append(reply.Answer, rr)
+				}
+			}
+		}
+		_ = w.WriteMsg(reply)
+	})}
+	go func() { _ = server.ActivateAndServe() }()
+	defer func() { _ = server.Shutdown() }()
+
+	defaultTrustAnchors := trustAnchors
+	defer func() { trustAnchors = defaultTrustAnchors }()
+	trustAnchors = []*dns.DS{key.ToDS(dns.SHA256)}
+
+	options := testutils.DefaultOptions
+
+	recursion := false
+	testutils.Init(options)
+	templateID := "testing-dnssec"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	request := &Request{
+		RequestType: DNSRequestTypeHolder{DNSRequestType: A},
+		Class:       "INET",
+		Retries:     1,
+		ID:          templateID,
+		Recursion:   &recursion,
+		Name:        "{{FQDN}}",
+		DNSSEC:      true,
+		Resolvers:   []string{conn.LocalAddr().String()},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{`dnssec_status != "secure"`},
+			}},
+		},
+		options: executerOpts,
+	}
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile dns request")
+
+	tests := map[string]string{
+		"signed.example":          DNSSECSecure,
+		"tampered.signed.example": DNSSECBogus,
+		"unsigned.example":        DNSSECInsecure,
+	}
+	for input, status := range tests {
+		var finalEvent *output.InternalWrappedEvent
+		err := request.ExecuteWithResults(contextargs.NewWithInput(input), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			finalEvent = event
+		})
+		require.Nil(t, err, "could not execute dns request")
+		require.NotNil(t, finalEvent, "could not get event output from request")
+		require.Equal(t, status, finalEvent.InternalEvent["dnssec_status"], "could not validate %v: %v", input, finalEvent.InternalEvent["dnssec_reason"])
+		require.Equal(t, status != DNSSECSecure, len(finalEvent.Results) == 1, "could not match dnssec status of %v", input)
+	}
+}
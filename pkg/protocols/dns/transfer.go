@@ -0,0 +1,164 @@
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/replacer"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/dns/dnsclientpool"
+	"github.com/projectdiscovery/retryabledns"
+)
+
+// defaultMaxRecords is the default maximum number of records of a zone transfer
+const defaultMaxRecords = 10000
+
+// isTransfer returns true if the question is an AXFR or IXFR zone transfer
+func isTransfer(question uint16) bool {
+	return question == dns.TypeAXFR || question == dns.TypeIXFR
+}
+
+// transferServer is a server a zone transfer is requested from
+type transferServer struct {
+	address string
+	tls     bool
+}
+
+// transfer requests the zone transfer of msg from the resolvers of the request
+// or the nameservers of the zone, the response contains the transferred records
+// of the first server allowing the transfer up to max-records.
+func (request *Request) transfer(ctx context.Context, dnsClient *retryabledns.Client, msg *dns.Msg, vars map[string]interface{}) (*dns.Msg, error) {
+	zone := msg.Question[0].Name
+	servers, err := request.transferServers(dnsClient, zone, vars)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for _, server := range servers {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		response, err := request.transferFrom(ctx, server, msg)
+		if err != nil {
+			lastErr = errors.Wrapf(err, "could not transfer zone %s from %s", zone, server.address)
+			gologger.Verbose().Msgf("[%s] %s\n", request.options.TemplateID, lastErr)
+			continue
+		}
+		return response, nil
+	}
+	if lastErr == nil {
+		lastErr = errors.Errorf("no nameservers found for zone %s", zone)
+	}
+	return nil, lastErr
+}
+
+// transferServers returns the servers to request the zone transfer from, the
+// udp, tcp and dot resolvers of the request or the nameservers of the zone.
+func (request *Request) transferServers(dnsClient *retryabledns.Client, zone string, vars map[string]interface{}) ([]transferServer, error) {
+	var servers []transferServer
+	for _, resolver := range request.Resolvers {
+		resolver, err := dnsclientpool.NormalizeResolver(replacer.Replace(resolver, vars))
+		if err != nil {
+			return nil, err
+		}
+		server := transferServer{address: resolver}
+		switch {
+		case strings.HasPrefix(resolver, "doh:"):
+			gologger.Verbose().Msgf("[%s] Skipping doh resolver %s for zone transfer\n", request.options.TemplateID, resolver)
+			continue
+		case strings.HasPrefix(resolver, "dot:"):
+			server = transferServer{address: strings.TrimPrefix(resolver, "dot:"), tls: true}
+		case strings.HasPrefix(resolver, "tcp:"), strings.HasPrefix(resolver, "udp:"):
+			server.address = resolver[len("tcp:"):]
+		}
+		if _, _, err := net.SplitHostPort(server.address); err != nil {
+			server.address = net.JoinHostPort(server.address, "53")
+		}
+		servers = append(servers, server)
+	}
+	if len(request.Resolvers) > 0 {
+		return servers, nil
+	}
+
+	nsData, err := dnsClient.NS(strings.TrimSuffix(zone, "."))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get nameservers")
+	}
+	for _, nameserver := range nsData.NS {
+		data, err := dnsClient.A(strings.TrimSuffix(nameserver, "."))
+		if err != nil {
+			continue
+		}
+		for _, ip := range data.A {
+			servers = append(servers, transferServer{address: net.JoinHostPort(ip, "53")})
+		}
+	}
+	return servers, nil
+}
+
+// transferFrom requests the zone transfer of msg from server, the transfer
+// is stopped once max-records records are received or the context is done.
+func (request *Request) transferFrom(ctx context.Context, server transferServer, msg *dns.Msg) (*dns.Msg, error) {
+	if err := protocolstate.CheckHost(server.address); err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(request.options.Options.Timeout) * time.Second
+	dialCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	conn, err := protocolstate.Dialer.Dial(dialCtx, "tcp", server.address)
+	if err != nil {
+		return nil, err
+	}
+	// the reads of the transfer are interrupted by closing the connection
+	rawConn := conn
+	stop := context.AfterFunc(ctx, func() { _ = rawConn.Close() })
+	defer stop()
+	if server.tls {
+		host, _, _ := net.SplitHostPort(server.address)
+		conn = tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+	}
+	transfer := &dns.Transfer{Conn: &dns.Conn{Conn: conn}, ReadTimeout: timeout, WriteTimeout: timeout}
+	envelopes, err := transfer.In(msg, server.address)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	maxRecords := request.MaxRecords
+	if maxRecords <= 0 {
+		maxRecords = defaultMaxRecords
+	}
+	response := new(dns.Msg)
+	response.SetReply(msg)
+	for envelope := range envelopes {
+		if envelope.Error != nil {
+			// the records received before the error are discarded as the transfer is incomplete
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, envelope.Error
+		}
+		response.Answer = append(response.Answer, envelope.RR...)
+		if len(response.Answer) >= maxRecords {
+			response.Answer = response.Answer[:maxRecords]
+			gologger.Verbose().Msgf("[%s] Stopped zone transfer of %s from %s at %d records\n", request.options.TemplateID, msg.Question[0].Name, server.address, maxRecords)
+			// the transfer goroutine exits on the read error of the closed connection
+			_ = conn.Close()
+			for range envelopes {
+			}
+			break
+		}
+	}
+	return response, nil
+}
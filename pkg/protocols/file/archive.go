@@ -0,0 +1,193 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/mholt/archiver"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/sevenzip"
+)
+
+// defaultArchiveDepth is the default maximum depth of nested archives extracted
+const defaultArchiveDepth = 3
+
+var defaultArchiveMaxSize, _ = units.FromHumanSize("1Gb")
+
+var errArchiveSizeLimit = errors.New("archive extraction size limit exceeded")
+
+// isArchive returns true if the file is an archive supported by the file protocol
+func isArchive(path string) bool {
+	if strings.EqualFold(filepath.Ext(path), ".7z") {
+		return true
+	}
+	archiveReader, _ := archiver.ByExtension(path)
+	return archiveReader != nil
+}
+
+// archiveBudget is the remaining size that can be extracted from an archive and its nested archives
+type archiveBudget struct {
+	remaining int64
+}
+
+// reader returns a reader failing once the extracted size exceeds the budget
+func (b *archiveBudget) reader(r io.Reader) io.Reader {
+	return &budgetReader{reader: r, budget: b}
+}
+
+type budgetReader struct {
+	reader io.Reader
+	budget *archiveBudget
+}
+
+func (r *budgetReader) Read(p []byte) (int, error) {
+	if r.budget.remaining <= 0 {
+		return 0, errArchiveSizeLimit
+	}
+	if int64(len(p)) > r.budget.remaining {
+		p = p[:r.budget.remaining]
+	}
+	n, err := r.reader.Read(p)
+	r.budget.remaining -= int64(n)
+	return n, err
+}
+
+// walkArchive calls fn with the name, size and content of the files of an archive,
+// single file compressed archives (ex: .gz) have an empty name
+func walkArchive(path string, fn func(name string, size int64, reader io.Reader) error) error {
+	if strings.EqualFold(filepath.Ext(path), ".7z") {
+		archive, file, err := sevenzip.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return archive.Walk(func(entry *sevenzip.File, reader io.Reader) error {
+			return fn(entry.Name, entry.Size, reader)
+		})
+	}
+
+	archiveReader, err := archiver.ByExtension(path)
+	if err != nil {
+		return err
+	}
+	switch archiveInstance := archiveReader.(type) {
+	case archiver.Walker:
+		return archiveInstance.Walk(path, func(file archiver.File) error {
+			defer file.Close()
+			if file.IsDir() {
+				return nil
+			}
+			return fn(archiveFileName(file), file.Size(), file)
+		})
+	case archiver.Decompressor:
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		stat, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		reader, writer := io.Pipe()
+		defer reader.Close()
+		go func() {
+			_ = writer.CloseWithError(archiveInstance.Decompress(file, writer))
+		}()
+		return fn("", stat.Size(), reader)
+	}
+	return errors.Errorf("unsupported archive %s", path)
+}
+
+// archiveFileName returns the path of a file in an archive
+func archiveFileName(file archiver.File) string {
+	switch header := file.Header.(type) {
+	case zip.FileHeader:
+		return header.Name
+	case *tar.Header:
+		return header.Name
+	}
+	return file.Name()
+}
+
+// processArchive matches the files of an archive and extracts its nested archives up to the depth limit
+func (request *Request) processArchive(path, displayPath string, depth int, budget *archiveBudget, input *contextargs.Context, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	return walkArchive(path, func(name string, size int64, reader io.Reader) error {
+		if budget.remaining <= 0 {
+			return errArchiveSizeLimit
+		}
+		entryPath, entryName := displayPath, name
+		if name != "" {
+			entryPath = filepath.Join(displayPath, name)
+		} else {
+			entryName = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+
+		if isArchive(entryName) {
+			if depth >= request.archiveDepth {
+				gologger.Verbose().Msgf("Ignoring nested archive %s: exceeded max depth %d\n", entryPath, request.archiveDepth)
+				return nil
+			}
+			err := request.processNestedArchive(entryName, entryPath, depth+1, budget.reader(reader), budget, input, previous, callback)
+			if err != nil {
+				if errors.Is(err, errArchiveSizeLimit) {
+					return err
+				}
+				gologger.Warning().Msgf("Could not extract nested archive %s: %s\n", entryPath, err)
+			}
+			return nil
+		}
+		if name != "" && !request.validatePath("/", name, true) {
+			return nil
+		}
+
+		// every new file in the compressed archive counts 1
+		request.options.Progress.AddToTotal(1)
+		event, fileMatches, err := request.processReader(budget.reader(reader), entryPath, input, size, previous)
+		if err != nil {
+			if errors.Is(err, errEmptyResult) {
+				// no matches but one file elaborated
+				request.options.Progress.IncrementRequests()
+				return nil
+			}
+			gologger.Error().Msgf("%s\n", err)
+			// error while elaborating the file
+			request.options.Progress.IncrementFailedRequestsBy(1)
+			return err
+		}
+		dumpResponse(event, request.options, fileMatches, entryPath)
+		callback(event)
+		// file elaborated and matched
+		request.options.Progress.IncrementRequests()
+		return nil
+	})
+}
+
+// processNestedArchive extracts a nested archive to a temporary file to process its files
+func (request *Request) processNestedArchive(name, displayPath string, depth int, reader io.Reader, budget *archiveBudget, input *contextargs.Context, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	// the temporary file keeps the archive extension to detect its format
+	tmpFile, err := os.CreateTemp("", "nuclei-archive-*-"+filepath.Base(name))
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpFile.Name())
+
+	_, err = io.Copy(tmpFile, reader)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return request.processArchive(tmpFile.Name(), displayPath, depth, budget, input, previous, callback)
+}
@@ -0,0 +1,125 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	permissionutil "github.com/projectdiscovery/utils/permission"
+)
+
+func createZip(t *testing.T, files map[string][]byte) []byte {
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		require.Nil(t, err)
+		_, err = file.Write(content)
+		require.Nil(t, err)
+	}
+	require.Nil(t, writer.Close())
+	return buf.Bytes()
+}
+
+func createTarGz(t *testing.T, files map[string][]byte) []byte {
+	buf := &bytes.Buffer{}
+	compressed := gzip.NewWriter(buf)
+	writer := tar.NewWriter(compressed)
+	for name, content := range files {
+		require.Nil(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write(content)
+		require.Nil(t, err)
+	}
+	require.Nil(t, writer.Close())
+	require.Nil(t, compressed.Close())
+	return buf.Bytes()
+}
+
+func TestFileArchives(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-file-archives"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+
+	deeper := createZip(t, map[string][]byte{"deeper/.env": []byte("SECRET=deeper\n")})
+	inner := createTarGz(t, map[string][]byte{
+		"backup/db.env":  []byte("SECRET=inner\n"),
+		"backup/old.zip": deeper,
+	})
+	archive := createZip(t, map[string][]byte{
+		"app/.env":            []byte("SECRET=app\n"),
+		"app/readme.md":       []byte("SECRET=ignored\n"),
+		"artifacts/inner.tgz": inner,
+	})
+	var dump bytes.Buffer
+	compressed := gzip.NewWriter(&dump)
+	_, _ = compressed.Write(createZip(t, map[string][]byte{"dump.env": []byte("SECRET=dump\n")}))
+	require.Nil(t, compressed.Close())
+
+	tempDir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(tempDir, "release.zip"), archive, permissionutil.TempFilePermission))
+	require.Nil(t, os.WriteFile(filepath.Join(tempDir, "dump.zip.gz"), dump.Bytes(), permissionutil.TempFilePermission))
+
+	newRequest := func(depth int, maxSize string) *Request {
+		request := &Request{
+			ID:             templateID,
+			Extensions:     []string{".env"},
+			Archive:        true,
+			ArchiveDepth:   depth,
+			ArchiveMaxSize: maxSize,
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Part:  "raw",
+					Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+					Words: []string{"SECRET"},
+				}},
+			},
+		}
+		require.Nil(t, request.Compile(executerOpts), "could not compile file request")
+		return request
+	}
+	execute := func(request *Request) []string {
+		var paths []string
+		err := request.ExecuteWithResults(contextargs.NewWithInput(tempDir), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			relative, _ := filepath.Rel(tempDir, types.ToString(event.InternalEvent["matched"]))
+			paths = append(paths, filepath.ToSlash(relative))
+		})
+		require.Nil(t, err, "could not execute file request")
+		sort.Strings(paths)
+		return paths
+	}
+
+	require.Equal(t, []string{
+		"dump.zip.gz/dump.env",
+		"release.zip/app/.env",
+		"release.zip/artifacts/inner.tgz/backup/db.env",
+		"release.zip/artifacts/inner.tgz/backup/old.zip/deeper/.env",
+	}, execute(newRequest(0, "")), "could not match files of nested archives")
+
+	require.Equal(t, []string{
+		"dump.zip.gz/dump.env",
+		"release.zip/app/.env",
+		"release.zip/artifacts/inner.tgz/backup/db.env",
+	}, execute(newRequest(2, "")), "could not limit nested archives depth")
+
+	require.NotContains(t, execute(newRequest(0, "100b")), "release.zip/artifacts/inner.tgz/backup/db.env", "could not limit archive extraction size")
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 )
 
 var (
@@ -22,7 +24,18 @@ type Request struct {
 	// Operators for the current request go here.
 	operators.Operators `yaml:",inline"`
 	// description: |
+	//   Path is the list of paths to perform matching on, relative paths are resolved
+	//   from the input directory.
+	//
+	//   Paths support variables (ex: {{app_dir}} passed with -var) and globs with
+	//   double-star, brace sets and exclusions prefixed with !.
+	// examples:
+	//   - value: '[]string{"{{app_dir}}/**/*.{yml,yaml}", "!**/testdata/**"}'
+	Path []string `yaml:"path,omitempty" json:"path,omitempty" jsonschema:"title=paths to match,description=List of paths or globs to perform matching on"`
+	// description: |
 	//   Extensions is the list of extensions or mime types to perform matching on.
+	//
+	//   Extensions support variables passed with -var.
 	// examples:
 	//   - value: '[]string{".txt", ".go", ".json"}'
 	Extensions []string `yaml:"extensions,omitempty" json:"extensions,omitempty" jsonschema:"title=extensions to match,description=List of extensions to perform matching on"`
@@ -51,8 +64,27 @@ type Request struct {
 
 	// description: |
 	//   elaborates archives
+	//
+	//   The files of zip, tar, gz, 7z and other archives are matched, nested
+	//   archives are extracted up to archive-depth.
 	Archive bool `yaml:"archive,omitempty" json:"archive,omitempty" jsonschema:"title=enable archives,description=Process compressed archives without unpacking"`
 
+	// description: |
+	//   ArchiveDepth is the maximum depth of nested archives extracted from archives.
+	//
+	//   By default, archives nested up to 3 levels deep are extracted.
+	ArchiveDepth int `yaml:"archive-depth,omitempty" json:"archive-depth,omitempty" jsonschema:"title=max depth of nested archives,description=Maximum depth of nested archives extracted from archives"`
+
+	// description: |
+	//   ArchiveMaxSize is the maximum size of the content extracted from an archive
+	//   including its nested archives.
+	//
+	//   By default, nuclei will extract 1 GB of content from an archive.
+	// examples:
+	//   - value: "\"100Mb\""
+	ArchiveMaxSize string `yaml:"archive-max-size,omitempty" json:"archive-max-size,omitempty" jsonschema:"title=max size of extracted archive content,description=Maximum size of the content extracted from an archive"`
+	archiveMaxSize int64
+
 	// description: |
 	//   enables mime types check
 	MimeType bool `yaml:"mime-type,omitempty" json:"mime-type,omitempty" jsonschema:"title=enable filtering by mime-type,description=Filter files by mime-type"`
@@ -65,6 +97,9 @@ type Request struct {
 	extensions          map[string]struct{}
 	denyList            map[string]struct{}
 	denyMimeTypesChecks []string
+	paths               []string
+	excludePaths        []string
+	archiveDepth        int
 
 	// description: |
 	//   NoRecursive specifies whether to not do recursive checks if folders are provided.
@@ -125,12 +160,47 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		request.maxSize = defaultMaxReadSize
 	}
 
+	request.archiveDepth = request.ArchiveDepth
+	if request.archiveDepth <= 0 {
+		request.archiveDepth = defaultArchiveDepth
+	}
+	request.archiveMaxSize = defaultArchiveMaxSize
+	if request.ArchiveMaxSize != "" {
+		archiveMaxSize, err := units.FromHumanSize(request.ArchiveMaxSize)
+		if err != nil {
+			return errors.Wrap(err, "could not parse archive max size")
+		}
+		request.archiveMaxSize = archiveMaxSize
+	}
+
 	request.options = options
 
 	request.extensions = make(map[string]struct{})
 	request.denyList = make(map[string]struct{})
 
+	values := generators.MergeMaps(options.Constants, options.Options.Vars.AsMap())
+	values = generators.MergeMaps(values, options.Variables.Evaluate(values))
+	for _, path := range request.Path {
+		evaluated, err := evaluateVariables(path, values)
+		if err != nil {
+			return errors.Wrapf(err, "could not evaluate path %s", path)
+		}
+		if exclude, ok := strings.CutPrefix(evaluated, "!"); ok {
+			request.excludePaths = append(request.excludePaths, exclude)
+		} else {
+			request.paths = append(request.paths, evaluated)
+		}
+	}
+
+	extensions := make([]string, 0, len(request.Extensions))
 	for _, extension := range request.Extensions {
+		evaluated, err := evaluateVariables(extension, values)
+		if err != nil {
+			return errors.Wrapf(err, "could not evaluate extension %s", extension)
+		}
+		extensions = append(extensions, evaluated)
+	}
+	for _, extension := range extensions {
 		switch {
 		case extension == "all":
 			request.allExtensions = true
@@ -143,7 +213,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 			request.extensions[extension] = struct{}{}
 		}
 	}
-	request.mimeTypesChecks = extractMimeTypes(request.Extensions)
+	request.mimeTypesChecks = extractMimeTypes(extensions)
 
 	// process default denylist (extensions)
 	var denyList []string
@@ -167,6 +237,21 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	return nil
 }
 
+// evaluateVariables evaluates the variables of a path or extension, returning an error for unresolved variables
+func evaluateVariables(data string, values map[string]interface{}) (string, error) {
+	if !strings.Contains(data, "{{") {
+		return data, nil
+	}
+	evaluated, err := expressions.Evaluate(data, values)
+	if err != nil {
+		return "", err
+	}
+	if unresolved := expressions.ContainsUnresolvedVariables(evaluated); unresolved != nil {
+		return "", unresolved
+	}
+	return evaluated, nil
+}
+
 func matchAnyMimeTypes(data []byte, mimeTypes []string) bool {
 	for _, mimeType := range mimeTypes {
 		if filetype.Is(data, mimeType) {
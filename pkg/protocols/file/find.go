@@ -19,6 +19,14 @@ import (
 func (request *Request) getInputPaths(target string, callback func(string)) error {
 	processed := make(map[string]struct{})
 
+	// Template paths are resolved from the input
+	if len(request.paths) > 0 {
+		if err := request.findPathMatches(target, processed, callback); err != nil {
+			return errors.Wrap(err, "could not find path matches")
+		}
+		return nil
+	}
+
 	// Template input includes a wildcard
 	if strings.Contains(target, "*") && !request.NoRecursive {
 		if err := request.findGlobPathMatches(target, processed, callback); err != nil {
@@ -64,6 +72,70 @@ func (request *Request) findGlobPathMatches(absPath string, processed map[string
 	return nil
 }
 
+// findPathMatches returns the matched files of the template paths resolved from the input,
+// skipping the files matching any of the excluded paths
+func (request *Request) findPathMatches(target string, processed map[string]struct{}, callback func(string)) error {
+	var excludes []*globPattern
+	for _, exclude := range request.excludePaths {
+		patterns, err := compileGlobPatterns(resolvePath(target, exclude))
+		if err != nil {
+			return err
+		}
+		excludes = append(excludes, patterns...)
+	}
+	filteredCallback := func(path string) {
+		for _, exclude := range excludes {
+			if exclude.Match(path) {
+				return
+			}
+		}
+		callback(path)
+	}
+
+	for _, path := range request.paths {
+		resolved := resolvePath(target, path)
+		if !isGlob(resolved) {
+			// missing paths are skipped as they depend on the layout of the input
+			file, err := request.findFileMatches(resolved, processed, filteredCallback)
+			if err != nil || file || request.NoRecursive {
+				continue
+			}
+			if err := request.findDirectoryMatches(resolved, processed, filteredCallback); err != nil {
+				return err
+			}
+			continue
+		}
+		patterns, err := compileGlobPatterns(resolved)
+		if err != nil {
+			return err
+		}
+		for _, pattern := range patterns {
+			err := pattern.walk(func(match string) {
+				if _, ok := processed[match]; ok {
+					return
+				}
+				if !request.validatePath(pattern.base, match, false) {
+					return
+				}
+				processed[match] = struct{}{}
+				filteredCallback(match)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolvePath resolves a relative template path from the input directory
+func resolvePath(target, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(target, path)
+}
+
 // findFileMatches finds if a path is an absolute file. If the path
 // is a file, it returns true otherwise false with no errors.
 func (request *Request) findFileMatches(absPath string, processed map[string]struct{}, callback func(string)) (bool, error) {
@@ -112,8 +184,8 @@ func (request *Request) findDirectoryMatches(absPath string, processed map[strin
 // validatePath validates a file path for blacklist and whitelist options
 func (request *Request) validatePath(absPath, item string, inArchive bool) bool {
 	extension := filepath.Ext(item)
-	// extension check
-	if len(request.extensions) > 0 {
+	// extension check, archives are extracted to check the extensions of their files
+	if len(request.extensions) > 0 && !(request.Archive && !inArchive && isArchive(item)) {
 		if _, ok := request.extensions[extension]; ok {
 			return true
 		} else if !request.allExtensions {
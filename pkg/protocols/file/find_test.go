@@ -65,3 +65,61 @@ func TestFindInputPaths(t *testing.T) {
 	require.Nil(t, err, "could not get input paths for directory")
 	require.ElementsMatch(t, expected, got, "could not get correct file matches for directory")
 }
+
+func TestFindTemplatePaths(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	require.Nil(t, options.Vars.Set("app_dir=service"))
+	require.Nil(t, options.Vars.Set("config_ext=.yml"))
+	defer func() {
+		_ = options.Vars.Del("app_dir")
+		_ = options.Vars.Del("config_ext")
+	}()
+	templateID := "testing-file-paths"
+	request := &Request{
+		ID:         templateID,
+		MaxSize:    "1Gb",
+		Path:       []string{"{{app_dir}}/**/*.{yml,json}", "{{app_dir}}/conf", "!**/testdata/**"},
+		Extensions: []string{"{{config_ext}}", ".json", ".ini"},
+		Operators:  newMockOperator(),
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile file request")
+
+	tempDir := t.TempDir()
+	files := []string{
+		"service/app.yml",
+		"service/nested/deep/db.json",
+		"service/nested/readme.md",
+		"service/testdata/fixture.yml",
+		"service/conf/app.ini",
+		"other/app.yml",
+	}
+	for _, file := range files {
+		path := filepath.Join(tempDir, file)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.Nil(t, os.WriteFile(path, []byte("TEST"), permissionutil.TempFilePermission), "could not write temporary file")
+	}
+
+	got := []string{}
+	err = request.getInputPaths(tempDir, func(item string) {
+		relative, _ := filepath.Rel(tempDir, item)
+		got = append(got, filepath.ToSlash(relative))
+	})
+	require.Nil(t, err, "could not get input paths for template paths")
+	require.ElementsMatch(t, []string{"service/app.yml", "service/nested/deep/db.json", "service/conf/app.ini"}, got, "could not get correct file matches for template paths")
+
+	unresolved := &Request{ID: templateID, Path: []string{"{{missing}}/*.yml"}, Operators: newMockOperator()}
+	require.NotNil(t, unresolved.Compile(executerOpts), "could not detect unresolved path variables")
+}
+
+func TestExpandBraces(t *testing.T) {
+	require.Equal(t, []string{"a/b/d", "a/c/d"}, expandBraces("a/{b,c}/d"))
+	require.Equal(t, []string{"x.yml", "x.yaml", "x.json"}, expandBraces("x.{y{ml,aml},json}"))
+	require.Equal(t, []string{"a/{b"}, expandBraces("a/{b"), "could not keep unbalanced braces")
+}
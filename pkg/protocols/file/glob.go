@@ -0,0 +1,159 @@
+package file
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// globPattern is a compiled path pattern supporting double-star, brace sets
+// and the standard *, ? and [class] wildcards
+type globPattern struct {
+	pattern string
+	// base is the static directory prefix of the pattern to start walking from
+	base  string
+	regex *regexp.Regexp
+}
+
+// compileGlobPatterns expands the brace sets of a pattern and compiles the results
+func compileGlobPatterns(pattern string) ([]*globPattern, error) {
+	var compiled []*globPattern
+	for _, expanded := range expandBraces(pattern) {
+		glob, err := compileGlob(expanded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not compile path %s", pattern)
+		}
+		compiled = append(compiled, glob)
+	}
+	return compiled, nil
+}
+
+// isGlob returns true if the path contains glob meta characters
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[{")
+}
+
+// expandBraces expands the brace sets of a pattern, ex: a/{b,c}/d => a/b/d, a/c/d
+func expandBraces(pattern string) []string {
+	start := strings.Index(pattern, "{")
+	if start == -1 {
+		return []string{pattern}
+	}
+	depth, end := 0, -1
+	var alternatives []string
+	last := start + 1
+	for i := start; i < len(pattern) && end == -1; i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				alternatives = append(alternatives, pattern[last:i])
+				end = i
+			}
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[last:i])
+				last = i + 1
+			}
+		}
+	}
+	if end == -1 {
+		// unbalanced braces are matched literally
+		return []string{pattern}
+	}
+	var expanded []string
+	for _, alternative := range alternatives {
+		expanded = append(expanded, expandBraces(pattern[:start]+alternative+pattern[end+1:])...)
+	}
+	return expanded
+}
+
+// compileGlob compiles a brace expanded pattern to a regular expression
+func compileGlob(pattern string) (*globPattern, error) {
+	pattern = filepath.ToSlash(pattern)
+	glob := &globPattern{pattern: pattern}
+
+	segments := strings.Split(pattern, "/")
+	var static []string
+	for _, segment := range segments {
+		if isGlob(segment) {
+			break
+		}
+		static = append(static, segment)
+	}
+	glob.base = strings.Join(static, "/")
+	switch {
+	case glob.base == "" && strings.HasPrefix(pattern, "/"):
+		glob.base = "/"
+	case glob.base == "":
+		glob.base = "."
+	}
+	glob.base = filepath.FromSlash(glob.base)
+
+	var builder strings.Builder
+	builder.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch char := pattern[i]; char {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					// **/ matches zero or more directories
+					i++
+					builder.WriteString("(?:.*/)?")
+				} else {
+					builder.WriteString(".*")
+				}
+			} else {
+				builder.WriteString("[^/]*")
+			}
+		case '?':
+			builder.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end == -1 {
+				return nil, errors.New("unterminated character class")
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			builder.WriteString("[" + class + "]")
+			i += end
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	builder.WriteString("$")
+
+	regex, err := regexp.Compile(builder.String())
+	if err != nil {
+		return nil, err
+	}
+	glob.regex = regex
+	return glob, nil
+}
+
+// Match returns true if the path matches the pattern
+func (g *globPattern) Match(path string) bool {
+	return g.regex.MatchString(filepath.ToSlash(path))
+}
+
+// walk calls callback with the files below the base directory matching the pattern
+func (g *globPattern) walk(callback func(string)) error {
+	return filepath.WalkDir(g.base, func(path string, d fs.DirEntry, err error) error {
+		// continue on errors
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && g.Match(path) {
+			callback(path)
+		}
+		return nil
+	})
+}
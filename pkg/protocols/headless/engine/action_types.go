@@ -80,60 +80,90 @@ const (
 	// ActionWaitVisible waits until an element appears.
 	// name:waitvisible
 	ActionWaitVisible
+	// ActionSetDevice emulates a device profile (viewport, user agent and touch).
+	// name:setdevice
+	ActionSetDevice
+	// ActionSetGeolocation overrides the geolocation of the page.
+	// name:setgeolocation
+	ActionSetGeolocation
+	// ActionSetLocale overrides the locale and timezone of the page.
+	// name:setlocale
+	ActionSetLocale
+	// ActionThrottle emulates network conditions for the page.
+	// name:throttle
+	ActionThrottle
+	// ActionTrace records a performance trace of the page writing to a file.
+	// name:trace
+	ActionTrace
+	// ActionFrame selects the iframe used by element actions.
+	// name:frame
+	ActionFrame
 	// limit
 	limit
 )
 
 // ActionStringToAction converts an action from string to internal representation
 var ActionStringToAction = map[string]ActionType{
-	"navigate":     ActionNavigate,
-	"script":       ActionScript,
-	"click":        ActionClick,
-	"rightclick":   ActionRightClick,
-	"text":         ActionTextInput,
-	"screenshot":   ActionScreenshot,
-	"time":         ActionTimeInput,
-	"select":       ActionSelectInput,
-	"files":        ActionFilesInput,
-	"waitload":     ActionWaitLoad,
-	"getresource":  ActionGetResource,
-	"extract":      ActionExtract,
-	"setmethod":    ActionSetMethod,
-	"addheader":    ActionAddHeader,
-	"setheader":    ActionSetHeader,
-	"deleteheader": ActionDeleteHeader,
-	"setbody":      ActionSetBody,
-	"waitevent":    ActionWaitEvent,
-	"keyboard":     ActionKeyboard,
-	"debug":        ActionDebug,
-	"sleep":        ActionSleep,
-	"waitvisible":  ActionWaitVisible,
+	"navigate":       ActionNavigate,
+	"script":         ActionScript,
+	"click":          ActionClick,
+	"rightclick":     ActionRightClick,
+	"text":           ActionTextInput,
+	"screenshot":     ActionScreenshot,
+	"time":           ActionTimeInput,
+	"select":         ActionSelectInput,
+	"files":          ActionFilesInput,
+	"waitload":       ActionWaitLoad,
+	"getresource":    ActionGetResource,
+	"extract":        ActionExtract,
+	"setmethod":      ActionSetMethod,
+	"addheader":      ActionAddHeader,
+	"setheader":      ActionSetHeader,
+	"deleteheader":   ActionDeleteHeader,
+	"setbody":        ActionSetBody,
+	"waitevent":      ActionWaitEvent,
+	"keyboard":       ActionKeyboard,
+	"debug":          ActionDebug,
+	"sleep":          ActionSleep,
+	"waitvisible":    ActionWaitVisible,
+	"setdevice":      ActionSetDevice,
+	"setgeolocation": ActionSetGeolocation,
+	"setlocale":      ActionSetLocale,
+	"throttle":       ActionThrottle,
+	"trace":          ActionTrace,
+	"frame":          ActionFrame,
 }
 
 // ActionToActionString converts an action from  internal representation to string
 var ActionToActionString = map[ActionType]string{
-	ActionNavigate:     "navigate",
-	ActionScript:       "script",
-	ActionClick:        "click",
-	ActionRightClick:   "rightclick",
-	ActionTextInput:    "text",
-	ActionScreenshot:   "screenshot",
-	ActionTimeInput:    "time",
-	ActionSelectInput:  "select",
-	ActionFilesInput:   "files",
-	ActionWaitLoad:     "waitload",
-	ActionGetResource:  "getresource",
-	ActionExtract:      "extract",
-	ActionSetMethod:    "setmethod",
-	ActionAddHeader:    "addheader",
-	ActionSetHeader:    "setheader",
-	ActionDeleteHeader: "deleteheader",
-	ActionSetBody:      "setbody",
-	ActionWaitEvent:    "waitevent",
-	ActionKeyboard:     "keyboard",
-	ActionDebug:        "debug",
-	ActionSleep:        "sleep",
-	ActionWaitVisible:  "waitvisible",
+	ActionNavigate:       "navigate",
+	ActionScript:         "script",
+	ActionClick:          "click",
+	ActionRightClick:     "rightclick",
+	ActionTextInput:      "text",
+	ActionScreenshot:     "screenshot",
+	ActionTimeInput:      "time",
+	ActionSelectInput:    "select",
+	ActionFilesInput:     "files",
+	ActionWaitLoad:       "waitload",
+	ActionGetResource:    "getresource",
+	ActionExtract:        "extract",
+	ActionSetMethod:      "setmethod",
+	ActionAddHeader:      "addheader",
+	ActionSetHeader:      "setheader",
+	ActionDeleteHeader:   "deleteheader",
+	ActionSetBody:        "setbody",
+	ActionWaitEvent:      "waitevent",
+	ActionKeyboard:       "keyboard",
+	ActionDebug:          "debug",
+	ActionSleep:          "sleep",
+	ActionWaitVisible:    "waitvisible",
+	ActionSetDevice:      "setdevice",
+	ActionSetGeolocation: "setgeolocation",
+	ActionSetLocale:      "setlocale",
+	ActionThrottle:       "throttle",
+	ActionTrace:          "trace",
+	ActionFrame:          "frame",
 }
 
 // GetSupportedActionTypes returns list of supported types
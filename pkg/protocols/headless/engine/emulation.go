@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/go-rod/rod/lib/devices"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// emulatedDevices contains the device profiles that can be used with the setdevice action
+var emulatedDevices = map[string]devices.Device{}
+
+func init() {
+	for _, device := range []devices.Device{
+		devices.IPhone4, devices.IPhone5orSE, devices.IPhone6or7or8, devices.IPhone6or7or8Plus, devices.IPhoneX,
+		devices.BlackBerryZ30, devices.Nexus4, devices.Nexus5, devices.Nexus5X, devices.Nexus6, devices.Nexus6P,
+		devices.Pixel2, devices.Pixel2XL, devices.LGOptimusL70, devices.NokiaN9, devices.NokiaLumia520,
+		devices.MicrosoftLumia550, devices.MicrosoftLumia950, devices.GalaxySIII, devices.GalaxyS5, devices.JioPhone2,
+		devices.KindleFireHDX, devices.IPadMini, devices.IPad, devices.IPadPro, devices.BlackberryPlayBook,
+		devices.Nexus10, devices.Nexus7, devices.GalaxyNote3, devices.GalaxyNoteII, devices.LaptopWithTouch,
+		devices.LaptopWithHiDPIScreen, devices.LaptopWithMDPIScreen, devices.MotoG4, devices.SurfaceDuo, devices.GalaxyFold,
+	} {
+		emulatedDevices[normalizeDeviceName(device.Title)] = device
+	}
+}
+
+// normalizeDeviceName normalizes a device name so that "iPhone X", "iphonex"
+// and "iphone-x" all refer to the same device profile
+func normalizeDeviceName(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(name))
+}
+
+// getEmulatedDevice returns a device profile by its name
+func getEmulatedDevice(name string) (devices.Device, bool) {
+	device, ok := emulatedDevices[normalizeDeviceName(name)]
+	return device, ok
+}
+
+// networkConditionPresets contains the network throttling presets for the throttle action
+// based on the profiles available in chrome devtools
+var networkConditionPresets = map[string]proto.NetworkEmulateNetworkConditions{
+	"offline": {
+		Offline:            true,
+		DownloadThroughput: -1,
+		UploadThroughput:   -1,
+		ConnectionType:     proto.NetworkConnectionTypeNone,
+	},
+	"slow3g": {
+		Latency:            2000,
+		DownloadThroughput: 50 * 1024,
+		UploadThroughput:   50 * 1024,
+		ConnectionType:     proto.NetworkConnectionTypeCellular3g,
+	},
+	"fast3g": {
+		Latency:            562.5,
+		DownloadThroughput: 180 * 1024,
+		UploadThroughput:   84 * 1024,
+		ConnectionType:     proto.NetworkConnectionTypeCellular3g,
+	},
+	"4g": {
+		Latency:            150,
+		DownloadThroughput: 1.6 * 1024 * 1024 / 8,
+		UploadThroughput:   750 * 1024 / 8,
+		ConnectionType:     proto.NetworkConnectionTypeCellular4g,
+	},
+	"none": {
+		DownloadThroughput: -1,
+		UploadThroughput:   -1,
+	},
+}
@@ -1,12 +1,15 @@
 package engine
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/launcher/flags"
 	"github.com/pkg/errors"
@@ -25,13 +28,82 @@ type Browser struct {
 	engine       *rod.Browser
 	httpclient   *http.Client
 	options      *types.Options
+	// remoteConn is the devtools protocol connection of an externally
+	// managed browser, nil if the browser was launched by nuclei
+	remoteConn io.Closer
 }
 
 // New creates a new nuclei headless browser module
 func New(options *types.Options) (*Browser, error) {
+	var (
+		launcherURL  string
+		dataStore    string
+		previousPIDs map[int32]struct{}
+		err          error
+	)
+	browser := rod.New()
+	var remoteConn io.Closer
+	if options.HeadlessRemoteURL != "" {
+		// connect to an already running browser instead of launching one,
+		// the connection is kept to close it without closing the browser
+		launcherURL, err = launcher.ResolveURL(options.HeadlessRemoteURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not resolve remote browser url")
+		}
+		ws := &cdp.WebSocket{}
+		if err := ws.Connect(context.Background(), launcherURL, nil); err != nil {
+			return nil, errors.Wrap(err, "could not connect to remote browser")
+		}
+		browser = browser.Client(cdp.New().Start(ws))
+		remoteConn = ws
+	} else {
+		dataStore, previousPIDs, launcherURL, err = launchLocalBrowser(options)
+		if err != nil {
+			return nil, err
+		}
+		browser = browser.ControlURL(launcherURL)
+	}
+
+	if browserErr := browser.Connect(); browserErr != nil {
+		if remoteConn != nil {
+			_ = remoteConn.Close()
+		}
+		return nil, browserErr
+	}
+	customAgent := ""
+	for _, option := range options.CustomHeaders {
+		parts := strings.SplitN(option, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if strings.EqualFold(parts[0], "User-Agent") {
+			customAgent = parts[1]
+		}
+	}
+
+	httpclient, err := newHttpClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := &Browser{
+		tempDir:     dataStore,
+		customAgent: customAgent,
+		engine:      browser,
+		httpclient:  httpclient,
+		options:     options,
+		remoteConn:  remoteConn,
+	}
+	engine.previousPIDs = previousPIDs
+	return engine, nil
+}
+
+// launchLocalBrowser launches a local chrome instance returning its data directory,
+// the chrome processes that were already running and the devtools control url
+func launchLocalBrowser(options *types.Options) (string, map[int32]struct{}, string, error) {
 	dataStore, err := os.MkdirTemp("", "nuclei-*")
 	if err != nil {
-		return nil, errors.Wrap(err, "could not create temporary directory")
+		return "", nil, "", errors.Wrap(err, "could not create temporary directory")
 	}
 	previousPIDs := processutil.FindProcesses(processutil.IsChromeProcess)
 
@@ -55,7 +127,7 @@ func New(options *types.Options) (*Browser, error) {
 
 	executablePath, err := os.Executable()
 	if err != nil {
-		return nil, err
+		return "", nil, "", err
 	}
 
 	// if musl is used, most likely we are on alpine linux which is not supported by go-rod, so we fallback to default chrome
@@ -64,7 +136,7 @@ func New(options *types.Options) (*Browser, error) {
 		if chromePath, hasChrome := launcher.LookPath(); hasChrome {
 			chromeLauncher.Bin(chromePath)
 		} else {
-			return nil, errors.New("the chrome browser is not installed")
+			return "", nil, "", errors.New("the chrome browser is not installed")
 		}
 	}
 
@@ -83,38 +155,9 @@ func New(options *types.Options) (*Browser, error) {
 
 	launcherURL, err := chromeLauncher.Launch()
 	if err != nil {
-		return nil, err
-	}
-
-	browser := rod.New().ControlURL(launcherURL)
-	if browserErr := browser.Connect(); browserErr != nil {
-		return nil, browserErr
-	}
-	customAgent := ""
-	for _, option := range options.CustomHeaders {
-		parts := strings.SplitN(option, ":", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		if strings.EqualFold(parts[0], "User-Agent") {
-			customAgent = parts[1]
-		}
-	}
-
-	httpclient, err := newHttpClient(options)
-	if err != nil {
-		return nil, err
+		return "", nil, "", err
 	}
-
-	engine := &Browser{
-		tempDir:     dataStore,
-		customAgent: customAgent,
-		engine:      browser,
-		httpclient:  httpclient,
-		options:     options,
-	}
-	engine.previousPIDs = previousPIDs
-	return engine, nil
+	return dataStore, previousPIDs, launcherURL, nil
 }
 
 // MustDisableSandbox determines if the current os and user needs sandbox mode disabled
@@ -136,6 +179,12 @@ func (b *Browser) UserAgent() string {
 
 // Close closes the browser engine
 func (b *Browser) Close() {
+	if b.remoteConn != nil {
+		// remote browsers are managed externally, only the connection is
+		// closed and the incognito contexts are closed by the instances
+		_ = b.remoteConn.Close()
+		return
+	}
 	b.engine.Close()
 	os.RemoveAll(b.tempDir)
 	processutil.CloseProcesses(processutil.IsChromeProcess, b.previousPIDs)
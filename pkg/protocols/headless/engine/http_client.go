@@ -12,6 +12,7 @@ import (
 	"golang.org/x/net/proxy"
 
 	"github.com/projectdiscovery/fastdialer/fastdialer/ja3/impersonate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
@@ -31,6 +32,7 @@ func newHttpClient(options *types.Options) (*http.Client, error) {
 	if options.SNI != "" {
 		tlsConfig.ServerName = options.SNI
 	}
+	fips.RestrictTLSConfig(tlsConfig)
 
 	// Add the client certificate authentication to the request if it's configured
 	var err error
@@ -46,7 +48,7 @@ func newHttpClient(options *types.Options) (*http.Client, error) {
 			if options.TlsImpersonate {
 				return dialer.DialTLSWithConfigImpersonate(ctx, network, addr, tlsConfig, impersonate.Random, nil)
 			}
-			if options.HasClientCertificates() || options.ForceAttemptHTTP2 {
+			if options.HasClientCertificates() || options.ForceAttemptHTTP2 || fips.IsEnabled() {
 				return dialer.DialTLSWithConfig(ctx, network, addr, tlsConfig)
 			}
 			return dialer.DialTLS(ctx, network, addr)
@@ -2,9 +2,11 @@ package engine
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -28,7 +30,18 @@ type Page struct {
 	mutex          *sync.RWMutex
 	History        []HistoryData
 	InteractshURLs []string
-	payloads       map[string]interface{}
+	// Artifacts contains the paths of files generated by actions (screenshots, traces)
+	Artifacts []string
+	// Performance contains the timing and runtime data collected after the actions
+	Performance   *Performance
+	payloads      map[string]interface{}
+	trace         *pageTrace
+	consoleErrors []string
+	// frame is the iframe selected with the frame action, element
+	// actions are executed on the main page when nil
+	frame *rod.Page
+	// extraHeaders are the name/value pairs of the extra headers sent by the page
+	extraHeaders []string
 }
 
 // HistoryData contains the page request/response pairs
@@ -50,7 +63,8 @@ func (i *Instance) Run(input *contextargs.Context, actions []*Action, payloads m
 	if err != nil {
 		return nil, nil, err
 	}
-	page = page.Timeout(options.Timeout)
+	// actions are aborted once the scan is cancelled
+	page = page.Context(input.Context()).Timeout(options.Timeout)
 
 	if i.browser.customAgent != "" {
 		if userAgentErr := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: i.browser.customAgent}); userAgentErr != nil {
@@ -66,6 +80,7 @@ func (i *Instance) Run(input *contextargs.Context, actions []*Action, payloads m
 		mutex:    &sync.RWMutex{},
 		payloads: payloads,
 	}
+	createdPage.watchConsoleErrors()
 
 	// in case the page has request/response modification rules - enable global hijacking
 	if createdPage.hasModificationRules() || containsModificationActions(actions...) {
@@ -96,7 +111,7 @@ func (i *Instance) Run(input *contextargs.Context, actions []*Action, payloads m
 		return nil, nil, err
 	}
 
-	if _, err := page.SetExtraHeaders([]string{"Accept-Language", "en, en-GB, en-us;"}); err != nil {
+	if err := createdPage.setExtraHeaders("Accept-Language", "en, en-GB, en-us;"); err != nil {
 		return nil, nil, err
 	}
 
@@ -140,6 +155,10 @@ func (i *Instance) Run(input *contextargs.Context, actions []*Action, payloads m
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := createdPage.stopTrace(); err != nil {
+		return nil, nil, err
+	}
+	createdPage.Performance = createdPage.collectPerformance()
 
 	if options.CookieReuse {
 		// at the end of actions pull out updated cookies from the browser and inject them into the shared cookie jar
@@ -182,7 +201,8 @@ func (p *Page) Close() {
 	if p.hijackNative != nil {
 		_ = p.hijackNative.Stop()
 	}
-	p.page.Close()
+	// the page context may already be cancelled, close it with a fresh one
+	_ = p.page.Context(context.Background()).Close()
 }
 
 // Page returns the current page for the actions
@@ -204,6 +224,18 @@ func (p *Page) URL() string {
 	return info.URL
 }
 
+// TransferredBytes returns the size of the requests and responses of the page navigation history
+func (p *Page) TransferredBytes() (sent, received int) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, historyData := range p.History {
+		sent += len(historyData.RawRequest)
+		received += len(historyData.RawResponse)
+	}
+	return sent, received
+}
+
 // DumpHistory returns the full page navigation history
 func (p *Page) DumpHistory() string {
 	p.mutex.RLock()
@@ -225,6 +257,17 @@ func (p *Page) addToHistory(historyData ...HistoryData) {
 	p.History = append(p.History, historyData...)
 }
 
+// addArtifact adds the path of a file generated by an action to the page artifacts
+func (p *Page) addArtifact(path string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	p.Artifacts = append(p.Artifacts, path)
+}
+
 func (p *Page) addInteractshURL(URLs ...string) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -282,3 +325,22 @@ func GetSameSite(cookie *http.Cookie) string {
 		return ""
 	}
 }
+
+// setExtraHeaders merges the name/value pairs with the extra headers already
+// sent by the page, the headers with the same name are replaced
+func (p *Page) setExtraHeaders(headers ...string) error {
+	for i := 0; i+1 < len(headers); i += 2 {
+		replaced := false
+		for j := 0; j+1 < len(p.extraHeaders); j += 2 {
+			if strings.EqualFold(p.extraHeaders[j], headers[i]) {
+				p.extraHeaders[j+1] = headers[i+1]
+				replaced = true
+			}
+		}
+		if !replaced {
+			p.extraHeaders = append(p.extraHeaders, headers[i], headers[i+1])
+		}
+	}
+	_, err := p.page.SetExtraHeaders(p.extraHeaders)
+	return err
+}
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -94,6 +95,18 @@ func (p *Page) ExecuteActions(input *contextargs.Context, actions []*Action, var
 			err = p.SleepAction(act, outData)
 		case ActionWaitVisible:
 			err = p.WaitVisible(act, outData)
+		case ActionSetDevice:
+			err = p.SetDevice(act, outData)
+		case ActionSetGeolocation:
+			err = p.SetGeolocation(act, outData)
+		case ActionSetLocale:
+			err = p.SetLocale(act, outData)
+		case ActionThrottle:
+			err = p.Throttle(act, outData)
+		case ActionTrace:
+			err = p.Trace(act, outData)
+		case ActionFrame:
+			err = p.SelectFrame(act, outData)
 		default:
 			continue
 		}
@@ -140,15 +153,22 @@ func (p *Page) WaitVisible(act *Action, out map[string]string) error {
 
 func (p *Page) Sleeper(pollTimeout, timeout time.Duration) *Page {
 	page := *p
-	page.page = page.Page().Sleeper(func() utils.Sleeper {
+	sleeper := func() utils.Sleeper {
 		return createBackOffSleeper(pollTimeout, timeout)
-	})
+	}
+	page.page = page.Page().Sleeper(sleeper)
+	if page.frame != nil {
+		page.frame = page.frame.Sleeper(sleeper)
+	}
 	return &page
 }
 
 func (p *Page) Timeout(timeout time.Duration) *Page {
 	page := *p
 	page.page = page.Page().Timeout(timeout)
+	if page.frame != nil {
+		page.frame = page.frame.Timeout(timeout)
+	}
 	return &page
 }
 
@@ -389,10 +409,89 @@ func (p *Page) Screenshot(act *Action, out map[string]string) error {
 	if err != nil {
 		return errors.Wrap(err, "could not write screenshot")
 	}
+	p.addArtifact(filePath)
 	gologger.Info().Msgf("Screenshot successfully saved at %v\n", filePath)
 	return nil
 }
 
+// pageTrace is a performance trace being recorded for a page
+type pageTrace struct {
+	path     string
+	wait     func()
+	complete *proto.TracingTracingComplete
+}
+
+// Trace starts recording a performance trace of the page.
+//
+// The trace is recorded until all the actions have been executed and
+// is written as json to the file specified in the `to` arg.
+func (p *Page) Trace(act *Action, out map[string]string) error {
+	if p.trace != nil {
+		return errorutil.NewWithTag("trace", "trace is already being recorded")
+	}
+	to := p.getActionArgWithDefaultValues(act, "to")
+	if to == "" {
+		to = ksuid.New().String()
+		if act.Name != "" {
+			out[act.Name] = to
+		}
+	}
+	filePath := to
+	if !strings.HasSuffix(to, ".json") {
+		filePath += ".json"
+	}
+	if fileutil.FileExists(filePath) {
+		// return custom error as overwriting files is not supported
+		return errorutil.NewWithTag("trace", "failed to write trace, file %v already exists", filePath)
+	}
+	if p.getActionArgWithDefaultValues(act, "mkdir") == "true" && stringsutil.ContainsAny(to, folderutil.UnixPathSeparator, folderutil.WindowsPathSeparator) {
+		if err := os.MkdirAll(filepath.Dir(to), 0700); err != nil {
+			return errorutil.NewWithErr(err).Msgf("failed to create directory while writing trace")
+		}
+	}
+
+	complete := &proto.TracingTracingComplete{}
+	// subscribe before starting so that the completion event is not missed
+	wait := p.page.WaitEvent(complete)
+	if err := (proto.TracingStart{TransferMode: proto.TracingStartTransferModeReturnAsStream}).Call(p.page); err != nil {
+		return errors.Wrap(err, "could not start trace")
+	}
+	p.trace = &pageTrace{path: filePath, wait: wait, complete: complete}
+	return nil
+}
+
+// stopTrace stops the trace recording if any and writes it to the file
+func (p *Page) stopTrace() error {
+	if p.trace == nil {
+		return nil
+	}
+	trace := p.trace
+	p.trace = nil
+
+	if err := (proto.TracingEnd{}).Call(p.page); err != nil {
+		return errors.Wrap(err, "could not stop trace")
+	}
+	trace.wait()
+
+	reader := rod.NewStreamReader(p.page, trace.complete.Stream)
+	defer func() {
+		_ = (proto.IOClose{Handle: trace.complete.Stream}).Call(p.page)
+	}()
+
+	file, err := os.OpenFile(trace.path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0540)
+	if err != nil {
+		return errors.Wrap(err, "could not write trace")
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		return errors.Wrap(err, "could not write trace")
+	}
+	p.addArtifact(trace.path)
+	gologger.Info().Msgf("Trace successfully saved at %v\n", trace.path)
+	return nil
+}
+
 // InputElement executes input element actions for an element.
 func (p *Page) InputElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
 	value := p.getActionArgWithDefaultValues(act, "value")
@@ -575,16 +674,44 @@ func (p *Page) WaitEvent(act *Action, out map[string]string /*TODO review unused
 	return nil
 }
 
+// SelectFrame selects the iframe on which element actions are executed.
+//
+// The iframe is looked up on the page (or on the currently selected frame)
+// using the same selector args as the other element actions. The main page
+// is selected back when the `reset` arg is true.
+func (p *Page) SelectFrame(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	if p.getActionArgWithDefaultValues(act, "reset") == "true" {
+		p.frame = nil
+		return nil
+	}
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	frame, err := element.Frame()
+	if err != nil {
+		return errors.Wrap(err, "could not get frame for element")
+	}
+	p.frame = frame
+	return nil
+}
+
 // pageElementBy returns a page element from a variety of inputs.
 //
 // Supported values for by: r -> selector & regex, x -> xpath, js -> eval js,
 // search => query, default ("") => selector.
+//
+// Elements are looked up on the frame selected with the frame action if any,
+// css selectors can traverse open shadow roots using ">>>" (ex: "my-app >>> #login").
 func (p *Page) pageElementBy(data map[string]string) (*rod.Element, error) {
 	by, ok := data["by"]
 	if !ok {
 		by = ""
 	}
 	page := p.page
+	if p.frame != nil {
+		page = p.frame
+	}
 
 	switch by {
 	case "r", "regex":
@@ -604,10 +731,36 @@ func (p *Page) pageElementBy(data map[string]string) (*rod.Element, error) {
 		}
 		return nil, errors.New("no such element")
 	default:
-		return page.Element(data["selector"])
+		return elementPiercingShadowRoots(page, data["selector"])
 	}
 }
 
+// shadowRootSeparator separates the selectors of each shadow root to traverse
+const shadowRootSeparator = ">>>"
+
+// elementPiercingShadowRoots returns an element for a css selector traversing
+// the open shadow roots separated by ">>>" in the selector.
+func elementPiercingShadowRoots(page *rod.Page, selector string) (*rod.Element, error) {
+	if !strings.Contains(selector, shadowRootSeparator) {
+		return page.Element(selector)
+	}
+	parts := strings.Split(selector, shadowRootSeparator)
+	element, err := page.Element(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, err
+	}
+	for _, part := range parts[1:] {
+		shadowRoot, err := element.ShadowRoot()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get shadow root")
+		}
+		if element, err = shadowRoot.Element(strings.TrimSpace(part)); err != nil {
+			return nil, err
+		}
+	}
+	return element, nil
+}
+
 // DebugAction enables debug action on a page.
 func (p *Page) DebugAction(act *Action, out map[string]string /*TODO review unused parameter*/) error {
 	p.instance.browser.engine.SlowMotion(5 * time.Second)
@@ -629,6 +782,158 @@ func (p *Page) SleepAction(act *Action, out map[string]string /*TODO review unus
 	return nil
 }
 
+// SetDevice emulates a device profile on the page.
+//
+// A known device can be selected by name (ex: "iPhone X") or a custom
+// profile can be created using width, height, scale, mobile, touch and useragent args.
+func (p *Page) SetDevice(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	if name := p.getActionArgWithDefaultValues(act, "device"); name != "" {
+		device, ok := getEmulatedDevice(name)
+		if !ok {
+			return errorutil.New("unknown device %v", name)
+		}
+		if p.getActionArgWithDefaultValues(act, "landscape") == "true" {
+			device = device.Landscape()
+		}
+		if err := p.page.Emulate(device); err != nil {
+			return errors.Wrap(err, "could not emulate device")
+		}
+		return nil
+	}
+
+	width, err := strconv.Atoi(p.getActionArgWithDefaultValues(act, "width"))
+	if err != nil {
+		return errors.Wrap(err, "could not parse width")
+	}
+	height, err := strconv.Atoi(p.getActionArgWithDefaultValues(act, "height"))
+	if err != nil {
+		return errors.Wrap(err, "could not parse height")
+	}
+	scale := 1.0
+	if value := p.getActionArgWithDefaultValues(act, "scale"); value != "" {
+		if scale, err = strconv.ParseFloat(value, 64); err != nil {
+			return errors.Wrap(err, "could not parse scale")
+		}
+	}
+	mobile := p.getActionArgWithDefaultValues(act, "mobile") == "true"
+	if err := p.page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+		Width:             width,
+		Height:            height,
+		DeviceScaleFactor: scale,
+		Mobile:            mobile,
+	}); err != nil {
+		return errors.Wrap(err, "could not set viewport")
+	}
+	if p.getActionArgWithDefaultValues(act, "touch") == "true" {
+		maxTouchPoints := 5
+		if err := (proto.EmulationSetTouchEmulationEnabled{Enabled: true, MaxTouchPoints: &maxTouchPoints}).Call(p.page); err != nil {
+			return errors.Wrap(err, "could not enable touch emulation")
+		}
+	}
+	if userAgent := p.getActionArgWithDefaultValues(act, "useragent"); userAgent != "" {
+		if err := p.page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: userAgent}); err != nil {
+			return errors.Wrap(err, "could not set user agent")
+		}
+	}
+	return nil
+}
+
+// SetGeolocation overrides the geolocation reported by the page.
+func (p *Page) SetGeolocation(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	latitude, err := strconv.ParseFloat(p.getActionArgWithDefaultValues(act, "latitude"), 64)
+	if err != nil {
+		return errors.Wrap(err, "could not parse latitude")
+	}
+	longitude, err := strconv.ParseFloat(p.getActionArgWithDefaultValues(act, "longitude"), 64)
+	if err != nil {
+		return errors.Wrap(err, "could not parse longitude")
+	}
+	accuracy := 1.0
+	if value := p.getActionArgWithDefaultValues(act, "accuracy"); value != "" {
+		if accuracy, err = strconv.ParseFloat(value, 64); err != nil {
+			return errors.Wrap(err, "could not parse accuracy")
+		}
+	}
+	// the geolocation api is only available to the page after the permission is granted
+	if err := (proto.BrowserGrantPermissions{
+		Permissions:      []proto.BrowserPermissionType{proto.BrowserPermissionTypeGeolocation},
+		BrowserContextID: p.instance.engine.BrowserContextID,
+	}).Call(p.instance.engine); err != nil {
+		return errors.Wrap(err, "could not grant geolocation permission")
+	}
+	if err := (proto.EmulationSetGeolocationOverride{
+		Latitude:  &latitude,
+		Longitude: &longitude,
+		Accuracy:  &accuracy,
+	}).Call(p.page); err != nil {
+		return errors.Wrap(err, "could not set geolocation")
+	}
+	return nil
+}
+
+// SetLocale overrides the locale, accept-language and timezone of the page.
+func (p *Page) SetLocale(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	locale := p.getActionArgWithDefaultValues(act, "locale")
+	timezone := p.getActionArgWithDefaultValues(act, "timezone")
+	if locale == "" && timezone == "" {
+		return errinvalidArguments
+	}
+	if locale != "" {
+		if err := (proto.EmulationSetLocaleOverride{Locale: locale}).Call(p.page); err != nil {
+			return errors.Wrap(err, "could not set locale")
+		}
+		if err := p.setExtraHeaders("Accept-Language", strings.ReplaceAll(locale, "_", "-")); err != nil {
+			return errors.Wrap(err, "could not set accept-language header")
+		}
+	}
+	if timezone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{TimezoneID: timezone}).Call(p.page); err != nil {
+			return errors.Wrap(err, "could not set timezone")
+		}
+	}
+	return nil
+}
+
+// Throttle emulates network conditions for the page.
+//
+// A preset (offline, slow3g, fast3g, 4g, none) can be used or custom
+// conditions can be specified with latency (ms), download and upload (bytes/sec) args.
+func (p *Page) Throttle(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	var conditions proto.NetworkEmulateNetworkConditions
+	if preset := p.getActionArgWithDefaultValues(act, "preset"); preset != "" {
+		value, ok := networkConditionPresets[normalizeValue(preset)]
+		if !ok {
+			return errorutil.New("unknown network preset %v", preset)
+		}
+		conditions = value
+	} else {
+		conditions = proto.NetworkEmulateNetworkConditions{DownloadThroughput: -1, UploadThroughput: -1}
+		for arg, value := range map[string]*float64{
+			"latency":  &conditions.Latency,
+			"download": &conditions.DownloadThroughput,
+			"upload":   &conditions.UploadThroughput,
+		} {
+			argValue := p.getActionArgWithDefaultValues(act, arg)
+			if argValue == "" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(argValue, 64)
+			if err != nil {
+				return errors.Wrapf(err, "could not parse %s", arg)
+			}
+			*value = parsed
+		}
+	}
+	// network conditions are only applied when the network domain is enabled
+	if err := (proto.NetworkEnable{}).Call(p.page); err != nil {
+		return errors.Wrap(err, "could not enable network domain")
+	}
+	if err := conditions.Call(p.page); err != nil {
+		return errors.Wrap(err, "could not emulate network conditions")
+	}
+	return nil
+}
+
 // selectorBy returns a selector from a representation.
 func selectorBy(selector string) rod.SelectorType {
 	switch selector {
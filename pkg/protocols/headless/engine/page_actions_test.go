@@ -209,6 +209,33 @@ func TestActionScreenshot(t *testing.T) {
 		require.Equal(t, "Nuclei Test Page", page.Page().MustInfo().Title, "could not navigate correctly")
 		_ = page.Page()
 		require.FileExists(t, filePath, "could not find screenshot file %v", filePath)
+		require.Equal(t, []string{filePath}, page.Artifacts, "could not find screenshot in page artifacts")
+		if err := os.RemoveAll(filePath); err != nil {
+			t.Logf("got error %v while deleting temp file", err)
+		}
+	})
+}
+
+func TestActionTrace(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>Nuclei Test Page</body>
+		</html>`
+
+	filePath := filepath.Join(os.TempDir(), "trace-"+strconv.Itoa(rand.Intn(1000))+".json")
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionTrace}, Data: map[string]string{"to": filePath}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.FileExists(t, filePath, "could not find trace file %v", filePath)
+		require.Equal(t, []string{filePath}, page.Artifacts, "could not find trace in page artifacts")
 		if err := os.RemoveAll(filePath); err != nil {
 			t.Logf("got error %v while deleting temp file", err)
 		}
@@ -568,6 +595,141 @@ func TestActionWaitVisible(t *testing.T) {
 	})
 }
 
+func TestActionSetDevice(t *testing.T) {
+	response := `
+		<html>
+		<head>
+			<title>Nuclei Test Page</title>
+		</head>
+		<body>Nuclei Test Page</body>
+	</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionSetDevice}, Data: map[string]string{"device": "iPhone X"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Name: "ua", Data: map[string]string{"code": "() => navigator.userAgent"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Name: "width", Data: map[string]string{"code": "() => window.innerWidth"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Contains(t, out["ua"], "iPhone", "could not emulate device user agent")
+		require.Equal(t, "375", out["width"], "could not emulate device viewport")
+	})
+}
+
+func TestActionSetLocale(t *testing.T) {
+	response := `
+		<html>
+		<head>
+			<title>Nuclei Test Page</title>
+		</head>
+		<body>Nuclei Test Page</body>
+	</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionSetLocale}, Data: map[string]string{"locale": "de_DE", "timezone": "Europe/Berlin"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Name: "timezone", Data: map[string]string{"code": "() => Intl.DateTimeFormat().resolvedOptions().timeZone"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "Europe/Berlin", out["timezone"], "could not emulate timezone")
+	})
+}
+
+func TestActionShadowRootSelector(t *testing.T) {
+	response := `
+		<html>
+		<head>
+			<title>Nuclei Test Page</title>
+		</head>
+		<body>
+			<div id="host"></div>
+			<script>
+				const root = document.getElementById('host').attachShadow({mode: 'open'});
+				root.innerHTML = '<span class="secret">Nuclei Shadow</span>';
+			</script>
+		</body>
+	</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionExtract}, Name: "extract", Data: map[string]string{"selector": "#host >>> .secret"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "Nuclei Shadow", out["extract"], "could not extract element in shadow root")
+	})
+}
+
+func TestActionFrame(t *testing.T) {
+	response := `
+		<html>
+		<head>
+			<title>Nuclei Test Page</title>
+		</head>
+		<body>
+			<h1>Nuclei Main</h1>
+			<iframe id="frame" srcdoc="<h1>Nuclei Frame</h1>"></iframe>
+		</body>
+	</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionFrame}, Data: map[string]string{"selector": "#frame"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionExtract}, Name: "frame", Data: map[string]string{"selector": "h1"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionFrame}, Data: map[string]string{"reset": "true"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionExtract}, Name: "main", Data: map[string]string{"selector": "h1"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "Nuclei Frame", out["frame"], "could not extract element in frame")
+		require.Equal(t, "Nuclei Main", out["main"], "could not extract element in main page")
+	})
+}
+
+func TestPagePerformance(t *testing.T) {
+	response := `
+		<html>
+		<head>
+			<title>Nuclei Test Page</title>
+		</head>
+		<body>
+			<script>console.error('nuclei console error');</script>
+		</body>
+	</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.NotNil(t, page.Performance, "could not collect page performance")
+		require.Greater(t, page.Performance.LoadTime, float64(0), "could not get load time")
+		require.Contains(t, page.Performance.ConsoleErrors, "nuclei console error", "could not get console errors")
+	})
+}
+
+func TestGetEmulatedDevice(t *testing.T) {
+	for _, name := range []string{"iPhone X", "iphonex", "IPHONE-X"} {
+		device, ok := getEmulatedDevice(name)
+		require.True(t, ok, "could not get device %s", name)
+		require.Equal(t, "iPhone X", device.Title)
+	}
+	_, ok := getEmulatedDevice("not-a-device")
+	require.False(t, ok)
+}
+
 func testHeadlessSimpleResponse(t *testing.T, response string, actions []*Action, timeout time.Duration, assert func(page *Page, pageErr error, out map[string]string)) {
 	t.Helper()
 	testHeadless(t, actions, timeout, func(w http.ResponseWriter, r *http.Request) {
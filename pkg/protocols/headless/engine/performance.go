@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// performanceScript collects navigation timing and resource data of the page
+// using the performance api. all timings are in milliseconds.
+const performanceScript = `() => {
+	const navigation = performance.getEntriesByType('navigation')[0] || {};
+	const resources = performance.getEntriesByType('resource');
+	let thirdParty = 0, scripts = 0, transferSize = navigation.transferSize || 0;
+	for (const resource of resources) {
+		try {
+			if (new URL(resource.name).host !== location.host) {
+				thirdParty++;
+			}
+		} catch (e) {}
+		if (resource.initiatorType === 'script') {
+			scripts++;
+		}
+		transferSize += resource.transferSize || 0;
+	}
+	return {
+		ttfb: navigation.responseStart || 0,
+		dom_content_loaded: navigation.domContentLoadedEventEnd || 0,
+		load_time: navigation.loadEventEnd || 0,
+		navigation_duration: navigation.duration || 0,
+		resource_count: resources.length,
+		third_party_resource_count: thirdParty,
+		script_resource_count: scripts,
+		transfer_size: transferSize,
+	};
+}`
+
+// Performance contains the timing and runtime data collected for a page
+type Performance struct {
+	// TTFB is the time to first byte of the main document
+	TTFB float64 `json:"ttfb"`
+	// DOMContentLoaded is the time at which the DOMContentLoaded event completed
+	DOMContentLoaded float64 `json:"dom_content_loaded"`
+	// LoadTime is the time at which the load event completed
+	LoadTime float64 `json:"load_time"`
+	// Duration is the total duration of the navigation
+	Duration float64 `json:"navigation_duration"`
+	// ResourceCount is the number of resources loaded by the page
+	ResourceCount int `json:"resource_count"`
+	// ThirdPartyResourceCount is the number of resources loaded from other hosts
+	ThirdPartyResourceCount int `json:"third_party_resource_count"`
+	// ScriptResourceCount is the number of scripts loaded by the page
+	ScriptResourceCount int `json:"script_resource_count"`
+	// TransferSize is the total size in bytes transferred for the page and its resources
+	TransferSize int `json:"transfer_size"`
+	// ConsoleErrors contains the console errors and uncaught exceptions of the page
+	ConsoleErrors []string `json:"console_errors"`
+}
+
+// ToMap returns the performance data as a map to be used by matchers and extractors
+func (perf *Performance) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"ttfb":                       perf.TTFB,
+		"dom_content_loaded":         perf.DOMContentLoaded,
+		"load_time":                  perf.LoadTime,
+		"navigation_duration":        perf.Duration,
+		"resource_count":             perf.ResourceCount,
+		"third_party_resource_count": perf.ThirdPartyResourceCount,
+		"script_resource_count":      perf.ScriptResourceCount,
+		"transfer_size":              perf.TransferSize,
+		"console_errors":             strings.Join(perf.ConsoleErrors, "\n"),
+		"console_error_count":        len(perf.ConsoleErrors),
+	}
+}
+
+// watchConsoleErrors records the console errors and uncaught exceptions of the page
+func (p *Page) watchConsoleErrors() {
+	wait := p.page.EachEvent(func(e *proto.RuntimeConsoleAPICalled) {
+		if e.Type != proto.RuntimeConsoleAPICalledTypeError {
+			return
+		}
+		var args []string
+		for _, arg := range e.Args {
+			if arg.Value.Nil() {
+				args = append(args, arg.Description)
+			} else {
+				args = append(args, arg.Value.String())
+			}
+		}
+		p.addConsoleError(strings.Join(args, " "))
+	}, func(e *proto.RuntimeExceptionThrown) {
+		if e.ExceptionDetails == nil {
+			return
+		}
+		message := e.ExceptionDetails.Text
+		if e.ExceptionDetails.Exception != nil && e.ExceptionDetails.Exception.Description != "" {
+			message = e.ExceptionDetails.Exception.Description
+		}
+		p.addConsoleError(message)
+	})
+	go wait()
+}
+
+func (p *Page) addConsoleError(message string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.consoleErrors = append(p.consoleErrors, message)
+}
+
+// collectPerformance collects the performance data of the page
+func (p *Page) collectPerformance() *Performance {
+	perf := &Performance{}
+	if result, err := p.page.Eval(performanceScript); err == nil {
+		_ = result.Value.Unmarshal(perf)
+	}
+
+	p.mutex.RLock()
+	perf.ConsoleErrors = append(perf.ConsoleErrors, p.consoleErrors...)
+	p.mutex.RUnlock()
+	return perf
+}
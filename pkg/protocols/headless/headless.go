@@ -30,6 +30,10 @@ type Request struct {
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Files can also be https urls fetched once per scan, an expected sha256
+	//   checksum can be set with a #sha256=hex url fragment. The urls are
+	//   only fetched with the -allow-remote-payloads option.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the headless request,description=Payloads contains any payloads for the current request"`
 
 	// description: |
@@ -2,7 +2,9 @@ package headless
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -44,7 +46,9 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 		if err != nil {
 			return err
 		}
-		input = contextargs.NewWithInput(url)
+		selfContainedInput := contextargs.NewWithInput(url)
+		selfContainedInput.SetContext(input.Context())
+		input = selfContainedInput
 	}
 
 	if request.options.Browser.UserAgent() == "" {
@@ -131,7 +135,7 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 	}
 	options := &engine.Options{
 		Timeout:     time.Duration(request.options.Options.PageTimeout) * time.Second,
-		CookieReuse: request.CookieReuse,
+		CookieReuse: request.CookieReuse || input.CookieReuse,
 		Options:     request.options.Options,
 	}
 
@@ -139,7 +143,15 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 		return errors.New("cookie-reuse set but cookie-jar is nil")
 	}
 
+	if request.options.HostRateLimiter != nil {
+		if err := request.options.HostRateLimiter.Take(input.Context(), input.MetaInput.Input); err != nil {
+			return err
+		}
+	}
 	out, page, err := instance.Run(input, request.Steps, payloads, options)
+	if request.options.HostRateLimiter != nil {
+		observeHostRate(request.options, input.MetaInput.Input, out, page, err)
+	}
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, input.MetaInput.Input, request.Type().String(), err)
 		request.options.Progress.IncrementFailedRequestsBy(1)
@@ -150,7 +162,14 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 	reqLog := instance.GetRequestLog()
 	navigatedURL := request.getLastNavigationURLWithLog(reqLog) // also known as matchedURL if there is a match
 
-	request.options.Output.Request(request.options.TemplatePath, input.MetaInput.Input, request.Type().String(), nil)
+	sent, received := page.TransferredBytes()
+	request.options.Output.LogRequest(&output.RequestEvent{
+		Template:      request.options.TemplatePath,
+		Input:         input.MetaInput.Input,
+		Type:          request.Type().String(),
+		BytesSent:     sent,
+		BytesReceived: received,
+	})
 	request.options.Progress.IncrementRequests()
 	gologger.Verbose().Msgf("Sent Headless request to %s", navigatedURL)
 
@@ -184,12 +203,20 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 	// add response fields to template context and merge templatectx variables to output event
 	request.options.AddTemplateVars(input.MetaInput, request.Type(), request.ID, outputEvent)
 	outputEvent = generators.MergeMaps(outputEvent, request.options.GetTemplateCtx(input.MetaInput).GetAll())
+	if page.Performance != nil {
+		for k, v := range page.Performance.ToMap() {
+			outputEvent[k] = v
+		}
+	}
 	for k, v := range out {
 		outputEvent[k] = v
 	}
 	for k, v := range payloads {
 		outputEvent[k] = v
 	}
+	if len(page.Artifacts) > 0 {
+		outputEvent["artifacts"] = page.Artifacts
+	}
 
 	var event *output.InternalWrappedEvent
 	if len(page.InteractshURLs) == 0 {
@@ -213,6 +240,21 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 	return nil
 }
 
+// observeHostRate records the time to first byte and the status code of the
+// main document of the page in the adaptive host rate limiter
+func observeHostRate(options *protocols.ExecutorOptions, input string, out map[string]string, page *engine.Page, err error) {
+	if err != nil {
+		options.HostRateLimiter.Observe(input, 0, nil, err)
+		return
+	}
+	if page == nil || page.Performance == nil {
+		return
+	}
+	resp := &http.Response{Header: http.Header{}}
+	resp.StatusCode, _ = strconv.Atoi(out["status_code"])
+	options.HostRateLimiter.Observe(input, time.Duration(page.Performance.TTFB*float64(time.Millisecond)), resp, nil)
+}
+
 func dumpResponse(event *output.InternalWrappedEvent, requestOptions *protocols.ExecutorOptions, responseBody string, input string) {
 	cliOptions := requestOptions.Options
 	if cliOptions.Debug || cliOptions.DebugResponse {
@@ -47,6 +47,10 @@ type generatedRequest struct {
 	dynamicValues        map[string]interface{}
 	interactshURLs       []string
 	customCancelFunction context.CancelFunc
+	// singlePacket is the response received with the single-packet race attack
+	singlePacket *race.Response
+	// socket is the unix socket or named pipe of the input the request is sent to
+	socket *httputil.Socket
 }
 
 func (g *generatedRequest) URL() string {
@@ -93,8 +97,16 @@ func (r *requestGenerator) Make(ctx context.Context, input *contextargs.Context,
 		}
 	}
 
-	// Parse target url
-	parsed, err := urlutil.Parse(input.MetaInput.Input)
+	// Parse target url, the requests to the unix sockets and named pipes are built for their base url
+	target := input.MetaInput.Input
+	socket, isSocket := httputil.ParseSocket(target)
+	if isSocket {
+		if r.request.Unsafe || r.request.Pipeline {
+			return nil, errorutil.NewWithTag("http", "unsafe and pipelined requests can not be sent to socket %v", target)
+		}
+		target = httputil.SocketBaseURL
+	}
+	parsed, err := urlutil.Parse(target)
 	if err != nil {
 		return nil, err
 	}
@@ -146,19 +158,24 @@ func (r *requestGenerator) Make(ctx context.Context, input *contextargs.Context,
 		return nil, ErrEvalExpression.Wrap(err).WithTag("http")
 	}
 
+	var generated *generatedRequest
 	if isRawRequest {
-		return r.generateRawRequest(ctx, reqData, parsed, finalVars, payloads)
+		generated, err = r.generateRawRequest(ctx, reqData, parsed, finalVars, payloads)
+	} else {
+		reqURL, parseErr := urlutil.ParseURL(reqData, true)
+		if parseErr != nil {
+			return nil, errorutil.NewWithTag("http", "failed to parse url %v while creating http request", reqData)
+		}
+		// while merging parameters first preference is given to target params
+		finalparams := parsed.Params
+		finalparams.Merge(reqURL.Params.Encode())
+		reqURL.Params = finalparams
+		generated, err = r.generateHttpRequest(ctx, reqURL, finalVars, payloads)
 	}
-
-	reqURL, err := urlutil.ParseURL(reqData, true)
-	if err != nil {
-		return nil, errorutil.NewWithTag("http", "failed to parse url %v while creating http request", reqData)
+	if generated != nil {
+		generated.socket = socket
 	}
-	// while merging parameters first preference is given to target params
-	finalparams := parsed.Params
-	finalparams.Merge(reqURL.Params.Encode())
-	reqURL.Params = finalparams
-	return r.generateHttpRequest(ctx, reqURL, finalVars, payloads)
+	return generated, err
 }
 
 // selfContained templates do not need/use target data and all values i.e {{Hostname}} , {{BaseURL}} etc are already available
@@ -282,6 +299,13 @@ func (r *requestGenerator) generateRawRequest(ctx context.Context, rawRequest st
 		if len(r.options.Options.CustomHeaders) > 0 {
 			_ = rawRequestData.TryFillCustomHeaders(r.options.Options.CustomHeaders)
 		}
+		if r.request.Smuggling != nil {
+			smuggled, err := expressions.Evaluate(r.request.Smuggling.Smuggled, finalVars)
+			if err != nil {
+				return nil, errorutil.NewWithErr(err).Msgf("failed to evaluate smuggled data")
+			}
+			rawRequestData.UnsafeRawBytes = r.request.Smuggling.Build(rawRequestData.UnsafeRawBytes, smuggled)
+		}
 		if rawRequestData.Data != "" && !stringsutil.EqualFoldAny(rawRequestData.Method, http.MethodHead, http.MethodGet) && rawRequestData.Headers["Transfer-Encoding"] != "chunked" {
 			rawRequestData.Headers["Content-Length"] = strconv.Itoa(len(rawRequestData.Data))
 		}
@@ -362,7 +386,7 @@ func (r *requestGenerator) fillRequest(req *retryablehttp.Request, values map[st
 	}
 
 	// Check if the user requested a request body
-	if r.request.Body != "" {
+	if r.request.Body != "" || r.request.Protobuf != nil {
 		body := r.request.Body
 		if r.options.Interactsh != nil {
 			body, r.interactshURLs = r.options.Interactsh.Replace(r.request.Body, r.interactshURLs)
@@ -371,12 +395,42 @@ func (r *requestGenerator) fillRequest(req *retryablehttp.Request, values map[st
 		if err != nil {
 			return nil, ErrEvalExpression.Wrap(err)
 		}
-		bodyReader, err := readerutil.NewReusableReadCloser([]byte(body))
+		data := []byte(body)
+		// protobuf bodies are encoded from their evaluated json
+		if r.request.Protobuf != nil {
+			if data, err = r.request.Protobuf.Encode(body); err != nil {
+				return nil, errors.Wrap(err, "failed to encode protobuf body")
+			}
+			req.ContentLength = int64(len(data))
+			httputil.SetHeader(req, "Content-Type", r.request.Protobuf.ContentType())
+			if r.request.Protobuf.GRPCWeb {
+				httputil.SetHeader(req, "X-Grpc-Web", "1")
+			}
+		}
+		bodyReader, err := readerutil.NewReusableReadCloser(data)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create reusable reader for request body")
 		}
 		req.Body = bodyReader
 	}
+	if r.request.Multipart != nil {
+		body, contentType, err := r.request.Multipart.Build(func(value string) (string, error) {
+			if r.options.Interactsh != nil {
+				value, r.interactshURLs = r.options.Interactsh.Replace(value, r.interactshURLs)
+			}
+			return expressions.Evaluate(value, values)
+		})
+		if err != nil {
+			return nil, ErrEvalExpression.Wrap(err).Msgf("failed to evaluate while building multipart body")
+		}
+		bodyReader, err := readerutil.NewReusableReadCloser(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create reusable reader for multipart body")
+		}
+		req.Body = bodyReader
+		req.ContentLength = int64(len(body))
+		httputil.SetHeader(req, "Content-Type", contentType)
+	}
 	if !r.request.Unsafe {
 		httputil.SetHeader(req, "User-Agent", uarand.GetRandom())
 	}
@@ -386,6 +440,10 @@ func (r *requestGenerator) fillRequest(req *retryablehttp.Request, values map[st
 		httputil.SetHeader(req, "Accept", "*/*")
 		httputil.SetHeader(req, "Accept-Language", "en")
 	}
+	// the responses are decompressed by the normalization of the bodies
+	if r.options.Options.HTTPCompression && !r.request.Unsafe {
+		httputil.SetHeader(req, "Accept-Encoding", acceptEncoding)
+	}
 
 	if !LeaveDefaultPorts {
 		switch {
@@ -396,13 +454,5 @@ func (r *requestGenerator) fillRequest(req *retryablehttp.Request, values map[st
 		}
 	}
 
-	if r.request.DigestAuthUsername != "" {
-		req.Auth = &retryablehttp.Auth{
-			Type:     retryablehttp.DigestAuth,
-			Username: r.request.DigestAuthUsername,
-			Password: r.request.DigestAuthPassword,
-		}
-	}
-
 	return req, nil
 }
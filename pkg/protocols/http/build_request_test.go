@@ -12,6 +12,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/multipart"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
 )
 
@@ -217,3 +218,44 @@ func areUnique(elements []string) bool {
 	}
 	return true
 }
+
+func TestMakeRequestFromModelMultipart(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:         templateID,
+		Name:       "testing",
+		Path:       []string{"{{BaseURL}}/upload.php"},
+		Method:     HTTPMethodTypeHolder{MethodType: HTTPPost},
+		Payloads:   map[string]interface{}{"ext": []string{"php", "phtml"}},
+		AttackType: generators.AttackTypeHolder{Value: generators.ClusterBombAttack},
+		Multipart: &multipart.Config{
+			Boundary: "nuclei",
+			Fields:   []*multipart.Field{{Name: "file", Filename: "shell.{{ext}}", Value: "{{to_upper(ext)}}"}},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	generator := request.newGenerator(false)
+	inputData, payloads, _ := generator.nextValue()
+	req, err := generator.Make(context.Background(), contextargs.NewWithInput("https://example.com"), inputData, payloads, map[string]interface{}{})
+	require.Nil(t, err, "could not make http request")
+	require.Equal(t, "multipart/form-data; boundary=nuclei", req.request.Header.Get("Content-Type"), "could not get multipart content type")
+	bodyBytes, _ := req.request.BodyBytes()
+	require.Equal(t, "--nuclei\r\nContent-Disposition: form-data; name=\"file\"; filename=\"shell.php\"\r\nContent-Type: application/octet-stream\r\n\r\nPHP\r\n--nuclei--\r\n", string(bodyBytes), "could not get correct multipart body")
+	require.Equal(t, int64(len(bodyBytes)), req.request.ContentLength, "could not get multipart content length")
+
+	inputData, payloads, _ = generator.nextValue()
+	req, err = generator.Make(context.Background(), contextargs.NewWithInput("https://example.com"), inputData, payloads, map[string]interface{}{})
+	require.Nil(t, err, "could not make http request")
+	bodyBytes, _ = req.request.BodyBytes()
+	require.Contains(t, string(bodyBytes), `filename="shell.phtml"`, "could not evaluate payload in multipart filename")
+	require.Contains(t, string(bodyBytes), "\r\n\r\nPHTML\r\n", "could not evaluate expression in multipart value")
+}
@@ -11,13 +11,14 @@ import (
 // are similar enough to be considered one and can be checked by
 // just adding the matcher/extractors for the request and the correct IDs.
 func (request *Request) CanCluster(other *Request) bool {
-	if len(request.Payloads) > 0 || len(request.Fuzzing) > 0 || len(request.Raw) > 0 || len(request.Body) > 0 || request.Unsafe || request.NeedsRequestCondition() || request.Name != "" {
+	if len(request.Payloads) > 0 || len(request.Fuzzing) > 0 || len(request.Raw) > 0 || len(request.Body) > 0 || request.Multipart != nil || request.Protobuf != nil || request.Stream || request.Unsafe || len(request.Proxy) > 0 || request.NeedsRequestCondition() || request.Name != "" {
 		return false
 	}
 	if request.Method != other.Method ||
 		request.MaxRedirects != other.MaxRedirects ||
 		request.CookieReuse != other.CookieReuse ||
-		request.Redirects != other.Redirects {
+		request.Redirects != other.Redirects ||
+		request.authConfig().Hash() != other.authConfig().Hash() {
 		return false
 	}
 	if !sliceutil.Equal(request.Path, other.Path) {
@@ -8,6 +8,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/httpauth"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
 )
 
@@ -40,3 +41,21 @@ Accept-Encoding: gzip`},
 	require.Equal(t, 6, request.Requests(), "could not get correct number of requests")
 	require.Equal(t, map[string]string{"User-Agent": "test", "Hello": "World"}, request.customHeaders, "could not get correct custom headers")
 }
+
+func TestHTTPValidateHTTP2(t *testing.T) {
+	require.Nil(t, (&Request{HTTP2: "prior-knowledge"}).validate())
+	require.EqualError(t, (&Request{HTTP2: "h3"}).validate(), `invalid 'http2' mode "h3"`)
+	require.EqualError(t, (&Request{HTTP2: "h2c", Unsafe: true}).validate(), "'http2' can't be used with 'unsafe' or 'pipeline'")
+}
+
+func TestHTTPValidateAuth(t *testing.T) {
+	require.Nil(t, (&Request{Auth: &httpauth.Config{Type: "ntlm", Username: `CORP\admin`}}).validate())
+	require.ErrorContains(t, (&Request{Auth: &httpauth.Config{Type: "kerberos", Username: "admin"}}).validate(), "invalid 'auth'")
+	require.EqualError(t, (&Request{HTTP2: "h2c", Auth: &httpauth.Config{Type: "ntlm", Username: "admin"}}).validate(), "'auth' can't be used with 'http2', 'unsafe' or 'pipeline'")
+}
+
+func TestHTTPValidateProxy(t *testing.T) {
+	require.Nil(t, (&Request{Proxy: []string{"http://127.0.0.1:8080", "socks5://127.0.0.1:1080"}}).validate())
+	require.ErrorContains(t, (&Request{Proxy: []string{"ftp://127.0.0.1:21"}}).validate(), "invalid 'proxy'")
+	require.EqualError(t, (&Request{Proxy: []string{"http://127.0.0.1:8080"}, Unsafe: true}).validate(), "'proxy' can't be used with 'unsafe', 'pipeline' or 'race-mode'")
+}
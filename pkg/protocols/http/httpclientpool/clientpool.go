@@ -18,8 +18,14 @@ import (
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/fastdialer/fastdialer/ja3/impersonate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/httpauth"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/proxychain"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/proxypool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/tlsconfig"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
+	httputil "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils/http"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types/scanstrategy"
 	"github.com/projectdiscovery/rawhttp"
@@ -101,6 +107,18 @@ type Configuration struct {
 	RedirectFlow RedirectFlow
 	// Connection defines custom connection configuration
 	Connection *ConnectionConfiguration
+	// TLS defines custom tls client configuration
+	TLS *tlsconfig.Config
+	// HTTP2 is the cleartext HTTP/2 mode of the http urls (h2c or prior-knowledge)
+	HTTP2 string
+	// Auth defines the connection based or digest authentication of the requests
+	Auth *httpauth.Config
+	// ConnectionPool is the keep-alive pool of the template with the template connections reuse
+	ConnectionPool string
+	// Socket is the unix domain socket or windows named pipe the requests are sent to
+	Socket *httputil.Socket
+	// Proxy is the chain of proxies of the requests overriding the proxies of the scan
+	Proxy []string
 }
 
 // Hash returns the hash of the configuration to allow client pooling
@@ -119,13 +137,27 @@ func (c *Configuration) Hash() string {
 	builder.WriteString(strconv.FormatBool(c.CookieReuse))
 	builder.WriteString("c")
 	builder.WriteString(strconv.FormatBool(c.Connection != nil))
+	builder.WriteString("s")
+	builder.WriteString(c.TLS.Hash())
+	builder.WriteString("h")
+	builder.WriteString(c.HTTP2)
+	builder.WriteString("a")
+	builder.WriteString(c.Auth.Hash())
+	builder.WriteString("p")
+	builder.WriteString(c.ConnectionPool)
+	if c.Socket != nil {
+		builder.WriteString("u")
+		builder.WriteString(c.Socket.Network + ":" + c.Socket.Path)
+	}
+	builder.WriteString("x")
+	builder.WriteString(strings.Join(c.Proxy, ","))
 	hash := builder.String()
 	return hash
 }
 
 // HasStandardOptions checks whether the configuration requires custom settings
 func (c *Configuration) HasStandardOptions() bool {
-	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && !c.CookieReuse && c.Connection == nil && !c.NoTimeout
+	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && !c.CookieReuse && c.Connection == nil && !c.NoTimeout && c.TLS.IsEmpty() && c.HTTP2 == "" && c.Auth.IsEmpty() && c.ConnectionPool == "" && c.Socket == nil && len(c.Proxy) == 0
 }
 
 // GetRawHTTP returns the rawhttp request client
@@ -208,6 +240,18 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 		disableKeepAlives = configuration.Connection.DisableKeepAlive
 	}
 
+	// keep the connections alive to reuse them across the requests
+	var idleConnTimeout time.Duration
+	if options.HTTPConnectionReuse != "" {
+		retryableHttpOptions = retryablehttp.DefaultOptionsSingle
+		retryableHttpOptions.RetryWaitMax = 10 * time.Second
+		retryableHttpOptions.RetryMax = options.Retries
+		disableKeepAlives = false
+		maxConnsPerHost = maxHostConnections(options.HTTPMaxHostConnections)
+		maxIdleConnsPerHost = maxConnsPerHost
+		idleConnTimeout = reuseIdleTimeout
+	}
+
 	// Set the base TLS configuration definition
 	tlsConfig := &tls.Config{
 		Renegotiation:      tls.RenegotiateOnceAsClient,
@@ -225,26 +269,72 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 		return nil, errors.Wrap(err, "could not create client certificate")
 	}
 
+	// Apply the template and global custom tls parameters, the client
+	// certificate of the template takes precedence over the global one
+	customTLS := configuration.TLS.Merge(options.TLSConfig())
+	if err := customTLS.Apply(tlsConfig); err != nil {
+		return nil, errors.Wrap(err, "could not apply tls configuration")
+	}
+	fips.RestrictTLSConfig(tlsConfig)
+
 	transport := &http.Transport{
 		ForceAttemptHTTP2: options.ForceAttemptHTTP2,
-		DialContext:       Dialer.Dial,
-		DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+		DialContext:       protocolstate.WithIPVersion(Dialer.Dial),
+		DialTLSContext: protocolstate.WithIPVersion(func(ctx context.Context, network, addr string) (net.Conn, error) {
 			if options.TlsImpersonate {
 				return Dialer.DialTLSWithConfigImpersonate(ctx, network, addr, tlsConfig, impersonate.Random, nil)
 			}
-			if options.HasClientCertificates() || options.ForceAttemptHTTP2 {
+			if options.HasClientCertificates() || options.ForceAttemptHTTP2 || !customTLS.IsEmpty() || fips.IsEnabled() {
 				return Dialer.DialTLSWithConfig(ctx, network, addr, tlsConfig)
 			}
 			return Dialer.DialTLS(ctx, network, addr)
-		},
+		}),
 		MaxIdleConns:        maxIdleConns,
 		MaxIdleConnsPerHost: maxIdleConnsPerHost,
 		MaxConnsPerHost:     maxConnsPerHost,
 		TLSClientConfig:     tlsConfig,
 		DisableKeepAlives:   disableKeepAlives,
+		IdleConnTimeout:     idleConnTimeout,
 	}
 
-	if types.ProxyURL != "" {
+	if configuration.Socket != nil {
+		// the requests to the sockets are not proxied
+		transport.DialContext = socketDialer(configuration.Socket)
+	} else if len(configuration.Proxy) > 0 {
+		// the proxies of the requests are chained after the proxies of the scan
+		chain, err := proxychain.New(configuration.Proxy, scanProxyDialer(proxychain.DialFunc(protocolstate.WithIPVersion(Dialer.Dial))))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create proxy chain")
+		}
+		transport.DialContext = chain.DialContext
+		transport.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := chain.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			config := tlsConfig.Clone()
+			if config.ServerName == "" {
+				config.ServerName, _, _ = net.SplitHostPort(addr)
+			}
+			return tls.Client(conn, config), nil
+		}
+	} else if pool := proxypool.Default; pool != nil {
+		// rotate requests across the proxy pool
+		if pool.IsSocks() {
+			transport.DialContext = pool.DialContext
+			transport.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				conn, err := pool.DialContext(ctx, network, addr)
+				if err != nil {
+					return nil, err
+				}
+				return tls.Client(conn, tlsConfig), nil
+			}
+		} else {
+			transport.Proxy = pool.Proxy
+			transport.DialContext = pool.WrapDialer(transport.DialContext)
+			transport.DialTLSContext = pool.WrapDialer(transport.DialTLSContext)
+		}
+	} else if types.ProxyURL != "" {
 		if proxyURL, err := url.Parse(types.ProxyURL); err == nil {
 			transport.Proxy = http.ProxyURL(proxyURL)
 		}
@@ -283,8 +373,23 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 		}
 	}
 
+	var roundTripper http.RoundTripper = transport
+	if configuration.HTTP2 != "" {
+		roundTripper = newH2CTransport(configuration.HTTP2, transport)
+	} else if auth := configuration.Auth.Merge(options.HTTPAuthConfig()); !auth.IsEmpty() {
+		// authenticate with the template or the global credentials
+		switch {
+		case strings.EqualFold(auth.Type, httpauth.Kerberos):
+			roundTripper = newKerberosTransport(auth, transport)
+		case strings.EqualFold(auth.Type, httpauth.Digest):
+			roundTripper = newDigestTransport(auth, transport)
+		default:
+			roundTripper = newNTLMTransport(auth, transport)
+		}
+	}
+
 	httpclient := &http.Client{
-		Transport:     transport,
+		Transport:     roundTripper,
 		CheckRedirect: makeCheckRedirectFunc(redirectFlow, maxRedirects),
 	}
 	if !configuration.NoTimeout {
@@ -298,7 +403,12 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 
 	// Only add to client pool if we don't have a cookie jar in place.
 	if jar == nil {
-		if err := clientPool.Set(hash, client); err != nil {
+		if configuration.ConnectionPool != "" {
+			err = addTemplateClient(configuration.ConnectionPool, hash, client)
+		} else {
+			err = clientPool.Set(hash, client)
+		}
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -317,6 +427,41 @@ const defaultMaxRedirects = 10
 
 type checkRedirectFunc func(req *http.Request, via []*http.Request) error
 
+// scanProxyDialer returns the dialer connecting through the proxies of the scan, the
+// first proxy of the chains of the templates is dialed with it
+func scanProxyDialer(dial proxychain.DialFunc) proxychain.DialFunc {
+	var scanProxy func(addr string) (*url.URL, error)
+	switch pool := proxypool.Default; {
+	case pool != nil && pool.IsSocks():
+		return pool.DialContext
+	case pool != nil:
+		dial = proxychain.DialFunc(pool.WrapDialer(proxypool.DialFunc(dial)))
+		scanProxy = func(addr string) (*url.URL, error) {
+			host, _, _ := net.SplitHostPort(addr)
+			return pool.Get(host)
+		}
+	case types.ProxyURL != "" || types.ProxySocksURL != "":
+		value := types.ProxyURL
+		if value == "" {
+			value = types.ProxySocksURL
+		}
+		scanProxy = func(string) (*url.URL, error) { return url.Parse(value) }
+	default:
+		return dial
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		proxyURL, err := scanProxy(addr)
+		if err != nil {
+			return nil, err
+		}
+		chain, err := proxychain.New([]string{proxyURL.String()}, dial)
+		if err != nil {
+			return nil, err
+		}
+		return chain.DialContext(ctx, network, addr)
+	}
+}
+
 func makeCheckRedirectFunc(redirectType RedirectFlow, maxRedirects int) checkRedirectFunc {
 	return func(req *http.Request, via []*http.Request) error {
 		switch redirectType {
@@ -0,0 +1,99 @@
+package httpclientpool
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// scopes of the keep-alive connections reuse
+const (
+	// ReuseTemplate reuses the connections across the requests of a template
+	ReuseTemplate = "template"
+	// ReuseGlobal reuses the connections across the requests of all the templates
+	ReuseGlobal = "global"
+)
+
+// DefaultMaxHostConnections is the default maximum number of reused connections per host
+const DefaultMaxHostConnections = 25
+
+// reuseIdleTimeout is the time the reused connections are kept idle before being closed
+const reuseIdleTimeout = 30 * time.Second
+
+// maxTemplateConnectionPools is the maximum number of keep-alive pools of the
+// templates kept in the client pool, the clients of an evicted pool keep working
+// for the requests of their template while their idle connections are closed
+const maxTemplateConnectionPools = 100
+
+// templatePools tracks the clients of the keep-alive pools of the templates
+var templatePools = &connectionPools{clients: make(map[string][]string)}
+
+// connectionPools contains the client hashes of the keep-alive pools of the templates
+type connectionPools struct {
+	mutex sync.Mutex
+	// order contains the pools from the least recently created
+	order   []string
+	clients map[string][]string
+}
+
+// add records the client of a template pool and returns the client
+// hashes of the oldest pools above maxTemplateConnectionPools
+func (c *connectionPools) add(pool, hash string) []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.clients[pool]; !ok {
+		c.order = append(c.order, pool)
+	}
+	c.clients[pool] = append(c.clients[pool], hash)
+
+	var evicted []string
+	for len(c.order) > maxTemplateConnectionPools {
+		evicted = append(evicted, c.clients[c.order[0]]...)
+		delete(c.clients, c.order[0])
+		c.order = c.order[1:]
+	}
+	return evicted
+}
+
+// addTemplateClient adds the client of a template keep-alive pool to the client
+// pool, the clients of the oldest pools are removed and their idle connections closed
+func addTemplateClient(pool, hash string, client *retryablehttp.Client) error {
+	if err := clientPool.Set(hash, client); err != nil {
+		return err
+	}
+	for _, evicted := range templatePools.add(pool, hash) {
+		if client, ok := clientPool.Get(evicted); ok {
+			clientPool.Delete(evicted)
+			for _, httpClient := range []*http.Client{client.HTTPClient, client.HTTPClient2} {
+				if httpClient != nil {
+					httpClient.CloseIdleConnections()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// maxHostConnections returns the maximum number of reused connections per host of the options
+func maxHostConnections(value int) int {
+	if value <= 0 {
+		return DefaultMaxHostConnections
+	}
+	return value
+}
+
+// TraceConnections returns the request with a trace counting the new
+// and the reused connections of the request in the progress.
+func TraceConnections(req *retryablehttp.Request, progress progress.Progress) *retryablehttp.Request {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			progress.IncrementConnections(info.Reused)
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
@@ -0,0 +1,98 @@
+package httpclientpool
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// connectionsProgress counts the connections of the requests
+type connectionsProgress struct {
+	progress.Progress
+	connections, reused int
+}
+
+func (p *connectionsProgress) IncrementConnections(reused bool) {
+	p.connections++
+	if reused {
+		p.reused++
+	}
+}
+
+func TestConnectionReuse(t *testing.T) {
+	options := types.DefaultOptions()
+	require.Nil(t, protocolstate.Init(options))
+	require.Nil(t, Init(options))
+
+	var mu sync.Mutex
+	addresses := map[string]struct{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		addresses[r.RemoteAddr] = struct{}{}
+		mu.Unlock()
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	options.HTTPConnectionReuse = ReuseTemplate
+	options.HTTPMaxHostConnections = 2
+	configuration := func(pool string) *Configuration {
+		return &Configuration{ConnectionPool: pool, Connection: &ConnectionConfiguration{}}
+	}
+	client, err := Get(options, configuration("template-1"))
+	require.Nil(t, err)
+	other, err := Get(options, configuration("template-2"))
+	require.Nil(t, err)
+	// the templates have dedicated pools
+	require.NotSame(t, client, other)
+	transport := client.HTTPClient.Transport.(*http.Transport)
+	require.False(t, transport.DisableKeepAlives)
+	require.Equal(t, 2, transport.MaxConnsPerHost)
+
+	counter := &connectionsProgress{}
+	for i := 0; i < 3; i++ {
+		req, err := retryablehttp.NewRequest(http.MethodGet, server.URL, nil)
+		require.Nil(t, err)
+		resp, err := client.Do(TraceConnections(req, counter))
+		require.Nil(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	require.Len(t, addresses, 1)
+	require.Equal(t, 3, counter.connections)
+	require.Equal(t, 2, counter.reused)
+}
+
+func TestTemplateConnectionPoolsEviction(t *testing.T) {
+	options := types.DefaultOptions()
+	require.Nil(t, protocolstate.Init(options))
+	require.Nil(t, Init(options))
+
+	options.HTTPConnectionReuse = ReuseTemplate
+	configuration := func(pool string) *Configuration {
+		return &Configuration{ConnectionPool: pool, Connection: &ConnectionConfiguration{}}
+	}
+	first, err := Get(options, configuration("evicted-template"))
+	require.Nil(t, err)
+	for i := 0; i < maxTemplateConnectionPools; i++ {
+		_, err := Get(options, configuration(fmt.Sprintf("evicting-template-%d", i)))
+		require.Nil(t, err)
+	}
+	require.False(t, clientPool.Has(configuration("evicted-template").Hash()), "could not evict the oldest template pool")
+	require.LessOrEqual(t, len(templatePools.order), maxTemplateConnectionPools)
+
+	last, err := Get(options, configuration(fmt.Sprintf("evicting-template-%d", maxTemplateConnectionPools-1)))
+	require.Nil(t, err)
+	require.True(t, clientPool.Has(configuration(fmt.Sprintf("evicting-template-%d", maxTemplateConnectionPools-1)).Hash()))
+	require.NotSame(t, first, last)
+}
@@ -0,0 +1,300 @@
+package httpclientpool
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/httpauth"
+)
+
+// digestAlgorithms are the supported digest algorithms (RFC 7616) by order of preference
+var digestAlgorithms = []struct {
+	name string
+	hash func() hash.Hash
+}{
+	{name: "SHA-512-256", hash: sha512.New512_256},
+	{name: "SHA-256", hash: sha256.New},
+	{name: "MD5", hash: md5.New},
+}
+
+// digestCnonce returns the client nonce of the digest authorizations
+var digestCnonce = func() string {
+	data := make([]byte, 16)
+	_, _ = rand.Read(data)
+	return hex.EncodeToString(data)
+}
+
+// digestTransport authenticates the requests with the Digest scheme.
+//
+// The challenge of a host is answered for the next requests to the host with
+// an incremented nonce count, a new challenge is answered once the nonce expired.
+type digestTransport struct {
+	config *httpauth.Config
+	base   http.RoundTripper
+
+	mu         sync.Mutex
+	challenges map[string]*digestChallenge
+}
+
+// newDigestTransport returns a transport authenticating the requests with the config
+func newDigestTransport(config *httpauth.Config, base http.RoundTripper) http.RoundTripper {
+	return &digestTransport{config: config, base: base, challenges: make(map[string]*digestChallenge)}
+}
+
+// RoundTrip sends the request with the authorization of the last challenge of the host
+// and answers the new challenges of the server
+func (t *digestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	t.mu.Lock()
+	challenge := t.challenges[req.URL.Host]
+	t.mu.Unlock()
+
+	clone := withBody(req.Clone(req.Context()), body)
+	if challenge != nil {
+		clone.Header.Set("Authorization", challenge.authorization(t.config.Username, t.config.Password, req.Method, req.URL.RequestURI(), body))
+	}
+	resp, err := t.base.RoundTrip(clone)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge = digestChallengeFromResponse(resp)
+	if challenge == nil {
+		// authentication not requested or refused by the server
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	t.mu.Lock()
+	t.challenges[req.URL.Host] = challenge
+	t.mu.Unlock()
+
+	clone = withBody(req.Clone(req.Context()), body)
+	clone.Header.Set("Authorization", challenge.authorization(t.config.Username, t.config.Password, req.Method, req.URL.RequestURI(), body))
+	return t.base.RoundTrip(clone)
+}
+
+// withBody sets the body of the request
+func withBody(req *http.Request, body []byte) *http.Request {
+	if body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	return req
+}
+
+// digestChallenge is a Digest challenge of the server
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	userhash  bool
+	session   bool
+	hash      func() hash.Hash
+
+	count uint32
+}
+
+// digestChallengeFromResponse returns the challenge of the strongest supported algorithm sent by the server
+func digestChallengeFromResponse(resp *http.Response) *digestChallenge {
+	var selected *digestChallenge
+	selectedRank := len(digestAlgorithms)
+	for _, params := range parseDigestChallenges(resp.Header.Values("Www-Authenticate")) {
+		challenge := &digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+			userhash:  strings.EqualFold(params["userhash"], "true"),
+		}
+		if challenge.nonce == "" {
+			continue
+		}
+		if qop, ok := params["qop"]; ok {
+			// auth is preferred to auth-int, the challenges without a supported qop are ignored
+			for _, value := range []string{"auth", "auth-int"} {
+				if listContains(qop, value) {
+					challenge.qop = value
+					break
+				}
+			}
+			if challenge.qop == "" {
+				continue
+			}
+		}
+		algorithm := strings.ToUpper(challenge.algorithm)
+		if algorithm == "" {
+			algorithm = "MD5"
+		}
+		if strings.HasSuffix(algorithm, "-SESS") {
+			algorithm = strings.TrimSuffix(algorithm, "-SESS")
+			challenge.session = true
+		}
+		for rank, supported := range digestAlgorithms {
+			if supported.name == algorithm && rank < selectedRank {
+				challenge.hash = supported.hash
+				selected, selectedRank = challenge, rank
+			}
+		}
+	}
+	return selected
+}
+
+// authorization returns the Authorization header answering the challenge
+func (c *digestChallenge) authorization(username, password, method, uri string, body []byte) string {
+	digest := func(value string) string {
+		h := c.hash()
+		h.Write([]byte(value))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+	cnonce := digestCnonce()
+	nc := fmt.Sprintf("%08x", atomic.AddUint32(&c.count, 1))
+
+	ha1 := digest(username + ":" + c.realm + ":" + password)
+	if c.session {
+		ha1 = digest(ha1 + ":" + c.nonce + ":" + cnonce)
+	}
+	a2 := method + ":" + uri
+	if c.qop == "auth-int" {
+		a2 += ":" + digest(string(body))
+	}
+	ha2 := digest(a2)
+
+	var response string
+	if c.qop == "" {
+		// RFC 2069 compatibility
+		response = digest(ha1 + ":" + c.nonce + ":" + ha2)
+	} else {
+		response = digest(strings.Join([]string{ha1, c.nonce, nc, cnonce, c.qop, ha2}, ":"))
+	}
+	if c.userhash {
+		username = digest(username + ":" + c.realm)
+	}
+
+	fields := []string{
+		"username=" + quote(username),
+		"realm=" + quote(c.realm),
+		"nonce=" + quote(c.nonce),
+		"uri=" + quote(uri),
+	}
+	if c.algorithm != "" {
+		fields = append(fields, "algorithm="+c.algorithm)
+	}
+	fields = append(fields, "response="+quote(response))
+	if c.opaque != "" {
+		fields = append(fields, "opaque="+quote(c.opaque))
+	}
+	if c.qop != "" {
+		fields = append(fields, "qop="+c.qop, "nc="+nc, "cnonce="+quote(cnonce))
+	}
+	if c.userhash {
+		fields = append(fields, "userhash=true")
+	}
+	return "Digest " + strings.Join(fields, ", ")
+}
+
+// quote returns the quoted-string of the value
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// listContains returns true if the comma separated list contains the value
+func listContains(list, value string) bool {
+	for _, item := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDigestChallenges returns the parameters of the Digest challenges of the
+// WWW-Authenticate headers, a header can contain the challenges of several schemes
+func parseDigestChallenges(values []string) []map[string]string {
+	var challenges []map[string]string
+	for _, value := range values {
+		// current is nil while the parameters of another scheme are parsed
+		var current map[string]string
+		for len(value) > 0 {
+			value = strings.TrimLeft(value, " \t,")
+			token := value[:tokenEnd(value)]
+			if token == "" {
+				if value != "" {
+					value = value[1:]
+				}
+				continue
+			}
+			value = strings.TrimLeft(value[len(token):], " \t")
+			if !strings.HasPrefix(value, "=") {
+				current = nil
+				if strings.EqualFold(token, "digest") {
+					current = make(map[string]string)
+					challenges = append(challenges, current)
+				}
+				continue
+			}
+			value = strings.TrimLeft(value[1:], " \t")
+			var param string
+			if strings.HasPrefix(value, `"`) {
+				param, value = unquote(value)
+			} else {
+				param, value = value[:tokenEnd(value)], value[tokenEnd(value):]
+			}
+			if current != nil {
+				current[strings.ToLower(token)] = param
+			}
+		}
+	}
+	return challenges
+}
+
+// tokenEnd returns the end of the token at the start of the value
+func tokenEnd(value string) int {
+	if i := strings.IndexAny(value, " \t,=\""); i != -1 {
+		return i
+	}
+	return len(value)
+}
+
+// unquote returns the content of the quoted-string at the start of the value and the rest of the value
+func unquote(value string) (string, string) {
+	builder := &strings.Builder{}
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if i+1 < len(value) {
+				i++
+				builder.WriteByte(value[i])
+			}
+		case '"':
+			return builder.String(), value[i+1:]
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+	return builder.String(), ""
+}
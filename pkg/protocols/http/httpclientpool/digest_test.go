@@ -0,0 +1,93 @@
+package httpclientpool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/httpauth"
+)
+
+// rfc7616Challenge is the challenge of the examples of RFC 7616 section 3.9.1
+const rfc7616Challenge = `realm="http-auth@example.org", qop="auth, auth-int", nonce="7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS"`
+
+func TestDigestAuthorization(t *testing.T) {
+	defaultCnonce := digestCnonce
+	defer func() { digestCnonce = defaultCnonce }()
+	digestCnonce = func() string { return "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ" }
+
+	resp := &http.Response{Header: http.Header{"Www-Authenticate": []string{
+		"Digest " + rfc7616Challenge + ", algorithm=MD5",
+		"Basic realm=\"fallback\", Digest " + rfc7616Challenge + ", algorithm=SHA-256",
+	}}}
+	challenge := digestChallengeFromResponse(resp)
+	require.NotNil(t, challenge, "could not parse digest challenge")
+	require.Equal(t, "SHA-256", challenge.algorithm, "could not select the strongest algorithm")
+	require.Equal(t, "auth", challenge.qop)
+	require.Equal(t, `Digest username="Mufasa", realm="http-auth@example.org", nonce="7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", uri="/dir/index.html", algorithm=SHA-256, response="753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1", opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS", qop=auth, nc=00000001, cnonce="f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ"`,
+		challenge.authorization("Mufasa", "Circle of Life", http.MethodGet, "/dir/index.html", nil))
+
+	resp.Header["Www-Authenticate"] = resp.Header["Www-Authenticate"][:1]
+	challenge = digestChallengeFromResponse(resp)
+	require.Contains(t, challenge.authorization("Mufasa", "Circle of Life", http.MethodGet, "/dir/index.html", nil), `response="8ca523f5e9506fed4657c9700eebdbec"`)
+
+	// the challenges without supported algorithm or qop are ignored
+	resp.Header["Www-Authenticate"] = []string{`Digest realm="a", nonce="b", algorithm=SHA-1`, `Digest realm="a", nonce="b", qop="auth-conf"`}
+	require.Nil(t, digestChallengeFromResponse(resp))
+}
+
+func TestDigestTransport(t *testing.T) {
+	var challenges, authenticated int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		params := parseDigestChallenges([]string{r.Header.Get("Authorization")})
+		if len(params) == 1 && params[0]["nonce"] == "n1" {
+			digest := func(value string) string {
+				sum := sha256.Sum256([]byte(value))
+				return hex.EncodeToString(sum[:])
+			}
+			p := params[0]
+			ha1 := digest(digest("admin:device:secret") + ":n1:" + p["cnonce"])
+			ha2 := digest(r.Method + ":" + p["uri"] + ":" + digest(string(body)))
+			if p["response"] == digest(strings.Join([]string{ha1, "n1", p["nc"], p["cnonce"], "auth-int", ha2}, ":")) && p["username"] == digest("admin:device") {
+				atomic.AddInt32(&authenticated, 1)
+				_, _ = w.Write([]byte(p["nc"] + ":" + string(body)))
+				return
+			}
+		}
+		atomic.AddInt32(&challenges, 1)
+		w.Header().Set("WWW-Authenticate", `Digest realm="device", nonce="n1", qop="auth-int", algorithm=SHA-256-sess, userhash=true`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	base := &http.Transport{DialContext: (&net.Dialer{}).DialContext}
+	client := &http.Client{Transport: newDigestTransport(&httpauth.Config{Type: "digest", Username: "admin", Password: "secret"}, base)}
+	for _, expected := range []string{"00000001:first", "00000002:second"} {
+		resp, err := client.Post(server.URL+"/cgi-bin/login?a=1", "text/plain", strings.NewReader(strings.Split(expected, ":")[1]))
+		require.Nil(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, expected, string(body))
+	}
+	// the challenge of the host is answered for the next requests
+	require.Equal(t, int32(1), atomic.LoadInt32(&challenges))
+	require.Equal(t, int32(2), atomic.LoadInt32(&authenticated))
+
+	// the response is returned if the credentials are refused
+	client = &http.Client{Transport: newDigestTransport(&httpauth.Config{Type: "digest", Username: "admin", Password: "wrong"}, base)}
+	resp, err := client.Get(server.URL)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,226 @@
+package httpclientpool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// cleartext HTTP/2 modes of the requests
+const (
+	// HTTP2Upgrade upgrades the HTTP/1.1 connections to HTTP/2 with the h2c upgrade
+	HTTP2Upgrade = "h2c"
+	// HTTP2PriorKnowledge sends HTTP/2 frames directly without upgrade
+	HTTP2PriorKnowledge = "prior-knowledge"
+)
+
+// maxH2CBodySize is the maximum size of the bodies read from the upgraded connections
+const maxH2CBodySize = 10 * 1024 * 1024
+
+// upgradeSettings is the base64url encoded SETTINGS payload of the upgrade requests disabling the server push
+var upgradeSettings = base64.RawURLEncoding.EncodeToString([]byte{0, byte(http2.SettingEnablePush), 0, 0, 0, 0})
+
+// h2cTransport sends the requests of http urls with cleartext HTTP/2,
+// the requests of https urls are sent with the base transport.
+type h2cTransport struct {
+	mode  string
+	base  *http.Transport
+	prior *http2.Transport
+}
+
+// newH2CTransport returns a transport sending cleartext HTTP/2 requests with the mode
+func newH2CTransport(mode string, base *http.Transport) http.RoundTripper {
+	transport := &h2cTransport{mode: mode, base: base}
+	if mode == HTTP2PriorKnowledge {
+		transport.prior = &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return base.DialContext(ctx, network, addr)
+			},
+			DisableCompression: base.DisableCompression,
+		}
+	}
+	return transport
+}
+
+// RoundTrip sends the request with cleartext HTTP/2
+func (t *h2cTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "http" {
+		return t.base.RoundTrip(req)
+	}
+	if t.mode == HTTP2PriorKnowledge {
+		return t.prior.RoundTrip(req)
+	}
+	return t.upgradeRoundTrip(req)
+}
+
+// upgradeRoundTrip sends the request with the h2c upgrade headers and reads the response of the
+// stream 1 once the connection is upgraded, the HTTP/1.1 response is returned if the server
+// refuses the upgrade.
+//
+// https://www.rfc-editor.org/rfc/rfc7540#section-3.2
+func (t *h2cTransport) upgradeRoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	conn, err := t.base.DialContext(ctx, "tcp", canonicalAddr(req))
+	if err != nil {
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+
+	upgradeReq := req.Clone(ctx)
+	upgradeReq.Header.Set("Connection", "Upgrade, HTTP2-Settings")
+	upgradeReq.Header.Set("Upgrade", "h2c")
+	upgradeReq.Header.Set("HTTP2-Settings", upgradeSettings)
+	if err := upgradeReq.Write(conn); err != nil {
+		stop()
+		_ = conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		stop()
+		_ = conn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Body = &connBody{ReadCloser: resp.Body, conn: conn, stop: stop}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	defer stop()
+	defer conn.Close()
+	return readUpgradedResponse(conn, reader, req)
+}
+
+// readUpgradedResponse sends the connection preface and reads the response of the stream 1
+func readUpgradedResponse(conn net.Conn, reader io.Reader, req *http.Request) (*http.Response, error) {
+	if _, err := io.WriteString(conn, http2.ClientPreface); err != nil {
+		return nil, err
+	}
+	framer := http2.NewFramer(conn, reader)
+	framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+	if err := framer.WriteSettings(http2.Setting{ID: http2.SettingEnablePush, Val: 0}); err != nil {
+		return nil, err
+	}
+
+	resp := &http.Response{
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     make(http.Header),
+		Request:    req,
+	}
+	body := &bytes.Buffer{}
+	for {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read http2 frame")
+		}
+		switch f := frame.(type) {
+		case *http2.SettingsFrame:
+			if !f.IsAck() {
+				if err := framer.WriteSettingsAck(); err != nil {
+					return nil, err
+				}
+			}
+		case *http2.PingFrame:
+			if !f.IsAck() {
+				if err := framer.WritePing(true, f.Data); err != nil {
+					return nil, err
+				}
+			}
+		case *http2.MetaHeadersFrame:
+			if f.StreamID != 1 {
+				continue
+			}
+			status, err := strconv.Atoi(f.PseudoValue("status"))
+			if err != nil {
+				return nil, errors.New("invalid http2 status")
+			}
+			if status >= 100 && status < 200 {
+				// informational responses precede the final response
+				continue
+			}
+			resp.StatusCode = status
+			resp.Status = fmt.Sprintf("%d %s", status, http.StatusText(status))
+			for _, field := range f.RegularFields() {
+				resp.Header.Add(http.CanonicalHeaderKey(field.Name), field.Value)
+			}
+			if f.StreamEnded() {
+				return finalizeUpgradedResponse(resp, body)
+			}
+		case *http2.DataFrame:
+			if f.StreamID != 1 {
+				continue
+			}
+			if body.Len()+len(f.Data()) > maxH2CBodySize {
+				return nil, errors.New("http2 response body exceeds the maximum size")
+			}
+			body.Write(f.Data())
+			if length := uint32(len(f.Data())); length > 0 {
+				// keep the flow control windows open for the rest of the body
+				if err := framer.WriteWindowUpdate(0, length); err != nil {
+					return nil, err
+				}
+				if err := framer.WriteWindowUpdate(1, length); err != nil {
+					return nil, err
+				}
+			}
+			if f.StreamEnded() {
+				return finalizeUpgradedResponse(resp, body)
+			}
+		case *http2.RSTStreamFrame:
+			if f.StreamID == 1 {
+				return nil, fmt.Errorf("http2 stream reset by the server: %s", f.ErrCode)
+			}
+		case *http2.GoAwayFrame:
+			return nil, fmt.Errorf("http2 connection closed by the server: %s", f.ErrCode)
+		}
+	}
+}
+
+// finalizeUpgradedResponse sets the body of the response read from the upgraded connection
+func finalizeUpgradedResponse(resp *http.Response, body *bytes.Buffer) (*http.Response, error) {
+	if resp.StatusCode == 0 {
+		return nil, errors.New("http2 stream ended without response headers")
+	}
+	resp.ContentLength = int64(body.Len())
+	resp.Body = io.NopCloser(body)
+	return resp, nil
+}
+
+// connBody closes the connection once the body of a refused upgrade is closed
+type connBody struct {
+	io.ReadCloser
+	conn net.Conn
+	stop func() bool
+}
+
+func (b *connBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.stop()
+	_ = b.conn.Close()
+	return err
+}
+
+// canonicalAddr returns the host and port of the request url, the default port is used if missing
+func canonicalAddr(req *http.Request) string {
+	host := req.URL.Host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		return net.JoinHostPort(strings.Trim(host, "[]"), "80")
+	}
+	return host
+}
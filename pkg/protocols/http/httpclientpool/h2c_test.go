@@ -0,0 +1,47 @@
+package httpclientpool
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestH2CTransport(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+		_, _ = io.WriteString(w, strings.Repeat("a", 100000))
+	})
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer server.Close()
+	http1Server := httptest.NewServer(handler)
+	defer http1Server.Close()
+
+	base := &http.Transport{DialContext: (&net.Dialer{}).DialContext}
+	for _, mode := range []string{HTTP2Upgrade, HTTP2PriorKnowledge} {
+		client := &http.Client{Transport: newH2CTransport(mode, base)}
+		resp, err := client.Get(server.URL)
+		require.Nil(t, err, mode)
+		body, err := io.ReadAll(resp.Body)
+		require.Nil(t, err, mode)
+		_ = resp.Body.Close()
+		require.Equal(t, "HTTP/2.0", resp.Proto, mode)
+		require.Equal(t, http.StatusOK, resp.StatusCode, mode)
+		// the body is larger than the initial flow control window
+		require.Len(t, body, 100000, mode)
+	}
+
+	// the HTTP/1.1 response is returned if the upgrade is refused
+	client := &http.Client{Transport: newH2CTransport(HTTP2Upgrade, base)}
+	resp, err := client.Get(http1Server.URL)
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "HTTP/1.1", resp.Proto)
+	require.Equal(t, "HTTP/1.1", resp.Header.Get("X-Proto"))
+}
@@ -0,0 +1,178 @@
+package httpclientpool
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	kclient "github.com/ropnop/gokrb5/v8/client"
+	kconfig "github.com/ropnop/gokrb5/v8/config"
+	"github.com/ropnop/gokrb5/v8/credentials"
+	"github.com/ropnop/gokrb5/v8/keytab"
+	"github.com/ropnop/gokrb5/v8/spnego"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/httpauth"
+)
+
+// krb5ConfigTemplateDNS is the kerberos configuration of a realm with the KDCs looked up with DNS
+const krb5ConfigTemplateDNS = `[libdefaults]
+default_realm = %s
+dns_lookup_kdc = true
+udp_preference_limit = 1
+`
+
+// krb5ConfigTemplateKDC is the kerberos configuration of a realm with a KDC
+const krb5ConfigTemplateKDC = `[libdefaults]
+default_realm = %[1]s
+udp_preference_limit = 1
+[realms]
+%[1]s = {
+	kdc = %[2]s
+}
+`
+
+// kerberosTransport authenticates the requests with the Kerberos tokens
+// of the Negotiate scheme (SPNEGO).
+//
+// The requests are sent without token first, the token of the service principal
+// is only requested from the KDC when the server asks for the Negotiate scheme.
+type kerberosTransport struct {
+	config *httpauth.Config
+	base   http.RoundTripper
+
+	once   sync.Once
+	client *kclient.Client
+	err    error
+}
+
+// newKerberosTransport returns a transport authenticating the requests with the config
+func newKerberosTransport(config *httpauth.Config, base http.RoundTripper) http.RoundTripper {
+	return &kerberosTransport{config: config, base: base}
+}
+
+// RoundTrip sends the request and retries it with a Kerberos token if the server asks for it
+func (t *kerberosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	resp, err := t.base.RoundTrip(requestWithBody(req, body))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized || !asksNegotiate(resp) {
+		return resp, nil
+	}
+
+	client, err := t.kerberosClient()
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	authenticated := requestWithBody(req, body)
+	if err := spnego.SetSPNEGOHeader(client, authenticated, t.servicePrincipal(req)); err != nil {
+		_ = resp.Body.Close()
+		return nil, errors.Wrap(err, "could not get kerberos token")
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return t.base.RoundTrip(authenticated)
+}
+
+// servicePrincipal returns the service principal name of the request
+func (t *kerberosTransport) servicePrincipal(req *http.Request) string {
+	if t.config.SPN != "" {
+		return t.config.SPN
+	}
+	return "HTTP/" + strings.TrimSuffix(req.URL.Hostname(), ".")
+}
+
+// kerberosClient returns the kerberos client of the config, logged in once with the
+// password or the keytab. The credential cache already holds the tickets of the user.
+func (t *kerberosTransport) kerberosClient() (*kclient.Client, error) {
+	t.once.Do(func() {
+		realm, username := t.config.Principal()
+		var ccache *credentials.CCache
+		if t.config.CCache != "" {
+			if ccache, t.err = credentials.LoadCCache(t.config.CCache); t.err != nil {
+				t.err = errors.Wrap(t.err, "could not load kerberos credential cache")
+				return
+			}
+			if realm == "" {
+				realm = ccache.GetClientRealm()
+			}
+		}
+
+		krb5Config, err := newKrb5Config(realm, t.config.KDC)
+		if err != nil {
+			t.err = errors.Wrap(err, "could not create kerberos config")
+			return
+		}
+		switch {
+		case ccache != nil:
+			t.client, t.err = kclient.NewFromCCache(ccache, krb5Config, kclient.DisablePAFXFAST(true))
+			return
+		case t.config.Keytab != "":
+			kt, err := keytab.Load(t.config.Keytab)
+			if err != nil {
+				t.err = errors.Wrap(err, "could not load kerberos keytab")
+				return
+			}
+			t.client = kclient.NewWithKeytab(username, realm, kt, krb5Config, kclient.DisablePAFXFAST(true))
+		default:
+			t.client = kclient.NewWithPassword(username, realm, t.config.Password, krb5Config, kclient.DisablePAFXFAST(true))
+		}
+		if err := t.client.Login(); err != nil {
+			t.client, t.err = nil, errors.Wrap(err, "could not login with kerberos")
+		}
+	})
+	return t.client, t.err
+}
+
+// newKrb5Config returns the kerberos config of the realm and the kdc
+func newKrb5Config(realm, kdc string) (*kconfig.Config, error) {
+	if realm == "" {
+		return nil, errors.New("realm is required")
+	}
+	if kdc == "" {
+		return kconfig.NewFromString(fmt.Sprintf(krb5ConfigTemplateDNS, realm))
+	}
+	if _, _, err := net.SplitHostPort(kdc); err != nil {
+		kdc = net.JoinHostPort(kdc, "88")
+	}
+	return kconfig.NewFromString(fmt.Sprintf(krb5ConfigTemplateKDC, realm, kdc))
+}
+
+// asksNegotiate returns true if the server asks for the Negotiate scheme
+func asksNegotiate(resp *http.Response) bool {
+	for _, value := range resp.Header.Values("Www-Authenticate") {
+		name, _, _ := strings.Cut(strings.TrimSpace(value), " ")
+		if strings.EqualFold(name, "Negotiate") {
+			return true
+		}
+	}
+	return false
+}
+
+// requestWithBody returns a copy of the request with the body
+func requestWithBody(req *http.Request, body []byte) *http.Request {
+	clone := req.Clone(req.Context())
+	if body != nil {
+		clone.Body = io.NopCloser(bytes.NewReader(body))
+		clone.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	return clone
+}
@@ -0,0 +1,64 @@
+package httpclientpool
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/httpauth"
+)
+
+func TestKerberosTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/negotiate" {
+			w.Header().Set("WWW-Authenticate", "Negotiate")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", "Basic")
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	// the kdc is unreachable, the login fails once asked for the Negotiate scheme
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	kdc := listener.Addr().String()
+	listener.Close()
+
+	config := &httpauth.Config{Type: httpauth.Kerberos, Username: "admin@corp.local", Password: "secret", KDC: kdc}
+	client := &http.Client{Transport: newKerberosTransport(config, &http.Transport{DisableKeepAlives: true})}
+
+	// the kdc is not contacted if the server doesn't ask for the Negotiate scheme
+	resp, err := client.Get(server.URL)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	_, err = client.Get(server.URL + "/negotiate")
+	require.ErrorContains(t, err, "could not login with kerberos")
+}
+
+func TestKerberosServicePrincipal(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://intranet.corp.local.:8080/", nil)
+	require.Nil(t, err)
+	transport := &kerberosTransport{config: &httpauth.Config{}}
+	require.Equal(t, "HTTP/intranet.corp.local", transport.servicePrincipal(req))
+	transport.config.SPN = "HTTP/web.corp.local"
+	require.Equal(t, "HTTP/web.corp.local", transport.servicePrincipal(req))
+}
+
+func TestKerberosConfig(t *testing.T) {
+	config, err := newKrb5Config("CORP.LOCAL", "dc01.corp.local")
+	require.Nil(t, err)
+	_, kdcs, err := config.GetKDCs("CORP.LOCAL", true)
+	require.Nil(t, err)
+	require.Equal(t, "dc01.corp.local:88", kdcs[1])
+
+	config, err = newKrb5Config("CORP.LOCAL", "")
+	require.Nil(t, err)
+	require.True(t, config.LibDefaults.DNSLookupKDC)
+}
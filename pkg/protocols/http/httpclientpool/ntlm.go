@@ -0,0 +1,127 @@
+package httpclientpool
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-ntlmssp"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/httpauth"
+)
+
+// ntlmTransport authenticates the requests with the NTLM or Negotiate schemes.
+//
+// The handshake authenticates the connection instead of the requests, each
+// request is therefore sent with a dedicated connection kept alive until the
+// body of the response is closed.
+type ntlmTransport struct {
+	config *httpauth.Config
+	base   *http.Transport
+}
+
+// newNTLMTransport returns a transport authenticating the requests with the config
+func newNTLMTransport(config *httpauth.Config, base *http.Transport) http.RoundTripper {
+	return &ntlmTransport{config: config, base: base}
+}
+
+// RoundTrip sends the negotiate message with the request and answers the challenge of the server
+func (t *ntlmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+	scheme := "NTLM"
+	if strings.EqualFold(t.config.Type, httpauth.Negotiate) {
+		scheme = "Negotiate"
+	}
+	domain, username := t.config.Credentials()
+
+	transport := t.base.Clone()
+	transport.DisableKeepAlives = false
+	transport.MaxConnsPerHost = 1
+
+	negotiateMessage, err := ntlmssp.NewNegotiateMessage(domain, "")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := transport.RoundTrip(authenticatedRequest(req, body, scheme, negotiateMessage))
+	if err != nil {
+		transport.CloseIdleConnections()
+		return nil, err
+	}
+	challengeMessage := challengeFromResponse(resp, scheme)
+	if resp.StatusCode != http.StatusUnauthorized || challengeMessage == nil {
+		// authentication not requested or refused by the server
+		return withIdleClose(resp, transport), nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	// the domain of the challenge is used unless the username has the user@domain format
+	authenticateMessage, err := ntlmssp.ProcessChallenge(challengeMessage, username, t.config.Password, !strings.Contains(username, "@"))
+	if err != nil {
+		transport.CloseIdleConnections()
+		return nil, errors.Wrap(err, "could not answer ntlm challenge")
+	}
+	resp, err = transport.RoundTrip(authenticatedRequest(req, body, scheme, authenticateMessage))
+	if err != nil {
+		transport.CloseIdleConnections()
+		return nil, err
+	}
+	return withIdleClose(resp, transport), nil
+}
+
+// authenticatedRequest returns a copy of the request with the authorization message and the body
+func authenticatedRequest(req *http.Request, body []byte, scheme string, message []byte) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", scheme+" "+base64.StdEncoding.EncodeToString(message))
+	if body != nil {
+		clone.Body = io.NopCloser(bytes.NewReader(body))
+		clone.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	return clone
+}
+
+// challengeFromResponse returns the challenge message of the scheme sent by the server
+func challengeFromResponse(resp *http.Response, scheme string) []byte {
+	for _, value := range resp.Header.Values("Www-Authenticate") {
+		name, data, ok := strings.Cut(strings.TrimSpace(value), " ")
+		if !ok || !strings.EqualFold(name, scheme) {
+			continue
+		}
+		message, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+		if err == nil && len(message) > 0 {
+			return message
+		}
+	}
+	return nil
+}
+
+// idleClosingBody closes the connection of the dedicated transport once the body is closed
+type idleClosingBody struct {
+	io.ReadCloser
+	transport *http.Transport
+}
+
+func (b *idleClosingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.transport.CloseIdleConnections()
+	return err
+}
+
+// withIdleClose sets the body of the response to close the connections of the transport
+func withIdleClose(resp *http.Response, transport *http.Transport) *http.Response {
+	resp.Body = &idleClosingBody{ReadCloser: resp.Body, transport: transport}
+	return resp
+}
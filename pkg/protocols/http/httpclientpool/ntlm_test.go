@@ -0,0 +1,71 @@
+package httpclientpool
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/httpauth"
+)
+
+// ntlmChallenge is a minimal unicode NTLM challenge message without target information
+func ntlmChallenge() string {
+	message := &bytes.Buffer{}
+	message.WriteString("NTLMSSP\x00")
+	_ = binary.Write(message, binary.LittleEndian, uint32(2))
+	message.Write(make([]byte, 8))
+	_ = binary.Write(message, binary.LittleEndian, uint32(1))
+	message.Write(make([]byte, 24))
+	return base64.StdEncoding.EncodeToString(message.Bytes())
+}
+
+func TestNTLMTransport(t *testing.T) {
+	var mu sync.Mutex
+	// negotiated are the connections which sent a negotiate message
+	negotiated := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		message, _ := base64.StdEncoding.DecodeString(token)
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case scheme == "Negotiate" && len(message) > 8 && message[8] == 1:
+			negotiated[r.RemoteAddr] = true
+			w.Header().Set("WWW-Authenticate", "Negotiate "+ntlmChallenge())
+			w.WriteHeader(http.StatusUnauthorized)
+		case scheme == "Negotiate" && len(message) > 8 && message[8] == 3 && negotiated[r.RemoteAddr]:
+			_, _ = w.Write(body)
+		default:
+			w.Header().Set("WWW-Authenticate", "Negotiate")
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	base := &http.Transport{DialContext: (&net.Dialer{}).DialContext, DisableKeepAlives: true}
+	client := &http.Client{Transport: newNTLMTransport(&httpauth.Config{Type: "negotiate", Username: `CORP\admin`, Password: "secret"}, base)}
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	// the handshake is completed on the same connection with the body of the request
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "payload", string(body))
+
+	// the response is returned if another scheme is requested
+	resp, err = (&http.Client{Transport: newNTLMTransport(&httpauth.Config{Type: "ntlm", Username: "admin"}, base)}).Get(server.URL)
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,19 @@
+package httpclientpool
+
+import (
+	"context"
+	"net"
+
+	httputil "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils/http"
+)
+
+// socketDialer returns a dialer connecting to the socket whatever the address of the requests
+func socketDialer(socket *httputil.Socket) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		if socket.Network == httputil.NamedPipe {
+			return dialNamedPipe(ctx, socket.Path)
+		}
+		dialer := &net.Dialer{}
+		return dialer.DialContext(ctx, httputil.UnixSocket, socket.Path)
+	}
+}
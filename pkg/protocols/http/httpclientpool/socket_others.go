@@ -0,0 +1,16 @@
+//go:build !windows
+
+package httpclientpool
+
+import (
+	"context"
+	"net"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// dialNamedPipe is not supported on this platform
+func dialNamedPipe(context.Context, string) (net.Conn, error) {
+	return nil, errors.Errorf("named pipes are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,13 @@
+package httpclientpool
+
+import (
+	"context"
+	"net"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// dialNamedPipe connects to the windows named pipe
+func dialNamedPipe(ctx context.Context, path string) (net.Conn, error) {
+	return winio.DialPipeContext(ctx, path)
+}
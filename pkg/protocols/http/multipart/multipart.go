@@ -0,0 +1,126 @@
+// Package multipart builds the multipart/form-data bodies of the http templates
+package multipart
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// boundaryPrefix is the prefix of the random boundaries
+const boundaryPrefix = "----NucleiFormBoundary"
+
+// Config contains the parts of a multipart/form-data body.
+//
+// The body is built for every request after the evaluation of the variables,
+// the Content-Type header is set with the boundary unless set by the request.
+type Config struct {
+	// description: |
+	//   Boundary is the boundary of the parts, a random boundary is used if empty.
+	// examples:
+	//   - value: "\"----WebKitFormBoundary7MA4YWxkTrZu0gW\""
+	Boundary string `yaml:"boundary,omitempty" json:"boundary,omitempty" jsonschema:"title=multipart boundary,description=Boundary of the parts"`
+	// description: |
+	//   Fields are the form fields and the files of the body.
+	Fields []*Field `yaml:"fields,omitempty" json:"fields,omitempty" jsonschema:"title=multipart fields,description=Form fields and files of the body"`
+}
+
+// Field is a form field or a file of a multipart body
+type Field struct {
+	// description: |
+	//   Name is the name of the form field.
+	// examples:
+	//   - value: "\"file\""
+	Name string `yaml:"name,omitempty" json:"name,omitempty" jsonschema:"title=field name,description=Name of the form field"`
+	// description: |
+	//   Value is the content of the field or of the file.
+	// examples:
+	//   - value: "\"<?php echo md5('nuclei'); ?>\""
+	Value string `yaml:"value,omitempty" json:"value,omitempty" jsonschema:"title=field value,description=Content of the field or of the file"`
+	// description: |
+	//   Filename makes the field a file part with the filename.
+	//
+	//   The filename is sent verbatim, without escaping of the quotes.
+	// examples:
+	//   - value: "\"shell.php.jpg\""
+	Filename string `yaml:"filename,omitempty" json:"filename,omitempty" jsonschema:"title=file name,description=Filename of the file part"`
+	// description: |
+	//   ContentType is the Content-Type header of the part.
+	// examples:
+	//   - value: "\"image/jpeg\""
+	ContentType string `yaml:"content-type,omitempty" json:"content-type,omitempty" jsonschema:"title=part content type,description=Content-Type header of the part"`
+	// description: |
+	//   Headers are the additional headers of the part.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=part headers,description=Additional headers of the part"`
+}
+
+// Validate validates the fields of the config
+func (c *Config) Validate() error {
+	if len(c.Fields) == 0 {
+		return errors.New("no multipart fields")
+	}
+	for i, field := range c.Fields {
+		if field == nil || field.Name == "" {
+			return errors.Errorf("multipart field %d has no name", i+1)
+		}
+	}
+	return nil
+}
+
+// Build returns the body and the Content-Type header of the multipart body,
+// every value of the config is given to evaluate before being written
+func (c *Config) Build(evaluate func(string) (string, error)) ([]byte, string, error) {
+	boundary, err := evaluate(c.Boundary)
+	if err != nil {
+		return nil, "", err
+	}
+	if boundary == "" {
+		random := make([]byte, 8)
+		_, _ = rand.Read(random)
+		boundary = boundaryPrefix + hex.EncodeToString(random)
+	}
+
+	buf := &bytes.Buffer{}
+	for _, field := range c.Fields {
+		var values [4]string
+		for i, value := range []string{field.Name, field.Value, field.Filename, field.ContentType} {
+			if values[i], err = evaluate(value); err != nil {
+				return nil, "", err
+			}
+		}
+		name, value, filename, contentType := values[0], values[1], values[2], values[3]
+
+		buf.WriteString("--" + boundary + "\r\n")
+		buf.WriteString(`Content-Disposition: form-data; name="` + name + `"`)
+		if field.Filename != "" {
+			buf.WriteString(`; filename="` + filename + `"`)
+		}
+		buf.WriteString("\r\n")
+		if contentType == "" && field.Filename != "" {
+			contentType = "application/octet-stream"
+		}
+		if contentType != "" {
+			buf.WriteString("Content-Type: " + contentType + "\r\n")
+		}
+		headers := make([]string, 0, len(field.Headers))
+		for header := range field.Headers {
+			headers = append(headers, header)
+		}
+		sort.Strings(headers)
+		for _, header := range headers {
+			headerValue, err := evaluate(field.Headers[header])
+			if err != nil {
+				return nil, "", err
+			}
+			buf.WriteString(header + ": " + headerValue + "\r\n")
+		}
+		buf.WriteString("\r\n")
+		buf.WriteString(value)
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString("--" + boundary + "--\r\n")
+	return buf.Bytes(), "multipart/form-data; boundary=" + boundary, nil
+}
@@ -0,0 +1,57 @@
+package multipart
+
+import (
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	config := &Config{
+		Boundary: "nuclei",
+		Fields: []*Field{
+			{Name: "submit", Value: "Upload"},
+			{Name: "file", Filename: "{{name}}.php", ContentType: "image/png", Value: "<?php echo 1; ?>", Headers: map[string]string{"X-Part": "{{name}}"}},
+			{Name: "raw", Filename: "a.bin"},
+		},
+	}
+	require.Nil(t, config.Validate())
+
+	body, contentType, err := config.Build(func(value string) (string, error) {
+		return strings.ReplaceAll(value, "{{name}}", "shell"), nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, "multipart/form-data; boundary=nuclei", contentType)
+	require.Equal(t, "--nuclei\r\n"+
+		"Content-Disposition: form-data; name=\"submit\"\r\n\r\nUpload\r\n"+
+		"--nuclei\r\n"+
+		"Content-Disposition: form-data; name=\"file\"; filename=\"shell.php\"\r\nContent-Type: image/png\r\nX-Part: shell\r\n\r\n<?php echo 1; ?>\r\n"+
+		"--nuclei\r\n"+
+		"Content-Disposition: form-data; name=\"raw\"; filename=\"a.bin\"\r\nContent-Type: application/octet-stream\r\n\r\n\r\n"+
+		"--nuclei--\r\n", string(body))
+}
+
+func TestBuildRandomBoundary(t *testing.T) {
+	config := &Config{Fields: []*Field{{Name: "file", Filename: "test.txt", Value: "nuclei"}}}
+	body, contentType, err := config.Build(func(value string) (string, error) { return value, nil })
+	require.Nil(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	require.Nil(t, err)
+	require.Equal(t, "multipart/form-data", mediaType)
+	require.True(t, strings.HasPrefix(params["boundary"], boundaryPrefix))
+
+	reader := multipart.NewReader(strings.NewReader(string(body)), params["boundary"])
+	form, err := reader.ReadForm(1024)
+	require.Nil(t, err, "could not parse multipart body")
+	require.Len(t, form.File["file"], 1)
+	require.Equal(t, "test.txt", form.File["file"][0].Filename)
+}
+
+func TestValidate(t *testing.T) {
+	require.EqualError(t, (&Config{}).Validate(), "no multipart fields")
+	require.EqualError(t, (&Config{Fields: []*Field{{Value: "x"}}}).Validate(), "multipart field 1 has no name")
+}
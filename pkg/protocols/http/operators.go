@@ -12,12 +12,21 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/stream"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
 
+// streamResultsKey is the key of the results of the matchers of the streamed bodies
+const streamResultsKey = "stream_results"
+
 // Match matches a generic data response again a given matcher
 func (request *Request) Match(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
+	if results, ok := data[streamResultsKey].(stream.Results); ok {
+		if result, ok := results[matcher]; ok {
+			return matcher.ResultWithMatchedSnippet(result.Matched, result.Snippets)
+		}
+	}
 	item, ok := request.getMatchPart(matcher.Part, data)
 	if !ok && matcher.Type.MatcherType != matchers.DSLMatcher {
 		return false, []string{}
@@ -40,6 +49,10 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(item))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
+	case matchers.PluginMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchPlugin(item))
+	case matchers.YaraMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchYara(item))
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(item)), []string{}
 	}
@@ -75,6 +88,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractJSON(item)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.PluginExtractor:
+		return extractor.ExtractPlugin(item)
 	}
 	return nil
 }
@@ -0,0 +1,101 @@
+package protobuf
+
+import (
+	"encoding/base64"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// maxDepth is the maximum depth of the nested messages decoded without descriptor
+const maxDepth = 32
+
+// decodeRaw decodes a message without its descriptor like protoc --decode_raw,
+// the fields are keyed by their numbers and repeated fields are decoded to lists.
+//
+// The length-delimited fields are decoded to printable strings, then to nested
+// messages and to base64 for the other bytes.
+func decodeRaw(data []byte) (map[string]interface{}, error) {
+	return decodeRawDepth(data, 0)
+}
+
+func decodeRawDepth(data []byte, depth int) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for len(data) > 0 {
+		number, kind, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var value interface{}
+		switch kind {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			value, data = v, data[n:]
+		case protowire.Fixed32Type:
+			v, n := protowire.ConsumeFixed32(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			value, data = v, data[n:]
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			value, data = v, data[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			value, data = decodeBytes(v, depth), data[n:]
+		default:
+			return nil, errors.Errorf("unsupported wire type %d of field %d", kind, number)
+		}
+
+		key := strconv.Itoa(int(number))
+		switch existing := fields[key].(type) {
+		case nil:
+			fields[key] = value
+		case []interface{}:
+			fields[key] = append(existing, value)
+		default:
+			fields[key] = []interface{}{existing, value}
+		}
+	}
+	return fields, nil
+}
+
+// decodeBytes returns the string, the nested message or the base64 of a length-delimited field
+func decodeBytes(data []byte, depth int) interface{} {
+	if isPrintable(data) {
+		return string(data)
+	}
+	if depth < maxDepth {
+		if nested, err := decodeRawDepth(data, depth+1); err == nil {
+			return nested
+		}
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// isPrintable returns true if the data is an utf-8 string without control characters
+func isPrintable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
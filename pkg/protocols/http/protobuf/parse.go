@@ -0,0 +1,549 @@
+package protobuf
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// scalarTypes are the field types of the scalar values
+var scalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"double":   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"float":    descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	"int64":    descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"uint64":   descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"int32":    descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"fixed64":  descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+	"fixed32":  descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+	"bool":     descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"string":   descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":    descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+	"uint32":   descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"sfixed32": descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+	"sfixed64": descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+	"sint32":   descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+	"sint64":   descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+}
+
+// Parse parses a .proto definition to the descriptor of its file.
+//
+// The services, options and reserved statements are skipped and the
+// types of the fields are resolved when the file is built.
+func Parse(definition string) (*descriptorpb.FileDescriptorProto, error) {
+	tokens, err := tokenize(definition)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	file := &descriptorpb.FileDescriptorProto{Name: proto.String("template.proto"), Syntax: proto.String("proto3")}
+	if err := p.parseFile(file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+	proto2 bool
+}
+
+// next returns the next token, empty at the end of the definition
+func (p *parser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	return token
+}
+
+// peek returns the next token without consuming it
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// expect consumes the next token and fails if it is not the expected one
+func (p *parser) expect(expected string) error {
+	if token := p.next(); token != expected {
+		return p.unexpected(token, expected)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(token, expected string) error {
+	if token == "" {
+		return errors.Errorf("unexpected end of proto, expected %q", expected)
+	}
+	return errors.Errorf("unexpected %q, expected %q", token, expected)
+}
+
+// name consumes an identifier
+func (p *parser) name() (string, error) {
+	token := p.next()
+	if token == "" || !isIdentifier(token) {
+		return "", p.unexpected(token, "identifier")
+	}
+	return token, nil
+}
+
+// skip consumes a statement until its semicolon or its block
+func (p *parser) skip() error {
+	depth := 0
+	for {
+		switch p.next() {
+		case "":
+			return errors.New("unexpected end of proto")
+		case ";":
+			if depth == 0 {
+				return nil
+			}
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				// optional semicolon after the blocks
+				if p.peek() == ";" {
+					p.next()
+				}
+				return nil
+			}
+		}
+	}
+}
+
+func (p *parser) parseFile(file *descriptorpb.FileDescriptorProto) error {
+	for {
+		switch token := p.peek(); token {
+		case "":
+			return nil
+		case ";":
+			p.next()
+		case "syntax":
+			p.next()
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			syntax := unquote(p.next())
+			if syntax != "proto2" && syntax != "proto3" {
+				return errors.Errorf("unsupported syntax %q", syntax)
+			}
+			p.proto2 = syntax == "proto2"
+			if p.proto2 {
+				file.Syntax = nil
+			}
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "package":
+			p.next()
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			file.Package = proto.String(name)
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "import":
+			p.next()
+			if token := p.peek(); token == "public" || token == "weak" {
+				p.next()
+			}
+			file.Dependency = append(file.Dependency, unquote(p.next()))
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "message":
+			p.next()
+			message, err := p.parseMessage()
+			if err != nil {
+				return err
+			}
+			file.MessageType = append(file.MessageType, message)
+		case "enum":
+			p.next()
+			enum, err := p.parseEnum()
+			if err != nil {
+				return err
+			}
+			file.EnumType = append(file.EnumType, enum)
+		case "option", "service", "extend":
+			if err := p.skip(); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("unexpected %q in proto", token)
+		}
+	}
+}
+
+func (p *parser) parseMessage() (*descriptorpb.DescriptorProto, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	message := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	// the synthetic oneofs of the proto3 optional fields follow the declared oneofs
+	var optionals []*descriptorpb.FieldDescriptorProto
+
+	for {
+		switch token := p.peek(); token {
+		case "":
+			return nil, p.unexpected("", "}")
+		case "}":
+			p.next()
+			for _, field := range optionals {
+				field.OneofIndex = proto.Int32(int32(len(message.OneofDecl)))
+				message.OneofDecl = append(message.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + field.GetName())})
+			}
+			return message, nil
+		case ";":
+			p.next()
+		case "message":
+			p.next()
+			nested, err := p.parseMessage()
+			if err != nil {
+				return nil, err
+			}
+			message.NestedType = append(message.NestedType, nested)
+		case "enum":
+			p.next()
+			enum, err := p.parseEnum()
+			if err != nil {
+				return nil, err
+			}
+			message.EnumType = append(message.EnumType, enum)
+		case "oneof":
+			p.next()
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("{"); err != nil {
+				return nil, err
+			}
+			index := proto.Int32(int32(len(message.OneofDecl)))
+			message.OneofDecl = append(message.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(name)})
+			for p.peek() != "}" {
+				if p.peek() == "option" {
+					if err := p.skip(); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				field, err := p.parseField(nil)
+				if err != nil {
+					return nil, err
+				}
+				field.OneofIndex = index
+				message.Field = append(message.Field, field)
+			}
+			p.next()
+		case "map":
+			p.next()
+			field, entry, err := p.parseMap()
+			if err != nil {
+				return nil, err
+			}
+			message.Field = append(message.Field, field)
+			message.NestedType = append(message.NestedType, entry)
+		case "option", "reserved", "extensions", "extend":
+			if err := p.skip(); err != nil {
+				return nil, err
+			}
+		default:
+			var label string
+			if token == "repeated" || token == "optional" || token == "required" {
+				label = p.next()
+			}
+			if label == "required" && !p.proto2 {
+				return nil, errors.New("required fields are not allowed in proto3")
+			}
+			field, err := p.parseField(&label)
+			if err != nil {
+				return nil, err
+			}
+			if label == "optional" && !p.proto2 {
+				field.Proto3Optional = proto.Bool(true)
+				optionals = append(optionals, field)
+			}
+			message.Field = append(message.Field, field)
+		}
+	}
+}
+
+// parseField parses a field after its label, the fields of the oneofs have no label
+func (p *parser) parseField(label *string) (*descriptorpb.FieldDescriptorProto, error) {
+	kind, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if kind == "group" {
+		return nil, errors.New("groups are not supported")
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	number, err := p.number()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid number of field %s", name)
+	}
+	if err := p.options(); err != nil {
+		return nil, err
+	}
+
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	if label != nil {
+		switch *label {
+		case "repeated":
+			field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		case "required":
+			field.Label = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum()
+		}
+	}
+	setType(field, kind)
+	return field, nil
+}
+
+// parseMap parses a map field and returns its entry message
+func (p *parser) parseMap() (*descriptorpb.FieldDescriptorProto, *descriptorpb.DescriptorProto, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, nil, err
+	}
+	keyType, err := p.name()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := p.expect(","); err != nil {
+		return nil, nil, err
+	}
+	valueType, err := p.name()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := p.expect(">"); err != nil {
+		return nil, nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, nil, err
+	}
+	number, err := p.number()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid number of field %s", name)
+	}
+	if err := p.options(); err != nil {
+		return nil, nil, err
+	}
+
+	entryName := entryName(name)
+	key := &descriptorpb.FieldDescriptorProto{Name: proto.String("key"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+	setType(key, keyType)
+	value := &descriptorpb.FieldDescriptorProto{Name: proto.String("value"), Number: proto.Int32(2), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+	setType(value, valueType)
+	entry := &descriptorpb.DescriptorProto{
+		Name:    proto.String(entryName),
+		Field:   []*descriptorpb.FieldDescriptorProto{key, value},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+		TypeName: proto.String(entryName),
+	}
+	return field, entry, nil
+}
+
+func (p *parser) parseEnum() (*descriptorpb.EnumDescriptorProto, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	enum := &descriptorpb.EnumDescriptorProto{Name: proto.String(name)}
+	for {
+		switch token := p.peek(); token {
+		case "":
+			return nil, p.unexpected("", "}")
+		case "}":
+			p.next()
+			return enum, nil
+		case ";":
+			p.next()
+		case "option", "reserved":
+			if err := p.skip(); err != nil {
+				return nil, err
+			}
+		default:
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			number, err := p.number()
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid number of enum value %s", name)
+			}
+			if err := p.options(); err != nil {
+				return nil, err
+			}
+			enum.Value = append(enum.Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String(name), Number: proto.Int32(number)})
+		}
+	}
+}
+
+// number consumes the = and the number of a field or an enum value
+func (p *parser) number() (int32, error) {
+	if err := p.expect("="); err != nil {
+		return 0, err
+	}
+	token := p.next()
+	if token == "-" {
+		token += p.next()
+	}
+	number, err := strconv.ParseInt(token, 0, 32)
+	if err != nil {
+		return 0, err
+	}
+	return int32(number), nil
+}
+
+// options consumes the options of a field or an enum value and its semicolon
+func (p *parser) options() error {
+	if p.peek() == "[" {
+		for {
+			token := p.next()
+			if token == "" {
+				return p.unexpected("", "]")
+			}
+			if token == "]" {
+				break
+			}
+		}
+	}
+	return p.expect(";")
+}
+
+// setType sets the scalar type of the field or the name of its message or enum
+func setType(field *descriptorpb.FieldDescriptorProto, kind string) {
+	if scalar, ok := scalarTypes[kind]; ok {
+		field.Type = scalar.Enum()
+		return
+	}
+	// the kind of the named types is resolved when the file is built
+	field.TypeName = proto.String(kind)
+}
+
+// entryName returns the name of the entry message of a map field
+func entryName(field string) string {
+	var builder strings.Builder
+	upper := true
+	for _, r := range field {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String() + "Entry"
+}
+
+// tokenize splits a definition to its identifiers, numbers, strings and symbols
+func tokenize(definition string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(definition); {
+		c := definition[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case strings.HasPrefix(definition[i:], "//"):
+			end := strings.IndexByte(definition[i:], '\n')
+			if end == -1 {
+				return tokens, nil
+			}
+			i += end
+		case strings.HasPrefix(definition[i:], "/*"):
+			end := strings.Index(definition[i+2:], "*/")
+			if end == -1 {
+				return nil, errors.New("unterminated comment")
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(definition) && definition[end] != c {
+				if definition[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(definition) {
+				return nil, errors.New("unterminated string")
+			}
+			tokens = append(tokens, definition[i:end+1])
+			i = end + 1
+		case isIdentifierByte(c):
+			end := i
+			for end < len(definition) && (isIdentifierByte(definition[end]) || definition[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, definition[i:end])
+			i = end
+		case c == '.':
+			// fully qualified type names
+			end := i + 1
+			for end < len(definition) && (isIdentifierByte(definition[end]) || definition[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, definition[i:end])
+			i = end
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// isIdentifier returns true if the token is a name or a qualified name
+func isIdentifier(token string) bool {
+	if token[0] >= '0' && token[0] <= '9' {
+		return false
+	}
+	return token[0] == '.' || isIdentifierByte(token[0])
+}
+
+// unquote returns the value of a string token
+func unquote(token string) string {
+	if len(token) >= 2 && (token[0] == '"' || token[0] == '\'') {
+		return token[1 : len(token)-1]
+	}
+	return token
+}
@@ -0,0 +1,296 @@
+// Package protobuf encodes the protobuf and gRPC-web bodies of the http templates
+// and decodes their responses for the matchers and extractors
+package protobuf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// well-known types available to the imports of the proto definitions
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// content types of the encoded bodies
+const (
+	// ContentTypeProtobuf is the Content-Type of the protobuf bodies
+	ContentTypeProtobuf = "application/x-protobuf"
+	// ContentTypeGRPCWeb is the Content-Type of the gRPC-web bodies
+	ContentTypeGRPCWeb = "application/grpc-web+proto"
+	// ContentTypeGRPCWebText is the Content-Type of the base64 gRPC-web bodies
+	ContentTypeGRPCWebText = "application/grpc-web-text"
+)
+
+// trailerFlag is the flag of the gRPC-web frames containing the trailers
+const trailerFlag = 0x80
+
+// Config contains the protobuf messages of a request.
+//
+// The body of the request is the protobuf JSON of the request message, it is
+// encoded after the evaluation of its variables.
+type Config struct {
+	// description: |
+	//   Proto is the .proto definition of the messages.
+	//
+	//   The messages, enums and imports of well-known types are supported,
+	//   proto3 is used unless the syntax is set.
+	// examples:
+	//   - value: "\"message LoginRequest { string username = 1; string password = 2; }\""
+	Proto string `yaml:"proto,omitempty" json:"proto,omitempty" jsonschema:"title=proto definition,description=.proto definition of the messages"`
+	// description: |
+	//   Descriptor is the base64 encoded FileDescriptorSet of the messages.
+	//
+	//   It is generated with protoc --include_imports --descriptor_set_out.
+	Descriptor string `yaml:"descriptor,omitempty" json:"descriptor,omitempty" jsonschema:"title=descriptor set,description=Base64 encoded FileDescriptorSet of the messages"`
+	// description: |
+	//   Request is the name of the message of the request body.
+	// examples:
+	//   - value: "\"LoginRequest\""
+	Request string `yaml:"request,omitempty" json:"request,omitempty" jsonschema:"title=request message,description=Name of the message of the request body"`
+	// description: |
+	//   Response is the name of the message of the response body.
+	//
+	//   The response is decoded to the protobuf part as JSON, the fields of
+	//   unknown responses are decoded by their numbers.
+	// examples:
+	//   - value: "\"LoginResponse\""
+	Response string `yaml:"response,omitempty" json:"response,omitempty" jsonschema:"title=response message,description=Name of the message of the response body"`
+	// description: |
+	//   GRPCWeb frames the messages with gRPC-web.
+	//
+	//   The status and the message of the trailers are available as
+	//   grpc_status and grpc_message.
+	GRPCWeb bool `yaml:"grpc-web,omitempty" json:"grpc-web,omitempty" jsonschema:"title=grpc-web framing,description=Frames the messages with gRPC-web"`
+
+	request  protoreflect.MessageDescriptor
+	response protoreflect.MessageDescriptor
+}
+
+// Compile parses the definitions and resolves the messages of the config
+func (c *Config) Compile() error {
+	if (c.Proto == "") == (c.Descriptor == "") {
+		return errors.New("one of proto or descriptor is required")
+	}
+	if c.Request == "" {
+		return errors.New("request message is required")
+	}
+
+	var files *protoregistry.Files
+	var pkg string
+	if c.Proto != "" {
+		file, err := Parse(c.Proto)
+		if err != nil {
+			return errors.Wrap(err, "could not parse proto")
+		}
+		pkg = file.GetPackage()
+		// the imports are resolved with the well-known types
+		descriptor, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+		if err != nil {
+			return errors.Wrap(err, "could not build proto")
+		}
+		files = &protoregistry.Files{}
+		if err := files.RegisterFile(descriptor); err != nil {
+			return errors.Wrap(err, "could not build proto")
+		}
+	} else {
+		data, err := base64.StdEncoding.DecodeString(c.Descriptor)
+		if err != nil {
+			return errors.Wrap(err, "could not decode descriptor")
+		}
+		set := &descriptorpb.FileDescriptorSet{}
+		if err := proto.Unmarshal(data, set); err != nil {
+			return errors.Wrap(err, "could not parse descriptor")
+		}
+		if files, err = protodesc.NewFiles(set); err != nil {
+			return errors.Wrap(err, "could not build descriptor")
+		}
+	}
+
+	var err error
+	if c.request, err = findMessage(files, pkg, c.Request); err != nil {
+		return err
+	}
+	if c.Response != "" {
+		if c.response, err = findMessage(files, pkg, c.Response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findMessage returns the message of the name, relative to the package of the proto
+func findMessage(files *protoregistry.Files, pkg, name string) (protoreflect.MessageDescriptor, error) {
+	names := []string{strings.TrimPrefix(name, ".")}
+	if pkg != "" && !strings.HasPrefix(name, ".") {
+		names = append(names, pkg+"."+name)
+	}
+	for _, name := range names {
+		descriptor, err := files.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			continue
+		}
+		if message, ok := descriptor.(protoreflect.MessageDescriptor); ok {
+			return message, nil
+		}
+	}
+	return nil, errors.Errorf("message %s not found", name)
+}
+
+// ContentType returns the Content-Type of the encoded bodies
+func (c *Config) ContentType() string {
+	if c.GRPCWeb {
+		return ContentTypeGRPCWeb
+	}
+	return ContentTypeProtobuf
+}
+
+// Encode encodes the protobuf JSON of the request message, framed for gRPC-web if enabled
+func (c *Config) Encode(body string) ([]byte, error) {
+	message := dynamicpb.NewMessage(c.request)
+	if strings.TrimSpace(body) != "" {
+		if err := protojson.Unmarshal([]byte(body), message); err != nil {
+			return nil, errors.Wrapf(err, "could not parse %s", c.request.FullName())
+		}
+	}
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not encode %s", c.request.FullName())
+	}
+	if !c.GRPCWeb {
+		return data, nil
+	}
+	return frame(0, data), nil
+}
+
+// frame returns the gRPC-web frame of the data
+func frame(flag byte, data []byte) []byte {
+	framed := make([]byte, 5, 5+len(data))
+	framed[0] = flag
+	binary.BigEndian.PutUint32(framed[1:], uint32(len(data)))
+	return append(framed, data...)
+}
+
+// Decode returns the dsl values of the response body: protobuf is the JSON of
+// the first message and grpc_status and grpc_message are the gRPC-web status
+func (c *Config) Decode(header http.Header, body []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	message := body
+	if c.GRPCWeb {
+		if strings.HasPrefix(header.Get("Content-Type"), ContentTypeGRPCWebText) {
+			decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(body)))
+			if err != nil {
+				return values, errors.Wrap(err, "could not decode grpc-web-text body")
+			}
+			body = decoded
+		}
+		messages, trailers, err := unframe(body)
+		// trailers-only responses send the status in the headers
+		for _, name := range []string{"grpc-status", "grpc-message"} {
+			if value := header.Get(name); value != "" {
+				if _, ok := trailers[name]; !ok {
+					trailers[name] = value
+				}
+			}
+		}
+		if status, ok := trailers["grpc-status"]; ok {
+			if code, err := strconv.Atoi(status); err == nil {
+				values["grpc_status"] = code
+			} else {
+				values["grpc_status"] = status
+			}
+		}
+		if text, ok := trailers["grpc-message"]; ok {
+			if unescaped, err := url.PathUnescape(text); err == nil {
+				text = unescaped
+			}
+			values["grpc_message"] = text
+		}
+		if err != nil {
+			return values, err
+		}
+		if len(messages) == 0 {
+			return values, nil
+		}
+		message = messages[0]
+	} else if len(body) == 0 {
+		return values, nil
+	}
+
+	decoded, err := c.decodeMessage(message)
+	if err != nil {
+		return values, err
+	}
+	values["protobuf"] = decoded
+	return values, nil
+}
+
+// decodeMessage returns the JSON of a response message
+func (c *Config) decodeMessage(data []byte) (string, error) {
+	if c.response == nil {
+		fields, err := decodeRaw(data)
+		if err != nil {
+			return "", errors.Wrap(err, "could not decode message")
+		}
+		encoded, err := json.Marshal(fields)
+		return string(encoded), err
+	}
+	message := dynamicpb.NewMessage(c.response)
+	if err := proto.Unmarshal(data, message); err != nil {
+		return "", errors.Wrapf(err, "could not decode %s", c.response.FullName())
+	}
+	encoded, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(message)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not encode %s", c.response.FullName())
+	}
+	return string(encoded), nil
+}
+
+// unframe returns the messages and the trailers of a gRPC-web body
+func unframe(body []byte) ([][]byte, map[string]string, error) {
+	var messages [][]byte
+	trailers := make(map[string]string)
+	for len(body) > 0 {
+		if len(body) < 5 {
+			return messages, trailers, errors.New("truncated grpc-web frame")
+		}
+		flag, length := body[0], binary.BigEndian.Uint32(body[1:5])
+		if uint64(len(body)-5) < uint64(length) {
+			return messages, trailers, errors.New("truncated grpc-web frame")
+		}
+		data := body[5 : 5+length]
+		body = body[5+length:]
+
+		switch {
+		case flag&trailerFlag != 0:
+			for _, line := range strings.Split(string(data), "\r\n") {
+				if name, value, ok := strings.Cut(line, ":"); ok {
+					trailers[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+				}
+			}
+		case flag&0x01 != 0:
+			return messages, trailers, errors.New("compressed grpc-web messages are not supported")
+		default:
+			messages = append(messages, data)
+		}
+	}
+	return messages, trailers, nil
+}
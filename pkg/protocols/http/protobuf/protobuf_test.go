@@ -0,0 +1,138 @@
+package protobuf
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const definition = `
+syntax = "proto3";
+package api.v1;
+
+import "google/protobuf/timestamp.proto";
+
+// LoginRequest logs in a user
+message LoginRequest {
+  string username = 1;
+  string password = 2 [deprecated = true];
+  repeated int32 roles = 3;
+  map<string, string> labels = 4;
+  Device device = 5;
+  optional bool remember = 6;
+  oneof otp {
+    string code = 7;
+    int64 token = 8;
+  }
+  google.protobuf.Timestamp time = 9;
+
+  message Device {
+    string name = 1;
+    Kind kind = 2;
+  }
+  reserved 10 to 12;
+}
+
+enum Kind {
+  KIND_UNKNOWN = 0;
+  KIND_MOBILE = 1;
+}
+
+message LoginResponse {
+  string token = 1;
+  Kind kind = 2;
+}
+
+service Auth {
+  rpc Login(LoginRequest) returns (LoginResponse) {}
+}
+`
+
+func TestEncodeDecode(t *testing.T) {
+	config := &Config{Proto: definition, Request: "LoginRequest", Response: "LoginRequest"}
+	require.Nil(t, config.Compile(), "could not compile config")
+	require.Equal(t, ContentTypeProtobuf, config.ContentType())
+
+	body := `{"username":"admin","roles":[1,2],"labels":{"env":"prod"},"device":{"name":"pixel","kind":"KIND_MOBILE"},"remember":false,"code":"123456","time":"2024-01-01T00:00:00Z"}`
+	data, err := config.Encode(body)
+	require.Nil(t, err, "could not encode body")
+
+	values, err := config.Decode(http.Header{}, data)
+	require.Nil(t, err, "could not decode body")
+	require.JSONEq(t, body, values["protobuf"].(string))
+
+	_, err = config.Encode(`{"unknown":1}`)
+	require.ErrorContains(t, err, "could not parse api.v1.LoginRequest")
+}
+
+func TestGRPCWeb(t *testing.T) {
+	config := &Config{Proto: definition, Request: "LoginRequest", Response: "LoginResponse", GRPCWeb: true}
+	require.Nil(t, config.Compile(), "could not compile config")
+	require.Equal(t, ContentTypeGRPCWeb, config.ContentType())
+
+	data, err := config.Encode(`{"username":"a"}`)
+	require.Nil(t, err, "could not encode body")
+	require.Equal(t, []byte{0, 0, 0, 0, 3, 0x0a, 0x01, 'a'}, data)
+
+	response := append(frame(0, []byte{0x0a, 0x02, 't', '1', 0x10, 0x01}), frame(trailerFlag, []byte("grpc-status: 0\r\ngrpc-message: OK%20done\r\n"))...)
+	values, err := config.Decode(http.Header{}, response)
+	require.Nil(t, err, "could not decode response")
+	require.JSONEq(t, `{"token":"t1","kind":"KIND_MOBILE"}`, values["protobuf"].(string))
+	require.Equal(t, 0, values["grpc_status"])
+	require.Equal(t, "OK done", values["grpc_message"])
+
+	header := http.Header{"Content-Type": []string{ContentTypeGRPCWebText}, "Grpc-Status": []string{"16"}}
+	values, err = config.Decode(header, []byte(base64.StdEncoding.EncodeToString(nil)))
+	require.Nil(t, err, "could not decode trailers-only response")
+	require.Equal(t, 16, values["grpc_status"])
+	require.NotContains(t, values, "protobuf")
+
+	values, err = config.Decode(http.Header{}, []byte{0, 0, 0, 0, 9})
+	require.EqualError(t, err, "truncated grpc-web frame")
+	require.Empty(t, values)
+}
+
+func TestDecodeRaw(t *testing.T) {
+	config := &Config{Proto: definition, Request: "LoginRequest"}
+	require.Nil(t, config.Compile(), "could not compile config")
+
+	data, err := config.Encode(`{"username":"admin","roles":[1],"device":{"name":"pixel","kind":"KIND_MOBILE"},"code":"x"}`)
+	require.Nil(t, err, "could not encode body")
+	values, err := config.Decode(http.Header{}, data)
+	require.Nil(t, err, "could not decode body")
+	require.JSONEq(t, `{"1":"admin","3":"AQ==","5":{"1":"pixel","2":1},"7":"x"}`, values["protobuf"].(string))
+
+	fields, err := decodeRaw([]byte{0x08, 0x01, 0x08, 0x02, 0x12, 0x02, 0xff, 0xfe})
+	require.Nil(t, err)
+	require.Equal(t, map[string]interface{}{"1": []interface{}{uint64(1), uint64(2)}, "2": "//4="}, fields)
+}
+
+func TestDescriptor(t *testing.T) {
+	file, err := Parse(definition)
+	require.Nil(t, err, "could not parse proto")
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto), file}}
+	data, err := proto.Marshal(set)
+	require.Nil(t, err)
+
+	config := &Config{Descriptor: base64.StdEncoding.EncodeToString(data), Request: "api.v1.LoginResponse"}
+	require.Nil(t, config.Compile(), "could not compile descriptor")
+	encoded, err := config.Encode(`{"token":"t"}`)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x0a, 0x01, 't'}, encoded)
+}
+
+func TestCompile(t *testing.T) {
+	require.EqualError(t, (&Config{Request: "A"}).Compile(), "one of proto or descriptor is required")
+	require.EqualError(t, (&Config{Proto: definition}).Compile(), "request message is required")
+	require.EqualError(t, (&Config{Proto: definition, Request: "Missing"}).Compile(), "message Missing not found")
+	require.ErrorContains(t, (&Config{Proto: "message A { Missing b = 1; }", Request: "A"}).Compile(), "could not build proto")
+	require.ErrorContains(t, (&Config{Proto: "message A { string b = 1 }", Request: "A"}).Compile(), `unexpected "}", expected ";"`)
+	require.ErrorContains(t, (&Config{Proto: "message A { required string b = 1; }", Request: "A"}).Compile(), "required fields are not allowed in proto3")
+	require.Nil(t, (&Config{Proto: "syntax = 'proto2'; message A { required string b = 1; }", Request: "A"}).Compile())
+}
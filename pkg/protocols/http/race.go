@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/race"
+)
+
+// RaceModeSinglePacket sends the race condition requests with the HTTP/2 single-packet attack
+const RaceModeSinglePacket = "single-packet"
+
+// sendSinglePacket sends the requests with the single-packet attack
+// and stores the responses in the generated requests
+func (request *Request) sendSinglePacket(generatedRequests []*generatedRequest) error {
+	if len(generatedRequests) == 0 {
+		return nil
+	}
+	requests := make([]*http.Request, 0, len(generatedRequests))
+	for _, generatedRequest := range generatedRequests {
+		request.setCustomHeaders(generatedRequest)
+		if err := request.handleSignature(generatedRequest); err != nil {
+			return err
+		}
+		requests = append(requests, generatedRequest.request.Request)
+	}
+
+	timeout := time.Duration(request.options.Options.Timeout) * time.Second
+	conn, err := request.dialHTTP2(requests[0].URL, timeout)
+	if err != nil {
+		return errors.Wrap(err, "could not connect for single-packet race")
+	}
+	defer conn.Close()
+
+	responses, err := race.SinglePacket(conn, requests, timeout)
+	if err != nil {
+		return errors.Wrap(err, "could not send single-packet race requests")
+	}
+	for i, response := range responses {
+		generatedRequests[i].singlePacket = response
+	}
+	return nil
+}
+
+// dialHTTP2 returns a connection speaking HTTP/2 to the host of the url,
+// negotiated with ALPN for https urls and with prior knowledge for http urls
+func (request *Request) dialHTTP2(target *url.URL, timeout time.Duration) (net.Conn, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	address := target.Host
+	if target.Port() == "" {
+		port := "443"
+		if target.Scheme == "http" {
+			port = "80"
+		}
+		address = net.JoinHostPort(target.Hostname(), port)
+	}
+	if target.Scheme == "http" {
+		return httpclientpool.Dialer.Dial(ctx, "tcp", address)
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS12,
+		NextProtos:         []string{"h2"},
+	}
+	if net.ParseIP(target.Hostname()) == nil {
+		config.ServerName = target.Hostname()
+	}
+	if err := request.TLS.Merge(request.options.Options.TLSConfig()).Apply(config); err != nil {
+		return nil, err
+	}
+	conn, err := httpclientpool.Dialer.DialTLSWithConfig(ctx, "tcp", address, config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConn, ok := conn.(*tls.Conn); ok && tlsConn.ConnectionState().NegotiatedProtocol != "h2" {
+		conn.Close()
+		return nil, errors.New("server does not support http/2")
+	}
+	return conn, nil
+}
@@ -0,0 +1,275 @@
+package race
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+const (
+	// maxFrameSize is the default maximum size of the http/2 frames
+	maxFrameSize = 16384
+	// maxBodySize is the default initial flow-control window of the streams
+	maxBodySize = 65535
+	// windowSize is the flow-control window advertised to the server
+	windowSize = 1 << 24
+	// syncDelay lets the frames sent before the last bytes reach the server
+	syncDelay = 100 * time.Millisecond
+)
+
+// connectionHeaders are the connection-specific headers forbidden with http/2
+var connectionHeaders = []string{"connection", "host", "keep-alive", "proxy-connection", "transfer-encoding", "upgrade"}
+
+// Response is the response of a request sent with the single-packet attack
+type Response struct {
+	Response *http.Response
+	Err      error
+	// Time is the time between the last packet and the end of the response
+	Time time.Duration
+	// Delta is the time between the first response of the attack and the response
+	Delta time.Duration
+	// Index is the arrival order of the response
+	Index int
+}
+
+// SinglePacket sends the requests on the http/2 connection with the single-packet attack.
+//
+// The requests are sent on concurrent streams without their last byte, the last
+// bytes of all the requests are then written at once to complete the requests in
+// the same packet. The responses are returned in the order of the requests.
+func SinglePacket(conn net.Conn, requests []*http.Request, timeout time.Duration) ([]*Response, error) {
+	if timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(timeout))
+	}
+	bodies := make([][]byte, len(requests))
+	for i, req := range requests {
+		if req.Body == nil {
+			continue
+		}
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > maxBodySize {
+			return nil, fmt.Errorf("request body of %d bytes is too large for the single-packet attack", len(body))
+		}
+		bodies[i] = body
+	}
+
+	if _, err := io.WriteString(conn, http2.ClientPreface); err != nil {
+		return nil, err
+	}
+	framer := http2.NewFramer(conn, conn)
+	framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+	if err := framer.WriteSettings(http2.Setting{ID: http2.SettingEnablePush}, http2.Setting{ID: http2.SettingInitialWindowSize, Val: windowSize}); err != nil {
+		return nil, err
+	}
+	if err := framer.WriteWindowUpdate(0, windowSize-maxBodySize); err != nil {
+		return nil, err
+	}
+
+	headers := &bytes.Buffer{}
+	encoder := hpack.NewEncoder(headers)
+	for i, req := range requests {
+		headers.Reset()
+		encodeHeaders(encoder, req, len(bodies[i]))
+		if headers.Len() > maxFrameSize {
+			return nil, fmt.Errorf("request headers of %d bytes are too large for the single-packet attack", headers.Len())
+		}
+		streamID := uint32(2*i + 1)
+		if err := framer.WriteHeaders(http2.HeadersFrameParam{StreamID: streamID, BlockFragment: headers.Bytes(), EndHeaders: true}); err != nil {
+			return nil, err
+		}
+		if len(bodies[i]) < 2 {
+			continue
+		}
+		for data := bodies[i][:len(bodies[i])-1]; len(data) > 0; {
+			n := min(len(data), maxFrameSize)
+			if err := framer.WriteData(streamID, false, data[:n]); err != nil {
+				return nil, err
+			}
+			data = data[n:]
+		}
+	}
+	time.Sleep(syncDelay)
+
+	last := &bytes.Buffer{}
+	lastFramer := http2.NewFramer(last, nil)
+	for i, body := range bodies {
+		var data []byte
+		if len(body) > 0 {
+			data = body[len(body)-1:]
+		}
+		if err := lastFramer.WriteData(uint32(2*i+1), true, data); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := conn.Write(last.Bytes()); err != nil {
+		return nil, err
+	}
+	return readResponses(framer, requests, time.Now()), nil
+}
+
+// encodeHeaders writes the header block of the request
+func encodeHeaders(encoder *hpack.Encoder, req *http.Request, bodySize int) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	_ = encoder.WriteField(hpack.HeaderField{Name: ":method", Value: req.Method})
+	_ = encoder.WriteField(hpack.HeaderField{Name: ":scheme", Value: req.URL.Scheme})
+	_ = encoder.WriteField(hpack.HeaderField{Name: ":authority", Value: host})
+	_ = encoder.WriteField(hpack.HeaderField{Name: ":path", Value: req.URL.RequestURI()})
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if isConnectionHeader(name) || (name == "content-length" && bodySize > 0) {
+			continue
+		}
+		for _, value := range values {
+			_ = encoder.WriteField(hpack.HeaderField{Name: name, Value: value})
+		}
+	}
+	if bodySize > 0 {
+		_ = encoder.WriteField(hpack.HeaderField{Name: "content-length", Value: strconv.Itoa(bodySize)})
+	}
+}
+
+// readResponses reads the responses of the requests until every stream is closed
+func readResponses(framer *http2.Framer, requests []*http.Request, start time.Time) []*Response {
+	responses := make([]*Response, len(requests))
+	bodies := make([]bytes.Buffer, len(requests))
+	for i := range responses {
+		responses[i] = &Response{Index: -1}
+	}
+
+	var received int
+	pending := len(requests)
+	stream := func(streamID uint32) *Response {
+		i := int(streamID-1) / 2
+		if streamID%2 == 0 || i >= len(responses) || responses[i].Index != -1 || responses[i].Err != nil {
+			return nil
+		}
+		return responses[i]
+	}
+	fail := func(response *Response, err error) {
+		response.Err = err
+		pending--
+	}
+	finish := func(streamID uint32) {
+		i := int(streamID-1) / 2
+		response := responses[i]
+		response.Time = time.Since(start)
+		response.Index = received
+		received++
+		pending--
+		if response.Response == nil {
+			response.Err = fmt.Errorf("stream %d closed without response", streamID)
+			return
+		}
+		response.Response.Request = requests[i]
+		response.Response.Body = io.NopCloser(bytes.NewReader(bodies[i].Bytes()))
+		response.Response.ContentLength = int64(bodies[i].Len())
+	}
+
+	for pending > 0 {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			// the streams without response fail with the connection
+			for _, response := range responses {
+				if response.Index == -1 && response.Err == nil {
+					fail(response, err)
+				}
+			}
+			break
+		}
+		switch f := frame.(type) {
+		case *http2.SettingsFrame:
+			if !f.IsAck() {
+				_ = framer.WriteSettingsAck()
+			}
+		case *http2.PingFrame:
+			if !f.IsAck() {
+				_ = framer.WritePing(true, f.Data)
+			}
+		case *http2.GoAwayFrame:
+			for i, response := range responses {
+				if uint32(2*i+1) > f.LastStreamID && response.Index == -1 && response.Err == nil {
+					fail(response, fmt.Errorf("connection closed by the server: %s", f.ErrCode))
+				}
+			}
+		case *http2.RSTStreamFrame:
+			if response := stream(f.StreamID); response != nil {
+				fail(response, fmt.Errorf("stream reset by the server: %s", f.ErrCode))
+			}
+		case *http2.MetaHeadersFrame:
+			response := stream(f.StreamID)
+			if response == nil {
+				continue
+			}
+			if response.Response == nil {
+				status, _ := strconv.Atoi(f.PseudoValue("status"))
+				if status >= 100 && status < 200 {
+					// informational responses precede the final response
+					continue
+				}
+				response.Response = &http.Response{
+					Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+					StatusCode: status,
+					Proto:      "HTTP/2.0",
+					ProtoMajor: 2,
+					Header:     http.Header{},
+				}
+			}
+			for _, field := range f.RegularFields() {
+				response.Response.Header.Add(http.CanonicalHeaderKey(field.Name), field.Value)
+			}
+			if f.StreamEnded() {
+				finish(f.StreamID)
+			}
+		case *http2.DataFrame:
+			response := stream(f.StreamID)
+			if response == nil {
+				continue
+			}
+			if data := f.Data(); len(data) > 0 {
+				bodies[(f.StreamID-1)/2].Write(data)
+				_ = framer.WriteWindowUpdate(0, uint32(len(data)))
+			}
+			if f.StreamEnded() {
+				finish(f.StreamID)
+			}
+		}
+	}
+
+	var first time.Duration = -1
+	for _, response := range responses {
+		if response.Err == nil && (first == -1 || response.Time < first) {
+			first = response.Time
+		}
+	}
+	for _, response := range responses {
+		if response.Err == nil {
+			response.Delta = response.Time - first
+		}
+	}
+	return responses
+}
+
+// isConnectionHeader returns true if the header is specific to http/1 connections
+func isConnectionHeader(name string) bool {
+	for _, header := range connectionHeaders {
+		if name == header {
+			return true
+		}
+	}
+	return false
+}
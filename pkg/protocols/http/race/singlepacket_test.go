@@ -0,0 +1,69 @@
+package race
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinglePacket(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Proto", r.Proto)
+		_, _ = w.Write([]byte(r.URL.Path + ":" + string(body)))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	conn, err := tls.Dial("tcp", ts.Listener.Addr().String(), &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"h2"}})
+	require.Nil(t, err)
+	defer conn.Close()
+	require.Equal(t, "h2", conn.ConnectionState().NegotiatedProtocol)
+
+	var requests []*http.Request
+	for _, path := range []string{"/a", "/b", "/c"} {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+path, strings.NewReader("coupon"))
+		require.Nil(t, err)
+		req.Header.Set("Connection", "close")
+		requests = append(requests, req)
+	}
+	get, err := http.NewRequest(http.MethodGet, ts.URL+"/d", nil)
+	require.Nil(t, err)
+	requests = append(requests, get)
+
+	responses, err := SinglePacket(conn, requests, 10*time.Second)
+	require.Nil(t, err)
+	require.Len(t, responses, 4)
+
+	indexes := map[int]bool{}
+	for i, response := range responses {
+		require.Nil(t, response.Err)
+		require.Equal(t, http.StatusOK, response.Response.StatusCode)
+		require.Equal(t, "HTTP/2.0", response.Response.Header.Get("X-Proto"))
+		body, err := io.ReadAll(response.Response.Body)
+		require.Nil(t, err)
+		expected := requests[i].URL.Path + ":coupon"
+		if i == 3 {
+			expected = "/d:"
+		}
+		require.Equal(t, expected, string(body))
+		require.GreaterOrEqual(t, response.Delta, time.Duration(0))
+		require.LessOrEqual(t, response.Delta, response.Time)
+		indexes[response.Index] = true
+	}
+	require.Equal(t, map[int]bool{0: true, 1: true, 2: true, 3: true}, indexes)
+}
+
+func TestSinglePacketBodyTooLarge(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://example.com", strings.NewReader(strings.Repeat("a", maxBodySize+1)))
+	require.Nil(t, err)
+	_, err = SinglePacket(nil, []*http.Request{req}, 0)
+	require.EqualError(t, err, "request body of 65536 bytes is too large for the single-packet attack")
+}
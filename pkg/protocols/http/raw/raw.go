@@ -86,7 +86,7 @@ func Parse(request string, inputURL *urlutil.URL, unsafe, disablePathAutomerge b
 			unsafeRelativePath = cloned.GetRelativePath()
 		}
 		rawrequest.Path = cloned.GetRelativePath()
-		rawrequest.UnsafeRawBytes = bytes.Replace(rawrequest.UnsafeRawBytes, []byte(prevPath), []byte(unsafeRelativePath), 1)
+		rawrequest.UnsafeRawBytes = replaceRequestLinePath(rawrequest.UnsafeRawBytes, prevPath, unsafeRelativePath)
 
 	default:
 		cloned := inputURL.Clone()
@@ -148,9 +148,9 @@ func readRawRequest(request string, unsafe bool) (*Request, error) {
 		Headers: make(map[string]string),
 	}
 
-	// store body if it is unsafe request
+	// store body if it is unsafe request, the annotations are not sent
 	if unsafe {
-		rawRequest.UnsafeRawBytes = []byte(request)
+		rawRequest.UnsafeRawBytes = []byte(trimAnnotations(request))
 	}
 
 	// parse raw request
@@ -237,12 +237,42 @@ read_line:
 
 }
 
+// trimAnnotations removes the annotations at the start of the request
+func trimAnnotations(request string) string {
+	for strings.HasPrefix(request, "@") {
+		index := strings.IndexByte(request, '\n')
+		if index == -1 {
+			return ""
+		}
+		request = request[index+1:]
+	}
+	return request
+}
+
+// replaceRequestLinePath replaces the path of the request line, the headers
+// and the body of the request are kept as they are
+func replaceRequestLinePath(data []byte, path, replacement string) []byte {
+	end := bytes.IndexByte(data, '\n')
+	if end == -1 {
+		end = len(data)
+	}
+	var buf bytes.Buffer
+	buf.Write(bytes.Replace(data[:end], []byte(path), []byte(replacement), 1))
+	buf.Write(data[end:])
+	return buf.Bytes()
+}
+
 // TryFillCustomHeaders after the Host header
 func (r *Request) TryFillCustomHeaders(headers []string) error {
 	unsafeBytes := bytes.ToLower(r.UnsafeRawBytes)
-	// locate first host header
-	hostHeaderIndex := bytes.Index(unsafeBytes, []byte("host:"))
+	// locate first host header of the headers section
+	headersEnd := bytes.Index(unsafeBytes, []byte("\r\n\r\n"))
+	if headersEnd == -1 {
+		headersEnd = len(unsafeBytes)
+	}
+	hostHeaderIndex := bytes.Index(unsafeBytes[:headersEnd], []byte("\r\nhost:"))
 	if hostHeaderIndex > 0 {
+		hostHeaderIndex += 2
 		// attempt to locate next newline
 		newLineIndex := bytes.Index(unsafeBytes[hostHeaderIndex:], []byte("\r\n"))
 		if newLineIndex > 0 {
@@ -84,6 +84,21 @@ Connection: close`, parseURL(t, "https://test.com/test/"), true, false)
 	require.Contains(t, string(request.UnsafeRawBytes), "GET /test.js?a=b", "Could not parse unsafe method request path correctly")
 }
 
+func TestParseUnsafeRequestLayout(t *testing.T) {
+	request, err := Parse("@timeout: 10s\r\nGET /admin HTTP/1.1\r\nhOST: {{Hostname}}\r\nReferer: /admin\r\nX-a: 1\r\nx-A: 2\r\n\r\n", parseURL(t, "https://test.com/app/"), true, false)
+	require.Nil(t, err, "could not parse unsafe request")
+	require.Equal(t, "GET /app/admin HTTP/1.1\r\nhOST: {{Hostname}}\r\nReferer: /admin\r\nX-a: 1\r\nx-A: 2\r\n\r\n", string(request.UnsafeRawBytes), "could not keep the layout of the unsafe request")
+
+	err = request.TryFillCustomHeaders([]string{"test: test"})
+	require.Nil(t, err, "could not add custom headers")
+	require.Equal(t, "GET /app/admin HTTP/1.1\r\nhOST: {{Hostname}}\r\ntest: test\r\nReferer: /admin\r\nX-a: 1\r\nx-A: 2\r\n\r\n", string(request.UnsafeRawBytes))
+
+	// the host header is only looked for in the headers
+	request, err = Parse("GET /?host:a HTTP/1.1\r\nX: 1\r\n\r\nHost: body", parseURL(t, "https://test.com"), true, false)
+	require.Nil(t, err, "could not parse unsafe request")
+	require.NotNil(t, request.TryFillCustomHeaders([]string{"test: test"}), "could add custom headers without host header")
+}
+
 func TestTryFillCustomHeaders(t *testing.T) {
 	testValue := "GET /manager/html HTTP/1.1\r\nHost: Test\r\n"
 	expected := "GET /test/manager/html HTTP/1.1\r\nHost: Test\r\ntest: test\r\n"
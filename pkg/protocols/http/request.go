@@ -21,6 +21,7 @@ import (
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
@@ -34,6 +35,8 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/signer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/signerpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/smuggling"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/stream"
 	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/rawhttp"
@@ -99,6 +102,11 @@ func (request *Request) executeRaceRequest(input *contextargs.Context, previous
 		}
 		generatedRequests = append(generatedRequests, generatedRequest)
 	}
+	if request.RaceMode == RaceModeSinglePacket {
+		if err := request.sendSinglePacket(generatedRequests); err != nil {
+			return err
+		}
+	}
 
 	wg := sync.WaitGroup{}
 	var requestErr error
@@ -245,6 +253,10 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 		if request.options.HostErrorsCache != nil && request.options.HostErrorsCache.Check(input.MetaInput.Input) {
 			return false
 		}
+		if request.options.Robots != nil && !request.options.Robots.Allowed(gr.Request.URL.URL) {
+			gologger.Verbose().Msgf("[%s] Skipped %s disallowed by robots.txt\n", request.options.TemplateID, gr.Request.URL.String())
+			return true
+		}
 		request.options.RateLimiter.Take()
 		req := &generatedRequest{
 			request:        gr.Request,
@@ -299,7 +311,7 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 		if !result {
 			break
 		}
-		generated, err := generator.Make(context.Background(), input, value, payloads, nil)
+		generated, err := generator.Make(request.newContext(input), input, value, payloads, nil)
 		if err != nil {
 			continue
 		}
@@ -323,6 +335,11 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 
 // ExecuteWithResults executes the final request on a URL
 func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	// skip inputs where a port scanner detected a non http service
+	if !request.SelfContained && !input.MetaInput.SupportsHTTP() {
+		request.options.Progress.AddToTotal(-int64(request.Requests()))
+		return nil
+	}
 	if request.Pipeline || request.Race && request.RaceNumberRequests > 0 || request.Threads > 0 {
 		variablesMap := request.options.Variables.Evaluate(generators.MergeMaps(dynamicValues, previous))
 		dynamicValues = generators.MergeMaps(variablesMap, dynamicValues, request.options.Constants)
@@ -434,6 +451,10 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 			return false, nil
 		}
 
+		// stop generating requests once the scan is cancelled
+		if input.Context().Err() != nil {
+			break
+		}
 		inputData, payloads, ok := generator.nextValue()
 		if !ok {
 			break
@@ -468,6 +489,17 @@ var errStopExecution = errors.New("stop execution due to unresolved variables")
 func (request *Request) executeRequest(input *contextargs.Context, generatedRequest *generatedRequest, previousEvent output.InternalEvent, hasInteractMatchers bool, callback protocols.OutputEventCallback, requestCount int) error {
 	request.setCustomHeaders(generatedRequest)
 
+	// the session of authenticated scans is injected in the requests, the unsafe raw requests are sent as they are
+	var sessionGeneration int
+	useSession := request.options.Session != nil && generatedRequest.request != nil && generatedRequest.singlePacket == nil && !generatedRequest.original.Pipeline
+	if useSession {
+		generation, err := request.options.Session.Inject(generatedRequest.request.Request)
+		if err != nil {
+			return errors.Wrap(err, "could not login")
+		}
+		sessionGeneration = generation
+	}
+
 	// Try to evaluate any payloads before replacement
 	finalMap := generators.MergeMaps(generatedRequest.dynamicValues, generatedRequest.meta)
 
@@ -545,6 +577,11 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	}
 	var formedURL string
 	var hostname string
+	if request.options.HostRateLimiter != nil && generatedRequest.singlePacket == nil {
+		if err := request.options.HostRateLimiter.Take(input.Context(), input.MetaInput.Input); err != nil {
+			return err
+		}
+	}
 	timeStart := time.Now()
 	if generatedRequest.original.Pipeline {
 		// if request is a pipeline request, use the pipelined client
@@ -587,10 +624,23 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		}
 		formedURL = fmt.Sprintf("%s%s", inputUrl, generatedRequest.rawRequest.Path)
 		resp, err = generatedRequest.original.rawhttpClient.DoRawWithOptions(generatedRequest.rawRequest.Method, inputUrl, generatedRequest.rawRequest.Path, generators.ExpandMapValues(generatedRequest.rawRequest.Headers), io.NopCloser(strings.NewReader(generatedRequest.rawRequest.Data)), &options)
+		if err != nil && request.Smuggling != nil && request.Smuggling.Timing && smuggling.IsTimeout(err) {
+			// the timed out timing probes are matched with an empty response and their duration
+			resp, err = smuggling.TimeoutResponse(), nil
+		}
+	} else if generatedRequest.singlePacket != nil {
+		// the response was already received with the single-packet attack
+		hostname = generatedRequest.request.URL.Host
+		formedURL = generatedRequest.request.URL.String()
+		resp, err = generatedRequest.singlePacket.Response, generatedRequest.singlePacket.Err
 	} else {
 		//** For Normal requests **//
 		hostname = generatedRequest.request.URL.Host
 		formedURL = generatedRequest.request.URL.String()
+		if generatedRequest.socket != nil {
+			// the requests to the sockets are matched at the url of the socket
+			formedURL = generatedRequest.socket.URL(formedURL)
+		}
 		// if nuclei-project is available check if the request was already sent previously
 		if request.options.ProjectFile != nil {
 			// if unavailable fail silently
@@ -606,18 +656,40 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			}
 
 			httpclient := request.httpClient
-			if input.CookieJar != nil {
-				connConfiguration := request.connConfiguration
-				connConfiguration.Connection.SetCookieJar(input.CookieJar)
-				client, err := httpclientpool.Get(request.options.Options, connConfiguration)
+			if input.CookieJar != nil || generatedRequest.socket != nil {
+				// the connection configuration is copied to not share the jar and the socket of the input with the other inputs
+				connConfiguration := *request.connConfiguration
+				if input.CookieJar != nil {
+					connConfiguration.Connection = &httpclientpool.ConnectionConfiguration{DisableKeepAlive: request.connConfiguration.Connection.DisableKeepAlive}
+					connConfiguration.Connection.SetCookieJar(input.CookieJar)
+				}
+				connConfiguration.Socket = generatedRequest.socket
+				client, err := httpclientpool.Get(request.options.Options, &connConfiguration)
 				if err != nil {
 					return errors.Wrap(err, "could not get http client")
 				}
 				httpclient = client
 			}
+			if request.options.Options.HTTPConnectionReuse != "" {
+				generatedRequest.request = httpclientpool.TraceConnections(generatedRequest.request, request.options.Progress)
+			}
 			resp, err = httpclient.Do(generatedRequest.request)
+			if err == nil && useSession && request.options.Session.Expired(resp) {
+				// the request is sent again once the login of the expired session ran again
+				_, _ = io.CopyN(io.Discard, resp.Body, drainReqSize)
+				resp.Body.Close()
+				if err = request.options.Session.Refresh(generatedRequest.request.Request, sessionGeneration); err != nil {
+					return errors.Wrap(err, "could not refresh session")
+				}
+				// the latency of the target request doesn't include the login round trip
+				timeStart = time.Now()
+				resp, err = httpclient.Do(generatedRequest.request)
+			}
 		}
 	}
+	if request.options.HostRateLimiter != nil && !fromCache && generatedRequest.singlePacket == nil {
+		request.options.HostRateLimiter.Observe(input.MetaInput.Input, time.Since(timeStart), resp, err)
+	}
 	// use request url as matched url if empty
 	if formedURL == "" {
 		formedURL = input.MetaInput.Input
@@ -695,9 +767,28 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	}
 
 	gologger.Verbose().Msgf("[%s] Sent HTTP request to %s", request.options.TemplateID, formedURL)
-	request.options.Output.Request(request.options.TemplatePath, formedURL, request.Type().String(), err)
+	// the request is logged once the response is read to know its size
+	var receivedBytes int
+	var rawResponse []byte
+	defer func() {
+		request.options.Output.LogRequest(&output.RequestEvent{
+			Template:      request.options.TemplatePath,
+			Input:         formedURL,
+			Type:          request.Type().String(),
+			BytesSent:     len(dumpedRequest),
+			BytesReceived: receivedBytes,
+			Request:       tostring.UnsafeToString(dumpedRequest),
+			Response:      tostring.UnsafeToString(rawResponse),
+		})
+	}()
 
 	duration := time.Since(timeStart)
+	if generatedRequest.singlePacket != nil {
+		duration = generatedRequest.singlePacket.Time
+	}
+	if request.options.ScanStats != nil {
+		request.options.ScanStats.Latency(request.Type().String(), duration)
+	}
 
 	dumpedResponseHeaders, err := httputil.DumpResponse(resp, false)
 	if err != nil {
@@ -706,17 +797,35 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 
 	var dumpedResponse []redirectedResponse
 	var gotData []byte
+	var streamResults stream.Results
+	var isResponseTruncated bool
 	// If the status code is HTTP 101, we should not proceed with reading body.
 	if resp.StatusCode != http.StatusSwitchingProtocols {
-		var bodyReader io.Reader
-		if request.MaxSize != 0 {
-			bodyReader = io.LimitReader(resp.Body, int64(request.MaxSize))
-		} else if request.options.Options.ResponseReadSize != 0 {
-			bodyReader = io.LimitReader(resp.Body, int64(request.options.Options.ResponseReadSize))
+		var data []byte
+		var read int
+		var err error
+		if request.Stream {
+			maxSize := request.MaxSize
+			if maxSize == 0 {
+				maxSize = request.options.Options.ResponseReadSize
+			}
+			scanner := stream.New(request.streamMatchers(), generators.MergeMaps(generatedRequest.dynamicValues, generatedRequest.meta))
+			data, read, err = scanner.Read(resp.Body, maxSize, request.options.Options.ResponseReadSize)
+			streamResults = scanner.Results()
+			isResponseTruncated = len(data) < read || (maxSize > 0 && read >= maxSize)
 		} else {
-			bodyReader = resp.Body
+			var bodyReader io.Reader
+			if request.MaxSize != 0 {
+				bodyReader = io.LimitReader(resp.Body, int64(request.MaxSize))
+			} else if request.options.Options.ResponseReadSize != 0 {
+				bodyReader = io.LimitReader(resp.Body, int64(request.options.Options.ResponseReadSize))
+			} else {
+				bodyReader = resp.Body
+			}
+			data, err = io.ReadAll(bodyReader)
+			read = len(data)
+			isResponseTruncated = request.MaxSize > 0 && len(data) >= request.MaxSize
 		}
-		data, err := io.ReadAll(bodyReader)
 		if err != nil {
 			// Ignore body read due to server misconfiguration errors
 			if stringsutil.ContainsAny(err.Error(), "gzip: invalid header") {
@@ -726,6 +835,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			}
 		}
 		gotData = data
+		receivedBytes = len(dumpedResponseHeaders) + read
 		resp.Body.Close()
 
 		dumpedResponse, err = dumpResponseWithRedirectChain(resp, data)
@@ -733,8 +843,13 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			return errors.Wrap(err, "could not read http response with redirect chain")
 		}
 	} else {
+		receivedBytes = len(dumpedResponseHeaders)
 		dumpedResponse = []redirectedResponse{{resp: resp, fullResponse: dumpedResponseHeaders, headers: dumpedResponseHeaders}}
 	}
+	if len(dumpedResponse) > 0 {
+		// the redirect chain ends with the response to the sent request
+		rawResponse = dumpedResponse[len(dumpedResponse)-1].fullResponse
+	}
 
 	// if nuclei-project is enabled store the response if not previously done
 	if request.options.ProjectFile != nil && !fromCache {
@@ -764,6 +879,9 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 				matchedURL = responseURL
 			}
 		}
+		if generatedRequest.socket != nil {
+			matchedURL = generatedRequest.socket.URL(matchedURL)
+		}
 		finalEvent := make(output.InternalEvent)
 
 		outputEvent := request.responseToDSLMap(response.resp, input.MetaInput.Input, matchedURL, tostring.UnsafeToString(dumpedRequest), tostring.UnsafeToString(response.fullResponse), tostring.UnsafeToString(response.body), tostring.UnsafeToString(response.headers), duration, generatedRequest.meta)
@@ -779,6 +897,24 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		} else {
 			outputEvent["ip"] = httpclientpool.Dialer.GetDialedIP(hostname)
 		}
+		if generatedRequest.singlePacket != nil {
+			outputEvent["race_time"] = generatedRequest.singlePacket.Time.Seconds()
+			outputEvent["race_delta"] = generatedRequest.singlePacket.Delta.Seconds()
+			outputEvent["race_index"] = generatedRequest.singlePacket.Index
+		}
+		if request.Protobuf != nil {
+			decoded, err := request.Protobuf.Decode(response.resp.Header, response.body)
+			if err != nil {
+				gologger.Verbose().Msgf("[%s] Could not decode protobuf response for %s: %s\n", request.options.TemplateID, formedURL, err)
+			}
+			for k, v := range decoded {
+				outputEvent[k] = v
+			}
+		}
+		// the streamed body is the body of the last response of the chain
+		if streamResults != nil && response.resp == resp {
+			outputEvent[streamResultsKey] = streamResults
+		}
 		if request.options.Interactsh != nil {
 			request.options.Interactsh.MakePlaceholders(generatedRequest.interactshURLs, outputEvent)
 		}
@@ -810,7 +946,6 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		}
 
 		responseContentType := resp.Header.Get("Content-Type")
-		isResponseTruncated := request.MaxSize > 0 && len(gotData) >= request.MaxSize
 		dumpResponse(event, request, response.fullResponse, formedURL, responseContentType, isResponseTruncated, input.MetaInput.Input)
 
 		callback(event)
@@ -823,6 +958,14 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	return nil
 }
 
+// streamMatchers returns the matchers of the streamed bodies
+func (request *Request) streamMatchers() []*matchers.Matcher {
+	if request.CompiledOperators == nil {
+		return nil
+	}
+	return request.CompiledOperators.Matchers
+}
+
 // handleSignature of the http request
 func (request *Request) handleSignature(generatedRequest *generatedRequest) error {
 	switch request.Signature.Value {
@@ -923,7 +1066,7 @@ func (request *Request) pruneSignatureInternalValues(maps ...map[string]interfac
 
 func (request *Request) newContext(input *contextargs.Context) context.Context {
 	if input.MetaInput.CustomIP != "" {
-		return context.WithValue(context.Background(), fastdialer.IP, input.MetaInput.CustomIP)
+		return context.WithValue(input.Context(), fastdialer.IP, input.MetaInput.CustomIP)
 	}
-	return context.Background()
+	return input.Context()
 }
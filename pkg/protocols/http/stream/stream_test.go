@@ -0,0 +1,65 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+)
+
+func compile(t *testing.T, matcher *matchers.Matcher) *matchers.Matcher {
+	require.Nil(t, matcher.CompileMatchers(), "could not compile matcher")
+	return matcher
+}
+
+func TestScanner(t *testing.T) {
+	words := compile(t, &matchers.Matcher{
+		Type:            matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+		Words:           []string{"{{prefix}}-token", "admin"},
+		Condition:       "and",
+		CaseInsensitive: true,
+	})
+	regex := compile(t, &matchers.Matcher{
+		Type:  matchers.MatcherTypeHolder{MatcherType: matchers.RegexMatcher},
+		Regex: []string{"id=[0-9]+"},
+	})
+	status := compile(t, &matchers.Matcher{
+		Type:   matchers.MatcherTypeHolder{MatcherType: matchers.StatusMatcher},
+		Status: []int{200},
+	})
+
+	scanner := New([]*matchers.Matcher{words, regex, status}, map[string]interface{}{"prefix": "api"})
+	for _, chunk := range []string{"user=ADMIN&api-to", "ken=x&i", "d=42"} {
+		require.False(t, scanner.Done(), "scanner is done before the matches")
+		_, _ = scanner.Write([]byte(chunk))
+	}
+	require.True(t, scanner.Done(), "scanner is not done after the matches")
+	require.Equal(t, Results{
+		words: {Matched: true, Snippets: []string{"admin", "api-token"}},
+		regex: {Matched: true, Snippets: []string{"id=42"}},
+	}, scanner.Results())
+}
+
+func TestRead(t *testing.T) {
+	matcher := compile(t, &matchers.Matcher{
+		Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+		Words: []string{"needle"},
+	})
+	body := strings.Repeat("a", 3*chunkSize) + "needle" + strings.Repeat("b", 3*chunkSize)
+
+	scanner := New([]*matchers.Matcher{matcher}, nil)
+	data, read, err := scanner.Read(strings.NewReader(body), 0, 16)
+	require.Nil(t, err, "could not read body")
+	require.Equal(t, strings.Repeat("a", 16), string(data), "could not retain the start of the body")
+	require.Equal(t, 4*chunkSize, read, "could not stop reading after the match")
+	require.True(t, scanner.Results()[matcher].Matched)
+
+	scanner = New([]*matchers.Matcher{matcher}, nil)
+	data, read, err = scanner.Read(strings.NewReader(body), 2*chunkSize, 0)
+	require.Nil(t, err, "could not read body")
+	require.Len(t, data, 2*chunkSize)
+	require.Equal(t, 2*chunkSize, read, "could not stop reading at max size")
+	require.False(t, scanner.Results()[matcher].Matched)
+}
@@ -9,6 +9,8 @@ import (
 	"net/http/httputil"
 	"strings"
 
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/errors"
 	"golang.org/x/text/encoding/simplifiedchinese"
 	"golang.org/x/text/transform"
@@ -27,6 +29,16 @@ type redirectedResponse struct {
 	resp         *http.Response
 }
 
+// DumpNormalizedResponse dumps a http response with its body decompressed
+// and decoded the same way as the responses of template requests
+func DumpNormalizedResponse(resp *http.Response, body []byte) ([]byte, error) {
+	chain, err := dumpResponseWithRedirectChain(resp, body)
+	if err != nil {
+		return nil, err
+	}
+	return chain[0].fullResponse, nil
+}
+
 // dumpResponseWithRedirectChain dumps a http response with the
 // complete http redirect chain.
 //
@@ -122,31 +134,54 @@ func dump(req *generatedRequest, reqURL string) ([]byte, error) {
 	return rawhttp.DumpRequestRaw(req.rawRequest.Method, reqURL, req.rawRequest.Path, generators.ExpandMapValues(req.rawRequest.Headers), io.NopCloser(strings.NewReader(req.rawRequest.Data)), rawHttpOptions)
 }
 
+// acceptEncoding is the Accept-Encoding of the requests advertising the decompressed encodings
+const acceptEncoding = "gzip, deflate, br, zstd"
+
 // handleDecompression if the user specified a custom encoding (as golang transport doesn't do this automatically)
 func handleDecompression(resp *http.Response, bodyOrig []byte) (bodyDec []byte, err error) {
 	if resp == nil {
 		return bodyOrig, nil
 	}
 
-	var reader io.ReadCloser
-	switch resp.Header.Get("Content-Encoding") {
-	case "gzip":
-		reader, err = gzip.NewReader(bytes.NewReader(bodyOrig))
-	case "deflate":
-		reader, err = zlib.NewReader(bytes.NewReader(bodyOrig))
-	default:
-		return bodyOrig, nil
-	}
-	if err != nil {
-		return nil, err
+	bodyDec = bodyOrig
+	// the encodings are listed in the order they were applied
+	encodings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		reader, err := decompressionReader(strings.ToLower(strings.TrimSpace(encodings[i])), bodyDec)
+		if err != nil {
+			return nil, err
+		}
+		if reader == nil {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return bodyOrig, err
+		}
+		bodyDec = data
 	}
-	defer reader.Close()
+	return bodyDec, nil
+}
 
-	bodyDec, err = io.ReadAll(reader)
-	if err != nil {
-		return bodyOrig, err
+// decompressionReader returns the reader decompressing the data with the
+// content encoding, nil is returned for identity and unsupported encodings
+func decompressionReader(encoding string, data []byte) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(bytes.NewReader(data))
+	case "deflate":
+		return zlib.NewReader(bytes.NewReader(data))
+	case "br":
+		return io.NopCloser(brotli.NewReader(bytes.NewReader(data))), nil
+	case "zstd":
+		decoder, err := zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
 	}
-	return bodyDec, nil
+	return nil, nil
 }
 
 // decodeGBK converts GBK to UTF-8
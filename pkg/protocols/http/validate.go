@@ -1,15 +1,82 @@
 package http
 
-import "github.com/pkg/errors"
+import (
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/proxychain"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
+)
 
 func (request *Request) validate() error {
 	if request.Race && request.NeedsRequestCondition() {
 		return errors.New("'race' and 'req-condition' can't be used together")
 	}
 
+	switch request.RaceMode {
+	case "", RaceModeSinglePacket:
+	default:
+		return errors.Errorf("invalid 'race-mode' %q", request.RaceMode)
+	}
+	if request.RaceMode != "" && !request.Race {
+		return errors.New("'race-mode' requires 'race'")
+	}
+	if request.RaceMode != "" && (request.Unsafe || request.Pipeline) {
+		return errors.New("'race-mode' can't be used with 'unsafe' or 'pipeline'")
+	}
+
 	if request.Redirects && request.HostRedirects {
 		return errors.New("'redirects' and 'host-redirects' can't be used together")
 	}
 
+	if err := request.TLS.Validate(); err != nil {
+		return errors.Wrap(err, "invalid 'tls'")
+	}
+
+	switch request.HTTP2 {
+	case "", httpclientpool.HTTP2Upgrade, httpclientpool.HTTP2PriorKnowledge:
+	default:
+		return errors.Errorf("invalid 'http2' mode %q", request.HTTP2)
+	}
+	if request.HTTP2 != "" && (request.Unsafe || request.Pipeline) {
+		return errors.New("'http2' can't be used with 'unsafe' or 'pipeline'")
+	}
+
+	if len(request.Proxy) > 0 {
+		if _, err := proxychain.Parse(request.Proxy); err != nil {
+			return errors.Wrap(err, "invalid 'proxy'")
+		}
+		if request.Unsafe || request.Pipeline || request.RaceMode != "" {
+			return errors.New("'proxy' can't be used with 'unsafe', 'pipeline' or 'race-mode'")
+		}
+	}
+
+	if request.Smuggling != nil {
+		if err := request.Smuggling.Validate(); err != nil {
+			return errors.Wrap(err, "invalid 'smuggling'")
+		}
+		if !request.Unsafe || len(request.Raw) == 0 {
+			return errors.New("'smuggling' requires 'unsafe' raw requests")
+		}
+	}
+
+	if request.Multipart != nil {
+		if err := request.Multipart.Validate(); err != nil {
+			return errors.Wrap(err, "invalid 'multipart'")
+		}
+		if request.Body != "" || len(request.Raw) > 0 {
+			return errors.New("'multipart' can't be used with 'body' or 'raw'")
+		}
+	}
+
+	if request.Protobuf != nil && (request.Multipart != nil || len(request.Raw) > 0) {
+		return errors.New("'protobuf' can't be used with 'multipart' or 'raw'")
+	}
+
+	if err := request.Auth.Validate(); err != nil {
+		return errors.Wrap(err, "invalid 'auth'")
+	}
+	if !request.Auth.IsEmpty() && (request.HTTP2 != "" || request.Unsafe || request.Pipeline) {
+		return errors.New("'auth' can't be used with 'http2', 'unsafe' or 'pipeline'")
+	}
+
 	return nil
 }
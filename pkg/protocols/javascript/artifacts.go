@@ -0,0 +1,68 @@
+package javascript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+	"github.com/segmentio/ksuid"
+)
+
+// artifactsDir is the directory of the store response directory where the artifacts are saved
+const artifactsDir = "artifacts"
+
+// artifactNameRegex matches the characters replaced in the names of the artifacts
+var artifactNameRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// registerSaveArtifact registers the saveArtifact function saving the files generated
+// by the code (ex: screenshots) and appends the paths of the saved files to the artifacts
+func (request *Request) registerSaveArtifact(runtime *goja.Runtime, artifacts *[]string) error {
+	return gojs.RegisterFuncWithSignature(runtime, gojs.FuncOpts{
+		Name: "saveArtifact",
+		Signatures: []string{
+			"saveArtifact(string, interface{}) string",
+		},
+		Description: "saveArtifact saves the data to a file attached to the results and returns the path of the file",
+		FuncDecl: func(name string, value any) (string, error) {
+			var data []byte
+			switch v := value.(type) {
+			case string:
+				data = []byte(v)
+			case []byte:
+				data = v
+			default:
+				return "", fmt.Errorf("artifact data must be a string or bytes")
+			}
+			filePath, err := request.saveArtifact(name, data)
+			if err != nil {
+				return "", err
+			}
+			*artifacts = append(*artifacts, filePath)
+			return filePath, nil
+		},
+	})
+}
+
+// saveArtifact writes the data to a uniquely named file of the artifacts directory
+// and returns its absolute path, the base name of the artifact is kept.
+func (request *Request) saveArtifact(name string, data []byte) (string, error) {
+	name = artifactNameRegex.ReplaceAllString(filepath.Base(name), "_")
+	if name == "" || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid artifact name")
+	}
+	dir := filepath.Join(request.options.Options.StoreResponseDir, artifactsDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	filePath, err := filepath.Abs(filepath.Join(dir, fmt.Sprintf("%s-%s-%s", request.options.TemplateID, ksuid.New().String(), name)))
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
@@ -0,0 +1,51 @@
+package javascript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/compiler"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestSaveArtifact(t *testing.T) {
+	dir := t.TempDir()
+	request := &Request{options: &protocols.ExecutorOptions{
+		TemplateID: "vnc-screenshot",
+		Options:    &types.Options{StoreResponseDir: dir},
+	}}
+
+	var artifacts []string
+	opts := &compiler.ExecuteOptions{Callback: func(runtime *goja.Runtime) error {
+		return request.registerSaveArtifact(runtime, &artifacts)
+	}}
+	args := compiler.NewExecuteArgs()
+	args.Args["screenshot"] = []byte("\x89PNG")
+	code := `saveArtifact("../screen.png", screenshot); saveArtifact("notes.txt", "open desktop")`
+	_, err := compiler.New().ExecuteWithOptions(code, args, opts)
+	require.Nil(t, err)
+	require.Len(t, artifacts, 2)
+
+	// the artifacts are saved in the artifacts directory with their base names
+	for i, expected := range []struct{ suffix, data string }{
+		{"-screen.png", "\x89PNG"},
+		{"-notes.txt", "open desktop"},
+	} {
+		require.Equal(t, filepath.Join(dir, artifactsDir), filepath.Dir(artifacts[i]))
+		require.Contains(t, filepath.Base(artifacts[i]), "vnc-screenshot-")
+		require.True(t, filepath.IsAbs(artifacts[i]))
+		require.Equal(t, expected.suffix, filepath.Base(artifacts[i])[len(filepath.Base(artifacts[i]))-len(expected.suffix):])
+		data, err := os.ReadFile(artifacts[i])
+		require.Nil(t, err)
+		require.Equal(t, expected.data, string(data))
+	}
+
+	_, err = compiler.New().ExecuteWithOptions(`saveArtifact("numbers.txt", 42)`, compiler.NewExecuteArgs(), opts)
+	require.ErrorContains(t, err, "artifact data must be a string or bytes")
+	_, err = request.saveArtifact("..", []byte("data"))
+	require.EqualError(t, err, "invalid artifact name")
+}
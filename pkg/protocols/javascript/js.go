@@ -11,6 +11,7 @@ import (
 
 	"github.com/alecthomas/chroma/quick"
 	"github.com/ditashi/jsbeautifier-go/jsbeautifier"
+	"github.com/docker/go-units"
 	"github.com/dop251/goja"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/gologger"
@@ -60,7 +61,26 @@ type Request struct {
 	Args map[string]interface{} `yaml:"args,omitempty" json:"args,omitempty"`
 	// description: |
 	//   Code contains code to execute for the javascript request.
+	//
+	//   Relative .js modules can be required from the directory of the template,
+	//   ex: require("./lib/login.js"). The modules are included in the template signature.
 	Code string `yaml:"code,omitempty" json:"code,omitempty" jsonschema:"title=code to execute in javascript,description=Executes inline javascript code for the request"`
+	// description: |
+	//   Timeout is the maximum execution time of the javascript code.
+	//
+	//   The execution is interrupted once exceeded, the -js-timeout option is used by default.
+	// examples:
+	//   - name: Interrupt the code after 30 seconds
+	//     value: "\"30s\""
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"title=execution timeout,description=Maximum execution time of the javascript code"`
+	// description: |
+	//   MaxMemory is the maximum memory the javascript code can allocate.
+	//
+	//   The execution is interrupted once exceeded, the -js-max-memory option is used by default.
+	// examples:
+	//   - name: Interrupt the code after allocating 64mb
+	//     value: "\"64mb\""
+	MaxMemory string `yaml:"max-memory,omitempty" json:"max-memory,omitempty" jsonschema:"title=maximum memory,description=Maximum memory the javascript code can allocate"`
 
 	// description: |
 	//   StopAtFirstMatch stops processing the request at first match.
@@ -83,9 +103,18 @@ type Request struct {
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Files can also be https urls fetched once per scan, an expected sha256
+	//   checksum can be set with a #sha256=hex url fragment. The urls are
+	//   only fetched with the -allow-remote-payloads option.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the webosocket request,description=Payloads contains any payloads for the current request"`
 
 	generator *generators.PayloadGenerator
+	timeout   time.Duration
+	maxMemory int64
+	// modules are the sources of the modules required by the code indexed by their path
+	modules  map[string]string
+	filename string
 
 	// cache any variables that may be needed for operation.
 	options *protocols.ExecutorOptions `yaml:"-" json:"-"`
@@ -103,6 +132,15 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		}
 	}
 
+	if err := request.compileLimits(); err != nil {
+		return err
+	}
+
+	// modules are loaded with the template to be included in its signature
+	for modulePath, source := range request.modules {
+		request.options.JsCompiler.AddModule(modulePath, source)
+	}
+
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
@@ -141,7 +179,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 			prettyPrint(request.TemplateID, buff.String())
 		}
 
-		opts := &compiler.ExecuteOptions{}
+		opts := request.executeOptions(nil)
 		// register 'export' function to export variables from init code
 		// these are saved in args and are available in pre-condition and request code
 		opts.Callback = func(runtime *goja.Runtime) error {
@@ -216,6 +254,41 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	return nil
 }
 
+// compileLimits parses the execution limits of the request, the
+// global options are used for the limits missing from the template
+func (request *Request) compileLimits() error {
+	request.timeout = request.options.Options.JSTimeout
+	if request.Timeout != "" {
+		timeout, err := time.ParseDuration(request.Timeout)
+		if err != nil {
+			return errorutil.NewWithTag(request.TemplateID, "invalid timeout %s: %s", request.Timeout, err)
+		}
+		request.timeout = timeout
+	}
+	maxMemory := request.MaxMemory
+	if maxMemory == "" {
+		maxMemory = request.options.Options.JSMaxMemory
+	}
+	if maxMemory != "" {
+		value, err := units.FromHumanSize(maxMemory)
+		if err != nil {
+			return errorutil.NewWithTag(request.TemplateID, "invalid max memory %s: %s", maxMemory, err)
+		}
+		request.maxMemory = value
+	}
+	return nil
+}
+
+// executeOptions returns the options of a code execution with the limits of the request
+func (request *Request) executeOptions(ctx context.Context) *compiler.ExecuteOptions {
+	return &compiler.ExecuteOptions{
+		Context:   ctx,
+		Filename:  request.filename,
+		Timeout:   request.timeout,
+		MaxMemory: request.maxMemory,
+	}
+}
+
 // Options returns executer options for http request
 func (r *Request) Options() *protocols.ExecutorOptions {
 	return r.options
@@ -247,6 +320,10 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 	// and it is ignored if input port is not standard http(s) ports like 80,8080,8081 etc
 	// idea is to reduce redundant dials to http ports
 	if err := input.UseNetworkPort(request.getPort(), request.getExcludePorts()); err != nil {
+		if errors.Is(err, contextargs.ErrPortNotOpen) {
+			request.options.Progress.AddToTotal(-int64(request.Requests()))
+			return nil
+		}
 		gologger.Debug().Msgf("Could not network port from constants: %s\n", err)
 	}
 
@@ -303,8 +380,11 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 		}
 		argsCopy.TemplateCtx = templateCtx.GetAll()
 
-		result, err := request.options.JsCompiler.ExecuteWithOptions(request.PreCondition, argsCopy, nil)
+		result, err := request.options.JsCompiler.ExecuteWithOptions(request.PreCondition, argsCopy, request.executeOptions(input.Context()))
 		if err != nil {
+			if errors.Is(err, compiler.ErrJSExecDeadline) || errors.Is(err, compiler.ErrJSMemoryLimit) {
+				request.writeInterruptEvent(input, hostPort, request.PreCondition, err)
+			}
 			return errorutil.NewWithTag(request.TemplateID, "could not execute pre-condition: %s", err)
 		}
 		if !result.GetSuccess() || types.ToString(result["error"]) != "" {
@@ -319,7 +399,7 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 	}
 
 	if request.generator != nil && request.Threads > 1 {
-		request.executeRequestParallel(context.Background(), hostPort, hostname, input, payloadValues, callback)
+		request.executeRequestParallel(input.Context(), hostPort, hostname, input, payloadValues, callback)
 		return nil
 	}
 
@@ -329,7 +409,7 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 
 		for {
 			value, ok := iterator.Value()
-			if !ok {
+			if !ok || input.Context().Err() != nil {
 				return nil
 			}
 
@@ -425,10 +505,32 @@ func (request *Request) executeRequestWithPayloads(hostPort string, input *conte
 		requestData = []byte(transformedData)
 	}
 
-	results, err := request.options.JsCompiler.ExecuteWithOptions(string(requestData), argsCopy, &compiler.ExecuteOptions{
-		Pool: false,
-	})
+	var artifacts []string
+	opts := request.executeOptions(input.Context())
+	opts.Callback = func(runtime *goja.Runtime) error {
+		return request.registerSaveArtifact(runtime, &artifacts)
+	}
+	if request.options.HostRateLimiter != nil {
+		if err := request.options.HostRateLimiter.Take(input.Context(), hostPort); err != nil {
+			return err
+		}
+	}
+	timeStart := time.Now()
+	results, err := request.options.JsCompiler.ExecuteWithOptions(string(requestData), argsCopy, opts)
+	if request.options.HostRateLimiter != nil {
+		// the exceptions are expected failures of the code, only the
+		// interruptions of the code on slow hosts are errors of the host
+		var hostErr error
+		if errors.Is(err, compiler.ErrJSExecDeadline) {
+			hostErr = err
+		}
+		request.options.HostRateLimiter.Observe(hostPort, time.Since(timeStart), nil, hostErr)
+	}
 	if err != nil {
+		if errors.Is(err, compiler.ErrJSExecDeadline) || errors.Is(err, compiler.ErrJSMemoryLimit) {
+			gologger.Warning().Msgf("[%s] Javascript code interrupted for %s: %s\n", request.TemplateID, hostPort, err)
+			request.writeInterruptEvent(input, hostPort, request.Code, err)
+		}
 		// shouldn't fail even if it returned error instead create a failure event
 		results = compiler.ExecuteResult{"success": false, "error": err.Error()}
 	}
@@ -469,6 +571,9 @@ func (request *Request) executeRequestWithPayloads(hostPort string, input *conte
 	data["template-path"] = requestOptions.TemplatePath
 	data["template-id"] = requestOptions.TemplateID
 	data["template-info"] = requestOptions.TemplateInfo
+	if len(artifacts) > 0 {
+		data["artifacts"] = artifacts
+	}
 	if request.StopAtFirstMatch || request.options.StopAtFirstMatch {
 		data["stop-at-first-match"] = true
 	}
@@ -518,6 +623,25 @@ func (request *Request) executeRequestWithPayloads(hostPort string, input *conte
 	return nil
 }
 
+// writeInterruptEvent writes the interruption of the code by its limits as a
+// failure result with the error, it is written without -matcher-status as the
+// results of the code are missing
+func (request *Request) writeInterruptEvent(input *contextargs.Context, hostPort, code string, err error) {
+	event := &output.ResultEvent{
+		TemplateID:   request.options.TemplateID,
+		TemplatePath: request.options.TemplatePath,
+		Info:         request.options.TemplateInfo,
+		Type:         request.Type().String(),
+		Host:         input.MetaInput.Input,
+		Matched:      hostPort,
+		Request:      beautifyJavascript(code),
+		Error:        err.Error(),
+	}
+	if writeErr := request.options.Output.Write(event); writeErr != nil {
+		gologger.Warning().Msgf("[%s] Could not write interrupt event for %s: %s\n", request.TemplateID, hostPort, writeErr)
+	}
+}
+
 func (request *Request) getArgsCopy(input *contextargs.Context, payloadValues map[string]interface{}, requestOptions *protocols.ExecutorOptions, ignoreErrors bool) (*compiler.ExecuteArgs, error) {
 	// Template args from payloads
 	argsCopy, err := request.evaluateArgs(payloadValues, requestOptions, ignoreErrors)
@@ -635,6 +759,9 @@ func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent
 		Response:         types.ToString(wrapped.InternalEvent["response"]),
 		IP:               types.ToString(wrapped.InternalEvent["ip"]),
 	}
+	if artifacts, ok := wrapped.InternalEvent["artifacts"].([]string); ok {
+		data.Artifacts = artifacts
+	}
 	return data
 }
 
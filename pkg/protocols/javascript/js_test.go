@@ -8,9 +8,13 @@ import (
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/disk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/compiler"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/parsers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/javascript"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
 	"github.com/projectdiscovery/ratelimit"
@@ -72,3 +76,29 @@ func TestCompile(t *testing.T) {
 		}
 	}
 }
+
+func TestRequestInterruptEvent(t *testing.T) {
+	setup()
+	var events []*output.ResultEvent
+	writer := testutils.NewMockOutputWriter()
+	writer.WriteCallback = func(event *output.ResultEvent) {
+		events = append(events, event)
+	}
+	options := executerOpts
+	options.Output = writer
+	options.JsCompiler = compiler.New()
+	options.TemplateID = "interrupted-code"
+	options.CreateTemplateCtxStore()
+
+	request := &javascript.Request{Code: `let items = []; while (true) { items.push("x".repeat(1024) + items.length) }`, MaxMemory: "8mb", Timeout: "1m"}
+	require.Nil(t, request.Compile(&options))
+	input := contextargs.NewWithInput("127.0.0.1:22")
+	err := request.ExecuteWithResults(input, nil, nil, func(event *output.InternalWrappedEvent) {})
+	require.ErrorIs(t, err, compiler.ErrJSMemoryLimit)
+
+	require.Len(t, events, 1)
+	require.Equal(t, "interrupted-code", events[0].TemplateID)
+	require.Equal(t, "127.0.0.1:22", events[0].Matched)
+	require.Equal(t, compiler.ErrJSMemoryLimit.Error(), events[0].Error)
+	require.False(t, events[0].MatcherStatus)
+}
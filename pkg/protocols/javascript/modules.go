@@ -0,0 +1,53 @@
+package javascript
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// requireRegex matches the relative module imports of the code, ex: require("./lib/login.js")
+var requireRegex = regexp.MustCompile(`require\(\s*["'](\.{1,2}/[^"']+\.js)["']\s*\)`)
+
+// ImportModules loads the relative modules required by the code blocks of the request
+// along with the modules they require, the modules are resolved from the directory
+// of the template. It returns the paths of the loaded modules in the order of their imports.
+func (request *Request) ImportModules(templatePath string, load func(path string) (string, error)) ([]string, error) {
+	absPath, err := filepath.Abs(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	request.filename = absPath
+	request.modules = make(map[string]string)
+	imported := []string{}
+
+	var importModules func(dir, code string) error
+	importModules = func(dir, code string) error {
+		for _, match := range requireRegex.FindAllStringSubmatch(code, -1) {
+			modulePath := path.Join(dir, match[1])
+			if _, ok := request.modules[modulePath]; ok {
+				continue
+			}
+			source, err := load(filepath.FromSlash(modulePath))
+			if err != nil {
+				return errorutil.NewWithErr(err).Msgf("could not load module %s", match[1])
+			}
+			request.modules[modulePath] = source
+			imported = append(imported, filepath.FromSlash(modulePath))
+			if err := importModules(path.Dir(modulePath), source); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	dir := path.Dir(filepath.ToSlash(absPath))
+	for _, code := range []string{request.Init, request.PreCondition, request.Code} {
+		if err := importModules(dir, code); err != nil {
+			return imported, err
+		}
+	}
+	return imported, nil
+}
@@ -0,0 +1,45 @@
+package javascript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestImportModules(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"lib/login.js": `const util = require("./util.js"); module.exports = { login: util.login };`,
+		"lib/util.js":  `module.exports = { login: () => true };`,
+		"state.js":     `const util = require('./lib/util.js'); module.exports = {};`,
+	}
+	for name, content := range files {
+		require.Nil(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	load := func(path string) (string, error) {
+		data, err := os.ReadFile(path)
+		return string(data), err
+	}
+
+	request := &Request{
+		Init: `const state = require("./state.js");`,
+		Code: `const lib = require("./lib/login.js"); const net = require("nuclei/net"); lib.login()`,
+	}
+	imported, err := request.ImportModules(filepath.Join(dir, "template.yaml"), load)
+	require.Nil(t, err)
+	// the modules are imported once in the order of their imports
+	require.Equal(t, []string{
+		filepath.Join(dir, "state.js"),
+		filepath.Join(dir, "lib/util.js"),
+		filepath.Join(dir, "lib/login.js"),
+	}, imported)
+	require.Len(t, request.modules, 3)
+	require.Equal(t, files["lib/util.js"], request.modules[filepath.ToSlash(filepath.Join(dir, "lib/util.js"))])
+
+	request = &Request{Code: `require("./missing.js")`}
+	_, err = request.ImportModules(filepath.Join(dir, "template.yaml"), load)
+	require.ErrorContains(t, err, "could not load module ./missing.js")
+}
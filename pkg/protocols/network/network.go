@@ -1,15 +1,18 @@
 package network
 
 import (
+	"crypto/tls"
 	"strings"
 
 	"github.com/pkg/errors"
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/tlsconfig"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/network/networkclientpool"
 	fileutil "github.com/projectdiscovery/utils/file"
 )
@@ -42,6 +45,10 @@ type Request struct {
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Files can also be https urls fetched once per scan, an expected sha256
+	//   checksum can be set with a #sha256=hex url fragment. The urls are
+	//   only fetched with the -allow-remote-payloads option.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the network request,description=Payloads contains any payloads for the current request"`
 
 	// description: |
@@ -69,6 +76,11 @@ type Request struct {
 	// examples:
 	//   - value: false
 	ReadAll bool `yaml:"read-all,omitempty" json:"read-all,omitempty" jsonschema:"title=read all response stream,description=Read all response stream till the server stops sending"`
+	// description: |
+	//   TLS contains custom tls client parameters used for tls:// addresses.
+	//
+	//   Parameters not specified are taken from the global tls options.
+	TLS *tlsconfig.Config `yaml:"tls,omitempty" json:"tls,omitempty" jsonschema:"title=custom tls client parameters,description=Custom tls client parameters used for tls addresses"`
 
 	// description: |
 	//   SelfContained specifies if the request is self-contained.
@@ -80,8 +92,9 @@ type Request struct {
 
 	generator *generators.PayloadGenerator
 	// cache any variables that may be needed for operation.
-	dialer  *fastdialer.Dialer
-	options *protocols.ExecutorOptions
+	dialer    *fastdialer.Dialer
+	tlsConfig *tls.Config
+	options   *protocols.ExecutorOptions
 }
 
 // RequestPartDefinitions contains a mapping of request part definitions and their
@@ -205,6 +218,17 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	}
 	request.dialer = client
 
+	if err := request.TLS.ResolveClientCertificate(options.ResolvePEM); err != nil {
+		return errors.Wrap(err, "could not load client certificate")
+	}
+	if customTLS := request.TLS.Merge(options.Options.TLSConfig()); !customTLS.IsEmpty() || fips.IsEnabled() {
+		request.tlsConfig = &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10}
+		if err := customTLS.Apply(request.tlsConfig); err != nil {
+			return errors.Wrap(err, "could not apply tls configuration")
+		}
+		fips.RestrictTLSConfig(request.tlsConfig)
+	}
+
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
@@ -30,6 +30,10 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(itemStr))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
+	case matchers.PluginMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchPlugin(itemStr))
+	case matchers.YaraMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchYara(itemStr))
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(itemStr)), []string{}
 	}
@@ -50,6 +54,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractKval(data)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.PluginExtractor:
+		return extractor.ExtractPlugin(itemStr)
 	}
 	return nil
 }
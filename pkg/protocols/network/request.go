@@ -22,6 +22,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/replacer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
 	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
@@ -54,6 +55,10 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, metadata
 	// and it is ignored if input port is not standard http(s) ports like 80,8080,8081 etc
 	// idea is to reduce redundant dials to http ports
 	if err := input.UseNetworkPort(request.Port, request.ExcludePorts); err != nil {
+		if errors.Is(err, contextargs.ErrPortNotOpen) {
+			request.options.Progress.AddToTotal(-int64(request.Requests()))
+			return nil
+		}
 		gologger.Debug().Msgf("Could not network port from constants: %s\n", err)
 	}
 
@@ -137,10 +142,24 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		hostname = host
 	}
 
-	if shouldUseTLS {
-		conn, err = request.dialer.DialTLS(context.Background(), "tcp", actualAddress)
+	if request.options.HostRateLimiter != nil {
+		if err := request.options.HostRateLimiter.Take(input.Context(), actualAddress); err != nil {
+			return err
+		}
+	}
+	dialStart := time.Now()
+	if shouldUseTLS && request.tlsConfig != nil {
+		conn, err = protocolstate.WithIPVersion(func(ctx context.Context, network, address string) (net.Conn, error) {
+			return request.dialer.DialTLSWithConfig(ctx, network, address, request.tlsConfig)
+		})(input.Context(), "tcp", actualAddress)
+	} else if shouldUseTLS {
+		conn, err = protocolstate.WithIPVersion(request.dialer.DialTLS)(input.Context(), "tcp", actualAddress)
 	} else {
-		conn, err = request.dialer.Dial(context.Background(), "tcp", actualAddress)
+		conn, err = protocolstate.WithIPVersion(request.dialer.Dial)(input.Context(), "tcp", actualAddress)
+	}
+	if request.options.HostRateLimiter != nil {
+		// the connection time is the round trip of the host
+		request.options.HostRateLimiter.Observe(actualAddress, time.Since(dialStart), nil, err)
 	}
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, address, request.Type().String(), err)
@@ -148,6 +167,11 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		return errors.Wrap(err, "could not connect to server")
 	}
 	defer conn.Close()
+	// unblock pending reads and writes when the scan is cancelled
+	stopCloseOnCancel := context.AfterFunc(input.Context(), func() {
+		_ = conn.Close()
+	})
+	defer stopCloseOnCancel()
 	_ = conn.SetDeadline(time.Now().Add(time.Duration(request.options.Options.Timeout) * time.Second))
 
 	var interactshURLs []string
@@ -240,7 +264,6 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		}
 	}
 
-	request.options.Output.Request(request.options.TemplatePath, actualAddress, request.Type().String(), err)
 	gologger.Verbose().Msgf("Sent TCP request to %s", actualAddress)
 
 	bufferSize := 1024
@@ -257,6 +280,15 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		return errors.Wrap(err, "could not read from server")
 	}
 	responseBuilder.Write(final)
+	request.options.Output.LogRequest(&output.RequestEvent{
+		Template:      request.options.TemplatePath,
+		Input:         actualAddress,
+		Type:          request.Type().String(),
+		BytesSent:     reqBuilder.Len(),
+		BytesReceived: responseBuilder.Len(),
+		Request:       reqBuilder.String(),
+		Response:      responseBuilder.String(),
+	})
 
 	response := responseBuilder.String()
 	outputEvent := request.responseToDSLMap(reqBuilder.String(), string(final), response, input.MetaInput.Input, actualAddress)
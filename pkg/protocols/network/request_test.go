@@ -3,6 +3,7 @@ package network
 import (
 	"encoding/hex"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -17,6 +18,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
 )
 
@@ -155,3 +157,30 @@ var exampleBody = `<!doctype html>
 </body>
 </html>
 `
+
+func TestNetworkHostRateLimit(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-network-host-rate-limit"
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	address := listener.Addr().String()
+	listener.Close()
+
+	request := &Request{
+		ID:      templateID,
+		Address: []string{"{{Hostname}}"},
+		Inputs:  []*Input{{Data: "PING\r\n"}},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.HostRateLimiter = hostratelimit.New(100, 1)
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile network request")
+
+	_ = request.ExecuteWithResults(contextargs.NewWithInput(address), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {})
+	require.Equal(t, float64(50), executerOpts.HostRateLimiter.Rate(address), "could not throttle the host refusing the connection")
+}
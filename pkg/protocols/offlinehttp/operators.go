@@ -40,6 +40,10 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(item))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
+	case matchers.PluginMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchPlugin(item))
+	case matchers.YaraMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchYara(item))
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(item)), []string{}
 	}
@@ -71,6 +75,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractKval(data)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.PluginExtractor:
+		return extractor.ExtractPlugin(item)
 	}
 	return nil
 }
@@ -1,9 +1,12 @@
 package protocols
 
 import (
+	"io"
+	"strings"
 	"sync/atomic"
 
 	"github.com/projectdiscovery/ratelimit"
+	errorutil "github.com/projectdiscovery/utils/errors"
 	mapsutil "github.com/projectdiscovery/utils/maps"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 
@@ -20,12 +23,18 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v3/pkg/projectfile"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/robots"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/session"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/excludematchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/variables"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v3/pkg/scanstats"
 	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
@@ -58,6 +67,8 @@ type ExecutorOptions struct {
 	IssuesClient reporting.Client
 	// Progress is a progress client for scan reporting
 	Progress progress.Progress
+	// ScanStats is an optional collector for the end of scan statistics report
+	ScanStats *scanstats.Collector
 	// RateLimiter is a rate-limiter for limiting sent number of requests.
 	RateLimiter *ratelimit.Limiter
 	// Catalog is a template catalog implementation for nuclei
@@ -70,6 +81,12 @@ type ExecutorOptions struct {
 	Interactsh *interactsh.Client
 	// HostErrorsCache is an optional cache for handling host errors
 	HostErrorsCache hosterrorscache.CacheInterface
+	// Robots is an optional robots.txt policy for skipping disallowed fuzzing paths
+	Robots *robots.Policy
+	// HostRateLimiter is an optional rate-limiter adapting the rate of every host
+	HostRateLimiter *hostratelimit.Limiter
+	// Session is an optional session manager injecting the login tokens in the http requests
+	Session *session.Manager
 	// Stop execution once first match is found (Assigned while parsing templates)
 	// Note: this is different from Options.StopAtFirstMatch (Assigned from CLI option)
 	StopAtFirstMatch bool
@@ -126,6 +143,10 @@ func (e *ExecutorOptions) GetTemplateCtx(input *contextargs.MetaInput) *contexta
 	if !ok {
 		// if template context does not exist create new and add it to store and return it
 		templateCtx = contextargs.New()
+		// the metadata of the input source are available to all the requests of the template
+		for k, v := range input.Metadata {
+			templateCtx.Set(k, v)
+		}
 		_ = e.templateCtxStore.Set(scanId, templateCtx)
 	}
 	return templateCtx
@@ -177,6 +198,36 @@ func (e ExecutorOptions) Copy() ExecutorOptions {
 	return copy
 }
 
+// ResolvePEM resolves a client certificate or key of the template to a PEM block.
+//
+// The value is evaluated with the variables and the constants of the template,
+// inline PEM blocks are returned as is while the other values are read as files
+// following the helper file access rules.
+func (e *ExecutorOptions) ResolvePEM(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	values := generators.MergeMaps(generators.BuildPayloadFromOptions(e.Options), e.Constants)
+	values = generators.MergeMaps(e.Variables.Evaluate(values), values)
+	evaluated, err := expressions.Evaluate(value, values)
+	if err != nil {
+		return "", errorutil.NewWithErr(err).Msgf("could not evaluate pem value")
+	}
+	if strings.Contains(evaluated, "-----BEGIN ") {
+		return evaluated, nil
+	}
+	file, err := e.Options.LoadHelperFile(strings.TrimSpace(evaluated), e.TemplatePath, e.Catalog)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", errorutil.NewWithErr(err).Msgf("could not read pem file %s", evaluated)
+	}
+	return string(data), nil
+}
+
 // Request is an interface implemented any protocol based request generator.
 type Request interface {
 	// Compile compiles the request generators preparing any requests possible.
@@ -260,6 +311,8 @@ func MakeDefaultExtractFunc(data map[string]interface{}, extractor *extractors.E
 		return extractor.ExtractXPath(itemStr)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.PluginExtractor:
+		return extractor.ExtractPlugin(itemStr)
 	}
 	return nil
 }
@@ -289,6 +342,10 @@ func MakeDefaultMatchFunc(data map[string]interface{}, matcher *matchers.Matcher
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(item))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), nil
+	case matchers.PluginMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchPlugin(item))
+	case matchers.YaraMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchYara(item))
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(item)), []string{}
 	}
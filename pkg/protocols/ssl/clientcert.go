@@ -0,0 +1,102 @@
+package ssl
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/tlsconfig"
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// tlsVersions are the names of the tls versions in the responses
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "tls10",
+	tls.VersionTLS11: "tls11",
+	tls.VersionTLS12: "tls12",
+	tls.VersionTLS13: "tls13",
+}
+
+// loadClientCertificate resolves and parses the client certificate of the request.
+//
+// The tlsx clients can't present client certificates, the handshakes of the
+// request are therefore done with crypto/tls, the ctls scan mode.
+func (request *Request) loadClientCertificate() error {
+	switch request.ScanMode {
+	case "auto", "ctls":
+		request.ScanMode = "ctls"
+	default:
+		return fmt.Errorf("client certificates are not supported with scan mode %s", request.ScanMode)
+	}
+	config := &tlsconfig.Config{ClientCert: request.ClientCert, ClientKey: request.ClientKey}
+	if err := config.ResolveClientCertificate(request.options.ResolvePEM); err != nil {
+		return err
+	}
+	certificate, err := tlsconfig.ClientCertificate(config.ClientCert, config.ClientKey)
+	if err != nil {
+		return err
+	}
+	request.clientCertificate = &certificate
+	return nil
+}
+
+// connectWithClientCertificate does the handshake with the client certificate
+// and returns the response in the format of the tlsx clients
+func (request *Request) connectWithClientCertificate(hostname, ip, port string) (*clients.Response, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		Certificates:       []tls.Certificate{*request.clientCertificate},
+	}
+	params := &tlsconfig.Config{MinVersion: request.MinVersion, MaxVersion: request.MaxVersion, CipherSuites: request.CipherSuites}
+	if err := params.Apply(config); err != nil {
+		return nil, err
+	}
+	if net.ParseIP(hostname) == nil {
+		config.ServerName = hostname
+	}
+
+	ctx := context.Background()
+	if timeout := request.options.Options.Timeout; timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+	rawConn, err := request.dialer.Dial(ctx, "tcp", net.JoinHostPort(ip, port))
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("failed to setup connection").WithTag("ctls")
+	}
+	conn := tls.Client(rawConn, config)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		rawConn.Close()
+		return nil, errorutil.NewWithTag("ctls", "could not do handshake").Wrap(err)
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, errorutil.New("no certificates returned by server")
+	}
+	resolvedIP, _, err := net.SplitHostPort(rawConn.RemoteAddr().String())
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	response := &clients.Response{
+		Timestamp:           &now,
+		Host:                hostname,
+		IP:                  resolvedIP,
+		ProbeStatus:         true,
+		Port:                port,
+		Version:             tlsVersions[state.Version],
+		Cipher:              tls.CipherSuiteName(state.CipherSuite),
+		TLSConnection:       "ctls",
+		CertificateResponse: clients.Convertx509toResponse(request.tlsxOptions, hostname, state.PeerCertificates[0], request.tlsxOptions.Cert),
+		ServerName:          config.ServerName,
+	}
+	response.Untrusted = clients.IsUntrustedCA(state.PeerCertificates[1:])
+	return response, nil
+}
@@ -1,6 +1,7 @@
 package ssl
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"time"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/extractors"
@@ -22,6 +24,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/tlsconfig"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/network/networkclientpool"
 	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
@@ -76,16 +79,31 @@ type Request struct {
 	//   - "auto"
 	//	 - "openssl" # reverts to "auto" is openssl is not installed
 	ScanMode string `yaml:"scan_mode,omitempty" json:"scan_mode,omitempty" jsonschema:"title=Scan Mode,description=Scan Mode - auto if not specified.,enum=ctls,enum=ztls,enum=auto"`
+	// description: |
+	//   Client certificate presented to the servers requiring mutual tls, the ctls scan mode is used.
+	//
+	//   The certificate can be an inline PEM block, a file path or a variable (ex: {{client_cert}}).
+	// examples:
+	//   - value: "\"certs/admin.crt\""
+	ClientCert string `yaml:"client_cert,omitempty" json:"client_cert,omitempty" jsonschema:"title=client certificate,description=Client certificate (PEM block or file path) presented to the servers"`
+	// description: |
+	//   Private key of the client certificate, the key is read from the certificate if not specified.
+	//
+	//   The key can be an inline PEM block, a file path or a variable (ex: {{client_key}}).
+	ClientKey string `yaml:"client_key,omitempty" json:"client_key,omitempty" jsonschema:"title=client key,description=Private key (PEM block or file path) of the client certificate"`
 
 	// cache any variables that may be needed for operation.
-	dialer  *fastdialer.Dialer
-	tlsx    *tlsx.Service
-	options *protocols.ExecutorOptions
+	dialer      *fastdialer.Dialer
+	tlsx        *tlsx.Service
+	tlsxOptions *clients.Options
+	// clientCertificate is the parsed client certificate of the request
+	clientCertificate *tls.Certificate
+	options           *protocols.ExecutorOptions
 }
 
 // CanCluster returns true if the request can be clustered.
 func (request *Request) CanCluster(other *Request) bool {
-	if len(request.CipherSuites) > 0 || request.MinVersion != "" || request.MaxVersion != "" {
+	if len(request.CipherSuites) > 0 || request.MinVersion != "" || request.MaxVersion != "" || request.ClientCert != "" {
 		return false
 	}
 	if request.Address != other.Address || request.ScanMode != other.ScanMode {
@@ -115,6 +133,18 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		// if openssl is not installed instead of failing "auto" scanmode is used
 		request.ScanMode = "auto"
 	}
+	if request.ClientCert != "" {
+		if err := request.loadClientCertificate(); err != nil {
+			return errorutil.NewWithTag(request.TemplateID, "could not load client certificate").Wrap(err)
+		}
+	} else if request.ClientKey != "" {
+		return errorutil.NewWithTag(request.TemplateID, "client key requires a client certificate")
+	}
+	if fips.IsEnabled() {
+		if err := request.restrictToFIPS(); err != nil {
+			return errorutil.NewWithTag(request.TemplateID, "invalid tls parameters").Wrap(err)
+		}
+	}
 
 	tlsxOptions := &clients.Options{
 		AllCiphers:        true,
@@ -140,6 +170,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		return errorutil.NewWithTag(request.TemplateID, "could not create tlsx service")
 	}
 	request.tlsx = tlsxService
+	request.tlsxOptions = tlsxOptions
 
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
@@ -158,6 +189,29 @@ func (r *Request) Options() *protocols.ExecutorOptions {
 	return r.options
 }
 
+// restrictToFIPS restricts the scan mode and tls parameters of the request
+// to the ones approved in fips mode
+func (request *Request) restrictToFIPS() error {
+	switch request.ScanMode {
+	case "auto", "ctls":
+		// ztls and openssl do not use approved implementations
+		request.ScanMode = "ctls"
+	default:
+		return fmt.Errorf("scan mode %s not approved in fips mode", request.ScanMode)
+	}
+	config := &tlsconfig.Config{MinVersion: request.MinVersion, MaxVersion: request.MaxVersion, CipherSuites: request.CipherSuites}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if request.MinVersion == "" {
+		request.MinVersion = "tls12"
+	}
+	if len(request.CipherSuites) == 0 {
+		request.CipherSuites = fips.ApprovedCipherSuiteNames()
+	}
+	return nil
+}
+
 // Requests returns the total number of requests the rule will perform
 func (request *Request) Requests() int {
 	return 1
@@ -215,7 +269,12 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		hostIp = host
 	}
 
-	response, err := request.tlsx.Connect(host, hostIp, port)
+	var response *clients.Response
+	if request.clientCertificate != nil {
+		response, err = request.connectWithClientCertificate(host, hostIp, port)
+	} else {
+		response, err = request.tlsx.Connect(host, hostIp, port)
+	}
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
@@ -1,7 +1,16 @@
 package ssl
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -40,3 +49,73 @@ func TestGetAddress(t *testing.T) {
 	address, _ := getAddress("https://scanme.sh")
 	require.Equal(t, "scanme.sh:443", address, "could not get correct address")
 }
+
+// selfSignedPEM returns a self signed certificate and its key as PEM blocks
+func selfSignedPEM(t *testing.T, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestSSLProtocolClientCertificate(t *testing.T) {
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+
+	serverCert, serverKey := selfSignedPEM(t, "server")
+	certificate, err := tls.X509KeyPair([]byte(serverCert), []byte(serverKey))
+	require.Nil(t, err)
+	clientNames := make(chan string, 1)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAnyClientCert,
+	})
+	require.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		if tlsConn.Handshake() == nil {
+			clientNames <- tlsConn.ConnectionState().PeerCertificates[0].Subject.CommonName
+		}
+	}()
+
+	clientCert, clientKey := selfSignedPEM(t, "client")
+	request := &Request{
+		Address:    "{{Hostname}}",
+		ClientCert: "{{client_cert}}",
+		ClientKey:  clientKey,
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   "testing-ssl-client-certificate",
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.Constants = map[string]interface{}{"client_cert": clientCert}
+	require.Nil(t, request.Compile(executerOpts), "could not compile ssl request")
+	require.Equal(t, "ctls", request.ScanMode)
+
+	var gotEvent output.InternalEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput(listener.Addr().String()), nil, nil, func(event *output.InternalWrappedEvent) {
+		gotEvent = event.InternalEvent
+	})
+	require.Nil(t, err, "could not run ssl request")
+	require.Equal(t, "client", <-clientNames)
+	require.Equal(t, []string{"server"}, gotEvent["subject_an"])
+
+	request = &Request{Address: "{{Hostname}}", ClientCert: clientCert, ScanMode: "ztls"}
+	require.ErrorContains(t, request.Compile(executerOpts), "client certificates are not supported with scan mode ztls")
+}
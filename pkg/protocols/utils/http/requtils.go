@@ -47,6 +47,6 @@ func SetHeader(req *retryablehttp.Request, name, value string) {
 
 // ShouldDisableKeepAlive depending on scan strategy
 func ShouldDisableKeepAlive(options *types.Options) bool {
-	// with host-spray strategy keep-alive must be enabled
-	return options.ScanStrategy != scanstrategy.HostSpray.String()
+	// with host-spray strategy or connections reuse keep-alive must be enabled
+	return options.ScanStrategy != scanstrategy.HostSpray.String() && options.HTTPConnectionReuse == ""
 }
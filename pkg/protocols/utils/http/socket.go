@@ -0,0 +1,57 @@
+package httputil
+
+import (
+	"strings"
+)
+
+const (
+	// UnixSocket is the network of the unix domain sockets inputs (ex: unix:///var/run/docker.sock)
+	UnixSocket = "unix"
+	// NamedPipe is the network of the windows named pipes inputs (ex: npipe:////./pipe/docker_engine)
+	NamedPipe = "npipe"
+
+	// SocketBaseURL is the base url of the requests sent to the sockets
+	SocketBaseURL = "http://localhost"
+)
+
+// Socket is the unix domain socket or windows named pipe of an http input
+type Socket struct {
+	// Network is the network of the socket (unix or npipe)
+	Network string
+	// Path is the path of the socket
+	Path string
+	// Input is the input of the socket
+	Input string
+}
+
+// ParseSocket returns the socket of the unix:// and npipe:// inputs
+func ParseSocket(input string) (*Socket, bool) {
+	scheme, path, ok := strings.Cut(input, "://")
+	if !ok || path == "" {
+		return nil, false
+	}
+	switch strings.ToLower(scheme) {
+	case UnixSocket:
+		return &Socket{Network: UnixSocket, Path: path, Input: input}, true
+	case NamedPipe:
+		// the slashes of npipe:////./pipe/name and npipe://./pipe/name are the backslashes of \\.\pipe\name
+		path = `\\` + strings.ReplaceAll(strings.TrimLeft(path, `/\`), "/", `\`)
+		return &Socket{Network: NamedPipe, Path: path, Input: input}, true
+	}
+	return nil, false
+}
+
+// IsSocket returns true if the input is a unix domain socket or a windows named pipe
+func IsSocket(input string) bool {
+	_, ok := ParseSocket(input)
+	return ok
+}
+
+// URL returns the url of the socket for the url of a request sent to it,
+// ex: http://localhost/info is unix:///var/run/docker.sock/info
+func (s *Socket) URL(requestURL string) string {
+	if !strings.HasPrefix(requestURL, SocketBaseURL) {
+		return requestURL
+	}
+	return strings.TrimSuffix(s.Input, "/") + strings.TrimPrefix(requestURL, SocketBaseURL)
+}
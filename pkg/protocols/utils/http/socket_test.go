@@ -0,0 +1,34 @@
+package httputil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSocket(t *testing.T) {
+	tests := []struct {
+		input   string
+		network string
+		path    string
+	}{
+		{"unix:///var/run/docker.sock", UnixSocket, "/var/run/docker.sock"},
+		{"UNIX://relative.sock", UnixSocket, "relative.sock"},
+		{"npipe:////./pipe/docker_engine", NamedPipe, `\\.\pipe\docker_engine`},
+		{"npipe://./pipe/docker_engine", NamedPipe, `\\.\pipe\docker_engine`},
+	}
+	for _, test := range tests {
+		socket, ok := ParseSocket(test.input)
+		require.True(t, ok, "could not parse socket %v", test.input)
+		require.Equal(t, test.network, socket.Network)
+		require.Equal(t, test.path, socket.Path)
+	}
+
+	for _, input := range []string{"https://example.com", "unix://", "/var/run/docker.sock"} {
+		require.False(t, IsSocket(input), "%v is not a socket", input)
+	}
+
+	socket, _ := ParseSocket("unix:///var/run/docker.sock")
+	require.Equal(t, "unix:///var/run/docker.sock/v1.41/info?all=1", socket.URL("http://localhost/v1.41/info?all=1"))
+	require.Equal(t, "https://example.com/info", socket.URL("https://example.com/info"))
+}
@@ -1,11 +1,9 @@
 package websocket
 
 import (
-	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"path"
@@ -18,6 +16,7 @@ import (
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/extractors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
@@ -28,6 +27,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/network/networkclientpool"
 	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
@@ -53,6 +53,19 @@ type Request struct {
 	// description: |
 	//   Headers contains headers for the request.
 	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=headers contains the request headers,description=Headers contains headers for the request"`
+	// description: |
+	//   Origin is the Origin header of the upgrade request.
+	// examples:
+	//   - value: "\"https://{{Hostname}}\""
+	Origin string `yaml:"origin,omitempty" json:"origin,omitempty" jsonschema:"title=origin of the upgrade request,description=Origin header of the upgrade request"`
+	// description: |
+	//   Subprotocols are the subprotocols offered in the upgrade request.
+	//
+	//   The subprotocol negotiated by the server is available as subprotocol.
+	// examples:
+	//   - value: >
+	//       []string{"graphql-ws", "graphql-transport-ws"}
+	Subprotocols []string `yaml:"subprotocols,omitempty" json:"subprotocols,omitempty" jsonschema:"title=subprotocols of the upgrade request,description=Subprotocols offered in the upgrade request"`
 
 	// description: |
 	//   Attack is the type of payload combinations to perform.
@@ -66,6 +79,10 @@ type Request struct {
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Files can also be https urls fetched once per scan, an expected sha256
+	//   checksum can be set with a #sha256=hex url fragment. The urls are
+	//   only fetched with the -allow-remote-payloads option.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the websocket request,description=Payloads contains any payloads for the current request"`
 
 	generator *generators.PayloadGenerator
@@ -86,12 +103,48 @@ type Input struct {
 	//   - value: "\"hex_decode('50494e47')\""
 	Data string `yaml:"data,omitempty" json:"data,omitempty" jsonschema:"title=data to send as input,description=Data is the data to send as the input"`
 	// description: |
+	//   Type is the type of the frame of the data.
+	//
+	//   Default value is text, binary sends the data in a binary frame.
+	// values:
+	//   - "text"
+	//   - "binary"
+	Type string `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"title=type of the frame,description=Type of the frame of the data,enum=text,enum=binary"`
+	// description: |
+	//   Read is the number of messages to read after sending the data.
+	//
+	//   Default value is 1, the inputs without data only read the messages
+	//   sent by the server.
+	// examples:
+	//   - value: "2"
+	Read int `yaml:"read,omitempty" json:"read,omitempty" jsonschema:"title=messages to read,description=Number of messages to read after sending the data"`
+	// description: |
 	//   Name is the optional name of the data read to provide matching on.
 	// examples:
 	//   - value: "\"prefix\""
 	Name string `yaml:"name,omitempty" json:"name,omitempty" jsonschema:"title=optional name for data read,description=Optional name of the data read to provide matching on"`
+	// description: |
+	//   Matchers are the matchers of the messages read by the input.
+	//
+	//   The exchange stops without result when they don't match, the messages
+	//   are available as response and the negotiated subprotocol as subprotocol.
+	Matchers []*matchers.Matcher `yaml:"matchers,omitempty" json:"matchers,omitempty" jsonschema:"title=matchers of the input,description=Matchers of the messages read by the input"`
+	// description: |
+	//   MatchersCondition is the condition between the matchers of the input.
+	// values:
+	//   - "and"
+	//   - "or"
+	MatchersCondition string `yaml:"matchers-condition,omitempty" json:"matchers-condition,omitempty" jsonschema:"title=condition between the matchers,description=Conditions between the matchers of the input,enum=and,enum=or"`
+
+	operators *operators.Operators
 }
 
+// frame types of the inputs
+const (
+	textType   = "text"
+	binaryType = "binary"
+)
+
 const (
 	parseUrlErrorMessage                   = "could not parse input url"
 	evaluateTemplateExpressionErrorMessage = "could not evaluate template expressions"
@@ -114,6 +167,23 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		}
 	}
 
+	for i, input := range request.Inputs {
+		switch input.Type {
+		case "", textType, binaryType:
+		default:
+			return errors.Errorf("invalid type %q of input %d", input.Type, i+1)
+		}
+		if input.Read < 0 {
+			return errors.Errorf("invalid read %d of input %d", input.Read, i+1)
+		}
+		if len(input.Matchers) > 0 {
+			input.operators = &operators.Operators{Matchers: input.Matchers, MatchersCondition: input.MatchersCondition, TemplateID: options.TemplateID}
+			if err := input.operators.Compile(); err != nil {
+				return errors.Wrapf(err, "could not compile matchers of input %d", i+1)
+			}
+		}
+	}
+
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
@@ -192,6 +262,15 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 		}
 		header.Set(key, string(finalData))
 	}
+	if request.Origin != "" {
+		origin, dataErr := expressions.Evaluate(request.Origin, payloadValues)
+		if dataErr != nil {
+			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), dataErr)
+			requestOptions.Progress.IncrementFailedRequestsBy(1)
+			return errors.Wrap(dataErr, evaluateTemplateExpressionErrorMessage)
+		}
+		header.Set("Origin", origin)
+	}
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: true,
 		ServerName:         hostname,
@@ -200,10 +279,12 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	if requestOptions.Options.SNI != "" {
 		tlsConfig.ServerName = requestOptions.Options.SNI
 	}
+	fips.RestrictTLSConfig(tlsConfig)
 	websocketDialer := ws.Dialer{
 		Header:    ws.HandshakeHeaderHTTP(header),
+		Protocols: request.Subprotocols,
 		Timeout:   time.Duration(requestOptions.Options.Timeout) * time.Second,
-		NetDial:   request.dialer.Dial,
+		NetDial:   protocolstate.WithIPVersion(request.dialer.Dial),
 		TLSConfig: tlsConfig,
 	}
 
@@ -228,7 +309,7 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	parsedAddress.Path = path.Join(parsedAddress.Path, parsed.Path)
 	addressToDial = parsedAddress.String()
 
-	conn, readBuffer, _, err := websocketDialer.Dial(context.Background(), addressToDial)
+	conn, readBuffer, handshake, err := websocketDialer.Dial(target.Context(), addressToDial)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
@@ -237,24 +318,40 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	defer conn.Close()
 
 	responseBuilder := &strings.Builder{}
+	// the frames sent by the server right after the handshake are buffered by the dialer
+	var rw io.ReadWriter = conn
 	if readBuffer != nil {
-		_, _ = io.Copy(responseBuilder, readBuffer) // Copy initial response
+		rw = struct {
+			io.Reader
+			io.Writer
+		}{readBuffer, conn}
 	}
 
-	events, requestOutput, err := request.readWriteInputWebsocket(conn, payloadValues, input, responseBuilder)
+	events, requestOutput, matched, err := request.readWriteInputWebsocket(rw, payloadValues, input, handshake.Protocol, responseBuilder)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
 		return errors.Wrap(err, "could not read write response")
 	}
 	requestOptions.Progress.IncrementRequests()
+	if !matched {
+		gologger.Verbose().Msgf("[%s] Websocket exchange with %s stopped by the matchers of an input", requestOptions.TemplateID, input)
+		return nil
+	}
 
 	if requestOptions.Options.Debug || requestOptions.Options.DebugRequests {
 		gologger.Debug().Str("address", input).Msgf("[%s] Dumped Websocket request for %s", requestOptions.TemplateID, input)
 		gologger.Print().Msgf("%s", requestOutput)
 	}
 
-	requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
+	requestOptions.Output.LogRequest(&output.RequestEvent{
+		Template:      requestOptions.TemplateID,
+		Input:         input,
+		Type:          request.Type().String(),
+		BytesSent:     len(requestOutput),
+		BytesReceived: responseBuilder.Len(),
+		Error:         err,
+	})
 	gologger.Verbose().Msgf("Sent Websocket request to %s", input)
 
 	data := make(map[string]interface{})
@@ -265,6 +362,7 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	data["response"] = responseBuilder.String()
 	data["host"] = input
 	data["matched"] = addressToDial
+	data["subprotocol"] = handshake.Protocol
 	data["ip"] = request.dialer.GetDialedIP(hostname)
 
 	// add response fields to template context and merge templatectx variables to output event
@@ -291,44 +389,60 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	return nil
 }
 
-func (request *Request) readWriteInputWebsocket(conn net.Conn, payloadValues map[string]interface{}, input string, respBuilder *strings.Builder) (events map[string]interface{}, req string, err error) {
+// readWriteInputWebsocket performs the exchanges of the inputs, the returned bool is false
+// when the matchers of an input stopped the exchange
+func (request *Request) readWriteInputWebsocket(conn io.ReadWriter, payloadValues map[string]interface{}, input, subprotocol string, respBuilder *strings.Builder) (events map[string]interface{}, req string, matched bool, err error) {
 	reqBuilder := &strings.Builder{}
 	inputEvents := make(map[string]interface{})
 
 	requestOptions := request.options
 	for _, req := range request.Inputs {
-		reqBuilder.Grow(len(req.Data))
-
-		finalData, dataErr := expressions.EvaluateByte([]byte(req.Data), payloadValues)
-		if dataErr != nil {
-			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), dataErr)
-			requestOptions.Progress.IncrementFailedRequestsBy(1)
-			return nil, "", errors.Wrap(dataErr, evaluateTemplateExpressionErrorMessage)
-		}
-		reqBuilder.WriteString(string(finalData))
+		if req.Data != "" {
+			reqBuilder.Grow(len(req.Data))
+
+			finalData, dataErr := expressions.EvaluateByte([]byte(req.Data), payloadValues)
+			if dataErr != nil {
+				requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), dataErr)
+				requestOptions.Progress.IncrementFailedRequestsBy(1)
+				return nil, "", false, errors.Wrap(dataErr, evaluateTemplateExpressionErrorMessage)
+			}
+			reqBuilder.WriteString(string(finalData))
 
-		err = wsutil.WriteClientMessage(conn, ws.OpText, finalData)
-		if err != nil {
-			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
-			requestOptions.Progress.IncrementFailedRequestsBy(1)
-			return nil, "", errors.Wrap(err, "could not write request to server")
+			opCode := ws.OpText
+			if req.Type == binaryType {
+				opCode = ws.OpBinary
+			}
+			err = wsutil.WriteClientMessage(conn, opCode, finalData)
+			if err != nil {
+				requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
+				requestOptions.Progress.IncrementFailedRequestsBy(1)
+				return nil, "", false, errors.Wrap(err, "could not write request to server")
+			}
 		}
 
-		msg, opCode, err := wsutil.ReadServerData(conn)
-		if err != nil {
-			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
-			requestOptions.Progress.IncrementFailedRequestsBy(1)
-			return nil, "", errors.Wrap(err, "could not write request to server")
+		read := req.Read
+		if read == 0 {
+			read = 1
 		}
-		// Only perform matching and writes in case we receive
-		// text or binary opcode from the websocket server.
-		if opCode != ws.OpText && opCode != ws.OpBinary {
-			continue
+		stepBuilder := &strings.Builder{}
+		for i := 0; i < read; i++ {
+			msg, opCode, err := wsutil.ReadServerData(conn)
+			if err != nil {
+				requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
+				requestOptions.Progress.IncrementFailedRequestsBy(1)
+				return nil, "", false, errors.Wrap(err, "could not read response from server")
+			}
+			// Only perform matching and writes in case we receive
+			// text or binary opcode from the websocket server.
+			if opCode != ws.OpText && opCode != ws.OpBinary {
+				continue
+			}
+			respBuilder.Write(msg)
+			stepBuilder.Write(msg)
 		}
 
-		respBuilder.Write(msg)
+		bufferStr := stepBuilder.String()
 		if req.Name != "" {
-			bufferStr := string(msg)
 			inputEvents[req.Name] = bufferStr
 
 			// Run any internal extractors for the request here and add found values to map.
@@ -339,8 +453,14 @@ func (request *Request) readWriteInputWebsocket(conn net.Conn, payloadValues map
 				}
 			}
 		}
+		if req.operators != nil {
+			data := generators.MergeMaps(payloadValues, inputEvents, map[string]interface{}{"response": bufferStr, "subprotocol": subprotocol})
+			if _, ok := req.operators.Execute(data, request.Match, request.Extract, false); !ok {
+				return inputEvents, reqBuilder.String(), false, nil
+			}
+		}
 	}
-	return inputEvents, reqBuilder.String(), nil
+	return inputEvents, reqBuilder.String(), true, nil
 }
 
 // getAddress returns the address of the host to make request to
@@ -386,12 +506,13 @@ func (request *Request) GetCompiledOperators() []*operators.Operators {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"type":     "Type is the type of request made",
-	"success":  "Success specifies whether websocket connection was successful",
-	"request":  "Websocket request made to the server",
-	"response": "Websocket response received from the server",
-	"host":     "Host is the input to the template",
-	"matched":  "Matched is the input which was matched upon",
+	"type":        "Type is the type of request made",
+	"success":     "Success specifies whether websocket connection was successful",
+	"request":     "Websocket request made to the server",
+	"response":    "Websocket response received from the server",
+	"host":        "Host is the input to the template",
+	"matched":     "Matched is the input which was matched upon",
+	"subprotocol": "Subprotocol is the subprotocol negotiated by the server",
 }
 
 func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent) *output.ResultEvent {
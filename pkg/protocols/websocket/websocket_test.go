@@ -0,0 +1,133 @@
+package websocket
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+)
+
+// newServer returns a server greeting the clients and echoing their messages with their frame type
+func newServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "https://example.com" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		upgrader := ws.HTTPUpgrader{Protocol: func(protocol string) bool { return protocol == "chat" }}
+		conn, _, _, err := upgrader.Upgrade(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_ = wsutil.WriteServerMessage(conn, ws.OpText, []byte("hello"))
+		for {
+			msg, opCode, err := wsutil.ReadClientData(conn)
+			if err != nil {
+				return
+			}
+			kind := "text:"
+			if opCode == ws.OpBinary {
+				kind = "binary:"
+			}
+			_ = wsutil.WriteServerMessage(conn, opCode, append([]byte(kind), msg...))
+			_ = wsutil.WriteServerMessage(conn, opCode, []byte("done"))
+		}
+	}))
+}
+
+func TestWebsocketExchanges(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	ts := newServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		inputs  []*Input
+		matched bool
+	}{
+		{
+			name: "binary",
+			inputs: []*Input{
+				{Name: "greeting", Matchers: []*matchers.Matcher{{Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher}, DSL: []string{"subprotocol == 'chat'", "response == 'hello'"}}}},
+				{Data: "{{hex_decode('00ff')}}", Type: "binary", Read: 2},
+			},
+			matched: true,
+		},
+		{
+			name: "stopped",
+			inputs: []*Input{
+				{Data: "ping", Matchers: []*matchers.Matcher{{Type: matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher}, Part: "response", Words: []string{"pong"}}}},
+				{Data: "never sent"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			templateID := "testing-websocket"
+			request := &Request{
+				ID:           templateID,
+				Address:      "{{Scheme}}://{{Hostname}}",
+				Origin:       "https://{{host}}",
+				Subprotocols: []string{"graphql-ws", "chat"},
+				Inputs:       test.inputs,
+				Payloads:     map[string]interface{}{"host": []string{"example.com"}},
+				Operators: operators.Operators{
+					Matchers: []*matchers.Matcher{{
+						Type:   matchers.MatcherTypeHolder{MatcherType: matchers.BinaryMatcher},
+						Part:   "response",
+						Binary: []string{hex.EncodeToString([]byte("binary:\x00\xffdone"))},
+					}},
+				},
+			}
+			executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+				ID:   templateID,
+				Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+			})
+			err := request.Compile(executerOpts)
+			require.Nil(t, err, "could not compile websocket request")
+
+			var events []*output.InternalWrappedEvent
+			err = request.ExecuteWithResults(contextargs.NewWithInput(strings.Replace(ts.URL, "http", "ws", 1)), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+				events = append(events, event)
+			})
+			require.Nil(t, err, "could not execute websocket request")
+			if !test.matched {
+				require.Empty(t, events, "exchange should be stopped by the input matchers")
+				return
+			}
+			require.Len(t, events, 1)
+			require.True(t, events[0].OperatorsResult.Matched, "could not match websocket response")
+			require.Equal(t, "chat", events[0].InternalEvent["subprotocol"])
+			require.Equal(t, "hello", events[0].InternalEvent["greeting"])
+		})
+	}
+}
+
+func TestCompileInputs(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{ID: "testing-websocket"})
+
+	request := &Request{Inputs: []*Input{{Data: "a", Type: "hex"}}}
+	require.EqualError(t, request.Compile(executerOpts), `invalid type "hex" of input 1`)
+	request = &Request{Inputs: []*Input{{Data: "a"}, {Read: -1}}}
+	require.EqualError(t, request.Compile(executerOpts), "invalid read -1 of input 2")
+}
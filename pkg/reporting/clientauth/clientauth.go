@@ -0,0 +1,128 @@
+// Package clientauth configures the http clients of reporting trackers and exporters
+// to present client certificates (mTLS) and send oauth2 client credentials tokens.
+package clientauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Options contains the client authentication options of a reporting endpoint
+type Options struct {
+	// ClientCert (optional) is the PEM client certificate file presented to the endpoint
+	ClientCert string `yaml:"client-cert,omitempty" json:"client_cert,omitempty" validate:"required_with=ClientKey"`
+	// ClientKey (optional) is the PEM private key file of the client certificate
+	ClientKey string `yaml:"client-key,omitempty" json:"client_key,omitempty" validate:"required_with=ClientCert"`
+	// CACert (optional) is the PEM CA certificate file used to verify the endpoint
+	CACert string `yaml:"ca-cert,omitempty" json:"ca_cert,omitempty"`
+	// OAuth (optional) fetches tokens with the oauth2 client credentials flow,
+	// they are sent in the Authorization header instead of the configured credentials
+	OAuth *OAuthOptions `yaml:"oauth,omitempty" json:"oauth,omitempty"`
+}
+
+// OAuthOptions contains the oauth2 client credentials flow configuration
+type OAuthOptions struct {
+	// TokenURL is the token endpoint of the authorization server
+	TokenURL string `yaml:"token-url" json:"token_url" validate:"required,url"`
+	// ClientID is the id of the oauth client
+	ClientID string `yaml:"client-id" json:"client_id" validate:"required"`
+	// ClientSecret is the secret of the oauth client
+	ClientSecret string `yaml:"client-secret" json:"client_secret"`
+	// Scopes (optional) are the scopes requested for the token
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	// EndpointParams (optional) are additional token request parameters, e.g. audience
+	EndpointParams map[string]string `yaml:"endpoint-params,omitempty" json:"endpoint_params,omitempty"`
+}
+
+// IsConfigured returns true if any client authentication is configured
+func (o *Options) IsConfigured() bool {
+	return o.ClientCert != "" || o.CACert != "" || o.OAuth != nil
+}
+
+// AuthClient returns a copy of client presenting the client certificate and sending the
+// oauth tokens of the options, client is returned unchanged if nothing is configured.
+// A nil client uses the default transport.
+func (o *Options) AuthClient(client *http.Client) (*http.Client, error) {
+	if !o.IsConfigured() {
+		return client, nil
+	}
+	authClient := &http.Client{}
+	if client != nil {
+		*authClient = *client
+	}
+	transport := authClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if o.ClientCert != "" || o.CACert != "" {
+		httpTransport, ok := transport.(*http.Transport)
+		if !ok {
+			return nil, errors.New("client certificates require an http transport")
+		}
+		httpTransport = httpTransport.Clone()
+		tlsConfig, err := o.tlsConfig(httpTransport.TLSClientConfig)
+		if err != nil {
+			return nil, err
+		}
+		httpTransport.TLSClientConfig = tlsConfig
+		// custom tls dialers ignore the tls configuration of the transport
+		httpTransport.DialTLSContext = nil
+		transport = httpTransport
+	}
+
+	if o.OAuth != nil {
+		config := &clientcredentials.Config{
+			ClientID:     o.OAuth.ClientID,
+			ClientSecret: o.OAuth.ClientSecret,
+			TokenURL:     o.OAuth.TokenURL,
+			Scopes:       o.OAuth.Scopes,
+		}
+		if len(o.OAuth.EndpointParams) > 0 {
+			config.EndpointParams = make(url.Values, len(o.OAuth.EndpointParams))
+			for key, value := range o.OAuth.EndpointParams {
+				config.EndpointParams.Set(key, value)
+			}
+		}
+		// tokens are fetched with the same transport to present the client certificate
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: transport, Timeout: authClient.Timeout})
+		transport = &oauth2.Transport{Source: config.TokenSource(ctx), Base: transport}
+	}
+	authClient.Transport = transport
+	return authClient, nil
+}
+
+// tlsConfig returns a copy of base with the client certificate and ca of the options
+func (o *Options) tlsConfig(base *tls.Config) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if base != nil {
+		config = base.Clone()
+	}
+	if o.ClientCert != "" {
+		certificate, err := tls.LoadX509KeyPair(o.ClientCert, o.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load client certificate")
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	if o.CACert != "" {
+		data, err := os.ReadFile(o.CACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read ca certificate")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.Errorf("no certificate found in %s", o.CACert)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
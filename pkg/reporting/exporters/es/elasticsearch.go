@@ -15,6 +15,7 @@ import (
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/clientauth"
 	"github.com/projectdiscovery/retryablehttp-go"
 )
 
@@ -30,12 +31,14 @@ type Options struct {
 	SSL bool `yaml:"ssl"`
 	// SSLVerification (optional) disables SSL verification for elasticsearch
 	SSLVerification bool `yaml:"ssl-verification"`
-	// Username for the elasticsearch instance
-	Username string `yaml:"username"  validate:"required"`
-	// Password is the password for elasticsearch instance
-	Password string `yaml:"password"  validate:"required"`
+	// Username for the elasticsearch instance, optional with oauth
+	Username string `yaml:"username"  validate:"required_without=OAuth"`
+	// Password is the password for elasticsearch instance, optional with oauth
+	Password string `yaml:"password"  validate:"required_without=OAuth"`
 	// IndexName is the name of the elasticsearch index
 	IndexName string `yaml:"index-name"  validate:"required"`
+	// Options contains the client certificate and oauth configuration
+	clientauth.Options `yaml:",inline"`
 
 	HttpClient *retryablehttp.Client `yaml:"-"`
 }
@@ -71,6 +74,10 @@ func New(option *Options) (*Exporter, error) {
 			},
 		}
 	}
+	client, err := option.AuthClient(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not configure client authentication")
+	}
 
 	// preparing url for elasticsearch
 	scheme := "http://"
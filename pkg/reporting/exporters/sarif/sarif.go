@@ -5,10 +5,12 @@ import (
 	"math"
 	"os"
 	"path"
+	"strings"
 	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/compliance"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/sarif"
 )
@@ -107,6 +109,29 @@ func (exporter *Exporter) Export(event *output.ResultEvent) error {
 	ghMeta["tags"] = []string{"security"}
 	ghMeta["security-severity"] = vulnRating
 
+	// compliance classification of the template, cwes are added as tags
+	// so that they are displayed by GitHub code scanning
+	mapping := event.Compliance
+	if mapping == nil {
+		mapping = compliance.Map(&event.Info)
+	}
+	if !mapping.IsEmpty() {
+		tags := []string{"security"}
+		for _, cwe := range mapping.CWE {
+			tags = append(tags, "external/cwe/"+strings.ToLower(cwe))
+		}
+		ghMeta["tags"] = tags
+		if len(mapping.OWASP) > 0 {
+			ghMeta["owasp"] = mapping.OWASP
+		}
+		if len(mapping.CWE) > 0 {
+			ghMeta["cwe"] = mapping.CWE
+		}
+		if len(mapping.Attack) > 0 {
+			ghMeta["attack"] = mapping.Attack
+		}
+	}
+
 	// rule contain details of template
 	rule := sarif.ReportingDescriptor{
 		Id:   event.TemplateID,
@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/clientauth"
 	"github.com/projectdiscovery/retryablehttp-go"
 )
 
@@ -29,6 +30,8 @@ type Options struct {
 	// Token for HEC instance
 	Token     string `yaml:"token"  validate:"required"`
 	IndexName string `yaml:"index-name"  validate:"required"`
+	// Options contains the client certificate and oauth configuration
+	clientauth.Options `yaml:",inline"`
 
 	HttpClient *retryablehttp.Client `yaml:"-"`
 }
@@ -63,6 +66,10 @@ func New(option *Options) (*Exporter, error) {
 			},
 		}
 	}
+	client, err := option.AuthClient(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not configure client authentication")
+	}
 
 	// preparing url for splunk
 	scheme := "http://"
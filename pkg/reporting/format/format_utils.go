@@ -67,7 +67,7 @@ func CreateReportDescription(event *output.ResultEvent, formatter ResultFormatte
 		builder.WriteString(formatter.CreateCodeBlock("Response", responseString, "http"))
 	}
 
-	if len(event.ExtractedResults) > 0 || len(event.Metadata) > 0 {
+	if len(event.ExtractedResults) > 0 || len(event.Metadata) > 0 || len(event.Artifacts) > 0 {
 		builder.WriteString("\n")
 		builder.WriteString(formatter.MakeBold("Extra Information"))
 		builder.WriteString("\n\n")
@@ -95,6 +95,16 @@ func CreateReportDescription(event *output.ResultEvent, formatter ResultFormatte
 			}
 			builder.WriteString("\n")
 		}
+		if len(event.Artifacts) > 0 {
+			builder.WriteString(formatter.MakeBold("Artifacts:"))
+			builder.WriteString("\n\n")
+			for _, v := range event.Artifacts {
+				builder.WriteString("- ")
+				builder.WriteString(v)
+				builder.WriteString("\n")
+			}
+			builder.WriteString("\n")
+		}
 	}
 	if event.Interaction != nil {
 		builder.WriteString(fmt.Sprintf("%s\n%s", formatter.MakeBold("Interaction Data"), formatter.CreateHorizontalLine()))
@@ -6,6 +6,7 @@ import (
 	"github.com/google/go-github/github"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/clientauth"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/markdown/util"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/format"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
@@ -43,25 +44,32 @@ type Options struct {
 	// DuplicateIssueCheck (optional) comments under existing finding issue
 	// instead of creating duplicates for subsequent runs.
 	DuplicateIssueCheck bool `yaml:"duplicate-issue-check"`
+	// Options contains the client certificate and oauth configuration
+	clientauth.Options `yaml:",inline"`
 
 	HttpClient *retryablehttp.Client `yaml:"-"`
 }
 
 // New creates a new issue tracker integration client based on options.
 func New(options *Options) (*Integration, error) {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: options.Token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-
 	// patch transport to support proxy - only http
 	// TODO: investigate if it's possible to reuse existing retryablehttp
+	transport := http.DefaultTransport.(*http.Transport).Clone()
 	if types.ProxyURL != "" {
 		if proxyURL, err := url.Parse(types.ProxyURL); err == nil {
-			tc.Transport.(*http.Transport).Proxy = http.ProxyURL(proxyURL)
+			transport.Proxy = http.ProxyURL(proxyURL)
 		}
 	}
+	httpClient, err := options.AuthClient(&http.Client{Transport: transport})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not configure client authentication")
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: options.Token},
+	)
+	tc := oauth2.NewClient(ctx, ts)
 
 	client := github.NewClient(tc)
 	if options.BaseURL != "" {
@@ -2,10 +2,13 @@ package gitlab
 
 import (
 	"fmt"
+	"net/http"
 
+	"github.com/pkg/errors"
 	"github.com/xanzy/go-gitlab"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/clientauth"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/markdown/util"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/format"
 	"github.com/projectdiscovery/retryablehttp-go"
@@ -35,6 +38,8 @@ type Options struct {
 	SeverityAsLabel bool `yaml:"severity-as-label"`
 	// DuplicateIssueCheck is a bool to enable duplicate tracking issue check and update the newest
 	DuplicateIssueCheck bool `yaml:"duplicate-issue-check" default:"false"`
+	// Options contains the client certificate and oauth configuration
+	clientauth.Options `yaml:",inline"`
 
 	HttpClient *retryablehttp.Client `yaml:"-"`
 }
@@ -45,8 +50,16 @@ func New(options *Options) (*Integration, error) {
 	if options.BaseURL != "" {
 		gitlabOpts = append(gitlabOpts, gitlab.WithBaseURL(options.BaseURL))
 	}
+	var httpClient *http.Client
 	if options.HttpClient != nil {
-		gitlabOpts = append(gitlabOpts, gitlab.WithHTTPClient(options.HttpClient.HTTPClient))
+		httpClient = options.HttpClient.HTTPClient
+	}
+	httpClient, err := options.AuthClient(httpClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not configure client authentication")
+	}
+	if httpClient != nil {
+		gitlabOpts = append(gitlabOpts, gitlab.WithHTTPClient(httpClient))
 	}
 	git, err := gitlab.NewClient(options.Token, gitlabOpts...)
 	if err != nil {
@@ -94,10 +107,9 @@ func (i *Integration) CreateIssue(event *output.ResultEvent) error {
 			}
 			if issue.State == "closed" {
 				reopen := "reopen"
-				_, resp, err := i.client.Issues.UpdateIssue(i.options.ProjectName, issue.IID, &gitlab.UpdateIssueOptions{
+				_, _, err = i.client.Issues.UpdateIssue(i.options.ProjectName, issue.IID, &gitlab.UpdateIssueOptions{
 					StateEvent: &reopen,
 				})
-				fmt.Sprintln(resp, err)
 			}
 			return err
 		}
@@ -3,13 +3,16 @@ package jira
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
 	"github.com/trivago/tgo/tcontainer"
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/clientauth"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/markdown/util"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/format"
 	"github.com/projectdiscovery/retryablehttp-go"
@@ -77,6 +80,8 @@ type Options struct {
 	// that will be used to create the issue
 	CustomFields map[string]interface{} `yaml:"custom-fields" json:"custom_fields"`
 	StatusNot    string                 `yaml:"status-not" json:"status_not"`
+	// Options contains the client certificate and oauth configuration
+	clientauth.Options `yaml:",inline"`
 }
 
 // New creates a new issue tracker integration client based on options.
@@ -92,6 +97,13 @@ func New(options *Options) (*Integration, error) {
 	if options.HttpClient != nil {
 		tp.Transport = options.HttpClient.HTTPClient.Transport
 	}
+	if options.IsConfigured() {
+		httpClient, err := options.AuthClient(&http.Client{Transport: tp.Transport})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not configure client authentication")
+		}
+		tp.Transport = httpClient.Transport
+	}
 	jiraClient, err := jira.NewClient(tp.Client(), options.URL)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,3 @@
+// Package scanstats collects request, error and latency statistics
+// during a scan and builds an end of scan report from them.
+package scanstats
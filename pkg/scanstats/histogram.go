@@ -0,0 +1,116 @@
+package scanstats
+
+import (
+	"time"
+)
+
+// histogramBuckets are the upper bounds of the latency histogram buckets,
+// durations above the last bound are counted in an overflow bucket
+var histogramBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// histogram is a fixed bucket latency histogram
+type histogram struct {
+	counts []int
+	count  int
+	total  time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+func newHistogram() *histogram {
+	return &histogram{counts: make([]int, len(histogramBuckets)+1)}
+}
+
+// observe records a duration in the histogram
+func (h *histogram) observe(duration time.Duration) {
+	index := len(histogramBuckets)
+	for i, bound := range histogramBuckets {
+		if duration <= bound {
+			index = i
+			break
+		}
+	}
+	h.counts[index]++
+	if h.count == 0 || duration < h.min {
+		h.min = duration
+	}
+	if duration > h.max {
+		h.max = duration
+	}
+	h.count++
+	h.total += duration
+}
+
+// percentile returns the upper bound of the bucket containing the
+// given percentile, or the maximum observed duration for the overflow bucket
+func (h *histogram) percentile(percentile float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	rank := int(float64(h.count)*percentile + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	var cumulative int
+	for i, count := range h.counts {
+		cumulative += count
+		if cumulative >= rank {
+			if i < len(histogramBuckets) && histogramBuckets[i] < h.max {
+				return histogramBuckets[i]
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+// Latency is the summary of a latency histogram
+type Latency struct {
+	Count   int      `json:"count"`
+	Min     Duration `json:"min"`
+	Max     Duration `json:"max"`
+	Average Duration `json:"average"`
+	P50     Duration `json:"p50"`
+	P90     Duration `json:"p90"`
+	P99     Duration `json:"p99"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Bucket is a histogram bucket counting durations up to LessOrEqual,
+// the overflow bucket has an empty LessOrEqual
+type Bucket struct {
+	LessOrEqual string `json:"le,omitempty"`
+	Count       int    `json:"count"`
+}
+
+func (h *histogram) summary() *Latency {
+	if h.count == 0 {
+		return nil
+	}
+	latency := &Latency{
+		Count:   h.count,
+		Min:     Duration(h.min),
+		Max:     Duration(h.max),
+		Average: Duration(h.total / time.Duration(h.count)),
+		P50:     Duration(h.percentile(0.5)),
+		P90:     Duration(h.percentile(0.9)),
+		P99:     Duration(h.percentile(0.99)),
+	}
+	for i, count := range h.counts {
+		bucket := Bucket{Count: count}
+		if i < len(histogramBuckets) {
+			bucket.LessOrEqual = histogramBuckets[i].String()
+		}
+		latency.Buckets = append(latency.Buckets, bucket)
+	}
+	return latency
+}
@@ -0,0 +1,253 @@
+package scanstats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// reportLimit is the number of templates and hosts listed in the report
+const reportLimit = 10
+
+// Duration is a time.Duration written as a human readable string in json
+type Duration time.Duration
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// String returns the duration rounded to milliseconds
+func (d Duration) String() string {
+	return time.Duration(d).Round(time.Millisecond).String()
+}
+
+// Report is the end of scan statistics report
+type Report struct {
+	StartedAt        time.Time         `json:"started_at"`
+	Duration         Duration          `json:"duration"`
+	Requests         int               `json:"requests"`
+	Errors           int               `json:"errors"`
+	Protocols        []*ProtocolReport `json:"protocols,omitempty"`
+	ErrorBreakdown   map[string]int    `json:"error_breakdown,omitempty"`
+	SlowestTemplates []*TemplateReport `json:"slowest_templates,omitempty"`
+	Hosts            []*HostReport     `json:"hosts,omitempty"`
+}
+
+// ProtocolReport contains the statistics of a protocol
+type ProtocolReport struct {
+	Protocol string `json:"protocol"`
+	Requests int    `json:"requests"`
+	Errors   int    `json:"errors"`
+	// Latency is only available for protocols reporting response times
+	Latency *Latency `json:"latency,omitempty"`
+}
+
+// TemplateReport contains the execution times and requests of a template
+type TemplateReport struct {
+	ID         string   `json:"template_id"`
+	Executions int      `json:"executions"`
+	Total      Duration `json:"total"`
+	Average    Duration `json:"average"`
+	Max        Duration `json:"max"`
+	Requests   int      `json:"requests"`
+	Errors     int      `json:"errors"`
+}
+
+// HostReport contains the request failures of a host
+type HostReport struct {
+	Host        string  `json:"host"`
+	Requests    int     `json:"requests"`
+	Errors      int     `json:"errors"`
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// Report builds the report from the statistics collected so far
+func (c *Collector) Report() *Report {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	report := &Report{
+		StartedAt:      c.started,
+		Duration:       Duration(time.Since(c.started)),
+		ErrorBreakdown: make(map[string]int, len(c.errors)),
+	}
+	for name, stats := range c.protocols {
+		report.Requests += stats.requests
+		report.Errors += stats.errors
+		report.Protocols = append(report.Protocols, &ProtocolReport{
+			Protocol: name,
+			Requests: stats.requests,
+			Errors:   stats.errors,
+			Latency:  stats.latency.summary(),
+		})
+	}
+	sort.Slice(report.Protocols, func(i, j int) bool {
+		return report.Protocols[i].Protocol < report.Protocols[j].Protocol
+	})
+	for kind, count := range c.errors {
+		report.ErrorBreakdown[kind] = count
+	}
+
+	report.SlowestTemplates = c.templateProfile()
+	if len(report.SlowestTemplates) > reportLimit {
+		report.SlowestTemplates = report.SlowestTemplates[:reportLimit]
+	}
+
+	for host, stats := range c.hosts {
+		if stats.errors == 0 {
+			continue
+		}
+		report.Hosts = append(report.Hosts, &HostReport{
+			Host:        host,
+			Requests:    stats.requests,
+			Errors:      stats.errors,
+			FailureRate: float64(stats.errors) / float64(stats.requests),
+		})
+	}
+	sort.Slice(report.Hosts, func(i, j int) bool {
+		first, second := report.Hosts[i], report.Hosts[j]
+		if first.FailureRate != second.FailureRate {
+			return first.FailureRate > second.FailureRate
+		}
+		if first.Errors != second.Errors {
+			return first.Errors > second.Errors
+		}
+		return first.Host < second.Host
+	})
+	if len(report.Hosts) > reportLimit {
+		report.Hosts = report.Hosts[:reportLimit]
+	}
+	return report
+}
+
+// TemplateProfile is the list of executed templates ranked by total execution time
+type TemplateProfile []*TemplateReport
+
+// TemplateProfile returns the statistics of all the executed templates ranked by total execution time
+func (c *Collector) TemplateProfile() TemplateProfile {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.templateProfile()
+}
+
+func (c *Collector) templateProfile() TemplateProfile {
+	profile := make(TemplateProfile, 0, len(c.templates))
+	for id, stats := range c.templates {
+		// templates which only sent requests were not executed by the scan, e.g. workflow subtemplates
+		if stats.executions == 0 {
+			continue
+		}
+		profile = append(profile, &TemplateReport{
+			ID:         id,
+			Executions: stats.executions,
+			Total:      Duration(stats.total),
+			Average:    Duration(stats.total / time.Duration(stats.executions)),
+			Max:        Duration(stats.max),
+			Requests:   stats.requests,
+			Errors:     stats.errors,
+		})
+	}
+	sort.Slice(profile, func(i, j int) bool {
+		first, second := profile[i], profile[j]
+		if first.Total != second.Total {
+			return first.Total > second.Total
+		}
+		return first.ID < second.ID
+	})
+	return profile
+}
+
+// WriteJSON writes the profile in json format to a file
+func (p TemplateProfile) WriteJSON(file string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+// String returns the profile as human readable text
+func (p TemplateProfile) String() string {
+	builder := &strings.Builder{}
+	var total time.Duration
+	for _, template := range p {
+		total += time.Duration(template.Total)
+	}
+	fmt.Fprintf(builder, "Template profile (%d templates, %s total execution time)\n\n", len(p), Duration(total))
+
+	writer := tabwriter.NewWriter(builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "RANK\tTEMPLATE\tEXECUTIONS\tTOTAL\tSHARE\tAVG\tMAX\tREQUESTS\tERRORS\n")
+	for i, template := range p {
+		var share float64
+		if total > 0 {
+			share = float64(template.Total) / float64(total) * 100
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%d\t%s\t%.1f%%\t%s\t%s\t%d\t%d\n", i+1, template.ID, template.Executions, template.Total, share, template.Average, template.Max, template.Requests, template.Errors)
+	}
+	_ = writer.Flush()
+	return builder.String()
+}
+
+// WriteJSON writes the report in json format to a file
+func (r *Report) WriteJSON(file string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+// String returns the report as human readable text
+func (r *Report) String() string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "Scan statistics (duration %s, %d requests, %d errors)\n", r.Duration, r.Requests, r.Errors)
+
+	writer := tabwriter.NewWriter(builder, 0, 0, 2, ' ', 0)
+	if len(r.Protocols) > 0 {
+		fmt.Fprintf(writer, "\nPROTOCOL\tREQUESTS\tERRORS\tAVG\tP50\tP90\tP99\tMAX\n")
+		for _, protocol := range r.Protocols {
+			if protocol.Latency == nil {
+				fmt.Fprintf(writer, "%s\t%d\t%d\t-\t-\t-\t-\t-\n", protocol.Protocol, protocol.Requests, protocol.Errors)
+				continue
+			}
+			latency := protocol.Latency
+			fmt.Fprintf(writer, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n", protocol.Protocol, protocol.Requests, protocol.Errors, latency.Average, latency.P50, latency.P90, latency.P99, latency.Max)
+		}
+	}
+	if len(r.ErrorBreakdown) > 0 {
+		kinds := make([]string, 0, len(r.ErrorBreakdown))
+		for kind := range r.ErrorBreakdown {
+			kinds = append(kinds, kind)
+		}
+		sort.Slice(kinds, func(i, j int) bool {
+			if r.ErrorBreakdown[kinds[i]] != r.ErrorBreakdown[kinds[j]] {
+				return r.ErrorBreakdown[kinds[i]] > r.ErrorBreakdown[kinds[j]]
+			}
+			return kinds[i] < kinds[j]
+		})
+		fmt.Fprintf(writer, "\nERROR\tCOUNT\n")
+		for _, kind := range kinds {
+			fmt.Fprintf(writer, "%s\t%d\n", kind, r.ErrorBreakdown[kind])
+		}
+	}
+	if len(r.SlowestTemplates) > 0 {
+		fmt.Fprintf(writer, "\nTEMPLATE\tEXECUTIONS\tTOTAL\tAVG\tMAX\tREQUESTS\tERRORS\n")
+		for _, template := range r.SlowestTemplates {
+			fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\t%d\t%d\n", template.ID, template.Executions, template.Total, template.Average, template.Max, template.Requests, template.Errors)
+		}
+	}
+	if len(r.Hosts) > 0 {
+		fmt.Fprintf(writer, "\nHOST\tREQUESTS\tERRORS\tFAILURE RATE\n")
+		for _, host := range r.Hosts {
+			fmt.Fprintf(writer, "%s\t%d\t%d\t%.1f%%\n", host.Host, host.Requests, host.Errors, host.FailureRate*100)
+		}
+	}
+	_ = writer.Flush()
+	return builder.String()
+}
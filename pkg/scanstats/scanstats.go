@@ -0,0 +1,191 @@
+package scanstats
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// Collector collects the statistics of a scan
+type Collector struct {
+	mutex     sync.Mutex
+	started   time.Time
+	protocols map[string]*protocolStats
+	errors    map[string]int
+	hosts     map[string]*hostStats
+	templates map[string]*templateStats
+}
+
+type protocolStats struct {
+	requests int
+	errors   int
+	latency  *histogram
+}
+
+type hostStats struct {
+	requests int
+	errors   int
+}
+
+type templateStats struct {
+	executions int
+	total      time.Duration
+	max        time.Duration
+	requests   int
+	errors     int
+}
+
+// New creates a new statistics collector
+func New() *Collector {
+	return &Collector{
+		started:   time.Now(),
+		protocols: make(map[string]*protocolStats),
+		errors:    make(map[string]int),
+		hosts:     make(map[string]*hostStats),
+		templates: make(map[string]*templateStats),
+	}
+}
+
+func (c *Collector) protocol(name string) *protocolStats {
+	stats, ok := c.protocols[name]
+	if !ok {
+		stats = &protocolStats{latency: newHistogram()}
+		c.protocols[name] = stats
+	}
+	return stats
+}
+
+// Request records a request sent to a host by a protocol
+func (c *Collector) Request(protocol, target string, err error) {
+	host := hostFromTarget(target)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.protocol(protocol)
+	stats.requests++
+	hstats, ok := c.hosts[host]
+	if !ok {
+		hstats = &hostStats{}
+		c.hosts[host] = hstats
+	}
+	hstats.requests++
+	if err != nil {
+		stats.errors++
+		hstats.errors++
+		c.errors[ErrorKind(err)]++
+	}
+}
+
+// Latency records the response time of a request sent by a protocol
+func (c *Collector) Latency(protocol string, duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.protocol(protocol).latency.observe(duration)
+}
+
+// TemplateExecuted records the time taken to execute a template on an input
+func (c *Collector) TemplateExecuted(templateID string, duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.template(templateID)
+	stats.executions++
+	stats.total += duration
+	if duration > stats.max {
+		stats.max = duration
+	}
+}
+
+// TemplateRequest records a request sent by a template
+func (c *Collector) TemplateRequest(templateID string, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.template(templateID)
+	stats.requests++
+	if err != nil {
+		stats.errors++
+	}
+}
+
+func (c *Collector) template(id string) *templateStats {
+	stats, ok := c.templates[id]
+	if !ok {
+		stats = &templateStats{}
+		c.templates[id] = stats
+	}
+	return stats
+}
+
+// hostFromTarget returns the host of an url or address used as target
+func hostFromTarget(target string) string {
+	if strings.Contains(target, "://") {
+		if parsed, err := url.Parse(target); err == nil && parsed.Host != "" {
+			return parsed.Host
+		}
+	}
+	if host, _, found := strings.Cut(target, "/"); found {
+		return host
+	}
+	return target
+}
+
+// errorKinds maps substrings of errors to the kind they are reported as
+var errorKinds = []struct {
+	kind     string
+	patterns []string
+}{
+	{"timeout", []string{"timeout", "deadline exceeded"}},
+	{"connection-refused", []string{"connection refused"}},
+	{"connection-reset", []string{"connection reset", "broken pipe"}},
+	{"dns", []string{"no such host", "no address found", "could not resolve"}},
+	{"tls", []string{"tls", "x509", "certificate"}},
+	{"eof", []string{"eof"}},
+	{"unresolved-variables", []string{"unresolved variables"}},
+}
+
+// ErrorKind returns the kind of a request error used in the error breakdown
+func ErrorKind(err error) string {
+	message := strings.ToLower(err.Error())
+	for _, errorKind := range errorKinds {
+		for _, pattern := range errorKind.patterns {
+			if strings.Contains(message, pattern) {
+				return errorKind.kind
+			}
+		}
+	}
+	return "other"
+}
+
+// Writer is an output writer recording the requests logged to it
+// in a collector before passing them to the underlying writer
+type Writer struct {
+	output.Writer
+
+	collector *Collector
+}
+
+// NewWriter returns an output writer recording requests in collector
+func NewWriter(writer output.Writer, collector *Collector) *Writer {
+	return &Writer{Writer: writer, collector: collector}
+}
+
+// Request records the request and logs it to the underlying writer
+func (w *Writer) Request(templateID, url, requestType string, err error) {
+	w.collector.Request(requestType, url, err)
+	w.Writer.Request(templateID, url, requestType, err)
+}
+
+// LogRequest records the request and logs it to the underlying writer
+func (w *Writer) LogRequest(event *output.RequestEvent) {
+	w.collector.Request(event.Type, event.Input, event.Error)
+	if event.TemplateID != "" {
+		w.collector.TemplateRequest(event.TemplateID, event.Error)
+	}
+	w.Writer.LogRequest(event)
+}
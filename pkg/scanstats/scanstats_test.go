@@ -0,0 +1,99 @@
+package scanstats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+func TestCollectorReport(t *testing.T) {
+	collector := New()
+	collector.Request("http", "https://example.com/path", nil)
+	collector.Request("http", "https://example.com/other", errors.New("context deadline exceeded (Client.Timeout exceeded while awaiting headers)"))
+	collector.Request("http", "http://down.example.com", errors.New("dial tcp: connection refused"))
+	collector.Request("dns", "example.com", nil)
+	for _, duration := range []time.Duration{20 * time.Millisecond, 80 * time.Millisecond, 300 * time.Millisecond, 12 * time.Second} {
+		collector.Latency("http", duration)
+	}
+	collector.TemplateExecuted("fast", time.Second)
+	collector.TemplateExecuted("slow", 3*time.Second)
+	collector.TemplateExecuted("slow", time.Second)
+
+	report := collector.Report()
+	require.Equal(t, 4, report.Requests)
+	require.Equal(t, 2, report.Errors)
+	require.Equal(t, map[string]int{"timeout": 1, "connection-refused": 1}, report.ErrorBreakdown)
+
+	require.Len(t, report.Protocols, 2)
+	require.Equal(t, "dns", report.Protocols[0].Protocol)
+	require.Nil(t, report.Protocols[0].Latency)
+	latency := report.Protocols[1].Latency
+	require.Equal(t, 4, latency.Count)
+	require.Equal(t, Duration(20*time.Millisecond), latency.Min)
+	require.Equal(t, Duration(12*time.Second), latency.Max)
+	require.Equal(t, Duration(100*time.Millisecond), latency.P50)
+	require.Equal(t, Duration(12*time.Second), latency.P99)
+	require.Equal(t, 1, latency.Buckets[len(latency.Buckets)-1].Count)
+
+	require.Equal(t, "slow", report.SlowestTemplates[0].ID)
+	require.Equal(t, Duration(2*time.Second), report.SlowestTemplates[0].Average)
+
+	require.Len(t, report.Hosts, 2)
+	require.Equal(t, &HostReport{Host: "down.example.com", Requests: 1, Errors: 1, FailureRate: 1}, report.Hosts[0])
+	require.Equal(t, &HostReport{Host: "example.com", Requests: 3, Errors: 1, FailureRate: 1.0 / 3}, report.Hosts[1])
+
+	text := report.String()
+	require.Contains(t, text, "connection-refused")
+	require.Contains(t, text, "down.example.com")
+
+	file := filepath.Join(t.TempDir(), "report.json")
+	require.Nil(t, report.WriteJSON(file), "could not write report")
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read report")
+	var decoded map[string]interface{}
+	require.Nil(t, json.Unmarshal(data, &decoded), "could not decode report")
+	require.Equal(t, "2s", decoded["slowest_templates"].([]interface{})[0].(map[string]interface{})["average"])
+}
+
+// requestWriter is an output writer discarding logged requests
+type requestWriter struct {
+	output.Writer
+}
+
+func (requestWriter) LogRequest(*output.RequestEvent) {}
+
+func TestTemplateProfile(t *testing.T) {
+	collector := New()
+	writer := output.WithTemplateID(NewWriter(requestWriter{}, collector), "slow")
+	writer.LogRequest(&output.RequestEvent{Input: "https://example.com", Type: "http"})
+	writer.LogRequest(&output.RequestEvent{Input: "https://example.com", Type: "http", Error: errors.New("timeout")})
+	writer.LogRequest(&output.RequestEvent{TemplateID: "subtemplate", Input: "https://example.com", Type: "http"})
+	for i := 0; i < reportLimit+2; i++ {
+		collector.TemplateExecuted(fmt.Sprintf("template-%02d", i), time.Duration(i)*time.Millisecond)
+	}
+	collector.TemplateExecuted("slow", 5*time.Second)
+
+	profile := collector.TemplateProfile()
+	require.Len(t, profile, reportLimit+3, "could not list all executed templates")
+	require.Equal(t, &TemplateReport{ID: "slow", Executions: 1, Total: Duration(5 * time.Second), Average: Duration(5 * time.Second), Max: Duration(5 * time.Second), Requests: 2, Errors: 1}, profile[0])
+	require.Equal(t, "template-00", profile[len(profile)-1].ID)
+	require.Len(t, collector.Report().SlowestTemplates, reportLimit)
+
+	text := profile.String()
+	require.Contains(t, text, "Template profile (13 templates")
+	require.NotContains(t, text, "subtemplate", "could not skip templates which were not executed")
+}
+
+func TestErrorKind(t *testing.T) {
+	require.Equal(t, "tls", ErrorKind(errors.New("remote error: tls: handshake failure")))
+	require.Equal(t, "dns", ErrorKind(errors.New("dial tcp: lookup x: no such host")))
+	require.Equal(t, "other", ErrorKind(errors.New("unexpected status")))
+}
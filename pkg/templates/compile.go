@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/compiler"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/offlinehttp"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/cache"
@@ -115,6 +117,18 @@ func (template *Template) parseSelfContainedRequests() {
 	}
 }
 
+// parseProxyRequests sets the proxies of the template to the http requests without their own proxies.
+func (template *Template) parseProxyRequests() {
+	if len(template.Proxy) == 0 {
+		return
+	}
+	for _, request := range template.RequestsHTTP {
+		if len(request.Proxy) == 0 {
+			request.Proxy = template.Proxy
+		}
+	}
+}
+
 // Requests returns the total request count for the template
 func (template *Template) Requests() int {
 	return len(template.RequestsDNS) +
@@ -326,10 +340,17 @@ func parseTemplate(data []byte, options protocols.ExecutorOptions) (*Template, e
 	if template.Info.Authors.IsEmpty() {
 		return nil, errors.New("no template author field provided")
 	}
+	if violations := fipsViolations(template); len(violations) > 0 {
+		return nil, fmt.Errorf("template uses algorithms not approved in fips mode: %s", strings.Join(violations, ", "))
+	}
+	template.ApplyOverride()
 
 	// Setting up variables regarding template metadata
 	options.TemplateID = template.ID
 	options.TemplateInfo = template.Info
+	if options.Output != nil {
+		options.Output = output.WithTemplateID(options.Output, template.ID)
+	}
 	options.StopAtFirstMatch = template.StopAtFirstMatch
 
 	if template.Variables.Len() > 0 {
@@ -363,6 +384,7 @@ func parseTemplate(data []byte, options protocols.ExecutorOptions) (*Template, e
 		return nil, errorutil.NewWithErr(err).Msgf("failed to load file refs for %s", template.ID)
 	}
 
+	template.parseProxyRequests()
 	if err := template.compileProtocolRequests(options); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,103 @@
+package templates
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/fips"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/marker"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/variables"
+)
+
+var variablesType = reflect.TypeOf(variables.Variable{})
+
+// fipsViolations returns the calls to helper functions using algorithms not approved
+// in fips mode of the expressions of a parsed template
+func fipsViolations(template *Template) []string {
+	if !fips.IsEnabled() {
+		return nil
+	}
+	unique := make(map[string]struct{})
+	templateExpressions(template, func(expression string) {
+		for _, violation := range fips.ExpressionViolations(expression) {
+			unique[violation] = struct{}{}
+		}
+	})
+	violations := make([]string, 0, len(unique))
+	for violation := range unique {
+		violations = append(violations, violation)
+	}
+	sort.Strings(violations)
+	return violations
+}
+
+// templateExpressions calls fn with the expressions of a parsed template. The dsl
+// fields of matchers and extractors are expressions, the other fields contain
+// {{expression}} placeholders.
+func templateExpressions(template *Template, fn func(expression string)) {
+	check := func(value string, isDSL bool) {
+		if isDSL {
+			fn(value)
+			return
+		}
+		for _, expression := range expressions.FindExpressions(value, marker.ParenthesisOpen, marker.ParenthesisClose, nil) {
+			fn(expression)
+		}
+	}
+	visited := make(map[uintptr]struct{})
+	value := reflect.ValueOf(template).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		// the info of the template is not evaluated
+		if field := value.Type().Field(i); field.IsExported() && field.Name != "Info" {
+			walkStrings(value.Field(i), false, visited, check)
+		}
+	}
+}
+
+// walkStrings calls fn with the strings of the exported fields of value, isDSL is
+// true for the strings of the DSL fields
+func walkStrings(value reflect.Value, isDSL bool, visited map[uintptr]struct{}, fn func(value string, isDSL bool)) {
+	if !value.IsValid() {
+		return
+	}
+	if value.Type() == variablesType {
+		vars := value.Interface().(variables.Variable)
+		vars.ForEach(func(_ string, data interface{}) {
+			walkStrings(reflect.ValueOf(data), false, visited, fn)
+		})
+		return
+	}
+	switch value.Kind() {
+	case reflect.String:
+		fn(value.String(), isDSL)
+	case reflect.Pointer:
+		if value.IsNil() {
+			return
+		}
+		if _, ok := visited[value.Pointer()]; ok {
+			return
+		}
+		visited[value.Pointer()] = struct{}{}
+		walkStrings(value.Elem(), isDSL, visited, fn)
+	case reflect.Interface:
+		if !value.IsNil() {
+			walkStrings(value.Elem(), isDSL, visited, fn)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			walkStrings(value.Index(i), isDSL, visited, fn)
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			walkStrings(iter.Value(), isDSL, visited, fn)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Type().Field(i); field.IsExported() {
+				walkStrings(value.Field(i), field.Name == "DSL", visited, fn)
+			}
+		}
+	}
+}
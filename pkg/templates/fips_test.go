@@ -0,0 +1,38 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestTemplateExpressions(t *testing.T) {
+	data := `id: fips-test
+info:
+  name: fips test
+  author: pdteam
+  description: "Description mentioning {{md5(info)}} is not evaluated"
+variables:
+  signature: '{{hmac("md5", "a", "b")}}'
+http:
+  - raw:
+      - |
+        GET /?h={{sha256(id)}} HTTP/1.1
+    matchers:
+      - type: word
+        words:
+          - "md5(body)"
+      - type: dsl
+        dsl:
+          - "sha1(body) == hash"
+`
+	template := &Template{}
+	require.Nil(t, yaml.Unmarshal([]byte(data), template), "could not unmarshal template")
+
+	var got []string
+	templateExpressions(template, func(expression string) {
+		got = append(got, expression)
+	})
+	require.ElementsMatch(t, []string{`hmac("md5", "a", "b")`, "sha256(id)", "sha1(body) == hash"}, got, "could not get template expressions")
+}
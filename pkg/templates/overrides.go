@@ -0,0 +1,90 @@
+package templates
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	fileutil "github.com/projectdiscovery/utils/file"
+	"gopkg.in/yaml.v2"
+)
+
+// Override re-assigns the classification of a template without editing it
+type Override struct {
+	// Severity replaces the severity of the template
+	Severity severity.Holder `yaml:"severity,omitempty"`
+	// Tags replaces the tags of the template
+	Tags stringslice.StringSlice `yaml:"tags,omitempty"`
+	// Suppress excludes the template from being loaded
+	Suppress bool `yaml:"suppress,omitempty"`
+}
+
+var (
+	overridesMutex sync.RWMutex
+	// overrides maps template ids to their override
+	overrides map[string]*Override
+)
+
+// LoadOverrides loads the overrides file mapping template ids to overrides,
+// the overrides file of the config directory is used if it exists and file is empty
+func LoadOverrides(file string) error {
+	if file == "" {
+		file = config.DefaultConfig.GetTemplateOverridesFilePath()
+		if !fileutil.FileExists(file) {
+			SetOverrides(nil)
+			return nil
+		}
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not read template overrides file %s", file)
+	}
+	values := make(map[string]*Override)
+	if err := yaml.UnmarshalStrict(data, &values); err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not parse template overrides file %s", file)
+	}
+	for id, override := range values {
+		if strings.TrimSpace(id) == "" || override == nil {
+			return errorutil.New("invalid template override %q in %s", id, file)
+		}
+	}
+	SetOverrides(values)
+	return nil
+}
+
+// SetOverrides sets the overrides applied to templates, nil removes them
+func SetOverrides(values map[string]*Override) {
+	overridesMutex.Lock()
+	defer overridesMutex.Unlock()
+
+	overrides = values
+}
+
+// GetOverride returns the override of a template id if any
+func GetOverride(id string) (*Override, bool) {
+	overridesMutex.RLock()
+	defer overridesMutex.RUnlock()
+
+	override, ok := overrides[id]
+	return override, ok
+}
+
+// ApplyOverride applies the override of the template to its info and
+// returns true if the template is suppressed
+func (template *Template) ApplyOverride() bool {
+	override, ok := GetOverride(template.ID)
+	if !ok {
+		return false
+	}
+	if override.Severity.Severity != severity.Undefined {
+		template.Info.SeverityHolder = override.Severity
+	}
+	if !override.Tags.IsEmpty() {
+		template.Info.Tags = override.Tags
+	}
+	return override.Suppress
+}
@@ -0,0 +1,45 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOverrides(t *testing.T) {
+	defer SetOverrides(nil)
+
+	file := filepath.Join(t.TempDir(), "overrides.yaml")
+	data := `
+tech-detect:
+  severity: high
+  tags: tech,internal
+noisy-template:
+  suppress: true
+`
+	require.Nil(t, os.WriteFile(file, []byte(data), 0600))
+	require.Nil(t, LoadOverrides(file))
+
+	template := &Template{ID: "tech-detect", Info: model.Info{
+		SeverityHolder: severity.Holder{Severity: severity.Info},
+		Tags:           stringslice.StringSlice{Value: "tech"},
+	}}
+	require.False(t, template.ApplyOverride())
+	require.Equal(t, severity.High, template.Info.SeverityHolder.Severity)
+	require.ElementsMatch(t, []string{"tech", "internal"}, template.Info.Tags.ToSlice())
+
+	suppressed := &Template{ID: "noisy-template", Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}}}
+	require.True(t, suppressed.ApplyOverride())
+	require.Equal(t, severity.Low, suppressed.Info.SeverityHolder.Severity)
+
+	other := &Template{ID: "other"}
+	require.False(t, other.ApplyOverride())
+
+	require.Nil(t, os.WriteFile(file, []byte("tech-detect:\n  severity: urgent\n"), 0600))
+	require.NotNil(t, LoadOverrides(file), "could load invalid severity")
+}
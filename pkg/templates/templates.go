@@ -136,6 +136,17 @@ type Template struct {
 	//   - "AWS"
 	Signature http.SignatureTypeHolder `yaml:"signature,omitempty" json:"signature,omitempty" jsonschema:"title=signature is the http request signature method,description=Signature is the HTTP Request signature Method,enum=AWS"`
 
+	// description: |
+	//   Proxy contains the http(s) and socks5 proxies the http requests of the template are sent through.
+	//
+	//   The proxies are chained in order after the proxies of the scan, the http
+	//   requests with their own proxies don't use them. The -allow-template-proxy
+	//   option is required.
+	// examples:
+	//   - value: >
+	//       []string{"socks5://127.0.0.1:9050"}
+	Proxy []string `yaml:"proxy,omitempty" json:"proxy,omitempty" jsonschema:"title=proxy chain of the template,description=Chain of http(s) and socks5 proxies the http requests of the template are sent through"`
+
 	// description: |
 	//   Variables contains any variables for the current request.
 	Variables variables.Variable `yaml:"variables,omitempty" json:"variables,omitempty" jsonschema:"title=variables for the http request,description=Variables contains any variables for the current request"`
@@ -340,6 +351,7 @@ func (template *Template) UnmarshalYAML(unmarshal func(interface{}) error) error
 
 // ImportFileRefs checks if sensitive fields like `flow` , `source` in code protocol are referencing files
 // instead of actual javascript / engine code if so it loads the file contents and replaces the reference
+// the relative modules required by javascript protocol requests are loaded as well
 func (template *Template) ImportFileRefs(options *protocols.ExecutorOptions) error {
 	var errs []error
 
@@ -371,6 +383,23 @@ func (template *Template) ImportFileRefs(options *protocols.ExecutorOptions) err
 		}
 	}
 
+	// relative modules required by javascript protocol requests
+	for _, request := range template.RequestsJavascript {
+		imported, err := request.ImportModules(options.TemplatePath, func(path string) (string, error) {
+			data, err := options.Options.LoadHelperFile(path, options.TemplatePath, options.Catalog)
+			if err != nil {
+				return "", err
+			}
+			defer data.Close()
+			bin, err := io.ReadAll(data)
+			return string(bin), err
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+		template.ImportedFiles = append(template.ImportedFiles, imported...)
+	}
+
 	// flow code references
 	if template.Flow != "" {
 		if len(template.Flow) > 0 && filepath.Ext(template.Flow) == ".js" && fileutil.FileExists(template.Flow) {
@@ -34,3 +34,22 @@ func TestTemplateStruct(t *testing.T) {
 	err = yaml.Unmarshal(yamlBin, &yamlTemplate)
 	require.Nil(t, err, "failed to unmarshal yaml template")
 }
+
+func TestTemplateProxy(t *testing.T) {
+	var template Template
+	err := yaml.Unmarshal([]byte(`id: proxy
+proxy:
+  - socks5://127.0.0.1:9050
+http:
+  - path: ["{{BaseURL}}"]
+  - path: ["{{BaseURL}}/oob"]
+    proxy:
+      - http://127.0.0.1:8080
+      - socks5://127.0.0.1:1080
+`), &template)
+	require.Nil(t, err, "failed to unmarshal template")
+
+	template.parseProxyRequests()
+	require.Equal(t, []string{"socks5://127.0.0.1:9050"}, template.RequestsHTTP[0].Proxy, "could not use the proxy of the template")
+	require.Equal(t, []string{"http://127.0.0.1:8080", "socks5://127.0.0.1:1080"}, template.RequestsHTTP[1].Proxy, "could not keep the proxies of the request")
+}
@@ -139,6 +139,11 @@ func (m *MockOutputWriter) Request(templateID, url, requestType string, err erro
 	}
 }
 
+// LogRequest writes a log the requests trace log
+func (m *MockOutputWriter) LogRequest(event *output.RequestEvent) {
+	m.Request(event.Template, event.Input, event.Type, event.Error)
+}
+
 // WriteFailure writes the event to file and/or screen.
 func (m *MockOutputWriter) WriteFailure(wrappedEvent *output.InternalWrappedEvent) error {
 	// if failure event has more than one result, write them all
@@ -207,3 +212,7 @@ func (m *MockProgressClient) IncrementErrorsBy(count int64) {}
 // IncrementFailedRequestsBy increments the number of requests counter by count
 // along with errors.
 func (m *MockProgressClient) IncrementFailedRequestsBy(count int64) {}
+
+// IncrementConnections increments the connections counter by 1
+// along with the reused connections counter if reused.
+func (m *MockProgressClient) IncrementConnections(reused bool) {}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
@@ -95,12 +96,23 @@ func (e *TemplateExecuter) Requests() int {
 // Execute executes the protocol group and returns true or false if results were found.
 func (e *TemplateExecuter) Execute(input *contextargs.Context) (bool, error) {
 	results := &atomic.Bool{}
+	if e.options.ScanStats != nil {
+		timeStart := time.Now()
+		defer func() {
+			e.options.ScanStats.TemplateExecuted(e.options.TemplateID, time.Since(timeStart))
+		}()
+	}
 	defer func() {
 		// it is essential to remove template context of `Scan i.e template x input pair`
 		// since it is of no use after scan is completed (regardless of success or failure)
 		e.options.RemoveTemplateCtx(input.MetaInput)
 	}()
 
+	var evidence *output.EvidenceChain
+	if e.options.Options.EvidenceDirectory != "" {
+		evidence = &output.EvidenceChain{}
+	}
+
 	var lastMatcherEvent *output.InternalWrappedEvent
 	writeFailureCallback := func(event *output.InternalWrappedEvent, matcherStatus bool) {
 		if !results.Load() && matcherStatus {
@@ -116,6 +128,9 @@ func (e *TemplateExecuter) Execute(input *contextargs.Context) (bool, error) {
 			// something went wrong
 			return
 		}
+		if evidence != nil {
+			evidence.Add(event)
+		}
 		// If no results were found, and also interactsh is not being used
 		// in that case we can skip it, otherwise we've to show failure in
 		// case of matcher-status flag.
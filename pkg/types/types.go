@@ -11,6 +11,8 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/httpauth"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/tlsconfig"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	fileutil "github.com/projectdiscovery/utils/file"
@@ -71,13 +73,25 @@ type Options struct {
 	// ProjectPath allows nuclei to use a user defined project folder
 	ProjectPath string
 	// InteractshURL is the URL for the interactsh server.
+	// multiple comma separated servers are tried in order
 	InteractshURL string
 	// Interactsh Authorization header value for self-hosted servers
+	// multiple comma separated values are used for the server at the same position
 	InteractshToken string
+	// InteractshCorrelationIDLength is the correlation id length of the interactsh server
+	InteractshCorrelationIDLength int
+	// InteractshCorrelationIDNonceLength is the correlation id nonce length of the interactsh server
+	InteractshCorrelationIDNonceLength int
 	// Target URLs/Domains to scan using a template
 	Targets goflags.StringSlice
 	// TargetsFilePath specifies the targets from a file to scan using templates.
 	TargetsFilePath string
+	// PortScanFiles is the list of nmap xml or masscan output files to import targets from
+	PortScanFiles goflags.StringSlice
+	// ExcludeHosts is the list of hosts/ips to exclude from the scan
+	ExcludeHosts goflags.StringSlice
+	// ExcludeCIDRs is the list of cidr ranges to exclude from the scan
+	ExcludeCIDRs goflags.StringSlice
 	// Resume the scan from the state stored in the resume config file
 	Resume string
 	// Output is the file to write found results to.
@@ -88,12 +102,26 @@ type Options struct {
 	ListDslSignatures bool
 	// List of HTTP(s)/SOCKS5 proxy to use (comma separated or file input)
 	Proxy goflags.StringSlice
+	// ProxyRotation is the strategy used to rotate http requests across proxies (round-robin, sticky)
+	ProxyRotation string
 	// TemplatesDirectory is the directory to use for storing templates
 	NewTemplatesDirectory string
 	// TraceLogFile specifies a file to write with the trace of all requests
 	TraceLogFile string
 	// ErrorLogFile specifies a file to write with the errors of all requests
 	ErrorLogFile string
+	// AuditLogFile specifies an append-only file to write with the audit log of all outgoing requests
+	AuditLogFile string
+	// AuditLogKey is the key used to sign the audit log entries (hmac-sha256)
+	AuditLogKey string
+	// AuditLogRaw includes the raw requests and responses in the audit log
+	AuditLogRaw bool
+	// ReplayFile is an audit log with raw requests to replay diffing the responses with the recorded ones
+	ReplayFile string
+	// LogFile specifies a file to write nuclei logs to instead of stderr
+	LogFile string
+	// LogMaxSize is the maximum size in MB of the log file before it is rotated
+	LogMaxSize int
 	// ReportingDB is the db for report storage as well as deduplication
 	ReportingDB string
 	// ReportingConfig is the config file for nuclei reporting module
@@ -150,6 +178,14 @@ type Options struct {
 	ResolversFile string
 	// StatsInterval is the number of seconds to display stats after
 	StatsInterval int
+	// ScanReport displays the end of scan statistics report
+	ScanReport bool
+	// ScanReportJSON is the file to write the end of scan statistics report to in json format
+	ScanReportJSON string
+	// ProfileTemplates displays the templates ranked by execution time with their request and error counts at the end of the scan
+	ProfileTemplates bool
+	// ProfileTemplatesJSON is the file to write the templates profile to in json format
+	ProfileTemplatesJSON string
 	// MetricsPort is the port to show metrics on
 	MetricsPort int
 	// MaxHostError is the maximum number of errors allowed for a host
@@ -174,6 +210,10 @@ type Options struct {
 	RateLimit int
 	// Rate-Limit is the maximum number of requests per minute for specified target
 	RateLimitMinute int
+	// RateLimitAdaptive adapts the rate of every host to its latency and errors
+	RateLimitAdaptive bool
+	// RateLimitAdaptiveMin is the minimum number of requests per second of the throttled hosts
+	RateLimitAdaptiveMin int
 	// PageTimeout is the maximum time to wait for a page in seconds
 	PageTimeout int
 	// InteractionsCacheSize is the number of interaction-url->req to keep in cache at a time.
@@ -212,6 +252,8 @@ type Options struct {
 	DisableClustering bool
 	// UseInstalledChrome skips chrome install and use local instance
 	UseInstalledChrome bool
+	// HeadlessRemoteURL is the devtools endpoint of an already running browser to use for headless templates
+	HeadlessRemoteURL string
 	// SystemResolvers enables override of nuclei's DNS client opting to use system resolver stack.
 	SystemResolvers bool
 	// ShowActions displays a list of all headless actions
@@ -226,6 +268,18 @@ type Options struct {
 	DebugResponse bool
 	// DisableHTTPProbe disables http probing feature of input normalization
 	DisableHTTPProbe bool
+	// LivenessCheck contains the methods (tcp,http,icmp) used to skip dead hosts before scanning
+	LivenessCheck goflags.StringSlice
+	// LivenessPorts are the ports checked by the tcp liveness check for targets without port
+	LivenessPorts goflags.StringSlice
+	// LivenessConcurrency is the number of targets checked for liveness in parallel
+	LivenessConcurrency int
+	// JSPoolSize is the maximum number of connections pooled by the javascript protocol libraries
+	JSPoolSize int
+	// JSTimeout is the default maximum execution time of the javascript protocol code
+	JSTimeout time.Duration
+	// JSMaxMemory is the default maximum memory the javascript protocol code can allocate, ex: 256mb
+	JSMaxMemory string
 	// LeaveDefaultPorts skips normalization of default ports
 	LeaveDefaultPorts bool
 	// AutomaticScan enables automatic tech based template execution
@@ -264,6 +318,8 @@ type Options struct {
 	TemplateDisplay bool
 	// TemplateList lists available templates
 	TemplateList bool
+	// TemplateOverrides is the file mapping template ids to severity, tags or suppression overrides
+	TemplateOverrides string
 	// HangMonitor enables nuclei hang monitoring
 	HangMonitor bool
 	// Stdin specifies whether stdin input was given to the process
@@ -298,16 +354,44 @@ type Options struct {
 	ZTLS bool
 	// AllowLocalFileAccess allows local file access from templates payloads
 	AllowLocalFileAccess bool
+	// AllowRemotePayloads allows templates to fetch payload files from https urls
+	AllowRemotePayloads bool
+	// AllowTemplateProxy allows the templates to send their http requests through their own proxies
+	AllowTemplateProxy bool
 	// RestrictLocalNetworkAccess restricts local network access from templates requests
 	RestrictLocalNetworkAccess bool
+	// DisallowPrivateIPs rejects connections to private and reserved addresses at the dialer level
+	DisallowPrivateIPs bool
 	// ShowMatchLine enables display of match line number
 	ShowMatchLine bool
 	// EnablePprof enables exposing pprof runtime information with a webserver.
 	EnablePprof bool
+	// ServerMode runs nuclei as a REST API server to manage scans
+	ServerMode bool
+	// ServerAddress is the listen address of the REST API server
+	ServerAddress string
+	// ServerToken is the token required to authenticate to the REST API server
+	ServerToken string
+	// ServerTenantsFile is the file containing the tenants of the REST API server
+	ServerTenantsFile string
+	// ServerRetention is the duration the finished scans of the REST API server are kept
+	ServerRetention time.Duration
+	// ServerMaxFinishedScans is the maximum number of finished scans kept by the REST API server
+	ServerMaxFinishedScans int
 	// StoreResponse stores received response to output directory
 	StoreResponse bool
 	// StoreResponseDir stores received response to custom directory
 	StoreResponseDir string
+	// OutputSchemaVersion is the json output schema version to write results in for compatibility
+	OutputSchemaVersion string
+	// CVEDatabase is the offline cve database directory used to enrich results
+	CVEDatabase string
+	// UpdateCVEDatabase downloads or updates the offline cve database at startup
+	UpdateCVEDatabase bool
+	// FailOn contains severity:count thresholds of findings at which nuclei exits with a failure code
+	FailOn goflags.StringSlice
+	// EvidenceDirectory is the directory to write request/response chains of findings to
+	EvidenceDirectory string
 	// DisableRedirects disables following redirects for http request module
 	DisableRedirects bool
 	// SNI custom hostname
@@ -322,6 +406,8 @@ type Options struct {
 	ResponseReadSize int
 	// ResponseSaveSize is the maximum size of response to save
 	ResponseSaveSize int
+	// HTTPCompression advertises the brotli and zstd compressions in the http requests
+	HTTPCompression bool
 	// Health Check
 	HealthCheck bool
 	// Time to wait between each input read operation before closing the stream
@@ -342,6 +428,12 @@ type Options struct {
 	UncoverLimit int
 	// Uncover search delay
 	UncoverRateLimit int
+	// UncoverEngineRateLimit overrides the ratelimit of specific engines (engine=count)
+	UncoverEngineRateLimit goflags.StringSlice
+	// CloudAssets is the list of cloud providers to enumerate targets from
+	CloudAssets goflags.StringSlice
+	// CloudAssetsRegions is the list of aws regions to enumerate
+	CloudAssetsRegions goflags.StringSlice
 	// ScanAllIPs associated to a dns record
 	ScanAllIPs bool
 	// IPVersion to scan (4,6)
@@ -390,8 +482,44 @@ type Options struct {
 	FuzzingType string
 	// Fuzzing Mode overrides template level fuzzing-mode configuration
 	FuzzingMode string
+	// RespectRobots skips the fuzzing requests to paths disallowed by robots.txt of the host
+	RespectRobots bool
 	// TlsImpersonate enables TLS impersonation
 	TlsImpersonate bool
+	// TLSMinVersion is the minimum tls version used by clients (tls10, tls11, tls12, tls13)
+	TLSMinVersion string
+	// TLSMaxVersion is the maximum tls version used by clients (tls10, tls11, tls12, tls13)
+	TLSMaxVersion string
+	// TLSCiphers is the list of tls cipher suites offered by clients
+	TLSCiphers goflags.StringSlice
+	// TLSCurves is the list of elliptic curves offered by clients
+	TLSCurves goflags.StringSlice
+	// TLSALPN is the list of alpn protocols offered by clients
+	TLSALPN goflags.StringSlice
+	// TLSRenegotiation is the renegotiation support of clients (never, once, freely)
+	TLSRenegotiation string
+	// HTTPAuthType is the authentication of the http requests (ntlm, negotiate, kerberos, digest)
+	HTTPAuthType string
+	// HTTPAuthUsername is the username of the http authentication, optionally as DOMAIN\username
+	HTTPAuthUsername string
+	// HTTPAuthPassword is the password of the http authentication
+	HTTPAuthPassword string
+	// HTTPAuthKDC is the address of the kerberos kdc of the http authentication
+	HTTPAuthKDC string
+	// HTTPAuthKeytab is the path of the kerberos keytab of the http authentication
+	HTTPAuthKeytab string
+	// HTTPAuthCCache is the path of the kerberos credential cache of the http authentication
+	HTTPAuthCCache string
+	// SessionFile is the session file with the login requests and the tokens of the authenticated scans
+	SessionFile string
+	// HTTPConnectionReuse is the scope of the keep-alive http connections reuse (template, global)
+	HTTPConnectionReuse string
+	// HTTPMaxHostConnections is the maximum number of reused http connections per host
+	HTTPMaxHostConnections int
+	// FIPS restricts crypto usage to FIPS approved algorithms
+	FIPS bool
+	// Plugins contains the plugin executables (or directories of them) providing matchers and extractors
+	Plugins goflags.StringSlice
 	// CodeTemplateSignaturePublicKey is the custom public key used to verify the template signature (algorithm is automatically inferred from the length)
 	CodeTemplateSignaturePublicKey string
 	// CodeTemplateSignatureAlgorithm specifies the sign algorithm (rsa, ecdsa)
@@ -420,6 +548,33 @@ func (options *Options) HasClientCertificates() bool {
 	return options.ClientCertFile != "" || options.ClientCAFile != "" || options.ClientKeyFile != ""
 }
 
+// TLSConfig returns the global custom tls client configuration
+func (options *Options) TLSConfig() *tlsconfig.Config {
+	return &tlsconfig.Config{
+		MinVersion:    options.TLSMinVersion,
+		MaxVersion:    options.TLSMaxVersion,
+		CipherSuites:  options.TLSCiphers,
+		Curves:        options.TLSCurves,
+		ALPN:          options.TLSALPN,
+		Renegotiation: options.TLSRenegotiation,
+	}
+}
+
+// HTTPAuthConfig returns the http authentication of the options
+func (options *Options) HTTPAuthConfig() *httpauth.Config {
+	if options.HTTPAuthType == "" {
+		return nil
+	}
+	return &httpauth.Config{
+		Type:     options.HTTPAuthType,
+		Username: options.HTTPAuthUsername,
+		Password: options.HTTPAuthPassword,
+		KDC:      options.HTTPAuthKDC,
+		Keytab:   options.HTTPAuthKeytab,
+		CCache:   options.HTTPAuthCCache,
+	}
+}
+
 // DefaultOptions returns default options for nuclei
 func DefaultOptions() *Options {
 	return &Options{
@@ -0,0 +1,817 @@
+// Package sevenzip implements a reader for 7z archives supporting the
+// copy, lzma, lzma2, deflate and bzip2 methods of unencrypted archives.
+package sevenzip
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+// signature is the magic of 7z archives
+var signature = []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}
+
+// signatureHeaderSize is the size of the header at the start of 7z archives
+const signatureHeaderSize = 32
+
+// property ids of the 7z header
+const (
+	idEnd                   = 0x00
+	idHeader                = 0x01
+	idArchiveProperties     = 0x02
+	idAdditionalStreamsInfo = 0x03
+	idMainStreamsInfo       = 0x04
+	idFilesInfo             = 0x05
+	idPackInfo              = 0x06
+	idUnpackInfo            = 0x07
+	idSubStreamsInfo        = 0x08
+	idSize                  = 0x09
+	idCRC                   = 0x0A
+	idFolder                = 0x0B
+	idCodersUnpackSize      = 0x0C
+	idNumUnpackStream       = 0x0D
+	idEmptyStream           = 0x0E
+	idEmptyFile             = 0x0F
+	idName                  = 0x11
+	idEncodedHeader         = 0x17
+)
+
+// maxEncodedHeaders is the maximum number of nested encoded headers of an archive
+const maxEncodedHeaders = 4
+
+// ErrUnsupportedMethod is returned for archives compressed with an unsupported method
+var ErrUnsupportedMethod = errors.New("unsupported 7z compression method")
+
+// File is a file stored in a 7z archive
+type File struct {
+	// Name is the path of the file in the archive
+	Name string
+	// Size is the uncompressed size of the file
+	Size int64
+	// IsDir is true for directories
+	IsDir bool
+
+	hasStream bool
+}
+
+// Reader reads the files of a 7z archive
+type Reader struct {
+	// Files are the files of the archive in their stored order
+	Files []*File
+
+	r       io.ReaderAt
+	streams *streamsInfo
+}
+
+type coder struct {
+	id         []byte
+	properties []byte
+}
+
+type folder struct {
+	coders      []coder
+	unpackSizes []uint64
+	// hasCRC is true if the digest of the folder is stored in the unpack info
+	hasCRC bool
+}
+
+// unpackSize returns the uncompressed size of the folder
+func (f *folder) unpackSize() uint64 {
+	if len(f.unpackSizes) == 0 {
+		return 0
+	}
+	return f.unpackSizes[len(f.unpackSizes)-1]
+}
+
+type streamsInfo struct {
+	packPos    uint64
+	packSizes  []uint64
+	folders    []*folder
+	subStreams [][]uint64
+}
+
+// Open opens the 7z archive at path, the caller must close the returned file
+func Open(path string) (*Reader, *os.File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	reader, err := NewReader(file, stat.Size())
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return reader, file, nil
+}
+
+// NewReader reads the headers of the 7z archive of size bytes read from r
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	start := make([]byte, signatureHeaderSize)
+	if _, err := r.ReadAt(start, 0); err != nil {
+		return nil, errors.Wrap(err, "could not read 7z signature header")
+	}
+	if !bytes.Equal(start[:len(signature)], signature) {
+		return nil, errors.New("not a 7z archive")
+	}
+	nextHeaderOffset := binary.LittleEndian.Uint64(start[12:20])
+	nextHeaderSize := binary.LittleEndian.Uint64(start[20:28])
+	if nextHeaderOffset > uint64(size) || nextHeaderSize > uint64(size)-nextHeaderOffset {
+		return nil, errors.New("7z header is out of range")
+	}
+	if nextHeaderSize == 0 {
+		return &Reader{r: r}, nil
+	}
+
+	data := make([]byte, nextHeaderSize)
+	if _, err := r.ReadAt(data, signatureHeaderSize+int64(nextHeaderOffset)); err != nil {
+		return nil, errors.Wrap(err, "could not read 7z header")
+	}
+	archive := &Reader{r: r}
+	for i := 0; ; i++ {
+		h := &headerReader{data: data}
+		id, err := h.byte()
+		if err != nil {
+			return nil, err
+		}
+		if id == idHeader {
+			if err := archive.readHeader(h); err != nil {
+				return nil, errors.Wrap(err, "could not parse 7z header")
+			}
+			return archive, nil
+		}
+		if id != idEncodedHeader {
+			return nil, fmt.Errorf("unexpected 7z header id %d", id)
+		}
+		if i >= maxEncodedHeaders {
+			return nil, errors.New("7z encoded header is nested too deeply")
+		}
+		// the header is itself compressed as the first folder of streams
+		streams, err := h.streamsInfo()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse 7z encoded header")
+		}
+		if len(streams.folders) == 0 {
+			return nil, errors.New("7z encoded header has no folder")
+		}
+		decoded, err := archive.folderReader(streams, 0)
+		if err != nil {
+			return nil, err
+		}
+		unpackSize := streams.folders[0].unpackSize()
+		if unpackSize > uint64(size)*1024 {
+			return nil, errors.New("7z encoded header is too large")
+		}
+		data = make([]byte, unpackSize)
+		if _, err := io.ReadFull(decoded, data); err != nil {
+			return nil, errors.Wrap(err, "could not decode 7z header")
+		}
+	}
+}
+
+// Walk calls fn with each file of the archive and a reader of its content,
+// the reader is only valid during the call
+func (r *Reader) Walk(fn func(file *File, reader io.Reader) error) error {
+	streamFiles := make([]*File, 0, len(r.Files))
+	for _, file := range r.Files {
+		if file.hasStream {
+			streamFiles = append(streamFiles, file)
+			continue
+		}
+		if file.IsDir {
+			continue
+		}
+		if err := fn(file, bytes.NewReader(nil)); err != nil {
+			return err
+		}
+	}
+	if r.streams == nil {
+		return nil
+	}
+
+	index := 0
+	for folderIndex := range r.streams.folders {
+		sizes := r.streams.subStreams[folderIndex]
+		if len(sizes) == 0 {
+			continue
+		}
+		decoded, err := r.folderReader(r.streams, folderIndex)
+		if err != nil {
+			return err
+		}
+		for _, size := range sizes {
+			if index >= len(streamFiles) {
+				return errors.New("7z archive has more streams than files")
+			}
+			file := streamFiles[index]
+			index++
+			reader := io.LimitReader(decoded, int64(size))
+			if err := fn(file, reader); err != nil {
+				return err
+			}
+			// skip the unread content of the file to reach the next one
+			if _, err := io.Copy(io.Discard, reader); err != nil {
+				return errors.Wrapf(err, "could not decode %s", file.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// folderReader returns the decoded content of a folder of streams
+func (r *Reader) folderReader(streams *streamsInfo, folderIndex int) (io.Reader, error) {
+	f := streams.folders[folderIndex]
+	// each supported folder has a single coder reading a single pack stream
+	if folderIndex >= len(streams.packSizes) {
+		return nil, errors.New("7z folder has no pack stream")
+	}
+	offset := signatureHeaderSize + streams.packPos
+	for _, size := range streams.packSizes[:folderIndex] {
+		offset += size
+	}
+	packed := io.NewSectionReader(r.r, int64(offset), int64(streams.packSizes[folderIndex]))
+	return decoder(f.coders[0], packed, f.unpackSize())
+}
+
+// decoder returns the reader decoding a stream compressed by coder
+func decoder(c coder, packed io.Reader, unpackSize uint64) (io.Reader, error) {
+	switch string(c.id) {
+	case "\x00":
+		return packed, nil
+	case "\x03\x01\x01":
+		if len(c.properties) != 5 {
+			return nil, errors.New("invalid 7z lzma properties")
+		}
+		// 7z stores the lzma properties without the classic header uncompressed size
+		header := make([]byte, lzma.HeaderLen)
+		copy(header, c.properties)
+		binary.LittleEndian.PutUint64(header[5:], unpackSize)
+		reader, err := lzma.NewReader(io.MultiReader(bytes.NewReader(header), packed))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create lzma reader")
+		}
+		return reader, nil
+	case "\x21":
+		if len(c.properties) != 1 || c.properties[0] > 40 {
+			return nil, errors.New("invalid 7z lzma2 properties")
+		}
+		dictCap := int64(2|(c.properties[0]&1)) << (c.properties[0]/2 + 11)
+		if c.properties[0] == 40 || dictCap > lzma.MaxDictCap {
+			dictCap = lzma.MaxDictCap
+		}
+		reader, err := lzma.Reader2Config{DictCap: int(max(dictCap, lzma.MinDictCap))}.NewReader2(packed)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create lzma2 reader")
+		}
+		return reader, nil
+	case "\x04\x01\x08":
+		return flate.NewReader(packed), nil
+	case "\x04\x02\x02":
+		return bzip2.NewReader(packed), nil
+	}
+	return nil, errors.Wrapf(ErrUnsupportedMethod, "method %x", c.id)
+}
+
+// readHeader parses the main header of the archive
+func (r *Reader) readHeader(h *headerReader) error {
+	id, err := h.byte()
+	if err != nil {
+		return err
+	}
+	if id == idArchiveProperties {
+		if err := h.skipProperties(); err != nil {
+			return err
+		}
+		if id, err = h.byte(); err != nil {
+			return err
+		}
+	}
+	if id == idAdditionalStreamsInfo {
+		if _, err := h.streamsInfo(); err != nil {
+			return err
+		}
+		if id, err = h.byte(); err != nil {
+			return err
+		}
+	}
+	if id == idMainStreamsInfo {
+		if r.streams, err = h.streamsInfo(); err != nil {
+			return err
+		}
+		if id, err = h.byte(); err != nil {
+			return err
+		}
+	}
+	if id == idFilesInfo {
+		if r.Files, err = h.filesInfo(); err != nil {
+			return err
+		}
+		if id, err = h.byte(); err != nil {
+			return err
+		}
+	}
+	if id != idEnd {
+		return fmt.Errorf("unexpected 7z header id %d", id)
+	}
+
+	var streams int
+	if r.streams != nil {
+		for _, sizes := range r.streams.subStreams {
+			streams += len(sizes)
+		}
+	}
+	var streamFiles int
+	for _, file := range r.Files {
+		if file.hasStream {
+			streamFiles++
+		}
+	}
+	if streams != streamFiles {
+		return fmt.Errorf("7z archive has %d streams for %d files", streams, streamFiles)
+	}
+	if r.streams != nil {
+		index := 0
+		sizes := make([]uint64, 0, streams)
+		for _, folderSizes := range r.streams.subStreams {
+			sizes = append(sizes, folderSizes...)
+		}
+		for _, file := range r.Files {
+			if file.hasStream {
+				file.Size = int64(sizes[index])
+				index++
+			}
+		}
+	}
+	return nil
+}
+
+// headerReader decodes the values of a 7z header
+type headerReader struct {
+	data []byte
+	pos  int
+}
+
+func (h *headerReader) byte() (byte, error) {
+	if h.pos >= len(h.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := h.data[h.pos]
+	h.pos++
+	return b, nil
+}
+
+func (h *headerReader) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(h.data)-h.pos) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := h.data[h.pos : h.pos+int(n)]
+	h.pos += int(n)
+	return b, nil
+}
+
+// number decodes a 7z variable length number
+func (h *headerReader) number() (uint64, error) {
+	first, err := h.byte()
+	if err != nil {
+		return 0, err
+	}
+	var value uint64
+	mask := byte(0x80)
+	for i := 0; i < 8; i++ {
+		if first&mask == 0 {
+			high := uint64(first & (mask - 1))
+			return value | high<<(8*i), nil
+		}
+		b, err := h.byte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint64(b) << (8 * i)
+		mask >>= 1
+	}
+	return value, nil
+}
+
+// count decodes a number of items, limited by the remaining header size
+func (h *headerReader) count() (int, error) {
+	n, err := h.number()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(h.data)) {
+		return 0, errors.New("7z header item count is out of range")
+	}
+	return int(n), nil
+}
+
+func (h *headerReader) bits(n int) ([]bool, error) {
+	bits := make([]bool, n)
+	var b, mask byte
+	for i := range bits {
+		if mask == 0 {
+			var err error
+			if b, err = h.byte(); err != nil {
+				return nil, err
+			}
+			mask = 0x80
+		}
+		bits[i] = b&mask != 0
+		mask >>= 1
+	}
+	return bits, nil
+}
+
+// definedBits decodes a bit field preceded by an all defined flag
+func (h *headerReader) definedBits(n int) ([]bool, error) {
+	allDefined, err := h.byte()
+	if err != nil {
+		return nil, err
+	}
+	if allDefined == 0 {
+		return h.bits(n)
+	}
+	bits := make([]bool, n)
+	for i := range bits {
+		bits[i] = true
+	}
+	return bits, nil
+}
+
+// skipDigests skips the crc digests of n streams returning the streams having a digest
+func (h *headerReader) skipDigests(n int) ([]bool, error) {
+	defined, err := h.definedBits(n)
+	if err != nil {
+		return nil, err
+	}
+	for _, ok := range defined {
+		if ok {
+			if _, err := h.bytes(4); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return defined, nil
+}
+
+func (h *headerReader) skipProperties() error {
+	for {
+		id, err := h.byte()
+		if err != nil {
+			return err
+		}
+		if id == idEnd {
+			return nil
+		}
+		size, err := h.number()
+		if err != nil {
+			return err
+		}
+		if _, err := h.bytes(size); err != nil {
+			return err
+		}
+	}
+}
+
+func (h *headerReader) streamsInfo() (*streamsInfo, error) {
+	streams := &streamsInfo{}
+	for {
+		id, err := h.byte()
+		if err != nil {
+			return nil, err
+		}
+		switch id {
+		case idEnd:
+			if streams.subStreams == nil {
+				// folders without substreams info contain a single stream
+				for _, f := range streams.folders {
+					streams.subStreams = append(streams.subStreams, []uint64{f.unpackSize()})
+				}
+			}
+			return streams, nil
+		case idPackInfo:
+			if err := h.packInfo(streams); err != nil {
+				return nil, err
+			}
+		case idUnpackInfo:
+			if err := h.unpackInfo(streams); err != nil {
+				return nil, err
+			}
+		case idSubStreamsInfo:
+			if err := h.subStreamsInfo(streams); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected 7z streams info id %d", id)
+		}
+	}
+}
+
+func (h *headerReader) packInfo(streams *streamsInfo) error {
+	var err error
+	if streams.packPos, err = h.number(); err != nil {
+		return err
+	}
+	count, err := h.count()
+	if err != nil {
+		return err
+	}
+	for {
+		id, err := h.byte()
+		if err != nil {
+			return err
+		}
+		switch id {
+		case idEnd:
+			if len(streams.packSizes) != count {
+				return errors.New("7z pack info has no sizes")
+			}
+			return nil
+		case idSize:
+			streams.packSizes = make([]uint64, count)
+			for i := range streams.packSizes {
+				if streams.packSizes[i], err = h.number(); err != nil {
+					return err
+				}
+			}
+		case idCRC:
+			if _, err := h.skipDigests(count); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected 7z pack info id %d", id)
+		}
+	}
+}
+
+func (h *headerReader) unpackInfo(streams *streamsInfo) error {
+	id, err := h.byte()
+	if err != nil {
+		return err
+	}
+	if id != idFolder {
+		return fmt.Errorf("unexpected 7z unpack info id %d", id)
+	}
+	count, err := h.count()
+	if err != nil {
+		return err
+	}
+	if external, err := h.byte(); err != nil || external != 0 {
+		return errors.New("external 7z folders are not supported")
+	}
+	streams.folders = make([]*folder, count)
+	outStreams := make([]int, count)
+	for i := range streams.folders {
+		if streams.folders[i], outStreams[i], err = h.folder(); err != nil {
+			return err
+		}
+	}
+	if id, err = h.byte(); err != nil {
+		return err
+	}
+	if id != idCodersUnpackSize {
+		return fmt.Errorf("unexpected 7z unpack info id %d", id)
+	}
+	for i, f := range streams.folders {
+		f.unpackSizes = make([]uint64, outStreams[i])
+		for j := range f.unpackSizes {
+			if f.unpackSizes[j], err = h.number(); err != nil {
+				return err
+			}
+		}
+	}
+	for {
+		id, err := h.byte()
+		if err != nil {
+			return err
+		}
+		switch id {
+		case idEnd:
+			return nil
+		case idCRC:
+			defined, err := h.skipDigests(count)
+			if err != nil {
+				return err
+			}
+			for i, ok := range defined {
+				streams.folders[i].hasCRC = ok
+			}
+		default:
+			return fmt.Errorf("unexpected 7z unpack info id %d", id)
+		}
+	}
+}
+
+// folder decodes a folder returning its total number of out streams
+func (h *headerReader) folder() (*folder, int, error) {
+	count, err := h.count()
+	if err != nil {
+		return nil, 0, err
+	}
+	f := &folder{}
+	var inStreams, outStreams int
+	for i := 0; i < count; i++ {
+		flags, err := h.byte()
+		if err != nil {
+			return nil, 0, err
+		}
+		if flags&0x80 != 0 {
+			return nil, 0, errors.New("7z alternative methods are not supported")
+		}
+		var c coder
+		if c.id, err = h.bytes(uint64(flags & 0x0F)); err != nil {
+			return nil, 0, err
+		}
+		coderIn, coderOut := 1, 1
+		if flags&0x10 != 0 {
+			if coderIn, err = h.count(); err != nil {
+				return nil, 0, err
+			}
+			if coderOut, err = h.count(); err != nil {
+				return nil, 0, err
+			}
+		}
+		inStreams += coderIn
+		outStreams += coderOut
+		if flags&0x20 != 0 {
+			size, err := h.number()
+			if err != nil {
+				return nil, 0, err
+			}
+			if c.properties, err = h.bytes(size); err != nil {
+				return nil, 0, err
+			}
+		}
+		f.coders = append(f.coders, c)
+	}
+	if len(f.coders) != 1 || inStreams != 1 || outStreams != 1 {
+		// filter chains (ex: bcj + lzma) are not supported
+		return nil, 0, errors.Wrap(ErrUnsupportedMethod, "multiple coders")
+	}
+	return f, outStreams, nil
+}
+
+func (h *headerReader) subStreamsInfo(streams *streamsInfo) error {
+	counts := make([]int, len(streams.folders))
+	for i := range counts {
+		counts[i] = 1
+	}
+	id, err := h.byte()
+	if err != nil {
+		return err
+	}
+	if id == idNumUnpackStream {
+		for i := range counts {
+			if counts[i], err = h.count(); err != nil {
+				return err
+			}
+		}
+		if id, err = h.byte(); err != nil {
+			return err
+		}
+	}
+
+	hasSizes := id == idSize
+	streams.subStreams = make([][]uint64, len(streams.folders))
+	for i, f := range streams.folders {
+		if counts[i] == 0 {
+			continue
+		}
+		sizes := make([]uint64, counts[i])
+		var sum uint64
+		for j := 0; j < counts[i]-1 && hasSizes; j++ {
+			if sizes[j], err = h.number(); err != nil {
+				return err
+			}
+			sum += sizes[j]
+		}
+		if sum > f.unpackSize() {
+			return errors.New("7z substream sizes exceed the folder size")
+		}
+		sizes[counts[i]-1] = f.unpackSize() - sum
+		streams.subStreams[i] = sizes
+	}
+	if hasSizes {
+		if id, err = h.byte(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		switch id {
+		case idEnd:
+			return nil
+		case idCRC:
+			var digests int
+			for i, count := range counts {
+				// digests of single stream folders may be stored in the unpack info
+				if count != 1 || !streams.folders[i].hasCRC {
+					digests += count
+				}
+			}
+			if _, err := h.skipDigests(digests); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected 7z substreams info id %d", id)
+		}
+		if id, err = h.byte(); err != nil {
+			return err
+		}
+	}
+}
+
+func (h *headerReader) filesInfo() ([]*File, error) {
+	count, err := h.count()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]*File, count)
+	for i := range files {
+		files[i] = &File{hasStream: true}
+	}
+	var emptyStreams []int
+	for {
+		id, err := h.byte()
+		if err != nil {
+			return nil, err
+		}
+		if id == idEnd {
+			return files, nil
+		}
+		size, err := h.number()
+		if err != nil {
+			return nil, err
+		}
+		data, err := h.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		property := &headerReader{data: data}
+
+		switch id {
+		case idEmptyStream:
+			empty, err := property.bits(count)
+			if err != nil {
+				return nil, err
+			}
+			emptyStreams = emptyStreams[:0]
+			for i, ok := range empty {
+				if ok {
+					files[i].hasStream = false
+					// empty streams are directories unless marked as empty files
+					files[i].IsDir = true
+					emptyStreams = append(emptyStreams, i)
+				}
+			}
+		case idEmptyFile:
+			emptyFiles, err := property.bits(len(emptyStreams))
+			if err != nil {
+				return nil, err
+			}
+			for i, ok := range emptyFiles {
+				if ok {
+					files[emptyStreams[i]].IsDir = false
+				}
+			}
+		case idName:
+			if external, err := property.byte(); err != nil || external != 0 {
+				return nil, errors.New("external 7z file names are not supported")
+			}
+			for _, file := range files {
+				if file.Name, err = property.name(); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			// attributes, times, anti items and start positions are not needed to read the files
+		}
+	}
+}
+
+// name decodes a zero terminated utf-16le file name
+func (h *headerReader) name() (string, error) {
+	var units []uint16
+	for {
+		b, err := h.bytes(2)
+		if err != nil {
+			return "", err
+		}
+		unit := binary.LittleEndian.Uint16(b)
+		if unit == 0 {
+			return string(utf16.Decode(units)), nil
+		}
+		units = append(units, unit)
+	}
+}
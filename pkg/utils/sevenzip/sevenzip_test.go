@@ -0,0 +1,253 @@
+package sevenzip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+type archiveFile struct {
+	name    string
+	content string
+	dir     bool
+}
+
+// number encodes a 7z number using the full 8 byte form for large values
+func number(buf *bytes.Buffer, value uint64) {
+	if value < 0x80 {
+		buf.WriteByte(byte(value))
+		return
+	}
+	buf.WriteByte(0xFF)
+	_ = binary.Write(buf, binary.LittleEndian, value)
+}
+
+func bits(values []bool) []byte {
+	data := make([]byte, (len(values)+7)/8)
+	for i, value := range values {
+		if value {
+			data[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return data
+}
+
+func writeFolder(buf *bytes.Buffer, id, properties []byte, unpackSize int) {
+	buf.WriteByte(idFolder)
+	number(buf, 1)
+	buf.WriteByte(0)
+	number(buf, 1)
+	flags := byte(len(id))
+	if properties != nil {
+		flags |= 0x20
+	}
+	buf.WriteByte(flags)
+	buf.Write(id)
+	if properties != nil {
+		number(buf, uint64(len(properties)))
+		buf.Write(properties)
+	}
+	buf.WriteByte(idCodersUnpackSize)
+	number(buf, uint64(unpackSize))
+	buf.WriteByte(idEnd)
+}
+
+// createArchive creates a 7z archive with a single folder compressed by compress
+func createArchive(t *testing.T, files []archiveFile, id []byte, compress func([]byte) ([]byte, []byte), encodeHeader bool) []byte {
+	var data []byte
+	var sizes []int
+	for _, file := range files {
+		if file.content != "" {
+			data = append(data, file.content...)
+			sizes = append(sizes, len(file.content))
+		}
+	}
+	packed, properties := compress(data)
+
+	header := &bytes.Buffer{}
+	header.WriteByte(idHeader)
+	header.WriteByte(idMainStreamsInfo)
+	header.WriteByte(idPackInfo)
+	number(header, 0)
+	number(header, 1)
+	header.WriteByte(idSize)
+	number(header, uint64(len(packed)))
+	header.WriteByte(idEnd)
+	header.WriteByte(idUnpackInfo)
+	writeFolder(header, id, properties, len(data))
+	header.WriteByte(idSubStreamsInfo)
+	header.WriteByte(idNumUnpackStream)
+	number(header, uint64(len(sizes)))
+	header.WriteByte(idSize)
+	for _, size := range sizes[:len(sizes)-1] {
+		number(header, uint64(size))
+	}
+	header.WriteByte(idCRC)
+	header.WriteByte(1)
+	header.Write(make([]byte, 4*len(sizes)))
+	header.WriteByte(idEnd)
+	header.WriteByte(idEnd)
+
+	header.WriteByte(idFilesInfo)
+	number(header, uint64(len(files)))
+	var emptyStreams, emptyFiles []bool
+	names := []uint16{}
+	for _, file := range files {
+		emptyStreams = append(emptyStreams, file.content == "")
+		if file.content == "" {
+			emptyFiles = append(emptyFiles, !file.dir)
+		}
+		names = append(names, utf16.Encode([]rune(file.name))...)
+		names = append(names, 0)
+	}
+	header.WriteByte(idEmptyStream)
+	number(header, uint64(len(bits(emptyStreams))))
+	header.Write(bits(emptyStreams))
+	header.WriteByte(idEmptyFile)
+	number(header, uint64(len(bits(emptyFiles))))
+	header.Write(bits(emptyFiles))
+	header.WriteByte(idName)
+	number(header, uint64(1+2*len(names)))
+	header.WriteByte(0)
+	_ = binary.Write(header, binary.LittleEndian, names)
+	// modification times are skipped by the reader
+	header.WriteByte(0x14)
+	number(header, 2)
+	header.Write([]byte{0, 0})
+	header.WriteByte(idEnd)
+	header.WriteByte(idEnd)
+
+	body := append([]byte{}, packed...)
+	nextHeader := header.Bytes()
+	if encodeHeader {
+		body = append(body, nextHeader...)
+		encoded := &bytes.Buffer{}
+		encoded.WriteByte(idEncodedHeader)
+		encoded.WriteByte(idPackInfo)
+		number(encoded, uint64(len(packed)))
+		number(encoded, 1)
+		encoded.WriteByte(idSize)
+		number(encoded, uint64(len(nextHeader)))
+		encoded.WriteByte(idEnd)
+		encoded.WriteByte(idUnpackInfo)
+		writeFolder(encoded, []byte{0x00}, nil, len(nextHeader))
+		encoded.WriteByte(idEnd)
+		nextHeader = encoded.Bytes()
+	}
+
+	archive := &bytes.Buffer{}
+	archive.Write(signature)
+	archive.Write([]byte{0, 4, 0, 0, 0, 0})
+	_ = binary.Write(archive, binary.LittleEndian, uint64(len(body)))
+	_ = binary.Write(archive, binary.LittleEndian, uint64(len(nextHeader)))
+	archive.Write([]byte{0, 0, 0, 0})
+	archive.Write(body)
+	archive.Write(nextHeader)
+	return archive.Bytes()
+}
+
+func readArchive(t *testing.T, archive []byte) map[string]string {
+	reader, err := NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.Nil(t, err, "could not read 7z archive")
+	contents := make(map[string]string)
+	err = reader.Walk(func(file *File, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		require.Nil(t, err)
+		require.Equal(t, int64(len(data)), file.Size)
+		contents[file.Name] = string(data)
+		return nil
+	})
+	require.Nil(t, err, "could not walk 7z archive")
+	return contents
+}
+
+func TestReader(t *testing.T) {
+	files := []archiveFile{
+		{name: "config/.env", content: "AWS_SECRET_ACCESS_KEY=secret\n"},
+		{name: "config", dir: true},
+		{name: "empty.txt"},
+		{name: "backup/db.sql", content: "INSERT INTO users VALUES ('admin', 'password');\n"},
+	}
+	expected := map[string]string{
+		"config/.env":   "AWS_SECRET_ACCESS_KEY=secret\n",
+		"empty.txt":     "",
+		"backup/db.sql": "INSERT INTO users VALUES ('admin', 'password');\n",
+	}
+
+	copyMethod := func(data []byte) ([]byte, []byte) { return data, nil }
+	lzmaMethod := func(data []byte) ([]byte, []byte) {
+		buf := &bytes.Buffer{}
+		writer, err := lzma.WriterConfig{DictCap: 1 << 16, Size: int64(len(data))}.NewWriter(buf)
+		require.Nil(t, err)
+		_, _ = writer.Write(data)
+		require.Nil(t, writer.Close())
+		// the classic header contains the properties followed by the size
+		return buf.Bytes()[lzma.HeaderLen:], buf.Bytes()[:5]
+	}
+	lzma2Method := func(data []byte) ([]byte, []byte) {
+		buf := &bytes.Buffer{}
+		writer, err := lzma.Writer2Config{DictCap: 1 << 16}.NewWriter2(buf)
+		require.Nil(t, err)
+		_, _ = writer.Write(data)
+		require.Nil(t, writer.Close())
+		return buf.Bytes(), []byte{8}
+	}
+
+	t.Run("copy", func(t *testing.T) {
+		require.Equal(t, expected, readArchive(t, createArchive(t, files, []byte{0x00}, copyMethod, false)))
+	})
+	t.Run("lzma", func(t *testing.T) {
+		require.Equal(t, expected, readArchive(t, createArchive(t, files, []byte{0x03, 0x01, 0x01}, lzmaMethod, false)))
+	})
+	t.Run("lzma2-encoded-header", func(t *testing.T) {
+		require.Equal(t, expected, readArchive(t, createArchive(t, files, []byte{0x21}, lzma2Method, true)))
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		archive := createArchive(t, files, []byte{0x06, 0xF1, 0x07, 0x01}, copyMethod, false)
+		reader, err := NewReader(bytes.NewReader(archive), int64(len(archive)))
+		require.Nil(t, err)
+		err = reader.Walk(func(file *File, r io.Reader) error { return nil })
+		require.ErrorIs(t, err, ErrUnsupportedMethod)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		archive := createArchive(t, files, []byte{0x00}, copyMethod, false)
+		_, err := NewReader(bytes.NewReader(archive[:40]), 40)
+		require.Error(t, err, "could not detect truncated archive")
+		_, err = NewReader(bytes.NewReader([]byte("PK\x03\x04not a 7z archive at all....")), 32)
+		require.Error(t, err, "could not detect invalid signature")
+	})
+	t.Run("recursive-encoded-header", func(t *testing.T) {
+		// the encoded header is decoded to itself with the copy method
+		var encoded []byte
+		for size := 0; size != len(encoded) || size == 0; {
+			size = len(encoded)
+			buf := &bytes.Buffer{}
+			buf.WriteByte(idEncodedHeader)
+			buf.WriteByte(idPackInfo)
+			number(buf, 0)
+			number(buf, 1)
+			buf.WriteByte(idSize)
+			number(buf, uint64(size))
+			buf.WriteByte(idEnd)
+			buf.WriteByte(idUnpackInfo)
+			writeFolder(buf, []byte{0x00}, nil, size)
+			buf.WriteByte(idEnd)
+			encoded = buf.Bytes()
+		}
+		archive := &bytes.Buffer{}
+		archive.Write(signature)
+		archive.Write([]byte{0, 4, 0, 0, 0, 0})
+		_ = binary.Write(archive, binary.LittleEndian, uint64(len(encoded)))
+		_ = binary.Write(archive, binary.LittleEndian, uint64(len(encoded)))
+		archive.Write([]byte{0, 0, 0, 0})
+		archive.Write(encoded)
+		archive.Write(encoded)
+		_, err := NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+		require.ErrorContains(t, err, "nested too deeply")
+	})
+}
@@ -0,0 +1,411 @@
+package yara
+
+import (
+	"encoding/binary"
+)
+
+// value is the result of an expression, booleans are 0 or 1 and out of
+// range data reads are undefined as in YARA
+type value struct {
+	number    int64
+	undefined bool
+}
+
+var undefined = value{undefined: true}
+
+func boolean(b bool) value {
+	if b {
+		return value{number: 1}
+	}
+	return value{}
+}
+
+func (v value) truthy() bool {
+	return !v.undefined && v.number != 0
+}
+
+type expression interface {
+	eval(ctx *scanContext) value
+}
+
+// scanContext is the state of the evaluation of a rule condition
+type scanContext struct {
+	corpus   *corpus
+	patterns []*pattern
+	matches  [][]stringMatch
+	scanned  []bool
+	// rules are the results of the rules defined before the evaluated one
+	rules     []bool
+	variables map[string]int64
+	// current is the pattern iterated by a for..of loop
+	current int
+}
+
+func newScanContext(c *corpus, patterns []*pattern, rules []bool) *scanContext {
+	return &scanContext{
+		corpus:    c,
+		patterns:  patterns,
+		matches:   make([][]stringMatch, len(patterns)),
+		scanned:   make([]bool, len(patterns)),
+		rules:     rules,
+		variables: make(map[string]int64),
+		current:   -1,
+	}
+}
+
+// find returns the matches of a pattern, the data is scanned on first use
+func (ctx *scanContext) find(index int) []stringMatch {
+	if index == currentPattern {
+		index = ctx.current
+	}
+	if !ctx.scanned[index] {
+		ctx.matches[index] = ctx.corpus.find(ctx.patterns[index])
+		ctx.scanned[index] = true
+	}
+	return ctx.matches[index]
+}
+
+// currentPattern references the pattern iterated by a for..of loop, ex: $ or #
+const currentPattern = -1
+
+type constant struct {
+	value value
+}
+
+func (e *constant) eval(ctx *scanContext) value {
+	return e.value
+}
+
+type filesize struct{}
+
+func (e *filesize) eval(ctx *scanContext) value {
+	return value{number: int64(len(ctx.corpus.data))}
+}
+
+type unary struct {
+	operator string
+	operand  expression
+}
+
+func (e *unary) eval(ctx *scanContext) value {
+	operand := e.operand.eval(ctx)
+	if operand.undefined {
+		return undefined
+	}
+	switch e.operator {
+	case "not":
+		return boolean(operand.number == 0)
+	case "-":
+		return value{number: -operand.number}
+	case "~":
+		return value{number: ^operand.number}
+	}
+	return undefined
+}
+
+type binaryOperation struct {
+	operator    string
+	left, right expression
+}
+
+func (e *binaryOperation) eval(ctx *scanContext) value {
+	switch e.operator {
+	case "and":
+		return boolean(e.left.eval(ctx).truthy() && e.right.eval(ctx).truthy())
+	case "or":
+		return boolean(e.left.eval(ctx).truthy() || e.right.eval(ctx).truthy())
+	}
+
+	left, right := e.left.eval(ctx), e.right.eval(ctx)
+	if left.undefined || right.undefined {
+		return undefined
+	}
+	a, b := left.number, right.number
+	switch e.operator {
+	case "==":
+		return boolean(a == b)
+	case "!=":
+		return boolean(a != b)
+	case "<":
+		return boolean(a < b)
+	case "<=":
+		return boolean(a <= b)
+	case ">":
+		return boolean(a > b)
+	case ">=":
+		return boolean(a >= b)
+	case "+":
+		return value{number: a + b}
+	case "-":
+		return value{number: a - b}
+	case "*":
+		return value{number: a * b}
+	case "\\", "%":
+		if b == 0 {
+			return undefined
+		}
+		if e.operator == "%" {
+			return value{number: a % b}
+		}
+		return value{number: a / b}
+	case "&":
+		return value{number: a & b}
+	case "|":
+		return value{number: a | b}
+	case "^":
+		return value{number: a ^ b}
+	case "<<":
+		if b < 0 {
+			return undefined
+		}
+		return value{number: a << uint64(b)}
+	case ">>":
+		if b < 0 {
+			return undefined
+		}
+		return value{number: a >> uint64(b)}
+	}
+	return undefined
+}
+
+// interval is an inclusive range of a condition, ex: (0..filesize)
+type interval struct {
+	low, high expression
+}
+
+func (r *interval) contains(ctx *scanContext, number int) bool {
+	low, high := r.low.eval(ctx), r.high.eval(ctx)
+	return !low.undefined && !high.undefined && int64(number) >= low.number && int64(number) <= high.number
+}
+
+// stringFound is true if a string is found, optionally at an offset or in a range
+type stringFound struct {
+	pattern int
+	at      expression
+	in      *interval
+}
+
+func (e *stringFound) eval(ctx *scanContext) value {
+	matches := ctx.find(e.pattern)
+	if e.at == nil && e.in == nil {
+		return boolean(len(matches) > 0)
+	}
+	var offset value
+	if e.at != nil {
+		if offset = e.at.eval(ctx); offset.undefined {
+			return boolean(false)
+		}
+	}
+	for _, match := range matches {
+		if (e.at != nil && int64(match.offset) == offset.number) || (e.in != nil && e.in.contains(ctx, match.offset)) {
+			return boolean(true)
+		}
+	}
+	return boolean(false)
+}
+
+type stringCount struct {
+	pattern int
+	in      *interval
+}
+
+func (e *stringCount) eval(ctx *scanContext) value {
+	matches := ctx.find(e.pattern)
+	if e.in == nil {
+		return value{number: int64(len(matches))}
+	}
+	var count int64
+	for _, match := range matches {
+		if e.in.contains(ctx, match.offset) {
+			count++
+		}
+	}
+	return value{number: count}
+}
+
+// stringMatchValue is the offset or the length of the nth match of a string, ex: @a[1] or !a[1]
+type stringMatchValue struct {
+	pattern int
+	index   expression
+	length  bool
+}
+
+func (e *stringMatchValue) eval(ctx *scanContext) value {
+	matches := ctx.find(e.pattern)
+	index := e.index.eval(ctx)
+	if index.undefined || index.number < 1 || index.number > int64(len(matches)) {
+		return undefined
+	}
+	match := matches[index.number-1]
+	if e.length {
+		return value{number: int64(match.length)}
+	}
+	return value{number: int64(match.offset)}
+}
+
+// readInteger reads an integer from the data, ex: uint16(0) or uint32be(4)
+type readInteger struct {
+	size      int
+	signed    bool
+	bigEndian bool
+	offset    expression
+}
+
+func (e *readInteger) eval(ctx *scanContext) value {
+	offset := e.offset.eval(ctx)
+	data := ctx.corpus.data
+	if offset.undefined || offset.number < 0 || offset.number+int64(e.size) > int64(len(data)) {
+		return undefined
+	}
+	raw := data[offset.number : offset.number+int64(e.size)]
+	var order binary.ByteOrder = binary.LittleEndian
+	if e.bigEndian {
+		order = binary.BigEndian
+	}
+	switch e.size {
+	case 1:
+		if e.signed {
+			return value{number: int64(int8(raw[0]))}
+		}
+		return value{number: int64(raw[0])}
+	case 2:
+		if e.signed {
+			return value{number: int64(int16(order.Uint16(raw)))}
+		}
+		return value{number: int64(order.Uint16(raw))}
+	}
+	if e.signed {
+		return value{number: int64(int32(order.Uint32(raw)))}
+	}
+	return value{number: int64(order.Uint32(raw))}
+}
+
+type ruleReference struct {
+	rule int
+}
+
+func (e *ruleReference) eval(ctx *scanContext) value {
+	return boolean(ctx.rules[e.rule])
+}
+
+type variable struct {
+	name string
+}
+
+func (e *variable) eval(ctx *scanContext) value {
+	return value{number: ctx.variables[e.name]}
+}
+
+type quantifierKind int
+
+const (
+	quantifierAll quantifierKind = iota + 1
+	quantifierAny
+	quantifierNone
+	quantifierCount
+)
+
+// quantifier is the number of items required by of and for expressions
+type quantifier struct {
+	kind  quantifierKind
+	count expression
+}
+
+func (q *quantifier) satisfied(ctx *scanContext, matched, total int) bool {
+	switch q.kind {
+	case quantifierAll:
+		return matched == total
+	case quantifierAny:
+		return matched > 0
+	case quantifierNone:
+		return matched == 0
+	}
+	count := q.count.eval(ctx)
+	return !count.undefined && int64(matched) >= count.number
+}
+
+// stringsOf is true if the quantifier of strings in a set are found, ex: 2 of ($a*)
+type stringsOf struct {
+	quantifier *quantifier
+	patterns   []int
+}
+
+func (e *stringsOf) eval(ctx *scanContext) value {
+	var matched int
+	for _, index := range e.patterns {
+		if len(ctx.find(index)) > 0 {
+			matched++
+		}
+	}
+	return boolean(e.quantifier.satisfied(ctx, matched, len(e.patterns)))
+}
+
+// forOf evaluates an expression for the strings of a set, ex: for all of them : ( # > 2 )
+type forOf struct {
+	quantifier *quantifier
+	patterns   []int
+	body       expression
+}
+
+func (e *forOf) eval(ctx *scanContext) value {
+	previous := ctx.current
+	defer func() { ctx.current = previous }()
+
+	var matched int
+	for _, index := range e.patterns {
+		ctx.current = index
+		if e.body.eval(ctx).truthy() {
+			matched++
+		}
+	}
+	return boolean(e.quantifier.satisfied(ctx, matched, len(e.patterns)))
+}
+
+// forIn evaluates an expression for the integers of a range or a list, ex: for any i in (1..#a) : ( @a[i] < 10 )
+type forIn struct {
+	quantifier *quantifier
+	variable   string
+	interval   *interval
+	items      []expression
+	body       expression
+}
+
+// maxLoopIterations is the maximum number of iterations of a for..in loop
+const maxLoopIterations = maxStringMatches
+
+func (e *forIn) eval(ctx *scanContext) value {
+	var items []int64
+	if e.interval != nil {
+		low, high := e.interval.low.eval(ctx), e.interval.high.eval(ctx)
+		if low.undefined || high.undefined {
+			return boolean(false)
+		}
+		for item := low.number; item <= high.number && len(items) < maxLoopIterations; item++ {
+			items = append(items, item)
+		}
+	} else {
+		for _, item := range e.items {
+			if evaluated := item.eval(ctx); !evaluated.undefined {
+				items = append(items, evaluated.number)
+			}
+		}
+	}
+
+	previous, shadowed := ctx.variables[e.variable]
+	defer func() {
+		if shadowed {
+			ctx.variables[e.variable] = previous
+		} else {
+			delete(ctx.variables, e.variable)
+		}
+	}()
+
+	var matched int
+	for _, item := range items {
+		ctx.variables[e.variable] = item
+		if e.body.eval(ctx).truthy() {
+			matched++
+		}
+	}
+	return boolean(e.quantifier.satisfied(ctx, matched, len(items)))
+}
@@ -0,0 +1,278 @@
+package yara
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenNumber
+	tokenText
+	// tokenString is a string identifier, ex: $a or $a* in sets
+	tokenString
+	// tokenCount is a string count, ex: #a
+	tokenCount
+	// tokenOffset is a string offset, ex: @a
+	tokenOffset
+	// tokenLength is a string match length, ex: !a
+	tokenLength
+	tokenSymbol
+)
+
+type token struct {
+	kind   tokenKind
+	value  string
+	number int64
+	pos    int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of source"
+	case tokenText:
+		return strconv.Quote(t.value)
+	case tokenString:
+		return "$" + t.value
+	case tokenCount:
+		return "#" + t.value
+	case tokenOffset:
+		return "@" + t.value
+	case tokenLength:
+		return "!" + t.value
+	}
+	return t.value
+}
+
+// syntaxError is raised by the lexer and the parser and recovered by Compile
+type syntaxError struct {
+	line    int
+	message string
+}
+
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.message)
+}
+
+// lexer splits a YARA source into tokens, hex strings and regular
+// expressions are read on demand by the parser as they are context dependent
+type lexer struct {
+	source string
+	pos    int
+	peeked *token
+}
+
+func (l *lexer) errorf(pos int, format string, args ...interface{}) {
+	panic(&syntaxError{line: strings.Count(l.source[:pos], "\n") + 1, message: fmt.Sprintf(format, args...)})
+}
+
+func (l *lexer) skipSpaces() {
+	for l.pos < len(l.source) {
+		switch {
+		case strings.ContainsRune(" \t\r\n", rune(l.source[l.pos])):
+			l.pos++
+		case strings.HasPrefix(l.source[l.pos:], "//"):
+			end := strings.IndexByte(l.source[l.pos:], '\n')
+			if end == -1 {
+				l.pos = len(l.source)
+			} else {
+				l.pos += end + 1
+			}
+		case strings.HasPrefix(l.source[l.pos:], "/*"):
+			end := strings.Index(l.source[l.pos+2:], "*/")
+			if end == -1 {
+				l.errorf(l.pos, "unterminated comment")
+			}
+			l.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next token without consuming it
+func (l *lexer) peek() token {
+	if l.peeked == nil {
+		next := l.scan()
+		l.peeked = &next
+	}
+	return *l.peeked
+}
+
+// next consumes the next token
+func (l *lexer) next() token {
+	next := l.peek()
+	l.peeked = nil
+	return next
+}
+
+// peekIs returns true if the next token is the identifier or symbol
+func (l *lexer) peekIs(value string) bool {
+	next := l.peek()
+	return (next.kind == tokenIdentifier || next.kind == tokenSymbol) && next.value == value
+}
+
+// expect consumes the next token failing if it is not the identifier or symbol
+func (l *lexer) expect(value string) token {
+	next := l.next()
+	if (next.kind != tokenIdentifier && next.kind != tokenSymbol) || next.value != value {
+		l.errorf(next.pos, "expected %q, found %s", value, next)
+	}
+	return next
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (l *lexer) scan() token {
+	l.skipSpaces()
+	start := l.pos
+	if l.pos >= len(l.source) {
+		return token{kind: tokenEOF, pos: start}
+	}
+
+	c := l.source[l.pos]
+	switch {
+	case isDigit(c):
+		return l.scanNumber()
+	case isIdentifierChar(c):
+		for l.pos < len(l.source) && (isIdentifierChar(l.source[l.pos]) || (l.source[l.pos] == '.' && !strings.HasPrefix(l.source[l.pos:], ".."))) {
+			l.pos++
+		}
+		return token{kind: tokenIdentifier, value: l.source[start:l.pos], pos: start}
+	case c == '"':
+		return l.scanText()
+	case c == '!' && strings.HasPrefix(l.source[l.pos:], "!="):
+	case c == '$' || c == '#' || c == '@' || c == '!':
+		l.pos++
+		for l.pos < len(l.source) && isIdentifierChar(l.source[l.pos]) {
+			l.pos++
+		}
+		if c == '$' && l.pos < len(l.source) && l.source[l.pos] == '*' {
+			l.pos++
+		}
+		kind := map[byte]tokenKind{'$': tokenString, '#': tokenCount, '@': tokenOffset, '!': tokenLength}[c]
+		return token{kind: kind, value: l.source[start+1 : l.pos], pos: start}
+	}
+
+	for _, symbol := range []string{"==", "!=", "<=", ">=", "<<", ">>", ".."} {
+		if strings.HasPrefix(l.source[l.pos:], symbol) {
+			l.pos += len(symbol)
+			return token{kind: tokenSymbol, value: symbol, pos: start}
+		}
+	}
+	if strings.IndexByte("{}()[]:,=<>+-*\\%&|^~", c) == -1 {
+		l.errorf(start, "unexpected character %q", c)
+	}
+	l.pos++
+	return token{kind: tokenSymbol, value: string(c), pos: start}
+}
+
+func (l *lexer) scanNumber() token {
+	start := l.pos
+	base := 10
+	if strings.HasPrefix(l.source[l.pos:], "0x") {
+		base = 16
+		l.pos += 2
+	} else if strings.HasPrefix(l.source[l.pos:], "0o") {
+		base = 8
+		l.pos += 2
+	}
+	digits := l.pos
+	for l.pos < len(l.source) && isIdentifierChar(l.source[l.pos]) && !strings.HasPrefix(l.source[l.pos:], "KB") && !strings.HasPrefix(l.source[l.pos:], "MB") {
+		l.pos++
+	}
+	number, err := strconv.ParseInt(l.source[digits:l.pos], base, 64)
+	if err != nil {
+		l.errorf(start, "invalid number %s", l.source[start:l.pos])
+	}
+	switch {
+	case strings.HasPrefix(l.source[l.pos:], "KB"):
+		number *= 1024
+		l.pos += 2
+	case strings.HasPrefix(l.source[l.pos:], "MB"):
+		number *= 1024 * 1024
+		l.pos += 2
+	}
+	return token{kind: tokenNumber, value: l.source[start:l.pos], number: number, pos: start}
+}
+
+// scanText reads a quoted text string, the escape sequences are kept as is
+func (l *lexer) scanText() token {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.source) {
+		switch l.source[l.pos] {
+		case '\\':
+			l.pos += 2
+			continue
+		case '\n':
+			l.errorf(start, "unterminated text string")
+		case '"':
+			l.pos++
+			return token{kind: tokenText, value: l.source[start+1 : l.pos-1], pos: start}
+		}
+		l.pos++
+	}
+	l.errorf(start, "unterminated text string")
+	return token{}
+}
+
+// peekChar returns the next character that is not a space or a comment
+func (l *lexer) peekChar() byte {
+	if l.peeked != nil {
+		l.errorf(l.peeked.pos, "unexpected %s", l.peeked)
+	}
+	l.skipSpaces()
+	if l.pos >= len(l.source) {
+		return 0
+	}
+	return l.source[l.pos]
+}
+
+// scanHex reads the content of a hex string between braces
+func (l *lexer) scanHex() (string, int) {
+	start := l.pos
+	end := strings.IndexByte(l.source[l.pos:], '}')
+	if end == -1 {
+		l.errorf(start, "unterminated hex string")
+	}
+	l.pos += end + 1
+	return l.source[start+1 : l.pos-1], start
+}
+
+// scanRegex reads a regular expression between slashes and its flags
+func (l *lexer) scanRegex() (string, string, int) {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.source) {
+		switch l.source[l.pos] {
+		case '\\':
+			l.pos += 2
+			continue
+		case '\n':
+			l.errorf(start, "unterminated regular expression")
+		case '/':
+			expression := l.source[start+1 : l.pos]
+			l.pos++
+			flags := l.pos
+			for l.pos < len(l.source) && (l.source[l.pos] == 'i' || l.source[l.pos] == 's') {
+				l.pos++
+			}
+			return expression, l.source[flags:l.pos], start
+		}
+		l.pos++
+	}
+	l.errorf(start, "unterminated regular expression")
+	return "", "", 0
+}
@@ -0,0 +1,508 @@
+package yara
+
+import (
+	"regexp"
+	"strings"
+)
+
+// parser compiles the rules of a YARA source
+type parser struct {
+	lexer
+	rules []*rule
+	// rule is the rule being parsed
+	rule *rule
+	// variables are the for..in loop variables in scope
+	variables []string
+	// loops is the depth of the for..of loops allowing the anonymous $ references
+	loops int
+}
+
+func (p *parser) parseRules() {
+	for p.peek().kind != tokenEOF {
+		p.parseRule()
+	}
+}
+
+func (p *parser) parseRule() {
+	compiled := &rule{meta: make(map[string]interface{})}
+	for {
+		next := p.next()
+		if next.kind != tokenIdentifier {
+			p.errorf(next.pos, "expected rule, found %s", next)
+		}
+		switch next.value {
+		case "private":
+			compiled.private = true
+			continue
+		case "global":
+			compiled.global = true
+			continue
+		case "import":
+			p.errorf(next.pos, "modules are not supported")
+		case "include":
+			p.errorf(next.pos, "includes are not supported")
+		case "rule":
+		default:
+			p.errorf(next.pos, "expected rule, found %s", next)
+		}
+		break
+	}
+
+	name := p.next()
+	if name.kind != tokenIdentifier || strings.Contains(name.value, ".") {
+		p.errorf(name.pos, "invalid rule name %s", name)
+	}
+	if p.ruleIndex(name.value) != -1 {
+		p.errorf(name.pos, "duplicated rule %s", name.value)
+	}
+	compiled.name = name.value
+	p.rule = compiled
+
+	if p.peekIs(":") {
+		p.next()
+		for p.peek().kind == tokenIdentifier {
+			compiled.tags = append(compiled.tags, p.next().value)
+		}
+	}
+	p.expect("{")
+	if p.peekIs("meta") {
+		p.next()
+		p.expect(":")
+		p.parseMeta()
+	}
+	if p.peekIs("strings") {
+		p.next()
+		p.expect(":")
+		p.parseStrings()
+	}
+	p.expect("condition")
+	p.expect(":")
+	compiled.condition = p.parseExpression()
+	p.expect("}")
+	p.rules = append(p.rules, compiled)
+}
+
+func (p *parser) parseMeta() {
+	for p.peek().kind == tokenIdentifier && !p.peekIs("strings") && !p.peekIs("condition") {
+		key := p.next().value
+		p.expect("=")
+		next := p.next()
+		switch {
+		case next.kind == tokenText:
+			text, err := unescapeText(next.value)
+			if err != nil {
+				p.errorf(next.pos, "%s", err)
+			}
+			p.rule.meta[key] = string(text)
+		case next.kind == tokenNumber:
+			p.rule.meta[key] = next.number
+		case next.kind == tokenSymbol && next.value == "-":
+			number := p.next()
+			if number.kind != tokenNumber {
+				p.errorf(number.pos, "expected number, found %s", number)
+			}
+			p.rule.meta[key] = -number.number
+		case next.kind == tokenIdentifier && (next.value == "true" || next.value == "false"):
+			p.rule.meta[key] = next.value == "true"
+		default:
+			p.errorf(next.pos, "invalid meta value %s", next)
+		}
+	}
+}
+
+func (p *parser) parseStrings() {
+	for p.peek().kind == tokenString {
+		identifier := p.next()
+		if strings.HasSuffix(identifier.value, "*") {
+			p.errorf(identifier.pos, "invalid string identifier %s", identifier)
+		}
+		if identifier.value != "" && p.patternIndex(identifier.value) != -1 {
+			p.errorf(identifier.pos, "duplicated string identifier %s", identifier)
+		}
+		p.expect("=")
+
+		var kind byte
+		var text, hex, expression, flags string
+		var pos int
+		switch kind = p.peekChar(); kind {
+		case '"':
+			next := p.next()
+			text, pos = next.value, next.pos
+		case '{':
+			hex, pos = p.scanHex()
+		case '/':
+			expression, flags, pos = p.scanRegex()
+		default:
+			p.errorf(p.pos, "expected text, hex or regular expression string")
+		}
+
+		mods := p.parseModifiers(kind)
+		var source string
+		var err error
+		switch kind {
+		case '"':
+			var decoded []byte
+			if decoded, err = unescapeText(text); err == nil {
+				if len(decoded) == 0 {
+					p.errorf(pos, "empty text string")
+				}
+				source = textRegex(decoded, mods)
+			}
+		case '{':
+			source, err = hexRegex(hex)
+		case '/':
+			source, err = regexRegex(expression, flags, mods)
+		}
+		if err != nil {
+			p.errorf(pos, "%s", err)
+		}
+		compiled, err := regexp.Compile(source)
+		if err != nil {
+			p.errorf(pos, "could not compile string %s: %s", identifier, err)
+		}
+		p.rule.patterns = append(p.rule.patterns, &pattern{
+			identifier: identifier.value,
+			regex:      compiled,
+			fullword:   mods.fullword,
+			private:    mods.private,
+		})
+	}
+}
+
+func (p *parser) parseModifiers(kind byte) modifiers {
+	var mods modifiers
+	for p.peek().kind == tokenIdentifier {
+		next := p.peek()
+		switch next.value {
+		case "nocase":
+			mods.nocase = true
+		case "wide":
+			mods.wide = true
+		case "ascii":
+			mods.ascii = true
+		case "fullword":
+			mods.fullword = true
+		case "private":
+			mods.private = true
+		case "xor", "base64", "base64wide":
+			p.errorf(next.pos, "%s modifier is not supported", next.value)
+		default:
+			return mods
+		}
+		if kind == '{' && next.value != "private" {
+			p.errorf(next.pos, "%s modifier is not supported for hex strings", next.value)
+		}
+		p.next()
+	}
+	return mods
+}
+
+func (p *parser) ruleIndex(name string) int {
+	for i, compiled := range p.rules {
+		if compiled.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *parser) patternIndex(identifier string) int {
+	for i, compiled := range p.rule.patterns {
+		if compiled.identifier == identifier {
+			return i
+		}
+	}
+	return -1
+}
+
+// parsePatternReference resolves a string identifier, the anonymous
+// identifier references the current string of a for..of loop
+func (p *parser) parsePatternReference(next token) int {
+	if next.value == "" {
+		if p.loops == 0 {
+			p.errorf(next.pos, "%s is only valid in for..of loops", next)
+		}
+		return currentPattern
+	}
+	if strings.HasSuffix(next.value, "*") {
+		p.errorf(next.pos, "wildcard string %s is only valid in sets", next)
+	}
+	index := p.patternIndex(next.value)
+	if index == -1 {
+		p.errorf(next.pos, "undefined string identifier $%s", next.value)
+	}
+	return index
+}
+
+// parseStringSet parses "them" or a list of string identifiers, ex: ($a, $b*)
+func (p *parser) parseStringSet() []int {
+	if p.peekIs("them") {
+		p.next()
+		if len(p.rule.patterns) == 0 {
+			p.errorf(p.pos, "rule %s has no strings", p.rule.name)
+		}
+		return p.allPatterns("")
+	}
+	p.expect("(")
+	var indexes []int
+	for {
+		next := p.next()
+		if next.kind != tokenString || next.value == "" {
+			p.errorf(next.pos, "expected string identifier, found %s", next)
+		}
+		if prefix, ok := strings.CutSuffix(next.value, "*"); ok {
+			matched := p.allPatterns(prefix)
+			if len(matched) == 0 {
+				p.errorf(next.pos, "undefined string identifier %s", next)
+			}
+			indexes = append(indexes, matched...)
+		} else {
+			indexes = append(indexes, p.parsePatternReference(next))
+		}
+		if !p.peekIs(",") {
+			break
+		}
+		p.next()
+	}
+	p.expect(")")
+	return indexes
+}
+
+func (p *parser) allPatterns(prefix string) []int {
+	var indexes []int
+	for i, compiled := range p.rule.patterns {
+		if strings.HasPrefix(compiled.identifier, prefix) && (prefix == "" || compiled.identifier != "") {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (p *parser) parseInterval() *interval {
+	p.expect("(")
+	low := p.parseExpression()
+	p.expect("..")
+	high := p.parseExpression()
+	p.expect(")")
+	return &interval{low: low, high: high}
+}
+
+func (p *parser) parseExpression() expression {
+	left := p.parseAnd()
+	for p.peekIs("or") {
+		p.next()
+		left = &binaryOperation{operator: "or", left: left, right: p.parseAnd()}
+	}
+	return left
+}
+
+func (p *parser) parseAnd() expression {
+	left := p.parseNot()
+	for p.peekIs("and") {
+		p.next()
+		left = &binaryOperation{operator: "and", left: left, right: p.parseNot()}
+	}
+	return left
+}
+
+func (p *parser) parseNot() expression {
+	if p.peekIs("not") {
+		p.next()
+		return &unary{operator: "not", operand: p.parseNot()}
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() expression {
+	left := p.parseBinary(0)
+	for _, operator := range []string{"==", "!=", "<", "<=", ">", ">="} {
+		if p.peekIs(operator) {
+			p.next()
+			return &binaryOperation{operator: operator, left: left, right: p.parseBinary(0)}
+		}
+	}
+	return left
+}
+
+// binaryPrecedence are the arithmetic and bitwise operators from the lowest precedence
+var binaryPrecedence = [][]string{{"|"}, {"^"}, {"&"}, {"<<", ">>"}, {"+", "-"}, {"*", "\\", "%"}}
+
+func (p *parser) parseBinary(level int) expression {
+	if level == len(binaryPrecedence) {
+		return p.parseUnary()
+	}
+	left := p.parseBinary(level + 1)
+	for {
+		var matched string
+		for _, operator := range binaryPrecedence[level] {
+			if p.peek().kind == tokenSymbol && p.peekIs(operator) {
+				matched = operator
+			}
+		}
+		if matched == "" {
+			return left
+		}
+		p.next()
+		left = &binaryOperation{operator: matched, left: left, right: p.parseBinary(level + 1)}
+	}
+}
+
+func (p *parser) parseUnary() expression {
+	if p.peekIs("-") || p.peekIs("~") {
+		return &unary{operator: p.next().value, operand: p.parseUnary()}
+	}
+	return p.parsePrimary()
+}
+
+// integerFunctions are the functions reading integers from the data
+var integerFunctions = map[string]readInteger{
+	"uint8": {size: 1}, "uint16": {size: 2}, "uint32": {size: 4},
+	"int8": {size: 1, signed: true}, "int16": {size: 2, signed: true}, "int32": {size: 4, signed: true},
+	"uint16be": {size: 2, bigEndian: true}, "uint32be": {size: 4, bigEndian: true},
+	"int16be": {size: 2, signed: true, bigEndian: true}, "int32be": {size: 4, signed: true, bigEndian: true},
+}
+
+func (p *parser) parsePrimary() expression {
+	next := p.next()
+	switch next.kind {
+	case tokenNumber:
+		if p.peekIs("of") {
+			return p.parseOf(&quantifier{kind: quantifierCount, count: &constant{value: value{number: next.number}}})
+		}
+		return &constant{value: value{number: next.number}}
+	case tokenString:
+		found := &stringFound{pattern: p.parsePatternReference(next)}
+		switch {
+		case p.peekIs("at"):
+			p.next()
+			found.at = p.parseBinary(0)
+		case p.peekIs("in"):
+			p.next()
+			found.in = p.parseInterval()
+		}
+		return found
+	case tokenCount:
+		count := &stringCount{pattern: p.parsePatternReference(token{value: next.value, pos: next.pos, kind: tokenString})}
+		if p.peekIs("in") {
+			p.next()
+			count.in = p.parseInterval()
+		}
+		return count
+	case tokenOffset, tokenLength:
+		matchValue := &stringMatchValue{
+			pattern: p.parsePatternReference(token{value: next.value, pos: next.pos, kind: tokenString}),
+			index:   &constant{value: value{number: 1}},
+			length:  next.kind == tokenLength,
+		}
+		if p.peekIs("[") {
+			p.next()
+			matchValue.index = p.parseExpression()
+			p.expect("]")
+		}
+		return matchValue
+	case tokenSymbol:
+		if next.value == "(" {
+			inner := p.parseExpression()
+			p.expect(")")
+			return inner
+		}
+	case tokenIdentifier:
+		return p.parseIdentifier(next)
+	}
+	p.errorf(next.pos, "unexpected %s", next)
+	return nil
+}
+
+func (p *parser) parseIdentifier(next token) expression {
+	switch next.value {
+	case "true", "false":
+		return &constant{value: boolean(next.value == "true")}
+	case "filesize":
+		return &filesize{}
+	case "all", "any", "none":
+		kind := map[string]quantifierKind{"all": quantifierAll, "any": quantifierAny, "none": quantifierNone}[next.value]
+		return p.parseOf(&quantifier{kind: kind})
+	case "for":
+		return p.parseFor()
+	case "entrypoint":
+		p.errorf(next.pos, "entrypoint is not supported")
+	}
+	if function, ok := integerFunctions[next.value]; ok {
+		p.expect("(")
+		function.offset = p.parseExpression()
+		p.expect(")")
+		return &function
+	}
+	if strings.Contains(next.value, ".") {
+		p.errorf(next.pos, "modules are not supported")
+	}
+	for _, name := range p.variables {
+		if name == next.value {
+			return &variable{name: name}
+		}
+	}
+	if index := p.ruleIndex(next.value); index != -1 {
+		return &ruleReference{rule: index}
+	}
+	p.errorf(next.pos, "undefined identifier %s", next.value)
+	return nil
+}
+
+func (p *parser) parseOf(q *quantifier) expression {
+	p.expect("of")
+	return &stringsOf{quantifier: q, patterns: p.parseStringSet()}
+}
+
+func (p *parser) parseFor() expression {
+	q := &quantifier{kind: quantifierCount}
+	switch {
+	case p.peekIs("all"), p.peekIs("any"), p.peekIs("none"):
+		q.kind = map[string]quantifierKind{"all": quantifierAll, "any": quantifierAny, "none": quantifierNone}[p.next().value]
+	case p.peek().kind == tokenNumber:
+		q.count = &constant{value: value{number: p.next().number}}
+	default:
+		q.count = p.parseBinary(0)
+	}
+
+	if p.peekIs("of") {
+		p.next()
+		loop := &forOf{quantifier: q, patterns: p.parseStringSet()}
+		p.expect(":")
+		p.loops++
+		loop.body = p.parseBody()
+		p.loops--
+		return loop
+	}
+
+	name := p.next()
+	if name.kind != tokenIdentifier || strings.Contains(name.value, ".") {
+		p.errorf(name.pos, "expected loop variable, found %s", name)
+	}
+	p.expect("in")
+	loop := &forIn{quantifier: q, variable: name.value}
+	p.expect("(")
+	first := p.parseExpression()
+	if p.peekIs("..") {
+		p.next()
+		loop.interval = &interval{low: first, high: p.parseExpression()}
+	} else {
+		loop.items = []expression{first}
+		for p.peekIs(",") {
+			p.next()
+			loop.items = append(loop.items, p.parseExpression())
+		}
+	}
+	p.expect(")")
+	p.expect(":")
+	p.variables = append(p.variables, name.value)
+	loop.body = p.parseBody()
+	p.variables = p.variables[:len(p.variables)-1]
+	return loop
+}
+
+func (p *parser) parseBody() expression {
+	p.expect("(")
+	body := p.parseExpression()
+	p.expect(")")
+	return body
+}
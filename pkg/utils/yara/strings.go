@@ -0,0 +1,329 @@
+package yara
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxStringMatches is the maximum number of matches recorded for a string
+const maxStringMatches = 10000
+
+// maxJump is the maximum bound of a hex string jump supported by the regexp engine
+const maxJump = 1000
+
+// pattern is a compiled string of a rule, every string kind is compiled
+// to a regular expression matched against the latin-1 decoded data
+type pattern struct {
+	identifier string
+	regex      *regexp.Regexp
+	fullword   bool
+	private    bool
+}
+
+type stringMatch struct {
+	offset int
+	length int
+}
+
+// modifiers are the modifiers of a string declaration
+type modifiers struct {
+	nocase, wide, ascii, fullword, private bool
+}
+
+// unescapeText decodes the escape sequences of a text string
+func unescapeText(text string) ([]byte, error) {
+	var decoded []byte
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' {
+			decoded = append(decoded, text[i])
+			continue
+		}
+		if i+1 >= len(text) {
+			return nil, fmt.Errorf("invalid escape sequence at the end of %q", text)
+		}
+		i++
+		switch text[i] {
+		case '"', '\\':
+			decoded = append(decoded, text[i])
+		case 'n':
+			decoded = append(decoded, '\n')
+		case 'r':
+			decoded = append(decoded, '\r')
+		case 't':
+			decoded = append(decoded, '\t')
+		case 'x':
+			if i+2 >= len(text) {
+				return nil, fmt.Errorf("invalid escape sequence in %q", text)
+			}
+			value, err := strconv.ParseUint(text[i+1:i+3], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid escape sequence in %q", text)
+			}
+			decoded = append(decoded, byte(value))
+			i += 2
+		default:
+			return nil, fmt.Errorf("unknown escape sequence \\%c", text[i])
+		}
+	}
+	return decoded, nil
+}
+
+func literalByte(b byte) string {
+	return fmt.Sprintf(`\x{%02x}`, b)
+}
+
+// byteClass returns the regular expression matching the set of bytes
+func byteClass(set *[256]bool) string {
+	var ranges []string
+	var count int
+	for start := 0; start < 256; start++ {
+		if !set[start] {
+			continue
+		}
+		end := start
+		for end+1 < 256 && set[end+1] {
+			end++
+		}
+		count += end - start + 1
+		if start == end {
+			ranges = append(ranges, literalByte(byte(start)))
+		} else {
+			ranges = append(ranges, literalByte(byte(start))+"-"+literalByte(byte(end)))
+		}
+		start = end
+	}
+	if count == 1 {
+		return ranges[0]
+	}
+	return "[" + strings.Join(ranges, "") + "]"
+}
+
+// textRegex returns the regular expression matching a text string
+func textRegex(text []byte, mods modifiers) string {
+	encode := func(wide bool) string {
+		var builder strings.Builder
+		for _, b := range text {
+			if mods.nocase && isLetter(b) {
+				builder.WriteString("[" + literalByte(b|0x20) + literalByte(b&^0x20) + "]")
+			} else {
+				builder.WriteString(literalByte(b))
+			}
+			if wide {
+				builder.WriteString(literalByte(0))
+			}
+		}
+		return builder.String()
+	}
+	switch {
+	case mods.wide && mods.ascii:
+		return "(?:" + encode(false) + "|" + encode(true) + ")"
+	case mods.wide:
+		return encode(true)
+	}
+	return encode(false)
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func hexValue(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10, true
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10, true
+	}
+	return 0, false
+}
+
+// hexRegex returns the regular expression matching a hex string
+func hexRegex(hex string) (string, error) {
+	var builder strings.Builder
+	var depth, tokens int
+	for i := 0; i < len(hex); i++ {
+		c := hex[i]
+		switch {
+		case strings.ContainsRune(" \t\r\n", rune(c)):
+			continue
+		case c == '(':
+			depth++
+			builder.WriteString("(?:")
+		case c == '|':
+			if depth == 0 {
+				return "", fmt.Errorf("alternative outside of parentheses in hex string")
+			}
+			builder.WriteString("|")
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return "", fmt.Errorf("unbalanced parentheses in hex string")
+			}
+			builder.WriteString(")")
+		case c == '[':
+			end := strings.IndexByte(hex[i:], ']')
+			if end == -1 {
+				return "", fmt.Errorf("unterminated jump in hex string")
+			}
+			jump, err := jumpRegex(strings.TrimSpace(hex[i+1 : i+end]))
+			if err != nil {
+				return "", err
+			}
+			builder.WriteString(jump)
+			i += end
+		default:
+			negated := c == '~'
+			if negated {
+				i++
+			}
+			if i+1 >= len(hex) {
+				return "", fmt.Errorf("invalid byte in hex string")
+			}
+			high, highOk := hexValue(hex[i])
+			low, lowOk := hexValue(hex[i+1])
+			if (!highOk && hex[i] != '?') || (!lowOk && hex[i+1] != '?') {
+				return "", fmt.Errorf("invalid byte %q in hex string", hex[i:i+2])
+			}
+			var set [256]bool
+			for value := 0; value < 256; value++ {
+				matched := (!highOk || value>>4 == high) && (!lowOk || value&0x0f == low)
+				set[value] = matched != negated
+			}
+			builder.WriteString(byteClass(&set))
+			tokens++
+			i++
+		}
+	}
+	if depth != 0 {
+		return "", fmt.Errorf("unbalanced parentheses in hex string")
+	}
+	if tokens == 0 {
+		return "", fmt.Errorf("empty hex string")
+	}
+	return builder.String(), nil
+}
+
+// jumpRegex returns the regular expression of a jump, ex: [4], [2-8], [2-] or [-]
+func jumpRegex(jump string) (string, error) {
+	const anyByte = `[\x{00}-\x{ff}]`
+	parse := func(value string) (int, error) {
+		number, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || number < 0 {
+			return 0, fmt.Errorf("invalid jump [%s] in hex string", jump)
+		}
+		if number > maxJump {
+			return 0, fmt.Errorf("jump [%s] exceeds the supported maximum of %d", jump, maxJump)
+		}
+		return number, nil
+	}
+	low, high, ranged := strings.Cut(jump, "-")
+	if !ranged {
+		count, err := parse(low)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s{%d}", anyByte, count), nil
+	}
+	minimum := 0
+	if strings.TrimSpace(low) != "" {
+		var err error
+		if minimum, err = parse(low); err != nil {
+			return "", err
+		}
+	}
+	if strings.TrimSpace(high) == "" {
+		return fmt.Sprintf("%s{%d,}", anyByte, minimum), nil
+	}
+	maximum, err := parse(high)
+	if err != nil {
+		return "", err
+	}
+	if minimum > maximum {
+		return "", fmt.Errorf("invalid jump [%s] in hex string", jump)
+	}
+	return fmt.Sprintf("%s{%d,%d}", anyByte, minimum, maximum), nil
+}
+
+// regexRegex returns the go regular expression of a YARA regular expression,
+// the non-ascii bytes of the expression are escaped to match the raw bytes
+func regexRegex(expression, flags string, mods modifiers) (string, error) {
+	if mods.wide {
+		return "", fmt.Errorf("wide modifier is not supported for regular expressions")
+	}
+	var builder strings.Builder
+	if mods.nocase || strings.Contains(flags, "i") {
+		builder.WriteString("(?i)")
+	}
+	if strings.Contains(flags, "s") {
+		builder.WriteString("(?s)")
+	}
+	for i := 0; i < len(expression); i++ {
+		if expression[i] >= utf8.RuneSelf {
+			builder.WriteString(literalByte(expression[i]))
+		} else {
+			builder.WriteByte(expression[i])
+		}
+	}
+	return builder.String(), nil
+}
+
+// corpus is the scanned data decoded as latin-1 so that the regular
+// expressions match single bytes
+type corpus struct {
+	data  []byte
+	text  string
+	ascii bool
+}
+
+func newCorpus(data []byte) *corpus {
+	c := &corpus{data: data, ascii: true}
+	for _, b := range data {
+		if b >= utf8.RuneSelf {
+			c.ascii = false
+			break
+		}
+	}
+	if c.ascii {
+		c.text = string(data)
+		return c
+	}
+	var builder strings.Builder
+	builder.Grow(len(data) * 2)
+	for _, b := range data {
+		builder.WriteRune(rune(b))
+	}
+	c.text = builder.String()
+	return c
+}
+
+func isAlphanumeric(b byte) bool {
+	return isLetter(b) || isDigit(b)
+}
+
+// find returns the matches of a pattern with their offsets in the raw data
+func (c *corpus) find(p *pattern) []stringMatch {
+	indexes := p.regex.FindAllStringIndex(c.text, maxStringMatches)
+	matches := make([]stringMatch, 0, len(indexes))
+	lastIndex, lastOffset := 0, 0
+	for _, index := range indexes {
+		match := stringMatch{offset: index[0], length: index[1] - index[0]}
+		if !c.ascii {
+			lastOffset += utf8.RuneCountInString(c.text[lastIndex:index[0]])
+			lastIndex = index[0]
+			match = stringMatch{offset: lastOffset, length: utf8.RuneCountInString(c.text[index[0]:index[1]])}
+		}
+		if p.fullword {
+			end := match.offset + match.length
+			if (match.offset > 0 && isAlphanumeric(c.data[match.offset-1])) || (end < len(c.data) && isAlphanumeric(c.data[end])) {
+				continue
+			}
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
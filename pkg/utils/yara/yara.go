@@ -0,0 +1,101 @@
+// Package yara implements a pure go subset of the YARA rules language.
+//
+// Rules support meta, text strings with the nocase, wide, ascii, fullword and
+// private modifiers, hex strings with wildcards, jumps and alternatives and
+// regular expressions. Conditions support boolean, arithmetic and bitwise
+// operators, string counts, offsets and lengths, the at and in operators,
+// filesize, the integer functions, rule references, of expressions and for
+// loops. Modules, includes and the xor and base64 modifiers are not supported.
+package yara
+
+import (
+	"errors"
+	"fmt"
+)
+
+type rule struct {
+	name      string
+	tags      []string
+	meta      map[string]interface{}
+	private   bool
+	global    bool
+	patterns  []*pattern
+	condition expression
+}
+
+// Rules is a compiled set of YARA rules
+type Rules struct {
+	rules []*rule
+}
+
+// MatchRule is a rule matching the scanned data
+type MatchRule struct {
+	Rule    string
+	Tags    []string
+	Meta    map[string]interface{}
+	Strings []MatchString
+}
+
+// MatchString is a string of a rule found in the scanned data
+type MatchString struct {
+	Identifier string
+	Offset     int
+	Data       []byte
+}
+
+// Compile compiles the rules of a YARA source
+func Compile(source string) (rules *Rules, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			syntaxErr, ok := recovered.(*syntaxError)
+			if !ok {
+				panic(recovered)
+			}
+			rules, err = nil, syntaxErr
+		}
+	}()
+
+	p := &parser{lexer: lexer{source: source}}
+	p.parseRules()
+	if len(p.rules) == 0 {
+		return nil, errors.New("no rules found")
+	}
+	return &Rules{rules: p.rules}, nil
+}
+
+// Scan returns the non private rules matching the data
+func (r *Rules) Scan(data []byte) []MatchRule {
+	c := newCorpus(data)
+	results := make([]bool, len(r.rules))
+	contexts := make([]*scanContext, len(r.rules))
+	for i, compiled := range r.rules {
+		contexts[i] = newScanContext(c, compiled.patterns, results[:i])
+		results[i] = compiled.condition.eval(contexts[i]).truthy()
+		// a global rule not matching fails all the rules
+		if compiled.global && !results[i] {
+			return nil
+		}
+	}
+
+	var matches []MatchRule
+	for i, compiled := range r.rules {
+		if !results[i] || compiled.private {
+			continue
+		}
+		match := MatchRule{Rule: compiled.name, Tags: compiled.tags, Meta: compiled.meta}
+		for index, p := range compiled.patterns {
+			if p.private {
+				continue
+			}
+			for _, found := range contexts[i].find(index) {
+				match.Strings = append(match.Strings, MatchString{
+					Identifier: fmt.Sprintf("$%s", p.identifier),
+					Offset:     found.offset,
+					Data:       data[found.offset : found.offset+found.length],
+				})
+			}
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
@@ -0,0 +1,182 @@
+package yara
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func scanRules(t *testing.T, source string, data []byte) []string {
+	rules, err := Compile(source)
+	require.Nil(t, err, "could not compile rules")
+	var names []string
+	for _, match := range rules.Scan(data) {
+		names = append(names, match.Rule)
+	}
+	return names
+}
+
+func TestScan(t *testing.T) {
+	webshell := []byte("<?php @EVAL(base64_decode($_POST['cmd'])); ?>")
+	binary := []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00PE\x00\x00\xff\xfe")
+
+	tests := []struct {
+		name     string
+		source   string
+		data     []byte
+		expected []string
+	}{
+		{
+			name: "text-nocase",
+			source: `rule webshell : php {
+				meta:
+					author = "nuclei"
+					score = 80
+				strings:
+					$eval = "eval(" nocase
+					$post = "$_POST"
+				condition:
+					all of them
+			}`,
+			data:     webshell,
+			expected: []string{"webshell"},
+		},
+		{
+			name:     "text-case-sensitive",
+			source:   `rule webshell { strings: $eval = "eval(" condition: $eval }`,
+			data:     webshell,
+			expected: nil,
+		},
+		{
+			name:     "wide",
+			source:   `rule wide { strings: $a = "cmd" wide $b = "cmd" condition: $a and not $b }`,
+			data:     []byte("c\x00m\x00d\x00"),
+			expected: []string{"wide"},
+		},
+		{
+			name:     "wide-ascii",
+			source:   `rule wide { strings: $a = "cmd" wide ascii condition: #a == 2 }`,
+			data:     []byte("c\x00m\x00d\x00 cmd"),
+			expected: []string{"wide"},
+		},
+		{
+			name:     "fullword",
+			source:   `rule fullword { strings: $a = "shell" fullword condition: #a == 1 }`,
+			data:     []byte("webshell shell shells"),
+			expected: []string{"fullword"},
+		},
+		{
+			name:     "hex-wildcards-jumps-alternatives",
+			source:   `rule pe { strings: $mz = { 4D 5A ?0 00 [2-6] ( 04 | 05 ) 00 50 45 } $tail = { ~00 F? } condition: $mz at 0 and $tail }`,
+			data:     binary,
+			expected: []string{"pe"},
+		},
+		{
+			name:     "integer-functions-out-of-range",
+			source:   `rule pe { condition: uint16(0) == 0x5A4D and uint16be(0) == 0x4D5A and uint8(filesize - 1) == 0xfe and int8(filesize - 1) == -2 and not (uint32(100) == 0) }`,
+			data:     binary,
+			expected: nil,
+		},
+		{
+			name:     "integer-functions-in-range",
+			source:   `rule pe { condition: uint16(0) == 0x5A4D and uint16be(0) == 0x4D5A and uint8(filesize - 1) == 0xfe and int8(filesize - 1) == -2 }`,
+			data:     binary,
+			expected: []string{"pe"},
+		},
+		{
+			name:     "regex",
+			source:   `rule regex { strings: $a = /base64_decode\s*\(\s*\$_(POST|GET)/i condition: $a in (0..20) }`,
+			data:     webshell,
+			expected: []string{"regex"},
+		},
+		{
+			name:     "regex-binary",
+			source:   `rule regex { strings: $a = /\x90\x00\x03/ condition: @a[1] == 2 and !a == 3 }`,
+			data:     binary,
+			expected: []string{"regex"},
+		},
+		{
+			name: "rule-references-and-private",
+			source: `private rule php { strings: $open = "<?php" condition: $open at 0 }
+				rule webshell { strings: $a = "eval" nocase condition: php and $a }`,
+			data:     webshell,
+			expected: []string{"webshell"},
+		},
+		{
+			name:     "global",
+			source:   `global rule small { condition: filesize < 10 } rule php { strings: $a = "php" condition: $a }`,
+			data:     webshell,
+			expected: nil,
+		},
+		{
+			name:     "of-sets",
+			source:   `rule of { strings: $a1 = "eval" nocase $a2 = "system" $b = "POST" condition: 1 of ($a*) and none of ($a2) and any of ($b, $a1) and 2 of them }`,
+			data:     webshell,
+			expected: []string{"of"},
+		},
+		{
+			name:     "for-of",
+			source:   `rule loop { strings: $ = "php" $ = "POST" condition: for all of them : ( # == 1 and @ > 0 ) }`,
+			data:     webshell,
+			expected: []string{"loop"},
+		},
+		{
+			name:     "for-in",
+			source:   `rule loop { strings: $a = "a" condition: for all i in (1..#a) : ( @a[i] % 2 == 0 ) and for any i in (1, 2, 3) : ( i * 2 == 6 ) }`,
+			data:     []byte("a-a-a-"),
+			expected: []string{"loop"},
+		},
+		{
+			name:     "arithmetic",
+			source:   `rule math { condition: (filesize \ 2) * 2 + filesize % 2 == filesize and (1 << 4 | 1) == 17 and ~0 == -1 }`,
+			data:     []byte("abc"),
+			expected: []string{"math"},
+		},
+		{
+			name:     "sizes",
+			source:   `rule size { condition: filesize < 1KB and 1MB == 1048576 }`,
+			data:     []byte("abc"),
+			expected: []string{"size"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, scanRules(t, test.source, test.data))
+		})
+	}
+}
+
+func TestScanStrings(t *testing.T) {
+	rules, err := Compile(`rule strings {
+		strings:
+			$a = "é"
+			$b = "a" private
+		condition:
+			$a and $b
+	}`)
+	require.Nil(t, err)
+	matches := rules.Scan([]byte("\xff\xfeaé"))
+	require.Len(t, matches, 1)
+	require.Equal(t, []MatchString{{Identifier: "$a", Offset: 3, Data: []byte("é")}}, matches[0].Strings)
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, source := range []string{
+		``,
+		`import "pe" rule a { condition: pe.is_pe }`,
+		`rule a { condition: b }`,
+		`rule a { strings: $a = "a" condition: $b }`,
+		`rule a { strings: $a = "a" xor condition: $a }`,
+		`rule a { strings: $a = { 4D 5A [0-5000] 00 } condition: $a }`,
+		`rule a { strings: $a = { 4D 5G } condition: $a }`,
+		`rule a { strings: $a = "unterminated condition: $a }`,
+		`rule a { condition: $ }`,
+		`rule a { condition: true } rule a { condition: true }`,
+	} {
+		_, err := Compile(source)
+		require.Error(t, err, "could compile invalid rules %s", source)
+	}
+
+	_, err := Compile("rule a {\n condition:\n  unknown\n}")
+	require.EqualError(t, err, "line 3: undefined identifier unknown")
+}
@@ -14,6 +14,12 @@ type Workflow struct {
 	// description: |
 	//   Workflows is a list of workflows to execute for a template.
 	Workflows []*WorkflowTemplate `yaml:"workflows,omitempty" json:"workflows,omitempty" jsonschema:"title=list of workflows to execute,description=List of workflows to execute for template"`
+	// description: |
+	//   CookieReuse shares the cookies between all the steps of a workflow execution.
+	//
+	//   The session established by a step, like a login template, is used by the http and
+	//   headless requests of the subsequent steps, including the clustered templates.
+	CookieReuse bool `yaml:"cookie-reuse,omitempty" json:"cookie-reuse,omitempty" jsonschema:"title=share cookies between workflow steps,description=Shares the cookies between all the steps of a workflow execution"`
 
 	Options *protocols.ExecutorOptions `yaml:"-" json:"-"`
 }